@@ -0,0 +1,394 @@
+package bank
+
+import (
+	"errors"
+	"go-api/core/response"
+	"go-api/service"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 导入银行对账单
+// @Id 1001
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "对账单文件"
+// @Param payment_method_id formData string true "付款方式ID"
+// @Param format formData int true "格式 1CSV 2OFX 3CAMT.053"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /bankstatements [POST]
+func NewBankStatement(c *gin.Context) {
+	var info BankStatementNew
+	if err := c.ShouldBind(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	if file.Size > 10<<20 {
+		response.ResponseError(c, "BindingError", errors.New("file too large"))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bankService := NewBankService()
+	new, err := bankService.NewBankStatement(info, file.Filename, content)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 银行对账单列表
+// @Id 1002
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param payment_method_id query string false "付款方式ID"
+// @Param format query int false "格式"
+// @Success 200 object response.ListRes{data=[]BankStatementResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /bankstatements [GET]
+func GetBankStatementList(c *gin.Context) {
+	var filter BankStatementFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	bankService := NewBankService()
+	count, list, err := bankService.GetBankStatementList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取银行对账单
+// @Id 1003
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "对账单ID"
+// @Success 200 object response.SuccessRes{data=BankStatementResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /bankstatements/:id [GET]
+func GetBankStatementByID(c *gin.Context) {
+	var uri BankStatementID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bankService := NewBankService()
+	bankStatement, err := bankService.GetBankStatementByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, bankStatement)
+}
+
+// @Summary 根据ID删除银行对账单
+// @Id 1004
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "对账单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /bankstatements/:id [DELETE]
+func DeleteBankStatement(c *gin.Context) {
+	var uri BankStatementID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bankService := NewBankService()
+	err := bankService.DeleteBankStatement(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 银行流水列表
+// @Id 1005
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param bank_statement_id query string false "对账单ID"
+// @Param payment_method_id query string false "付款方式ID"
+// @Param match_status query int false "匹配状态"
+// @Param date_from query string false "开始日期"
+// @Param date_to query string false "结束日期"
+// @Success 200 object response.ListRes{data=[]BankTransactionResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions [GET]
+func GetBankTransactionList(c *gin.Context) {
+	var filter BankTransactionFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	bankService := NewBankService()
+	count, list, err := bankService.GetBankTransactionList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 银行流水自动匹配
+// @Id 1006
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param match_info body AutoMatchNew true "匹配信息"
+// @Success 200 object response.SuccessRes{data=AutoMatchResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions/automatch [POST]
+func AutoMatchBankTransaction(c *gin.Context) {
+	var info AutoMatchNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bankService := NewBankService()
+	res, err := bankService.AutoMatchBankTransaction(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 银行流水匹配列表
+// @Id 1007
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "银行流水ID"
+// @Success 200 object response.ListRes{data=[]BankTransactionMatchResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions/:id/matches [GET]
+func GetBankTransactionMatchList(c *gin.Context) {
+	var uri BankTransactionID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bankService := NewBankService()
+	list, err := bankService.GetBankTransactionMatchList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 手动匹配银行流水
+// @Id 1008
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "银行流水ID"
+// @Param match_info body BankTransactionMatchNew true "匹配信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions/:id/matches [POST]
+func MatchBankTransaction(c *gin.Context) {
+	var uri BankTransactionID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info BankTransactionMatchNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bankService := NewBankService()
+	new, err := bankService.MatchBankTransaction(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 拆分银行流水
+// @Id 1009
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "银行流水ID"
+// @Param split_info body BankTransactionSplitNew true "拆分信息"
+// @Success 200 object response.SuccessRes{data=[]string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions/:id/split [POST]
+func SplitBankTransaction(c *gin.Context) {
+	var uri BankTransactionID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info BankTransactionSplitNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bankService := NewBankService()
+	new, err := bankService.SplitBankTransaction(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据银行流水新建付款
+// @Id 1010
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "银行流水ID"
+// @Param payment_info body BankTransactionPaymentNew true "付款信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactions/:id/payments [POST]
+func NewPaymentFromBankTransaction(c *gin.Context) {
+	var uri BankTransactionID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info BankTransactionPaymentNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bankService := NewBankService()
+	new, err := bankService.NewPaymentFromBankTransaction(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 取消银行流水匹配
+// @Id 1011
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "匹配ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /banktransactionmatches/:id [DELETE]
+func DeleteBankTransactionMatch(c *gin.Context) {
+	var uri BankTransactionMatchID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bankService := NewBankService()
+	err := bankService.DeleteBankTransactionMatch(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 对账汇总
+// @Id 1012
+// @Tags 银行对账管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param payment_method_id query string false "付款方式ID"
+// @Param date_from query string false "开始日期"
+// @Param date_to query string false "结束日期"
+// @Success 200 object response.SuccessRes{data=[]ReconciliationResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /reconciliations [GET]
+func GetReconciliationSummary(c *gin.Context) {
+	var filter ReconciliationFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	bankService := NewBankService()
+	res, err := bankService.GetReconciliationSummary(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
@@ -0,0 +1,168 @@
+package bank
+
+import "go-api/core/request"
+
+type BankStatementNew struct {
+	PaymentMethodID string `form:"payment_method_id" binding:"required,min=6,max=64"`
+	Format          int    `form:"format" binding:"required,oneof=1 2 3"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+	User            string `json:"user" swaggerignore:"true"`
+	Email           string `json:"email" swaggerignore:"true"`
+}
+
+type BankStatementFilter struct {
+	PaymentMethodID string `form:"payment_method_id" binding:"omitempty,max=64"`
+	Format          int    `form:"format" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type BankStatementResponse struct {
+	OrganizationID    string `db:"organization_id" json:"organization_id"`
+	BankStatementID   string `db:"bank_statement_id" json:"bank_statement_id"`
+	PaymentMethodID   string `db:"payment_method_id" json:"payment_method_id"`
+	PaymentMethodName string `db:"payment_method_name" json:"payment_method_name"`
+	FileName          string `db:"file_name" json:"file_name"`
+	Format            int    `db:"format" json:"format"`
+	StatementDate     string `db:"statement_date" json:"statement_date"`
+	TransactionCount  int    `db:"transaction_count" json:"transaction_count"`
+	DuplicateCount    int    `db:"duplicate_count" json:"duplicate_count"`
+	Status            int    `db:"status" json:"status"`
+}
+
+type BankStatementID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type BankTransactionFilter struct {
+	BankStatementID string `form:"bank_statement_id" binding:"omitempty,max=64"`
+	PaymentMethodID string `form:"payment_method_id" binding:"omitempty,max=64"`
+	MatchStatus     int    `form:"match_status" binding:"omitempty,oneof=1 2 3 4"`
+	DateFrom        string `form:"date_from" binding:"omitempty,datetime=2006-01-02"`
+	DateTo          string `form:"date_to" binding:"omitempty,datetime=2006-01-02"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type BankTransactionResponse struct {
+	OrganizationID    string  `db:"organization_id" json:"organization_id"`
+	BankTransactionID string  `db:"bank_transaction_id" json:"bank_transaction_id"`
+	BankStatementID   string  `db:"bank_statement_id" json:"bank_statement_id"`
+	ParentID          string  `db:"parent_id" json:"parent_id"`
+	PaymentMethodID   string  `db:"payment_method_id" json:"payment_method_id"`
+	PaymentMethodName string  `db:"payment_method_name" json:"payment_method_name"`
+	TransactionDate   string  `db:"transaction_date" json:"transaction_date"`
+	Amount            float64 `db:"amount" json:"amount"`
+	MatchedAmount     float64 `db:"matched_amount" json:"matched_amount"`
+	Reference         string  `db:"reference" json:"reference"`
+	Description       string  `db:"description" json:"description"`
+	ExternalID        string  `db:"external_id" json:"external_id"`
+	MatchStatus       int     `db:"match_status" json:"match_status"`
+	Status            int     `db:"status" json:"status"`
+}
+
+type BankTransactionID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type BankTransactionMatchResponse struct {
+	OrganizationID         string  `db:"organization_id" json:"organization_id"`
+	BankTransactionMatchID string  `db:"bank_transaction_match_id" json:"bank_transaction_match_id"`
+	BankTransactionID      string  `db:"bank_transaction_id" json:"bank_transaction_id"`
+	PaymentType            int     `db:"payment_type" json:"payment_type"`
+	PaymentID              string  `db:"payment_id" json:"payment_id"`
+	PaymentNumber          string  `db:"payment_number" json:"payment_number"`
+	PaymentDate            string  `db:"payment_date" json:"payment_date"`
+	Amount                 float64 `db:"amount" json:"amount"`
+	MatchType              int     `db:"match_type" json:"match_type"`
+	Status                 int     `db:"status" json:"status"`
+}
+
+type BankTransactionMatchID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type BankTransactionMatchNew struct {
+	PaymentType    int     `json:"payment_type" binding:"required,oneof=1 2"`
+	PaymentID      string  `json:"payment_id" binding:"required,min=1,max=64"`
+	Amount         float64 `json:"amount" binding:"omitempty,gt=0"`
+	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
+	User           string  `json:"user" swaggerignore:"true"`
+	Email          string  `json:"email" swaggerignore:"true"`
+}
+
+type AutoMatchNew struct {
+	BankStatementID string `json:"bank_statement_id" binding:"omitempty,max=64"`
+	PaymentMethodID string `json:"payment_method_id" binding:"omitempty,max=64"`
+	DateWindow      int    `json:"date_window" binding:"omitempty,min=0,max=31"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+	User            string `json:"user" swaggerignore:"true"`
+	Email           string `json:"email" swaggerignore:"true"`
+}
+
+type AutoMatchResponse struct {
+	TransactionCount int `json:"transaction_count"`
+	MatchedCount     int `json:"matched_count"`
+}
+
+type BankTransactionSplitNew struct {
+	Items          []BankTransactionSplitItemNew `json:"items" binding:"required,min=2,dive"`
+	OrganizationID string                        `json:"organiztion_id" swaggerignore:"true"`
+	User           string                        `json:"user" swaggerignore:"true"`
+	Email          string                        `json:"email" swaggerignore:"true"`
+}
+
+type BankTransactionSplitItemNew struct {
+	Amount      float64 `json:"amount" binding:"required"`
+	Reference   string  `json:"reference" binding:"omitempty,max=255"`
+	Description string  `json:"description" binding:"omitempty,max=255"`
+}
+
+type BankTransactionPaymentNew struct {
+	InvoiceID      string `json:"invoice_id" binding:"omitempty,max=64"`
+	BillID         string `json:"bill_id" binding:"omitempty,max=64"`
+	PaymentNumber  string `json:"payment_number" binding:"required,min=6,max=64"`
+	Notes          string `json:"notes" binding:"omitempty"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type PaymentCandidateResponse struct {
+	PaymentType     int     `db:"payment_type" json:"payment_type"`
+	PaymentID       string  `db:"payment_id" json:"payment_id"`
+	PaymentNumber   string  `db:"payment_number" json:"payment_number"`
+	PaymentDate     string  `db:"payment_date" json:"payment_date"`
+	PaymentMethodID string  `db:"payment_method_id" json:"payment_method_id"`
+	DocumentNumber  string  `db:"document_number" json:"document_number"`
+	Amount          float64 `db:"amount" json:"amount"`
+	MatchedAmount   float64 `db:"matched_amount" json:"matched_amount"`
+}
+
+type ReconciliationFilter struct {
+	PaymentMethodID string `form:"payment_method_id" binding:"omitempty,max=64"`
+	DateFrom        string `form:"date_from" binding:"omitempty,datetime=2006-01-02"`
+	DateTo          string `form:"date_to" binding:"omitempty,datetime=2006-01-02"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type ReconciliationBalanceResponse struct {
+	PaymentMethodID  string  `db:"payment_method_id" json:"payment_method_id"`
+	Balance          float64 `db:"balance" json:"balance"`
+	ReconciledAmount float64 `db:"reconciled_amount" json:"reconciled_amount"`
+	UnmatchedCount   int     `db:"unmatched_count" json:"unmatched_count"`
+}
+
+type ReconciliationResponse struct {
+	PaymentMethodID        string  `json:"payment_method_id"`
+	PaymentMethodName      string  `json:"payment_method_name"`
+	BankBalance            float64 `json:"bank_balance"`
+	BankReconciledAmount   float64 `json:"bank_reconciled_amount"`
+	BankUnreconciledAmount float64 `json:"bank_unreconciled_amount"`
+	BankUnreconciledCount  int     `json:"bank_unreconciled_count"`
+	BookBalance            float64 `json:"book_balance"`
+	BookReconciledAmount   float64 `json:"book_reconciled_amount"`
+	BookUnreconciledAmount float64 `json:"book_unreconciled_amount"`
+	BookUnreconciledCount  int     `json:"book_unreconciled_count"`
+	Difference             float64 `json:"difference"`
+}
@@ -0,0 +1,57 @@
+package bank
+
+import "time"
+
+type BankStatement struct {
+	ID               int64     `db:"id" json:"id"`
+	OrganizationID   string    `db:"organization_id" json:"organization_id"`
+	BankStatementID  string    `db:"bank_statement_id" json:"bank_statement_id"`
+	PaymentMethodID  string    `db:"payment_method_id" json:"payment_method_id"`
+	FileName         string    `db:"file_name" json:"file_name"`
+	Format           int       `db:"format" json:"format"` //1 csv 2 ofx 3 camt.053
+	StatementDate    string    `db:"statement_date" json:"statement_date"`
+	TransactionCount int       `db:"transaction_count" json:"transaction_count"`
+	DuplicateCount   int       `db:"duplicate_count" json:"duplicate_count"`
+	Status           int       `db:"status" json:"status"`
+	Created          time.Time `db:"created" json:"created"`
+	CreatedBy        string    `db:"created_by" json:"created_by"`
+	Updated          time.Time `db:"updated" json:"updated"`
+	UpdatedBy        string    `db:"updated_by" json:"updated_by"`
+}
+
+type BankTransaction struct {
+	ID                int64     `db:"id" json:"id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	BankTransactionID string    `db:"bank_transaction_id" json:"bank_transaction_id"`
+	BankStatementID   string    `db:"bank_statement_id" json:"bank_statement_id"`
+	ParentID          string    `db:"parent_id" json:"parent_id"`
+	PaymentMethodID   string    `db:"payment_method_id" json:"payment_method_id"`
+	TransactionDate   string    `db:"transaction_date" json:"transaction_date"`
+	Amount            float64   `db:"amount" json:"amount"` //positive money in, negative money out
+	MatchedAmount     float64   `db:"matched_amount" json:"matched_amount"`
+	Reference         string    `db:"reference" json:"reference"`
+	Description       string    `db:"description" json:"description"`
+	ExternalID        string    `db:"external_id" json:"external_id"`
+	MatchStatus       int       `db:"match_status" json:"match_status"` //1 unmatched 2 partially matched 3 matched 4 split
+	Status            int       `db:"status" json:"status"`
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
+}
+
+type BankTransactionMatch struct {
+	ID                     int64     `db:"id" json:"id"`
+	OrganizationID         string    `db:"organization_id" json:"organization_id"`
+	BankTransactionMatchID string    `db:"bank_transaction_match_id" json:"bank_transaction_match_id"`
+	BankTransactionID      string    `db:"bank_transaction_id" json:"bank_transaction_id"`
+	PaymentType            int       `db:"payment_type" json:"payment_type"` //1 payment received 2 payment made
+	PaymentID              string    `db:"payment_id" json:"payment_id"`
+	Amount                 float64   `db:"amount" json:"amount"`
+	MatchType              int       `db:"match_type" json:"match_type"` //1 auto 2 manual
+	Status                 int       `db:"status" json:"status"`
+	Created                time.Time `db:"created" json:"created"`
+	CreatedBy              string    `db:"created_by" json:"created_by"`
+	Updated                time.Time `db:"updated" json:"updated"`
+	UpdatedBy              string    `db:"updated_by" json:"updated_by"`
+}
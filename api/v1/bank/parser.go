@@ -0,0 +1,332 @@
+package bank
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type statementLine struct {
+	TransactionDate string
+	Amount          float64
+	Reference       string
+	Description     string
+	ExternalID      string
+}
+
+func parseStatement(format int, content []byte) ([]statementLine, error) {
+	switch format {
+	case 1:
+		return parseCSV(content)
+	case 2:
+		return parseOFX(content)
+	case 3:
+		return parseCAMT053(content)
+	}
+	msg := "statement format error"
+	return nil, errors.New(msg)
+}
+
+//csv
+
+var csvColumns = map[string][]string{
+	"date":        {"date", "transaction date", "posting date", "posted date", "booking date", "value date"},
+	"amount":      {"amount", "transaction amount"},
+	"debit":       {"debit", "withdrawal", "withdrawals", "money out", "paid out"},
+	"credit":      {"credit", "deposit", "deposits", "money in", "paid in"},
+	"reference":   {"reference", "ref", "check number", "cheque number", "number"},
+	"description": {"description", "memo", "details", "narrative", "payee", "name"},
+	"id":          {"id", "transaction id", "fitid", "bank reference"},
+}
+
+func parseCSV(content []byte) ([]statementLine, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(content))
+	firstLine := string(content)
+	if idx := strings.IndexAny(firstLine, "\r\n"); idx >= 0 {
+		firstLine = firstLine[:idx]
+	}
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		msg := "statement is empty"
+		return nil, errors.New(msg)
+	}
+	columns := make(map[string]int)
+	for idx, header := range records[0] {
+		header = strings.ToLower(strings.TrimSpace(header))
+		for key, names := range csvColumns {
+			if _, ok := columns[key]; ok {
+				continue
+			}
+			for _, name := range names {
+				if header == name {
+					columns[key] = idx
+				}
+			}
+		}
+	}
+	if _, ok := columns["date"]; !ok {
+		msg := "date column not found"
+		return nil, errors.New(msg)
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasAmount && !hasDebit && !hasCredit {
+		msg := "amount column not found"
+		return nil, errors.New(msg)
+	}
+	value := func(record []string, key string) string {
+		idx, ok := columns[key]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+	var lines []statementLine
+	for row, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		var line statementLine
+		line.TransactionDate, err = parseStatementDate(value(record, "date"))
+		if err != nil {
+			msg := "row " + strconv.Itoa(row+2) + " date error: " + value(record, "date")
+			return nil, errors.New(msg)
+		}
+		if hasAmount {
+			line.Amount, err = parseStatementAmount(value(record, "amount"))
+		} else {
+			var debit, credit float64
+			debit, err = parseStatementAmount(value(record, "debit"))
+			if err == nil {
+				credit, err = parseStatementAmount(value(record, "credit"))
+			}
+			if debit < 0 {
+				debit = -debit
+			}
+			line.Amount = credit - debit
+		}
+		if err != nil {
+			msg := "row " + strconv.Itoa(row+2) + " amount error"
+			return nil, errors.New(msg)
+		}
+		line.Reference = value(record, "reference")
+		line.Description = value(record, "description")
+		line.ExternalID = value(record, "id")
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+//ofx
+
+var ofxTransactionRegexp = regexp.MustCompile(`(?is)<STMTTRN>(.*?)(?:</STMTTRN>|<STMTTRN>|</BANKTRANLIST>)`)
+
+func ofxValue(block, tag string) string {
+	re := regexp.MustCompile(`(?i)<` + tag + `>([^<\r\n]*)`)
+	match := re.FindStringSubmatch(block)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
+
+func parseOFX(content []byte) ([]statementLine, error) {
+	text := string(content)
+	var lines []statementLine
+	for {
+		loc := ofxTransactionRegexp.FindStringSubmatchIndex(text)
+		if loc == nil {
+			break
+		}
+		block := text[loc[2]:loc[3]]
+		// an unclosed transaction ends where the next one starts
+		text = text[loc[3]:]
+		var line statementLine
+		var err error
+		line.TransactionDate, err = parseStatementDate(ofxValue(block, "DTPOSTED"))
+		if err != nil {
+			msg := "transaction date error: " + ofxValue(block, "DTPOSTED")
+			return nil, errors.New(msg)
+		}
+		line.Amount, err = parseStatementAmount(ofxValue(block, "TRNAMT"))
+		if err != nil {
+			msg := "transaction amount error: " + ofxValue(block, "TRNAMT")
+			return nil, errors.New(msg)
+		}
+		line.ExternalID = ofxValue(block, "FITID")
+		line.Reference = ofxValue(block, "CHECKNUM")
+		if line.Reference == "" {
+			line.Reference = ofxValue(block, "REFNUM")
+		}
+		line.Description = strings.TrimSpace(ofxValue(block, "NAME") + " " + ofxValue(block, "MEMO"))
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		msg := "statement is empty"
+		return nil, errors.New(msg)
+	}
+	return lines, nil
+}
+
+//camt.053
+
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Amount             string            `xml:"Amt"`
+	CreditDebit        string            `xml:"CdtDbtInd"`
+	BookingDate        string            `xml:"BookgDt>Dt"`
+	BookingDateTime    string            `xml:"BookgDt>DtTm"`
+	ValueDate          string            `xml:"ValDt>Dt"`
+	AccountServicerRef string            `xml:"AcctSvcrRef"`
+	AdditionalInfo     string            `xml:"AddtlNtryInf"`
+	Details            []camtEntryDetail `xml:"NtryDtls>TxDtls"`
+}
+
+type camtEntryDetail struct {
+	EndToEndID    string   `xml:"Refs>EndToEndId"`
+	InstructionID string   `xml:"Refs>InstrId"`
+	CreditorRef   string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	Unstructured  []string `xml:"RmtInf>Ustrd"`
+}
+
+func parseCAMT053(content []byte) ([]statementLine, error) {
+	var document camtDocument
+	err := xml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, err
+	}
+	var lines []statementLine
+	for _, statement := range document.Statements {
+		for _, entry := range statement.Entries {
+			var line statementLine
+			date := entry.BookingDate
+			if date == "" {
+				date = entry.BookingDateTime
+			}
+			if date == "" {
+				date = entry.ValueDate
+			}
+			line.TransactionDate, err = parseStatementDate(date)
+			if err != nil {
+				msg := "entry date error: " + date
+				return nil, errors.New(msg)
+			}
+			line.Amount, err = parseStatementAmount(entry.Amount)
+			if err != nil {
+				msg := "entry amount error: " + entry.Amount
+				return nil, errors.New(msg)
+			}
+			if entry.CreditDebit == "DBIT" {
+				line.Amount = -line.Amount
+			}
+			line.ExternalID = entry.AccountServicerRef
+			var descriptions []string
+			for _, detail := range entry.Details {
+				if line.Reference == "" {
+					switch {
+					case detail.CreditorRef != "":
+						line.Reference = detail.CreditorRef
+					case detail.EndToEndID != "" && detail.EndToEndID != "NOTPROVIDED":
+						line.Reference = detail.EndToEndID
+					case detail.InstructionID != "":
+						line.Reference = detail.InstructionID
+					}
+				}
+				descriptions = append(descriptions, detail.Unstructured...)
+			}
+			line.Description = strings.Join(descriptions, " ")
+			if line.Description == "" {
+				line.Description = entry.AdditionalInfo
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		msg := "statement is empty"
+		return nil, errors.New(msg)
+	}
+	return lines, nil
+}
+
+//helpers
+
+var statementDateLayouts = []string{"2006-01-02", "2006/01/02", "01/02/2006", "1/2/2006", "02.01.2006", "20060102"}
+
+func parseStatementDate(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	// ofx and camt dates carry a time part, only the day is kept
+	if len(v) >= 10 && v[4] == '-' && (len(v) == 10 || v[10] == 'T' || v[10] == ' ') {
+		v = v[:10]
+	} else if len(v) > 8 && v[0] >= '0' && v[0] <= '9' && !strings.ContainsAny(v[:8], "-/.") {
+		v = v[:8]
+	}
+	for _, layout := range statementDateLayouts {
+		date, err := time.Parse(layout, v)
+		if err == nil {
+			return date.Format("2006-01-02"), nil
+		}
+	}
+	msg := "date format error"
+	return "", errors.New(msg)
+}
+
+func parseStatementAmount(v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+	negative := false
+	if strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")") {
+		negative = true
+		v = strings.Trim(v, "()")
+	}
+	v = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '-' || r == '+' || r == ',' || r == '.' {
+			return r
+		}
+		return -1
+	}, v)
+	lastComma := strings.LastIndex(v, ",")
+	lastDot := strings.LastIndex(v, ".")
+	if lastComma > lastDot {
+		// decimal comma, as in 1.234,56
+		if len(v)-lastComma-1 <= 2 {
+			v = strings.ReplaceAll(v, ".", "")
+			v = strings.Replace(v, ",", ".", 1)
+		} else {
+			v = strings.ReplaceAll(v, ",", "")
+		}
+	} else {
+		v = strings.ReplaceAll(v, ",", "")
+	}
+	amount, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,296 @@
+package bank
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type bankQuery struct {
+	conn *sqlx.DB
+}
+
+func NewBankQuery(connection *sqlx.DB) *bankQuery {
+	return &bankQuery{
+		conn: connection,
+	}
+}
+
+//statement
+
+func (r *bankQuery) GetBankStatementByID(organizationID, id string) (*BankStatementResponse, error) {
+	var bankStatement BankStatementResponse
+	err := r.conn.Get(&bankStatement, `
+		SELECT
+		b.organization_id,
+		b.bank_statement_id,
+		b.payment_method_id,
+		IFNULL(p.name, "") as payment_method_name,
+		b.file_name,
+		b.format,
+		b.statement_date,
+		b.transaction_count,
+		b.duplicate_count,
+		b.status
+		FROM b_bank_statements b
+		LEFT JOIN s_payment_methods p
+		ON b.payment_method_id = p.payment_method_id
+		WHERE b.organization_id = ? AND b.bank_statement_id = ? AND b.status > 0
+	`, organizationID, id)
+	return &bankStatement, err
+}
+
+func (r *bankQuery) GetBankStatementCount(filter BankStatementFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		where, args = append(where, "payment_method_id = ?"), append(args, v)
+	}
+	if v := filter.Format; v != 0 {
+		where, args = append(where, "format = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM b_bank_statements
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *bankQuery) GetBankStatementList(filter BankStatementFilter) (*[]BankStatementResponse, error) {
+	where, args := []string{"b.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "b.organization_id = ?"), append(args, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		where, args = append(where, "b.payment_method_id = ?"), append(args, v)
+	}
+	if v := filter.Format; v != 0 {
+		where, args = append(where, "b.format = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var bankStatements []BankStatementResponse
+	err := r.conn.Select(&bankStatements, `
+		SELECT
+		b.organization_id,
+		b.bank_statement_id,
+		b.payment_method_id,
+		IFNULL(p.name, "") as payment_method_name,
+		b.file_name,
+		b.format,
+		b.statement_date,
+		b.transaction_count,
+		b.duplicate_count,
+		b.status
+		FROM b_bank_statements b
+		LEFT JOIN s_payment_methods p
+		ON b.payment_method_id = p.payment_method_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY b.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &bankStatements, err
+}
+
+//transaction
+
+func (r *bankQuery) GetBankTransactionByID(organizationID, id string) (*BankTransactionResponse, error) {
+	var bankTransaction BankTransactionResponse
+	err := r.conn.Get(&bankTransaction, `
+		SELECT
+		b.organization_id,
+		b.bank_transaction_id,
+		b.bank_statement_id,
+		b.parent_id,
+		b.payment_method_id,
+		IFNULL(p.name, "") as payment_method_name,
+		b.transaction_date,
+		b.amount,
+		b.matched_amount,
+		b.reference,
+		b.description,
+		b.external_id,
+		b.match_status,
+		b.status
+		FROM b_bank_transactions b
+		LEFT JOIN s_payment_methods p
+		ON b.payment_method_id = p.payment_method_id
+		WHERE b.organization_id = ? AND b.bank_transaction_id = ? AND b.status > 0
+	`, organizationID, id)
+	return &bankTransaction, err
+}
+
+func (r *bankQuery) GetBankTransactionCount(filter BankTransactionFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.BankStatementID; v != "" {
+		where, args = append(where, "bank_statement_id = ?"), append(args, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		where, args = append(where, "payment_method_id = ?"), append(args, v)
+	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "match_status = ?"), append(args, v)
+	}
+	if v := filter.DateFrom; v != "" {
+		where, args = append(where, "transaction_date >= ?"), append(args, v)
+	}
+	if v := filter.DateTo; v != "" {
+		where, args = append(where, "transaction_date <= ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM b_bank_transactions
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *bankQuery) GetBankTransactionList(filter BankTransactionFilter) (*[]BankTransactionResponse, error) {
+	where, args := []string{"b.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "b.organization_id = ?"), append(args, v)
+	}
+	if v := filter.BankStatementID; v != "" {
+		where, args = append(where, "b.bank_statement_id = ?"), append(args, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		where, args = append(where, "b.payment_method_id = ?"), append(args, v)
+	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "b.match_status = ?"), append(args, v)
+	}
+	if v := filter.DateFrom; v != "" {
+		where, args = append(where, "b.transaction_date >= ?"), append(args, v)
+	}
+	if v := filter.DateTo; v != "" {
+		where, args = append(where, "b.transaction_date <= ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var bankTransactions []BankTransactionResponse
+	err := r.conn.Select(&bankTransactions, `
+		SELECT
+		b.organization_id,
+		b.bank_transaction_id,
+		b.bank_statement_id,
+		b.parent_id,
+		b.payment_method_id,
+		IFNULL(p.name, "") as payment_method_name,
+		b.transaction_date,
+		b.amount,
+		b.matched_amount,
+		b.reference,
+		b.description,
+		b.external_id,
+		b.match_status,
+		b.status
+		FROM b_bank_transactions b
+		LEFT JOIN s_payment_methods p
+		ON b.payment_method_id = p.payment_method_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY b.transaction_date DESC, b.id ASC
+		LIMIT ?, ?
+	`, args...)
+	return &bankTransactions, err
+}
+
+func (r *bankQuery) GetBankTransactionMatchList(organizationID, bankTransactionID string) (*[]BankTransactionMatchResponse, error) {
+	var matches []BankTransactionMatchResponse
+	err := r.conn.Select(&matches, `
+		SELECT
+		m.organization_id,
+		m.bank_transaction_match_id,
+		m.bank_transaction_id,
+		m.payment_type,
+		m.payment_id,
+		IFNULL(IF(m.payment_type = 1, r.payment_received_number, p.payment_made_number), "") as payment_number,
+		IFNULL(IF(m.payment_type = 1, r.payment_received_date, p.payment_made_date), "") as payment_date,
+		m.amount,
+		m.match_type,
+		m.status
+		FROM b_bank_transaction_matches m
+		LEFT JOIN s_payment_receiveds r
+		ON m.payment_type = 1 AND m.payment_id = r.payment_received_id
+		LEFT JOIN p_payment_mades p
+		ON m.payment_type = 2 AND m.payment_id = p.payment_made_id
+		WHERE m.organization_id = ? AND m.bank_transaction_id = ? AND m.status > 0
+	`, organizationID, bankTransactionID)
+	return &matches, err
+}
+
+//reconciliation
+
+func (r *bankQuery) GetReconciliationBank(filter ReconciliationFilter) (*[]ReconciliationBalanceResponse, error) {
+	where, args := []string{"status > 0", "match_status != 4"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		where, args = append(where, "payment_method_id = ?"), append(args, v)
+	}
+	if v := filter.DateFrom; v != "" {
+		where, args = append(where, "transaction_date >= ?"), append(args, v)
+	}
+	if v := filter.DateTo; v != "" {
+		where, args = append(where, "transaction_date <= ?"), append(args, v)
+	}
+	var balances []ReconciliationBalanceResponse
+	err := r.conn.Select(&balances, `
+		SELECT
+		payment_method_id,
+		IFNULL(SUM(amount), 0) as balance,
+		IFNULL(SUM(IF(amount < 0, -matched_amount, matched_amount)), 0) as reconciled_amount,
+		IFNULL(SUM(IF(match_status = 3, 0, 1)), 0) as unmatched_count
+		FROM b_bank_transactions
+		WHERE `+strings.Join(where, " AND ")+`
+		GROUP BY payment_method_id
+	`, args...)
+	return &balances, err
+}
+
+func (r *bankQuery) GetReconciliationBook(filter ReconciliationFilter) (*[]ReconciliationBalanceResponse, error) {
+	receivedWhere, madeWhere, receivedArgs, madeArgs := []string{"p.status > 0"}, []string{"p.status > 0"}, []interface{}{}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		receivedWhere, receivedArgs = append(receivedWhere, "p.organization_id = ?"), append(receivedArgs, v)
+		madeWhere, madeArgs = append(madeWhere, "p.organization_id = ?"), append(madeArgs, v)
+	}
+	if v := filter.PaymentMethodID; v != "" {
+		receivedWhere, receivedArgs = append(receivedWhere, "p.payment_method_id = ?"), append(receivedArgs, v)
+		madeWhere, madeArgs = append(madeWhere, "p.payment_method_id = ?"), append(madeArgs, v)
+	}
+	if v := filter.DateFrom; v != "" {
+		receivedWhere, receivedArgs = append(receivedWhere, "p.payment_received_date >= ?"), append(receivedArgs, v)
+		madeWhere, madeArgs = append(madeWhere, "p.payment_made_date >= ?"), append(madeArgs, v)
+	}
+	if v := filter.DateTo; v != "" {
+		receivedWhere, receivedArgs = append(receivedWhere, "p.payment_received_date <= ?"), append(receivedArgs, v)
+		madeWhere, madeArgs = append(madeWhere, "p.payment_made_date <= ?"), append(madeArgs, v)
+	}
+	var balances []ReconciliationBalanceResponse
+	err := r.conn.Select(&balances, `
+		SELECT
+		payment_method_id,
+		IFNULL(SUM(amount), 0) as balance,
+		IFNULL(SUM(matched_amount), 0) as reconciled_amount,
+		IFNULL(SUM(IF(ABS(amount) - ABS(matched_amount) > 0.005, 1, 0)), 0) as unmatched_count
+		FROM (
+			SELECT p.payment_method_id, p.amount,
+			IFNULL((SELECT SUM(m.amount) FROM b_bank_transaction_matches m WHERE m.payment_type = 1 AND m.payment_id = p.payment_received_id AND m.status > 0), 0) as matched_amount
+			FROM s_payment_receiveds p
+			WHERE `+strings.Join(receivedWhere, " AND ")+`
+			UNION ALL
+			SELECT p.payment_method_id, -p.amount,
+			-IFNULL((SELECT SUM(m.amount) FROM b_bank_transaction_matches m WHERE m.payment_type = 2 AND m.payment_id = p.payment_made_id AND m.status > 0), 0) as matched_amount
+			FROM p_payment_mades p
+			WHERE `+strings.Join(madeWhere, " AND ")+`
+		) t
+		GROUP BY payment_method_id
+	`, append(receivedArgs, madeArgs...)...)
+	return &balances, err
+}
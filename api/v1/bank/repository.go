@@ -0,0 +1,279 @@
+package bank
+
+import (
+	"database/sql"
+	"time"
+)
+
+type bankRepository struct {
+	tx *sql.Tx
+}
+
+func NewBankRepository(tx *sql.Tx) *bankRepository {
+	return &bankRepository{tx: tx}
+}
+
+//statement
+
+func (r *bankRepository) CreateBankStatement(info BankStatement) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO b_bank_statements
+		(
+			organization_id,
+			bank_statement_id,
+			payment_method_id,
+			file_name,
+			format,
+			statement_date,
+			transaction_count,
+			duplicate_count,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BankStatementID, info.PaymentMethodID, info.FileName, info.Format, info.StatementDate, info.TransactionCount, info.DuplicateCount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *bankRepository) GetBankStatementByID(organizationID, id string) (*BankStatementResponse, error) {
+	var res BankStatementResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, bank_statement_id, payment_method_id, file_name, format, statement_date, transaction_count, duplicate_count, status
+		FROM b_bank_statements
+		WHERE organization_id = ? AND bank_statement_id = ? AND status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.BankStatementID, &res.PaymentMethodID, &res.FileName, &res.Format, &res.StatementDate, &res.TransactionCount, &res.DuplicateCount, &res.Status)
+	return &res, err
+}
+
+func (r *bankRepository) GetBankStatementMatchedCount(bankStatementID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM b_bank_transactions WHERE bank_statement_id = ? AND matched_amount > 0 AND status > 0", bankStatementID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *bankRepository) DeleteBankStatement(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_bank_statements SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE bank_statement_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	_, err = r.tx.Exec(`
+		UPDATE b_bank_transactions SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE bank_statement_id = ? AND status > 0
+	`, time.Now(), byUser, id)
+	return err
+}
+
+//transaction
+
+func (r *bankRepository) CheckBankTransactionExist(organizationID, paymentMethodID, externalID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM b_bank_transactions WHERE organization_id = ? AND payment_method_id = ? AND external_id = ? AND status > 0", organizationID, paymentMethodID, externalID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *bankRepository) CreateBankTransaction(info BankTransaction) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO b_bank_transactions
+		(
+			organization_id,
+			bank_transaction_id,
+			bank_statement_id,
+			parent_id,
+			payment_method_id,
+			transaction_date,
+			amount,
+			matched_amount,
+			reference,
+			description,
+			external_id,
+			match_status,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BankTransactionID, info.BankStatementID, info.ParentID, info.PaymentMethodID, info.TransactionDate, info.Amount, info.MatchedAmount, info.Reference, info.Description, info.ExternalID, info.MatchStatus, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *bankRepository) GetBankTransactionByID(organizationID, id string) (*BankTransactionResponse, error) {
+	var res BankTransactionResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, bank_transaction_id, bank_statement_id, parent_id, payment_method_id, transaction_date, amount, matched_amount, reference, description, external_id, match_status, status
+		FROM b_bank_transactions
+		WHERE organization_id = ? AND bank_transaction_id = ? AND status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.BankTransactionID, &res.BankStatementID, &res.ParentID, &res.PaymentMethodID, &res.TransactionDate, &res.Amount, &res.MatchedAmount, &res.Reference, &res.Description, &res.ExternalID, &res.MatchStatus, &res.Status)
+	return &res, err
+}
+
+func (r *bankRepository) GetUnmatchedBankTransactionList(organizationID, bankStatementID, paymentMethodID string) (*[]BankTransactionResponse, error) {
+	query := `
+		SELECT organization_id, bank_transaction_id, bank_statement_id, parent_id, payment_method_id, transaction_date, amount, matched_amount, reference, description, external_id, match_status, status
+		FROM b_bank_transactions
+		WHERE organization_id = ? AND match_status = 1 AND status > 0`
+	args := []interface{}{organizationID}
+	if bankStatementID != "" {
+		query += " AND bank_statement_id = ?"
+		args = append(args, bankStatementID)
+	}
+	if paymentMethodID != "" {
+		query += " AND payment_method_id = ?"
+		args = append(args, paymentMethodID)
+	}
+	rows, err := r.tx.Query(query+" ORDER BY transaction_date ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []BankTransactionResponse
+	for rows.Next() {
+		var row BankTransactionResponse
+		err = rows.Scan(&row.OrganizationID, &row.BankTransactionID, &row.BankStatementID, &row.ParentID, &row.PaymentMethodID, &row.TransactionDate, &row.Amount, &row.MatchedAmount, &row.Reference, &row.Description, &row.ExternalID, &row.MatchStatus, &row.Status)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+	return &res, rows.Err()
+}
+
+func (r *bankRepository) UpdateBankTransactionMatched(id string, matchedAmount float64, matchStatus int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_bank_transactions SET
+		matched_amount = ?,
+		match_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE bank_transaction_id = ?
+	`, matchedAmount, matchStatus, time.Now(), byUser, id)
+	return err
+}
+
+//match
+
+func (r *bankRepository) CreateBankTransactionMatch(info BankTransactionMatch) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO b_bank_transaction_matches
+		(
+			organization_id,
+			bank_transaction_match_id,
+			bank_transaction_id,
+			payment_type,
+			payment_id,
+			amount,
+			match_type,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BankTransactionMatchID, info.BankTransactionID, info.PaymentType, info.PaymentID, info.Amount, info.MatchType, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *bankRepository) GetBankTransactionMatchByID(organizationID, id string) (*BankTransactionMatchResponse, error) {
+	var res BankTransactionMatchResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, bank_transaction_match_id, bank_transaction_id, payment_type, payment_id, amount, match_type, status
+		FROM b_bank_transaction_matches
+		WHERE organization_id = ? AND bank_transaction_match_id = ? AND status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.BankTransactionMatchID, &res.BankTransactionID, &res.PaymentType, &res.PaymentID, &res.Amount, &res.MatchType, &res.Status)
+	return &res, err
+}
+
+func (r *bankRepository) DeleteBankTransactionMatch(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_bank_transaction_matches SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE bank_transaction_match_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+//payment
+
+const paymentReceivedCandidateSQL = `
+	SELECT 1 as payment_type, p.payment_received_id, p.payment_received_number, p.payment_received_date, p.payment_method_id, IFNULL(i.invoice_number, ""), p.amount,
+	IFNULL((SELECT SUM(m.amount) FROM b_bank_transaction_matches m WHERE m.payment_type = 1 AND m.payment_id = p.payment_received_id AND m.status > 0), 0)
+	FROM s_payment_receiveds p
+	LEFT JOIN s_invoices i
+	ON p.invoice_id = i.invoice_id
+`
+
+const paymentMadeCandidateSQL = `
+	SELECT 2 as payment_type, p.payment_made_id, p.payment_made_number, p.payment_made_date, p.payment_method_id, IFNULL(b.bill_number, ""), p.amount,
+	IFNULL((SELECT SUM(m.amount) FROM b_bank_transaction_matches m WHERE m.payment_type = 2 AND m.payment_id = p.payment_made_id AND m.status > 0), 0)
+	FROM p_payment_mades p
+	LEFT JOIN p_bills b
+	ON p.bill_id = b.bill_id
+`
+
+func (r *bankRepository) GetPaymentByID(organizationID string, paymentType int, paymentID string) (*PaymentCandidateResponse, error) {
+	var res PaymentCandidateResponse
+	var row *sql.Row
+	if paymentType == 1 {
+		row = r.tx.QueryRow(paymentReceivedCandidateSQL+" WHERE p.organization_id = ? AND p.payment_received_id = ? AND p.status > 0 LIMIT 1", organizationID, paymentID)
+	} else {
+		row = r.tx.QueryRow(paymentMadeCandidateSQL+" WHERE p.organization_id = ? AND p.payment_made_id = ? AND p.status > 0 LIMIT 1", organizationID, paymentID)
+	}
+	err := row.Scan(&res.PaymentType, &res.PaymentID, &res.PaymentNumber, &res.PaymentDate, &res.PaymentMethodID, &res.DocumentNumber, &res.Amount, &res.MatchedAmount)
+	return &res, err
+}
+
+// GetPaymentCandidateList returns the unmatched payments of a payment method
+// with the given amount, paid within the date range
+func (r *bankRepository) GetPaymentCandidateList(organizationID string, paymentType int, paymentMethodID string, amount float64, dateFrom, dateTo string) (*[]PaymentCandidateResponse, error) {
+	var query string
+	if paymentType == 1 {
+		query = paymentReceivedCandidateSQL + " WHERE p.organization_id = ? AND p.payment_method_id = ? AND ABS(p.amount - ?) < 0.005 AND p.payment_received_date BETWEEN ? AND ? AND p.status > 0"
+	} else {
+		query = paymentMadeCandidateSQL + " WHERE p.organization_id = ? AND p.payment_method_id = ? AND ABS(p.amount - ?) < 0.005 AND p.payment_made_date BETWEEN ? AND ? AND p.status > 0"
+	}
+	rows, err := r.tx.Query(query, organizationID, paymentMethodID, amount, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []PaymentCandidateResponse
+	for rows.Next() {
+		var row PaymentCandidateResponse
+		err = rows.Scan(&row.PaymentType, &row.PaymentID, &row.PaymentNumber, &row.PaymentDate, &row.PaymentMethodID, &row.DocumentNumber, &row.Amount, &row.MatchedAmount)
+		if err != nil {
+			return nil, err
+		}
+		if row.MatchedAmount > 0 {
+			continue
+		}
+		res = append(res, row)
+	}
+	return &res, rows.Err()
+}
@@ -0,0 +1,21 @@
+package bank
+
+import "github.com/gin-gonic/gin"
+
+func AuthRouter(g *gin.RouterGroup) {
+	g.POST("/bankstatements", NewBankStatement)
+	g.GET("/bankstatements", GetBankStatementList)
+	g.GET("/bankstatements/:id", GetBankStatementByID)
+	g.DELETE("/bankstatements/:id", DeleteBankStatement)
+
+	g.GET("/banktransactions", GetBankTransactionList)
+	g.POST("/banktransactions/automatch", AutoMatchBankTransaction)
+	g.GET("/banktransactions/:id/matches", GetBankTransactionMatchList)
+	g.POST("/banktransactions/:id/matches", MatchBankTransaction)
+	g.POST("/banktransactions/:id/split", SplitBankTransaction)
+	g.POST("/banktransactions/:id/payments", NewPaymentFromBankTransaction)
+	g.DELETE("/banktransactionmatches/:id", DeleteBankTransactionMatch)
+
+	g.GET("/reconciliations", GetReconciliationSummary)
+
+}
@@ -0,0 +1,622 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/api/v1/common"
+	"go-api/api/v1/purchaseorder"
+	"go-api/api/v1/salesorder"
+	"go-api/api/v1/setting"
+	"go-api/core/database"
+	"go-api/core/queue"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+type bankService struct {
+}
+
+func NewBankService() *bankService {
+	return &bankService{}
+}
+
+//statement
+
+func (s *bankService) NewBankStatement(info BankStatementNew, fileName string, content []byte) (*string, error) {
+	lines, err := parseStatement(info.Format, content)
+	if err != nil {
+		msg := "parse statement error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	settingRepo := setting.NewSettingRepository(tx)
+	_, err = settingRepo.GetPaymentMethodByID(info.OrganizationID, info.PaymentMethodID)
+	if err != nil {
+		msg := "payment method not exist"
+		return nil, errors.New(msg)
+	}
+	bankStatementID := "bst-" + xid.New().String()
+	statementDate := ""
+	transactionCount := 0
+	duplicateCount := 0
+	for _, line := range lines {
+		// lines without a bank id are identified by their content, so a
+		// statement imported twice does not create the transactions again
+		externalID := line.ExternalID
+		if externalID == "" {
+			externalID = fmt.Sprintf("%s|%.2f|%s|%s", line.TransactionDate, line.Amount, line.Reference, line.Description)
+		}
+		isExist, err := repo.CheckBankTransactionExist(info.OrganizationID, info.PaymentMethodID, externalID)
+		if err != nil {
+			msg := "check bank transaction error: "
+			return nil, errors.New(msg)
+		}
+		if isExist {
+			duplicateCount++
+			continue
+		}
+		var bankTransaction BankTransaction
+		bankTransaction.OrganizationID = info.OrganizationID
+		bankTransaction.BankTransactionID = "btx-" + xid.New().String()
+		bankTransaction.BankStatementID = bankStatementID
+		bankTransaction.ParentID = ""
+		bankTransaction.PaymentMethodID = info.PaymentMethodID
+		bankTransaction.TransactionDate = line.TransactionDate
+		bankTransaction.Amount = line.Amount
+		bankTransaction.MatchedAmount = 0
+		bankTransaction.Reference = line.Reference
+		bankTransaction.Description = line.Description
+		bankTransaction.ExternalID = externalID
+		bankTransaction.MatchStatus = 1
+		bankTransaction.Status = 1
+		bankTransaction.Created = time.Now()
+		bankTransaction.CreatedBy = info.Email
+		bankTransaction.Updated = time.Now()
+		bankTransaction.UpdatedBy = info.Email
+		err = repo.CreateBankTransaction(bankTransaction)
+		if err != nil {
+			msg := "create bank transaction error: "
+			return nil, errors.New(msg)
+		}
+		transactionCount++
+		if line.TransactionDate > statementDate {
+			statementDate = line.TransactionDate
+		}
+	}
+	var bankStatement BankStatement
+	bankStatement.OrganizationID = info.OrganizationID
+	bankStatement.BankStatementID = bankStatementID
+	bankStatement.PaymentMethodID = info.PaymentMethodID
+	bankStatement.FileName = fileName
+	bankStatement.Format = info.Format
+	bankStatement.StatementDate = statementDate
+	bankStatement.TransactionCount = transactionCount
+	bankStatement.DuplicateCount = duplicateCount
+	bankStatement.Status = 1
+	bankStatement.Created = time.Now()
+	bankStatement.CreatedBy = info.Email
+	bankStatement.Updated = time.Now()
+	bankStatement.UpdatedBy = info.Email
+	err = repo.CreateBankStatement(bankStatement)
+	if err != nil {
+		msg := "create bank statement error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &bankStatementID, nil
+}
+
+func (s *bankService) GetBankStatementList(filter BankStatementFilter) (int, *[]BankStatementResponse, error) {
+	db := database.RDB()
+	query := NewBankQuery(db)
+	count, err := query.GetBankStatementCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetBankStatementList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *bankService) GetBankStatementByID(organizationID, id string) (*BankStatementResponse, error) {
+	db := database.RDB()
+	query := NewBankQuery(db)
+	bankStatement, err := query.GetBankStatementByID(organizationID, id)
+	if err != nil {
+		msg := "get bank statement error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return bankStatement, nil
+}
+
+func (s *bankService) DeleteBankStatement(bankStatementID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	_, err = repo.GetBankStatementByID(organizationID, bankStatementID)
+	if err != nil {
+		msg := "bank statement not exist"
+		return errors.New(msg)
+	}
+	matchedCount, err := repo.GetBankStatementMatchedCount(bankStatementID)
+	if err != nil {
+		msg := "get matched count error"
+		return errors.New(msg)
+	}
+	if matchedCount > 0 {
+		msg := "bank statement has matched transactions"
+		return errors.New(msg)
+	}
+	err = repo.DeleteBankStatement(bankStatementID, email)
+	if err != nil {
+		msg := "delete bank statement error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+//transaction
+
+func (s *bankService) GetBankTransactionList(filter BankTransactionFilter) (int, *[]BankTransactionResponse, error) {
+	db := database.RDB()
+	query := NewBankQuery(db)
+	count, err := query.GetBankTransactionCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetBankTransactionList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *bankService) GetBankTransactionMatchList(bankTransactionID, organizationID string) (*[]BankTransactionMatchResponse, error) {
+	db := database.RDB()
+	query := NewBankQuery(db)
+	_, err := query.GetBankTransactionByID(organizationID, bankTransactionID)
+	if err != nil {
+		msg := "get bank transaction error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetBankTransactionMatchList(organizationID, bankTransactionID)
+	return list, err
+}
+
+func bankTransactionMatchStatus(amount, matchedAmount float64) int {
+	if matchedAmount <= 0.005 {
+		return 1
+	}
+	if math.Abs(amount)-matchedAmount > 0.005 {
+		return 2
+	}
+	return 3
+}
+
+func bankTransactionPaymentType(amount float64) int {
+	if amount > 0 {
+		return 1
+	}
+	return 2
+}
+
+func createBankTransactionMatch(repo *bankRepository, bankTransaction *BankTransactionResponse, payment *PaymentCandidateResponse, amount float64, matchType int, organizationID, email string) error {
+	var match BankTransactionMatch
+	match.OrganizationID = organizationID
+	match.BankTransactionMatchID = "btm-" + xid.New().String()
+	match.BankTransactionID = bankTransaction.BankTransactionID
+	match.PaymentType = payment.PaymentType
+	match.PaymentID = payment.PaymentID
+	match.Amount = amount
+	match.MatchType = matchType
+	match.Status = 1
+	match.Created = time.Now()
+	match.CreatedBy = email
+	match.Updated = time.Now()
+	match.UpdatedBy = email
+	err := repo.CreateBankTransactionMatch(match)
+	if err != nil {
+		return err
+	}
+	matchedAmount := bankTransaction.MatchedAmount + amount
+	return repo.UpdateBankTransactionMatched(bankTransaction.BankTransactionID, matchedAmount, bankTransactionMatchStatus(bankTransaction.Amount, matchedAmount), email)
+}
+
+// AutoMatchBankTransaction matches unmatched transactions to payments of the
+// same payment method with the same amount paid within the date window.
+// When several payments qualify, the one whose number or invoice / bill number
+// appears in the bank reference wins; ambiguous lines are left for review.
+func (s *bankService) AutoMatchBankTransaction(info AutoMatchNew) (*AutoMatchResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	window := info.DateWindow
+	if window == 0 {
+		window = 3
+	}
+	bankTransactions, err := repo.GetUnmatchedBankTransactionList(info.OrganizationID, info.BankStatementID, info.PaymentMethodID)
+	if err != nil {
+		msg := "get bank transaction error: "
+		return nil, errors.New(msg)
+	}
+	var res AutoMatchResponse
+	used := make(map[string]bool)
+	for _, bankTransaction := range *bankTransactions {
+		res.TransactionCount++
+		if bankTransaction.Amount == 0 {
+			continue
+		}
+		transactionDate, err := time.Parse("2006-01-02", bankTransaction.TransactionDate)
+		if err != nil {
+			continue
+		}
+		dateFrom := transactionDate.AddDate(0, 0, -window).Format("2006-01-02")
+		dateTo := transactionDate.AddDate(0, 0, window).Format("2006-01-02")
+		candidates, err := repo.GetPaymentCandidateList(info.OrganizationID, bankTransactionPaymentType(bankTransaction.Amount), bankTransaction.PaymentMethodID, math.Abs(bankTransaction.Amount), dateFrom, dateTo)
+		if err != nil {
+			msg := "get payment candidate error: "
+			return nil, errors.New(msg)
+		}
+		reference := strings.ToLower(bankTransaction.Reference + " " + bankTransaction.Description)
+		var available, referenced []PaymentCandidateResponse
+		for _, candidate := range *candidates {
+			if used[candidate.PaymentID] {
+				continue
+			}
+			available = append(available, candidate)
+			if (candidate.PaymentNumber != "" && strings.Contains(reference, strings.ToLower(candidate.PaymentNumber))) || (candidate.DocumentNumber != "" && strings.Contains(reference, strings.ToLower(candidate.DocumentNumber))) {
+				referenced = append(referenced, candidate)
+			}
+		}
+		var payment *PaymentCandidateResponse
+		if len(referenced) == 1 {
+			payment = &referenced[0]
+		} else if len(referenced) == 0 && len(available) == 1 {
+			payment = &available[0]
+		}
+		if payment == nil {
+			continue
+		}
+		bankTransactionRow := bankTransaction
+		err = createBankTransactionMatch(repo, &bankTransactionRow, payment, payment.Amount, 1, info.OrganizationID, info.Email)
+		if err != nil {
+			msg := "create bank transaction match error: "
+			return nil, errors.New(msg)
+		}
+		used[payment.PaymentID] = true
+		res.MatchedCount++
+	}
+	tx.Commit()
+	return &res, nil
+}
+
+func (s *bankService) MatchBankTransaction(bankTransactionID string, info BankTransactionMatchNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	bankTransaction, err := repo.GetBankTransactionByID(info.OrganizationID, bankTransactionID)
+	if err != nil {
+		msg := "bank transaction not exist"
+		return nil, errors.New(msg)
+	}
+	if bankTransaction.MatchStatus == 3 || bankTransaction.MatchStatus == 4 {
+		msg := "bank transaction can not be matched"
+		return nil, errors.New(msg)
+	}
+	if bankTransactionPaymentType(bankTransaction.Amount) != info.PaymentType {
+		msg := "payment type not match bank transaction"
+		return nil, errors.New(msg)
+	}
+	payment, err := repo.GetPaymentByID(info.OrganizationID, info.PaymentType, info.PaymentID)
+	if err != nil {
+		msg := "payment not exist"
+		return nil, errors.New(msg)
+	}
+	if payment.PaymentMethodID != bankTransaction.PaymentMethodID {
+		msg := "payment method not match"
+		return nil, errors.New(msg)
+	}
+	transactionRemaining := math.Abs(bankTransaction.Amount) - bankTransaction.MatchedAmount
+	paymentRemaining := payment.Amount - payment.MatchedAmount
+	amount := info.Amount
+	if amount == 0 {
+		amount = math.Min(transactionRemaining, paymentRemaining)
+	}
+	if amount <= 0 || amount-transactionRemaining > 0.005 {
+		msg := "match amount error"
+		return nil, errors.New(msg)
+	}
+	if amount-paymentRemaining > 0.005 {
+		msg := "payment already matched"
+		return nil, errors.New(msg)
+	}
+	err = createBankTransactionMatch(repo, bankTransaction, payment, amount, 2, info.OrganizationID, info.Email)
+	if err != nil {
+		msg := "create bank transaction match error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &bankTransactionID, nil
+}
+
+func (s *bankService) DeleteBankTransactionMatch(bankTransactionMatchID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	match, err := repo.GetBankTransactionMatchByID(organizationID, bankTransactionMatchID)
+	if err != nil {
+		msg := "bank transaction match not exist"
+		return errors.New(msg)
+	}
+	bankTransaction, err := repo.GetBankTransactionByID(organizationID, match.BankTransactionID)
+	if err != nil {
+		msg := "bank transaction not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteBankTransactionMatch(bankTransactionMatchID, email)
+	if err != nil {
+		msg := "delete bank transaction match error: "
+		return errors.New(msg)
+	}
+	matchedAmount := bankTransaction.MatchedAmount - match.Amount
+	if matchedAmount < 0 {
+		matchedAmount = 0
+	}
+	err = repo.UpdateBankTransactionMatched(bankTransaction.BankTransactionID, matchedAmount, bankTransactionMatchStatus(bankTransaction.Amount, matchedAmount), email)
+	if err != nil {
+		msg := "update bank transaction error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// SplitBankTransaction replaces an unmatched transaction with several lines
+// of the same direction, each matched on its own
+func (s *bankService) SplitBankTransaction(bankTransactionID string, info BankTransactionSplitNew) (*[]string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	bankTransaction, err := repo.GetBankTransactionByID(info.OrganizationID, bankTransactionID)
+	if err != nil {
+		msg := "bank transaction not exist"
+		return nil, errors.New(msg)
+	}
+	if bankTransaction.MatchStatus != 1 {
+		msg := "only unmatched bank transaction can be split"
+		return nil, errors.New(msg)
+	}
+	total := 0.0
+	for _, itemRow := range info.Items {
+		if (itemRow.Amount > 0) != (bankTransaction.Amount > 0) {
+			msg := "split amount direction error"
+			return nil, errors.New(msg)
+		}
+		total += itemRow.Amount
+	}
+	if math.Abs(total-bankTransaction.Amount) > 0.005 {
+		msg := "split total not equal to bank transaction amount"
+		return nil, errors.New(msg)
+	}
+	var ids []string
+	for idx, itemRow := range info.Items {
+		var splitTransaction BankTransaction
+		splitTransaction.OrganizationID = info.OrganizationID
+		splitTransaction.BankTransactionID = "btx-" + xid.New().String()
+		splitTransaction.BankStatementID = bankTransaction.BankStatementID
+		splitTransaction.ParentID = bankTransactionID
+		splitTransaction.PaymentMethodID = bankTransaction.PaymentMethodID
+		splitTransaction.TransactionDate = bankTransaction.TransactionDate
+		splitTransaction.Amount = itemRow.Amount
+		splitTransaction.MatchedAmount = 0
+		splitTransaction.Reference = itemRow.Reference
+		if splitTransaction.Reference == "" {
+			splitTransaction.Reference = bankTransaction.Reference
+		}
+		splitTransaction.Description = itemRow.Description
+		if splitTransaction.Description == "" {
+			splitTransaction.Description = bankTransaction.Description
+		}
+		splitTransaction.ExternalID = fmt.Sprintf("%s-%d", bankTransaction.ExternalID, idx+1)
+		splitTransaction.MatchStatus = 1
+		splitTransaction.Status = 1
+		splitTransaction.Created = time.Now()
+		splitTransaction.CreatedBy = info.Email
+		splitTransaction.Updated = time.Now()
+		splitTransaction.UpdatedBy = info.Email
+		err = repo.CreateBankTransaction(splitTransaction)
+		if err != nil {
+			msg := "create bank transaction error: "
+			return nil, errors.New(msg)
+		}
+		ids = append(ids, splitTransaction.BankTransactionID)
+	}
+	err = repo.UpdateBankTransactionMatched(bankTransactionID, 0, 4, info.Email)
+	if err != nil {
+		msg := "update bank transaction error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &ids, nil
+}
+
+// NewPaymentFromBankTransaction records the unmatched amount of a bank line
+// as a payment of an invoice or a bill and matches it to the line
+func (s *bankService) NewPaymentFromBankTransaction(bankTransactionID string, info BankTransactionPaymentNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBankRepository(tx)
+	bankTransaction, err := repo.GetBankTransactionByID(info.OrganizationID, bankTransactionID)
+	if err != nil {
+		msg := "bank transaction not exist"
+		return nil, errors.New(msg)
+	}
+	if bankTransaction.MatchStatus == 3 || bankTransaction.MatchStatus == 4 {
+		msg := "bank transaction can not be matched"
+		return nil, errors.New(msg)
+	}
+	amount := math.Abs(bankTransaction.Amount) - bankTransaction.MatchedAmount
+	paymentType := bankTransactionPaymentType(bankTransaction.Amount)
+	var paymentID string
+	var newEvent common.NewHistoryCreated
+	if paymentType == 1 {
+		if info.InvoiceID == "" {
+			msg := "invoice id required for money in"
+			return nil, errors.New(msg)
+		}
+		var paymentInfo salesorder.PaymentReceivedNew
+		paymentInfo.PaymentReceivedNumber = info.PaymentNumber
+		paymentInfo.PaymentReceivedDate = bankTransaction.TransactionDate
+		paymentInfo.PaymentMethodID = bankTransaction.PaymentMethodID
+		paymentInfo.Amount = amount
+		paymentInfo.Notes = info.Notes
+		paymentInfo.OrganizationID = info.OrganizationID
+		paymentInfo.User = info.User
+		paymentInfo.Email = info.Email
+		paymentID, err = salesorder.CreatePaymentReceived(tx, info.InvoiceID, paymentInfo)
+		newEvent.HistoryType = "invoice"
+		newEvent.ReferenceID = info.InvoiceID
+		newEvent.Description = "Payment Received Created"
+	} else {
+		if info.BillID == "" {
+			msg := "bill id required for money out"
+			return nil, errors.New(msg)
+		}
+		var paymentInfo purchaseorder.PaymentMadeNew
+		paymentInfo.PaymentMadeNumber = info.PaymentNumber
+		paymentInfo.PaymentMadeDate = bankTransaction.TransactionDate
+		paymentInfo.PaymentMethodID = bankTransaction.PaymentMethodID
+		paymentInfo.Amount = amount
+		paymentInfo.Notes = info.Notes
+		paymentInfo.OrganizationID = info.OrganizationID
+		paymentInfo.User = info.User
+		paymentInfo.Email = info.Email
+		paymentID, err = purchaseorder.CreatePaymentMade(tx, info.BillID, paymentInfo)
+		newEvent.HistoryType = "bill"
+		newEvent.ReferenceID = info.BillID
+		newEvent.Description = "Payment Made Created"
+	}
+	if err != nil {
+		return nil, err
+	}
+	payment, err := repo.GetPaymentByID(info.OrganizationID, paymentType, paymentID)
+	if err != nil {
+		msg := "payment not exist"
+		return nil, errors.New(msg)
+	}
+	err = createBankTransactionMatch(repo, bankTransaction, payment, amount, 2, info.OrganizationID, info.Email)
+	if err != nil {
+		msg := "create bank transaction match error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = info.User
+	newEvent.OrganizationID = info.OrganizationID
+	newEvent.Email = info.Email
+	rabbit, _ := queue.GetConn()
+	msg, _ := json.Marshal(newEvent)
+	err = rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return nil, errors.New(msg)
+	}
+	return &paymentID, nil
+}
+
+//reconciliation
+
+func (s *bankService) GetReconciliationSummary(filter ReconciliationFilter) (*[]ReconciliationResponse, error) {
+	db := database.RDB()
+	query := NewBankQuery(db)
+	settingQuery := setting.NewSettingQuery(db)
+	bankBalances, err := query.GetReconciliationBank(filter)
+	if err != nil {
+		return nil, err
+	}
+	bookBalances, err := query.GetReconciliationBook(filter)
+	if err != nil {
+		return nil, err
+	}
+	summary := make(map[string]*ReconciliationResponse)
+	get := func(paymentMethodID string) *ReconciliationResponse {
+		row, ok := summary[paymentMethodID]
+		if !ok {
+			row = &ReconciliationResponse{PaymentMethodID: paymentMethodID}
+			paymentMethod, err := settingQuery.GetPaymentMethodByID(filter.OrganizationID, paymentMethodID)
+			if err == nil {
+				row.PaymentMethodName = paymentMethod.Name
+			}
+			summary[paymentMethodID] = row
+		}
+		return row
+	}
+	for _, balance := range *bankBalances {
+		row := get(balance.PaymentMethodID)
+		row.BankBalance = balance.Balance
+		row.BankReconciledAmount = balance.ReconciledAmount
+		row.BankUnreconciledAmount = balance.Balance - balance.ReconciledAmount
+		row.BankUnreconciledCount = balance.UnmatchedCount
+	}
+	for _, balance := range *bookBalances {
+		row := get(balance.PaymentMethodID)
+		row.BookBalance = balance.Balance
+		row.BookReconciledAmount = balance.ReconciledAmount
+		row.BookUnreconciledAmount = balance.Balance - balance.ReconciledAmount
+		row.BookUnreconciledCount = balance.UnmatchedCount
+	}
+	var res []ReconciliationResponse
+	for _, row := range summary {
+		row.Difference = math.Round((row.BankBalance-row.BookBalance)*100) / 100
+		res = append(res, *row)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PaymentMethodName < res[j].PaymentMethodName
+	})
+	return &res, nil
+}
@@ -0,0 +1,264 @@
+package bulk
+
+import (
+	"errors"
+	"go-api/core/response"
+	"go-api/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 上传导入文件
+// @Id 1101
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "CSV或XLSX文件"
+// @Param import_type formData string true "类型 items customers vendors locations barcodes"
+// @Param format formData int false "格式 1CSV 2XLSX 不传时按文件后缀判断"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports [POST]
+func NewImportJob(c *gin.Context) {
+	var info ImportJobNew
+	if err := c.ShouldBind(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	if file.Size > 10<<20 {
+		response.ResponseError(c, "BindingError", errors.New("file too large"))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bulkService := NewBulkService()
+	new, err := bulkService.NewImportJob(info, file.Filename, content)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 导入任务列表
+// @Id 1102
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param import_type query string false "类型"
+// @Param import_status query int false "状态 1已上传 2已校验 3已导入 4部分导入"
+// @Success 200 object response.ListRes{data=[]ImportJobResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports [GET]
+func GetImportJobList(c *gin.Context) {
+	var filter ImportJobFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	bulkService := NewBulkService()
+	count, list, err := bulkService.GetImportJobList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取导入任务及字段映射建议
+// @Id 1103
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "导入任务ID"
+// @Success 200 object response.SuccessRes{data=ImportJobDetailResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports/:id [GET]
+func GetImportJobByID(c *gin.Context) {
+	var uri ImportJobID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bulkService := NewBulkService()
+	importJob, err := bulkService.GetImportJobByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, importJob)
+}
+
+// @Summary 保存字段映射并校验导入数据
+// @Id 1104
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "导入任务ID"
+// @Param info body ImportJobValidate true "字段映射"
+// @Success 200 object response.SuccessRes{data=ImportJobDetailResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports/:id/validate [POST]
+func ValidateImportJob(c *gin.Context) {
+	var uri ImportJobID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ImportJobValidate
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bulkService := NewBulkService()
+	importJob, err := bulkService.ValidateImportJob(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, importJob)
+}
+
+// @Summary 导入数据行报告
+// @Id 1105
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "导入任务ID"
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param row_status query int false "状态 1待校验 2有效 3无效 4已导入 5导入失败"
+// @Success 200 object response.ListRes{data=[]ImportJobRowResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports/:id/rows [GET]
+func GetImportJobRowList(c *gin.Context) {
+	var uri ImportJobID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var filter ImportJobRowFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	filter.ImportJobID = uri.ID
+	bulkService := NewBulkService()
+	count, list, err := bulkService.GetImportJobRowList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 执行导入或预览
+// @Id 1106
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "导入任务ID"
+// @Param info body ImportJobCommit true "导入方式"
+// @Success 200 object response.SuccessRes{data=ImportJobCommitResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /imports/:id/commit [POST]
+func CommitImportJob(c *gin.Context) {
+	var uri ImportJobID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ImportJobCommit
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	bulkService := NewBulkService()
+	res, err := bulkService.CommitImportJob(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 导出数据
+// @Id 1107
+// @Tags 批量导入导出
+// @version 1.0
+// @Accept application/json
+// @Produce application/octet-stream
+// @Param type path string true "类型 items customers vendors locations barcodes"
+// @Param format query string false "格式 csv xlsx"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /exports/:type [GET]
+func ExportData(c *gin.Context) {
+	var uri ExportType
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var filter ExportFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	bulkService := NewBulkService()
+	content, fileName, err := bulkService.ExportData(claims.OrganizationID, uri.Type, filter.Format)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	contentType := "text/csv"
+	if filter.Format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", "attachment; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, contentType, content)
+}
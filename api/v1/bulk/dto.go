@@ -0,0 +1,99 @@
+package bulk
+
+import "go-api/core/request"
+
+type ImportJobNew struct {
+	ImportType     string `form:"import_type" binding:"required,oneof=items customers vendors locations barcodes"`
+	Format         int    `form:"format" binding:"omitempty,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type ImportJobFilter struct {
+	ImportType     string `form:"import_type" binding:"omitempty,oneof=items customers vendors locations barcodes"`
+	ImportStatus   int    `form:"import_status" binding:"omitempty,oneof=1 2 3 4"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ImportJobResponse struct {
+	OrganizationID string `db:"organization_id" json:"organization_id"`
+	ImportJobID    string `db:"import_job_id" json:"import_job_id"`
+	ImportType     string `db:"import_type" json:"import_type"`
+	FileName       string `db:"file_name" json:"file_name"`
+	Format         int    `db:"format" json:"format"`
+	RowCount       int    `db:"row_count" json:"row_count"`
+	ValidCount     int    `db:"valid_count" json:"valid_count"`
+	InvalidCount   int    `db:"invalid_count" json:"invalid_count"`
+	ImportedCount  int    `db:"imported_count" json:"imported_count"`
+	FailedCount    int    `db:"failed_count" json:"failed_count"`
+	ImportStatus   int    `db:"import_status" json:"import_status"`
+	Status         int    `db:"status" json:"status"`
+}
+
+type ImportJobDetailResponse struct {
+	ImportJobResponse
+	Headers []string              `json:"headers"`
+	Mapping map[string]string     `json:"mapping"`
+	Fields  []ImportFieldResponse `json:"fields"`
+}
+
+type ImportFieldResponse struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type ImportJobID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ImportJobValidate struct {
+	Mapping        map[string]string `json:"mapping" binding:"required"`
+	OrganizationID string            `json:"organiztion_id" swaggerignore:"true"`
+	User           string            `json:"user" swaggerignore:"true"`
+	Email          string            `json:"email" swaggerignore:"true"`
+}
+
+type ImportJobCommit struct {
+	Mode           int    `json:"mode" binding:"required,oneof=1 2"` //1 one transaction 2 chunks
+	ChunkSize      int    `json:"chunk_size" binding:"omitempty,min=1,max=1000"`
+	DryRun         int    `json:"dry_run" binding:"omitempty,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type ImportJobCommitResponse struct {
+	ImportJobID   string                 `json:"import_job_id"`
+	DryRun        int                    `json:"dry_run"`
+	ImportedCount int                    `json:"imported_count"`
+	FailedCount   int                    `json:"failed_count"`
+	Rows          []ImportJobRowResponse `json:"rows"`
+}
+
+type ImportJobRowFilter struct {
+	RowStatus      int    `form:"row_status" binding:"omitempty,oneof=1 2 3 4 5"`
+	ImportJobID    string `json:"import_job_id" swaggerignore:"true"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ImportJobRowResponse struct {
+	ImportJobID string   `db:"import_job_id" json:"import_job_id"`
+	LineNumber  int      `db:"line_number" json:"line_number"`
+	Data        string   `db:"data" json:"-"`
+	Cells       []string `json:"cells"`
+	RowStatus   int      `db:"row_status" json:"row_status"`
+	Error       string   `db:"error" json:"error"`
+	ReferenceID string   `db:"reference_id" json:"reference_id"`
+}
+
+type ExportType struct {
+	Type string `uri:"type" binding:"required,oneof=items customers vendors locations barcodes"`
+}
+
+type ExportFilter struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
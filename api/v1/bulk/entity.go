@@ -0,0 +1,41 @@
+package bulk
+
+import "time"
+
+type ImportJob struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ImportJobID    string    `db:"import_job_id" json:"import_job_id"`
+	ImportType     string    `db:"import_type" json:"import_type"` //items customers vendors locations barcodes
+	FileName       string    `db:"file_name" json:"file_name"`
+	Format         int       `db:"format" json:"format"`   //1 csv 2 xlsx
+	Headers        string    `db:"headers" json:"headers"` //json array of the header row
+	Mapping        string    `db:"mapping" json:"mapping"` //json object field => header
+	RowCount       int       `db:"row_count" json:"row_count"`
+	ValidCount     int       `db:"valid_count" json:"valid_count"`
+	InvalidCount   int       `db:"invalid_count" json:"invalid_count"`
+	ImportedCount  int       `db:"imported_count" json:"imported_count"`
+	FailedCount    int       `db:"failed_count" json:"failed_count"`
+	ImportStatus   int       `db:"import_status" json:"import_status"` //1 uploaded 2 validated 3 imported 4 partially imported
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type ImportJobRow struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ImportJobID    string    `db:"import_job_id" json:"import_job_id"`
+	LineNumber     int       `db:"line_number" json:"line_number"` //line in the file, header is 1
+	Data           string    `db:"data" json:"data"`               //json array of the cells
+	RowStatus      int       `db:"row_status" json:"row_status"`   //1 pending 2 valid 3 invalid 4 imported 5 failed
+	Error          string    `db:"error" json:"error"`
+	ReferenceID    string    `db:"reference_id" json:"reference_id"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,282 @@
+package bulk
+
+import (
+	"database/sql"
+	"errors"
+	"go-api/api/v1/item"
+	"go-api/api/v1/setting"
+	"go-api/api/v1/warehouse"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type importType struct {
+	Sheet  string
+	Table  string
+	Key    string //field that must be unique within one file
+	DTO    func() interface{}
+	Entity func() interface{} //table row, its db tags are the columns that can be exported
+	Create func(tx *sql.Tx, info interface{}) (string, error)
+}
+
+var importTypes = map[string]importType{
+	"items":     {"Items", "i_items", "sku", func() interface{} { return &item.ItemNew{} }, func() interface{} { return &item.Item{} }, createItem},
+	"customers": {"Customers", "s_customers", "name", func() interface{} { return &setting.CustomerNew{} }, func() interface{} { return &setting.Customer{} }, createCustomer},
+	"vendors":   {"Vendors", "s_vendors", "name", func() interface{} { return &setting.VendorNew{} }, func() interface{} { return &setting.Vendor{} }, createVendor},
+	"locations": {"Locations", "w_locations", "code", func() interface{} { return &warehouse.LocationNew{} }, func() interface{} { return &warehouse.Location{} }, createLocation},
+	"barcodes":  {"Barcodes", "i_barcodes", "code", func() interface{} { return &item.BarcodeNew{} }, func() interface{} { return &item.Barcode{} }, createBarcode},
+}
+
+// fieldAliases are header spellings seen in spreadsheets from other systems,
+// compared after normalizeHeader
+var fieldAliases = map[string][]string{
+	"sku":               {"itemcode", "productcode", "partnumber"},
+	"name":              {"itemname", "productname", "customername", "vendorname", "companyname"},
+	"unit_id":           {"unit", "uom"},
+	"default_vendor_id": {"vendor", "defaultvendor"},
+	"selling_price":     {"price", "saleprice", "salesprice"},
+	"cost_price":        {"cost", "purchaseprice"},
+	"reorder_stock":     {"reorderlevel", "reorderpoint"},
+	"contact_email":     {"email"},
+	"contact_phone":     {"mobile"},
+	"address1":          {"address", "street"},
+	"zip":               {"zipcode", "postcode", "postalcode"},
+	"item_id":           {"item"},
+	"bay_id":            {"bay"},
+	"quantity":          {"qty"},
+}
+
+type importField struct {
+	Name     string
+	Type     string
+	Required bool
+	Index    int
+}
+
+// importFields lists the DTO fields a file can fill, the hidden fields set
+// from the token are left out
+func importFields(t importType) []importField {
+	var res []importField
+	dtoType := reflect.TypeOf(t.DTO()).Elem()
+	for i := 0; i < dtoType.NumField(); i++ {
+		field := dtoType.Field(i)
+		if field.Tag.Get("swaggerignore") == "true" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		var fieldType string
+		switch field.Type.Kind() {
+		case reflect.String:
+			fieldType = "string"
+		case reflect.Int:
+			fieldType = "int"
+		case reflect.Float64:
+			fieldType = "float"
+		default:
+			continue
+		}
+		required := false
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				required = true
+			}
+		}
+		res = append(res, importField{Name: name, Type: fieldType, Required: required, Index: i})
+	}
+	return res
+}
+
+// exportFields are the import fields stored as they are in the table,
+// fields only used by the request such as price_date are left out
+func exportFields(t importType) []importField {
+	columns := make(map[string]bool)
+	entityType := reflect.TypeOf(t.Entity()).Elem()
+	for i := 0; i < entityType.NumField(); i++ {
+		columns[entityType.Field(i).Tag.Get("db")] = true
+	}
+	var res []importField
+	for _, field := range importFields(t) {
+		if columns[field.Name] {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+func normalizeHeader(header string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' || r == '.' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(header)))
+}
+
+func suggestMapping(t importType, headers []string) map[string]string {
+	mapping := make(map[string]string)
+	used := make(map[int]bool)
+	for _, field := range importFields(t) {
+		names := append([]string{normalizeHeader(field.Name)}, fieldAliases[field.Name]...)
+	search:
+		for _, name := range names {
+			for idx, header := range headers {
+				if !used[idx] && header != "" && normalizeHeader(header) == name {
+					mapping[field.Name] = header
+					used[idx] = true
+					break search
+				}
+			}
+		}
+	}
+	return mapping
+}
+
+func checkMapping(t importType, headers []string, mapping map[string]string) error {
+	known := make(map[string]bool)
+	for _, field := range importFields(t) {
+		known[field.Name] = true
+		if field.Required && mapping[field.Name] == "" {
+			msg := "required field not mapped: " + field.Name
+			return errors.New(msg)
+		}
+	}
+	for name, header := range mapping {
+		if !known[name] {
+			msg := "unknown field: " + name
+			return errors.New(msg)
+		}
+		if header == "" {
+			continue
+		}
+		found := false
+		for _, h := range headers {
+			if h == header {
+				found = true
+			}
+		}
+		if !found {
+			msg := "column not in file: " + header
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+// buildRow fills a new DTO of the type from the mapped cells and runs the
+// same binding rules as the single record endpoints
+func buildRow(t importType, headers []string, mapping map[string]string, cells []string, organizationID, user, email string) (interface{}, error) {
+	headerIndex := make(map[string]int)
+	for idx, header := range headers {
+		if _, ok := headerIndex[header]; !ok {
+			headerIndex[header] = idx
+		}
+	}
+	fields := importFields(t)
+	info := t.DTO()
+	value := reflect.ValueOf(info).Elem()
+	var problems []string
+	failed := make(map[int]bool)
+	for _, field := range fields {
+		header, ok := mapping[field.Name]
+		if !ok || header == "" {
+			continue
+		}
+		idx := headerIndex[header]
+		if idx >= len(cells) || cells[idx] == "" {
+			continue
+		}
+		cell := cells[idx]
+		switch field.Type {
+		case "string":
+			value.Field(field.Index).SetString(cell)
+		case "int":
+			number, err := strconv.ParseFloat(strings.ReplaceAll(cell, ",", ""), 64)
+			if err != nil || number != math.Trunc(number) {
+				problems = append(problems, field.Name+": not an integer")
+				failed[field.Index] = true
+				continue
+			}
+			value.Field(field.Index).SetInt(int64(number))
+		case "float":
+			number, err := strconv.ParseFloat(strings.ReplaceAll(cell, ",", ""), 64)
+			if err != nil {
+				problems = append(problems, field.Name+": not a number")
+				failed[field.Index] = true
+				continue
+			}
+			value.Field(field.Index).SetFloat(number)
+		}
+	}
+	if f := value.FieldByName("OrganizationID"); f.IsValid() {
+		f.SetString(organizationID)
+	}
+	if f := value.FieldByName("User"); f.IsValid() {
+		f.SetString(user)
+	}
+	if f := value.FieldByName("Email"); f.IsValid() {
+		f.SetString(email)
+	}
+	err := binding.Validator.ValidateStruct(info)
+	if err != nil {
+		fieldErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return nil, err
+		}
+		dtoType := value.Type()
+		for _, fieldError := range fieldErrors {
+			structField, _ := dtoType.FieldByName(fieldError.StructField())
+			if failed[structField.Index[0]] {
+				continue
+			}
+			name := strings.Split(structField.Tag.Get("json"), ",")[0]
+			rule := fieldError.Tag()
+			if fieldError.Param() != "" {
+				rule += "=" + fieldError.Param()
+			}
+			problems = append(problems, name+": "+rule)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return info, nil
+}
+
+func keyValue(t importType, info interface{}) string {
+	for _, field := range importFields(t) {
+		if field.Name == t.Key {
+			return strings.ToLower(reflect.ValueOf(info).Elem().Field(field.Index).String())
+		}
+	}
+	return ""
+}
+
+//create, through the same helpers as the single record services but inside the import transaction
+
+func createItem(tx *sql.Tx, dto interface{}) (string, error) {
+	return item.CreateItem(tx, *dto.(*item.ItemNew))
+}
+
+func createCustomer(tx *sql.Tx, dto interface{}) (string, error) {
+	return setting.CreateCustomer(tx, *dto.(*setting.CustomerNew))
+}
+
+func createVendor(tx *sql.Tx, dto interface{}) (string, error) {
+	return setting.CreateVendor(tx, *dto.(*setting.VendorNew))
+}
+
+func createLocation(tx *sql.Tx, dto interface{}) (string, error) {
+	return warehouse.CreateLocation(tx, *dto.(*warehouse.LocationNew))
+}
+
+func createBarcode(tx *sql.Tx, dto interface{}) (string, error) {
+	return item.CreateBarcode(tx, *dto.(*item.BarcodeNew))
+}
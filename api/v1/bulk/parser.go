@@ -0,0 +1,98 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"go-api/core/xlsx"
+	"path/filepath"
+	"strings"
+)
+
+type importLine struct {
+	LineNumber int
+	Cells      []string
+}
+
+func detectFormat(fileName string) int {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		return 2
+	case ".csv", ".txt":
+		return 1
+	}
+	return 0
+}
+
+// parseFile returns the header row and the data rows of the file, rows with
+// no value at all are dropped but the others keep their line number
+func parseFile(format int, content []byte) ([]string, []importLine, error) {
+	var records [][]string
+	var err error
+	switch format {
+	case 1:
+		records, err = parseCSV(content)
+	case 2:
+		records, err = xlsx.Read(content)
+	default:
+		msg := "file format error"
+		return nil, nil, errors.New(msg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) < 2 {
+		msg := "file is empty"
+		return nil, nil, errors.New(msg)
+	}
+	headers := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		headers[i] = strings.TrimSpace(header)
+	}
+	var rows []importLine
+	for i, record := range records[1:] {
+		empty := true
+		for j := range record {
+			record[j] = strings.TrimSpace(record[j])
+			if record[j] != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, importLine{LineNumber: i + 2, Cells: record})
+	}
+	if len(rows) == 0 {
+		msg := "file is empty"
+		return nil, nil, errors.New(msg)
+	}
+	return headers, rows, nil
+}
+
+func parseCSV(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(content))
+	firstLine := string(content)
+	if idx := strings.IndexAny(firstLine, "\r\n"); idx >= 0 {
+		firstLine = firstLine[:idx]
+	}
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+func writeFile(format int, sheetName string, records [][]string) ([]byte, error) {
+	if format == 2 {
+		return xlsx.Write(sheetName, records)
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	err := writer.WriteAll(records)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,192 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type bulkQuery struct {
+	conn *sqlx.DB
+}
+
+func NewBulkQuery(connection *sqlx.DB) *bulkQuery {
+	return &bulkQuery{
+		conn: connection,
+	}
+}
+
+//import job
+
+func (r *bulkQuery) GetImportJobByID(organizationID, id string) (*ImportJob, error) {
+	var importJob ImportJob
+	err := r.conn.Get(&importJob, `
+		SELECT
+		id,
+		organization_id,
+		import_job_id,
+		import_type,
+		file_name,
+		format,
+		headers,
+		mapping,
+		row_count,
+		valid_count,
+		invalid_count,
+		imported_count,
+		failed_count,
+		import_status,
+		status,
+		created,
+		created_by,
+		updated,
+		updated_by
+		FROM b_import_jobs
+		WHERE organization_id = ? AND import_job_id = ? AND status > 0
+	`, organizationID, id)
+	return &importJob, err
+}
+
+func (r *bulkQuery) GetImportJobCount(filter ImportJobFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ImportType; v != "" {
+		where, args = append(where, "import_type = ?"), append(args, v)
+	}
+	if v := filter.ImportStatus; v != 0 {
+		where, args = append(where, "import_status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM b_import_jobs
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *bulkQuery) GetImportJobList(filter ImportJobFilter) (*[]ImportJobResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ImportType; v != "" {
+		where, args = append(where, "import_type = ?"), append(args, v)
+	}
+	if v := filter.ImportStatus; v != 0 {
+		where, args = append(where, "import_status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var importJobs []ImportJobResponse
+	err := r.conn.Select(&importJobs, `
+		SELECT
+		organization_id,
+		import_job_id,
+		import_type,
+		file_name,
+		format,
+		row_count,
+		valid_count,
+		invalid_count,
+		imported_count,
+		failed_count,
+		import_status,
+		status
+		FROM b_import_jobs
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &importJobs, err
+}
+
+//import job row
+
+func (r *bulkQuery) GetImportJobRowCount(filter ImportJobRowFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ImportJobID; v != "" {
+		where, args = append(where, "import_job_id = ?"), append(args, v)
+	}
+	if v := filter.RowStatus; v != 0 {
+		where, args = append(where, "row_status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM b_import_job_rows
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *bulkQuery) GetImportJobRowList(filter ImportJobRowFilter) (*[]ImportJobRowResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ImportJobID; v != "" {
+		where, args = append(where, "import_job_id = ?"), append(args, v)
+	}
+	if v := filter.RowStatus; v != 0 {
+		where, args = append(where, "row_status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var rows []ImportJobRowResponse
+	err := r.conn.Select(&rows, `
+		SELECT
+		import_job_id,
+		line_number,
+		data,
+		row_status,
+		error,
+		reference_id
+		FROM b_import_job_rows
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY line_number ASC
+		LIMIT ?, ?
+	`, args...)
+	return &rows, err
+}
+
+//export
+
+// GetExportRows reads the given columns of the active records in table, the
+// column names come from the DTO tags and never from the request
+func (r *bulkQuery) GetExportRows(table string, columns []string, organizationID string) ([][]string, error) {
+	rows, err := r.conn.Queryx(`
+		SELECT `+strings.Join(columns, ", ")+`
+		FROM `+table+`
+		WHERE organization_id = ? AND status > 0
+		ORDER BY id ASC
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res [][]string
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+		record := make([]string, len(values))
+		for i, value := range values {
+			switch v := value.(type) {
+			case nil:
+				record[i] = ""
+			case []byte:
+				record[i] = string(v)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		res = append(res, record)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,150 @@
+package bulk
+
+import (
+	"database/sql"
+	"time"
+)
+
+type bulkRepository struct {
+	tx *sql.Tx
+}
+
+func NewBulkRepository(tx *sql.Tx) *bulkRepository {
+	return &bulkRepository{tx: tx}
+}
+
+//import job
+
+func (r *bulkRepository) CreateImportJob(info ImportJob) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO b_import_jobs
+		(
+			organization_id,
+			import_job_id,
+			import_type,
+			file_name,
+			format,
+			headers,
+			mapping,
+			row_count,
+			valid_count,
+			invalid_count,
+			imported_count,
+			failed_count,
+			import_status,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ImportJobID, info.ImportType, info.FileName, info.Format, info.Headers, info.Mapping, info.RowCount, info.ValidCount, info.InvalidCount, info.ImportedCount, info.FailedCount, info.ImportStatus, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *bulkRepository) GetImportJobByID(organizationID, id string) (*ImportJob, error) {
+	var res ImportJob
+	row := r.tx.QueryRow(`
+		SELECT organization_id, import_job_id, import_type, file_name, format, headers, mapping, row_count, valid_count, invalid_count, imported_count, failed_count, import_status, status
+		FROM b_import_jobs
+		WHERE organization_id = ? AND import_job_id = ? AND status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.ImportJobID, &res.ImportType, &res.FileName, &res.Format, &res.Headers, &res.Mapping, &res.RowCount, &res.ValidCount, &res.InvalidCount, &res.ImportedCount, &res.FailedCount, &res.ImportStatus, &res.Status)
+	return &res, err
+}
+
+func (r *bulkRepository) UpdateImportJobMapping(id, mapping string, validCount, invalidCount, importStatus int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_import_jobs SET
+		mapping = ?,
+		valid_count = ?,
+		invalid_count = ?,
+		failed_count = 0,
+		import_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE import_job_id = ?
+	`, mapping, validCount, invalidCount, importStatus, time.Now(), byUser, id)
+	return err
+}
+
+func (r *bulkRepository) UpdateImportJobResult(id string, importedCount, failedCount, importStatus int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_import_jobs SET
+		imported_count = ?,
+		failed_count = ?,
+		import_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE import_job_id = ?
+	`, importedCount, failedCount, importStatus, time.Now(), byUser, id)
+	return err
+}
+
+//import job row
+
+func (r *bulkRepository) CreateImportJobRow(info ImportJobRow) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO b_import_job_rows
+		(
+			organization_id,
+			import_job_id,
+			line_number,
+			data,
+			row_status,
+			error,
+			reference_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ImportJobID, info.LineNumber, info.Data, info.RowStatus, info.Error, info.ReferenceID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *bulkRepository) GetImportJobRows(importJobID string, rowStatus int) (*[]ImportJobRowResponse, error) {
+	query := `
+		SELECT import_job_id, line_number, data, row_status, error, reference_id
+		FROM b_import_job_rows
+		WHERE import_job_id = ? AND status > 0
+	`
+	args := []interface{}{importJobID}
+	if rowStatus != 0 {
+		query += " AND row_status = ?"
+		args = append(args, rowStatus)
+	}
+	rows, err := r.tx.Query(query+" ORDER BY line_number ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []ImportJobRowResponse
+	for rows.Next() {
+		var row ImportJobRowResponse
+		err = rows.Scan(&row.ImportJobID, &row.LineNumber, &row.Data, &row.RowStatus, &row.Error, &row.ReferenceID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+	return &res, rows.Err()
+}
+
+func (r *bulkRepository) UpdateImportJobRow(importJobID string, lineNumber, rowStatus int, rowError, referenceID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE b_import_job_rows SET
+		row_status = ?,
+		error = ?,
+		reference_id = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE import_job_id = ? AND line_number = ? AND status > 0
+	`, rowStatus, rowError, referenceID, time.Now(), byUser, importJobID, lineNumber)
+	return err
+}
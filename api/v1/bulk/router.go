@@ -0,0 +1,14 @@
+package bulk
+
+import "github.com/gin-gonic/gin"
+
+func AuthRouter(g *gin.RouterGroup) {
+	g.POST("/imports", NewImportJob)
+	g.GET("/imports", GetImportJobList)
+	g.GET("/imports/:id", GetImportJobByID)
+	g.POST("/imports/:id/validate", ValidateImportJob)
+	g.GET("/imports/:id/rows", GetImportJobRowList)
+	g.POST("/imports/:id/commit", CommitImportJob)
+
+	g.GET("/exports/:type", ExportData)
+}
@@ -0,0 +1,454 @@
+package bulk
+
+import (
+	"encoding/json"
+	"errors"
+	"go-api/api/v1/common"
+	"go-api/core/database"
+	"go-api/core/queue"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/xid"
+)
+
+type bulkService struct {
+}
+
+func NewBulkService() *bulkService {
+	return &bulkService{}
+}
+
+//import job
+
+func (s *bulkService) NewImportJob(info ImportJobNew, fileName string, content []byte) (*string, error) {
+	t := importTypes[info.ImportType]
+	format := info.Format
+	if format == 0 {
+		format = detectFormat(fileName)
+	}
+	headers, lines, err := parseFile(format, content)
+	if err != nil {
+		msg := "parse file error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	headersJSON, _ := json.Marshal(headers)
+	mappingJSON, _ := json.Marshal(suggestMapping(t, headers))
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBulkRepository(tx)
+	importJobID := "imp-" + xid.New().String()
+	for _, line := range lines {
+		data, _ := json.Marshal(line.Cells)
+		var row ImportJobRow
+		row.OrganizationID = info.OrganizationID
+		row.ImportJobID = importJobID
+		row.LineNumber = line.LineNumber
+		row.Data = string(data)
+		row.RowStatus = 1
+		row.Error = ""
+		row.ReferenceID = ""
+		row.Status = 1
+		row.Created = time.Now()
+		row.CreatedBy = info.Email
+		row.Updated = time.Now()
+		row.UpdatedBy = info.Email
+		err = repo.CreateImportJobRow(row)
+		if err != nil {
+			msg := "create import job row error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	var importJob ImportJob
+	importJob.OrganizationID = info.OrganizationID
+	importJob.ImportJobID = importJobID
+	importJob.ImportType = info.ImportType
+	importJob.FileName = fileName
+	importJob.Format = format
+	importJob.Headers = string(headersJSON)
+	importJob.Mapping = string(mappingJSON)
+	importJob.RowCount = len(lines)
+	importJob.ValidCount = 0
+	importJob.InvalidCount = 0
+	importJob.ImportedCount = 0
+	importJob.FailedCount = 0
+	importJob.ImportStatus = 1
+	importJob.Status = 1
+	importJob.Created = time.Now()
+	importJob.CreatedBy = info.Email
+	importJob.Updated = time.Now()
+	importJob.UpdatedBy = info.Email
+	err = repo.CreateImportJob(importJob)
+	if err != nil {
+		msg := "create import job error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &importJobID, nil
+}
+
+func (s *bulkService) GetImportJobList(filter ImportJobFilter) (int, *[]ImportJobResponse, error) {
+	db := database.RDB()
+	query := NewBulkQuery(db)
+	count, err := query.GetImportJobCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetImportJobList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *bulkService) GetImportJobByID(organizationID, id string) (*ImportJobDetailResponse, error) {
+	db := database.RDB()
+	query := NewBulkQuery(db)
+	importJob, err := query.GetImportJobByID(organizationID, id)
+	if err != nil {
+		msg := "get import job error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return importJobDetail(importJob), nil
+}
+
+func importJobDetail(importJob *ImportJob) *ImportJobDetailResponse {
+	var res ImportJobDetailResponse
+	res.OrganizationID = importJob.OrganizationID
+	res.ImportJobID = importJob.ImportJobID
+	res.ImportType = importJob.ImportType
+	res.FileName = importJob.FileName
+	res.Format = importJob.Format
+	res.RowCount = importJob.RowCount
+	res.ValidCount = importJob.ValidCount
+	res.InvalidCount = importJob.InvalidCount
+	res.ImportedCount = importJob.ImportedCount
+	res.FailedCount = importJob.FailedCount
+	res.ImportStatus = importJob.ImportStatus
+	res.Status = importJob.Status
+	json.Unmarshal([]byte(importJob.Headers), &res.Headers)
+	json.Unmarshal([]byte(importJob.Mapping), &res.Mapping)
+	for _, field := range importFields(importTypes[importJob.ImportType]) {
+		res.Fields = append(res.Fields, ImportFieldResponse{Name: field.Name, Type: field.Type, Required: field.Required})
+	}
+	return &res
+}
+
+// ValidateImportJob saves the column mapping and checks every row not yet
+// imported against the binding rules of the target DTO. Lookups such as
+// units or bays are only checked by a dry run commit.
+func (s *bulkService) ValidateImportJob(importJobID string, info ImportJobValidate) (*ImportJobDetailResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBulkRepository(tx)
+	importJob, err := repo.GetImportJobByID(info.OrganizationID, importJobID)
+	if err != nil {
+		msg := "import job not exist"
+		return nil, errors.New(msg)
+	}
+	if importJob.ImportStatus == 3 {
+		msg := "import job already imported"
+		return nil, errors.New(msg)
+	}
+	t := importTypes[importJob.ImportType]
+	var headers []string
+	json.Unmarshal([]byte(importJob.Headers), &headers)
+	err = checkMapping(t, headers, info.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := repo.GetImportJobRows(importJobID, 0)
+	if err != nil {
+		msg := "get import job rows error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	seen := make(map[string]int)
+	validCount := 0
+	invalidCount := 0
+	for _, row := range *rows {
+		if row.RowStatus == 4 {
+			continue
+		}
+		var cells []string
+		json.Unmarshal([]byte(row.Data), &cells)
+		rowStatus := 2
+		rowError := ""
+		dto, err := buildRow(t, headers, info.Mapping, cells, info.OrganizationID, info.User, info.Email)
+		if err != nil {
+			rowStatus = 3
+			rowError = err.Error()
+		} else if key := keyValue(t, dto); key != "" {
+			if lineNumber, ok := seen[key]; ok {
+				rowStatus = 3
+				rowError = t.Key + ": duplicate of line " + strconv.Itoa(lineNumber)
+			} else {
+				seen[key] = row.LineNumber
+			}
+		}
+		if rowStatus == 2 {
+			validCount++
+		} else {
+			invalidCount++
+		}
+		err = repo.UpdateImportJobRow(importJobID, row.LineNumber, rowStatus, rowError, "", info.Email)
+		if err != nil {
+			msg := "update import job row error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	mappingJSON, _ := json.Marshal(info.Mapping)
+	err = repo.UpdateImportJobMapping(importJobID, string(mappingJSON), validCount, invalidCount, 2, info.Email)
+	if err != nil {
+		msg := "update import job error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	importJob, err = repo.GetImportJobByID(info.OrganizationID, importJobID)
+	if err != nil {
+		msg := "get import job error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return importJobDetail(importJob), nil
+}
+
+func (s *bulkService) GetImportJobRowList(filter ImportJobRowFilter) (int, *[]ImportJobRowResponse, error) {
+	db := database.RDB()
+	query := NewBulkQuery(db)
+	_, err := query.GetImportJobByID(filter.OrganizationID, filter.ImportJobID)
+	if err != nil {
+		msg := "import job not exist"
+		return 0, nil, errors.New(msg)
+	}
+	count, err := query.GetImportJobRowCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetImportJobRowList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i := range *list {
+		json.Unmarshal([]byte((*list)[i].Data), &(*list)[i].Cells)
+	}
+	return count, list, err
+}
+
+// CommitImportJob creates the records of the valid rows. Mode 1 runs all rows
+// in one transaction so a single failure imports nothing, mode 2 commits each
+// chunk on its own and only the chunks with a failing row are rolled back.
+// A dry run goes through the same path and always rolls back.
+func (s *bulkService) CommitImportJob(importJobID string, info ImportJobCommit) (*ImportJobCommitResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewBulkRepository(tx)
+	importJob, err := repo.GetImportJobByID(info.OrganizationID, importJobID)
+	if err != nil {
+		msg := "import job not exist"
+		return nil, errors.New(msg)
+	}
+	if importJob.ImportStatus != 2 && importJob.ImportStatus != 4 {
+		msg := "import job not validated"
+		return nil, errors.New(msg)
+	}
+	rows, err := repo.GetImportJobRows(importJobID, 2)
+	if err != nil {
+		msg := "get import job rows error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if len(*rows) == 0 {
+		msg := "no valid row to import"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	var headers []string
+	var mapping map[string]string
+	json.Unmarshal([]byte(importJob.Headers), &headers)
+	json.Unmarshal([]byte(importJob.Mapping), &mapping)
+	dryRun := info.DryRun == 1
+	chunkSize := len(*rows)
+	if info.Mode == 2 {
+		chunkSize = info.ChunkSize
+		if chunkSize == 0 {
+			chunkSize = 100
+		}
+	}
+	var res ImportJobCommitResponse
+	res.ImportJobID = importJobID
+	res.DryRun = 2
+	if dryRun {
+		res.DryRun = 1
+	}
+	var failedRows []ImportJobRowResponse
+	for start := 0; start < len(*rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(*rows) {
+			end = len(*rows)
+		}
+		results, err := importChunk(db, importJob, headers, mapping, (*rows)[start:end], dryRun, info.User, info.Email)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range results {
+			if row.RowStatus == 4 {
+				res.ImportedCount++
+			}
+			if row.RowStatus == 5 {
+				res.FailedCount++
+				failedRows = append(failedRows, row)
+			}
+		}
+		res.Rows = append(res.Rows, results...)
+	}
+	if dryRun {
+		return &res, nil
+	}
+	tx, err = db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo = NewBulkRepository(tx)
+	for _, row := range failedRows {
+		err = repo.UpdateImportJobRow(importJobID, row.LineNumber, 5, row.Error, "", info.Email)
+		if err != nil {
+			msg := "update import job row error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	importedCount := importJob.ImportedCount + res.ImportedCount
+	importStatus := importJob.ImportStatus
+	if importedCount == importJob.RowCount {
+		importStatus = 3
+	} else if importedCount > 0 {
+		importStatus = 4
+	}
+	err = repo.UpdateImportJobResult(importJobID, importedCount, res.FailedCount, importStatus, info.Email)
+	if err != nil {
+		msg := "update import job error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &res, nil
+}
+
+// importChunk creates the rows in one transaction, which is committed only
+// when every row passes and it is not a dry run
+func importChunk(db *sqlx.DB, importJob *ImportJob, headers []string, mapping map[string]string, rows []ImportJobRowResponse, dryRun bool, user, email string) ([]ImportJobRowResponse, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	t := importTypes[importJob.ImportType]
+	var results []ImportJobRowResponse
+	failed := false
+	for _, row := range rows {
+		json.Unmarshal([]byte(row.Data), &row.Cells)
+		dto, err := buildRow(t, headers, mapping, row.Cells, importJob.OrganizationID, user, email)
+		if err == nil {
+			row.ReferenceID, err = t.Create(tx, dto)
+		}
+		if err != nil {
+			row.RowStatus = 5
+			row.Error = err.Error()
+			row.ReferenceID = ""
+			failed = true
+		} else {
+			row.RowStatus = 4
+			row.Error = ""
+		}
+		results = append(results, row)
+	}
+	if dryRun {
+		for i := range results {
+			results[i].ReferenceID = ""
+		}
+		return results, nil
+	}
+	if failed {
+		// the passing rows were rolled back with the chunk and stay valid
+		for i := range results {
+			if results[i].RowStatus == 4 {
+				results[i].RowStatus = 2
+				results[i].ReferenceID = ""
+			}
+		}
+		return results, nil
+	}
+	repo := NewBulkRepository(tx)
+	for _, row := range results {
+		err = repo.UpdateImportJobRow(importJob.ImportJobID, row.LineNumber, 4, "", row.ReferenceID, email)
+		if err != nil {
+			msg := "update import job row error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	tx.Commit()
+	if importJob.ImportType == "items" {
+		rabbit, _ := queue.GetConn()
+		for _, row := range results {
+			var newEvent common.NewHistoryCreated
+			newEvent.HistoryType = "item"
+			newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+			newEvent.HistoryBy = user
+			newEvent.ReferenceID = row.ReferenceID
+			newEvent.Description = "Item Imported"
+			newEvent.OrganizationID = importJob.OrganizationID
+			newEvent.Email = email
+			msg, _ := json.Marshal(newEvent)
+			err = rabbit.Publish("NewHistoryCreated", msg)
+			if err != nil {
+				msg := "create event NewHistoryCreated error"
+				return nil, errors.New(msg)
+			}
+		}
+	}
+	return results, nil
+}
+
+//export
+
+func (s *bulkService) ExportData(organizationID, exportType, format string) ([]byte, string, error) {
+	t := importTypes[exportType]
+	var columns []string
+	for _, field := range exportFields(t) {
+		columns = append(columns, field.Name)
+	}
+	db := database.RDB()
+	query := NewBulkQuery(db)
+	records, err := query.GetExportRows(t.Table, columns, organizationID)
+	if err != nil {
+		msg := "get export data error: " + err.Error()
+		return nil, "", errors.New(msg)
+	}
+	fileFormat := 1
+	fileName := exportType + ".csv"
+	if format == "xlsx" {
+		fileFormat = 2
+		fileName = exportType + ".xlsx"
+	}
+	content, err := writeFile(fileFormat, t.Sheet, append([][]string{columns}, records...))
+	if err != nil {
+		msg := "write export file error: " + err.Error()
+		return nil, "", errors.New(msg)
+	}
+	return content, fileName, nil
+}
@@ -65,3 +65,285 @@ func GetNextNumber(c *gin.Context) {
 	}
 	response.Response(c, res)
 }
+
+// @Summary 新建审批规则
+// @Id 703
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param approval_rule_info body ApprovalRuleNew true "审批规则信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvalrules [POST]
+func NewApprovalRule(c *gin.Context) {
+	var info ApprovalRuleNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.Email
+	commonService := NewCommonService()
+	new, err := commonService.NewApprovalRule(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 审批规则列表
+// @Id 704
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param document_type query string false "单据类型（purchaseorder/salesorder）"
+// @Param name query string false "规则名称"
+// @Success 200 object response.ListRes{data=[]ApprovalRuleResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvalrules [GET]
+func GetApprovalRuleList(c *gin.Context) {
+	var filter ApprovalRuleFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	commonService := NewCommonService()
+	count, list, err := commonService.GetApprovalRuleList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取审批规则
+// @Id 705
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "审批规则ID"
+// @Success 200 object response.SuccessRes{data=ApprovalRuleResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvalrules/:id [GET]
+func GetApprovalRuleByID(c *gin.Context) {
+	var uri ApprovalRuleID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	commonService := NewCommonService()
+	approvalRule, err := commonService.GetApprovalRuleByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, approvalRule)
+}
+
+// @Summary 根据ID更新审批规则
+// @Id 706
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "审批规则ID"
+// @Param approval_rule_info body ApprovalRuleNew true "审批规则信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvalrules/:id [PUT]
+func UpdateApprovalRule(c *gin.Context) {
+	var uri ApprovalRuleID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ApprovalRuleNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.Email
+	commonService := NewCommonService()
+	new, err := commonService.UpdateApprovalRule(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除审批规则
+// @Id 707
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "审批规则ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvalrules/:id [DELETE]
+func DeleteApprovalRule(c *gin.Context) {
+	var uri ApprovalRuleID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	commonService := NewCommonService()
+	err := commonService.DeleteApprovalRule(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
+
+// @Summary 审批列表
+// @Id 708
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param document_type query string false "单据类型（purchaseorder/salesorder）"
+// @Param document_id query string false "单据ID"
+// @Param status query int false "状态（1待审批/2已通过/3已驳回）"
+// @Param mine query int false "待我审批（1是）"
+// @Success 200 object response.ListRes{data=[]ApprovalResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvals [GET]
+func GetApprovalList(c *gin.Context) {
+	var filter ApprovalFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	filter.RoleID = claims.RoleID
+	commonService := NewCommonService()
+	count, list, err := commonService.GetApprovalList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取审批
+// @Id 709
+// @Tags 审批管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "审批ID"
+// @Success 200 object response.SuccessRes{data=ApprovalResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /approvals/:id [GET]
+func GetApprovalByID(c *gin.Context) {
+	var uri ApprovalID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	commonService := NewCommonService()
+	approval, err := commonService.GetApprovalByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, approval)
+}
+
+// @Summary 通知列表
+// @Id 710
+// @Tags 通知管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param notification_type query string false "通知类型"
+// @Param is_read query int false "已读（1已读/2未读）"
+// @Success 200 object response.ListRes{data=[]NotificationResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /notifications [GET]
+func GetNotificationList(c *gin.Context) {
+	var filter NotificationFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	commonService := NewCommonService()
+	count, list, err := commonService.GetNotificationList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 通知标记为已读
+// @Id 711
+// @Tags 通知管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "通知ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /notifications/:id/read [POST]
+func ReadNotification(c *gin.Context) {
+	var uri NotificationID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	commonService := NewCommonService()
+	err := commonService.ReadNotification(claims.OrganizationID, uri.ID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
+
+// @Summary 全部通知标记为已读
+// @Id 712
+// @Tags 通知管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /notifications/read [POST]
+func ReadAllNotifications(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	commonService := NewCommonService()
+	err := commonService.ReadNotification(claims.OrganizationID, "", claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
@@ -31,6 +31,156 @@ type HistoryResponse struct {
 }
 
 type NumberFilter struct {
-	NumberType     string `form:"number_type" binding:"required,oneof=purchaseorder salesorder purchasereceive pickingorder package shippingorder invoice bill paymentreceived paymentmade"`
+	NumberType     string `form:"number_type" binding:"required,oneof=purchaseorder salesorder purchasereceive pickingorder package shippingorder invoice bill paymentreceived paymentmade quote"`
 	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
 }
+
+//approval
+
+type ApprovalRuleFilter struct {
+	DocumentType   string `form:"document_type" binding:"omitempty,oneof=purchaseorder salesorder"`
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ApprovalRuleResponse struct {
+	OrganizationID  string                     `db:"organization_id" json:"organization_id"`
+	ApprovalRuleID  string                     `db:"approval_rule_id" json:"approval_rule_id"`
+	DocumentType    string                     `db:"document_type" json:"document_type"`
+	Name            string                     `db:"name" json:"name"`
+	MinTotal        float64                    `db:"min_total" json:"min_total"`
+	MaxTotal        float64                    `db:"max_total" json:"max_total"`
+	ContactID       string                     `db:"contact_id" json:"contact_id"`
+	RequesterRoleID string                     `db:"requester_role_id" json:"requester_role_id"`
+	Priority        int                        `db:"priority" json:"priority"`
+	Status          int                        `db:"status" json:"status"`
+	Steps           []ApprovalRuleStepResponse `json:"steps"`
+}
+
+type ApprovalRuleStepResponse struct {
+	ApprovalRuleID string `db:"approval_rule_id" json:"approval_rule_id"`
+	Step           int    `db:"step" json:"step"`
+	RoleID         string `db:"role_id" json:"role_id"`
+	RoleName       string `db:"role_name" json:"role_name"`
+}
+
+type ApprovalRuleNew struct {
+	DocumentType    string                `json:"document_type" binding:"required,oneof=purchaseorder salesorder"`
+	Name            string                `json:"name" binding:"required,min=1,max=64"`
+	MinTotal        float64               `json:"min_total" binding:"omitempty,min=0"`
+	MaxTotal        float64               `json:"max_total" binding:"omitempty,min=0"`
+	ContactID       string                `json:"contact_id" binding:"omitempty,max=64"`
+	RequesterRoleID string                `json:"requester_role_id" binding:"omitempty,max=64"`
+	Priority        int                   `json:"priority" binding:"omitempty,min=0"`
+	Steps           []ApprovalRuleStepNew `json:"steps" binding:"required,min=1,dive"`
+	Status          int                   `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID  string                `json:"organiztion_id" swaggerignore:"true"`
+	User            string                `json:"user" swaggerignore:"true"`
+}
+
+type ApprovalRuleStepNew struct {
+	RoleID string `json:"role_id" binding:"required,min=1,max=64"`
+}
+
+type ApprovalRuleID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ApprovalFilter struct {
+	DocumentType   string `form:"document_type" binding:"omitempty,oneof=purchaseorder salesorder"`
+	DocumentID     string `form:"document_id" binding:"omitempty,max=64,min=1"`
+	Status         int    `form:"status" binding:"omitempty,oneof=1 2 3"`
+	Mine           int    `form:"mine" binding:"omitempty,oneof=1 2"`
+	RoleID         string `json:"role_id" swaggerignore:"true"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ApprovalResponse struct {
+	OrganizationID string                 `db:"organization_id" json:"organization_id"`
+	ApprovalID     string                 `db:"approval_id" json:"approval_id"`
+	ApprovalRuleID string                 `db:"approval_rule_id" json:"approval_rule_id"`
+	RuleName       string                 `db:"rule_name" json:"rule_name"`
+	DocumentType   string                 `db:"document_type" json:"document_type"`
+	DocumentID     string                 `db:"document_id" json:"document_id"`
+	DocumentNumber string                 `db:"document_number" json:"document_number"`
+	Total          float64                `db:"total" json:"total"`
+	CurrentStep    int                    `db:"current_step" json:"current_step"`
+	StepCount      int                    `db:"step_count" json:"step_count"`
+	RequestedBy    string                 `db:"requested_by" json:"requested_by"`
+	Status         int                    `db:"status" json:"status"`
+	Steps          []ApprovalStepResponse `json:"steps"`
+}
+
+type ApprovalStepResponse struct {
+	ApprovalStepID string `db:"approval_step_id" json:"approval_step_id"`
+	ApprovalID     string `db:"approval_id" json:"approval_id"`
+	Step           int    `db:"step" json:"step"`
+	RoleID         string `db:"role_id" json:"role_id"`
+	RoleName       string `db:"role_name" json:"role_name"`
+	Action         int    `db:"action" json:"action"`
+	ActedBy        string `db:"acted_by" json:"acted_by"`
+	ActedAt        string `db:"acted_at" json:"acted_at"`
+	Comments       string `db:"comments" json:"comments"`
+}
+
+type ApprovalID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+// ApprovalStart is what the document modules pass to start an approval
+type ApprovalStart struct {
+	DocumentType   string
+	DocumentID     string
+	DocumentNumber string
+	Total          float64
+	ContactID      string
+	RoleID         string
+	OrganizationID string
+	User           string
+	Email          string
+}
+
+type ApprovalAction struct {
+	Approve        int    `json:"approve" binding:"required,oneof=1 2"`
+	Comments       string `json:"comments" binding:"omitempty,max=512"`
+	DocumentType   string `json:"document_type" swaggerignore:"true"`
+	DocumentID     string `json:"document_id" swaggerignore:"true"`
+	RoleID         string `json:"role_id" swaggerignore:"true"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+// NotificationNew is what the other modules pass to raise a notification
+type NotificationNew struct {
+	NotificationType string
+	ReferenceID      string
+	Title            string
+	Description      string
+	OrganizationID   string
+	Email            string
+}
+
+type NotificationFilter struct {
+	NotificationType string `form:"notification_type" binding:"omitempty,max=64"`
+	IsRead           int    `form:"is_read" binding:"omitempty,oneof=1 2"`
+	OrganizationID   string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type NotificationResponse struct {
+	OrganizationID   string `db:"organization_id" json:"organization_id"`
+	NotificationID   string `db:"notification_id" json:"notification_id"`
+	NotificationType string `db:"notification_type" json:"notification_type"`
+	ReferenceID      string `db:"reference_id" json:"reference_id"`
+	Title            string `db:"title" json:"title"`
+	Description      string `db:"description" json:"description"`
+	IsRead           int    `db:"is_read" json:"is_read"`
+	Created          string `db:"created" json:"created"`
+}
+
+type NotificationID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
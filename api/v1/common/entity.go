@@ -28,3 +28,89 @@ type OrderNumber struct {
 	Updated        time.Time `db:"updated" json:"updated"`
 	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
 }
+
+type ApprovalRule struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	ApprovalRuleID  string    `db:"approval_rule_id" json:"approval_rule_id"`
+	DocumentType    string    `db:"document_type" json:"document_type"`
+	Name            string    `db:"name" json:"name"`
+	MinTotal        float64   `db:"min_total" json:"min_total"`
+	MaxTotal        float64   `db:"max_total" json:"max_total"`
+	ContactID       string    `db:"contact_id" json:"contact_id"`
+	RequesterRoleID string    `db:"requester_role_id" json:"requester_role_id"`
+	Priority        int       `db:"priority" json:"priority"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type ApprovalRuleStep struct {
+	ID                 int64     `db:"id" json:"id"`
+	OrganizationID     string    `db:"organization_id" json:"organization_id"`
+	ApprovalRuleStepID string    `db:"approval_rule_step_id" json:"approval_rule_step_id"`
+	ApprovalRuleID     string    `db:"approval_rule_id" json:"approval_rule_id"`
+	Step               int       `db:"step" json:"step"`
+	RoleID             string    `db:"role_id" json:"role_id"`
+	Status             int       `db:"status" json:"status"`
+	Created            time.Time `db:"created" json:"created"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	Updated            time.Time `db:"updated" json:"updated"`
+	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
+}
+
+type Approval struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ApprovalID     string    `db:"approval_id" json:"approval_id"`
+	ApprovalRuleID string    `db:"approval_rule_id" json:"approval_rule_id"`
+	RuleName       string    `db:"rule_name" json:"rule_name"`
+	DocumentType   string    `db:"document_type" json:"document_type"`
+	DocumentID     string    `db:"document_id" json:"document_id"`
+	DocumentNumber string    `db:"document_number" json:"document_number"`
+	Total          float64   `db:"total" json:"total"`
+	CurrentStep    int       `db:"current_step" json:"current_step"`
+	StepCount      int       `db:"step_count" json:"step_count"`
+	RequestedBy    string    `db:"requested_by" json:"requested_by"`
+	Status         int       `db:"status" json:"status"` //1 pending, 2 approved, 3 rejected
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type ApprovalStep struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ApprovalStepID string    `db:"approval_step_id" json:"approval_step_id"`
+	ApprovalID     string    `db:"approval_id" json:"approval_id"`
+	Step           int       `db:"step" json:"step"`
+	RoleID         string    `db:"role_id" json:"role_id"`
+	Action         int       `db:"action" json:"action"` //1 waiting, 2 approved, 3 rejected
+	ActedBy        string    `db:"acted_by" json:"acted_by"`
+	ActedAt        string    `db:"acted_at" json:"acted_at"`
+	Comments       string    `db:"comments" json:"comments"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type Notification struct {
+	ID               int64     `db:"id" json:"id"`
+	OrganizationID   string    `db:"organization_id" json:"organization_id"`
+	NotificationID   string    `db:"notification_id" json:"notification_id"`
+	NotificationType string    `db:"notification_type" json:"notification_type"`
+	ReferenceID      string    `db:"reference_id" json:"reference_id"`
+	Title            string    `db:"title" json:"title"`
+	Description      string    `db:"description" json:"description"`
+	IsRead           int       `db:"is_read" json:"is_read"` //1 read 2 unread
+	Status           int       `db:"status" json:"status"`
+	Created          time.Time `db:"created" json:"created"`
+	CreatedBy        string    `db:"created_by" json:"created_by"`
+	Updated          time.Time `db:"updated" json:"updated"`
+	UpdatedBy        string    `db:"updated_by" json:"updated_by"`
+}
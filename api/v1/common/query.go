@@ -92,3 +92,185 @@ func (r *commonQuery) GetHistoryList(filter HistoryFilter) (*[]HistoryResponse,
 	`, args...)
 	return &historys, err
 }
+
+//approval
+
+func (r *commonQuery) GetApprovalRuleByID(organizationID, id string) (*ApprovalRuleResponse, error) {
+	var approvalRule ApprovalRuleResponse
+	err := r.conn.Get(&approvalRule, `
+		SELECT organization_id, approval_rule_id, document_type, name, min_total, max_total, contact_id, requester_role_id, priority, status
+		FROM s_approval_rules
+		WHERE organization_id = ? AND approval_rule_id = ? AND status > 0
+	`, organizationID, id)
+	return &approvalRule, err
+}
+
+func (r *commonQuery) GetApprovalRuleStepList(approvalRuleID string) (*[]ApprovalRuleStepResponse, error) {
+	var steps []ApprovalRuleStepResponse
+	err := r.conn.Select(&steps, `
+		SELECT s.approval_rule_id, s.step, s.role_id, IFNULL(r.name, "") as role_name
+		FROM s_approval_rule_steps s
+		LEFT JOIN s_roles r
+		ON s.role_id = r.role_id
+		WHERE s.approval_rule_id = ? AND s.status > 0
+		ORDER BY s.step ASC
+	`, approvalRuleID)
+	return &steps, err
+}
+
+func (r *commonQuery) GetApprovalRuleCount(filter ApprovalRuleFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.DocumentType; v != "" {
+		where, args = append(where, "document_type = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_approval_rules
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *commonQuery) GetApprovalRuleList(filter ApprovalRuleFilter) (*[]ApprovalRuleResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.DocumentType; v != "" {
+		where, args = append(where, "document_type = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var approvalRules []ApprovalRuleResponse
+	err := r.conn.Select(&approvalRules, `
+		SELECT organization_id, approval_rule_id, document_type, name, min_total, max_total, contact_id, requester_role_id, priority, status
+		FROM s_approval_rules
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY document_type ASC, priority ASC, id ASC
+		LIMIT ?, ?
+	`, args...)
+	return &approvalRules, err
+}
+
+func (r *commonQuery) GetApprovalByID(organizationID, id string) (*ApprovalResponse, error) {
+	var approval ApprovalResponse
+	err := r.conn.Get(&approval, `
+		SELECT organization_id, approval_id, approval_rule_id, rule_name, document_type, document_id, document_number, total, current_step, step_count, requested_by, status
+		FROM s_approvals
+		WHERE organization_id = ? AND approval_id = ? AND status > 0
+	`, organizationID, id)
+	return &approval, err
+}
+
+func (r *commonQuery) GetApprovalStepList(approvalID string) (*[]ApprovalStepResponse, error) {
+	var steps []ApprovalStepResponse
+	err := r.conn.Select(&steps, `
+		SELECT s.approval_step_id, s.approval_id, s.step, s.role_id, IFNULL(r.name, "") as role_name, s.action, s.acted_by, s.acted_at, s.comments
+		FROM s_approval_steps s
+		LEFT JOIN s_roles r
+		ON s.role_id = r.role_id
+		WHERE s.approval_id = ? AND s.status > 0
+		ORDER BY s.step ASC
+	`, approvalID)
+	return &steps, err
+}
+
+func approvalWhere(filter ApprovalFilter) ([]string, []interface{}) {
+	where, args := []string{"a.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "a.organization_id = ?"), append(args, v)
+	}
+	if v := filter.DocumentType; v != "" {
+		where, args = append(where, "a.document_type = ?"), append(args, v)
+	}
+	if v := filter.DocumentID; v != "" {
+		where, args = append(where, "a.document_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "a.status = ?"), append(args, v)
+	}
+	if filter.Mine == 1 {
+		// waiting for the role of the user at the current step
+		where, args = append(where, "a.status = 1 AND EXISTS (SELECT 1 FROM s_approval_steps s WHERE s.approval_id = a.approval_id AND s.step = a.current_step AND s.role_id = ? AND s.status > 0)"), append(args, filter.RoleID)
+	}
+	return where, args
+}
+
+func (r *commonQuery) GetApprovalCount(filter ApprovalFilter) (int, error) {
+	where, args := approvalWhere(filter)
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_approvals a
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *commonQuery) GetApprovalList(filter ApprovalFilter) (*[]ApprovalResponse, error) {
+	where, args := approvalWhere(filter)
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var approvals []ApprovalResponse
+	err := r.conn.Select(&approvals, `
+		SELECT a.organization_id, a.approval_id, a.approval_rule_id, a.rule_name, a.document_type, a.document_id, a.document_number, a.total, a.current_step, a.step_count, a.requested_by, a.status
+		FROM s_approvals a
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY a.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &approvals, err
+}
+
+//notification
+
+func (r *commonQuery) GetNotificationCount(filter NotificationFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.NotificationType; v != "" {
+		where, args = append(where, "notification_type = ?"), append(args, v)
+	}
+	if v := filter.IsRead; v != 0 {
+		where, args = append(where, "is_read = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_notifications
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *commonQuery) GetNotificationList(filter NotificationFilter) (*[]NotificationResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.NotificationType; v != "" {
+		where, args = append(where, "notification_type = ?"), append(args, v)
+	}
+	if v := filter.IsRead; v != 0 {
+		where, args = append(where, "is_read = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var notifications []NotificationResponse
+	err := r.conn.Select(&notifications, `
+		SELECT organization_id, notification_id, notification_type, reference_id, title, description, is_read, DATE_FORMAT(created, "%Y-%m-%d %H:%i:%s") as created
+		FROM s_notifications
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &notifications, err
+}
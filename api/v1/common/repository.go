@@ -77,3 +77,359 @@ func (r commonRepository) UpdateNumber(info NumberFilter, value int) error {
 	`, value, time.Now(), "SYSTEM", info.NumberType)
 	return err
 }
+
+//approval
+
+func (r *commonRepository) CheckRoleExist(organizationID, roleID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_roles WHERE organization_id = ? AND role_id = ? AND status > 0", organizationID, roleID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return false, err
+	}
+	return existed != 0, nil
+}
+
+func (r *commonRepository) CheckApprovalRuleConfict(approvalRuleID, organizationID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_approval_rules WHERE organization_id = ? AND approval_rule_id != ? AND name = ? AND status > 0", organizationID, approvalRuleID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *commonRepository) CreateApprovalRule(info ApprovalRule) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_approval_rules
+		(
+			organization_id,
+			approval_rule_id,
+			document_type,
+			name,
+			min_total,
+			max_total,
+			contact_id,
+			requester_role_id,
+			priority,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ApprovalRuleID, info.DocumentType, info.Name, info.MinTotal, info.MaxTotal, info.ContactID, info.RequesterRoleID, info.Priority, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *commonRepository) GetApprovalRuleByID(organizationID, approvalRuleID string) (*ApprovalRuleResponse, error) {
+	var res ApprovalRuleResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, approval_rule_id, document_type, name, min_total, max_total, contact_id, requester_role_id, priority, status
+		FROM s_approval_rules
+		WHERE organization_id = ? AND approval_rule_id = ? AND status > 0 LIMIT 1
+	`, organizationID, approvalRuleID)
+	err := row.Scan(&res.OrganizationID, &res.ApprovalRuleID, &res.DocumentType, &res.Name, &res.MinTotal, &res.MaxTotal, &res.ContactID, &res.RequesterRoleID, &res.Priority, &res.Status)
+	return &res, err
+}
+
+func (r *commonRepository) UpdateApprovalRule(id string, info ApprovalRule) error {
+	_, err := r.tx.Exec(`
+		Update s_approval_rules SET
+		document_type = ?,
+		name = ?,
+		min_total = ?,
+		max_total = ?,
+		contact_id = ?,
+		requester_role_id = ?,
+		priority = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE approval_rule_id = ?
+	`, info.DocumentType, info.Name, info.MinTotal, info.MaxTotal, info.ContactID, info.RequesterRoleID, info.Priority, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *commonRepository) DeleteApprovalRule(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_approval_rules SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE approval_rule_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *commonRepository) CreateApprovalRuleStep(info ApprovalRuleStep) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_approval_rule_steps
+		(
+			organization_id,
+			approval_rule_step_id,
+			approval_rule_id,
+			step,
+			role_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ApprovalRuleStepID, info.ApprovalRuleID, info.Step, info.RoleID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *commonRepository) DeleteApprovalRuleSteps(approvalRuleID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_approval_rule_steps SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE approval_rule_id = ? AND status > 0
+	`, time.Now(), byUser, approvalRuleID)
+	return err
+}
+
+func (r *commonRepository) GetApprovalRuleStepList(approvalRuleID string) (*[]ApprovalRuleStepResponse, error) {
+	rows, err := r.tx.Query(`
+		SELECT approval_rule_id, step, role_id
+		FROM s_approval_rule_steps
+		WHERE approval_rule_id = ? AND status > 0
+		ORDER BY step ASC
+	`, approvalRuleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []ApprovalRuleStepResponse
+	for rows.Next() {
+		var rowRes ApprovalRuleStepResponse
+		err = rows.Scan(&rowRes.ApprovalRuleID, &rowRes.Step, &rowRes.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rowRes)
+	}
+	return &res, rows.Err()
+}
+
+// MatchApprovalRule returns the active rule that applies to a document, rules
+// for the vendor or customer of the document come before general rules, then
+// the lowest priority wins
+func (r *commonRepository) MatchApprovalRule(info ApprovalStart) (*ApprovalRuleResponse, error) {
+	var res ApprovalRuleResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, approval_rule_id, document_type, name, min_total, max_total, contact_id, requester_role_id, priority, status
+		FROM s_approval_rules
+		WHERE organization_id = ? AND document_type = ? AND status = 1
+		AND min_total <= ? AND (max_total = 0 OR max_total >= ?)
+		AND (contact_id = "" OR contact_id = ?)
+		AND (requester_role_id = "" OR requester_role_id = ?)
+		ORDER BY IF(contact_id = "", 1, 0) ASC, priority ASC, id ASC
+		LIMIT 1
+	`, info.OrganizationID, info.DocumentType, info.Total, info.Total, info.ContactID, info.RoleID)
+	err := row.Scan(&res.OrganizationID, &res.ApprovalRuleID, &res.DocumentType, &res.Name, &res.MinTotal, &res.MaxTotal, &res.ContactID, &res.RequesterRoleID, &res.Priority, &res.Status)
+	return &res, err
+}
+
+func (r *commonRepository) CreateApproval(info Approval) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_approvals
+		(
+			organization_id,
+			approval_id,
+			approval_rule_id,
+			rule_name,
+			document_type,
+			document_id,
+			document_number,
+			total,
+			current_step,
+			step_count,
+			requested_by,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ApprovalID, info.ApprovalRuleID, info.RuleName, info.DocumentType, info.DocumentID, info.DocumentNumber, info.Total, info.CurrentStep, info.StepCount, info.RequestedBy, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *commonRepository) GetPendingApproval(organizationID, documentType, documentID string) (*ApprovalResponse, error) {
+	var res ApprovalResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, approval_id, approval_rule_id, rule_name, document_type, document_id, document_number, total, current_step, step_count, requested_by, status
+		FROM s_approvals
+		WHERE organization_id = ? AND document_type = ? AND document_id = ? AND status = 1
+		ORDER BY id DESC LIMIT 1
+	`, organizationID, documentType, documentID)
+	err := row.Scan(&res.OrganizationID, &res.ApprovalID, &res.ApprovalRuleID, &res.RuleName, &res.DocumentType, &res.DocumentID, &res.DocumentNumber, &res.Total, &res.CurrentStep, &res.StepCount, &res.RequestedBy, &res.Status)
+	return &res, err
+}
+
+func (r *commonRepository) UpdateApproval(id string, currentStep, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_approvals SET
+		current_step = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE approval_id = ?
+	`, currentStep, status, time.Now(), byUser, id)
+	return err
+}
+
+// CancelApproval drops the pending approval of a document that was changed
+// or deleted
+func (r *commonRepository) CancelApproval(organizationID, documentType, documentID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_approvals SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND document_type = ? AND document_id = ? AND status = 1
+	`, time.Now(), byUser, organizationID, documentType, documentID)
+	return err
+}
+
+func (r *commonRepository) CreateApprovalStep(info ApprovalStep) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_approval_steps
+		(
+			organization_id,
+			approval_step_id,
+			approval_id,
+			step,
+			role_id,
+			action,
+			acted_by,
+			acted_at,
+			comments,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ApprovalStepID, info.ApprovalID, info.Step, info.RoleID, info.Action, info.ActedBy, info.ActedAt, info.Comments, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *commonRepository) GetApprovalStep(approvalID string, step int) (*ApprovalStepResponse, error) {
+	var res ApprovalStepResponse
+	row := r.tx.QueryRow(`
+		SELECT approval_step_id, approval_id, step, role_id, action, acted_by, acted_at, comments
+		FROM s_approval_steps
+		WHERE approval_id = ? AND step = ? AND status > 0 LIMIT 1
+	`, approvalID, step)
+	err := row.Scan(&res.ApprovalStepID, &res.ApprovalID, &res.Step, &res.RoleID, &res.Action, &res.ActedBy, &res.ActedAt, &res.Comments)
+	return &res, err
+}
+
+func (r *commonRepository) UpdateApprovalStep(id string, action int, comments, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_approval_steps SET
+		action = ?,
+		acted_by = ?,
+		acted_at = ?,
+		comments = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE approval_step_id = ?
+	`, action, byUser, time.Now().Format("2006-01-02 15:04:05"), comments, time.Now(), byUser, id)
+	return err
+}
+
+//notification
+
+func (r *commonRepository) CreateNotification(info Notification) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_notifications
+		(
+			organization_id,
+			notification_id,
+			notification_type,
+			reference_id,
+			title,
+			description,
+			is_read,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.NotificationID, info.NotificationType, info.ReferenceID, info.Title, info.Description, info.IsRead, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *commonRepository) CheckUnreadNotificationExist(organizationID, notificationType, referenceID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_notifications WHERE organization_id = ? AND notification_type = ? AND reference_id = ? AND is_read = 2 AND status > 0", organizationID, notificationType, referenceID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return false, err
+	}
+	return existed != 0, nil
+}
+
+func (r *commonRepository) CheckNotificationExist(organizationID, notificationType, referenceID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_notifications WHERE organization_id = ? AND notification_type = ? AND reference_id = ? AND status > 0", organizationID, notificationType, referenceID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return false, err
+	}
+	return existed != 0, nil
+}
+
+func (r *commonRepository) UpdateNotificationByReference(info Notification) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_notifications SET
+		title = ?,
+		description = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND notification_type = ? AND reference_id = ? AND status > 0
+	`, info.Title, info.Description, info.Updated, info.UpdatedBy, info.OrganizationID, info.NotificationType, info.ReferenceID)
+	return err
+}
+
+func (r *commonRepository) GetNotificationByID(organizationID, notificationID string) (*NotificationResponse, error) {
+	var res NotificationResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, notification_id, notification_type, reference_id, title, description, is_read, DATE_FORMAT(created, "%Y-%m-%d %H:%i:%s")
+		FROM s_notifications
+		WHERE organization_id = ? AND notification_id = ? AND status > 0 LIMIT 1
+	`, organizationID, notificationID)
+	err := row.Scan(&res.OrganizationID, &res.NotificationID, &res.NotificationType, &res.ReferenceID, &res.Title, &res.Description, &res.IsRead, &res.Created)
+	return &res, err
+}
+
+func (r *commonRepository) ReadNotifications(organizationID, notificationID, byUser string) error {
+	query := `
+		UPDATE s_notifications SET
+		is_read = 1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND is_read = 2 AND status > 0
+	`
+	args := []interface{}{time.Now(), byUser, organizationID}
+	if notificationID != "" {
+		query += " AND notification_id = ?"
+		args = append(args, notificationID)
+	}
+	_, err := r.tx.Exec(query, args...)
+	return err
+}
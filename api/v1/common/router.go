@@ -5,4 +5,16 @@ import "github.com/gin-gonic/gin"
 func AuthRouter(g *gin.RouterGroup) {
 	g.GET("/historys", GetHistoryList)
 	g.GET("/nextnumber", GetNextNumber)
+
+	g.POST("/approvalrules", NewApprovalRule)
+	g.GET("/approvalrules", GetApprovalRuleList)
+	g.GET("/approvalrules/:id", GetApprovalRuleByID)
+	g.PUT("/approvalrules/:id", UpdateApprovalRule)
+	g.DELETE("/approvalrules/:id", DeleteApprovalRule)
+	g.GET("/approvals", GetApprovalList)
+	g.GET("/approvals/:id", GetApprovalByID)
+
+	g.GET("/notifications", GetNotificationList)
+	g.POST("/notifications/read", ReadAllNotifications)
+	g.POST("/notifications/:id/read", ReadNotification)
 }
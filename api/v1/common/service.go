@@ -1,6 +1,7 @@
 package common
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"go-api/core/database"
@@ -102,6 +103,8 @@ func (s *commonService) GetNextNumber(filter NumberFilter) (*string, error) {
 		prefix = "BIL"
 	case "paymentmade":
 		prefix = "PAYM"
+	case "quote":
+		prefix = "QT"
 	default:
 		msg := "number type error"
 		return nil, errors.New(msg)
@@ -123,3 +126,463 @@ func (s *commonService) GetNextNumber(filter NumberFilter) (*string, error) {
 	tx.Commit()
 	return &res, nil
 }
+
+//approval
+
+func (s *commonService) NewApprovalRule(info ApprovalRuleNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewCommonRepository(tx)
+	approvalRuleID := "apr-" + xid.New().String()
+	err = saveApprovalRule(repo, approvalRuleID, info)
+	if err != nil {
+		return nil, err
+	}
+	var approvalRule ApprovalRule
+	approvalRule.OrganizationID = info.OrganizationID
+	approvalRule.ApprovalRuleID = approvalRuleID
+	approvalRule.DocumentType = info.DocumentType
+	approvalRule.Name = info.Name
+	approvalRule.MinTotal = info.MinTotal
+	approvalRule.MaxTotal = info.MaxTotal
+	approvalRule.ContactID = info.ContactID
+	approvalRule.RequesterRoleID = info.RequesterRoleID
+	approvalRule.Priority = info.Priority
+	approvalRule.Status = info.Status
+	approvalRule.Created = time.Now()
+	approvalRule.CreatedBy = info.User
+	approvalRule.Updated = time.Now()
+	approvalRule.UpdatedBy = info.User
+	err = repo.CreateApprovalRule(approvalRule)
+	if err != nil {
+		msg := "create approval rule error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &approvalRuleID, err
+}
+
+// saveApprovalRule checks the rule and writes its steps
+func saveApprovalRule(repo *commonRepository, approvalRuleID string, info ApprovalRuleNew) error {
+	isConflict, err := repo.CheckApprovalRuleConfict(approvalRuleID, info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error"
+		return errors.New(msg)
+	}
+	if isConflict {
+		msg := "approval rule name exists"
+		return errors.New(msg)
+	}
+	if info.MaxTotal > 0 && info.MaxTotal < info.MinTotal {
+		msg := "max total must not be less than min total"
+		return errors.New(msg)
+	}
+	roles := []string{info.RequesterRoleID}
+	for _, step := range info.Steps {
+		roles = append(roles, step.RoleID)
+	}
+	for _, roleID := range roles {
+		if roleID == "" {
+			continue
+		}
+		roleExist, err := repo.CheckRoleExist(info.OrganizationID, roleID)
+		if err != nil {
+			msg := "check role error"
+			return errors.New(msg)
+		}
+		if !roleExist {
+			msg := "role not exist: " + roleID
+			return errors.New(msg)
+		}
+	}
+	err = repo.DeleteApprovalRuleSteps(approvalRuleID, info.User)
+	if err != nil {
+		msg := "delete approval rule steps error"
+		return errors.New(msg)
+	}
+	for i, step := range info.Steps {
+		var ruleStep ApprovalRuleStep
+		ruleStep.OrganizationID = info.OrganizationID
+		ruleStep.ApprovalRuleStepID = "aprs-" + xid.New().String()
+		ruleStep.ApprovalRuleID = approvalRuleID
+		ruleStep.Step = i + 1
+		ruleStep.RoleID = step.RoleID
+		ruleStep.Status = 1
+		ruleStep.Created = time.Now()
+		ruleStep.CreatedBy = info.User
+		ruleStep.Updated = time.Now()
+		ruleStep.UpdatedBy = info.User
+		err = repo.CreateApprovalRuleStep(ruleStep)
+		if err != nil {
+			msg := "create approval rule step error"
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func (s *commonService) GetApprovalRuleList(filter ApprovalRuleFilter) (int, *[]ApprovalRuleResponse, error) {
+	db := database.RDB()
+	query := NewCommonQuery(db)
+	count, err := query.GetApprovalRuleCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetApprovalRuleList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i := range *list {
+		steps, err := query.GetApprovalRuleStepList((*list)[i].ApprovalRuleID)
+		if err != nil {
+			return 0, nil, err
+		}
+		(*list)[i].Steps = *steps
+	}
+	return count, list, err
+}
+
+func (s *commonService) GetApprovalRuleByID(organizationID, id string) (*ApprovalRuleResponse, error) {
+	db := database.RDB()
+	query := NewCommonQuery(db)
+	approvalRule, err := query.GetApprovalRuleByID(organizationID, id)
+	if err != nil {
+		msg := "approval rule not exist"
+		return nil, errors.New(msg)
+	}
+	steps, err := query.GetApprovalRuleStepList(id)
+	if err != nil {
+		return nil, err
+	}
+	approvalRule.Steps = *steps
+	return approvalRule, nil
+}
+
+func (s *commonService) UpdateApprovalRule(approvalRuleID string, info ApprovalRuleNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewCommonRepository(tx)
+	_, err = repo.GetApprovalRuleByID(info.OrganizationID, approvalRuleID)
+	if err != nil {
+		msg := "approval rule not exist"
+		return nil, errors.New(msg)
+	}
+	err = saveApprovalRule(repo, approvalRuleID, info)
+	if err != nil {
+		return nil, err
+	}
+	var approvalRule ApprovalRule
+	approvalRule.DocumentType = info.DocumentType
+	approvalRule.Name = info.Name
+	approvalRule.MinTotal = info.MinTotal
+	approvalRule.MaxTotal = info.MaxTotal
+	approvalRule.ContactID = info.ContactID
+	approvalRule.RequesterRoleID = info.RequesterRoleID
+	approvalRule.Priority = info.Priority
+	approvalRule.Status = info.Status
+	approvalRule.Updated = time.Now()
+	approvalRule.UpdatedBy = info.User
+	err = repo.UpdateApprovalRule(approvalRuleID, approvalRule)
+	if err != nil {
+		msg := "update approval rule error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &approvalRuleID, err
+}
+
+func (s *commonService) DeleteApprovalRule(approvalRuleID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewCommonRepository(tx)
+	_, err = repo.GetApprovalRuleByID(organizationID, approvalRuleID)
+	if err != nil {
+		msg := "approval rule not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteApprovalRuleSteps(approvalRuleID, user)
+	if err != nil {
+		msg := "delete approval rule steps error"
+		return errors.New(msg)
+	}
+	err = repo.DeleteApprovalRule(approvalRuleID, user)
+	if err != nil {
+		msg := "delete approval rule error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *commonService) GetApprovalList(filter ApprovalFilter) (int, *[]ApprovalResponse, error) {
+	db := database.RDB()
+	query := NewCommonQuery(db)
+	count, err := query.GetApprovalCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetApprovalList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *commonService) GetApprovalByID(organizationID, id string) (*ApprovalResponse, error) {
+	db := database.RDB()
+	query := NewCommonQuery(db)
+	approval, err := query.GetApprovalByID(organizationID, id)
+	if err != nil {
+		msg := "approval not exist"
+		return nil, errors.New(msg)
+	}
+	steps, err := query.GetApprovalStepList(id)
+	if err != nil {
+		return nil, err
+	}
+	approval.Steps = *steps
+	return approval, nil
+}
+
+// StartApproval starts the approval of a document when one of the rules of
+// the organization applies to it. It runs in the transaction of the document
+// module and returns nil when no approval is needed, or the approval and the
+// description for the document history.
+func StartApproval(repo *commonRepository, info ApprovalStart) (*Approval, string, error) {
+	rule, err := repo.MatchApprovalRule(info)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		msg := "match approval rule error"
+		return nil, "", errors.New(msg)
+	}
+	steps, err := repo.GetApprovalRuleStepList(rule.ApprovalRuleID)
+	if err != nil {
+		msg := "get approval rule steps error"
+		return nil, "", errors.New(msg)
+	}
+	if len(*steps) == 0 {
+		return nil, "", nil
+	}
+	err = repo.CancelApproval(info.OrganizationID, info.DocumentType, info.DocumentID, info.Email)
+	if err != nil {
+		msg := "cancel approval error"
+		return nil, "", errors.New(msg)
+	}
+	var approval Approval
+	approval.OrganizationID = info.OrganizationID
+	approval.ApprovalID = "apv-" + xid.New().String()
+	approval.ApprovalRuleID = rule.ApprovalRuleID
+	approval.RuleName = rule.Name
+	approval.DocumentType = info.DocumentType
+	approval.DocumentID = info.DocumentID
+	approval.DocumentNumber = info.DocumentNumber
+	approval.Total = info.Total
+	approval.CurrentStep = 1
+	approval.StepCount = len(*steps)
+	approval.RequestedBy = info.Email
+	approval.Status = 1
+	approval.Created = time.Now()
+	approval.CreatedBy = info.Email
+	approval.Updated = time.Now()
+	approval.UpdatedBy = info.Email
+	err = repo.CreateApproval(approval)
+	if err != nil {
+		msg := "create approval error"
+		return nil, "", errors.New(msg)
+	}
+	// the steps are copied so later rule changes do not affect this approval
+	for _, ruleStep := range *steps {
+		var step ApprovalStep
+		step.OrganizationID = info.OrganizationID
+		step.ApprovalStepID = "apvs-" + xid.New().String()
+		step.ApprovalID = approval.ApprovalID
+		step.Step = ruleStep.Step
+		step.RoleID = ruleStep.RoleID
+		step.Action = 1
+		step.Status = 1
+		step.Created = time.Now()
+		step.CreatedBy = info.Email
+		step.Updated = time.Now()
+		step.UpdatedBy = info.Email
+		err = repo.CreateApprovalStep(step)
+		if err != nil {
+			msg := "create approval step error"
+			return nil, "", errors.New(msg)
+		}
+	}
+	description := fmt.Sprintf("Approval Requested: %s, %d step(s)", rule.Name, approval.StepCount)
+	return &approval, description, nil
+}
+
+// ActApproval approves or rejects the current step of the pending approval of
+// a document. Only the role of the current step can act, and not the user who
+// requested the approval. The approval status (1 pending, 2 approved, 3
+// rejected) and the description for the document history are returned.
+func ActApproval(repo *commonRepository, info ApprovalAction) (int, string, error) {
+	approval, err := repo.GetPendingApproval(info.OrganizationID, info.DocumentType, info.DocumentID)
+	if err != nil {
+		msg := "no pending approval"
+		return 0, "", errors.New(msg)
+	}
+	step, err := repo.GetApprovalStep(approval.ApprovalID, approval.CurrentStep)
+	if err != nil {
+		msg := "approval step not exist"
+		return 0, "", errors.New(msg)
+	}
+	if step.RoleID != info.RoleID {
+		msg := "no privilege to act on this approval step"
+		return 0, "", errors.New(msg)
+	}
+	if approval.RequestedBy == info.Email {
+		msg := "approval can not be acted by the requester"
+		return 0, "", errors.New(msg)
+	}
+	action := 2
+	status := 1
+	currentStep := approval.CurrentStep
+	description := fmt.Sprintf("Approval Step %d/%d Approved", approval.CurrentStep, approval.StepCount)
+	if info.Approve == 2 {
+		if info.Comments == "" {
+			msg := "comments required to reject"
+			return 0, "", errors.New(msg)
+		}
+		action = 3
+		status = 3
+		description = fmt.Sprintf("Approval Rejected at Step %d/%d", approval.CurrentStep, approval.StepCount)
+	} else if approval.CurrentStep >= approval.StepCount {
+		status = 2
+		description = "Approval Completed"
+	} else {
+		currentStep++
+	}
+	if info.Comments != "" {
+		description += ": " + info.Comments
+	}
+	err = repo.UpdateApprovalStep(step.ApprovalStepID, action, info.Comments, info.Email)
+	if err != nil {
+		msg := "update approval step error"
+		return 0, "", errors.New(msg)
+	}
+	err = repo.UpdateApproval(approval.ApprovalID, currentStep, status, info.Email)
+	if err != nil {
+		msg := "update approval error"
+		return 0, "", errors.New(msg)
+	}
+	return status, description, nil
+}
+
+//notification
+
+// RaiseNotification adds a notification in the transaction of the calling
+// module. It is skipped while an unread one of the same type and reference
+// exists, so a job running every hour does not repeat itself.
+func RaiseNotification(repo *commonRepository, info NotificationNew) error {
+	isExist, err := repo.CheckUnreadNotificationExist(info.OrganizationID, info.NotificationType, info.ReferenceID)
+	if err != nil {
+		msg := "check notification error"
+		return errors.New(msg)
+	}
+	if isExist {
+		return nil
+	}
+	var notification Notification
+	notification.OrganizationID = info.OrganizationID
+	notification.NotificationID = "ntf-" + xid.New().String()
+	notification.NotificationType = info.NotificationType
+	notification.ReferenceID = info.ReferenceID
+	notification.Title = info.Title
+	notification.Description = info.Description
+	notification.IsRead = 2
+	notification.Status = 1
+	notification.Created = time.Now()
+	notification.CreatedBy = info.Email
+	notification.Updated = time.Now()
+	notification.UpdatedBy = info.Email
+	err = repo.CreateNotification(notification)
+	if err != nil {
+		msg := "create notification error"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// RefreshNotification keeps one notification per type and reference for a
+// condition a job finds on every run. The existing one, read or not, gets the
+// latest title and description instead of a new one being added.
+func RefreshNotification(repo *commonRepository, info NotificationNew) error {
+	isExist, err := repo.CheckNotificationExist(info.OrganizationID, info.NotificationType, info.ReferenceID)
+	if err != nil {
+		msg := "check notification error"
+		return errors.New(msg)
+	}
+	if !isExist {
+		return RaiseNotification(repo, info)
+	}
+	var notification Notification
+	notification.OrganizationID = info.OrganizationID
+	notification.NotificationType = info.NotificationType
+	notification.ReferenceID = info.ReferenceID
+	notification.Title = info.Title
+	notification.Description = info.Description
+	notification.Updated = time.Now()
+	notification.UpdatedBy = info.Email
+	err = repo.UpdateNotificationByReference(notification)
+	if err != nil {
+		msg := "update notification error"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *commonService) GetNotificationList(filter NotificationFilter) (int, *[]NotificationResponse, error) {
+	db := database.RDB()
+	query := NewCommonQuery(db)
+	count, err := query.GetNotificationCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetNotificationList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *commonService) ReadNotification(organizationID, notificationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewCommonRepository(tx)
+	if notificationID != "" {
+		_, err = repo.GetNotificationByID(organizationID, notificationID)
+		if err != nil {
+			msg := "notification not exist"
+			return errors.New(msg)
+		}
+	}
+	err = repo.ReadNotifications(organizationID, notificationID, user)
+	if err != nil {
+		msg := "update notification error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
@@ -233,6 +233,8 @@ func (s *crmService) ConvertLead(LeadID string, info LeadConvertNew) (*string, e
 		customer.Zip = oldLead.Zip
 		customer.Phone = oldLead.Phone
 		customer.Fax = oldLead.Fax
+		customer.TaxExempt = 2
+		customer.CreditHold = 2
 		customer.Status = 1
 		customer.Created = time.Now()
 		customer.CreatedBy = info.Email
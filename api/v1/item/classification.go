@@ -0,0 +1,79 @@
+package item
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	classificationWeeks    = 52
+	classificationInterval = 7 //days between two runs of the job
+	abcShareA              = 80.0
+	abcShareB              = 95.0
+	xyzLimitX              = 0.5
+	xyzLimitY              = 1.0
+)
+
+// abcClasses ranks the items by consumption value. Items are A until 80
+// percent of the total value is reached, then B until 95 percent, the rest
+// and items without consumption are C. The cumulative share up to each item
+// is returned alongside.
+func abcClasses(list []ItemConsumptionResponse) (map[string]string, map[string]float64) {
+	sorted := make([]ItemConsumptionResponse, len(list))
+	copy(sorted, list)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+	total := 0.0
+	for _, v := range sorted {
+		total += v.Value
+	}
+	classes := make(map[string]string)
+	shares := make(map[string]float64)
+	cumulative := 0.0
+	for _, v := range sorted {
+		class := "C"
+		if total > 0 && v.Value > 0 {
+			switch before := cumulative / total * 100; {
+			case before < abcShareA:
+				class = "A"
+			case before < abcShareB:
+				class = "B"
+			}
+			cumulative += v.Value
+			shares[v.ItemID] = math.Round(cumulative/total*10000) / 100
+		}
+		classes[v.ItemID] = class
+	}
+	return classes, shares
+}
+
+// xyzClass classifies the weekly demand by its coefficient of variation, X
+// steady up to 0.5, Y up to 1 and Z above or without demand
+func xyzClass(weeks []int) (string, float64, float64) {
+	if len(weeks) == 0 {
+		return "Z", 0, 0
+	}
+	sum := 0.0
+	for _, v := range weeks {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(weeks))
+	if mean <= 0 {
+		return "Z", 0, 0
+	}
+	variance := 0.0
+	for _, v := range weeks {
+		variance += (float64(v) - mean) * (float64(v) - mean)
+	}
+	cv := math.Sqrt(variance/float64(len(weeks))) / mean
+	mean = math.Round(mean*100) / 100
+	cv = math.Round(cv*1000) / 1000
+	switch {
+	case cv <= xyzLimitX:
+		return "X", mean, cv
+	case cv <= xyzLimitY:
+		return "Y", mean, cv
+	}
+	return "Z", mean, cv
+}
@@ -45,6 +45,9 @@ func NewItem(c *gin.Context) {
 // @Param page_id query int true "页码"
 // @Param page_size query int true "每页行数（5/10/15/20）"
 // @Param name query string false "商品名称"
+// @Param item_group_id query string false "商品组ID"
+// @Param abc_class query string false "ABC分类 A B C"
+// @Param xyz_class query string false "XYZ分类 X Y Z"
 // @Success 200 object response.ListRes{data=[]ItemResponse} 成功
 // @Failure 400 object response.ErrorRes 内部错误
 // @Router /items [GET]
@@ -326,3 +329,921 @@ func GetBarcodeByCode(c *gin.Context) {
 	response.Response(c, barcode)
 
 }
+
+// @Summary 扫码解析
+// @Id 238
+// @Tags 条码管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param code query string true "扫描内容 支持普通条码及GS1-128/GS1 DataMatrix（(01)...格式或以GS分隔的原始格式）"
+// @Success 200 object response.SuccessRes{data=ScanResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /scans [GET]
+func GetScan(c *gin.Context) {
+	var filter ScanFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	scan, err := itemService.ResolveScan(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, scan)
+}
+
+// @Summary 价格表列表
+// @Id 212
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param name query string false "价格表名称"
+// @Success 200 object response.ListRes{data=[]PriceListResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists [GET]
+func GetPriceListList(c *gin.Context) {
+	var filter PriceListFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	count, list, err := itemService.GetPriceListList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 新建价格表
+// @Id 213
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param price_list_info body PriceListNew true "价格表信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists [POST]
+func NewPriceList(c *gin.Context) {
+	var priceList PriceListNew
+	if err := c.ShouldBindJSON(&priceList); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	priceList.User = claims.UserName
+	priceList.Email = claims.Email
+	priceList.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	new, err := itemService.NewPriceList(priceList)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID获取价格表
+// @Id 214
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "价格表ID"
+// @Success 200 object response.SuccessRes{data=PriceListResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists/:id [GET]
+func GetPriceListByID(c *gin.Context) {
+	var uri PriceListID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	priceList, err := itemService.GetPriceListByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, priceList)
+}
+
+// @Summary 根据ID更新价格表
+// @Id 215
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "价格表ID"
+// @Param price_list_info body PriceListNew true "价格表信息"
+// @Success 200 object response.SuccessRes{data=PriceListResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists/:id [PUT]
+func UpdatePriceList(c *gin.Context) {
+	var uri PriceListID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var priceList PriceListNew
+	if err := c.ShouldBindJSON(&priceList); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	priceList.User = claims.UserName
+	priceList.Email = claims.Email
+	priceList.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	new, err := itemService.UpdatePriceList(uri.ID, priceList)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除价格表
+// @Id 216
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "价格表ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists/:id [DELETE]
+func DeletePriceList(c *gin.Context) {
+	var uri PriceListID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	err := itemService.DeletePriceList(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 价格表规则列表
+// @Id 217
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "价格表ID"
+// @Success 200 object response.SuccessRes{data=[]PriceListItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pricelists/:id/items [GET]
+func GetPriceListItemList(c *gin.Context) {
+	var uri PriceListID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetPriceListItemList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 客户商品报价
+// @Id 218
+// @Tags 价格表管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param customer_id query string true "客户ID"
+// @Param item_id query string true "商品ID"
+// @Param quantity query int true "数量"
+// @Param date query string false "日期"
+// @Success 200 object response.SuccessRes{data=PriceQuoteResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /prices [GET]
+func GetPriceQuote(c *gin.Context) {
+	var filter PriceQuoteFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	quote, err := itemService.GetPriceQuote(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, quote)
+}
+
+// @Summary 新建商品组
+// @Id 219
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param item_group_info body ItemGroupNew true "商品组信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups [POST]
+func NewItemGroup(c *gin.Context) {
+	var info ItemGroupNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.NewItemGroup(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 商品组列表
+// @Id 220
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param name query string false "商品组名称"
+// @Success 200 object response.ListRes{data=[]ItemGroupResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups [GET]
+func GetItemGroupList(c *gin.Context) {
+	var filter ItemGroupFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	count, list, err := itemService.GetItemGroupList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID更新商品组
+// @Id 221
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Param item_group_info body ItemGroupNew true "商品组信息"
+// @Success 200 object response.SuccessRes{data=ItemGroupResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id [PUT]
+func UpdateItemGroup(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemGroupNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.UserName
+	info.Email = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	new, err := itemService.UpdateItemGroup(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID获取商品组
+// @Id 222
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Success 200 object response.SuccessRes{data=ItemGroupResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id [GET]
+func GetItemGroupByID(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	itemGroup, err := itemService.GetItemGroupByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, itemGroup)
+}
+
+// @Summary 根据ID删除商品组
+// @Id 223
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id [DELETE]
+func DeleteItemGroup(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	err := itemService.DeleteItemGroup(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 商品组属性列表
+// @Id 224
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Success 200 object response.SuccessRes{data=[]ItemGroupAttributeResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/attributes [GET]
+func GetItemGroupAttributeList(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetItemGroupAttributeList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 新建商品组属性
+// @Id 225
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Param attribute_info body ItemGroupAttributeNew true "属性信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/attributes [POST]
+func NewItemGroupAttribute(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemGroupAttributeNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.NewItemGroupAttribute(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID更新商品组属性
+// @Id 226
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "属性ID"
+// @Param attribute_info body ItemGroupAttributeNew true "属性信息"
+// @Success 200 object response.SuccessRes{data=ItemGroupAttributeResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroupattributes/:id [PUT]
+func UpdateItemGroupAttribute(c *gin.Context) {
+	var uri ItemGroupAttributeID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemGroupAttributeNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.UpdateItemGroupAttribute(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除商品组属性
+// @Id 227
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "属性ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroupattributes/:id [DELETE]
+func DeleteItemGroupAttribute(c *gin.Context) {
+	var uri ItemGroupAttributeID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	err := itemService.DeleteItemGroupAttribute(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 生成商品组规格商品
+// @Id 228
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Param variant_info body ItemVariantNew true "规格信息"
+// @Success 200 object response.SuccessRes{data=[]string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/variants [POST]
+func GenerateItemVariants(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemVariantNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.GenerateItemVariants(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 商品组规格商品列表
+// @Id 229
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Success 200 object response.SuccessRes{data=[]ItemVariantResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/items [GET]
+func GetItemVariantList(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetItemVariantList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 商品组库存汇总
+// @Id 230
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Success 200 object response.SuccessRes{data=ItemGroupStockResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/stock [GET]
+func GetItemGroupStock(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	stock, err := itemService.GetItemGroupStock(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, stock)
+}
+
+// @Summary 批量更新商品组价格
+// @Id 231
+// @Tags 商品组管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品组ID"
+// @Param price_info body ItemGroupPriceNew true "价格信息"
+// @Success 200 object response.SuccessRes{data=[]string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemgroups/:id/prices [PUT]
+func UpdateItemGroupPrices(c *gin.Context) {
+	var uri ItemGroupID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemGroupPriceNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.UpdateItemGroupPrices(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 商品单位换算列表
+// @Id 232
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Success 200 object response.SuccessRes{data=[]ItemUnitResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/units [GET]
+func GetItemUnitList(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetItemUnitList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 新建商品单位换算
+// @Id 233
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Param unit_info body ItemUnitNew true "单位换算信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/units [POST]
+func NewItemUnit(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemUnitNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.NewItemUnit(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID更新商品单位换算
+// @Id 234
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "单位换算ID"
+// @Param unit_info body ItemUnitNew true "单位换算信息"
+// @Success 200 object response.SuccessRes{data=ItemUnitResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemunits/:id [PUT]
+func UpdateItemUnit(c *gin.Context) {
+	var uri ItemUnitID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemUnitNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	new, err := itemService.UpdateItemUnit(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除商品单位换算
+// @Id 235
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "单位换算ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemunits/:id [DELETE]
+func DeleteItemUnit(c *gin.Context) {
+	var uri ItemUnitID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	err := itemService.DeleteItemUnit(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 商品组件列表
+// @Id 236
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Success 200 object response.SuccessRes{data=[]ItemComponentResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/components [GET]
+func GetItemComponentList(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetItemComponentList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 更新商品组件
+// @Id 237
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Param component_info body ItemComponentNew true "组件信息"
+// @Success 200 object response.SuccessRes{data=[]ItemComponentResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/components [PUT]
+func UpdateItemComponents(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ItemComponentNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	itemService := NewItemService()
+	list, err := itemService.UpdateItemComponents(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary ABC/XYZ分类历史
+// @Id 239
+// @Tags ABC/XYZ分类
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param item_id query string false "商品ID"
+// @Param abc_class query string false "ABC分类 A B C"
+// @Param xyz_class query string false "XYZ分类 X Y Z"
+// @Param period_end query string false "分类日期"
+// @Success 200 object response.ListRes{data=[]ItemClassResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemclasses [GET]
+func GetItemClassList(c *gin.Context) {
+	var filter ItemClassFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	count, list, err := itemService.GetItemClassList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 立即计算ABC/XYZ分类
+// @Id 240
+// @Tags ABC/XYZ分类
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=ItemClassRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /itemclasses/run [POST]
+func RunItemClassification(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	res, err := itemService.ClassifyItems(claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 商品价格历史图表
+// @Id 241
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Param date_from query string false "开始日期 默认一年前"
+// @Param date_to query string false "结束日期 默认今天"
+// @Success 200 object response.SuccessRes{data=ItemPriceHistoryResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/pricehistory [GET]
+func GetItemPriceHistory(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var filter ItemPriceHistoryFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	itemService := NewItemService()
+	res, err := itemService.GetItemPriceHistory(uri.ID, filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 商品各供应商最近采购价
+// @Id 242
+// @Tags 商品管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Success 200 object response.SuccessRes{data=[]VendorPriceResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /items/:id/vendorprices [GET]
+func GetVendorPriceList(c *gin.Context) {
+	var uri ItemID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	itemService := NewItemService()
+	list, err := itemService.GetVendorPriceList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
@@ -1,6 +1,7 @@
 package item
 
 import (
+	"go-api/core/barcode"
 	"go-api/core/request"
 )
 
@@ -18,10 +19,12 @@ type ItemNew struct {
 	Height          float64 `json:"height" binding:"omitempty"`
 	SellingPrice    float64 `json:"selling_price" binding:"omitempty"`
 	CostPrice       float64 `json:"cost_price" binding:"omitempty"`
+	PriceDate       string  `json:"price_date" binding:"omitempty,datetime=2006-01-02"` //date a price change takes effect, today when empty
 	ReorderStock    int     `json:"reorder_stock" binding:"omitempty"`
 	DefaultVendorID string  `json:"default_vendor_id" binding:"omitempty"`
 	Description     string  `json:"description" binding:"omitempty"`
 	TrackLocation   int     `json:"track_location" binding:"required,oneof=1 2"`
+	ItemType        int     `json:"item_type" binding:"omitempty,oneof=1 2 3"`
 	Status          int     `json:"status" binding:"required,oneof=1 2"`
 	OrganizationID  string  `json:"organiztion_id" swaggerignore:"true"`
 	User            string  `json:"user" swaggerignore:"true"`
@@ -30,6 +33,9 @@ type ItemNew struct {
 
 type ItemFilter struct {
 	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	ItemGroupID    string `form:"item_group_id" binding:"omitempty,max=64"`
+	AbcClass       string `form:"abc_class" binding:"omitempty,oneof=A B C"`
+	XyzClass       string `form:"xyz_class" binding:"omitempty,oneof=X Y Z"`
 	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
 	request.PageInfo
 }
@@ -61,8 +67,12 @@ type ItemResponse struct {
 	StockPicking      int     `db:"stock_picking" json:"stock_picking"`
 	StockPacking      int     `db:"stock_packing" json:"stock_packing"`
 	DefaultVendorID   string  `db:"default_vendor_id" json:"default_vendor_id"`
+	ItemGroupID       string  `db:"item_group_id" json:"item_group_id"`
 	Description       string  `db:"description" json:"description"`
 	TrackLocation     int     `db:"track_location" json:"track_location"`
+	ItemType          int     `db:"item_type" json:"item_type"`
+	AbcClass          string  `db:"abc_class" json:"abc_class"`
+	XyzClass          string  `db:"xyz_class" json:"xyz_class"`
 	Status            int     `db:"status" json:"status"`
 }
 
@@ -84,14 +94,18 @@ type BarcodeResponse struct {
 	ItemName       string `db:"item_name" json:"item_name"`
 	Code           string `db:"code" json:"code"`
 	SKU            string `db:"sku" json:"sku"`
+	UnitID         string `db:"unit_id" json:"unit_id"`
 	Unit           string `db:"unit" json:"unit"`
 	Quantity       int    `db:"quantity" json:"quantity"`
+	BaseUnit       string `db:"base_unit" json:"base_unit"`
+	BaseQuantity   int    `db:"base_quantity" json:"base_quantity"`
 	Status         int    `db:"status" json:"status"`
 }
 
 type BarcodeNew struct {
 	Code           string `json:"code" binding:"required,min=1,max=64"`
 	ItemID         string `json:"item_id" binding:"required,min=1,max=64"`
+	UnitID         string `json:"unit_id" binding:"omitempty,max=64"`
 	Quantity       int    `json:"quantity" binding:"required,min=1"`
 	Status         int    `json:"status" binding:"required,oneof=1 2"`
 	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
@@ -102,19 +116,414 @@ type BarcodeID struct {
 }
 
 type ItemBatchResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	SKU            string  `db:"sku" json:"sku"`
+	ItemName       string  `db:"item_name" json:"item_name"`
+	BatchID        string  `db:"batch_id" json:"batch_id"`
+	Type           string  `db:"type" json:"type"`
+	ReferenceID    string  `db:"reference_id" json:"reference_id"`
+	LocationID     string  `db:"location_id" json:"location_id"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Rate           float64 `db:"rate" json:"rate"`
+	Balance        int     `db:"balance" json:"balance"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type BarcodeCode struct {
+	Code string `uri:"code" binding:"required,min=1"`
+}
+
+type ScanFilter struct {
+	Code           string `form:"code" binding:"required,min=1,max=200"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type ScanResponse struct {
+	Code         string               `json:"code"`
+	BarcodeID    string               `json:"barcode_id"`
+	ItemID       string               `json:"item_id"`
+	ItemName     string               `json:"item_name"`
+	SKU          string               `json:"sku"`
+	UnitID       string               `json:"unit_id"`
+	Unit         string               `json:"unit"`
+	Quantity     int                  `json:"quantity"`
+	BaseUnit     string               `json:"base_unit"`
+	BaseQuantity int                  `json:"base_quantity"`
+	GTIN         string               `json:"gtin"`
+	Lot          string               `json:"lot"`
+	Serial       string               `json:"serial"`
+	Expiry       string               `json:"expiry"`
+	Elements     []barcode.GS1Element `json:"elements"`
+}
+
+type PriceListNew struct {
+	Name           string             `json:"name" binding:"required,min=1,max=64"`
+	Description    string             `json:"description" binding:"omitempty,max=255"`
+	Items          []PriceListItemNew `json:"items" binding:"required,dive"`
+	Status         int                `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string             `json:"organiztion_id" swaggerignore:"true"`
+	User           string             `json:"user" swaggerignore:"true"`
+	Email          string             `json:"email" swaggerignore:"true"`
+}
+
+type PriceListItemNew struct {
+	ItemID      string  `json:"item_id" binding:"omitempty,max=64"` //empty applies a percentage rule to every item
+	PriceType   int     `json:"price_type" binding:"required,oneof=1 2"`
+	Value       float64 `json:"value" binding:"omitempty,min=0"`
+	MinQuantity int     `json:"min_quantity" binding:"omitempty,min=0"`
+	DateFrom    string  `json:"date_from" binding:"omitempty,datetime=2006-01-02"`
+	DateTo      string  `json:"date_to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type PriceListFilter struct {
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type PriceListResponse struct {
+	PriceListID    string `db:"price_list_id" json:"price_list_id"`
+	OrganizationID string `db:"organization_id" json:"organization_id"`
+	Name           string `db:"name" json:"name"`
+	Description    string `db:"description" json:"description"`
+	Status         int    `db:"status" json:"status"`
+}
+
+type PriceListItemResponse struct {
+	PriceListItemID string  `db:"price_list_item_id" json:"price_list_item_id"`
+	PriceListID     string  `db:"price_list_id" json:"price_list_id"`
+	ItemID          string  `db:"item_id" json:"item_id"`
+	SKU             string  `db:"sku" json:"sku"`
+	ItemName        string  `db:"item_name" json:"item_name"`
+	PriceType       int     `db:"price_type" json:"price_type"`
+	Value           float64 `db:"value" json:"value"`
+	MinQuantity     int     `db:"min_quantity" json:"min_quantity"`
+	DateFrom        string  `db:"date_from" json:"date_from"`
+	DateTo          string  `db:"date_to" json:"date_to"`
+	Status          int     `db:"status" json:"status"`
+}
+
+type PriceListID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type PriceQuoteFilter struct {
+	CustomerID     string `form:"customer_id" binding:"required,min=1,max=64"`
+	ItemID         string `form:"item_id" binding:"required,min=1,max=64"`
+	Quantity       int    `form:"quantity" binding:"required,min=1"`
+	Date           string `form:"date" binding:"omitempty,datetime=2006-01-02"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type PriceQuoteResponse struct {
+	ItemID          string  `json:"item_id"`
+	CustomerID      string  `json:"customer_id"`
+	PriceListID     string  `json:"price_list_id"`
+	PriceListItemID string  `json:"price_list_item_id"`
+	Quantity        int     `json:"quantity"`
+	SellingPrice    float64 `json:"selling_price"`
+	Rate            float64 `json:"rate"`
+	Amount          float64 `json:"amount"`
+}
+
+type ItemGroupNew struct {
+	Name           string                  `json:"name" binding:"required,min=1,max=255"`
+	UnitID         string                  `json:"unit_id" binding:"required,min=6,max=64"`
+	ManufacturerID string                  `json:"manufacturer_id" binding:"omitempty,min=6,max=64"`
+	BrandID        string                  `json:"brand_id" binding:"omitempty,min=6,max=64"`
+	SKUPattern     string                  `json:"sku_pattern" binding:"required,min=1,max=64"`
+	SellingPrice   float64                 `json:"selling_price" binding:"omitempty,min=0"`
+	CostPrice      float64                 `json:"cost_price" binding:"omitempty,min=0"`
+	TrackLocation  int                     `json:"track_location" binding:"required,oneof=1 2"`
+	Description    string                  `json:"description" binding:"omitempty"`
+	Attributes     []ItemGroupAttributeNew `json:"attributes" binding:"omitempty,dive"`
+	Status         int                     `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string                  `json:"organiztion_id" swaggerignore:"true"`
+	User           string                  `json:"user" swaggerignore:"true"`
+	Email          string                  `json:"email" swaggerignore:"true"`
+}
+
+type ItemGroupAttributeNew struct {
+	Name           string                        `json:"name" binding:"required,min=1,max=64"`
+	Options        []ItemGroupAttributeOptionNew `json:"options" binding:"required,min=1,dive"`
+	OrganizationID string                        `json:"organiztion_id" swaggerignore:"true"`
+	User           string                        `json:"user" swaggerignore:"true"`
+	Email          string                        `json:"email" swaggerignore:"true"`
+}
+
+type ItemGroupAttributeOptionNew struct {
+	OptionID string `json:"option_id" binding:"omitempty"`
+	Value    string `json:"value" binding:"required,min=1,max=64"`
+	Code     string `json:"code" binding:"omitempty,max=16"`
+}
+
+type ItemGroupFilter struct {
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ItemGroupResponse struct {
+	ItemGroupID      string  `db:"item_group_id" json:"item_group_id"`
+	OrganizationID   string  `db:"organization_id" json:"organization_id"`
+	Name             string  `db:"name" json:"name"`
+	UnitID           string  `db:"unit_id" json:"unit_id"`
+	UnitName         string  `db:"unit_name" json:"unit_name"`
+	ManufacturerID   string  `db:"manufacturer_id" json:"manufacturer_id"`
+	ManufacturerName string  `db:"manufacturer_name" json:"manufacturer_name"`
+	BrandID          string  `db:"brand_id" json:"brand_id"`
+	BrandName        string  `db:"brand_name" json:"brand_name"`
+	SKUPattern       string  `db:"sku_pattern" json:"sku_pattern"`
+	SellingPrice     float64 `db:"selling_price" json:"selling_price"`
+	CostPrice        float64 `db:"cost_price" json:"cost_price"`
+	TrackLocation    int     `db:"track_location" json:"track_location"`
+	Description      string  `db:"description" json:"description"`
+	ItemCount        int     `db:"item_count" json:"item_count"`
+	Status           int     `db:"status" json:"status"`
+}
+
+type ItemGroupAttributeResponse struct {
+	AttributeID string                             `db:"attribute_id" json:"attribute_id"`
+	ItemGroupID string                             `db:"item_group_id" json:"item_group_id"`
+	Name        string                             `db:"name" json:"name"`
+	Position    int                                `db:"position" json:"position"`
+	Options     []ItemGroupAttributeOptionResponse `json:"options"`
+}
+
+type ItemGroupAttributeOptionResponse struct {
+	OptionID    string `db:"option_id" json:"option_id"`
+	AttributeID string `db:"attribute_id" json:"attribute_id"`
+	Value       string `db:"value" json:"value"`
+	Code        string `db:"code" json:"code"`
+	Position    int    `db:"position" json:"position"`
+}
+
+type ItemGroupID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ItemGroupAttributeID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ItemVariantNew struct {
+	SKUPattern     string   `json:"sku_pattern" binding:"omitempty,max=64"`
+	OptionID       []string `json:"option_id" binding:"omitempty"`
+	OrganizationID string   `json:"organiztion_id" swaggerignore:"true"`
+	User           string   `json:"user" swaggerignore:"true"`
+	Email          string   `json:"email" swaggerignore:"true"`
+}
+
+type ItemVariantResponse struct {
+	ItemID         string  `db:"item_id" json:"item_id"`
+	SKU            string  `db:"sku" json:"sku"`
+	Name           string  `db:"name" json:"name"`
+	Options        string  `db:"options" json:"options"`
+	SellingPrice   float64 `db:"selling_price" json:"selling_price"`
+	CostPrice      float64 `db:"cost_price" json:"cost_price"`
+	StockOnHand    int     `db:"stock_on_hand" json:"stock_on_hand"`
+	StockAvailable int     `db:"stock_available" json:"stock_available"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type ItemGroupStockResponse struct {
+	ItemGroupID    string                         `json:"item_group_id"`
+	ItemCount      int                            `json:"item_count"`
+	StockOnHand    int                            `json:"stock_on_hand"`
+	StockAvailable int                            `json:"stock_available"`
+	StockPicking   int                            `json:"stock_picking"`
+	StockPacking   int                            `json:"stock_packing"`
+	Options        []ItemGroupOptionStockResponse `json:"options"`
+}
+
+type ItemGroupOptionStockResponse struct {
+	AttributeID    string `db:"attribute_id" json:"attribute_id"`
+	AttributeName  string `db:"attribute_name" json:"attribute_name"`
+	OptionID       string `db:"option_id" json:"option_id"`
+	Value          string `db:"value" json:"value"`
+	ItemCount      int    `db:"item_count" json:"item_count"`
+	StockOnHand    int    `db:"stock_on_hand" json:"stock_on_hand"`
+	StockAvailable int    `db:"stock_available" json:"stock_available"`
+	StockPicking   int    `db:"stock_picking" json:"stock_picking"`
+	StockPacking   int    `db:"stock_packing" json:"stock_packing"`
+}
+
+type ItemGroupPriceNew struct {
+	OptionID       string  `json:"option_id" binding:"omitempty"`
+	SellingPrice   float64 `json:"selling_price" binding:"omitempty,min=0"`
+	CostPrice      float64 `json:"cost_price" binding:"omitempty,min=0"`
+	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
+	User           string  `json:"user" swaggerignore:"true"`
+	Email          string  `json:"email" swaggerignore:"true"`
+}
+
+type ItemUnitNew struct {
+	UnitID         string `json:"unit_id" binding:"required,min=1,max=64"`
+	Factor         int    `json:"factor" binding:"required,min=1"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type ItemUnitResponse struct {
+	ItemUnitID     string `db:"item_unit_id" json:"item_unit_id"`
 	OrganizationID string `db:"organization_id" json:"organization_id"`
 	ItemID         string `db:"item_id" json:"item_id"`
-	SKU            string `db:"sku" json:"sku"`
-	ItemName       string `db:"item_name" json:"item_name"`
-	BatchID        string `db:"batch_id" json:"batch_id"`
-	Type           string `db:"type" json:"type"`
-	ReferenceID    string `db:"reference_id" json:"reference_id"`
-	LocationID     string `db:"location_id" json:"location_id"`
-	Quantity       int    `db:"quantity" json:"quantity"`
-	Balance        int    `db:"balance" json:"balance"`
+	UnitID         string `db:"unit_id" json:"unit_id"`
+	UnitName       string `db:"unit_name" json:"unit_name"`
+	Factor         int    `db:"factor" json:"factor"`
 	Status         int    `db:"status" json:"status"`
 }
 
-type BarcodeCode struct {
-	Code string `uri:"code" binding:"required,min=1"`
+type ItemUnitID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ItemComponentNew struct {
+	Components     []ItemComponentLineNew `json:"components" binding:"omitempty,dive"`
+	OrganizationID string                 `json:"organiztion_id" swaggerignore:"true"`
+	User           string                 `json:"user" swaggerignore:"true"`
+	Email          string                 `json:"email" swaggerignore:"true"`
+}
+
+type ItemComponentLineNew struct {
+	ComponentID string `json:"component_id" binding:"required,min=1,max=64"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+}
+
+type ItemComponentResponse struct {
+	ItemComponentID string  `db:"item_component_id" json:"item_component_id"`
+	OrganizationID  string  `db:"organization_id" json:"organization_id"`
+	ItemID          string  `db:"item_id" json:"item_id"`
+	ComponentID     string  `db:"component_id" json:"component_id"`
+	SKU             string  `db:"sku" json:"sku"`
+	Name            string  `db:"name" json:"name"`
+	UnitName        string  `db:"unit_name" json:"unit_name"`
+	Quantity        int     `db:"quantity" json:"quantity"`
+	CostPrice       float64 `db:"cost_price" json:"cost_price"`
+	StockAvailable  int     `db:"stock_available" json:"stock_available"`
+	Status          int     `db:"status" json:"status"`
+}
+
+// ItemStockLine is one stock item moved when an item is picked or packed,
+// the item itself or each component of a sales kit.
+type ItemStockLine struct {
+	ItemID         string
+	SKU            string
+	Name           string
+	TrackLocation  int
+	StockAvailable int
+	StockPicking   int
+	StockPacking   int
+	Quantity       int
+}
+
+type ItemClassFilter struct {
+	ItemID         string `form:"item_id" binding:"omitempty,max=64"`
+	AbcClass       string `form:"abc_class" binding:"omitempty,oneof=A B C"`
+	XyzClass       string `form:"xyz_class" binding:"omitempty,oneof=X Y Z"`
+	PeriodEnd      string `form:"period_end" binding:"omitempty,datetime=2006-01-02"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ItemClassResponse struct {
+	OrganizationID      string  `db:"organization_id" json:"organization_id"`
+	ItemClassID         string  `db:"item_class_id" json:"item_class_id"`
+	ItemID              string  `db:"item_id" json:"item_id"`
+	SKU                 string  `db:"sku" json:"sku"`
+	ItemName            string  `db:"item_name" json:"item_name"`
+	PeriodStart         string  `db:"period_start" json:"period_start"`
+	PeriodEnd           string  `db:"period_end" json:"period_end"`
+	ConsumptionQuantity int     `db:"consumption_quantity" json:"consumption_quantity"`
+	ConsumptionValue    float64 `db:"consumption_value" json:"consumption_value"`
+	ValueShare          float64 `db:"value_share" json:"value_share"`
+	AbcClass            string  `db:"abc_class" json:"abc_class"`
+	DemandMean          float64 `db:"demand_mean" json:"demand_mean"`
+	DemandCV            float64 `db:"demand_cv" json:"demand_cv"`
+	XyzClass            string  `db:"xyz_class" json:"xyz_class"`
+	Status              int     `db:"status" json:"status"`
+}
+
+type ItemConsumptionResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Value          float64 `db:"value" json:"value"`
+}
+
+type WeeklyDemandResponse struct {
+	ItemID   string `db:"item_id" json:"item_id"`
+	Week     int    `db:"week" json:"week"`
+	Quantity int    `db:"quantity" json:"quantity"`
+}
+
+type ItemClassRunResponse struct {
+	PeriodStart string         `json:"period_start"`
+	PeriodEnd   string         `json:"period_end"`
+	ItemCount   int            `json:"item_count"`
+	AbcCount    map[string]int `json:"abc_count"`
+	XyzCount    map[string]int `json:"xyz_count"`
+}
+
+type ItemPriceResponse struct {
+	ItemPriceID          string  `db:"item_price_id" json:"item_price_id"`
+	ItemID               string  `db:"item_id" json:"item_id"`
+	EffectiveDate        string  `db:"effective_date" json:"effective_date"`
+	SellingPrice         float64 `db:"selling_price" json:"selling_price"`
+	CostPrice            float64 `db:"cost_price" json:"cost_price"`
+	PreviousSellingPrice float64 `db:"previous_selling_price" json:"previous_selling_price"`
+	PreviousCostPrice    float64 `db:"previous_cost_price" json:"previous_cost_price"`
+	Source               string  `db:"source" json:"source"`
+	CreatedBy            string  `db:"created_by" json:"created_by"`
+}
+
+type ItemPriceHistoryFilter struct {
+	DateFrom       string `form:"date_from" binding:"omitempty,datetime=2006-01-02"`
+	DateTo         string `form:"date_to" binding:"omitempty,datetime=2006-01-02"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type ItemPricePoint struct {
+	Date         string  `json:"date"`
+	SellingPrice float64 `json:"selling_price"`
+	CostPrice    float64 `json:"cost_price"`
+}
+
+type PurchaseRateResponse struct {
+	VendorID       string  `db:"vendor_id" json:"vendor_id"`
+	VendorName     string  `db:"vendor_name" json:"vendor_name"`
+	Source         string  `db:"source" json:"source"` //purchaseorder or bill
+	DocumentID     string  `db:"document_id" json:"document_id"`
+	DocumentNumber string  `db:"document_number" json:"document_number"`
+	DocumentDate   string  `db:"document_date" json:"document_date"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Rate           float64 `db:"rate" json:"rate"` //rate of the base unit
+}
+
+type ItemPriceHistoryResponse struct {
+	ItemID    string                 `json:"item_id"`
+	SKU       string                 `json:"sku"`
+	Name      string                 `json:"name"`
+	DateFrom  string                 `json:"date_from"`
+	DateTo    string                 `json:"date_to"`
+	Points    []ItemPricePoint       `json:"points"`    //prices in effect, one point per change and at both ends
+	Changes   []ItemPriceResponse    `json:"changes"`   //price changes within the range
+	Purchases []PurchaseRateResponse `json:"purchases"` //purchase order and bill rates within the range
+}
+
+type VendorPriceResponse struct {
+	VendorID            string  `json:"vendor_id"`
+	VendorName          string  `json:"vendor_name"`
+	LastRate            float64 `json:"last_rate"`
+	LastDate            string  `json:"last_date"`
+	LastSource          string  `json:"last_source"`
+	LastDocumentNumber  string  `json:"last_document_number"`
+	PurchaseorderRate   float64 `json:"purchaseorder_rate"`
+	PurchaseorderDate   string  `json:"purchaseorder_date"`
+	PurchaseorderNumber string  `json:"purchaseorder_number"`
+	BillRate            float64 `json:"bill_rate"`
+	BillDate            string  `json:"bill_date"`
+	BillNumber          string  `json:"bill_number"`
 }
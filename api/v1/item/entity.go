@@ -4,11 +4,16 @@ import "time"
 
 type ItemGroup struct {
 	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
 	ItemGroupID    string    `db:"item_group_id" json:"item_group_id"`
 	Name           string    `db:"name" json:"name"`
-	Unit           string    `db:"unit" json:"unit"`
+	UnitID         string    `db:"unit_id" json:"unit_id"`
 	ManufacturerID string    `db:"manufacturer_id" json:"manufacturer_id"`
 	BrandID        string    `db:"brand_id" json:"brand_id"`
+	SKUPattern     string    `db:"sku_pattern" json:"sku_pattern"`
+	SellingPrice   float64   `db:"selling_price" json:"selling_price"`
+	CostPrice      float64   `db:"cost_price" json:"cost_price"`
+	TrackLocation  int       `db:"track_location" json:"track_location"`
 	Description    string    `db:"description" json:"description"`
 	Status         int       `db:"status" json:"status"`
 	Created        time.Time `db:"created" json:"created"`
@@ -18,27 +23,32 @@ type ItemGroup struct {
 }
 
 type ItemGroupAttribute struct {
-	ID          int64     `db:"id" json:"id"`
-	ItemGroupID string    `db:"item_group_id" json:"item_group_id"`
-	AttributeID string    `db:"attribute_id" json:"attribute_id"`
-	Name        string    `db:"name" json:"name"`
-	Status      int       `db:"status" json:"status"`
-	Created     time.Time `db:"created" json:"created"`
-	CreatedBy   string    `db:"created_by" json:"created_by"`
-	Updated     time.Time `db:"updated" json:"updated"`
-	UpdatedBy   string    `db:"updated_by" json:"updated_by"`
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ItemGroupID    string    `db:"item_group_id" json:"item_group_id"`
+	AttributeID    string    `db:"attribute_id" json:"attribute_id"`
+	Name           string    `db:"name" json:"name"`
+	Position       int       `db:"position" json:"position"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
 }
 
 type ItemGroupAttributeOption struct {
-	ID          int64     `db:"id" json:"id"`
-	AttributeID string    `db:"attribute_id" json:"attribute_id"`
-	OptionID    string    `db:"option_id" json:"option_id"`
-	Value       string    `db:"value" json:"value"`
-	Status      int       `db:"status" json:"status"`
-	Created     time.Time `db:"created" json:"created"`
-	CreatedBy   string    `db:"created_by" json:"created_by"`
-	Updated     time.Time `db:"updated" json:"updated"`
-	UpdatedBy   string    `db:"updated_by" json:"updated_by"`
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	AttributeID    string    `db:"attribute_id" json:"attribute_id"`
+	OptionID       string    `db:"option_id" json:"option_id"`
+	Value          string    `db:"value" json:"value"`
+	Code           string    `db:"code" json:"code"` //used in variant SKU, value when empty
+	Position       int       `db:"position" json:"position"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
 }
 
 type Item struct {
@@ -64,8 +74,12 @@ type Item struct {
 	StockPicking    int       `db:"stock_picking" json:"stock_picking"`
 	StockPacking    int       `db:"stock_packing" json:"stock_packing"`
 	DefaultVendorID string    `db:"default_vendor_id" json:"default_vendor_id"`
+	ItemGroupID     string    `db:"item_group_id" json:"item_group_id"`
 	Description     string    `db:"description" json:"description"`
 	TrackLocation   int       `db:"track_location" json:"track_location"`
+	ItemType        int       `db:"item_type" json:"item_type"` //1 standard, 2 sales kit, 3 assembled kit
+	AbcClass        string    `db:"abc_class" json:"abc_class"` //A B C by consumption value, empty until classified
+	XyzClass        string    `db:"xyz_class" json:"xyz_class"` //X Y Z by demand variability
 	Status          int       `db:"status" json:"status"`
 	Created         time.Time `db:"created" json:"created"`
 	CreatedBy       string    `db:"created_by" json:"created_by"`
@@ -74,15 +88,17 @@ type Item struct {
 }
 
 type ItemAttribute struct {
-	ID          int64     `db:"id" json:"id"`
-	ItemID      string    `db:"item_id" json:"item_id"`
-	AttributeID string    `db:"item_attribute_id" json:"item_attribute_id"`
-	OptionID    string    `db:"option_id" json:"option_id"`
-	Status      int       `db:"status" json:"status"`
-	Created     time.Time `db:"created" json:"created"`
-	CreatedBy   string    `db:"created_by" json:"created_by"`
-	Updated     time.Time `db:"updated" json:"updated"`
-	UpdatedBy   string    `db:"updated_by" json:"updated_by"`
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	ItemAttributeID string    `db:"item_attribute_id" json:"item_attribute_id"`
+	ItemID          string    `db:"item_id" json:"item_id"`
+	AttributeID     string    `db:"attribute_id" json:"attribute_id"`
+	OptionID        string    `db:"option_id" json:"option_id"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
 }
 
 type Barcode struct {
@@ -91,6 +107,7 @@ type Barcode struct {
 	BarcodeID      string    `db:"barcode_id" json:"barcode_id"`
 	Code           string    `db:"code" json:"code"`
 	ItemID         string    `db:"item_id" json:"item_id"`
+	UnitID         string    `db:"unit_id" json:"unit_id"` //empty for the item base unit
 	Quantity       int       `db:"quantity" json:"quantity"`
 	Status         int       `db:"status" json:"status"`
 	Created        time.Time `db:"created" json:"created"`
@@ -99,6 +116,34 @@ type Barcode struct {
 	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
 }
 
+type ItemUnit struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ItemUnitID     string    `db:"item_unit_id" json:"item_unit_id"`
+	ItemID         string    `db:"item_id" json:"item_id"`
+	UnitID         string    `db:"unit_id" json:"unit_id"`
+	Factor         int       `db:"factor" json:"factor"` //base units in one of this unit
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type ItemComponent struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	ItemComponentID string    `db:"item_component_id" json:"item_component_id"`
+	ItemID          string    `db:"item_id" json:"item_id"`
+	ComponentID     string    `db:"component_id" json:"component_id"`
+	Quantity        int       `db:"quantity" json:"quantity"` //base units of the component in one kit
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
 type ItemBatch struct {
 	ID             int64     `db:"id" json:"id"`
 	OrganizationID string    `db:"organization_id" json:"organization_id"`
@@ -116,3 +161,73 @@ type ItemBatch struct {
 	Updated        time.Time `db:"updated" json:"updated"`
 	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
 }
+
+type PriceList struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	PriceListID    string    `db:"price_list_id" json:"price_list_id"`
+	Name           string    `db:"name" json:"name"`
+	Description    string    `db:"description" json:"description"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type PriceListItem struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	PriceListID     string    `db:"price_list_id" json:"price_list_id"`
+	PriceListItemID string    `db:"price_list_item_id" json:"price_list_item_id"`
+	ItemID          string    `db:"item_id" json:"item_id"`
+	PriceType       int       `db:"price_type" json:"price_type"`
+	Value           float64   `db:"value" json:"value"`
+	MinQuantity     int       `db:"min_quantity" json:"min_quantity"`
+	DateFrom        string    `db:"date_from" json:"date_from"`
+	DateTo          string    `db:"date_to" json:"date_to"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type ItemClass struct {
+	ID                  int64     `db:"id" json:"id"`
+	OrganizationID      string    `db:"organization_id" json:"organization_id"`
+	ItemClassID         string    `db:"item_class_id" json:"item_class_id"`
+	ItemID              string    `db:"item_id" json:"item_id"`
+	PeriodStart         string    `db:"period_start" json:"period_start"`
+	PeriodEnd           string    `db:"period_end" json:"period_end"`
+	ConsumptionQuantity int       `db:"consumption_quantity" json:"consumption_quantity"`
+	ConsumptionValue    float64   `db:"consumption_value" json:"consumption_value"` //picked quantity times batch rate
+	ValueShare          float64   `db:"value_share" json:"value_share"`             //cumulative percent of the consumption value up to this item
+	AbcClass            string    `db:"abc_class" json:"abc_class"`
+	DemandMean          float64   `db:"demand_mean" json:"demand_mean"` //weekly
+	DemandCV            float64   `db:"demand_cv" json:"demand_cv"`     //coefficient of variation of the weekly demand
+	XyzClass            string    `db:"xyz_class" json:"xyz_class"`
+	Status              int       `db:"status" json:"status"`
+	Created             time.Time `db:"created" json:"created"`
+	CreatedBy           string    `db:"created_by" json:"created_by"`
+	Updated             time.Time `db:"updated" json:"updated"`
+	UpdatedBy           string    `db:"updated_by" json:"updated_by"`
+}
+
+type ItemPrice struct {
+	ID                   int64     `db:"id" json:"id"`
+	OrganizationID       string    `db:"organization_id" json:"organization_id"`
+	ItemPriceID          string    `db:"item_price_id" json:"item_price_id"`
+	ItemID               string    `db:"item_id" json:"item_id"`
+	EffectiveDate        string    `db:"effective_date" json:"effective_date"` //in effect until the next change
+	SellingPrice         float64   `db:"selling_price" json:"selling_price"`
+	CostPrice            float64   `db:"cost_price" json:"cost_price"`
+	PreviousSellingPrice float64   `db:"previous_selling_price" json:"previous_selling_price"`
+	PreviousCostPrice    float64   `db:"previous_cost_price" json:"previous_cost_price"`
+	Source               string    `db:"source" json:"source"` //item, itemgroup
+	Status               int       `db:"status" json:"status"`
+	Created              time.Time `db:"created" json:"created"`
+	CreatedBy            string    `db:"created_by" json:"created_by"`
+	Updated              time.Time `db:"updated" json:"updated"`
+	UpdatedBy            string    `db:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,13 @@
+package item
+
+import (
+	"go-api/core/job"
+	"time"
+)
+
+func Schedule(r *job.Runner) {
+	r.Every("ItemClassification", time.Hour, func() error {
+		itemService := NewItemService()
+		return itemService.RunItemClassification()
+	})
+}
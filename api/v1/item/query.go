@@ -41,12 +41,22 @@ func (r *itemQuery) GetItemByID(organizationID, id string) (*ItemResponse, error
 	i.cost_price,
 	i.reorder_stock,
 	i.stock_on_hand,
-	i.stock_available,
+	CASE WHEN i.item_type = 2 THEN (
+		SELECT IFNULL(MIN(FLOOR(ci.stock_available / c.quantity)), 0)
+		FROM i_item_components c
+		LEFT JOIN i_items ci
+		ON c.component_id = ci.item_id
+		WHERE c.item_id = i.item_id AND c.status > 0
+	) ELSE i.stock_available END as stock_available,
 	i.stock_picking,
 	i.stock_packing,
 	i.default_vendor_id,
+	i.item_group_id,
 	i.description,
 	i.track_location,
+	i.item_type,
+	i.abc_class,
+	i.xyz_class,
 	i.status
 	FROM i_items i
 	LEFT JOIN s_units u
@@ -72,6 +82,15 @@ func (r *itemQuery) GetItemCount(filter ItemFilter) (int, error) {
 	if v := filter.Name; v != "" {
 		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
 	}
+	if v := filter.ItemGroupID; v != "" {
+		where, args = append(where, "item_group_id = ?"), append(args, v)
+	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "abc_class = ?"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "xyz_class = ?"), append(args, v)
+	}
 	var count int
 	err := r.conn.Get(&count, `
 		SELECT count(1) as count
@@ -88,6 +107,15 @@ func (r *itemQuery) GetItemList(filter ItemFilter) (*[]ItemResponse, error) {
 	if v := filter.OrganizationID; v != "" {
 		where, args = append(where, "i.organization_id = ?"), append(args, v)
 	}
+	if v := filter.ItemGroupID; v != "" {
+		where, args = append(where, "i.item_group_id = ?"), append(args, v)
+	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "i.abc_class = ?"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "i.xyz_class = ?"), append(args, v)
+	}
 	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
 	args = append(args, filter.PageSize)
 	var items []ItemResponse
@@ -115,12 +143,22 @@ func (r *itemQuery) GetItemList(filter ItemFilter) (*[]ItemResponse, error) {
 		i.cost_price,
 		i.reorder_stock,
 		i.stock_on_hand,
-		i.stock_available,
+		CASE WHEN i.item_type = 2 THEN (
+			SELECT IFNULL(MIN(FLOOR(ci.stock_available / c.quantity)), 0)
+			FROM i_item_components c
+			LEFT JOIN i_items ci
+			ON c.component_id = ci.item_id
+			WHERE c.item_id = i.item_id AND c.status > 0
+		) ELSE i.stock_available END as stock_available,
 		i.stock_picking,
 		i.stock_packing,
 		i.default_vendor_id,
+		i.item_group_id,
 		i.description,
 		i.track_location,
+		i.item_type,
+		i.abc_class,
+		i.xyz_class,
 		i.status
 		FROM i_items i
 		LEFT JOIN s_units u
@@ -181,14 +219,21 @@ func (r *itemQuery) GetBarcodeList(filter BarcodeFilter) (*[]BarcodeResponse, er
 		i.name as item_name, 
 		b.code, 
 		i.sku, 
-		u.name as unit, 
+		b.unit_id,
+		IFNULL(bu.name, u.name) as unit, 
 		b.quantity,
+		u.name as base_unit,
+		b.quantity * IFNULL(iu.factor, 1) as base_quantity,
 		b.status
 		FROM i_barcodes b
 		LEFT JOIN i_items i
 		ON b.item_id = i.item_id
 		LEFT JOIN s_units u
 		ON i.unit_id = u.unit_id
+		LEFT JOIN s_units bu
+		ON b.unit_id = bu.unit_id
+		LEFT JOIN i_item_units iu
+		ON b.item_id = iu.item_id AND b.unit_id = iu.unit_id AND iu.status > 0
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
 	`, args...)
@@ -204,14 +249,21 @@ func (r *itemQuery) GetBarcodeByID(organizationID, barcodeID string) (*BarcodeRe
 		i.name as item_name, 
 		b.code, 
 		i.sku, 
-		u.name as unit, 
+		b.unit_id,
+		IFNULL(bu.name, u.name) as unit, 
 		b.quantity,
+		u.name as base_unit,
+		b.quantity * IFNULL(iu.factor, 1) as base_quantity,
 		b.status
 		FROM i_barcodes b
 		LEFT JOIN i_items i
 		ON b.item_id = i.item_id
 		LEFT JOIN s_units u
 		ON i.unit_id = u.unit_id
+		LEFT JOIN s_units bu
+		ON b.unit_id = bu.unit_id
+		LEFT JOIN i_item_units iu
+		ON b.item_id = iu.item_id AND b.unit_id = iu.unit_id AND iu.status > 0
 		WHERE b.organization_id = ? AND b.barcode_id = ? AND b.status > 0
 	`, organizationID, barcodeID)
 	return &barcode, err
@@ -227,15 +279,620 @@ func (r *itemQuery) GetBarcodeByCode(organizationID, code string) (*BarcodeRespo
 		i.name as item_name, 
 		b.code, 
 		i.sku, 
-		u.name as unit, 
+		b.unit_id,
+		IFNULL(bu.name, u.name) as unit, 
 		b.quantity,
+		u.name as base_unit,
+		b.quantity * IFNULL(iu.factor, 1) as base_quantity,
 		b.status
 		FROM i_barcodes b
 		LEFT JOIN i_items i
 		ON b.item_id = i.item_id
 		LEFT JOIN s_units u
 		ON i.unit_id = u.unit_id
+		LEFT JOIN s_units bu
+		ON b.unit_id = bu.unit_id
+		LEFT JOIN i_item_units iu
+		ON b.item_id = iu.item_id AND b.unit_id = iu.unit_id AND iu.status > 0
 		WHERE b.organization_id = ? AND b.code = ? AND b.status > 0
 	`, organizationID, code)
 	return &barcode, err
 }
+
+//Price list
+func (r *itemQuery) GetPriceListCount(filter PriceListFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM i_price_lists
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *itemQuery) GetPriceListList(filter PriceListFilter) (*[]PriceListResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var priceLists []PriceListResponse
+	err := r.conn.Select(&priceLists, `
+		SELECT 
+		price_list_id, 
+		organization_id,
+		name,
+		description,
+		status
+		FROM i_price_lists
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &priceLists, err
+}
+
+func (r *itemQuery) GetPriceListByID(organizationID, priceListID string) (*PriceListResponse, error) {
+	var priceList PriceListResponse
+	err := r.conn.Get(&priceList, `
+		SELECT 
+		price_list_id, 
+		organization_id,
+		name,
+		description,
+		status
+		FROM i_price_lists
+		WHERE organization_id = ? AND price_list_id = ? AND status > 0
+	`, organizationID, priceListID)
+	return &priceList, err
+}
+
+func (r *itemQuery) GetPriceListItemList(organizationID, priceListID string) (*[]PriceListItemResponse, error) {
+	var priceListItems []PriceListItemResponse
+	err := r.conn.Select(&priceListItems, `
+		SELECT 
+		p.price_list_item_id, 
+		p.price_list_id,
+		p.item_id,
+		IFNULL(i.sku, "") as sku,
+		IFNULL(i.name, "") as item_name,
+		p.price_type,
+		p.value,
+		p.min_quantity,
+		p.date_from,
+		p.date_to,
+		p.status
+		FROM i_price_list_items p
+		LEFT JOIN i_items i
+		ON p.item_id = i.item_id
+		WHERE p.organization_id = ? AND p.price_list_id = ? AND p.status > 0
+		ORDER BY p.item_id ASC, p.min_quantity ASC
+	`, organizationID, priceListID)
+	return &priceListItems, err
+}
+
+// GetPriceListRule returns the best matching rule: item specific rules win over
+// list wide ones, then the highest quantity break that the quantity reaches.
+func (r *itemQuery) GetPriceListRule(organizationID, priceListID, itemID string, quantity int, date string) (*PriceListItemResponse, error) {
+	var priceListItem PriceListItemResponse
+	err := r.conn.Get(&priceListItem, `
+		SELECT 
+		p.price_list_item_id, 
+		p.price_list_id,
+		p.item_id,
+		p.price_type,
+		p.value,
+		p.min_quantity,
+		p.date_from,
+		p.date_to,
+		p.status
+		FROM i_price_list_items p
+		LEFT JOIN i_price_lists l
+		ON p.price_list_id = l.price_list_id
+		WHERE p.organization_id = ? AND p.price_list_id = ? AND l.status = 1 AND p.status > 0
+		AND (p.item_id = ? OR p.item_id = "")
+		AND p.min_quantity <= ?
+		AND (p.date_from = "" OR p.date_from <= ?)
+		AND (p.date_to = "" OR p.date_to >= ?)
+		ORDER BY p.item_id DESC, p.min_quantity DESC
+		LIMIT 1
+	`, organizationID, priceListID, itemID, quantity, date, date)
+	return &priceListItem, err
+}
+
+//Item group
+func (r *itemQuery) GetItemGroupCount(filter ItemGroupFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM i_item_groups
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *itemQuery) GetItemGroupList(filter ItemGroupFilter) (*[]ItemGroupResponse, error) {
+	where, args := []string{"g.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "g.organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "g.name like ?"), append(args, "%"+v+"%")
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var itemGroups []ItemGroupResponse
+	err := r.conn.Select(&itemGroups, `
+		SELECT 
+		g.item_group_id,
+		g.organization_id,
+		g.name,
+		g.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		g.manufacturer_id,
+		IFNULL(m.name, "") as manufacturer_name,
+		g.brand_id,
+		IFNULL(b.name, "") as brand_name,
+		g.sku_pattern,
+		g.selling_price,
+		g.cost_price,
+		g.track_location,
+		g.description,
+		(SELECT count(1) FROM i_items i WHERE i.item_group_id = g.item_group_id AND i.status > 0) as item_count,
+		g.status
+		FROM i_item_groups g
+		LEFT JOIN s_units u
+		ON g.unit_id = u.unit_id
+		LEFT JOIN s_manufacturers m
+		ON g.manufacturer_id = m.manufacturer_id
+		LEFT JOIN s_brands b
+		ON g.brand_id = b.brand_id
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &itemGroups, err
+}
+
+func (r *itemQuery) GetItemGroupByID(organizationID, itemGroupID string) (*ItemGroupResponse, error) {
+	var itemGroup ItemGroupResponse
+	err := r.conn.Get(&itemGroup, `
+		SELECT 
+		g.item_group_id,
+		g.organization_id,
+		g.name,
+		g.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		g.manufacturer_id,
+		IFNULL(m.name, "") as manufacturer_name,
+		g.brand_id,
+		IFNULL(b.name, "") as brand_name,
+		g.sku_pattern,
+		g.selling_price,
+		g.cost_price,
+		g.track_location,
+		g.description,
+		(SELECT count(1) FROM i_items i WHERE i.item_group_id = g.item_group_id AND i.status > 0) as item_count,
+		g.status
+		FROM i_item_groups g
+		LEFT JOIN s_units u
+		ON g.unit_id = u.unit_id
+		LEFT JOIN s_manufacturers m
+		ON g.manufacturer_id = m.manufacturer_id
+		LEFT JOIN s_brands b
+		ON g.brand_id = b.brand_id
+		WHERE g.organization_id = ? AND g.item_group_id = ? AND g.status > 0
+	`, organizationID, itemGroupID)
+	return &itemGroup, err
+}
+
+func (r *itemQuery) GetItemGroupAttributeList(organizationID, itemGroupID string) (*[]ItemGroupAttributeResponse, error) {
+	var attributes []ItemGroupAttributeResponse
+	err := r.conn.Select(&attributes, `
+		SELECT 
+		attribute_id,
+		item_group_id,
+		name,
+		position
+		FROM i_item_group_attributes
+		WHERE organization_id = ? AND item_group_id = ? AND status > 0
+		ORDER BY position ASC
+	`, organizationID, itemGroupID)
+	return &attributes, err
+}
+
+func (r *itemQuery) GetItemGroupAttributeOptionList(organizationID, itemGroupID string) (*[]ItemGroupAttributeOptionResponse, error) {
+	var options []ItemGroupAttributeOptionResponse
+	err := r.conn.Select(&options, `
+		SELECT 
+		o.option_id,
+		o.attribute_id,
+		o.value,
+		o.code,
+		o.position
+		FROM i_item_group_attribute_options o
+		INNER JOIN i_item_group_attributes a
+		ON o.attribute_id = a.attribute_id
+		WHERE o.organization_id = ? AND a.item_group_id = ? AND o.status > 0 AND a.status > 0
+		ORDER BY o.position ASC
+	`, organizationID, itemGroupID)
+	return &options, err
+}
+
+func (r *itemQuery) GetItemVariantList(organizationID, itemGroupID string) (*[]ItemVariantResponse, error) {
+	var variants []ItemVariantResponse
+	err := r.conn.Select(&variants, `
+		SELECT 
+		i.item_id,
+		i.sku,
+		i.name,
+		IFNULL(GROUP_CONCAT(CONCAT(a.name, ": ", o.value) ORDER BY a.position SEPARATOR ", "), "") as options,
+		i.selling_price,
+		i.cost_price,
+		i.stock_on_hand,
+		i.stock_available,
+		i.status
+		FROM i_items i
+		LEFT JOIN i_item_attributes ia
+		ON i.item_id = ia.item_id AND ia.status > 0
+		LEFT JOIN i_item_group_attributes a
+		ON ia.attribute_id = a.attribute_id
+		LEFT JOIN i_item_group_attribute_options o
+		ON ia.option_id = o.option_id
+		WHERE i.organization_id = ? AND i.item_group_id = ? AND i.status > 0
+		GROUP BY i.item_id
+		ORDER BY i.sku ASC
+	`, organizationID, itemGroupID)
+	return &variants, err
+}
+
+func (r *itemQuery) GetItemGroupStock(organizationID, itemGroupID string) (*ItemGroupStockResponse, error) {
+	var stock ItemGroupStockResponse
+	row := r.conn.QueryRow(`
+		SELECT 
+		count(1),
+		IFNULL(SUM(stock_on_hand), 0),
+		IFNULL(SUM(stock_available), 0),
+		IFNULL(SUM(stock_picking), 0),
+		IFNULL(SUM(stock_packing), 0)
+		FROM i_items
+		WHERE organization_id = ? AND item_group_id = ? AND status > 0
+	`, organizationID, itemGroupID)
+	err := row.Scan(&stock.ItemCount, &stock.StockOnHand, &stock.StockAvailable, &stock.StockPicking, &stock.StockPacking)
+	stock.ItemGroupID = itemGroupID
+	return &stock, err
+}
+
+func (r *itemQuery) GetItemGroupOptionStockList(organizationID, itemGroupID string) (*[]ItemGroupOptionStockResponse, error) {
+	var options []ItemGroupOptionStockResponse
+	err := r.conn.Select(&options, `
+		SELECT 
+		a.attribute_id,
+		a.name as attribute_name,
+		o.option_id,
+		o.value,
+		count(i.item_id) as item_count,
+		IFNULL(SUM(i.stock_on_hand), 0) as stock_on_hand,
+		IFNULL(SUM(i.stock_available), 0) as stock_available,
+		IFNULL(SUM(i.stock_picking), 0) as stock_picking,
+		IFNULL(SUM(i.stock_packing), 0) as stock_packing
+		FROM i_item_group_attributes a
+		INNER JOIN i_item_group_attribute_options o
+		ON a.attribute_id = o.attribute_id AND o.status > 0
+		LEFT JOIN i_item_attributes ia
+		ON o.option_id = ia.option_id AND ia.status > 0
+		LEFT JOIN i_items i
+		ON ia.item_id = i.item_id AND i.status > 0
+		WHERE a.organization_id = ? AND a.item_group_id = ? AND a.status > 0
+		GROUP BY a.attribute_id, a.name, a.position, o.option_id, o.value, o.position
+		ORDER BY a.position ASC, o.position ASC
+	`, organizationID, itemGroupID)
+	return &options, err
+}
+
+//Item unit
+func (r *itemQuery) GetItemUnitList(organizationID, itemID string) (*[]ItemUnitResponse, error) {
+	var itemUnits []ItemUnitResponse
+	err := r.conn.Select(&itemUnits, `
+		SELECT 
+		iu.item_unit_id,
+		iu.organization_id,
+		iu.item_id,
+		iu.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		iu.factor,
+		iu.status
+		FROM i_item_units iu
+		LEFT JOIN s_units u
+		ON iu.unit_id = u.unit_id
+		WHERE iu.organization_id = ? AND iu.item_id = ? AND iu.status > 0
+		ORDER BY iu.factor ASC
+	`, organizationID, itemID)
+	return &itemUnits, err
+}
+
+func (r *itemQuery) GetItemUnitFactor(organizationID, itemID, unitID string) (int, error) {
+	var factor int
+	err := r.conn.Get(&factor, `
+		SELECT factor
+		FROM i_item_units
+		WHERE organization_id = ? AND item_id = ? AND unit_id = ? AND status > 0
+		LIMIT 1
+	`, organizationID, itemID, unitID)
+	return factor, err
+}
+
+func (r *itemQuery) GetItemComponentList(organizationID, itemID string) (*[]ItemComponentResponse, error) {
+	var components []ItemComponentResponse
+	err := r.conn.Select(&components, `
+		SELECT
+		c.item_component_id,
+		c.organization_id,
+		c.item_id,
+		c.component_id,
+		i.sku,
+		i.name,
+		IFNULL(u.name, "") as unit_name,
+		c.quantity,
+		i.cost_price,
+		i.stock_available,
+		c.status
+		FROM i_item_components c
+		LEFT JOIN i_items i
+		ON c.component_id = i.item_id
+		LEFT JOIN s_units u
+		ON i.unit_id = u.unit_id
+		WHERE c.organization_id = ? AND c.item_id = ? AND c.status > 0
+		ORDER BY c.id ASC
+	`, organizationID, itemID)
+	return &components, err
+}
+
+// GetItemConsumption sums the picked quantities between from and to, valued at
+// the rate of the batch they were picked from. Sales kits are left out, their
+// components are picked.
+func (r *itemQuery) GetItemConsumption(organizationID, from, to string) (*[]ItemConsumptionResponse, error) {
+	var list []ItemConsumptionResponse
+	err := r.conn.Select(&list, `
+		SELECT
+		i.organization_id,
+		i.item_id,
+		IFNULL(SUM(l.quantity), 0) as quantity,
+		IFNULL(SUM(l.quantity * IFNULL(b.rate, 0)), 0) as value
+		FROM i_items i
+		LEFT JOIN s_pickingorder_logs l
+		ON l.item_id = i.item_id AND l.status > 0 AND l.created >= ? AND l.created < ?
+		LEFT JOIN i_item_batches b
+		ON l.batch_id = b.batch_id
+		WHERE i.organization_id = ? AND i.item_type <> 2 AND i.status > 0
+		GROUP BY i.organization_id, i.item_id
+	`, from, to, organizationID)
+	return &list, err
+}
+
+// GetWeeklyDemand returns the picked quantity per item and week, counted in
+// weeks from the from date, weeks without picking are not returned
+func (r *itemQuery) GetWeeklyDemand(organizationID, from, to string) (*[]WeeklyDemandResponse, error) {
+	var list []WeeklyDemandResponse
+	err := r.conn.Select(&list, `
+		SELECT
+		item_id,
+		FLOOR(DATEDIFF(created, ?) / 7) as week,
+		SUM(quantity) as quantity
+		FROM s_pickingorder_logs
+		WHERE organization_id = ? AND status > 0 AND created >= ? AND created < ?
+		GROUP BY item_id, week
+	`, from, organizationID, from, to)
+	return &list, err
+}
+
+// GetItemClassDueOrganizations returns the organizations with items and no
+// classification ending after the date given
+func (r *itemQuery) GetItemClassDueOrganizations(date string) (*[]string, error) {
+	var list []string
+	err := r.conn.Select(&list, `
+		SELECT DISTINCT i.organization_id
+		FROM i_items i
+		WHERE i.status > 0 AND NOT EXISTS (
+			SELECT 1 FROM i_item_classes c
+			WHERE c.organization_id = i.organization_id AND c.period_end > ? AND c.status > 0
+		)
+	`, date)
+	return &list, err
+}
+
+func (r *itemQuery) GetItemClassCount(filter ItemClassFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "item_id = ?"), append(args, v)
+	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "abc_class = ?"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "xyz_class = ?"), append(args, v)
+	}
+	if v := filter.PeriodEnd; v != "" {
+		where, args = append(where, "period_end = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM i_item_classes
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *itemQuery) GetItemClassList(filter ItemClassFilter) (*[]ItemClassResponse, error) {
+	where, args := []string{"c.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "c.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "c.item_id = ?"), append(args, v)
+	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "c.abc_class = ?"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "c.xyz_class = ?"), append(args, v)
+	}
+	if v := filter.PeriodEnd; v != "" {
+		where, args = append(where, "c.period_end = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var list []ItemClassResponse
+	err := r.conn.Select(&list, `
+		SELECT
+		c.organization_id,
+		c.item_class_id,
+		c.item_id,
+		i.sku,
+		i.name as item_name,
+		DATE_FORMAT(c.period_start, "%Y-%m-%d") as period_start,
+		DATE_FORMAT(c.period_end, "%Y-%m-%d") as period_end,
+		c.consumption_quantity,
+		c.consumption_value,
+		c.value_share,
+		c.abc_class,
+		c.demand_mean,
+		c.demand_cv,
+		c.xyz_class,
+		c.status
+		FROM i_item_classes c
+		LEFT JOIN i_items i
+		ON c.item_id = i.item_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY c.period_end DESC, c.consumption_value DESC
+		LIMIT ?, ?
+	`, args...)
+	return &list, err
+}
+
+// GetItemPriceList lists the price changes of an item effective between the
+// dates, both optional
+func (r *itemQuery) GetItemPriceList(organizationID, itemID, dateFrom, dateTo string) (*[]ItemPriceResponse, error) {
+	where, args := []string{"status > 0", "organization_id = ?", "item_id = ?"}, []interface{}{organizationID, itemID}
+	if dateFrom != "" {
+		where, args = append(where, "effective_date >= ?"), append(args, dateFrom)
+	}
+	if dateTo != "" {
+		where, args = append(where, "effective_date <= ?"), append(args, dateTo)
+	}
+	var list []ItemPriceResponse
+	err := r.conn.Select(&list, `
+		SELECT
+		item_price_id,
+		item_id,
+		DATE_FORMAT(effective_date, "%Y-%m-%d") as effective_date,
+		selling_price,
+		cost_price,
+		previous_selling_price,
+		previous_cost_price,
+		source,
+		created_by
+		FROM i_item_prices
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY effective_date ASC, id ASC
+	`, args...)
+	return &list, err
+}
+
+// GetItemPriceAt returns the last price change effective before the date
+func (r *itemQuery) GetItemPriceAt(organizationID, itemID, date string) (*ItemPriceResponse, error) {
+	var price ItemPriceResponse
+	err := r.conn.Get(&price, `
+		SELECT
+		item_price_id,
+		item_id,
+		DATE_FORMAT(effective_date, "%Y-%m-%d") as effective_date,
+		selling_price,
+		cost_price,
+		previous_selling_price,
+		previous_cost_price,
+		source,
+		created_by
+		FROM i_item_prices
+		WHERE organization_id = ? AND item_id = ? AND effective_date < ? AND status > 0
+		ORDER BY effective_date DESC, id DESC
+		LIMIT 1
+	`, organizationID, itemID, date)
+	return &price, err
+}
+
+// GetPurchaseRateList lists the base unit rates an item was ordered and billed
+// at, newest first, the dates being optional. Draft purchase orders are left
+// out as their rates were never sent to the vendor.
+func (r *itemQuery) GetPurchaseRateList(organizationID, itemID, dateFrom, dateTo string) (*[]PurchaseRateResponse, error) {
+	where, args := []string{"1 = 1"}, []interface{}{organizationID, itemID, organizationID, itemID}
+	if dateFrom != "" {
+		where, args = append(where, "x.document_date >= ?"), append(args, dateFrom)
+	}
+	if dateTo != "" {
+		where, args = append(where, "x.document_date <= ?"), append(args, dateTo)
+	}
+	var list []PurchaseRateResponse
+	err := r.conn.Select(&list, `
+		SELECT
+		x.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		x.source,
+		x.document_id,
+		x.document_number,
+		DATE_FORMAT(x.document_date, "%Y-%m-%d") as document_date,
+		x.quantity,
+		x.rate
+		FROM (
+			SELECT
+			p.vendor_id,
+			"purchaseorder" as source,
+			p.purchaseorder_id as document_id,
+			p.purchaseorder_number as document_number,
+			p.purchaseorder_date as document_date,
+			pi.quantity,
+			pi.rate,
+			pi.id as line_id
+			FROM p_purchaseorder_items pi
+			LEFT JOIN p_purchaseorders p
+			ON pi.purchaseorder_id = p.purchaseorder_id
+			WHERE pi.organization_id = ? AND pi.item_id = ? AND pi.status > 0 AND p.status IN (2, 3)
+			UNION ALL
+			SELECT
+			b.vendor_id,
+			"bill" as source,
+			b.bill_id as document_id,
+			b.bill_number as document_number,
+			b.bill_date as document_date,
+			bi.quantity,
+			bi.rate,
+			bi.id as line_id
+			FROM p_bill_items bi
+			LEFT JOIN p_bills b
+			ON bi.bill_id = b.bill_id
+			WHERE bi.organization_id = ? AND bi.item_id = ? AND bi.status > 0 AND b.status > 0
+		) x
+		LEFT JOIN s_vendors v
+		ON x.vendor_id = v.vendor_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY x.document_date DESC, x.source ASC, x.line_id DESC
+	`, args...)
+	return &list, err
+}
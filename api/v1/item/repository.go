@@ -48,8 +48,10 @@ func (r itemRepository) CreateItem(info Item) error {
 			stock_picking,
 			stock_packing,
 			default_vendor_id,
+			item_group_id,
 			description,
 			track_location,
+			item_type,
 			status,
 			created,
 			created_by,
@@ -57,8 +59,8 @@ func (r itemRepository) CreateItem(info Item) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.ItemID, info.SKU, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.WeightUnit, info.Weight, info.DimensionUnit, info.Length, info.Width, info.Height, info.SellingPrice, info.CostPrice, info.ReorderStock, info.StockOnHand, info.StockAvailable, info.StockPicking, info.StockPacking, info.DefaultVendorID, info.Description, info.TrackLocation, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemID, info.SKU, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.WeightUnit, info.Weight, info.DimensionUnit, info.Length, info.Width, info.Height, info.SellingPrice, info.CostPrice, info.ReorderStock, info.StockOnHand, info.StockAvailable, info.StockPicking, info.StockPacking, info.DefaultVendorID, info.ItemGroupID, info.Description, info.TrackLocation, info.ItemType, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -87,12 +89,14 @@ func (r *itemRepository) GetItemByID(itemID, organiztionID string) (*ItemRespons
 		stock_picking,
 		stock_packing,
 		default_vendor_id,
+		item_group_id,
 		description,
 		track_location,
+		item_type,
 		status
 		FROM i_items WHERE item_id = ? AND organization_id = ? AND status > 0 LIMIT 1
 	`, itemID, organiztionID)
-	err := row.Scan(&res.ItemID, &res.OrganizationID, &res.SKU, &res.Name, &res.UnitID, &res.ManufacturerID, &res.BrandID, &res.WeightUnit, &res.Weight, &res.DimensionUnit, &res.Length, &res.Width, &res.Height, &res.SellingPrice, &res.CostPrice, &res.ReorderStock, &res.StockOnHand, &res.StockAvailable, &res.StockPicking, &res.StockPacking, &res.DefaultVendorID, &res.Description, &res.TrackLocation, &res.Status)
+	err := row.Scan(&res.ItemID, &res.OrganizationID, &res.SKU, &res.Name, &res.UnitID, &res.ManufacturerID, &res.BrandID, &res.WeightUnit, &res.Weight, &res.DimensionUnit, &res.Length, &res.Width, &res.Height, &res.SellingPrice, &res.CostPrice, &res.ReorderStock, &res.StockOnHand, &res.StockAvailable, &res.StockPicking, &res.StockPacking, &res.DefaultVendorID, &res.ItemGroupID, &res.Description, &res.TrackLocation, &res.ItemType, &res.Status)
 	return &res, err
 }
 
@@ -118,11 +122,12 @@ func (r *itemRepository) UpdateItem(id string, info Item) error {
 		default_vendor_id = ?,
 		description = ?,
 		track_location = ?,
+		item_type = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE item_id = ?
-	`, info.SKU, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.WeightUnit, info.Weight, info.DimensionUnit, info.Length, info.Width, info.Height, info.SellingPrice, info.CostPrice, info.ReorderStock, info.StockOnHand, info.StockAvailable, info.DefaultVendorID, info.Description, info.TrackLocation, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.SKU, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.WeightUnit, info.Weight, info.DimensionUnit, info.Length, info.Width, info.Height, info.SellingPrice, info.CostPrice, info.ReorderStock, info.StockOnHand, info.StockAvailable, info.DefaultVendorID, info.Description, info.TrackLocation, info.ItemType, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -177,6 +182,7 @@ func (r itemRepository) CreateBarcode(info Barcode) error {
 			barcode_id,
 			code,
 			item_id,
+			unit_id,
 			quantity,
 			status,
 			created,
@@ -185,8 +191,8 @@ func (r itemRepository) CreateBarcode(info Barcode) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.BarcodeID, info.Code, info.ItemID, info.Quantity, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BarcodeID, info.Code, info.ItemID, info.UnitID, info.Quantity, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -200,17 +206,24 @@ func (r *itemRepository) GetBarcodeByID(barcodeID string) (*BarcodeResponse, err
 		i.name as item_name, 
 		b.code, 
 		i.sku, 
-		u.name as unit, 
+		b.unit_id,
+		IFNULL(bu.name, u.name) as unit, 
 		b.quantity,
+		u.name as base_unit,
+		b.quantity * IFNULL(iu.factor, 1) as base_quantity,
 		b.status
 		FROM i_barcodes b
 		LEFT JOIN i_items i
 		ON b.item_id = i.item_id
 		LEFT JOIN s_units u
 		ON i.unit_id = u.unit_id
+		LEFT JOIN s_units bu
+		ON b.unit_id = bu.unit_id
+		LEFT JOIN i_item_units iu
+		ON b.item_id = iu.item_id AND b.unit_id = iu.unit_id AND iu.status > 0
 		WHERE b.barcode_id = ? LIMIT 1
 	`, barcodeID)
-	err := row.Scan(&res.BarcodeID, &res.OrganizationID, &res.ItemID, &res.ItemName, &res.Code, &res.SKU, &res.Unit, &res.Quantity, &res.Status)
+	err := row.Scan(&res.BarcodeID, &res.OrganizationID, &res.ItemID, &res.ItemName, &res.Code, &res.SKU, &res.UnitID, &res.Unit, &res.Quantity, &res.BaseUnit, &res.BaseQuantity, &res.Status)
 	return &res, err
 }
 
@@ -219,12 +232,13 @@ func (r *itemRepository) UpdateBarcode(id string, info Barcode) error {
 		Update i_barcodes SET
 		code = ?,
 		item_id = ?,
+		unit_id = ?,
 		quantity = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE barcode_id = ?
-	`, info.Code, info.ItemID, info.Quantity, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.Code, info.ItemID, info.UnitID, info.Quantity, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -349,15 +363,16 @@ func (r *itemRepository) GetItemNextBatch(itemID, organiztionID string) (*ItemBa
 		b.reference_id,
 		b.location_id,
 		b.quantity,
+		b.rate,
 		b.balance,
 		b.status
 		FROM i_item_batches b
 		LEFT JOIN i_items i
 		ON b.item_id = i.item_id WHERE b.item_id = ?  AND b.organization_id = ? AND b.balance > 0 AND b.status > 0 
-		ORDER BY i.created asc
+		ORDER BY b.created asc, b.id asc
 		LIMIT 1
 	`, itemID, organiztionID)
-	err := row.Scan(&res.OrganizationID, &res.ItemID, &res.SKU, &res.ItemName, &res.BatchID, &res.Type, &res.ReferenceID, &res.LocationID, &res.Quantity, &res.Balance, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.ItemID, &res.SKU, &res.ItemName, &res.BatchID, &res.Type, &res.ReferenceID, &res.LocationID, &res.Quantity, &res.Rate, &res.Balance, &res.Status)
 	return &res, err
 }
 
@@ -445,3 +460,901 @@ func (r *itemRepository) DeleteItemBatch(id, byUser string) error {
 	`, time.Now(), byUser, id)
 	return err
 }
+
+//Price list
+
+func (r *itemRepository) CheckPriceListConfict(priceListID, organizationID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_price_lists WHERE organization_id = ? AND price_list_id != ? AND name = ? AND status > 0 ", organizationID, priceListID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r itemRepository) CreatePriceList(info PriceList) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_price_lists 
+		(
+			organization_id,
+			price_list_id,
+			name,
+			description,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.PriceListID, info.Name, info.Description, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) GetPriceListByID(priceListID, organizationID string) (*PriceListResponse, error) {
+	var res PriceListResponse
+	row := r.tx.QueryRow(`
+		SELECT 
+		price_list_id, 
+		organization_id,
+		name,
+		description,
+		status
+		FROM i_price_lists
+		WHERE price_list_id = ? AND organization_id = ? AND status > 0 LIMIT 1
+	`, priceListID, organizationID)
+	err := row.Scan(&res.PriceListID, &res.OrganizationID, &res.Name, &res.Description, &res.Status)
+	return &res, err
+}
+
+func (r *itemRepository) UpdatePriceList(id string, info PriceList) error {
+	_, err := r.tx.Exec(`
+		Update i_price_lists SET
+		name = ?,
+		description = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE price_list_id = ?
+	`, info.Name, info.Description, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *itemRepository) DeletePriceList(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_price_lists SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE price_list_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r itemRepository) CreatePriceListItem(info PriceListItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_price_list_items 
+		(
+			organization_id,
+			price_list_id,
+			price_list_item_id,
+			item_id,
+			price_type,
+			value,
+			min_quantity,
+			date_from,
+			date_to,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.PriceListID, info.PriceListItemID, info.ItemID, info.PriceType, info.Value, info.MinQuantity, info.DateFrom, info.DateTo, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) DeletePriceListItem(priceListID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_price_list_items SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE price_list_id = ? AND status > 0
+	`, time.Now(), byUser, priceListID)
+	return err
+}
+
+func (r *itemRepository) GetPriceListCustomerCount(priceListID, organizationID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_customers WHERE organization_id = ? AND price_list_id = ? AND status > 0", organizationID, priceListID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *itemRepository) GetItemBatchListByReferenceID(referenceID, organiztionID string) (*[]ItemBatchResponse, error) {
+	var batches []ItemBatchResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		b.organization_id,
+		b.item_id,
+		i.SKU,
+		i.name as item_name,
+		b.batch_id,
+		b.type,
+		b.reference_id,
+		b.location_id,
+		b.quantity,
+		b.rate,
+		b.balance,
+		b.status
+		FROM i_item_batches b
+		LEFT JOIN i_items i
+		ON b.item_id = i.item_id WHERE b.reference_id = ? AND b.organization_id = ? AND b.status > 0
+	`, referenceID, organiztionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res ItemBatchResponse
+		err = rows.Scan(&res.OrganizationID, &res.ItemID, &res.SKU, &res.ItemName, &res.BatchID, &res.Type, &res.ReferenceID, &res.LocationID, &res.Quantity, &res.Rate, &res.Balance, &res.Status)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, res)
+	}
+	return &batches, err
+}
+
+func (r *itemRepository) UpdateItemBatchRate(id string, rate float64, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_batches SET
+		rate = rate + ?,
+		updated = ?,
+		updated_by = ?
+		WHERE batch_id = ?
+	`, rate, time.Now(), byUser, id)
+	return err
+}
+
+// Item group
+
+func (r *itemRepository) CheckItemGroupConfict(itemGroupID, organizationID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_groups WHERE organization_id = ? AND item_group_id != ? AND name = ? AND status > 0", organizationID, itemGroupID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r itemRepository) CreateItemGroup(info ItemGroup) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_groups
+		(
+			organization_id,
+			item_group_id,
+			name,
+			unit_id,
+			manufacturer_id,
+			brand_id,
+			sku_pattern,
+			selling_price,
+			cost_price,
+			track_location,
+			description,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemGroupID, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.SKUPattern, info.SellingPrice, info.CostPrice, info.TrackLocation, info.Description, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) GetItemGroupByID(itemGroupID, organizationID string) (*ItemGroupResponse, error) {
+	var res ItemGroupResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		item_group_id,
+		organization_id,
+		name,
+		unit_id,
+		manufacturer_id,
+		brand_id,
+		sku_pattern,
+		selling_price,
+		cost_price,
+		track_location,
+		description,
+		status
+		FROM i_item_groups WHERE item_group_id = ? AND organization_id = ? AND status > 0 LIMIT 1
+	`, itemGroupID, organizationID)
+	err := row.Scan(&res.ItemGroupID, &res.OrganizationID, &res.Name, &res.UnitID, &res.ManufacturerID, &res.BrandID, &res.SKUPattern, &res.SellingPrice, &res.CostPrice, &res.TrackLocation, &res.Description, &res.Status)
+	return &res, err
+}
+
+func (r *itemRepository) UpdateItemGroup(id string, info ItemGroup) error {
+	_, err := r.tx.Exec(`
+		Update i_item_groups SET
+		name = ?,
+		unit_id = ?,
+		manufacturer_id = ?,
+		brand_id = ?,
+		sku_pattern = ?,
+		selling_price = ?,
+		cost_price = ?,
+		track_location = ?,
+		description = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE item_group_id = ?
+	`, info.Name, info.UnitID, info.ManufacturerID, info.BrandID, info.SKUPattern, info.SellingPrice, info.CostPrice, info.TrackLocation, info.Description, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *itemRepository) DeleteItemGroup(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_groups SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE item_group_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	_, err = r.tx.Exec(`
+		Update i_item_group_attributes SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE item_group_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *itemRepository) GetItemGroupItemCount(itemGroupID, organizationID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_items WHERE organization_id = ? AND item_group_id = ? AND status > 0", organizationID, itemGroupID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r itemRepository) CreateItemGroupAttribute(info ItemGroupAttribute) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_group_attributes
+		(
+			organization_id,
+			item_group_id,
+			attribute_id,
+			name,
+			position,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemGroupID, info.AttributeID, info.Name, info.Position, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) GetItemGroupAttributeByID(attributeID, organizationID string) (*ItemGroupAttributeResponse, error) {
+	var res ItemGroupAttributeResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		attribute_id,
+		item_group_id,
+		name,
+		position
+		FROM i_item_group_attributes WHERE attribute_id = ? AND organization_id = ? AND status > 0 LIMIT 1
+	`, attributeID, organizationID)
+	err := row.Scan(&res.AttributeID, &res.ItemGroupID, &res.Name, &res.Position)
+	return &res, err
+}
+
+func (r *itemRepository) GetItemGroupAttributeList(itemGroupID, organizationID string) (*[]ItemGroupAttributeResponse, error) {
+	var attributes []ItemGroupAttributeResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		attribute_id,
+		item_group_id,
+		name,
+		position
+		FROM i_item_group_attributes WHERE item_group_id = ? AND organization_id = ? AND status > 0
+		ORDER BY position ASC
+	`, itemGroupID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res ItemGroupAttributeResponse
+		err = rows.Scan(&res.AttributeID, &res.ItemGroupID, &res.Name, &res.Position)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, res)
+	}
+	return &attributes, err
+}
+
+func (r *itemRepository) CheckItemGroupAttributeConfict(attributeID, itemGroupID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_group_attributes WHERE item_group_id = ? AND attribute_id != ? AND name = ? AND status > 0", itemGroupID, attributeID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *itemRepository) GetItemGroupAttributeCount(itemGroupID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_group_attributes WHERE item_group_id = ? AND status > 0", itemGroupID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *itemRepository) UpdateItemGroupAttribute(id, name, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_group_attributes SET
+		name = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE attribute_id = ?
+	`, name, time.Now(), byUser, id)
+	return err
+}
+
+func (r *itemRepository) DeleteItemGroupAttribute(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_group_attributes SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE attribute_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	_, err = r.tx.Exec(`
+		Update i_item_group_attribute_options SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE attribute_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r itemRepository) CreateItemGroupAttributeOption(info ItemGroupAttributeOption) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_group_attribute_options
+		(
+			organization_id,
+			attribute_id,
+			option_id,
+			value,
+			code,
+			position,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.AttributeID, info.OptionID, info.Value, info.Code, info.Position, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) GetItemGroupAttributeOptionList(attributeID string) (*[]ItemGroupAttributeOptionResponse, error) {
+	var options []ItemGroupAttributeOptionResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		option_id,
+		attribute_id,
+		value,
+		code,
+		position
+		FROM i_item_group_attribute_options WHERE attribute_id = ? AND status > 0
+		ORDER BY position ASC
+	`, attributeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res ItemGroupAttributeOptionResponse
+		err = rows.Scan(&res.OptionID, &res.AttributeID, &res.Value, &res.Code, &res.Position)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, res)
+	}
+	return &options, err
+}
+
+func (r *itemRepository) UpdateItemGroupAttributeOption(id string, info ItemGroupAttributeOption) error {
+	_, err := r.tx.Exec(`
+		Update i_item_group_attribute_options SET
+		value = ?,
+		code = ?,
+		position = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE option_id = ?
+	`, info.Value, info.Code, info.Position, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *itemRepository) DeleteItemGroupAttributeOption(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_group_attribute_options SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE option_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+// GetOptionItemCount counts the active variants using an attribute option,
+// or any option of an attribute when optionID is empty
+func (r *itemRepository) GetOptionItemCount(attributeID, optionID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow(`
+		SELECT count(1) FROM i_item_attributes a
+		INNER JOIN i_items i
+		ON a.item_id = i.item_id
+		WHERE a.attribute_id = ? AND (? = '' OR a.option_id = ?) AND a.status > 0 AND i.status > 0
+	`, attributeID, optionID, optionID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r itemRepository) CreateItemAttribute(info ItemAttribute) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_attributes
+		(
+			organization_id,
+			item_attribute_id,
+			item_id,
+			attribute_id,
+			option_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemAttributeID, info.ItemID, info.AttributeID, info.OptionID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+// GetItemGroupVariantKeys returns the sorted option IDs of every active variant
+// in the group joined by comma, used to skip combinations already generated
+func (r *itemRepository) GetItemGroupVariantKeys(itemGroupID, organizationID string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	rows, err := r.tx.Query(`
+		SELECT GROUP_CONCAT(a.option_id ORDER BY a.option_id SEPARATOR ',')
+		FROM i_items i
+		INNER JOIN i_item_attributes a
+		ON i.item_id = a.item_id AND a.status > 0
+		WHERE i.organization_id = ? AND i.item_group_id = ? AND i.status > 0
+		GROUP BY i.item_id
+	`, organizationID, itemGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		err = rows.Scan(&key)
+		if err != nil {
+			return nil, err
+		}
+		keys[key] = true
+	}
+	return keys, err
+}
+
+// GetItemGroupVariantIDs returns the active variants of the group, only the
+// ones with the option when optionID is set
+func (r *itemRepository) GetItemGroupVariantIDs(itemGroupID, optionID, organizationID string) ([]string, error) {
+	var itemIDs []string
+	rows, err := r.tx.Query(`
+		SELECT i.item_id
+		FROM i_items i
+		WHERE i.organization_id = ? AND i.item_group_id = ? AND i.status > 0
+		AND (? = '' OR EXISTS (SELECT 1 FROM i_item_attributes a WHERE a.item_id = i.item_id AND a.option_id = ? AND a.status > 0))
+	`, organizationID, itemGroupID, optionID, optionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var itemID string
+		err = rows.Scan(&itemID)
+		if err != nil {
+			return nil, err
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	return itemIDs, err
+}
+
+func (r *itemRepository) UpdateItemPrice(id string, sellingPrice, costPrice float64, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_items SET
+		selling_price = ?,
+		cost_price = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE item_id = ?
+	`, sellingPrice, costPrice, time.Now(), byUser, id)
+	return err
+}
+
+func (r *itemRepository) GetItemGroupAttributeOptionByID(optionID, organizationID string) (*ItemGroupAttributeOptionResponse, error) {
+	var res ItemGroupAttributeOptionResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		option_id,
+		attribute_id,
+		value,
+		code,
+		position
+		FROM i_item_group_attribute_options WHERE option_id = ? AND organization_id = ? AND status > 0 LIMIT 1
+	`, optionID, organizationID)
+	err := row.Scan(&res.OptionID, &res.AttributeID, &res.Value, &res.Code, &res.Position)
+	return &res, err
+}
+
+func (r *itemRepository) UpdateItemGroupPrice(id string, sellingPrice, costPrice float64, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_groups SET
+		selling_price = ?,
+		cost_price = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE item_group_id = ?
+	`, sellingPrice, costPrice, time.Now(), byUser, id)
+	return err
+}
+
+//Item unit
+
+func (r *itemRepository) CheckItemUnitConfict(itemUnitID, itemID, unitID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_units WHERE item_id = ? AND item_unit_id != ? AND unit_id = ? AND status > 0", itemID, itemUnitID, unitID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r itemRepository) CreateItemUnit(info ItemUnit) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_units
+		(
+			organization_id,
+			item_unit_id,
+			item_id,
+			unit_id,
+			factor,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemUnitID, info.ItemID, info.UnitID, info.Factor, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) GetItemUnitByID(itemUnitID, organizationID string) (*ItemUnitResponse, error) {
+	var res ItemUnitResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		iu.item_unit_id,
+		iu.organization_id,
+		iu.item_id,
+		iu.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		iu.factor,
+		iu.status
+		FROM i_item_units iu
+		LEFT JOIN s_units u
+		ON iu.unit_id = u.unit_id
+		WHERE iu.item_unit_id = ? AND iu.organization_id = ? AND iu.status > 0 LIMIT 1
+	`, itemUnitID, organizationID)
+	err := row.Scan(&res.ItemUnitID, &res.OrganizationID, &res.ItemID, &res.UnitID, &res.UnitName, &res.Factor, &res.Status)
+	return &res, err
+}
+
+func (r *itemRepository) GetItemUnitFactor(itemID, unitID string) (int, error) {
+	var factor int
+	row := r.tx.QueryRow("SELECT factor FROM i_item_units WHERE item_id = ? AND unit_id = ? AND status > 0 LIMIT 1", itemID, unitID)
+	err := row.Scan(&factor)
+	return factor, err
+}
+
+func (r *itemRepository) UpdateItemUnit(id string, info ItemUnit) error {
+	_, err := r.tx.Exec(`
+		Update i_item_units SET
+		unit_id = ?,
+		factor = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE item_unit_id = ?
+	`, info.UnitID, info.Factor, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *itemRepository) DeleteItemUnit(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_units SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE item_unit_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *itemRepository) GetUnitBarcodeCount(itemID, unitID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_barcodes WHERE item_id = ? AND unit_id = ? AND status > 0", itemID, unitID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+//Item component
+
+func (r itemRepository) CreateItemComponent(info ItemComponent) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_components
+		(
+			organization_id,
+			item_component_id,
+			item_id,
+			component_id,
+			quantity,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemComponentID, info.ItemID, info.ComponentID, info.Quantity, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *itemRepository) DeleteItemComponents(itemID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update i_item_components SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE item_id = ? AND status > 0
+	`, time.Now(), byUser, itemID)
+	return err
+}
+
+func (r *itemRepository) GetItemComponentList(itemID, organizationID string) (*[]ItemComponentResponse, error) {
+	rows, err := r.tx.Query(`
+		SELECT
+		c.item_component_id,
+		c.organization_id,
+		c.item_id,
+		c.component_id,
+		i.sku,
+		i.name,
+		c.quantity,
+		i.cost_price,
+		i.stock_available,
+		c.status
+		FROM i_item_components c
+		LEFT JOIN i_items i
+		ON c.component_id = i.item_id
+		WHERE c.item_id = ? AND c.organization_id = ? AND c.status > 0
+		ORDER BY c.id ASC
+	`, itemID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []ItemComponentResponse
+	for rows.Next() {
+		var rowRes ItemComponentResponse
+		err = rows.Scan(&rowRes.ItemComponentID, &rowRes.OrganizationID, &rowRes.ItemID, &rowRes.ComponentID, &rowRes.SKU, &rowRes.Name, &rowRes.Quantity, &rowRes.CostPrice, &rowRes.StockAvailable, &rowRes.Status)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rowRes)
+	}
+	return &res, nil
+}
+
+func (r *itemRepository) GetItemComponentCount(itemID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_components WHERE item_id = ? AND status > 0", itemID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *itemRepository) GetComponentKitCount(componentID, organizationID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_item_components WHERE organization_id = ? AND component_id = ? AND status > 0", organizationID, componentID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+// GetItemStockLines returns the stock items behind quantity of an item, the
+// components of a sales kit or the item itself.
+func (r *itemRepository) GetItemStockLines(itemID, organizationID string, quantity int) (*[]ItemStockLine, error) {
+	itemInfo, err := r.GetItemByID(itemID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	var res []ItemStockLine
+	if itemInfo.ItemType != 2 {
+		var line ItemStockLine
+		line.ItemID = itemInfo.ItemID
+		line.SKU = itemInfo.SKU
+		line.Name = itemInfo.Name
+		line.TrackLocation = itemInfo.TrackLocation
+		line.StockAvailable = itemInfo.StockAvailable
+		line.StockPicking = itemInfo.StockPicking
+		line.StockPacking = itemInfo.StockPacking
+		line.Quantity = quantity
+		res = append(res, line)
+		return &res, nil
+	}
+	rows, err := r.tx.Query(`
+		SELECT
+		i.item_id,
+		i.sku,
+		i.name,
+		i.track_location,
+		i.stock_available,
+		i.stock_picking,
+		i.stock_packing,
+		c.quantity * ?
+		FROM i_item_components c
+		LEFT JOIN i_items i
+		ON c.component_id = i.item_id
+		WHERE c.item_id = ? AND c.organization_id = ? AND c.status > 0 AND i.status > 0
+		ORDER BY c.id ASC
+	`, quantity, itemID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line ItemStockLine
+		err = rows.Scan(&line.ItemID, &line.SKU, &line.Name, &line.TrackLocation, &line.StockAvailable, &line.StockPicking, &line.StockPacking, &line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, line)
+	}
+	if len(res) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &res, nil
+}
+
+func (r *itemRepository) CreateItemClass(info ItemClass) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_classes
+		(
+			organization_id,
+			item_class_id,
+			item_id,
+			period_start,
+			period_end,
+			consumption_quantity,
+			consumption_value,
+			value_share,
+			abc_class,
+			demand_mean,
+			demand_cv,
+			xyz_class,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemClassID, info.ItemID, info.PeriodStart, info.PeriodEnd, info.ConsumptionQuantity, info.ConsumptionValue, info.ValueShare, info.AbcClass, info.DemandMean, info.DemandCV, info.XyzClass, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+// DeleteItemClassByDate removes an earlier run of the same day so the history
+// keeps one class per item and period
+func (r *itemRepository) DeleteItemClassByDate(organizationID, periodEnd, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE i_item_classes SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND period_end = ? AND status > 0
+	`, time.Now(), byUser, organizationID, periodEnd)
+	return err
+}
+
+func (r *itemRepository) UpdateItemClass(itemID, abcClass, xyzClass, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE i_items SET
+		abc_class = ?,
+		xyz_class = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE item_id = ?
+	`, abcClass, xyzClass, time.Now(), byUser, itemID)
+	return err
+}
+
+func (r *itemRepository) CreateItemPrice(info ItemPrice) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_item_prices
+		(
+			organization_id,
+			item_price_id,
+			item_id,
+			effective_date,
+			selling_price,
+			cost_price,
+			previous_selling_price,
+			previous_cost_price,
+			source,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemPriceID, info.ItemID, info.EffectiveDate, info.SellingPrice, info.CostPrice, info.PreviousSellingPrice, info.PreviousCostPrice, info.Source, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+// GetLastItemPriceDate returns the effective date of the latest price change
+// of the item
+func (r *itemRepository) GetLastItemPriceDate(itemID string) (string, error) {
+	var res string
+	row := r.tx.QueryRow(`
+		SELECT DATE_FORMAT(IFNULL(MAX(effective_date), "1970-01-01"), "%Y-%m-%d")
+		FROM i_item_prices
+		WHERE item_id = ? AND status > 0
+	`, itemID)
+	err := row.Scan(&res)
+	return res, err
+}
@@ -8,6 +8,16 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/items/:id", UpdateItem)
 	g.GET("/items/:id", GetItemByID)
 	g.DELETE("/items/:id", DeleteItem)
+	g.GET("/items/:id/units", GetItemUnitList)
+	g.POST("/items/:id/units", NewItemUnit)
+	g.PUT("/itemunits/:id", UpdateItemUnit)
+	g.DELETE("/itemunits/:id", DeleteItemUnit)
+	g.GET("/items/:id/components", GetItemComponentList)
+	g.PUT("/items/:id/components", UpdateItemComponents)
+	g.GET("/items/:id/pricehistory", GetItemPriceHistory)
+	g.GET("/items/:id/vendorprices", GetVendorPriceList)
+	g.GET("/itemclasses", GetItemClassList)
+	g.POST("/itemclasses/run", RunItemClassification)
 
 	g.GET("/barcodes", GetBarcodeList)
 	// g.GET("/barcodes/:id", GetBarcodeByID)
@@ -15,4 +25,27 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/barcodes/:id", UpdateBarcode)
 	g.POST("/barcodes", NewBarcode)
 	g.DELETE("/barcodes/:id", DeleteBarcode)
+	g.GET("/scans", GetScan)
+
+	g.GET("/pricelists", GetPriceListList)
+	g.POST("/pricelists", NewPriceList)
+	g.GET("/pricelists/:id", GetPriceListByID)
+	g.PUT("/pricelists/:id", UpdatePriceList)
+	g.DELETE("/pricelists/:id", DeletePriceList)
+	g.GET("/pricelists/:id/items", GetPriceListItemList)
+	g.GET("/prices", GetPriceQuote)
+
+	g.POST("/itemgroups", NewItemGroup)
+	g.GET("/itemgroups", GetItemGroupList)
+	g.GET("/itemgroups/:id", GetItemGroupByID)
+	g.PUT("/itemgroups/:id", UpdateItemGroup)
+	g.DELETE("/itemgroups/:id", DeleteItemGroup)
+	g.GET("/itemgroups/:id/attributes", GetItemGroupAttributeList)
+	g.POST("/itemgroups/:id/attributes", NewItemGroupAttribute)
+	g.PUT("/itemgroupattributes/:id", UpdateItemGroupAttribute)
+	g.DELETE("/itemgroupattributes/:id", DeleteItemGroupAttribute)
+	g.POST("/itemgroups/:id/variants", GenerateItemVariants)
+	g.GET("/itemgroups/:id/items", GetItemVariantList)
+	g.GET("/itemgroups/:id/stock", GetItemGroupStock)
+	g.PUT("/itemgroups/:id/prices", UpdateItemGroupPrices)
 }
@@ -1,12 +1,19 @@
 package item
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"go-api/api/v1/common"
 	"go-api/api/v1/setting"
+	"go-api/core/barcode"
 	"go-api/core/database"
 	"go-api/core/queue"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/xid"
@@ -27,37 +34,67 @@ func (s *itemService) NewItem(info ItemNew) (*string, error) {
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	itemID, err := CreateItem(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	rabbit, _ := queue.GetConn()
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "item"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = info.User
+	newEvent.ReferenceID = itemID
+	newEvent.Description = "Item Created"
+	newEvent.OrganizationID = info.OrganizationID
+	newEvent.Email = info.Email
+	msg, _ := json.Marshal(newEvent)
+	err = rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &itemID, err
+}
+
+// CreateItem saves a new item and records its first price, the caller owns
+// the transaction so the bulk import can create many items in one
+func CreateItem(tx *sql.Tx, info ItemNew) (string, error) {
 	repo := NewItemRepository(tx)
+	settingRepo := setting.NewSettingRepository(tx)
 	isConflict, err := repo.CheckSKUConfict("", info.OrganizationID, info.SKU)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "item SKU exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	settingService := setting.NewSettingService()
-	_, err = settingService.GetUnitByID(info.OrganizationID, info.UnitID)
+	_, err = settingRepo.GetUnitByID(info.UnitID, info.OrganizationID)
 	if err != nil {
-		return nil, err
+		msg := "unit not exist"
+		return "", errors.New(msg)
 	}
 	if info.BrandID != "" {
-		_, err = settingService.GetBrandByID(info.OrganizationID, info.BrandID)
+		_, err = settingRepo.GetBrandByID(info.BrandID, info.OrganizationID)
 		if err != nil {
-			return nil, err
+			msg := "brand not exist"
+			return "", errors.New(msg)
 		}
 	}
 	if info.ManufacturerID != "" {
-		_, err = settingService.GetManufacturerByID(info.OrganizationID, info.ManufacturerID)
+		_, err = settingRepo.GetManufacturerByID(info.ManufacturerID, info.OrganizationID)
 		if err != nil {
-			return nil, err
+			msg := "manufacturer not exist"
+			return "", errors.New(msg)
 		}
 	}
 	if info.DefaultVendorID != "" {
-		_, err = settingService.GetVendorByID(info.OrganizationID, info.DefaultVendorID)
+		_, err = settingRepo.GetVendorByID(info.DefaultVendorID, info.OrganizationID)
 		if err != nil {
-			return nil, err
+			msg := "vendor not exist"
+			return "", errors.New(msg)
 		}
 	}
 	var item Item
@@ -84,6 +121,10 @@ func (s *itemService) NewItem(info ItemNew) (*string, error) {
 	item.DefaultVendorID = info.DefaultVendorID
 	item.Description = info.Description
 	item.TrackLocation = info.TrackLocation
+	item.ItemType = info.ItemType
+	if item.ItemType == 0 {
+		item.ItemType = 1
+	}
 	item.Status = info.Status
 	item.Created = time.Now()
 	item.CreatedBy = info.Email
@@ -93,25 +134,13 @@ func (s *itemService) NewItem(info ItemNew) (*string, error) {
 	err = repo.CreateItem(item)
 	if err != nil {
 		msg := "create itemerror: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	rabbit, _ := queue.GetConn()
-	var newEvent common.NewHistoryCreated
-	newEvent.HistoryType = "item"
-	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
-	newEvent.HistoryBy = info.User
-	newEvent.ReferenceID = item.ItemID
-	newEvent.Description = "Item Created"
-	newEvent.OrganizationID = info.OrganizationID
-	newEvent.Email = info.Email
-	msg, _ := json.Marshal(newEvent)
-	err = rabbit.Publish("NewHistoryCreated", msg)
+	err = recordItemPrice(repo, info.OrganizationID, item.ItemID, 0, 0, item.SellingPrice, item.CostPrice, info.PriceDate, "item", info.Email)
 	if err != nil {
-		msg := "create event NewHistoryCreated error"
-		return nil, errors.New(msg)
+		return "", err
 	}
-	tx.Commit()
-	return &item.ItemID, err
+	return item.ItemID, nil
 }
 
 func (s *itemService) GetItemList(filter ItemFilter) (int, *[]ItemResponse, error) {
@@ -173,6 +202,16 @@ func (s *itemService) UpdateItem(itemID string, info ItemNew) (*ItemResponse, er
 		msg := "Item not exist" + err.Error()
 		return nil, errors.New(msg)
 	}
+	itemType := info.ItemType
+	if itemType == 0 {
+		itemType = oldItem.ItemType
+	}
+	if itemType != oldItem.ItemType {
+		err = checkItemTypeChange(repo, oldItem, itemType)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var item Item
 	item.SKU = info.SKU
 	item.Name = info.Name
@@ -193,6 +232,7 @@ func (s *itemService) UpdateItem(itemID string, info ItemNew) (*ItemResponse, er
 	item.DefaultVendorID = info.DefaultVendorID
 	item.Description = info.Description
 	item.TrackLocation = info.TrackLocation
+	item.ItemType = itemType
 	item.Status = info.Status
 	item.Updated = time.Now()
 	item.UpdatedBy = info.User
@@ -200,6 +240,10 @@ func (s *itemService) UpdateItem(itemID string, info ItemNew) (*ItemResponse, er
 	if err != nil {
 		return nil, err
 	}
+	err = recordItemPrice(repo, info.OrganizationID, itemID, oldItem.SellingPrice, oldItem.CostPrice, item.SellingPrice, item.CostPrice, info.PriceDate, "item", info.User)
+	if err != nil {
+		return nil, err
+	}
 	res, err := repo.GetItemByID(itemID, info.OrganizationID)
 	if err != nil {
 		return nil, err
@@ -287,10 +331,23 @@ func (s *itemService) DeleteItem(itemID, organizationID, email, user string) err
 		msg := "Item in barcode can not be deleted"
 		return errors.New(msg)
 	}
+	kitCount, err := repo.GetComponentKitCount(itemID, organizationID)
+	if err != nil {
+		msg := "get kit count error"
+		return errors.New(msg)
+	}
+	if kitCount > 0 {
+		msg := "Item used as kit component can not be deleted"
+		return errors.New(msg)
+	}
 	err = repo.DeleteItem(itemID, email)
 	if err != nil {
 		return err
 	}
+	err = repo.DeleteItemComponents(itemID, email)
+	if err != nil {
+		return err
+	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "item"
 	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
@@ -334,26 +391,45 @@ func (s *itemService) NewBarcode(info BarcodeNew) (*string, error) {
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	barcodeID, err := CreateBarcode(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return &barcodeID, err
+}
+
+// CreateBarcode validates the code and unit of a barcode and saves it within tx
+func CreateBarcode(tx *sql.Tx, info BarcodeNew) (string, error) {
+	err := CheckBarcodeCode(info.Code)
+	if err != nil {
+		return "", err
+	}
 	repo := NewItemRepository(tx)
 	isConflict, err := repo.CheckBarcodeConfict("", info.OrganizationID, info.Code)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "barcode conflict"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	_, err = repo.GetItemByID(info.ItemID, info.OrganizationID)
+	itemInfo, err := repo.GetItemByID(info.ItemID, info.OrganizationID)
 	if err != nil {
 		msg := "Item not exist"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
+	}
+	unitID, err := CheckBarcodeUnit(repo, itemInfo, info.UnitID)
+	if err != nil {
+		return "", err
 	}
 	var barcode Barcode
 	barcode.BarcodeID = "bar-" + xid.New().String()
 	barcode.OrganizationID = info.OrganizationID
 	barcode.Code = info.Code
 	barcode.ItemID = info.ItemID
+	barcode.UnitID = unitID
 	barcode.Quantity = info.Quantity
 	barcode.Status = info.Status
 	barcode.Created = time.Now()
@@ -364,13 +440,16 @@ func (s *itemService) NewBarcode(info BarcodeNew) (*string, error) {
 	err = repo.CreateBarcode(barcode)
 	if err != nil {
 		msg := "create barcode error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	tx.Commit()
-	return &barcode.BarcodeID, err
+	return barcode.BarcodeID, nil
 }
 
 func (s *itemService) UpdateBarcode(barcodeID string, info BarcodeNew) (*BarcodeResponse, error) {
+	err := CheckBarcodeCode(info.Code)
+	if err != nil {
+		return nil, err
+	}
 	db := database.WDB()
 	tx, err := db.Begin()
 	if err != nil {
@@ -387,11 +466,15 @@ func (s *itemService) UpdateBarcode(barcodeID string, info BarcodeNew) (*Barcode
 		msg := "barcode conflict"
 		return nil, errors.New(msg)
 	}
-	_, err = repo.GetItemByID(info.ItemID, info.OrganizationID)
+	itemInfo, err := repo.GetItemByID(info.ItemID, info.OrganizationID)
 	if err != nil {
 		msg := "Item not exist"
 		return nil, errors.New(msg)
 	}
+	unitID, err := CheckBarcodeUnit(repo, itemInfo, info.UnitID)
+	if err != nil {
+		return nil, err
+	}
 	oldBarcode, err := repo.GetBarcodeByID(barcodeID)
 	if err != nil {
 		msg := "Barcode not exist"
@@ -404,6 +487,7 @@ func (s *itemService) UpdateBarcode(barcodeID string, info BarcodeNew) (*Barcode
 	var barcode Barcode
 	barcode.Code = info.Code
 	barcode.ItemID = info.ItemID
+	barcode.UnitID = unitID
 	barcode.Quantity = info.Quantity
 	barcode.Status = info.Status
 	barcode.Updated = time.Now()
@@ -420,6 +504,31 @@ func (s *itemService) UpdateBarcode(barcodeID string, info BarcodeNew) (*Barcode
 	return res, err
 }
 
+// CheckBarcodeUnit returns the unit stored on the barcode, empty for the item
+// base unit, and rejects units without a conversion on the item.
+func CheckBarcodeUnit(repo *itemRepository, itemInfo *ItemResponse, unitID string) (string, error) {
+	if unitID == "" || unitID == itemInfo.UnitID {
+		return "", nil
+	}
+	_, err := repo.GetItemUnitFactor(itemInfo.ItemID, unitID)
+	if err != nil {
+		msg := "unit not valid for item"
+		return "", errors.New(msg)
+	}
+	return unitID, nil
+}
+
+// CheckBarcodeCode rejects GTINs with a wrong check digit and codes that can
+// not be printed.
+func CheckBarcodeCode(code string) error {
+	err := barcode.Validate(code)
+	if err != nil {
+		msg := "barcode not valid: " + err.Error()
+		return errors.New(msg)
+	}
+	return nil
+}
+
 func (s *itemService) GetBarcodeByID(organizationID, id string) (*BarcodeResponse, error) {
 	db := database.RDB()
 	query := NewItemQuery(db)
@@ -466,3 +575,1684 @@ func (s *itemService) GetBarcodeByCode(organizationID, code string) (*BarcodeRes
 	}
 	return unit, nil
 }
+
+// ResolveScan finds the item of a scanned code. Codes registered as barcodes
+// match as they are, otherwise a GS1 or all numeric code is read as GS1 and
+// its GTIN looked up, the lot, expiry and count coming from the other
+// elements. A count replaces the quantity of the barcode unit.
+func (s *itemService) ResolveScan(filter ScanFilter) (*ScanResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	var res ScanResponse
+	res.Code = filter.Code
+	res.Elements = []barcode.GS1Element{}
+	found, err := query.GetBarcodeByCode(filter.OrganizationID, filter.Code)
+	if err != nil {
+		isGS1 := barcode.IsGS1(filter.Code)
+		if !isGS1 && strings.Trim(filter.Code, "0123456789") != "" {
+			msg := "barcode not exist"
+			return nil, errors.New(msg)
+		}
+		gs1, err := barcode.ParseGS1(filter.Code)
+		if err != nil {
+			if !isGS1 {
+				msg := "barcode not exist"
+				return nil, errors.New(msg)
+			}
+			msg := "parse gs1 barcode error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		if gs1.GTIN == "" {
+			msg := "gs1 barcode has no gtin"
+			return nil, errors.New(msg)
+		}
+		for _, code := range barcode.GTINForms(gs1.GTIN) {
+			found, err = query.GetBarcodeByCode(filter.OrganizationID, code)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			msg := "gtin " + gs1.GTIN + " not exist"
+			return nil, errors.New(msg)
+		}
+		res.GTIN = gs1.GTIN
+		res.Lot = gs1.Lot
+		res.Serial = gs1.Serial
+		res.Expiry = gs1.Expiry
+		res.Elements = gs1.Elements
+		if gs1.Count > 0 {
+			found.BaseQuantity = found.BaseQuantity / found.Quantity * gs1.Count
+			found.Quantity = gs1.Count
+		}
+	}
+	res.BarcodeID = found.BarcodeID
+	res.ItemID = found.ItemID
+	res.ItemName = found.ItemName
+	res.SKU = found.SKU
+	res.UnitID = found.UnitID
+	res.Unit = found.Unit
+	res.Quantity = found.Quantity
+	res.BaseUnit = found.BaseUnit
+	res.BaseQuantity = found.BaseQuantity
+	return &res, nil
+}
+
+//Price list
+
+func (s *itemService) GetPriceListList(filter PriceListFilter) (int, *[]PriceListResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	count, err := query.GetPriceListCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetPriceListList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *itemService) NewPriceList(info PriceListNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	isConflict, err := repo.CheckPriceListConfict("", info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "price list name conflict"
+		return nil, errors.New(msg)
+	}
+	var priceList PriceList
+	priceList.PriceListID = "pl-" + xid.New().String()
+	priceList.OrganizationID = info.OrganizationID
+	priceList.Name = info.Name
+	priceList.Description = info.Description
+	priceList.Status = info.Status
+	priceList.Created = time.Now()
+	priceList.CreatedBy = info.Email
+	priceList.Updated = time.Now()
+	priceList.UpdatedBy = info.Email
+	err = repo.CreatePriceList(priceList)
+	if err != nil {
+		msg := "create price list error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	err = createPriceListItems(repo, priceList.PriceListID, info)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return &priceList.PriceListID, err
+}
+
+func createPriceListItems(repo *itemRepository, priceListID string, info PriceListNew) error {
+	for _, rule := range info.Items {
+		if rule.PriceType == 1 && rule.ItemID == "" {
+			msg := "fixed price rule needs an item"
+			return errors.New(msg)
+		}
+		if rule.ItemID != "" {
+			_, err := repo.GetItemByID(rule.ItemID, info.OrganizationID)
+			if err != nil {
+				msg := "Item not exist"
+				return errors.New(msg)
+			}
+		}
+		if rule.PriceType == 2 && rule.Value > 100 {
+			msg := "price list discount value error"
+			return errors.New(msg)
+		}
+		if rule.DateFrom != "" && rule.DateTo != "" && rule.DateFrom > rule.DateTo {
+			msg := "price list date range error"
+			return errors.New(msg)
+		}
+		var priceListItem PriceListItem
+		priceListItem.PriceListItemID = "pli-" + xid.New().String()
+		priceListItem.PriceListID = priceListID
+		priceListItem.OrganizationID = info.OrganizationID
+		priceListItem.ItemID = rule.ItemID
+		priceListItem.PriceType = rule.PriceType
+		priceListItem.Value = rule.Value
+		priceListItem.MinQuantity = rule.MinQuantity
+		priceListItem.DateFrom = rule.DateFrom
+		priceListItem.DateTo = rule.DateTo
+		priceListItem.Status = 1
+		priceListItem.Created = time.Now()
+		priceListItem.CreatedBy = info.Email
+		priceListItem.Updated = time.Now()
+		priceListItem.UpdatedBy = info.Email
+		err := repo.CreatePriceListItem(priceListItem)
+		if err != nil {
+			msg := "create price list item error: " + err.Error()
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func (s *itemService) UpdatePriceList(priceListID string, info PriceListNew) (*PriceListResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	isConflict, err := repo.CheckPriceListConfict(priceListID, info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "price list name conflict"
+		return nil, errors.New(msg)
+	}
+	_, err = repo.GetPriceListByID(priceListID, info.OrganizationID)
+	if err != nil {
+		msg := "Price list not exist"
+		return nil, errors.New(msg)
+	}
+	var priceList PriceList
+	priceList.Name = info.Name
+	priceList.Description = info.Description
+	priceList.Status = info.Status
+	priceList.Updated = time.Now()
+	priceList.UpdatedBy = info.Email
+	err = repo.UpdatePriceList(priceListID, priceList)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.DeletePriceListItem(priceListID, info.Email)
+	if err != nil {
+		msg := "delete price list item error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	err = createPriceListItems(repo, priceListID, info)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetPriceListByID(priceListID, info.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, err
+}
+
+func (s *itemService) GetPriceListByID(organizationID, id string) (*PriceListResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	priceList, err := query.GetPriceListByID(organizationID, id)
+	if err != nil {
+		msg := "get price list error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return priceList, nil
+}
+
+func (s *itemService) GetPriceListItemList(organizationID, id string) (*[]PriceListItemResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetPriceListByID(organizationID, id)
+	if err != nil {
+		msg := "Price list not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetPriceListItemList(organizationID, id)
+	if err != nil {
+		msg := "get price list item error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
+
+func (s *itemService) DeletePriceList(priceListID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	_, err = repo.GetPriceListByID(priceListID, organizationID)
+	if err != nil {
+		msg := "Price list not exist"
+		return errors.New(msg)
+	}
+	customerCount, err := repo.GetPriceListCustomerCount(priceListID, organizationID)
+	if err != nil {
+		return err
+	}
+	if customerCount > 0 {
+		msg := "price list is assigned to customers"
+		return errors.New(msg)
+	}
+	err = repo.DeletePriceList(priceListID, user)
+	if err != nil {
+		return err
+	}
+	err = repo.DeletePriceListItem(priceListID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *itemService) GetPriceQuote(filter PriceQuoteFilter) (*PriceQuoteResponse, error) {
+	settingService := setting.NewSettingService()
+	customer, err := settingService.GetCustomerByID(filter.OrganizationID, filter.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	date := filter.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	quote, err := s.GetItemPrice(filter.OrganizationID, customer.PriceListID, filter.ItemID, filter.Quantity, date)
+	if err != nil {
+		return nil, err
+	}
+	quote.CustomerID = filter.CustomerID
+	return quote, nil
+}
+
+// GetItemPrice resolves the unit rate of an item from a price list, falling back
+// to the item selling price when no list is given or no rule applies.
+func (s *itemService) GetItemPrice(organizationID, priceListID, itemID string, quantity int, date string) (*PriceQuoteResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	item, err := query.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	var res PriceQuoteResponse
+	res.ItemID = itemID
+	res.Quantity = quantity
+	res.SellingPrice = item.SellingPrice
+	res.Rate = item.SellingPrice
+	if priceListID != "" {
+		rule, err := query.GetPriceListRule(organizationID, priceListID, itemID, quantity, date)
+		if err != nil && err != sql.ErrNoRows {
+			msg := "get price list rule error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		if err == nil {
+			res.PriceListID = priceListID
+			res.PriceListItemID = rule.PriceListItemID
+			if rule.PriceType == 1 {
+				res.Rate = rule.Value
+			} else {
+				res.Rate = item.SellingPrice * (1 - rule.Value/100)
+			}
+		}
+	}
+	res.Amount = res.Rate * float64(quantity)
+	return &res, nil
+}
+
+//Item group
+
+func checkItemGroupReference(info ItemGroupNew) error {
+	settingService := setting.NewSettingService()
+	_, err := settingService.GetUnitByID(info.OrganizationID, info.UnitID)
+	if err != nil {
+		return err
+	}
+	if info.BrandID != "" {
+		_, err = settingService.GetBrandByID(info.OrganizationID, info.BrandID)
+		if err != nil {
+			return err
+		}
+	}
+	if info.ManufacturerID != "" {
+		_, err = settingService.GetManufacturerByID(info.OrganizationID, info.ManufacturerID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *itemService) NewItemGroup(info ItemGroupNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	isConflict, err := repo.CheckItemGroupConfict("", info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "item group name conflict"
+		return nil, errors.New(msg)
+	}
+	err = checkItemGroupReference(info)
+	if err != nil {
+		return nil, err
+	}
+	var itemGroup ItemGroup
+	itemGroup.ItemGroupID = "ig-" + xid.New().String()
+	itemGroup.OrganizationID = info.OrganizationID
+	itemGroup.Name = info.Name
+	itemGroup.UnitID = info.UnitID
+	itemGroup.ManufacturerID = info.ManufacturerID
+	itemGroup.BrandID = info.BrandID
+	itemGroup.SKUPattern = info.SKUPattern
+	itemGroup.SellingPrice = info.SellingPrice
+	itemGroup.CostPrice = info.CostPrice
+	itemGroup.TrackLocation = info.TrackLocation
+	itemGroup.Description = info.Description
+	itemGroup.Status = info.Status
+	itemGroup.Created = time.Now()
+	itemGroup.CreatedBy = info.Email
+	itemGroup.Updated = time.Now()
+	itemGroup.UpdatedBy = info.Email
+	err = repo.CreateItemGroup(itemGroup)
+	if err != nil {
+		msg := "create item group error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	for i, attribute := range info.Attributes {
+		attribute.OrganizationID = info.OrganizationID
+		attribute.Email = info.Email
+		_, err = createItemGroupAttribute(repo, itemGroup.ItemGroupID, i+1, attribute)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tx.Commit()
+	return &itemGroup.ItemGroupID, err
+}
+
+func createItemGroupAttribute(repo *itemRepository, itemGroupID string, position int, info ItemGroupAttributeNew) (string, error) {
+	isConflict, err := repo.CheckItemGroupAttributeConfict("", itemGroupID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return "", errors.New(msg)
+	}
+	if isConflict {
+		msg := "item group attribute name conflict"
+		return "", errors.New(msg)
+	}
+	var attribute ItemGroupAttribute
+	attribute.AttributeID = "iga-" + xid.New().String()
+	attribute.OrganizationID = info.OrganizationID
+	attribute.ItemGroupID = itemGroupID
+	attribute.Name = info.Name
+	attribute.Position = position
+	attribute.Status = 1
+	attribute.Created = time.Now()
+	attribute.CreatedBy = info.Email
+	attribute.Updated = time.Now()
+	attribute.UpdatedBy = info.Email
+	err = repo.CreateItemGroupAttribute(attribute)
+	if err != nil {
+		msg := "create item group attribute error: " + err.Error()
+		return "", errors.New(msg)
+	}
+	values := make(map[string]bool)
+	for i, option := range info.Options {
+		if values[option.Value] {
+			msg := "duplicate attribute option: " + option.Value
+			return "", errors.New(msg)
+		}
+		values[option.Value] = true
+		err = createItemGroupAttributeOption(repo, attribute.AttributeID, i+1, option, info)
+		if err != nil {
+			return "", err
+		}
+	}
+	return attribute.AttributeID, nil
+}
+
+func createItemGroupAttributeOption(repo *itemRepository, attributeID string, position int, option ItemGroupAttributeOptionNew, info ItemGroupAttributeNew) error {
+	var attributeOption ItemGroupAttributeOption
+	attributeOption.OptionID = "igo-" + xid.New().String()
+	attributeOption.OrganizationID = info.OrganizationID
+	attributeOption.AttributeID = attributeID
+	attributeOption.Value = option.Value
+	attributeOption.Code = option.Code
+	attributeOption.Position = position
+	attributeOption.Status = 1
+	attributeOption.Created = time.Now()
+	attributeOption.CreatedBy = info.Email
+	attributeOption.Updated = time.Now()
+	attributeOption.UpdatedBy = info.Email
+	err := repo.CreateItemGroupAttributeOption(attributeOption)
+	if err != nil {
+		msg := "create item group attribute option error: " + err.Error()
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *itemService) GetItemGroupList(filter ItemGroupFilter) (int, *[]ItemGroupResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	count, err := query.GetItemGroupCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetItemGroupList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *itemService) GetItemGroupByID(organizationID, id string) (*ItemGroupResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	itemGroup, err := query.GetItemGroupByID(organizationID, id)
+	if err != nil {
+		msg := "get item group error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return itemGroup, nil
+}
+
+// UpdateItemGroup updates the group defaults only, attributes are maintained
+// separately and existing variants keep their own values.
+func (s *itemService) UpdateItemGroup(itemGroupID string, info ItemGroupNew) (*ItemGroupResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	isConflict, err := repo.CheckItemGroupConfict(itemGroupID, info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "item group name conflict"
+		return nil, errors.New(msg)
+	}
+	_, err = repo.GetItemGroupByID(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	err = checkItemGroupReference(info)
+	if err != nil {
+		return nil, err
+	}
+	var itemGroup ItemGroup
+	itemGroup.Name = info.Name
+	itemGroup.UnitID = info.UnitID
+	itemGroup.ManufacturerID = info.ManufacturerID
+	itemGroup.BrandID = info.BrandID
+	itemGroup.SKUPattern = info.SKUPattern
+	itemGroup.SellingPrice = info.SellingPrice
+	itemGroup.CostPrice = info.CostPrice
+	itemGroup.TrackLocation = info.TrackLocation
+	itemGroup.Description = info.Description
+	itemGroup.Status = info.Status
+	itemGroup.Updated = time.Now()
+	itemGroup.UpdatedBy = info.Email
+	err = repo.UpdateItemGroup(itemGroupID, itemGroup)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetItemGroupByID(itemGroupID, info.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, err
+}
+
+func (s *itemService) DeleteItemGroup(itemGroupID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	_, err = repo.GetItemGroupByID(itemGroupID, organizationID)
+	if err != nil {
+		msg := "Item group not exist"
+		return errors.New(msg)
+	}
+	itemCount, err := repo.GetItemGroupItemCount(itemGroupID, organizationID)
+	if err != nil {
+		msg := "get item group item count error"
+		return errors.New(msg)
+	}
+	if itemCount > 0 {
+		msg := "Item group with existing items can not be deleted"
+		return errors.New(msg)
+	}
+	err = repo.DeleteItemGroup(itemGroupID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *itemService) GetItemGroupAttributeList(organizationID, id string) (*[]ItemGroupAttributeResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemGroupByID(organizationID, id)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	attributes, err := query.GetItemGroupAttributeList(organizationID, id)
+	if err != nil {
+		msg := "get item group attribute error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	options, err := query.GetItemGroupAttributeOptionList(organizationID, id)
+	if err != nil {
+		msg := "get item group attribute option error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	for i := range *attributes {
+		(*attributes)[i].Options = []ItemGroupAttributeOptionResponse{}
+		for _, option := range *options {
+			if option.AttributeID == (*attributes)[i].AttributeID {
+				(*attributes)[i].Options = append((*attributes)[i].Options, option)
+			}
+		}
+	}
+	return attributes, nil
+}
+
+func (s *itemService) NewItemGroupAttribute(itemGroupID string, info ItemGroupAttributeNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	_, err = repo.GetItemGroupByID(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	attributeCount, err := repo.GetItemGroupAttributeCount(itemGroupID)
+	if err != nil {
+		msg := "get item group attribute count error"
+		return nil, errors.New(msg)
+	}
+	attributeID, err := createItemGroupAttribute(repo, itemGroupID, attributeCount+1, info)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return &attributeID, err
+}
+
+// UpdateItemGroupAttribute renames the attribute and syncs its options: options
+// with an ID are updated, new ones are created and missing ones are removed
+// when no variant uses them.
+func (s *itemService) UpdateItemGroupAttribute(attributeID string, info ItemGroupAttributeNew) (*ItemGroupAttributeResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	oldAttribute, err := repo.GetItemGroupAttributeByID(attributeID, info.OrganizationID)
+	if err != nil {
+		msg := "Item group attribute not exist"
+		return nil, errors.New(msg)
+	}
+	isConflict, err := repo.CheckItemGroupAttributeConfict(attributeID, oldAttribute.ItemGroupID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "item group attribute name conflict"
+		return nil, errors.New(msg)
+	}
+	err = repo.UpdateItemGroupAttribute(attributeID, info.Name, info.Email)
+	if err != nil {
+		return nil, err
+	}
+	oldOptions, err := repo.GetItemGroupAttributeOptionList(attributeID)
+	if err != nil {
+		msg := "get item group attribute option error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	kept := make(map[string]bool)
+	values := make(map[string]bool)
+	for i, option := range info.Options {
+		if values[option.Value] {
+			msg := "duplicate attribute option: " + option.Value
+			return nil, errors.New(msg)
+		}
+		values[option.Value] = true
+		if option.OptionID == "" {
+			err = createItemGroupAttributeOption(repo, attributeID, i+1, option, info)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		existed := false
+		for _, oldOption := range *oldOptions {
+			if oldOption.OptionID == option.OptionID {
+				existed = true
+				break
+			}
+		}
+		if !existed {
+			msg := "Item group attribute option not exist"
+			return nil, errors.New(msg)
+		}
+		kept[option.OptionID] = true
+		var attributeOption ItemGroupAttributeOption
+		attributeOption.Value = option.Value
+		attributeOption.Code = option.Code
+		attributeOption.Position = i + 1
+		attributeOption.Updated = time.Now()
+		attributeOption.UpdatedBy = info.Email
+		err = repo.UpdateItemGroupAttributeOption(option.OptionID, attributeOption)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, oldOption := range *oldOptions {
+		if kept[oldOption.OptionID] {
+			continue
+		}
+		itemCount, err := repo.GetOptionItemCount(attributeID, oldOption.OptionID)
+		if err != nil {
+			msg := "get option item count error"
+			return nil, errors.New(msg)
+		}
+		if itemCount > 0 {
+			msg := "Attribute option used by items can not be deleted: " + oldOption.Value
+			return nil, errors.New(msg)
+		}
+		err = repo.DeleteItemGroupAttributeOption(oldOption.OptionID, info.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	res, err := repo.GetItemGroupAttributeByID(attributeID, info.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := repo.GetItemGroupAttributeOptionList(attributeID)
+	if err != nil {
+		return nil, err
+	}
+	res.Options = *options
+	tx.Commit()
+	return res, err
+}
+
+func (s *itemService) DeleteItemGroupAttribute(attributeID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	_, err = repo.GetItemGroupAttributeByID(attributeID, organizationID)
+	if err != nil {
+		msg := "Item group attribute not exist"
+		return errors.New(msg)
+	}
+	itemCount, err := repo.GetOptionItemCount(attributeID, "")
+	if err != nil {
+		msg := "get option item count error"
+		return errors.New(msg)
+	}
+	if itemCount > 0 {
+		msg := "Attribute used by items can not be deleted"
+		return errors.New(msg)
+	}
+	err = repo.DeleteItemGroupAttribute(attributeID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// variantSKU fills the SKU pattern: {attribute name} is replaced by the option
+// code (or value when the code is empty), {n} by the variant sequence.
+func variantSKU(pattern string, attributes []ItemGroupAttributeResponse, options []ItemGroupAttributeOptionResponse, sequence int) string {
+	sku := pattern
+	for i, attribute := range attributes {
+		code := options[i].Code
+		if code == "" {
+			code = options[i].Value
+		}
+		token := regexp.MustCompile("(?i)" + regexp.QuoteMeta("{"+attribute.Name+"}"))
+		sku = token.ReplaceAllLiteralString(sku, code)
+	}
+	return strings.ReplaceAll(sku, "{n}", strconv.Itoa(sequence))
+}
+
+// GenerateItemVariants creates an item for every option combination not yet in
+// the group. When option IDs are given, attributes with a selected option only
+// use the selected ones.
+func (s *itemService) GenerateItemVariants(itemGroupID string, info ItemVariantNew) (*[]string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	itemGroup, err := repo.GetItemGroupByID(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	attributes, err := repo.GetItemGroupAttributeList(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "get item group attribute error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if len(*attributes) == 0 {
+		msg := "item group has no attribute"
+		return nil, errors.New(msg)
+	}
+	selected := make(map[string]bool)
+	for _, optionID := range info.OptionID {
+		selected[optionID] = true
+	}
+	var optionSets [][]ItemGroupAttributeOptionResponse
+	for _, attribute := range *attributes {
+		options, err := repo.GetItemGroupAttributeOptionList(attribute.AttributeID)
+		if err != nil {
+			msg := "get item group attribute option error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		var chosen []ItemGroupAttributeOptionResponse
+		for _, option := range *options {
+			if selected[option.OptionID] {
+				chosen = append(chosen, option)
+			}
+		}
+		if len(chosen) == 0 {
+			chosen = *options
+		}
+		if len(chosen) == 0 {
+			msg := "item group attribute has no option: " + attribute.Name
+			return nil, errors.New(msg)
+		}
+		optionSets = append(optionSets, chosen)
+	}
+	combinations := [][]ItemGroupAttributeOptionResponse{{}}
+	for _, options := range optionSets {
+		var next [][]ItemGroupAttributeOptionResponse
+		for _, combination := range combinations {
+			for _, option := range options {
+				extended := make([]ItemGroupAttributeOptionResponse, len(combination), len(combination)+1)
+				copy(extended, combination)
+				next = append(next, append(extended, option))
+			}
+		}
+		combinations = next
+	}
+	existed, err := repo.GetItemGroupVariantKeys(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "get item group variant error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	itemCount, err := repo.GetItemGroupItemCount(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "get item group item count error"
+		return nil, errors.New(msg)
+	}
+	pattern := itemGroup.SKUPattern
+	if info.SKUPattern != "" {
+		pattern = info.SKUPattern
+	}
+	rabbit, _ := queue.GetConn()
+	var itemIDs []string
+	for _, combination := range combinations {
+		var optionIDs, values []string
+		for _, option := range combination {
+			optionIDs = append(optionIDs, option.OptionID)
+			values = append(values, option.Value)
+		}
+		sort.Strings(optionIDs)
+		if existed[strings.Join(optionIDs, ",")] {
+			continue
+		}
+		itemCount++
+		sku := variantSKU(pattern, *attributes, combination, itemCount)
+		isConflict, err := repo.CheckSKUConfict("", info.OrganizationID, sku)
+		if err != nil {
+			msg := "check conflict error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		if isConflict {
+			msg := "item SKU exists: " + sku
+			return nil, errors.New(msg)
+		}
+		var item Item
+		item.ItemID = "item-" + xid.New().String()
+		item.OrganizationID = info.OrganizationID
+		item.SKU = sku
+		item.Name = itemGroup.Name + " - " + strings.Join(values, " / ")
+		item.UnitID = itemGroup.UnitID
+		item.ManufacturerID = itemGroup.ManufacturerID
+		item.BrandID = itemGroup.BrandID
+		item.SellingPrice = itemGroup.SellingPrice
+		item.CostPrice = itemGroup.CostPrice
+		item.ItemGroupID = itemGroupID
+		item.Description = itemGroup.Description
+		item.TrackLocation = itemGroup.TrackLocation
+		item.ItemType = 1
+		item.Status = 1
+		item.Created = time.Now()
+		item.CreatedBy = info.Email
+		item.Updated = time.Now()
+		item.UpdatedBy = info.Email
+		err = repo.CreateItem(item)
+		if err != nil {
+			msg := "create item error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		err = recordItemPrice(repo, info.OrganizationID, item.ItemID, 0, 0, item.SellingPrice, item.CostPrice, "", "itemgroup", info.Email)
+		if err != nil {
+			return nil, err
+		}
+		for i, option := range combination {
+			var itemAttribute ItemAttribute
+			itemAttribute.ItemAttributeID = "ia-" + xid.New().String()
+			itemAttribute.OrganizationID = info.OrganizationID
+			itemAttribute.ItemID = item.ItemID
+			itemAttribute.AttributeID = (*attributes)[i].AttributeID
+			itemAttribute.OptionID = option.OptionID
+			itemAttribute.Status = 1
+			itemAttribute.Created = time.Now()
+			itemAttribute.CreatedBy = info.Email
+			itemAttribute.Updated = time.Now()
+			itemAttribute.UpdatedBy = info.Email
+			err = repo.CreateItemAttribute(itemAttribute)
+			if err != nil {
+				msg := "create item attribute error: " + err.Error()
+				return nil, errors.New(msg)
+			}
+		}
+		var newEvent common.NewHistoryCreated
+		newEvent.HistoryType = "item"
+		newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+		newEvent.HistoryBy = info.User
+		newEvent.ReferenceID = item.ItemID
+		newEvent.Description = "Item Created"
+		newEvent.OrganizationID = info.OrganizationID
+		newEvent.Email = info.Email
+		msg, _ := json.Marshal(newEvent)
+		err = rabbit.Publish("NewHistoryCreated", msg)
+		if err != nil {
+			msg := "create event NewHistoryCreated error"
+			return nil, errors.New(msg)
+		}
+		itemIDs = append(itemIDs, item.ItemID)
+	}
+	if len(itemIDs) == 0 {
+		msg := "no new variant to generate"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &itemIDs, nil
+}
+
+func (s *itemService) GetItemVariantList(organizationID, id string) (*[]ItemVariantResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemGroupByID(organizationID, id)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetItemVariantList(organizationID, id)
+	if err != nil {
+		msg := "get item variant error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
+
+func (s *itemService) GetItemGroupStock(organizationID, id string) (*ItemGroupStockResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemGroupByID(organizationID, id)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	stock, err := query.GetItemGroupStock(organizationID, id)
+	if err != nil {
+		msg := "get item group stock error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	options, err := query.GetItemGroupOptionStockList(organizationID, id)
+	if err != nil {
+		msg := "get item group option stock error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	stock.Options = *options
+	return stock, nil
+}
+
+// UpdateItemGroupPrices sets the prices of every variant with the option, or of
+// the whole group and its defaults when no option is given. A zero price is
+// left unchanged.
+func (s *itemService) UpdateItemGroupPrices(itemGroupID string, info ItemGroupPriceNew) (*[]string, error) {
+	if info.SellingPrice == 0 && info.CostPrice == 0 {
+		msg := "selling price or cost price required"
+		return nil, errors.New(msg)
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	itemGroup, err := repo.GetItemGroupByID(itemGroupID, info.OrganizationID)
+	if err != nil {
+		msg := "Item group not exist"
+		return nil, errors.New(msg)
+	}
+	if info.OptionID != "" {
+		option, err := repo.GetItemGroupAttributeOptionByID(info.OptionID, info.OrganizationID)
+		if err != nil {
+			msg := "Item group attribute option not exist"
+			return nil, errors.New(msg)
+		}
+		attribute, err := repo.GetItemGroupAttributeByID(option.AttributeID, info.OrganizationID)
+		if err != nil || attribute.ItemGroupID != itemGroupID {
+			msg := "Item group attribute option not exist"
+			return nil, errors.New(msg)
+		}
+	} else {
+		sellingPrice, costPrice := itemGroup.SellingPrice, itemGroup.CostPrice
+		if info.SellingPrice > 0 {
+			sellingPrice = info.SellingPrice
+		}
+		if info.CostPrice > 0 {
+			costPrice = info.CostPrice
+		}
+		err = repo.UpdateItemGroupPrice(itemGroupID, sellingPrice, costPrice, info.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	itemIDs, err := repo.GetItemGroupVariantIDs(itemGroupID, info.OptionID, info.OrganizationID)
+	if err != nil {
+		msg := "get item group variant error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	rabbit, _ := queue.GetConn()
+	for _, itemID := range itemIDs {
+		item, err := repo.GetItemByID(itemID, info.OrganizationID)
+		if err != nil {
+			msg := "Item not exist"
+			return nil, errors.New(msg)
+		}
+		sellingPrice, costPrice := item.SellingPrice, item.CostPrice
+		if info.SellingPrice > 0 {
+			sellingPrice = info.SellingPrice
+		}
+		if info.CostPrice > 0 {
+			costPrice = info.CostPrice
+		}
+		err = repo.UpdateItemPrice(itemID, sellingPrice, costPrice, info.Email)
+		if err != nil {
+			msg := "update item price error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		err = recordItemPrice(repo, info.OrganizationID, itemID, item.SellingPrice, item.CostPrice, sellingPrice, costPrice, "", "itemgroup", info.Email)
+		if err != nil {
+			return nil, err
+		}
+		var newEvent common.NewHistoryCreated
+		newEvent.HistoryType = "item"
+		newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+		newEvent.HistoryBy = info.User
+		newEvent.ReferenceID = itemID
+		newEvent.Description = "Item Price Updated"
+		newEvent.OrganizationID = info.OrganizationID
+		newEvent.Email = info.Email
+		msg, _ := json.Marshal(newEvent)
+		err = rabbit.Publish("NewHistoryCreated", msg)
+		if err != nil {
+			msg := "create event NewHistoryCreated error"
+			return nil, errors.New(msg)
+		}
+	}
+	tx.Commit()
+	return &itemIDs, nil
+}
+
+//Item unit
+
+func (s *itemService) GetItemUnitList(organizationID, itemID string) (*[]ItemUnitResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetItemUnitList(organizationID, itemID)
+	if err != nil {
+		msg := "get item unit error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
+
+func (s *itemService) NewItemUnit(itemID string, info ItemUnitNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	itemInfo, err := repo.GetItemByID(itemID, info.OrganizationID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	err = checkItemUnit(repo, itemInfo, "", info)
+	if err != nil {
+		return nil, err
+	}
+	var itemUnit ItemUnit
+	itemUnit.ItemUnitID = "iu-" + xid.New().String()
+	itemUnit.OrganizationID = info.OrganizationID
+	itemUnit.ItemID = itemID
+	itemUnit.UnitID = info.UnitID
+	itemUnit.Factor = info.Factor
+	itemUnit.Status = 1
+	itemUnit.Created = time.Now()
+	itemUnit.CreatedBy = info.Email
+	itemUnit.Updated = time.Now()
+	itemUnit.UpdatedBy = info.Email
+	err = repo.CreateItemUnit(itemUnit)
+	if err != nil {
+		msg := "create item unit error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &itemUnit.ItemUnitID, err
+}
+
+func checkItemUnit(repo *itemRepository, itemInfo *ItemResponse, itemUnitID string, info ItemUnitNew) error {
+	if info.UnitID == itemInfo.UnitID {
+		msg := "unit is the item base unit"
+		return errors.New(msg)
+	}
+	settingService := setting.NewSettingService()
+	_, err := settingService.GetUnitByID(info.OrganizationID, info.UnitID)
+	if err != nil {
+		return err
+	}
+	isConflict, err := repo.CheckItemUnitConfict(itemUnitID, itemInfo.ItemID, info.UnitID)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return errors.New(msg)
+	}
+	if isConflict {
+		msg := "item unit conflict"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *itemService) UpdateItemUnit(itemUnitID string, info ItemUnitNew) (*ItemUnitResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	oldItemUnit, err := repo.GetItemUnitByID(itemUnitID, info.OrganizationID)
+	if err != nil {
+		msg := "Item unit not exist"
+		return nil, errors.New(msg)
+	}
+	itemInfo, err := repo.GetItemByID(oldItemUnit.ItemID, info.OrganizationID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	err = checkItemUnit(repo, itemInfo, itemUnitID, info)
+	if err != nil {
+		return nil, err
+	}
+	if oldItemUnit.UnitID != info.UnitID {
+		barcodeCount, err := repo.GetUnitBarcodeCount(oldItemUnit.ItemID, oldItemUnit.UnitID)
+		if err != nil {
+			msg := "get unit barcode count error"
+			return nil, errors.New(msg)
+		}
+		if barcodeCount > 0 {
+			msg := "Item unit used by barcodes can not be changed"
+			return nil, errors.New(msg)
+		}
+	}
+	var itemUnit ItemUnit
+	itemUnit.UnitID = info.UnitID
+	itemUnit.Factor = info.Factor
+	itemUnit.Updated = time.Now()
+	itemUnit.UpdatedBy = info.Email
+	err = repo.UpdateItemUnit(itemUnitID, itemUnit)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetItemUnitByID(itemUnitID, info.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, err
+}
+
+func (s *itemService) DeleteItemUnit(itemUnitID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	oldItemUnit, err := repo.GetItemUnitByID(itemUnitID, organizationID)
+	if err != nil {
+		msg := "Item unit not exist"
+		return errors.New(msg)
+	}
+	barcodeCount, err := repo.GetUnitBarcodeCount(oldItemUnit.ItemID, oldItemUnit.UnitID)
+	if err != nil {
+		msg := "get unit barcode count error"
+		return errors.New(msg)
+	}
+	if barcodeCount > 0 {
+		msg := "Item unit used by barcodes can not be deleted"
+		return errors.New(msg)
+	}
+	err = repo.DeleteItemUnit(itemUnitID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetItemUnitFactor returns how many base units make one of the unit, 1 for
+// the item base unit or when no unit is given.
+func (s *itemService) GetItemUnitFactor(organizationID, itemID, unitID string) (int, error) {
+	if unitID == "" {
+		return 1, nil
+	}
+	db := database.RDB()
+	query := NewItemQuery(db)
+	itemInfo, err := query.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "Item not exist"
+		return 0, errors.New(msg)
+	}
+	if itemInfo.UnitID == unitID {
+		return 1, nil
+	}
+	factor, err := query.GetItemUnitFactor(organizationID, itemID, unitID)
+	if err != nil {
+		msg := "unit not valid for item: " + itemInfo.SKU
+		return 0, errors.New(msg)
+	}
+	return factor, nil
+}
+
+//Item component
+
+func checkItemTypeChange(repo *itemRepository, oldItem *ItemResponse, itemType int) error {
+	componentCount, err := repo.GetItemComponentCount(oldItem.ItemID)
+	if err != nil {
+		msg := "get component count error"
+		return errors.New(msg)
+	}
+	if componentCount > 0 {
+		msg := "kit with components can not change type"
+		return errors.New(msg)
+	}
+	if itemType == 2 && (oldItem.StockOnHand != 0 || oldItem.StockPicking != 0 || oldItem.StockPacking != 0) {
+		msg := "item with stock can not be a sales kit"
+		return errors.New(msg)
+	}
+	if itemType != 1 {
+		kitCount, err := repo.GetComponentKitCount(oldItem.ItemID, oldItem.OrganizationID)
+		if err != nil {
+			msg := "get kit count error"
+			return errors.New(msg)
+		}
+		if kitCount > 0 {
+			msg := "kit component can not be a kit"
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func (s *itemService) GetItemComponentList(organizationID, itemID string) (*[]ItemComponentResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetItemComponentList(organizationID, itemID)
+	if err != nil {
+		msg := "get item component error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
+
+func (s *itemService) UpdateItemComponents(itemID string, info ItemComponentNew) (*[]ItemComponentResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	itemInfo, err := repo.GetItemByID(itemID, info.OrganizationID)
+	if err != nil {
+		msg := "Item not exist"
+		return nil, errors.New(msg)
+	}
+	if itemInfo.ItemType == 1 {
+		msg := "item is not a kit"
+		return nil, errors.New(msg)
+	}
+	if itemInfo.ItemType == 2 && len(info.Components) == 0 {
+		msg := "sales kit must have components"
+		return nil, errors.New(msg)
+	}
+	err = repo.DeleteItemComponents(itemID, info.Email)
+	if err != nil {
+		msg := "delete item components error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	for i, line := range info.Components {
+		if line.ComponentID == itemID {
+			msg := "kit can not be its own component"
+			return nil, errors.New(msg)
+		}
+		for _, prev := range info.Components[:i] {
+			if prev.ComponentID == line.ComponentID {
+				msg := "component duplicated: " + line.ComponentID
+				return nil, errors.New(msg)
+			}
+		}
+		componentInfo, err := repo.GetItemByID(line.ComponentID, info.OrganizationID)
+		if err != nil {
+			msg := "component not exist: " + line.ComponentID
+			return nil, errors.New(msg)
+		}
+		if componentInfo.ItemType != 1 {
+			msg := "component must be a standard item: " + componentInfo.SKU
+			return nil, errors.New(msg)
+		}
+		var component ItemComponent
+		component.ItemComponentID = "ic-" + xid.New().String()
+		component.OrganizationID = info.OrganizationID
+		component.ItemID = itemID
+		component.ComponentID = line.ComponentID
+		component.Quantity = line.Quantity
+		component.Status = 1
+		component.Created = time.Now()
+		component.CreatedBy = info.Email
+		component.Updated = time.Now()
+		component.UpdatedBy = info.Email
+		err = repo.CreateItemComponent(component)
+		if err != nil {
+			msg := "create item component error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	res, err := repo.GetItemComponentList(itemID, info.OrganizationID)
+	if err != nil {
+		msg := "get item component error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "item"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = info.User
+	newEvent.ReferenceID = itemID
+	newEvent.Description = "Item Components Updated"
+	newEvent.OrganizationID = info.OrganizationID
+	newEvent.Email = info.Email
+	tx.Commit()
+	rabbit, _ := queue.GetConn()
+	msg, _ := json.Marshal(newEvent)
+	err = rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return nil, errors.New(msg)
+	}
+	return res, nil
+}
+
+//Item class
+
+// ClassifyItems computes the ABC and XYZ classes over the last 52 weeks,
+// stores them on the items and keeps the run in the class history
+func (s *itemService) ClassifyItems(organizationID, email string) (*ItemClassRunResponse, error) {
+	today := time.Now()
+	periodEnd := today.Format("2006-01-02")
+	periodStart := today.AddDate(0, 0, -classificationWeeks*7+1).Format("2006-01-02")
+	to := today.AddDate(0, 0, 1).Format("2006-01-02")
+	db := database.RDB()
+	query := NewItemQuery(db)
+	consumption, err := query.GetItemConsumption(organizationID, periodStart, to)
+	if err != nil {
+		msg := "get item consumption error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	demand, err := query.GetWeeklyDemand(organizationID, periodStart, to)
+	if err != nil {
+		msg := "get weekly demand error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	weeks := make(map[string][]int)
+	for _, v := range *demand {
+		if _, ok := weeks[v.ItemID]; !ok {
+			weeks[v.ItemID] = make([]int, classificationWeeks)
+		}
+		if v.Week >= 0 && v.Week < classificationWeeks {
+			weeks[v.ItemID][v.Week] += v.Quantity
+		}
+	}
+	abc, shares := abcClasses(*consumption)
+	var res ItemClassRunResponse
+	res.PeriodStart = periodStart
+	res.PeriodEnd = periodEnd
+	res.AbcCount = map[string]int{"A": 0, "B": 0, "C": 0}
+	res.XyzCount = map[string]int{"X": 0, "Y": 0, "Z": 0}
+	wdb := database.WDB()
+	tx, err := wdb.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewItemRepository(tx)
+	err = repo.DeleteItemClassByDate(organizationID, periodEnd, email)
+	if err != nil {
+		msg := "delete item class error"
+		return nil, errors.New(msg)
+	}
+	for _, v := range *consumption {
+		xyz, mean, cv := xyzClass(weeks[v.ItemID])
+		var itemClass ItemClass
+		itemClass.OrganizationID = organizationID
+		itemClass.ItemClassID = "icl-" + xid.New().String()
+		itemClass.ItemID = v.ItemID
+		itemClass.PeriodStart = periodStart
+		itemClass.PeriodEnd = periodEnd
+		itemClass.ConsumptionQuantity = v.Quantity
+		itemClass.ConsumptionValue = v.Value
+		itemClass.ValueShare = shares[v.ItemID]
+		itemClass.AbcClass = abc[v.ItemID]
+		itemClass.DemandMean = mean
+		itemClass.DemandCV = cv
+		itemClass.XyzClass = xyz
+		itemClass.Status = 1
+		itemClass.Created = time.Now()
+		itemClass.CreatedBy = email
+		itemClass.Updated = time.Now()
+		itemClass.UpdatedBy = email
+		err = repo.CreateItemClass(itemClass)
+		if err != nil {
+			msg := "create item class error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		err = repo.UpdateItemClass(v.ItemID, itemClass.AbcClass, itemClass.XyzClass, email)
+		if err != nil {
+			msg := "update item class error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		res.ItemCount++
+		res.AbcCount[itemClass.AbcClass]++
+		res.XyzCount[itemClass.XyzClass]++
+	}
+	tx.Commit()
+	return &res, nil
+}
+
+// RunItemClassification classifies the items of the organizations whose last
+// classification is older than the interval
+func (s *itemService) RunItemClassification() error {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	organizations, err := query.GetItemClassDueOrganizations(time.Now().AddDate(0, 0, -classificationInterval).Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+	for _, organizationID := range *organizations {
+		_, err := s.ClassifyItems(organizationID, "system")
+		if err != nil {
+			fmt.Println("classify items " + organizationID + " error: " + err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *itemService) GetItemClassList(filter ItemClassFilter) (int, *[]ItemClassResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	count, err := query.GetItemClassCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetItemClassList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+//Item price
+
+// recordItemPrice keeps the history of the selling and cost price. A change
+// is recorded with the date it takes effect, today by default, which may be
+// in the past but neither in the future nor before the last recorded change.
+func recordItemPrice(repo *itemRepository, organizationID, itemID string, oldSellingPrice, oldCostPrice, sellingPrice, costPrice float64, effectiveDate, source, user string) error {
+	if oldSellingPrice == sellingPrice && oldCostPrice == costPrice {
+		return nil
+	}
+	today := time.Now().Format("2006-01-02")
+	if effectiveDate == "" {
+		effectiveDate = today
+	}
+	if effectiveDate > today {
+		msg := "price date can not be in the future"
+		return errors.New(msg)
+	}
+	lastDate, err := repo.GetLastItemPriceDate(itemID)
+	if err != nil {
+		msg := "get item price error: " + err.Error()
+		return errors.New(msg)
+	}
+	if effectiveDate < lastDate {
+		msg := "price date before the last price change on " + lastDate
+		return errors.New(msg)
+	}
+	var itemPrice ItemPrice
+	itemPrice.OrganizationID = organizationID
+	itemPrice.ItemPriceID = "ipr-" + xid.New().String()
+	itemPrice.ItemID = itemID
+	itemPrice.EffectiveDate = effectiveDate
+	itemPrice.SellingPrice = sellingPrice
+	itemPrice.CostPrice = costPrice
+	itemPrice.PreviousSellingPrice = oldSellingPrice
+	itemPrice.PreviousCostPrice = oldCostPrice
+	itemPrice.Source = source
+	itemPrice.Status = 1
+	itemPrice.Created = time.Now()
+	itemPrice.CreatedBy = user
+	itemPrice.Updated = time.Now()
+	itemPrice.UpdatedBy = user
+	err = repo.CreateItemPrice(itemPrice)
+	if err != nil {
+		msg := "create item price error: " + err.Error()
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// GetItemPriceHistory returns the selling and cost prices in effect over the
+// range as chart points, last year by default, with the purchase rates of the
+// same period
+func (s *itemService) GetItemPriceHistory(itemID string, filter ItemPriceHistoryFilter) (*ItemPriceHistoryResponse, error) {
+	if filter.DateTo == "" {
+		filter.DateTo = time.Now().Format("2006-01-02")
+	}
+	if filter.DateFrom == "" {
+		dateTo, _ := time.Parse("2006-01-02", filter.DateTo)
+		filter.DateFrom = dateTo.AddDate(-1, 0, 0).Format("2006-01-02")
+	}
+	if filter.DateFrom > filter.DateTo {
+		msg := "date from after date to"
+		return nil, errors.New(msg)
+	}
+	db := database.RDB()
+	query := NewItemQuery(db)
+	itemInfo, err := query.GetItemByID(filter.OrganizationID, itemID)
+	if err != nil {
+		msg := "item not exist"
+		return nil, errors.New(msg)
+	}
+	changes, err := query.GetItemPriceList(filter.OrganizationID, itemID, filter.DateFrom, filter.DateTo)
+	if err != nil {
+		msg := "get item price error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	purchases, err := query.GetPurchaseRateList(filter.OrganizationID, itemID, filter.DateFrom, filter.DateTo)
+	if err != nil {
+		msg := "get purchase rate error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	var res ItemPriceHistoryResponse
+	res.ItemID = itemID
+	res.SKU = itemInfo.SKU
+	res.Name = itemInfo.Name
+	res.DateFrom = filter.DateFrom
+	res.DateTo = filter.DateTo
+	res.Changes = *changes
+	res.Purchases = *purchases
+	res.Points = []ItemPricePoint{}
+	if res.Changes == nil {
+		res.Changes = []ItemPriceResponse{}
+	}
+	if res.Purchases == nil {
+		res.Purchases = []PurchaseRateResponse{}
+	}
+	start, err := query.GetItemPriceAt(filter.OrganizationID, itemID, filter.DateFrom)
+	switch {
+	case err == nil:
+		res.Points = append(res.Points, ItemPricePoint{filter.DateFrom, start.SellingPrice, start.CostPrice})
+	case len(res.Changes) == 0:
+		// no history recorded, the current price applies to the whole range
+		res.Points = append(res.Points, ItemPricePoint{filter.DateFrom, itemInfo.SellingPrice, itemInfo.CostPrice})
+	case res.Changes[0].PreviousSellingPrice != 0 || res.Changes[0].PreviousCostPrice != 0:
+		res.Points = append(res.Points, ItemPricePoint{filter.DateFrom, res.Changes[0].PreviousSellingPrice, res.Changes[0].PreviousCostPrice})
+	}
+	for _, change := range res.Changes {
+		point := ItemPricePoint{change.EffectiveDate, change.SellingPrice, change.CostPrice}
+		if n := len(res.Points); n > 0 && res.Points[n-1].Date == point.Date {
+			res.Points[n-1] = point
+			continue
+		}
+		res.Points = append(res.Points, point)
+	}
+	last := res.Points[len(res.Points)-1]
+	if last.Date != filter.DateTo {
+		res.Points = append(res.Points, ItemPricePoint{filter.DateTo, last.SellingPrice, last.CostPrice})
+	}
+	return &res, nil
+}
+
+// GetVendorPriceList returns the last rate each vendor charged for the item,
+// overall and separately on purchase orders and bills
+func (s *itemService) GetVendorPriceList(organizationID, itemID string) (*[]VendorPriceResponse, error) {
+	db := database.RDB()
+	query := NewItemQuery(db)
+	_, err := query.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "item not exist"
+		return nil, errors.New(msg)
+	}
+	rates, err := query.GetPurchaseRateList(organizationID, itemID, "", "")
+	if err != nil {
+		msg := "get purchase rate error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	res := []VendorPriceResponse{}
+	vendors := make(map[string]int)
+	for _, rate := range *rates {
+		i, ok := vendors[rate.VendorID]
+		if !ok {
+			var vendorPrice VendorPriceResponse
+			vendorPrice.VendorID = rate.VendorID
+			vendorPrice.VendorName = rate.VendorName
+			vendorPrice.LastRate = rate.Rate
+			vendorPrice.LastDate = rate.DocumentDate
+			vendorPrice.LastSource = rate.Source
+			vendorPrice.LastDocumentNumber = rate.DocumentNumber
+			res = append(res, vendorPrice)
+			i = len(res) - 1
+			vendors[rate.VendorID] = i
+		}
+		if rate.Source == "bill" && res[i].BillDate == "" {
+			res[i].BillRate = rate.Rate
+			res[i].BillDate = rate.DocumentDate
+			res[i].BillNumber = rate.DocumentNumber
+		}
+		if rate.Source == "purchaseorder" && res[i].PurchaseorderDate == "" {
+			res[i].PurchaseorderRate = rate.Rate
+			res[i].PurchaseorderDate = rate.DocumentDate
+			res[i].PurchaseorderNumber = rate.DocumentNumber
+		}
+	}
+	return &res, nil
+}
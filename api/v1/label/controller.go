@@ -0,0 +1,217 @@
+package label
+
+import (
+	"go-api/core/response"
+	"go-api/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 新建标签模板
+// @Id 1201
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param label_template_info body LabelTemplateNew true "标签模板信息"
+// @Success 200 object response.SuccessRes{data=LabelTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labeltemplates [POST]
+func NewLabelTemplate(c *gin.Context) {
+	var info LabelTemplateNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	labelService := NewLabelService()
+	new, err := labelService.NewLabelTemplate(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 标签模板列表
+// @Id 1202
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param name query string false "模板名称"
+// @Success 200 object response.ListRes{data=[]LabelTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labeltemplates [GET]
+func GetLabelTemplateList(c *gin.Context) {
+	var filter LabelTemplateFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	labelService := NewLabelService()
+	count, list, err := labelService.GetLabelTemplateList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取标签模板
+// @Id 1203
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "标签模板ID"
+// @Success 200 object response.SuccessRes{data=LabelTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labeltemplates/:id [GET]
+func GetLabelTemplateByID(c *gin.Context) {
+	var uri LabelTemplateID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	labelService := NewLabelService()
+	labelTemplate, err := labelService.GetLabelTemplateByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, labelTemplate)
+}
+
+// @Summary 根据ID更新标签模板
+// @Id 1204
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "标签模板ID"
+// @Param label_template_info body LabelTemplateNew true "标签模板信息"
+// @Success 200 object response.SuccessRes{data=LabelTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labeltemplates/:id [PUT]
+func UpdateLabelTemplate(c *gin.Context) {
+	var uri LabelTemplateID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info LabelTemplateNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	labelService := NewLabelService()
+	new, err := labelService.UpdateLabelTemplate(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除标签模板
+// @Id 1205
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "标签模板ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labeltemplates/:id [DELETE]
+func DeleteLabelTemplate(c *gin.Context) {
+	var uri LabelTemplateID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	labelService := NewLabelService()
+	err := labelService.DeleteLabelTemplate(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 打印标签
+// @Id 1206
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param label_info body LabelNew true "标签信息 source_type: items locations packages shippingorders, format: pdf zpl"
+// @Success 200 {file} file "PDF或ZPL文件"
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /labels [POST]
+func PrintLabels(c *gin.Context) {
+	var info LabelNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	labelService := NewLabelService()
+	content, fileName, err := labelService.PrintLabels(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	contentType := "application/pdf"
+	if info.Format == "zpl" {
+		contentType = "text/plain"
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// @Summary 生成条码图片
+// @Id 1207
+// @Tags 标签打印
+// @version 1.0
+// @Accept application/json
+// @Produce image/png
+// @Param symbology query string true "码制 code128 ean13 qr"
+// @Param data query string true "条码内容"
+// @Param format query string false "格式 png svg"
+// @Param scale query int false "模块像素"
+// @Param height query int false "条码高度（像素）"
+// @Success 200 {file} file "条码图片"
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /barcodeimages [GET]
+func GetBarcodeImage(c *gin.Context) {
+	var filter BarcodeImageFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	labelService := NewLabelService()
+	content, contentType, err := labelService.GetBarcodeImage(filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	c.Data(http.StatusOK, contentType, content)
+}
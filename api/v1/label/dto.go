@@ -0,0 +1,69 @@
+package label
+
+import "go-api/core/request"
+
+type LabelTemplateNew struct {
+	Name           string  `json:"name" binding:"required,min=1,max=64"`
+	PaperSize      int     `json:"paper_size" binding:"required,oneof=1 2 3"`
+	LabelWidth     float64 `json:"label_width" binding:"required,gt=0,max=300"`
+	LabelHeight    float64 `json:"label_height" binding:"required,gt=0,max=300"`
+	ColumnCount    int     `json:"column_count" binding:"omitempty,min=1,max=10"`
+	RowCount       int     `json:"row_count" binding:"omitempty,min=1,max=40"`
+	MarginTop      float64 `json:"margin_top" binding:"omitempty,min=0,max=100"`
+	MarginLeft     float64 `json:"margin_left" binding:"omitempty,min=0,max=100"`
+	GapX           float64 `json:"gap_x" binding:"omitempty,min=0,max=100"`
+	GapY           float64 `json:"gap_y" binding:"omitempty,min=0,max=100"`
+	Symbology      int     `json:"symbology" binding:"required,oneof=1 2 3"`
+	FontSize       float64 `json:"font_size" binding:"omitempty,min=4,max=36"`
+	ShowText       int     `json:"show_text" binding:"required,oneof=1 2"`
+	Status         int     `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
+	User           string  `json:"user" swaggerignore:"true"`
+	Email          string  `json:"email" swaggerignore:"true"`
+}
+
+type LabelTemplateFilter struct {
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type LabelTemplateResponse struct {
+	OrganizationID  string  `db:"organization_id" json:"organization_id"`
+	LabelTemplateID string  `db:"label_template_id" json:"label_template_id"`
+	Name            string  `db:"name" json:"name"`
+	PaperSize       int     `db:"paper_size" json:"paper_size"`
+	LabelWidth      float64 `db:"label_width" json:"label_width"`
+	LabelHeight     float64 `db:"label_height" json:"label_height"`
+	ColumnCount     int     `db:"column_count" json:"column_count"`
+	RowCount        int     `db:"row_count" json:"row_count"`
+	MarginTop       float64 `db:"margin_top" json:"margin_top"`
+	MarginLeft      float64 `db:"margin_left" json:"margin_left"`
+	GapX            float64 `db:"gap_x" json:"gap_x"`
+	GapY            float64 `db:"gap_y" json:"gap_y"`
+	Symbology       int     `db:"symbology" json:"symbology"`
+	FontSize        float64 `db:"font_size" json:"font_size"`
+	ShowText        int     `db:"show_text" json:"show_text"`
+	Status          int     `db:"status" json:"status"`
+}
+
+type LabelTemplateID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type LabelNew struct {
+	SourceType      string   `json:"source_type" binding:"required,oneof=items locations packages shippingorders"`
+	IDs             []string `json:"ids" binding:"required,min=1,max=500,dive,min=1,max=64"`
+	Copies          int      `json:"copies" binding:"omitempty,min=1,max=100"`
+	LabelTemplateID string   `json:"label_template_id" binding:"omitempty,max=64"`
+	Format          string   `json:"format" binding:"omitempty,oneof=pdf zpl"`
+	OrganizationID  string   `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type BarcodeImageFilter struct {
+	Symbology string `form:"symbology" binding:"required,oneof=code128 ean13 qr"`
+	Data      string `form:"data" binding:"required,min=1,max=200"`
+	Format    string `form:"format" binding:"omitempty,oneof=png svg"`
+	Scale     int    `form:"scale" binding:"omitempty,min=1,max=20"`
+	Height    int    `form:"height" binding:"omitempty,min=10,max=1000"`
+}
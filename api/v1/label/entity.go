@@ -0,0 +1,27 @@
+package label
+
+import "time"
+
+type LabelTemplate struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	LabelTemplateID string    `db:"label_template_id" json:"label_template_id"`
+	Name            string    `db:"name" json:"name"`
+	PaperSize       int       `db:"paper_size" json:"paper_size"`     //1 A4 2 letter 3 label roll
+	LabelWidth      float64   `db:"label_width" json:"label_width"`   //mm
+	LabelHeight     float64   `db:"label_height" json:"label_height"` //mm
+	ColumnCount     int       `db:"column_count" json:"column_count"`
+	RowCount        int       `db:"row_count" json:"row_count"`
+	MarginTop       float64   `db:"margin_top" json:"margin_top"`
+	MarginLeft      float64   `db:"margin_left" json:"margin_left"`
+	GapX            float64   `db:"gap_x" json:"gap_x"`
+	GapY            float64   `db:"gap_y" json:"gap_y"`
+	Symbology       int       `db:"symbology" json:"symbology"` //1 code128 2 ean13 3 qr
+	FontSize        float64   `db:"font_size" json:"font_size"`
+	ShowText        int       `db:"show_text" json:"show_text"` //1 yes 2 no
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,66 @@
+package label
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type labelQuery struct {
+	conn *sqlx.DB
+}
+
+func NewLabelQuery(connection *sqlx.DB) *labelQuery {
+	return &labelQuery{
+		conn: connection,
+	}
+}
+
+//label template
+
+func (r *labelQuery) GetLabelTemplateByID(organizationID, id string) (*LabelTemplateResponse, error) {
+	var labelTemplate LabelTemplateResponse
+	err := r.conn.Get(&labelTemplate, `
+		SELECT organization_id, label_template_id, name, paper_size, label_width, label_height, column_count, row_count, margin_top, margin_left, gap_x, gap_y, symbology, font_size, show_text, status
+		FROM s_label_templates
+		WHERE organization_id = ? AND label_template_id = ? AND status > 0
+	`, organizationID, id)
+	return &labelTemplate, err
+}
+
+func (r *labelQuery) GetLabelTemplateCount(filter LabelTemplateFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_label_templates
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *labelQuery) GetLabelTemplateList(filter LabelTemplateFilter) (*[]LabelTemplateResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var labelTemplates []LabelTemplateResponse
+	err := r.conn.Select(&labelTemplates, `
+		SELECT organization_id, label_template_id, name, paper_size, label_width, label_height, column_count, row_count, margin_top, margin_left, gap_x, gap_y, symbology, font_size, show_text, status
+		FROM s_label_templates
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &labelTemplates, err
+}
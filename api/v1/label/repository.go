@@ -0,0 +1,103 @@
+package label
+
+import (
+	"database/sql"
+	"time"
+)
+
+type labelRepository struct {
+	tx *sql.Tx
+}
+
+func NewLabelRepository(tx *sql.Tx) *labelRepository {
+	return &labelRepository{tx: tx}
+}
+
+//label template
+
+func (r *labelRepository) CheckLabelTemplateConfict(labelTemplateID, organizationID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_label_templates WHERE organization_id = ? AND label_template_id != ? AND name = ? AND status > 0", organizationID, labelTemplateID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *labelRepository) CreateLabelTemplate(info LabelTemplate) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_label_templates
+		(
+			organization_id,
+			label_template_id,
+			name,
+			paper_size,
+			label_width,
+			label_height,
+			column_count,
+			row_count,
+			margin_top,
+			margin_left,
+			gap_x,
+			gap_y,
+			symbology,
+			font_size,
+			show_text,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LabelTemplateID, info.Name, info.PaperSize, info.LabelWidth, info.LabelHeight, info.ColumnCount, info.RowCount, info.MarginTop, info.MarginLeft, info.GapX, info.GapY, info.Symbology, info.FontSize, info.ShowText, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *labelRepository) GetLabelTemplateByID(organizationID, labelTemplateID string) (*LabelTemplateResponse, error) {
+	var res LabelTemplateResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, label_template_id, name, paper_size, label_width, label_height, column_count, row_count, margin_top, margin_left, gap_x, gap_y, symbology, font_size, show_text, status
+		FROM s_label_templates
+		WHERE organization_id = ? AND label_template_id = ? AND status > 0 LIMIT 1
+	`, organizationID, labelTemplateID)
+	err := row.Scan(&res.OrganizationID, &res.LabelTemplateID, &res.Name, &res.PaperSize, &res.LabelWidth, &res.LabelHeight, &res.ColumnCount, &res.RowCount, &res.MarginTop, &res.MarginLeft, &res.GapX, &res.GapY, &res.Symbology, &res.FontSize, &res.ShowText, &res.Status)
+	return &res, err
+}
+
+func (r *labelRepository) UpdateLabelTemplate(id string, info LabelTemplate) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_label_templates SET
+		name = ?,
+		paper_size = ?,
+		label_width = ?,
+		label_height = ?,
+		column_count = ?,
+		row_count = ?,
+		margin_top = ?,
+		margin_left = ?,
+		gap_x = ?,
+		gap_y = ?,
+		symbology = ?,
+		font_size = ?,
+		show_text = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE label_template_id = ?
+	`, info.Name, info.PaperSize, info.LabelWidth, info.LabelHeight, info.ColumnCount, info.RowCount, info.MarginTop, info.MarginLeft, info.GapX, info.GapY, info.Symbology, info.FontSize, info.ShowText, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *labelRepository) DeleteLabelTemplate(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_label_templates SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE label_template_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
@@ -0,0 +1,14 @@
+package label
+
+import "github.com/gin-gonic/gin"
+
+func AuthRouter(g *gin.RouterGroup) {
+	g.POST("/labeltemplates", NewLabelTemplate)
+	g.GET("/labeltemplates", GetLabelTemplateList)
+	g.GET("/labeltemplates/:id", GetLabelTemplateByID)
+	g.PUT("/labeltemplates/:id", UpdateLabelTemplate)
+	g.DELETE("/labeltemplates/:id", DeleteLabelTemplate)
+
+	g.POST("/labels", PrintLabels)
+	g.GET("/barcodeimages", GetBarcodeImage)
+}
@@ -0,0 +1,421 @@
+package label
+
+import (
+	"errors"
+	"go-api/api/v1/item"
+	"go-api/api/v1/salesorder"
+	"go-api/api/v1/warehouse"
+	"go-api/core/barcode"
+	"go-api/core/database"
+	corelabel "go-api/core/label"
+	"strconv"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+type labelService struct {
+}
+
+func NewLabelService() *labelService {
+	return &labelService{}
+}
+
+var symbologies = map[int]string{
+	1: barcode.Code128,
+	2: barcode.EAN13,
+	3: barcode.QR,
+}
+
+// defaultLabelTemplate is used when no template is given, a sheet of 3 by 8
+// labels of 70 x 37 mm on A4
+func defaultLabelTemplate(organizationID string) LabelTemplateResponse {
+	var res LabelTemplateResponse
+	res.OrganizationID = organizationID
+	res.Name = "Default"
+	res.PaperSize = 1
+	res.LabelWidth = 70
+	res.LabelHeight = 37
+	res.ColumnCount = 3
+	res.RowCount = 8
+	res.Symbology = 1
+	res.FontSize = 8
+	res.ShowText = 1
+	res.Status = 1
+	return res
+}
+
+func layoutOf(template LabelTemplateResponse) corelabel.Layout {
+	var layout corelabel.Layout
+	switch template.PaperSize {
+	case 1:
+		layout.PageWidth, layout.PageHeight = 210, 297
+	case 2:
+		layout.PageWidth, layout.PageHeight = 215.9, 279.4
+	}
+	layout.LabelWidth = template.LabelWidth
+	layout.LabelHeight = template.LabelHeight
+	layout.Columns = template.ColumnCount
+	layout.Rows = template.RowCount
+	if template.PaperSize == 3 {
+		layout.Columns, layout.Rows = 1, 1
+	}
+	layout.MarginTop = template.MarginTop
+	layout.MarginLeft = template.MarginLeft
+	layout.GapX = template.GapX
+	layout.GapY = template.GapY
+	layout.Symbology = symbologies[template.Symbology]
+	layout.FontSize = template.FontSize
+	layout.ShowText = template.ShowText == 1
+	return layout
+}
+
+// checkLabelTemplate makes sure the labels of a sheet fit on the paper
+func checkLabelTemplate(info *LabelTemplateNew) error {
+	if info.ColumnCount == 0 {
+		info.ColumnCount = 1
+	}
+	if info.RowCount == 0 {
+		info.RowCount = 1
+	}
+	if info.FontSize == 0 {
+		info.FontSize = 8
+	}
+	if info.PaperSize == 3 {
+		return nil
+	}
+	var template LabelTemplateResponse
+	template.PaperSize = info.PaperSize
+	layout := layoutOf(template)
+	width := info.MarginLeft + info.LabelWidth*float64(info.ColumnCount) + info.GapX*float64(info.ColumnCount-1)
+	height := info.MarginTop + info.LabelHeight*float64(info.RowCount) + info.GapY*float64(info.RowCount-1)
+	if width > layout.PageWidth || height > layout.PageHeight {
+		msg := "labels do not fit on the paper"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *labelService) NewLabelTemplate(info LabelTemplateNew) (*LabelTemplateResponse, error) {
+	err := checkLabelTemplate(&info)
+	if err != nil {
+		return nil, err
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewLabelRepository(tx)
+	isConflict, err := repo.CheckLabelTemplateConfict("", info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "label template name conflict"
+		return nil, errors.New(msg)
+	}
+	var labelTemplate LabelTemplate
+	labelTemplate.OrganizationID = info.OrganizationID
+	labelTemplate.LabelTemplateID = "ltpl-" + xid.New().String()
+	labelTemplate.Name = info.Name
+	labelTemplate.PaperSize = info.PaperSize
+	labelTemplate.LabelWidth = info.LabelWidth
+	labelTemplate.LabelHeight = info.LabelHeight
+	labelTemplate.ColumnCount = info.ColumnCount
+	labelTemplate.RowCount = info.RowCount
+	labelTemplate.MarginTop = info.MarginTop
+	labelTemplate.MarginLeft = info.MarginLeft
+	labelTemplate.GapX = info.GapX
+	labelTemplate.GapY = info.GapY
+	labelTemplate.Symbology = info.Symbology
+	labelTemplate.FontSize = info.FontSize
+	labelTemplate.ShowText = info.ShowText
+	labelTemplate.Status = info.Status
+	labelTemplate.Created = time.Now()
+	labelTemplate.CreatedBy = info.Email
+	labelTemplate.Updated = time.Now()
+	labelTemplate.UpdatedBy = info.Email
+	err = repo.CreateLabelTemplate(labelTemplate)
+	if err != nil {
+		msg := "create label template error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetLabelTemplateByID(info.OrganizationID, labelTemplate.LabelTemplateID)
+	if err != nil {
+		msg := "get label template error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return res, nil
+}
+
+func (s *labelService) GetLabelTemplateList(filter LabelTemplateFilter) (int, *[]LabelTemplateResponse, error) {
+	db := database.RDB()
+	query := NewLabelQuery(db)
+	count, err := query.GetLabelTemplateCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetLabelTemplateList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *labelService) GetLabelTemplateByID(organizationID, id string) (*LabelTemplateResponse, error) {
+	db := database.RDB()
+	query := NewLabelQuery(db)
+	labelTemplate, err := query.GetLabelTemplateByID(organizationID, id)
+	if err != nil {
+		msg := "label template not exist"
+		return nil, errors.New(msg)
+	}
+	return labelTemplate, nil
+}
+
+func (s *labelService) UpdateLabelTemplate(labelTemplateID string, info LabelTemplateNew) (*LabelTemplateResponse, error) {
+	err := checkLabelTemplate(&info)
+	if err != nil {
+		return nil, err
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewLabelRepository(tx)
+	_, err = repo.GetLabelTemplateByID(info.OrganizationID, labelTemplateID)
+	if err != nil {
+		msg := "label template not exist"
+		return nil, errors.New(msg)
+	}
+	isConflict, err := repo.CheckLabelTemplateConfict(labelTemplateID, info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "label template name conflict"
+		return nil, errors.New(msg)
+	}
+	var labelTemplate LabelTemplate
+	labelTemplate.Name = info.Name
+	labelTemplate.PaperSize = info.PaperSize
+	labelTemplate.LabelWidth = info.LabelWidth
+	labelTemplate.LabelHeight = info.LabelHeight
+	labelTemplate.ColumnCount = info.ColumnCount
+	labelTemplate.RowCount = info.RowCount
+	labelTemplate.MarginTop = info.MarginTop
+	labelTemplate.MarginLeft = info.MarginLeft
+	labelTemplate.GapX = info.GapX
+	labelTemplate.GapY = info.GapY
+	labelTemplate.Symbology = info.Symbology
+	labelTemplate.FontSize = info.FontSize
+	labelTemplate.ShowText = info.ShowText
+	labelTemplate.Status = info.Status
+	labelTemplate.Updated = time.Now()
+	labelTemplate.UpdatedBy = info.Email
+	err = repo.UpdateLabelTemplate(labelTemplateID, labelTemplate)
+	if err != nil {
+		msg := "update label template error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetLabelTemplateByID(info.OrganizationID, labelTemplateID)
+	if err != nil {
+		msg := "get label template error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return res, nil
+}
+
+func (s *labelService) DeleteLabelTemplate(labelTemplateID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewLabelRepository(tx)
+	_, err = repo.GetLabelTemplateByID(organizationID, labelTemplateID)
+	if err != nil {
+		msg := "label template not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteLabelTemplate(labelTemplateID, email)
+	if err != nil {
+		msg := "delete label template error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// itemLabels prints the base unit barcode of the items, items without one
+// get their SKU encoded
+func itemLabels(organizationID string, ids []string) ([]corelabel.Label, error) {
+	db := database.RDB()
+	query := item.NewItemQuery(db)
+	var res []corelabel.Label
+	for _, id := range ids {
+		itemInfo, err := query.GetItemByID(organizationID, id)
+		if err != nil {
+			msg := "item not exist: " + id
+			return nil, errors.New(msg)
+		}
+		var filter item.BarcodeFilter
+		filter.OrganizationID = organizationID
+		filter.ItemID = id
+		filter.PageID = 1
+		filter.PageSize = 200
+		barcodes, err := query.GetBarcodeList(filter)
+		if err != nil {
+			msg := "get barcode error"
+			return nil, errors.New(msg)
+		}
+		data := itemInfo.SKU
+		for _, v := range *barcodes {
+			if v.UnitID == "" && v.Status == 1 {
+				data = v.Code
+				break
+			}
+		}
+		res = append(res, corelabel.Label{Title: itemInfo.Name, Lines: []string{"SKU: " + itemInfo.SKU}, Data: data})
+	}
+	return res, nil
+}
+
+func locationLabels(organizationID string, ids []string) ([]corelabel.Label, error) {
+	db := database.RDB()
+	query := warehouse.NewWarehouseQuery(db)
+	var res []corelabel.Label
+	for _, id := range ids {
+		location, err := query.GetLocationByID(organizationID, id)
+		if err != nil {
+			msg := "location not exist: " + id
+			return nil, errors.New(msg)
+		}
+		res = append(res, corelabel.Label{Title: location.Code, Lines: []string{"Bay: " + location.BayCode + "  Level: " + location.Level}, Data: location.Code})
+	}
+	return res, nil
+}
+
+func packageLabels(organizationID string, ids []string) ([]corelabel.Label, error) {
+	db := database.RDB()
+	query := salesorder.NewSalesorderQuery(db)
+	var res []corelabel.Label
+	for _, id := range ids {
+		packageInfo, err := query.GetPackageByID(organizationID, id)
+		if err != nil {
+			msg := "package not exist: " + id
+			return nil, errors.New(msg)
+		}
+		res = append(res, corelabel.Label{Title: packageInfo.PackageNumber, Lines: []string{"Sales Order: " + packageInfo.SalesorderNumber, "Date: " + packageInfo.PackageDate}, Data: packageInfo.PackageNumber})
+	}
+	return res, nil
+}
+
+// shippingorderLabels encodes the shipping order number and prints the ship
+// to address with the carrier tracking number
+func shippingorderLabels(organizationID string, ids []string) ([]corelabel.Label, error) {
+	db := database.RDB()
+	query := salesorder.NewSalesorderQuery(db)
+	var res []corelabel.Label
+	for _, id := range ids {
+		shippingorder, err := query.GetShippingorderByID(organizationID, id)
+		if err != nil {
+			msg := "shipping order not exist: " + id
+			return nil, errors.New(msg)
+		}
+		lines := shippingorder.ShippingAddress.Lines()
+		if shippingorder.TrackingNumber != "" {
+			lines = append(lines, shippingorder.CarrierName+" "+shippingorder.TrackingNumber)
+		}
+		res = append(res, corelabel.Label{Title: shippingorder.ShippingorderNumber, Lines: lines, Data: shippingorder.ShippingorderNumber})
+	}
+	return res, nil
+}
+
+// PrintLabels renders the labels of the records with the template given or
+// the default one, each label repeated copies times
+func (s *labelService) PrintLabels(info LabelNew) ([]byte, string, error) {
+	template := defaultLabelTemplate(info.OrganizationID)
+	if info.LabelTemplateID != "" {
+		labelTemplate, err := s.GetLabelTemplateByID(info.OrganizationID, info.LabelTemplateID)
+		if err != nil {
+			return nil, "", err
+		}
+		template = *labelTemplate
+	}
+	var labels []corelabel.Label
+	var err error
+	switch info.SourceType {
+	case "items":
+		labels, err = itemLabels(info.OrganizationID, info.IDs)
+	case "locations":
+		labels, err = locationLabels(info.OrganizationID, info.IDs)
+	case "packages":
+		labels, err = packageLabels(info.OrganizationID, info.IDs)
+	case "shippingorders":
+		labels, err = shippingorderLabels(info.OrganizationID, info.IDs)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if info.Copies > 1 {
+		var copies []corelabel.Label
+		for _, l := range labels {
+			for i := 0; i < info.Copies; i++ {
+				copies = append(copies, l)
+			}
+		}
+		labels = copies
+	}
+	layout := layoutOf(template)
+	fileName := info.SourceType + "-labels-" + strconv.Itoa(len(labels))
+	if info.Format == "zpl" {
+		content, err := corelabel.ZPL(layout, labels)
+		if err != nil {
+			msg := "render label error: " + err.Error()
+			return nil, "", errors.New(msg)
+		}
+		return content, fileName + ".zpl", nil
+	}
+	content, err := corelabel.PDF(layout, labels)
+	if err != nil {
+		msg := "render label error: " + err.Error()
+		return nil, "", errors.New(msg)
+	}
+	return content, fileName + ".pdf", nil
+}
+
+// GetBarcodeImage renders one code as a PNG or SVG image
+func (s *labelService) GetBarcodeImage(filter BarcodeImageFilter) ([]byte, string, error) {
+	if filter.Scale == 0 {
+		filter.Scale = 2
+	}
+	if filter.Height == 0 {
+		filter.Height = 80
+	}
+	code, err := barcode.Encode(filter.Symbology, filter.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	if filter.Format == "svg" {
+		return barcode.SVG(code, filter.Scale, filter.Height), "image/svg+xml", nil
+	}
+	content, err := barcode.PNG(code, filter.Scale, filter.Height)
+	if err != nil {
+		msg := "render barcode error"
+		return nil, "", errors.New(msg)
+	}
+	return content, "image/png", nil
+}
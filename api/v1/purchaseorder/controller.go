@@ -1,8 +1,10 @@
 package purchaseorder
 
 import (
+	"go-api/api/v1/common"
 	"go-api/core/response"
 	"go-api/service"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,6 +29,7 @@ func NewPurchaseorder(c *gin.Context) {
 	purchaseorder.OrganizationID = claims.OrganizationID
 	purchaseorder.User = claims.UserName
 	purchaseorder.Email = claims.Email
+	purchaseorder.SalesorderID = ""
 	purchaseorderService := NewPurchaseorderService()
 	new, err := purchaseorderService.NewPurchaseorder(purchaseorder)
 	if err != nil {
@@ -45,6 +48,7 @@ func NewPurchaseorder(c *gin.Context) {
 // @Param page_id query int true "页码"
 // @Param page_size query int true "每页行数（5/10/15/20）"
 // @Param purchaseorder_number query string false "采购单号码"
+// @Param salesorder_id query string false "代发货销售单ID"
 // @Success 200 object response.ListRes{data=[]PurchaseorderResponse} 成功
 // @Failure 400 object response.ErrorRes 内部错误
 // @Router /purchaseorders [GET]
@@ -198,11 +202,15 @@ func IssuePurchaseorder(c *gin.Context) {
 	}
 	claims := c.MustGet("claims").(*service.CustomClaims)
 	purchaseorderService := NewPurchaseorderService()
-	err := purchaseorderService.IssuePurchaseorder(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	status, err := purchaseorderService.IssuePurchaseorder(uri.ID, claims.OrganizationID, claims.RoleID, claims.UserName, claims.Email)
 	if err != nil {
 		response.ResponseError(c, "DatabaseError", err)
 		return
 	}
+	if status == 1 {
+		response.Response(c, "pending approval")
+		return
+	}
 	response.Response(c, "ok")
 }
 
@@ -391,6 +399,7 @@ func NewBill(c *gin.Context) {
 // @Param page_id query int true "页码"
 // @Param page_size query int true "每页行数（5/10/15/20）"
 // @Param bill_number query string false "bill编码"
+// @Param match_status query int false "匹配状态（1已匹配/2挂起/3已放行）"
 // @Param purchaseorder_id query string false "销售订单ID"
 // @Success 200 object response.ListRes{data=[]BillResponse} 成功
 // @Failure 400 object response.ErrorRes 内部错误
@@ -652,3 +661,695 @@ func DeletePayment(c *gin.Context) {
 	}
 	response.Response(c, "OK")
 }
+
+// @Summary 新建到岸成本单
+// @Id 423
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param landed_cost body LandedCostNew true "到岸成本信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /landedcosts [POST]
+func NewLandedCost(c *gin.Context) {
+	var landedCost LandedCostNew
+	if err := c.ShouldBindJSON(&landedCost); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	landedCost.OrganizationID = claims.OrganizationID
+	landedCost.User = claims.UserName
+	landedCost.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	new, err := purchaseorderService.NewLandedCost(landedCost)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 到岸成本单列表
+// @Id 424
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param landed_cost_number query string false "到岸成本单号码"
+// @Param reference_id query string false "收货单或BillID"
+// @Success 200 object response.ListRes{data=[]LandedCostResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /landedcosts [GET]
+func GetLandedCostList(c *gin.Context) {
+	var filter LandedCostFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	count, list, err := purchaseorderService.GetLandedCostList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取到岸成本单
+// @Id 425
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "到岸成本单ID"
+// @Success 200 object response.SuccessRes{data=LandedCostResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /landedcosts/:id [GET]
+func GetLandedCostByID(c *gin.Context) {
+	var uri LandedCostID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	landedCost, err := purchaseorderService.GetLandedCostByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, landedCost)
+}
+
+// @Summary 到岸成本分摊明细
+// @Id 426
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "到岸成本单ID"
+// @Success 200 object response.SuccessRes{data=[]LandedCostAllocationResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /landedcosts/:id/allocations [GET]
+func GetLandedCostAllocationList(c *gin.Context) {
+	var uri LandedCostID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	list, err := purchaseorderService.GetLandedCostAllocationList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 根据ID删除到岸成本单
+// @Id 427
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "到岸成本单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /landedcosts/:id [DELETE]
+func DeleteLandedCost(c *gin.Context) {
+	var uri LandedCostID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	err := purchaseorderService.DeleteLandedCost(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 新建匹配策略
+// @Id 428
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param match_policy body MatchPolicyNew true "匹配策略信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /matchpolicies [POST]
+func NewMatchPolicy(c *gin.Context) {
+	var policy MatchPolicyNew
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	policy.OrganizationID = claims.OrganizationID
+	policy.User = claims.UserName
+	policy.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	new, err := purchaseorderService.NewMatchPolicy(policy)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 匹配策略列表
+// @Id 429
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param name query string false "匹配策略名称"
+// @Param vendor_id query string false "供应商ID"
+// @Success 200 object response.ListRes{data=[]MatchPolicyResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /matchpolicies [GET]
+func GetMatchPolicyList(c *gin.Context) {
+	var filter MatchPolicyFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	count, list, err := purchaseorderService.GetMatchPolicyList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取匹配策略
+// @Id 430
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "匹配策略ID"
+// @Success 200 object response.SuccessRes{data=MatchPolicyResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /matchpolicies/:id [GET]
+func GetMatchPolicyByID(c *gin.Context) {
+	var uri MatchPolicyID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	policy, err := purchaseorderService.GetMatchPolicyByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, policy)
+}
+
+// @Summary 根据ID更新匹配策略
+// @Id 431
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "匹配策略ID"
+// @Param match_policy body MatchPolicyNew true "匹配策略信息"
+// @Success 200 object response.SuccessRes{data=MatchPolicyResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /matchpolicies/:id [PUT]
+func UpdateMatchPolicy(c *gin.Context) {
+	var uri MatchPolicyID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var policy MatchPolicyNew
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	policy.OrganizationID = claims.OrganizationID
+	policy.User = claims.UserName
+	policy.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	new, err := purchaseorderService.UpdateMatchPolicy(uri.ID, policy)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除匹配策略
+// @Id 432
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "匹配策略ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /matchpolicies/:id [DELETE]
+func DeleteMatchPolicy(c *gin.Context) {
+	var uri MatchPolicyID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	err := purchaseorderService.DeleteMatchPolicy(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 放行挂起的Bill
+// @Id 433
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "BillID"
+// @Param release_info body BillReleaseNew true "放行信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /bills/:id/release [POST]
+func ReleaseBill(c *gin.Context) {
+	var uri BillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info BillReleaseNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	err := purchaseorderService.ReleaseBill(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary Bill匹配差异报表
+// @Id 434
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param vendor_id query string false "供应商ID"
+// @Param bill_id query string false "BillID"
+// @Param match_status query int false "匹配状态（2挂起/3已放行）"
+// @Success 200 object response.ListRes{data=[]BillMismatchResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /billmismatches [GET]
+func GetBillMismatchList(c *gin.Context) {
+	var filter BillMismatchFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	count, list, err := purchaseorderService.GetBillMismatchList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 新建循环账单
+// @Id 435
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param recurring_bill_info body RecurringBillNew true "循环账单信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills [POST]
+func NewRecurringBill(c *gin.Context) {
+	var info RecurringBillNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	new, err := purchaseorderService.NewRecurringBill(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 循环账单列表
+// @Id 436
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param profile_name query string false "名称"
+// @Param purchaseorder_id query string false "采购单ID"
+// @Param vendor_id query string false "供应商ID"
+// @Param status query int false "状态"
+// @Success 200 object response.ListRes{data=[]RecurringBillResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills [GET]
+func GetRecurringBillList(c *gin.Context) {
+	var filter RecurringBillFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	count, list, err := purchaseorderService.GetRecurringBillList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取循环账单
+// @Id 437
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Success 200 object response.SuccessRes{data=RecurringBillResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id [GET]
+func GetRecurringBillByID(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	recurringBill, err := purchaseorderService.GetRecurringBillByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, recurringBill)
+}
+
+// @Summary 根据ID更新循环账单
+// @Id 438
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Param recurring_bill_info body RecurringBillNew true "循环账单信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id [PUT]
+func UpdateRecurringBill(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info RecurringBillNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.UserName
+	info.Email = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	new, err := purchaseorderService.UpdateRecurringBill(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除循环账单
+// @Id 439
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id [DELETE]
+func DeleteRecurringBill(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	err := purchaseorderService.DeleteRecurringBill(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 循环账单产品列表
+// @Id 440
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Success 200 object response.ListRes{data=[]RecurringBillItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id/items [GET]
+func GetRecurringBillItemList(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	list, err := purchaseorderService.GetRecurringBillItemList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 循环账单生成记录
+// @Id 441
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Success 200 object response.ListRes{data=[]RecurringBillRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id/runs [GET]
+func GetRecurringBillRunList(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	list, err := purchaseorderService.GetRecurringBillRunList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 生成到期的循环账单
+// @Id 442
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环账单ID"
+// @Success 200 object response.ListRes{data=[]RecurringBillRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringbills/:id/run [POST]
+func RunRecurringBill(c *gin.Context) {
+	var uri RecurringBillID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	list, err := purchaseorderService.RunRecurringBill(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 采购单PDF
+// @Id 443
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param id path string true "采购单ID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /purchaseorders/:id/pdf [GET]
+func GetPurchaseorderPDF(c *gin.Context) {
+	var uri PurchaseorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	content, fileName, err := purchaseorderService.GetPurchaseorderPDF(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary 审批采购单
+// @Id 444
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "采购单ID"
+// @Param approval_info body common.ApprovalAction true "审批信息（1通过/2驳回）"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /purchaseorders/:id/approval [POST]
+func ApprovePurchaseorder(c *gin.Context) {
+	var uri PurchaseorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info common.ApprovalAction
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.RoleID = claims.RoleID
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	purchaseorderService := NewPurchaseorderService()
+	err := purchaseorderService.ApprovePurchaseorder(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
+
+// @Summary 低于再订货点的商品列表
+// @Id 445
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param vendor_id query string false "默认供应商ID"
+// @Success 200 object response.ListRes{data=[]ReorderItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /reorderitems [GET]
+func GetReorderItemList(c *gin.Context) {
+	var filter ReorderItemFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	purchaseorderService := NewPurchaseorderService()
+	count, list, err := purchaseorderService.GetReorderItemList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 按默认供应商生成再订货采购草稿
+// @Id 446
+// @Tags 采购单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=ReorderRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /reorderitems/run [POST]
+func RunReorder(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	purchaseorderService := NewPurchaseorderService()
+	res, err := purchaseorderService.RunReorder(claims.OrganizationID, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
@@ -1,27 +1,33 @@
 package purchaseorder
 
 import (
+	"go-api/api/v1/setting"
 	"go-api/core/request"
 )
 
 type PurchaseorderNew struct {
-	PurchaseorderNumber  string                 `json:"purchaseorder_number" binding:"required,min=6,max=64"`
-	PurchaseorderDate    string                 `json:"purchaseorder_date" binding:"required,datetime=2006-01-02"`
-	ExpectedDeliveryDate string                 `json:"expected_delivery_date" binding:"required,datetime=2006-01-02"`
-	VendorID             string                 `json:"vendor_id" binding:"required"`
-	DiscountType         int                    `json:"discount_type" binding:"omitempty,oneof=1 2"`
-	DiscountValue        float64                `json:"discount_value" binding:"omitempty"`
-	ShippingFee          float64                `json:"shipping_fee" binding:"omitempty"`
-	Notes                string                 `json:"notes" binding:"omitempty"`
-	Items                []PurchaseorderItemNew `json:"items" binding:"required"`
-	OrganizationID       string                 `json:"organiztion_id" swaggerignore:"true"`
-	User                 string                 `json:"user" swaggerignore:"true"`
-	Email                string                 `json:"email" swaggerignore:"true"`
+	PurchaseorderNumber  string                  `json:"purchaseorder_number" binding:"required,min=6,max=64"`
+	PurchaseorderDate    string                  `json:"purchaseorder_date" binding:"required,datetime=2006-01-02"`
+	ExpectedDeliveryDate string                  `json:"expected_delivery_date" binding:"required,datetime=2006-01-02"`
+	VendorID             string                  `json:"vendor_id" binding:"required"`
+	DiscountType         int                     `json:"discount_type" binding:"omitempty,oneof=1 2"`
+	DiscountValue        float64                 `json:"discount_value" binding:"omitempty"`
+	ShippingFee          float64                 `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID        string                  `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive         int                     `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
+	Notes                string                  `json:"notes" binding:"omitempty"`
+	Items                []PurchaseorderItemNew  `json:"items" binding:"required"`
+	SalesorderID         string                  `json:"salesorder_id" swaggerignore:"true"`
+	ShippingAddress      setting.AddressSnapshot `json:"shipping_address" swaggerignore:"true"`
+	OrganizationID       string                  `json:"organiztion_id" swaggerignore:"true"`
+	User                 string                  `json:"user" swaggerignore:"true"`
+	Email                string                  `json:"email" swaggerignore:"true"`
 }
 
 type PurchaseorderItemNew struct {
 	PurchaseorderItemID string  `json:"purchaseorder_item_id" binding:"omitempty"`
 	ItemID              string  `json:"item_id" binding:"required"`
+	UnitID              string  `json:"unit_id" binding:"omitempty"`
 	Quantity            int     `json:"quantity" binding:"required"`
 	Rate                float64 `json:"rate" binding:"required"`
 	TaxID               string  `json:"tax_id" binding:"omitempty"`
@@ -29,29 +35,39 @@ type PurchaseorderItemNew struct {
 
 type PurchaseorderFilter struct {
 	PurchaseorderNumber string `form:"purchaseorder_number" binding:"omitempty,max=64,min=1"`
+	SalesorderID        string `form:"salesorder_id" binding:"omitempty,max=64"`
 	OrganizationID      string `json:"organiztion_id" swaggerignore:"true"`
 	request.PageInfo
 }
 
 type PurchaseorderResponse struct {
-	PurchaseorderID      string  `db:"purchaseorder_id" json:"purchaseorder_id"`
-	OrganizationID       string  `db:"organization_id" json:"organization_id"`
-	PurchaseorderNumber  string  `db:"purchaseorder_number" json:"purchaseorder_number"`
-	PurchaseorderDate    string  `db:"purchaseorder_date" json:"purchaseorder_date"`
-	ExpectedDeliveryDate string  `db:"expected_delivery_date" json:"expected_delivery_date"`
-	VendorID             string  `db:"vendor_id" json:"vendor_id"`
-	VendorName           string  `db:"vendor_name" json:"vendor_name"`
-	ItemCount            float64 `db:"item_count" json:"item_count"`
-	Subtotal             float64 `db:"sub_total" json:"sub_total"`
-	TaxTotal             float64 `db:"tax_total" json:"tax_total"`
-	DiscountType         int     `db:"discount_type" json:"discount_type"`
-	DiscountValue        float64 `db:"discount_value" json:"discount_value"`
-	ShippingFee          float64 `db:"shipping_fee" json:"shipping_fee"`
-	Total                float64 `db:"total" json:"total"`
-	Notes                string  `db:"notes" json:"notes"`
-	BillingStatus        int     `db:"billing_status" json:"billing_status"`
-	ReceiveStatus        int     `db:"receive_status" json:"receive_status"`
-	Status               int     `db:"status" json:"status"`
+	PurchaseorderID      string                  `db:"purchaseorder_id" json:"purchaseorder_id"`
+	OrganizationID       string                  `db:"organization_id" json:"organization_id"`
+	PurchaseorderNumber  string                  `db:"purchaseorder_number" json:"purchaseorder_number"`
+	PurchaseorderDate    string                  `db:"purchaseorder_date" json:"purchaseorder_date"`
+	ExpectedDeliveryDate string                  `db:"expected_delivery_date" json:"expected_delivery_date"`
+	VendorID             string                  `db:"vendor_id" json:"vendor_id"`
+	VendorName           string                  `db:"vendor_name" json:"vendor_name"`
+	ItemCount            float64                 `db:"item_count" json:"item_count"`
+	Subtotal             float64                 `db:"sub_total" json:"sub_total"`
+	TaxTotal             float64                 `db:"tax_total" json:"tax_total"`
+	DiscountType         int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue        float64                 `db:"discount_value" json:"discount_value"`
+	ShippingFee          float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive         int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID        string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount    float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total                float64                 `db:"total" json:"total"`
+	Notes                string                  `db:"notes" json:"notes"`
+	BillingStatus        int                     `db:"billing_status" json:"billing_status"`
+	ReceiveStatus        int                     `db:"receive_status" json:"receive_status"`
+	ApprovalStatus       int                     `db:"approval_status" json:"approval_status"`
+	SalesorderID         string                  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber     string                  `db:"salesorder_number" json:"salesorder_number"`
+	CustomerID           string                  `db:"customer_id" json:"customer_id"`
+	CustomerName         string                  `db:"customer_name" json:"customer_name"`
+	ShippingAddress      setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status               int                     `db:"status" json:"status"`
 }
 
 type PurchaseorderItemResponse struct {
@@ -61,6 +77,10 @@ type PurchaseorderItemResponse struct {
 	ItemID              string  `db:"item_id" json:"item_id"`
 	ItemName            string  `db:"item_name" json:"item_name"`
 	SKU                 string  `db:"sku" json:"sku"`
+	UnitID              string  `db:"unit_id" json:"unit_id"`
+	UnitName            string  `db:"unit_name" json:"unit_name"`
+	UnitQuantity        int     `db:"unit_quantity" json:"unit_quantity"`
+	UnitRate            float64 `db:"unit_rate" json:"unit_rate"`
 	Quantity            int     `db:"quantity" json:"quantity"`
 	Rate                float64 `db:"rate" json:"rate"`
 	TaxID               string  `db:"tax_id" json:"tax_id"`
@@ -88,6 +108,7 @@ type PurchasereceiveNew struct {
 
 type PurchasereceiveItemNew struct {
 	ItemID   string `json:"item_id" binding:"omitempty"`
+	UnitID   string `json:"unit_id" binding:"omitempty"`
 	Quantity int    `json:"quantity" binding:"required"`
 }
 
@@ -143,10 +164,13 @@ type PurchasereceiveDetailResponse struct {
 type BillNew struct {
 	BillNumber     string        `json:"bill_number" binding:"required,min=6,max=64"`
 	BillDate       string        `json:"bill_date" binding:"required,datetime=2006-01-02"`
-	DueDate        string        `json:"due_date" binding:"required,datetime=2006-01-02"`
+	DueDate        string        `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	PaymentTermID  string        `json:"payment_term_id" binding:"omitempty,max=64"`
 	DiscountType   int           `json:"discount_type" binding:"omitempty,oneof=1 2"`
 	DiscountValue  float64       `json:"discount_value" binding:"omitempty"`
 	ShippingFee    float64       `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID  string        `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive   int           `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
 	Notes          string        `json:"notes" binding:"omitempty"`
 	Items          []BillItemNew `json:"items" binding:"required"`
 	OrganizationID string        `json:"organiztion_id" swaggerignore:"true"`
@@ -157,6 +181,7 @@ type BillNew struct {
 type BillItemNew struct {
 	PurchaseorderItemID string  `json:"purchaseorder_item_id" binding:"required"`
 	ItemID              string  `json:"item_id" binding:"required"`
+	UnitID              string  `json:"unit_id" binding:"omitempty"`
 	Quantity            int     `json:"quantity" binding:"required"`
 	Rate                float64 `json:"rate" binding:"required"`
 	TaxID               string  `json:"tax_id" binding:"omitempty"`
@@ -178,7 +203,14 @@ type BillResponse struct {
 	DiscountValue       float64 `db:"discount_value" json:"discount_value"`
 	TaxTotal            float64 `db:"tax_total" json:"tax_total"`
 	ShippingFee         float64 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive        int     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID       string  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount   float64 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	MatchStatus         int     `db:"match_status" json:"match_status"`
 	Total               float64 `db:"total" json:"total"`
+	PaymentTermID       string  `db:"payment_term_id" json:"payment_term_id"`
+	DiscountDate        string  `db:"discount_date" json:"discount_date"`
+	DiscountPercent     float64 `db:"discount_percent" json:"discount_percent"`
 	Notes               string  `db:"notes" json:"notes"`
 	Status              int     `db:"status" json:"status"`
 }
@@ -186,6 +218,7 @@ type BillResponse struct {
 type BillFilter struct {
 	PurchaseorderID string `form:"purchaseorder_id" binding:"omitempty,max=64"`
 	BillNumber      string `form:"bill_number" binding:"omitempty,max=64,min=1"`
+	MatchStatus     int    `form:"match_status" binding:"omitempty,oneof=1 2 3"`
 	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
 	request.PageInfo
 }
@@ -202,6 +235,10 @@ type BillItemResponse struct {
 	ItemID              string  `db:"item_id" json:"item_id"`
 	ItemName            string  `db:"item_name" json:"item_name"`
 	SKU                 string  `db:"sku" json:"sku"`
+	UnitID              string  `db:"unit_id" json:"unit_id"`
+	UnitName            string  `db:"unit_name" json:"unit_name"`
+	UnitQuantity        int     `db:"unit_quantity" json:"unit_quantity"`
+	UnitRate            float64 `db:"unit_rate" json:"unit_rate"`
 	Quantity            int     `db:"quantity" json:"quantity"`
 	Rate                float64 `db:"rate" json:"rate"`
 	TaxID               string  `db:"tax_id" json:"tax_id"`
@@ -216,6 +253,7 @@ type PaymentMadeNew struct {
 	PaymentMadeDate   string  `json:"payment_made_date" binding:"required,datetime=2006-01-02"`
 	PaymentMethodID   string  `json:"payment_method_id" binding:"required,min=6,max=64"`
 	Amount            float64 `json:"amount" binding:"required"`
+	TakeDiscount      int     `json:"take_discount" binding:"omitempty,oneof=1 2"`
 	Notes             string  `json:"notes" binding:"omitempty"`
 	OrganizationID    string  `json:"organiztion_id" swaggerignore:"true"`
 	User              string  `json:"user" swaggerignore:"true"`
@@ -242,6 +280,7 @@ type PaymentMadeResponse struct {
 	PaymentMethodID   string  `db:"payment_method_id" json:"payment_method_id"`
 	PaymentMethodName string  `db:"payment_method_name" json:"payment_method_name"`
 	Amount            float64 `db:"amount" json:"amount"`
+	DiscountAmount    float64 `db:"discount_amount" json:"discount_amount"`
 	Notes             string  `db:"notes" json:"notes"`
 	Status            int     `db:"status" json:"status"`
 }
@@ -249,3 +288,273 @@ type PaymentMadeResponse struct {
 type PaymentMadeID struct {
 	ID string `uri:"id" binding:"required,min=1"`
 }
+
+type LandedCostNew struct {
+	LandedCostNumber   string                   `json:"landed_cost_number" binding:"required,min=6,max=64"`
+	LandedCostDate     string                   `json:"landed_cost_date" binding:"required,datetime=2006-01-02"`
+	VendorID           string                   `json:"vendor_id" binding:"omitempty,max=64"`
+	AllocationMethod   int                      `json:"allocation_method" binding:"required,oneof=1 2 3 4"`
+	IncludeShippingFee int                      `json:"include_shipping_fee" binding:"omitempty,oneof=1 2"`
+	Notes              string                   `json:"notes" binding:"omitempty"`
+	Charges            []LandedCostChargeNew    `json:"charges" binding:"omitempty,dive"`
+	References         []LandedCostReferenceNew `json:"references" binding:"required,min=1,dive"`
+	OrganizationID     string                   `json:"organiztion_id" swaggerignore:"true"`
+	User               string                   `json:"user" swaggerignore:"true"`
+	Email              string                   `json:"email" swaggerignore:"true"`
+}
+
+type LandedCostChargeNew struct {
+	ChargeType  string  `json:"charge_type" binding:"required,oneof=freight duty brokerage other"`
+	Description string  `json:"description" binding:"omitempty,max=255"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+}
+
+type LandedCostReferenceNew struct {
+	ReferenceType string `json:"reference_type" binding:"required,oneof=receive bill"`
+	ReferenceID   string `json:"reference_id" binding:"required,min=1,max=64"`
+}
+
+type LandedCostFilter struct {
+	LandedCostNumber string `form:"landed_cost_number" binding:"omitempty,max=64,min=1"`
+	ReferenceID      string `form:"reference_id" binding:"omitempty,max=64"`
+	OrganizationID   string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type LandedCostResponse struct {
+	OrganizationID   string  `db:"organization_id" json:"organization_id"`
+	LandedCostID     string  `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostNumber string  `db:"landed_cost_number" json:"landed_cost_number"`
+	LandedCostDate   string  `db:"landed_cost_date" json:"landed_cost_date"`
+	VendorID         string  `db:"vendor_id" json:"vendor_id"`
+	VendorName       string  `db:"vendor_name" json:"vendor_name"`
+	AllocationMethod int     `db:"allocation_method" json:"allocation_method"`
+	Amount           float64 `db:"amount" json:"amount"`
+	Notes            string  `db:"notes" json:"notes"`
+	Status           int     `db:"status" json:"status"`
+}
+
+type LandedCostID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type LandedCostAllocationResponse struct {
+	OrganizationID         string  `db:"organization_id" json:"organization_id"`
+	LandedCostID           string  `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostAllocationID string  `db:"landed_cost_allocation_id" json:"landed_cost_allocation_id"`
+	ReferenceType          string  `db:"reference_type" json:"reference_type"`
+	ReferenceID            string  `db:"reference_id" json:"reference_id"`
+	ReferenceItemID        string  `db:"reference_item_id" json:"reference_item_id"`
+	ItemID                 string  `db:"item_id" json:"item_id"`
+	ItemName               string  `db:"item_name" json:"item_name"`
+	SKU                    string  `db:"sku" json:"sku"`
+	Quantity               int     `db:"quantity" json:"quantity"`
+	Basis                  float64 `db:"basis" json:"basis"`
+	Amount                 float64 `db:"amount" json:"amount"`
+	UnitCost               float64 `db:"unit_cost" json:"unit_cost"`
+	QuantityConsumed       int     `db:"quantity_consumed" json:"quantity_consumed"`
+	CogsAdjustment         float64 `db:"cogs_adjustment" json:"cogs_adjustment"`
+	Status                 int     `db:"status" json:"status"`
+}
+
+type LandedCostBatchResponse struct {
+	LandedCostBatchID      string  `db:"landed_cost_batch_id" json:"landed_cost_batch_id"`
+	LandedCostAllocationID string  `db:"landed_cost_allocation_id" json:"landed_cost_allocation_id"`
+	BatchID                string  `db:"batch_id" json:"batch_id"`
+	UnitCost               float64 `db:"unit_cost" json:"unit_cost"`
+}
+
+type MatchPolicyNew struct {
+	Name              string  `json:"name" binding:"required,min=1,max=64"`
+	VendorID          string  `json:"vendor_id" binding:"omitempty,max=64"`
+	MatchType         int     `json:"match_type" binding:"required,oneof=2 3"`
+	QuantityTolerance float64 `json:"quantity_tolerance" binding:"omitempty,min=0,max=100"`
+	PriceTolerance    float64 `json:"price_tolerance" binding:"omitempty,min=0,max=100"`
+	Status            int     `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID    string  `json:"organiztion_id" swaggerignore:"true"`
+	User              string  `json:"user" swaggerignore:"true"`
+	Email             string  `json:"email" swaggerignore:"true"`
+}
+
+type MatchPolicyFilter struct {
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	VendorID       string `form:"vendor_id" binding:"omitempty,max=64"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type MatchPolicyResponse struct {
+	OrganizationID    string  `db:"organization_id" json:"organization_id"`
+	MatchPolicyID     string  `db:"match_policy_id" json:"match_policy_id"`
+	Name              string  `db:"name" json:"name"`
+	VendorID          string  `db:"vendor_id" json:"vendor_id"`
+	VendorName        string  `db:"vendor_name" json:"vendor_name"`
+	MatchType         int     `db:"match_type" json:"match_type"`
+	QuantityTolerance float64 `db:"quantity_tolerance" json:"quantity_tolerance"`
+	PriceTolerance    float64 `db:"price_tolerance" json:"price_tolerance"`
+	Status            int     `db:"status" json:"status"`
+}
+
+type MatchPolicyID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type BillReleaseNew struct {
+	Notes          string `json:"notes" binding:"omitempty,max=255"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type BillMismatchFilter struct {
+	VendorID       string `form:"vendor_id" binding:"omitempty,max=64"`
+	BillID         string `form:"bill_id" binding:"omitempty,max=64"`
+	MatchStatus    int    `form:"match_status" binding:"omitempty,oneof=2 3"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type BillMismatchResponse struct {
+	OrganizationID       string  `db:"organization_id" json:"organization_id"`
+	BillMatchExceptionID string  `db:"bill_match_exception_id" json:"bill_match_exception_id"`
+	BillID               string  `db:"bill_id" json:"bill_id"`
+	BillNumber           string  `db:"bill_number" json:"bill_number"`
+	BillDate             string  `db:"bill_date" json:"bill_date"`
+	PurchaseorderID      string  `db:"purchaseorder_id" json:"purchaseorder_id"`
+	PurchaseorderNumber  string  `db:"purchaseorder_number" json:"purchaseorder_number"`
+	VendorID             string  `db:"vendor_id" json:"vendor_id"`
+	VendorName           string  `db:"vendor_name" json:"vendor_name"`
+	BillItemID           string  `db:"bill_item_id" json:"bill_item_id"`
+	ItemID               string  `db:"item_id" json:"item_id"`
+	ItemName             string  `db:"item_name" json:"item_name"`
+	SKU                  string  `db:"sku" json:"sku"`
+	ExceptionType        string  `db:"exception_type" json:"exception_type"`
+	Expected             float64 `db:"expected" json:"expected"`
+	Actual               float64 `db:"actual" json:"actual"`
+	Variance             float64 `db:"variance" json:"variance"`
+	Tolerance            float64 `db:"tolerance" json:"tolerance"`
+	MatchStatus          int     `db:"match_status" json:"match_status"`
+	Status               int     `db:"status" json:"status"`
+}
+
+type RecurringBillNew struct {
+	ProfileName     string                 `json:"profile_name" binding:"required,min=1,max=64"`
+	PurchaseorderID string                 `json:"purchaseorder_id" binding:"required,min=1,max=64"`
+	BillPrefix      string                 `json:"bill_prefix" binding:"required,min=2,max=32"`
+	Frequency       int                    `json:"frequency" binding:"required,oneof=1 2 3 4 5"`
+	RepeatEvery     int                    `json:"repeat_every" binding:"omitempty,min=1,max=365"`
+	StartDate       string                 `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate         string                 `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Occurrences     int                    `json:"occurrences" binding:"omitempty,min=1"`
+	PaymentTermID   string                 `json:"payment_term_id" binding:"omitempty,max=64"`
+	DiscountType    int                    `json:"discount_type" binding:"omitempty,oneof=1 2"`
+	DiscountValue   float64                `json:"discount_value" binding:"omitempty"`
+	ShippingFee     float64                `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID   string                 `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive    int                    `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
+	Notes           string                 `json:"notes" binding:"omitempty"`
+	Status          int                    `json:"status" binding:"required,oneof=1 2"`
+	Items           []RecurringBillItemNew `json:"items" binding:"required,dive"`
+	OrganizationID  string                 `json:"organiztion_id" swaggerignore:"true"`
+	User            string                 `json:"user" swaggerignore:"true"`
+	Email           string                 `json:"email" swaggerignore:"true"`
+}
+
+type RecurringBillItemNew struct {
+	PurchaseorderItemID string  `json:"purchaseorder_item_id" binding:"required"`
+	ItemID              string  `json:"item_id" binding:"required"`
+	Quantity            int     `json:"quantity" binding:"required,min=1"`
+	Rate                float64 `json:"rate" binding:"required"`
+	TaxID               string  `json:"tax_id" binding:"omitempty"`
+}
+
+type RecurringBillFilter struct {
+	ProfileName     string `form:"profile_name" binding:"omitempty,max=64,min=1"`
+	PurchaseorderID string `form:"purchaseorder_id" binding:"omitempty,max=64"`
+	VendorID        string `form:"vendor_id" binding:"omitempty,max=64"`
+	Status          int    `form:"status" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID  string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type RecurringBillResponse struct {
+	OrganizationID      string  `db:"organization_id" json:"organization_id"`
+	RecurringBillID     string  `db:"recurring_bill_id" json:"recurring_bill_id"`
+	ProfileName         string  `db:"profile_name" json:"profile_name"`
+	PurchaseorderID     string  `db:"purchaseorder_id" json:"purchaseorder_id"`
+	PurchaseorderNumber string  `db:"purchaseorder_number" json:"purchaseorder_number"`
+	VendorID            string  `db:"vendor_id" json:"vendor_id"`
+	VendorName          string  `db:"vendor_name" json:"vendor_name"`
+	BillPrefix          string  `db:"bill_prefix" json:"bill_prefix"`
+	Frequency           int     `db:"frequency" json:"frequency"`
+	RepeatEvery         int     `db:"repeat_every" json:"repeat_every"`
+	StartDate           string  `db:"start_date" json:"start_date"`
+	EndDate             string  `db:"end_date" json:"end_date"`
+	Occurrences         int     `db:"occurrences" json:"occurrences"`
+	GeneratedCount      int     `db:"generated_count" json:"generated_count"`
+	NextDate            string  `db:"next_date" json:"next_date"`
+	PaymentTermID       string  `db:"payment_term_id" json:"payment_term_id"`
+	DiscountType        int     `db:"discount_type" json:"discount_type"`
+	DiscountValue       float64 `db:"discount_value" json:"discount_value"`
+	ShippingFee         float64 `db:"shipping_fee" json:"shipping_fee"`
+	ShippingTaxID       string  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	TaxInclusive        int     `db:"tax_inclusive" json:"tax_inclusive"`
+	Notes               string  `db:"notes" json:"notes"`
+	Status              int     `db:"status" json:"status"`
+	UpdatedBy           string  `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringBillItemResponse struct {
+	OrganizationID      string  `db:"organization_id" json:"organization_id"`
+	RecurringBillID     string  `db:"recurring_bill_id" json:"recurring_bill_id"`
+	RecurringBillItemID string  `db:"recurring_bill_item_id" json:"recurring_bill_item_id"`
+	PurchaseorderItemID string  `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
+	ItemID              string  `db:"item_id" json:"item_id"`
+	ItemName            string  `db:"item_name" json:"item_name"`
+	SKU                 string  `db:"sku" json:"sku"`
+	Quantity            int     `db:"quantity" json:"quantity"`
+	Rate                float64 `db:"rate" json:"rate"`
+	TaxID               string  `db:"tax_id" json:"tax_id"`
+	Status              int     `db:"status" json:"status"`
+}
+
+type RecurringBillRunResponse struct {
+	OrganizationID     string `db:"organization_id" json:"organization_id"`
+	RecurringBillID    string `db:"recurring_bill_id" json:"recurring_bill_id"`
+	RecurringBillRunID string `db:"recurring_bill_run_id" json:"recurring_bill_run_id"`
+	PeriodDate         string `db:"period_date" json:"period_date"`
+	BillID             string `db:"bill_id" json:"bill_id"`
+	BillNumber         string `db:"bill_number" json:"bill_number"`
+	Message            string `db:"message" json:"message"`
+	Status             int    `db:"status" json:"status"`
+}
+
+type RecurringBillID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ReorderItemFilter struct {
+	VendorID       string `form:"vendor_id" binding:"omitempty,max=64"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ReorderItemResponse struct {
+	OrganizationID  string  `db:"organization_id" json:"organization_id"`
+	ItemID          string  `db:"item_id" json:"item_id"`
+	SKU             string  `db:"sku" json:"sku"`
+	Name            string  `db:"name" json:"name"`
+	DefaultVendorID string  `db:"default_vendor_id" json:"default_vendor_id"`
+	VendorName      string  `db:"vendor_name" json:"vendor_name"`
+	CostPrice       float64 `db:"cost_price" json:"cost_price"`
+	ReorderStock    int     `db:"reorder_stock" json:"reorder_stock"`
+	StockAvailable  int     `db:"stock_available" json:"stock_available"`
+	OpenQuantity    int     `db:"open_quantity" json:"open_quantity"`
+	OrderQuantity   int     `db:"order_quantity" json:"order_quantity"`
+}
+
+type ReorderRunResponse struct {
+	PurchaseorderIDs []string `json:"purchaseorder_ids"`
+	NoVendorCount    int      `json:"no_vendor_count"`
+	FailedCount      int      `json:"failed_count"`
+}
@@ -1,30 +1,39 @@
 package purchaseorder
 
-import "time"
+import (
+	"go-api/api/v1/setting"
+	"time"
+)
 
 type Purchaseorder struct {
-	ID                   int64     `db:"id" json:"id"`
-	OrganizationID       string    `db:"organization_id" json:"organization_id"`
-	PurchaseorderID      string    `db:"purchaseorder_id" json:"purchaseorder_id"`
-	PurchaseorderNumber  string    `db:"purchaseorder_number" json:"purchaseorder_number"`
-	PurchaseorderDate    string    `db:"purchaseorder_date" json:"purchaseorder_date"`
-	ExpectedDeliveryDate string    `db:"expected_delivery_date" json:"expected_delivery_date"`
-	VendorID             string    `db:"vendor_id" json:"vendor_id"`
-	ItemCount            int       `db:"item_count" json:"item_count"`
-	Subtotal             float64   `db:"subtotal" json:"subtotal"`
-	DiscountType         int       `db:"discount_type" json:"discount_type"`
-	DiscountValue        float64   `db:"discount_value" json:"discount_value"`
-	TaxTotal             float64   `db:"tax_total" json:"tax_total"`
-	ShippingFee          float64   `db:"shipping_fee" json:"shipping_fee"`
-	Total                float64   `db:"total" json:"total"`
-	Notes                string    `db:"notes" json:"notes"`
-	BillingStatus        int       `db:"billing_status" json:"billing_status"`
-	ReceiveStatus        int       `db:"receive_status" json:"receive_status"`
-	Status               int       `db:"status" json:"status"`
-	Created              time.Time `db:"created" json:"created"`
-	CreatedBy            string    `db:"created_by" json:"created_by"`
-	Updated              time.Time `db:"updated" json:"updated"`
-	UpdatedBy            string    `db:"updated_by" json:"updated_by"`
+	ID                   int64                   `db:"id" json:"id"`
+	OrganizationID       string                  `db:"organization_id" json:"organization_id"`
+	PurchaseorderID      string                  `db:"purchaseorder_id" json:"purchaseorder_id"`
+	PurchaseorderNumber  string                  `db:"purchaseorder_number" json:"purchaseorder_number"`
+	PurchaseorderDate    string                  `db:"purchaseorder_date" json:"purchaseorder_date"`
+	ExpectedDeliveryDate string                  `db:"expected_delivery_date" json:"expected_delivery_date"`
+	VendorID             string                  `db:"vendor_id" json:"vendor_id"`
+	ItemCount            int                     `db:"item_count" json:"item_count"`
+	Subtotal             float64                 `db:"subtotal" json:"subtotal"`
+	DiscountType         int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue        float64                 `db:"discount_value" json:"discount_value"`
+	TaxTotal             float64                 `db:"tax_total" json:"tax_total"`
+	ShippingFee          float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive         int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID        string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount    float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total                float64                 `db:"total" json:"total"`
+	Notes                string                  `db:"notes" json:"notes"`
+	BillingStatus        int                     `db:"billing_status" json:"billing_status"`
+	ReceiveStatus        int                     `db:"receive_status" json:"receive_status"`
+	ApprovalStatus       int                     `db:"approval_status" json:"approval_status"`
+	SalesorderID         string                  `db:"salesorder_id" json:"salesorder_id"`       //drop-ship sales order
+	ShippingAddress      setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"` //drop-ship customer address
+	Status               int                     `db:"status" json:"status"`
+	Created              time.Time               `db:"created" json:"created"`
+	CreatedBy            string                  `db:"created_by" json:"created_by"`
+	Updated              time.Time               `db:"updated" json:"updated"`
+	UpdatedBy            string                  `db:"updated_by" json:"updated_by"`
 }
 
 type PurchaseorderItem struct {
@@ -33,8 +42,11 @@ type PurchaseorderItem struct {
 	PurchaseorderID     string    `db:"purchaseorder_id" json:"purchaseorder_id"`
 	PurchaseorderItemID string    `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
 	ItemID              string    `db:"item_id" json:"item_id"`
-	Quantity            int       `db:"quantity" json:"quantity"`
-	Rate                float64   `db:"rate" json:"rate"`
+	UnitID              string    `db:"unit_id" json:"unit_id"`             //unit ordered in
+	UnitQuantity        int       `db:"unit_quantity" json:"unit_quantity"` //quantity in the ordered unit
+	UnitRate            float64   `db:"unit_rate" json:"unit_rate"`         //rate of the ordered unit
+	Quantity            int       `db:"quantity" json:"quantity"`           //quantity in base unit
+	Rate                float64   `db:"rate" json:"rate"`                   //rate of base unit
 	TaxID               string    `db:"tax_id" json:"tax_id"`
 	TaxValue            float64   `db:"tax_value" json:"tax_value"`
 	TaxAmount           float64   `db:"tax_amount" json:"tax_amount"`
@@ -96,27 +108,34 @@ type PurchasereceiveDetail struct {
 }
 
 type Bill struct {
-	ID              int64     `db:"id" json:"id"`
-	OrganizationID  string    `db:"organization_id" json:"organization_id"`
-	BillID          string    `db:"bill_id" json:"bill_id"`
-	PurchaseorderID string    `db:"purchaseorder_id" json:"purchaseorder_id"`
-	BillNumber      string    `db:"bill_number" json:"bill_number"`
-	BillDate        string    `db:"bill_date" json:"bill_date"`
-	DueDate         string    `db:"due_date" json:"due_date"`
-	VendorID        string    `db:"vendor_id" json:"vendor_id"`
-	ItemCount       int       `db:"item_count" json:"item_count"`
-	Subtotal        float64   `db:"subtotal" json:"subtotal"`
-	DiscountType    int       `db:"discount_type" json:"discount_type"`
-	DiscountValue   float64   `db:"discount_value" json:"discount_value"`
-	TaxTotal        float64   `db:"tax_total" json:"tax_total"`
-	ShippingFee     float64   `db:"shipping_fee" json:"shipping_fee"`
-	Total           float64   `db:"total" json:"total"`
-	Notes           string    `db:"notes" json:"notes"`
-	Status          int       `db:"status" json:"status"`
-	Created         time.Time `db:"created" json:"created"`
-	CreatedBy       string    `db:"created_by" json:"created_by"`
-	Updated         time.Time `db:"updated" json:"updated"`
-	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+	ID                int64     `db:"id" json:"id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	BillID            string    `db:"bill_id" json:"bill_id"`
+	PurchaseorderID   string    `db:"purchaseorder_id" json:"purchaseorder_id"`
+	BillNumber        string    `db:"bill_number" json:"bill_number"`
+	BillDate          string    `db:"bill_date" json:"bill_date"`
+	DueDate           string    `db:"due_date" json:"due_date"`
+	VendorID          string    `db:"vendor_id" json:"vendor_id"`
+	ItemCount         int       `db:"item_count" json:"item_count"`
+	Subtotal          float64   `db:"subtotal" json:"subtotal"`
+	DiscountType      int       `db:"discount_type" json:"discount_type"`
+	DiscountValue     float64   `db:"discount_value" json:"discount_value"`
+	TaxTotal          float64   `db:"tax_total" json:"tax_total"`
+	ShippingFee       float64   `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive      int       `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID     string    `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount float64   `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	MatchStatus       int       `db:"match_status" json:"match_status"`
+	Total             float64   `db:"total" json:"total"`
+	PaymentTermID     string    `db:"payment_term_id" json:"payment_term_id"`
+	DiscountDate      string    `db:"discount_date" json:"discount_date"`
+	DiscountPercent   float64   `db:"discount_percent" json:"discount_percent"`
+	Notes             string    `db:"notes" json:"notes"`
+	Status            int       `db:"status" json:"status"`
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
 }
 
 type BillItem struct {
@@ -126,8 +145,11 @@ type BillItem struct {
 	BillItemID          string    `db:"bill_item_id" json:"bill_item_id"`
 	PurchaseorderItemID string    `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
 	ItemID              string    `db:"item_id" json:"item_id"`
-	Quantity            int       `db:"quantity" json:"quantity"`
-	Rate                float64   `db:"rate" json:"rate"`
+	UnitID              string    `db:"unit_id" json:"unit_id"`             //unit billed in
+	UnitQuantity        int       `db:"unit_quantity" json:"unit_quantity"` //quantity in the billed unit
+	UnitRate            float64   `db:"unit_rate" json:"unit_rate"`         //rate of the billed unit
+	Quantity            int       `db:"quantity" json:"quantity"`           //quantity in base unit
+	Rate                float64   `db:"rate" json:"rate"`                   //rate of base unit
 	TaxID               string    `db:"tax_id" json:"tax_id"`
 	TaxValue            float64   `db:"tax_value" json:"tax_value"`
 	TaxAmount           float64   `db:"tax_amount" json:"tax_amount"`
@@ -149,6 +171,7 @@ type PaymentMade struct {
 	PaymentMadeDate   string    `db:"payment_made_date" json:"payment_made_date"`
 	PaymentMethodID   string    `db:"payment_method_id" json:"payment_method_id"`
 	Amount            float64   `db:"amount" json:"amount"`
+	DiscountAmount    float64   `db:"discount_amount" json:"discount_amount"`
 	Notes             string    `db:"notes" json:"notes"`
 	Status            int       `db:"status" json:"status"`
 	Created           time.Time `db:"created" json:"created"`
@@ -156,3 +179,188 @@ type PaymentMade struct {
 	Updated           time.Time `db:"updated" json:"updated"`
 	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
 }
+
+type LandedCost struct {
+	ID               int64     `db:"id" json:"id"`
+	OrganizationID   string    `db:"organization_id" json:"organization_id"`
+	LandedCostID     string    `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostNumber string    `db:"landed_cost_number" json:"landed_cost_number"`
+	LandedCostDate   string    `db:"landed_cost_date" json:"landed_cost_date"`
+	VendorID         string    `db:"vendor_id" json:"vendor_id"`
+	AllocationMethod int       `db:"allocation_method" json:"allocation_method"`
+	Amount           float64   `db:"amount" json:"amount"`
+	Notes            string    `db:"notes" json:"notes"`
+	Status           int       `db:"status" json:"status"`
+	Created          time.Time `db:"created" json:"created"`
+	CreatedBy        string    `db:"created_by" json:"created_by"`
+	Updated          time.Time `db:"updated" json:"updated"`
+	UpdatedBy        string    `db:"updated_by" json:"updated_by"`
+}
+
+type LandedCostCharge struct {
+	ID                 int64     `db:"id" json:"id"`
+	OrganizationID     string    `db:"organization_id" json:"organization_id"`
+	LandedCostID       string    `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostChargeID string    `db:"landed_cost_charge_id" json:"landed_cost_charge_id"`
+	ChargeType         string    `db:"charge_type" json:"charge_type"`
+	PurchaseorderID    string    `db:"purchaseorder_id" json:"purchaseorder_id"`
+	Description        string    `db:"description" json:"description"`
+	Amount             float64   `db:"amount" json:"amount"`
+	Status             int       `db:"status" json:"status"`
+	Created            time.Time `db:"created" json:"created"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	Updated            time.Time `db:"updated" json:"updated"`
+	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
+}
+
+type LandedCostReference struct {
+	ID                    int64     `db:"id" json:"id"`
+	OrganizationID        string    `db:"organization_id" json:"organization_id"`
+	LandedCostID          string    `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostReferenceID string    `db:"landed_cost_reference_id" json:"landed_cost_reference_id"`
+	ReferenceType         string    `db:"reference_type" json:"reference_type"`
+	ReferenceID           string    `db:"reference_id" json:"reference_id"`
+	PurchaseorderID       string    `db:"purchaseorder_id" json:"purchaseorder_id"`
+	Status                int       `db:"status" json:"status"`
+	Created               time.Time `db:"created" json:"created"`
+	CreatedBy             string    `db:"created_by" json:"created_by"`
+	Updated               time.Time `db:"updated" json:"updated"`
+	UpdatedBy             string    `db:"updated_by" json:"updated_by"`
+}
+
+type LandedCostAllocation struct {
+	ID                     int64     `db:"id" json:"id"`
+	OrganizationID         string    `db:"organization_id" json:"organization_id"`
+	LandedCostID           string    `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostAllocationID string    `db:"landed_cost_allocation_id" json:"landed_cost_allocation_id"`
+	ReferenceType          string    `db:"reference_type" json:"reference_type"`
+	ReferenceID            string    `db:"reference_id" json:"reference_id"`
+	ReferenceItemID        string    `db:"reference_item_id" json:"reference_item_id"`
+	ItemID                 string    `db:"item_id" json:"item_id"`
+	Quantity               int       `db:"quantity" json:"quantity"`
+	Basis                  float64   `db:"basis" json:"basis"`
+	Amount                 float64   `db:"amount" json:"amount"`
+	UnitCost               float64   `db:"unit_cost" json:"unit_cost"`
+	QuantityConsumed       int       `db:"quantity_consumed" json:"quantity_consumed"`
+	CogsAdjustment         float64   `db:"cogs_adjustment" json:"cogs_adjustment"`
+	Status                 int       `db:"status" json:"status"`
+	Created                time.Time `db:"created" json:"created"`
+	CreatedBy              string    `db:"created_by" json:"created_by"`
+	Updated                time.Time `db:"updated" json:"updated"`
+	UpdatedBy              string    `db:"updated_by" json:"updated_by"`
+}
+
+type LandedCostBatch struct {
+	ID                     int64     `db:"id" json:"id"`
+	OrganizationID         string    `db:"organization_id" json:"organization_id"`
+	LandedCostID           string    `db:"landed_cost_id" json:"landed_cost_id"`
+	LandedCostAllocationID string    `db:"landed_cost_allocation_id" json:"landed_cost_allocation_id"`
+	LandedCostBatchID      string    `db:"landed_cost_batch_id" json:"landed_cost_batch_id"`
+	BatchID                string    `db:"batch_id" json:"batch_id"`
+	Quantity               int       `db:"quantity" json:"quantity"`
+	QuantityConsumed       int       `db:"quantity_consumed" json:"quantity_consumed"`
+	UnitCost               float64   `db:"unit_cost" json:"unit_cost"`
+	CogsAdjustment         float64   `db:"cogs_adjustment" json:"cogs_adjustment"`
+	Status                 int       `db:"status" json:"status"`
+	Created                time.Time `db:"created" json:"created"`
+	CreatedBy              string    `db:"created_by" json:"created_by"`
+	Updated                time.Time `db:"updated" json:"updated"`
+	UpdatedBy              string    `db:"updated_by" json:"updated_by"`
+}
+
+type MatchPolicy struct {
+	ID                int64     `db:"id" json:"id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	MatchPolicyID     string    `db:"match_policy_id" json:"match_policy_id"`
+	Name              string    `db:"name" json:"name"`
+	VendorID          string    `db:"vendor_id" json:"vendor_id"`
+	MatchType         int       `db:"match_type" json:"match_type"`
+	QuantityTolerance float64   `db:"quantity_tolerance" json:"quantity_tolerance"`
+	PriceTolerance    float64   `db:"price_tolerance" json:"price_tolerance"`
+	Status            int       `db:"status" json:"status"`
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
+}
+
+type BillMatchException struct {
+	ID                   int64     `db:"id" json:"id"`
+	OrganizationID       string    `db:"organization_id" json:"organization_id"`
+	BillMatchExceptionID string    `db:"bill_match_exception_id" json:"bill_match_exception_id"`
+	BillID               string    `db:"bill_id" json:"bill_id"`
+	BillItemID           string    `db:"bill_item_id" json:"bill_item_id"`
+	PurchaseorderItemID  string    `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
+	ItemID               string    `db:"item_id" json:"item_id"`
+	ExceptionType        string    `db:"exception_type" json:"exception_type"`
+	Expected             float64   `db:"expected" json:"expected"`
+	Actual               float64   `db:"actual" json:"actual"`
+	Variance             float64   `db:"variance" json:"variance"`
+	Tolerance            float64   `db:"tolerance" json:"tolerance"`
+	Status               int       `db:"status" json:"status"`
+	Created              time.Time `db:"created" json:"created"`
+	CreatedBy            string    `db:"created_by" json:"created_by"`
+	Updated              time.Time `db:"updated" json:"updated"`
+	UpdatedBy            string    `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringBill struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	RecurringBillID string    `db:"recurring_bill_id" json:"recurring_bill_id"`
+	ProfileName     string    `db:"profile_name" json:"profile_name"`
+	PurchaseorderID string    `db:"purchaseorder_id" json:"purchaseorder_id"`
+	VendorID        string    `db:"vendor_id" json:"vendor_id"`
+	BillPrefix      string    `db:"bill_prefix" json:"bill_prefix"`
+	Frequency       int       `db:"frequency" json:"frequency"`
+	RepeatEvery     int       `db:"repeat_every" json:"repeat_every"`
+	StartDate       string    `db:"start_date" json:"start_date"`
+	EndDate         string    `db:"end_date" json:"end_date"`
+	Occurrences     int       `db:"occurrences" json:"occurrences"`
+	GeneratedCount  int       `db:"generated_count" json:"generated_count"`
+	NextDate        string    `db:"next_date" json:"next_date"`
+	PaymentTermID   string    `db:"payment_term_id" json:"payment_term_id"`
+	DiscountType    int       `db:"discount_type" json:"discount_type"`
+	DiscountValue   float64   `db:"discount_value" json:"discount_value"`
+	ShippingFee     float64   `db:"shipping_fee" json:"shipping_fee"`
+	ShippingTaxID   string    `db:"shipping_tax_id" json:"shipping_tax_id"`
+	TaxInclusive    int       `db:"tax_inclusive" json:"tax_inclusive"`
+	Notes           string    `db:"notes" json:"notes"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringBillItem struct {
+	ID                  int64     `db:"id" json:"id"`
+	OrganizationID      string    `db:"organization_id" json:"organization_id"`
+	RecurringBillID     string    `db:"recurring_bill_id" json:"recurring_bill_id"`
+	RecurringBillItemID string    `db:"recurring_bill_item_id" json:"recurring_bill_item_id"`
+	PurchaseorderItemID string    `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
+	ItemID              string    `db:"item_id" json:"item_id"`
+	Quantity            int       `db:"quantity" json:"quantity"`
+	Rate                float64   `db:"rate" json:"rate"`
+	TaxID               string    `db:"tax_id" json:"tax_id"`
+	Status              int       `db:"status" json:"status"`
+	Created             time.Time `db:"created" json:"created"`
+	CreatedBy           string    `db:"created_by" json:"created_by"`
+	Updated             time.Time `db:"updated" json:"updated"`
+	UpdatedBy           string    `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringBillRun struct {
+	ID                 int64     `db:"id" json:"id"`
+	OrganizationID     string    `db:"organization_id" json:"organization_id"`
+	RecurringBillID    string    `db:"recurring_bill_id" json:"recurring_bill_id"`
+	RecurringBillRunID string    `db:"recurring_bill_run_id" json:"recurring_bill_run_id"`
+	PeriodDate         string    `db:"period_date" json:"period_date"`
+	BillID             string    `db:"bill_id" json:"bill_id"`
+	Message            string    `db:"message" json:"message"`
+	Status             int       `db:"status" json:"status"`
+	Created            time.Time `db:"created" json:"created"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	Updated            time.Time `db:"updated" json:"updated"`
+	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,17 @@
+package purchaseorder
+
+import (
+	"go-api/core/job"
+	"time"
+)
+
+func Schedule(r *job.Runner) {
+	r.Every("RecurringBill", time.Hour, func() error {
+		purchaseorderService := NewPurchaseorderService()
+		return purchaseorderService.RunDueRecurringBills()
+	})
+	r.Every("ReorderAlert", time.Hour, func() error {
+		purchaseorderService := NewPurchaseorderService()
+		return purchaseorderService.RunReorderAlerts()
+	})
+}
@@ -0,0 +1,93 @@
+package purchaseorder
+
+import (
+	"errors"
+	"go-api/api/v1/setting"
+	"go-api/core/database"
+	"go-api/core/pdf"
+	"strconv"
+	"strings"
+)
+
+func (s *purchaseorderService) GetPurchaseorderPDF(organizationID, purchaseorderID string) ([]byte, string, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	purchaseorder, err := query.GetPurchaseorderByID(organizationID, purchaseorderID)
+	if err != nil {
+		msg := "purchase order not exist"
+		return nil, "", errors.New(msg)
+	}
+	items, err := query.GetPurchaseorderItemList(purchaseorderID)
+	if err != nil {
+		return nil, "", err
+	}
+	settingQuery := setting.NewSettingQuery(db)
+	vendor, err := settingQuery.GetVendorByID(organizationID, purchaseorder.VendorID)
+	if err != nil {
+		msg := "vendor not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingService := setting.NewSettingService()
+	layout, err := settingService.NewDocumentLayout(organizationID, 2)
+	if err != nil {
+		return nil, "", err
+	}
+	layout.Fields = []pdf.Field{
+		{Label: "Purchase Order #", Value: purchaseorder.PurchaseorderNumber},
+		{Label: "Order Date", Value: purchaseorder.PurchaseorderDate},
+		{Label: "Expected Delivery", Value: purchaseorder.ExpectedDeliveryDate},
+	}
+	contact := strings.TrimSpace(vendor.ContactSalutation + " " + vendor.ContactFirstName + " " + vendor.ContactLastName)
+	cityLine := strings.TrimSpace(strings.Join([]string{vendor.City, vendor.State, vendor.Zip}, " "))
+	deliverTo := strings.Split(layout.CompanyInfo, "\n")
+	if purchaseorder.ShippingAddress.Name != "" {
+		// drop-ship orders go straight to the customer, to the shipping
+		// address of the sales order
+		deliverTo = purchaseorder.ShippingAddress.Lines()
+	} else if purchaseorder.CustomerID != "" {
+		// drop-ship orders created before the address was saved
+		customer, err := settingQuery.GetCustomerByID(organizationID, purchaseorder.CustomerID)
+		if err != nil {
+			msg := "customer not exist"
+			return nil, "", errors.New(msg)
+		}
+		customerContact := strings.TrimSpace(customer.ContactSalutation + " " + customer.ContactFirstName + " " + customer.ContactLastName)
+		customerCity := strings.TrimSpace(strings.Join([]string{customer.City, customer.State, customer.Zip}, " "))
+		deliverTo = []string{customer.Name, customerContact, customer.Address1, customer.Address2, customerCity, customer.Country, customer.Phone}
+	}
+	layout.Blocks = []pdf.Block{
+		{Title: "Vendor", Lines: []string{vendor.Name, contact, vendor.Address1, vendor.Address2, cityLine, vendor.Country, vendor.Phone}},
+		{Title: "Deliver To", Lines: deliverTo},
+	}
+	table := pdf.Table{Columns: []pdf.Column{
+		{Title: "Item", Width: 4},
+		{Title: "SKU", Width: 2},
+		{Title: "Qty", Width: 1, Align: pdf.AlignRight},
+		{Title: "Rate", Width: 1.5, Align: pdf.AlignRight},
+		{Title: "Tax", Width: 1.5, Align: pdf.AlignRight},
+		{Title: "Amount", Width: 1.5, Align: pdf.AlignRight},
+	}}
+	for _, item := range *items {
+		table.Rows = append(table.Rows, []string{item.ItemName, item.SKU, strconv.Itoa(item.Quantity), pdf.Money(item.Rate), pdf.Money(item.TaxAmount), pdf.Money(item.Amount)})
+	}
+	layout.Tables = []pdf.Table{table}
+	discount := purchaseorder.DiscountValue
+	if purchaseorder.DiscountType == 1 {
+		discount = (purchaseorder.Subtotal + purchaseorder.TaxTotal) * purchaseorder.DiscountValue / 100
+	}
+	layout.Totals = []pdf.Field{{Label: "Subtotal", Value: pdf.Money(purchaseorder.Subtotal)}}
+	if discount != 0 {
+		layout.Totals = append(layout.Totals, pdf.Field{Label: "Discount", Value: "-" + pdf.Money(discount)})
+	}
+	layout.Totals = append(layout.Totals,
+		pdf.Field{Label: "Tax", Value: pdf.Money(purchaseorder.TaxTotal)},
+		pdf.Field{Label: "Shipping", Value: pdf.Money(purchaseorder.ShippingFee + purchaseorder.ShippingTaxAmount)},
+		pdf.Field{Label: "Total", Value: pdf.Money(purchaseorder.Total)},
+	)
+	layout.Notes = purchaseorder.Notes
+	data, err := layout.Render()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, purchaseorder.PurchaseorderNumber + ".pdf", nil
+}
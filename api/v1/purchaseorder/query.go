@@ -33,14 +33,27 @@ func (r *purchaseorderQuery) GetPurchaseorderByID(organizationID, id string) (*P
 	p.discount_type,
 	p.discount_value,
 	p.shipping_fee,
+	p.tax_inclusive,
+	p.shipping_tax_id,
+	p.shipping_tax_amount,
 	p.total,
 	p.notes,
 	p.receive_status,
 	p.billing_status,
+	p.approval_status,
+	p.salesorder_id,
+	IFNULL(so.salesorder_number, "") as salesorder_number,
+	IFNULL(so.customer_id, "") as customer_id,
+	IFNULL(c.name, "") as customer_name,
+	p.shipping_address,
 	p.status
 	FROM p_purchaseorders p
 	LEFT JOIN s_vendors v
 	ON p.vendor_id = v.vendor_id
+	LEFT JOIN s_salesorders so
+	ON p.salesorder_id = so.salesorder_id
+	LEFT JOIN s_customers c
+	ON so.customer_id = c.customer_id
 	WHERE p.organization_id = ? AND p.purchaseorder_id = ? AND p.status > 0
 	`, organizationID, id)
 	return &purchaseorder, err
@@ -54,6 +67,9 @@ func (r *purchaseorderQuery) GetPurchaseorderCount(filter PurchaseorderFilter) (
 	if v := filter.PurchaseorderNumber; v != "" {
 		where, args = append(where, "purchaseorder_number like ?"), append(args, "%"+v+"%")
 	}
+	if v := filter.SalesorderID; v != "" {
+		where, args = append(where, "salesorder_id = ?"), append(args, v)
+	}
 	var count int
 	err := r.conn.Get(&count, `
 		SELECT count(1) as count
@@ -70,6 +86,9 @@ func (r *purchaseorderQuery) GetPurchaseorderList(filter PurchaseorderFilter) (*
 	if v := filter.PurchaseorderNumber; v != "" {
 		where, args = append(where, "p.purchaseorder_number like ?"), append(args, "%"+v+"%")
 	}
+	if v := filter.SalesorderID; v != "" {
+		where, args = append(where, "p.salesorder_id = ?"), append(args, v)
+	}
 	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
 	args = append(args, filter.PageSize)
 	var purchaseorders []PurchaseorderResponse
@@ -88,14 +107,27 @@ func (r *purchaseorderQuery) GetPurchaseorderList(filter PurchaseorderFilter) (*
 		p.discount_type,
 		p.discount_value,
 		p.shipping_fee,
+		p.tax_inclusive,
+		p.shipping_tax_id,
+		p.shipping_tax_amount,
 		p.total,
 		p.notes,
 		p.receive_status,
 		p.billing_status,
+		p.approval_status,
+		p.salesorder_id,
+		IFNULL(so.salesorder_number, "") as salesorder_number,
+		IFNULL(so.customer_id, "") as customer_id,
+		IFNULL(c.name, "") as customer_name,
+		p.shipping_address,
 		p.status
 		FROM p_purchaseorders p
 		LEFT JOIN s_vendors v
 		ON p.vendor_id = v.vendor_id
+		LEFT JOIN s_salesorders so
+		ON p.salesorder_id = so.salesorder_id
+		LEFT JOIN s_customers c
+		ON so.customer_id = c.customer_id
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
 	`, args...)
@@ -112,6 +144,10 @@ func (r *purchaseorderQuery) GetPurchaseorderItemList(purchaseorderID string) (*
 		p.item_id,
 		i.name as item_name,
 		i.sku as sku,
+		p.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		p.unit_quantity,
+		p.unit_rate,
 		p.quantity,
 		p.rate,
 		p.tax_id,
@@ -124,6 +160,8 @@ func (r *purchaseorderQuery) GetPurchaseorderItemList(purchaseorderID string) (*
 		FROM p_purchaseorder_items p
 		LEFT JOIN i_items i
 		ON p.item_id = i.item_id
+		LEFT JOIN s_units u
+		ON p.unit_id = u.unit_id
 		WHERE p.purchaseorder_id = ? AND p.status > 0 
 	`, purchaseorderID)
 	return &purchaseorders, err
@@ -253,6 +291,9 @@ func (r *purchaseorderQuery) GetBillCount(filter BillFilter) (int, error) {
 	if v := filter.PurchaseorderID; v != "" {
 		where, args = append(where, "purchaseorder_id = ?"), append(args, v)
 	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "match_status = ?"), append(args, v)
+	}
 	var count int
 	err := r.conn.Get(&count, `
 		SELECT count(1) as count
@@ -272,6 +313,9 @@ func (r *purchaseorderQuery) GetBillList(filter BillFilter) (*[]BillResponse, er
 	if v := filter.PurchaseorderID; v != "" {
 		where, args = append(where, "i.purchaseorder_id = ?"), append(args, v)
 	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "i.match_status = ?"), append(args, v)
+	}
 	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
 	args = append(args, filter.PageSize)
 	var purchaseorders []BillResponse
@@ -292,7 +336,14 @@ func (r *purchaseorderQuery) GetBillList(filter BillFilter) (*[]BillResponse, er
 		i.discount_value,
 		i.tax_total,
 		i.shipping_fee,
+		i.tax_inclusive,
+		i.shipping_tax_id,
+		i.shipping_tax_amount,
+		i.match_status,
 		i.total,
+		i.payment_term_id,
+		i.discount_date,
+		i.discount_percent,
 		i.notes,
 		i.status
 		FROM p_bills i
@@ -325,7 +376,14 @@ func (r *purchaseorderQuery) GetBillByID(organizationID, id string) (*BillRespon
 	i.discount_value,
 	i.tax_total,
 	i.shipping_fee,
+	i.tax_inclusive,
+	i.shipping_tax_id,
+	i.shipping_tax_amount,
+	i.match_status,
 	i.total,
+	i.payment_term_id,
+	i.discount_date,
+	i.discount_percent,
 	i.notes,
 	i.status
 	FROM p_bills i
@@ -349,6 +407,10 @@ func (r *purchaseorderQuery) GetBillItemList(billID string) (*[]BillItemResponse
 		s.item_id,
 		i.name as item_name,
 		i.sku as sku,
+		s.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		s.unit_quantity,
+		s.unit_rate,
 		s.quantity,
 		s.rate,
 		s.tax_id,
@@ -359,6 +421,8 @@ func (r *purchaseorderQuery) GetBillItemList(billID string) (*[]BillItemResponse
 		FROM p_bill_items s
 		LEFT JOIN i_items i
 		ON s.item_id = i.item_id
+		LEFT JOIN s_units u
+		ON s.unit_id = u.unit_id
 		WHERE s.bill_id = ? AND i.status > 0 
 	`, billID)
 	return &billItems, err
@@ -367,7 +431,7 @@ func (r *purchaseorderQuery) GetBillItemList(billID string) (*[]BillItemResponse
 func (r *purchaseorderQuery) GeBillPaymentMade(organizationID, billID string) (float64, error) {
 	var count float64
 	err := r.conn.Get(&count, `
-		SELECT IFNULL(SUM(amount),0) FROM p_payment_mades 
+		SELECT IFNULL(SUM(amount + discount_amount),0) FROM p_payment_mades 
 		WHERE organization_id = ? AND bill_id = ? AND status > 0 
 		`, organizationID, billID)
 	return count, err
@@ -425,6 +489,7 @@ func (r *purchaseorderQuery) GetPaymentMadeList(filter PaymentMadeFilter) (*[]Pa
 		p.payment_method_id,
 		IFNULL(pm.name, "") as payment_method_name,
 		p.amount,
+		p.discount_amount,
 		p.notes,
 		p.status
 		FROM p_payment_mades p
@@ -439,3 +504,553 @@ func (r *purchaseorderQuery) GetPaymentMadeList(filter PaymentMadeFilter) (*[]Pa
 	`, args...)
 	return &paymentReceiveds, err
 }
+
+// landed cost
+func (r *purchaseorderQuery) GetLandedCostCount(filter LandedCostFilter) (int, error) {
+	where, args := []string{"l.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "l.organization_id = ?"), append(args, v)
+	}
+	if v := filter.LandedCostNumber; v != "" {
+		where, args = append(where, "l.landed_cost_number like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.ReferenceID; v != "" {
+		where, args = append(where, "l.landed_cost_id IN (SELECT landed_cost_id FROM p_landed_cost_references WHERE reference_id = ? AND status > 0)"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM p_landed_costs l
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *purchaseorderQuery) GetLandedCostList(filter LandedCostFilter) (*[]LandedCostResponse, error) {
+	where, args := []string{"l.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "l.organization_id = ?"), append(args, v)
+	}
+	if v := filter.LandedCostNumber; v != "" {
+		where, args = append(where, "l.landed_cost_number like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.ReferenceID; v != "" {
+		where, args = append(where, "l.landed_cost_id IN (SELECT landed_cost_id FROM p_landed_cost_references WHERE reference_id = ? AND status > 0)"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var landedCosts []LandedCostResponse
+	err := r.conn.Select(&landedCosts, `
+		SELECT 
+		l.organization_id,
+		l.landed_cost_id,
+		l.landed_cost_number,
+		l.landed_cost_date,
+		l.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		l.allocation_method,
+		l.amount,
+		l.notes,
+		l.status
+		FROM p_landed_costs l
+		LEFT JOIN s_vendors v
+		ON l.vendor_id = v.vendor_id
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &landedCosts, err
+}
+
+func (r *purchaseorderQuery) GetLandedCostByID(organizationID, id string) (*LandedCostResponse, error) {
+	var landedCost LandedCostResponse
+	err := r.conn.Get(&landedCost, `
+		SELECT 
+		l.organization_id,
+		l.landed_cost_id,
+		l.landed_cost_number,
+		l.landed_cost_date,
+		l.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		l.allocation_method,
+		l.amount,
+		l.notes,
+		l.status
+		FROM p_landed_costs l
+		LEFT JOIN s_vendors v
+		ON l.vendor_id = v.vendor_id
+		WHERE l.organization_id = ? AND l.landed_cost_id = ? AND l.status > 0
+	`, organizationID, id)
+	return &landedCost, err
+}
+
+func (r *purchaseorderQuery) GetLandedCostAllocationList(organizationID, landedCostID string) (*[]LandedCostAllocationResponse, error) {
+	var allocations []LandedCostAllocationResponse
+	err := r.conn.Select(&allocations, `
+		SELECT
+		a.organization_id,
+		a.landed_cost_id,
+		a.landed_cost_allocation_id,
+		a.reference_type,
+		a.reference_id,
+		a.reference_item_id,
+		a.item_id,
+		i.name as item_name,
+		i.sku as sku,
+		a.quantity,
+		a.basis,
+		a.amount,
+		a.unit_cost,
+		a.quantity_consumed,
+		a.cogs_adjustment,
+		a.status
+		FROM p_landed_cost_allocations a
+		LEFT JOIN i_items i
+		ON a.item_id = i.item_id
+		WHERE a.organization_id = ? AND a.landed_cost_id = ? AND a.status > 0
+	`, organizationID, landedCostID)
+	return &allocations, err
+}
+
+// match policy
+func (r *purchaseorderQuery) GetMatchPolicyCount(filter MatchPolicyFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "vendor_id = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM p_match_policies
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *purchaseorderQuery) GetMatchPolicyList(filter MatchPolicyFilter) (*[]MatchPolicyResponse, error) {
+	where, args := []string{"m.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "m.organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "m.name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "m.vendor_id = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var policies []MatchPolicyResponse
+	err := r.conn.Select(&policies, `
+		SELECT
+		m.organization_id,
+		m.match_policy_id,
+		m.name,
+		m.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		m.match_type,
+		m.quantity_tolerance,
+		m.price_tolerance,
+		m.status
+		FROM p_match_policies m
+		LEFT JOIN s_vendors v
+		ON m.vendor_id = v.vendor_id
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &policies, err
+}
+
+func (r *purchaseorderQuery) GetMatchPolicyByID(organizationID, id string) (*MatchPolicyResponse, error) {
+	var policy MatchPolicyResponse
+	err := r.conn.Get(&policy, `
+		SELECT
+		m.organization_id,
+		m.match_policy_id,
+		m.name,
+		m.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		m.match_type,
+		m.quantity_tolerance,
+		m.price_tolerance,
+		m.status
+		FROM p_match_policies m
+		LEFT JOIN s_vendors v
+		ON m.vendor_id = v.vendor_id
+		WHERE m.organization_id = ? AND m.match_policy_id = ? AND m.status > 0
+	`, organizationID, id)
+	return &policy, err
+}
+
+func (r *purchaseorderQuery) GetBillMismatchCount(filter BillMismatchFilter) (int, error) {
+	where, args := []string{"e.status > 0", "b.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "e.organization_id = ?"), append(args, v)
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "b.vendor_id = ?"), append(args, v)
+	}
+	if v := filter.BillID; v != "" {
+		where, args = append(where, "e.bill_id = ?"), append(args, v)
+	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "b.match_status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM p_bill_match_exceptions e
+		LEFT JOIN p_bills b
+		ON e.bill_id = b.bill_id
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *purchaseorderQuery) GetBillMismatchList(filter BillMismatchFilter) (*[]BillMismatchResponse, error) {
+	where, args := []string{"e.status > 0", "b.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "e.organization_id = ?"), append(args, v)
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "b.vendor_id = ?"), append(args, v)
+	}
+	if v := filter.BillID; v != "" {
+		where, args = append(where, "e.bill_id = ?"), append(args, v)
+	}
+	if v := filter.MatchStatus; v != 0 {
+		where, args = append(where, "b.match_status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var mismatches []BillMismatchResponse
+	err := r.conn.Select(&mismatches, `
+		SELECT
+		e.organization_id,
+		e.bill_match_exception_id,
+		e.bill_id,
+		b.bill_number,
+		b.bill_date,
+		b.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		b.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		e.bill_item_id,
+		e.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		e.exception_type,
+		e.expected,
+		e.actual,
+		e.variance,
+		e.tolerance,
+		b.match_status,
+		e.status
+		FROM p_bill_match_exceptions e
+		LEFT JOIN p_bills b
+		ON e.bill_id = b.bill_id
+		LEFT JOIN p_purchaseorders p
+		ON b.purchaseorder_id = p.purchaseorder_id
+		LEFT JOIN s_vendors v
+		ON b.vendor_id = v.vendor_id
+		LEFT JOIN i_items i
+		ON e.item_id = i.item_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY b.bill_date DESC
+		LIMIT ?, ?
+	`, args...)
+	return &mismatches, err
+}
+
+// recurring bill
+
+func (r *purchaseorderQuery) GetRecurringBillCount(filter RecurringBillFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ProfileName; v != "" {
+		where, args = append(where, "profile_name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.PurchaseorderID; v != "" {
+		where, args = append(where, "purchaseorder_id = ?"), append(args, v)
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "vendor_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM p_recurring_bills
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *purchaseorderQuery) GetRecurringBillList(filter RecurringBillFilter) (*[]RecurringBillResponse, error) {
+	where, args := []string{"r.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "r.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ProfileName; v != "" {
+		where, args = append(where, "r.profile_name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.PurchaseorderID; v != "" {
+		where, args = append(where, "r.purchaseorder_id = ?"), append(args, v)
+	}
+	if v := filter.VendorID; v != "" {
+		where, args = append(where, "r.vendor_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "r.status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var recurringBills []RecurringBillResponse
+	err := r.conn.Select(&recurringBills, `
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.profile_name,
+		r.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		r.vendor_id,
+		IFNULL(c.name, "") as vendor_name,
+		r.bill_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM p_recurring_bills r
+		LEFT JOIN p_purchaseorders p
+		ON r.purchaseorder_id = p.purchaseorder_id
+		LEFT JOIN s_vendors c
+		ON r.vendor_id = c.vendor_id
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &recurringBills, err
+}
+
+func (r *purchaseorderQuery) GetRecurringBillByID(organizationID, id string) (*RecurringBillResponse, error) {
+	var recurringBill RecurringBillResponse
+	err := r.conn.Get(&recurringBill, `
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.profile_name,
+		r.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		r.vendor_id,
+		IFNULL(c.name, "") as vendor_name,
+		r.bill_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM p_recurring_bills r
+		LEFT JOIN p_purchaseorders p
+		ON r.purchaseorder_id = p.purchaseorder_id
+		LEFT JOIN s_vendors c
+		ON r.vendor_id = c.vendor_id
+		WHERE r.organization_id = ? AND r.recurring_bill_id = ? AND r.status > 0
+	`, organizationID, id)
+	return &recurringBill, err
+}
+
+func (r *purchaseorderQuery) GetDueRecurringBillList(date string) (*[]RecurringBillResponse, error) {
+	var recurringBills []RecurringBillResponse
+	err := r.conn.Select(&recurringBills, `
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.profile_name,
+		r.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		r.vendor_id,
+		IFNULL(c.name, "") as vendor_name,
+		r.bill_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM p_recurring_bills r
+		LEFT JOIN p_purchaseorders p
+		ON r.purchaseorder_id = p.purchaseorder_id
+		LEFT JOIN s_vendors c
+		ON r.vendor_id = c.vendor_id
+		WHERE r.status = 1 AND r.next_date <= ?
+	`, date)
+	return &recurringBills, err
+}
+
+func (r *purchaseorderQuery) GetRecurringBillItemList(organizationID, recurringBillID string) (*[]RecurringBillItemResponse, error) {
+	var recurringBillItems []RecurringBillItemResponse
+	err := r.conn.Select(&recurringBillItems, `
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.recurring_bill_item_id,
+		r.purchaseorder_item_id,
+		r.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		r.quantity,
+		r.rate,
+		r.tax_id,
+		r.status
+		FROM p_recurring_bill_items r
+		LEFT JOIN i_items i
+		ON r.item_id = i.item_id
+		WHERE r.organization_id = ? AND r.recurring_bill_id = ? AND r.status > 0
+	`, organizationID, recurringBillID)
+	return &recurringBillItems, err
+}
+
+func (r *purchaseorderQuery) GetRecurringBillRunList(organizationID, recurringBillID string) (*[]RecurringBillRunResponse, error) {
+	var recurringBillRuns []RecurringBillRunResponse
+	err := r.conn.Select(&recurringBillRuns, `
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.recurring_bill_run_id,
+		r.period_date,
+		r.bill_id,
+		IFNULL(i.bill_number, "") as bill_number,
+		r.message,
+		r.status
+		FROM p_recurring_bill_runs r
+		LEFT JOIN p_bills i
+		ON r.bill_id = i.bill_id
+		WHERE r.organization_id = ? AND r.recurring_bill_id = ? AND r.status > 0
+		ORDER BY r.period_date DESC
+	`, organizationID, recurringBillID)
+	return &recurringBillRuns, err
+}
+
+//reorder
+
+// reorderItemQuery lists the purchasable items whose available stock plus the
+// quantity still to receive on draft and issued purchase orders is below the
+// reorder point
+func reorderItemQuery(organizationID, vendorID string) (string, []interface{}) {
+	where, args := []string{"i.status = 1", "i.item_type <> 2", "i.reorder_stock > 0"}, []interface{}{}
+	if organizationID != "" {
+		where, args = append(where, "i.organization_id = ?"), append(args, organizationID)
+	}
+	if vendorID != "" {
+		where, args = append(where, "i.default_vendor_id = ?"), append(args, vendorID)
+	}
+	return `
+		SELECT
+		r.organization_id,
+		r.item_id,
+		r.sku,
+		r.name,
+		r.default_vendor_id,
+		r.vendor_name,
+		r.cost_price,
+		r.reorder_stock,
+		r.stock_available,
+		r.open_quantity,
+		r.reorder_stock - r.stock_available - r.open_quantity as order_quantity
+		FROM (
+			SELECT
+			i.id,
+			i.organization_id,
+			i.item_id,
+			i.sku,
+			i.name,
+			i.default_vendor_id,
+			IFNULL(v.name, "") as vendor_name,
+			i.cost_price,
+			i.reorder_stock,
+			i.stock_available,
+			IFNULL((
+				SELECT SUM(pi.quantity - pi.quantity_received)
+				FROM p_purchaseorder_items pi
+				INNER JOIN p_purchaseorders p
+				ON pi.purchaseorder_id = p.purchaseorder_id
+				WHERE pi.item_id = i.item_id AND pi.status > 0 AND p.status IN (1, 2)
+			), 0) as open_quantity
+			FROM i_items i
+			LEFT JOIN s_vendors v
+			ON i.default_vendor_id = v.vendor_id
+			WHERE ` + strings.Join(where, " AND ") + `
+		) r
+		WHERE r.stock_available + r.open_quantity < r.reorder_stock
+	`, args
+}
+
+func (r *purchaseorderQuery) GetReorderItemCount(filter ReorderItemFilter) (int, error) {
+	query, args := reorderItemQuery(filter.OrganizationID, filter.VendorID)
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM (`+query+`) c
+	`, args...)
+	return count, err
+}
+
+func (r *purchaseorderQuery) GetReorderItemList(filter ReorderItemFilter) (*[]ReorderItemResponse, error) {
+	query, args := reorderItemQuery(filter.OrganizationID, filter.VendorID)
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var reorderItems []ReorderItemResponse
+	err := r.conn.Select(&reorderItems, query+`
+		ORDER BY r.id ASC
+		LIMIT ?, ?
+	`, args...)
+	return &reorderItems, err
+}
+
+// GetReorderItems returns every item to reorder, of all organizations when
+// organizationID is empty
+func (r *purchaseorderQuery) GetReorderItems(organizationID string) (*[]ReorderItemResponse, error) {
+	query, args := reorderItemQuery(organizationID, "")
+	var reorderItems []ReorderItemResponse
+	err := r.conn.Select(&reorderItems, query+`
+		ORDER BY r.organization_id ASC, r.id ASC
+	`, args...)
+	return &reorderItems, err
+}
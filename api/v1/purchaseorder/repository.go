@@ -31,6 +31,9 @@ func (r purchaseorderRepository) CreatePurchaseorderItem(info PurchaseorderItem)
 			purchaseorder_item_id,
 			purchaseorder_id,
 			item_id,
+			unit_id,
+			unit_quantity,
+			unit_rate,
 			quantity,
 			rate,
 			amount,
@@ -46,14 +49,17 @@ func (r purchaseorderRepository) CreatePurchaseorderItem(info PurchaseorderItem)
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.PurchaseorderItemID, info.PurchaseorderID, info.ItemID, info.Quantity, info.Rate, info.Amount, info.TaxID, info.TaxValue, info.TaxAmount, info.QuantityReceived, info.QuantityBilled, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.PurchaseorderItemID, info.PurchaseorderID, info.ItemID, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.Amount, info.TaxID, info.TaxValue, info.TaxAmount, info.QuantityReceived, info.QuantityBilled, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
 func (r purchaseorderRepository) UpdatePurchaseorderItem(id string, info PurchaseorderItem) error {
 	_, err := r.tx.Exec(`
 		UPDATE p_purchaseorder_items set
+		unit_id = ?,
+		unit_quantity = ?,
+		unit_rate = ?,
 		quantity = ?,
 		rate = ?,
 		tax_id = ?,
@@ -64,7 +70,7 @@ func (r purchaseorderRepository) UpdatePurchaseorderItem(id string, info Purchas
 		updated = ?,
 		updated_by =?
 		WHERE purchaseorder_item_id = ?
-	`, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -84,10 +90,16 @@ func (r purchaseorderRepository) CreatePurchaseorder(info Purchaseorder) error {
 			discount_value,
 			tax_total,
 			shipping_fee,
+			tax_inclusive,
+			shipping_tax_id,
+			shipping_tax_amount,
 			total,
 			notes,
 			receive_status,
 			billing_status,
+			approval_status,
+			salesorder_id,
+			shipping_address,
 			status,
 			created,
 			created_by,
@@ -95,8 +107,8 @@ func (r purchaseorderRepository) CreatePurchaseorder(info Purchaseorder) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.PurchaseorderID, info.PurchaseorderNumber, info.PurchaseorderDate, info.ExpectedDeliveryDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.ReceiveStatus, info.BillingStatus, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.PurchaseorderID, info.PurchaseorderNumber, info.PurchaseorderDate, info.ExpectedDeliveryDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.ReceiveStatus, info.BillingStatus, info.ApprovalStatus, info.SalesorderID, info.ShippingAddress, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -116,14 +128,19 @@ func (r *purchaseorderRepository) GetPurchaseorderByID(organizationID, purchaseo
 		discount_type,
 		discount_value,
 		shipping_fee,
+		tax_inclusive,
+		shipping_tax_id,
+		shipping_tax_amount,
 		total,
 		notes,
 		receive_status,
 		billing_status,
+		approval_status,
+		salesorder_id,
 		status
 		FROM p_purchaseorders WHERE organization_id = ? AND purchaseorder_id = ? AND status > 0 LIMIT 1
 	`, organizationID, purchaseorderID)
-	err := row.Scan(&res.PurchaseorderID, &res.OrganizationID, &res.PurchaseorderNumber, &res.PurchaseorderDate, &res.ExpectedDeliveryDate, &res.VendorID, &res.ItemCount, &res.TaxTotal, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.Total, &res.Notes, &res.ReceiveStatus, &res.BillingStatus, &res.Status)
+	err := row.Scan(&res.PurchaseorderID, &res.OrganizationID, &res.PurchaseorderNumber, &res.PurchaseorderDate, &res.ExpectedDeliveryDate, &res.VendorID, &res.ItemCount, &res.TaxTotal, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.TaxInclusive, &res.ShippingTaxID, &res.ShippingTaxAmount, &res.Total, &res.Notes, &res.ReceiveStatus, &res.BillingStatus, &res.ApprovalStatus, &res.SalesorderID, &res.Status)
 	return &res, err
 }
 
@@ -168,6 +185,9 @@ func (r *purchaseorderRepository) UpdatePurchaseorder(id string, info Purchaseor
 		discount_type = ?,
 		discount_value = ?,
 		shipping_fee = ?,
+		tax_inclusive = ?,
+		shipping_tax_id = ?,
+		shipping_tax_amount = ?,
 		total = ?,
 		notes = ?,
 		receive_status = ?,
@@ -176,7 +196,18 @@ func (r *purchaseorderRepository) UpdatePurchaseorder(id string, info Purchaseor
 		updated = ?,
 		updated_by = ?
 		WHERE purchaseorder_id = ?
-	`, info.PurchaseorderNumber, info.PurchaseorderDate, info.ExpectedDeliveryDate, info.VendorID, info.ItemCount, info.Subtotal, info.TaxTotal, info.DiscountType, info.DiscountValue, info.ShippingFee, info.Total, info.Notes, info.ReceiveStatus, info.BillingStatus, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.PurchaseorderNumber, info.PurchaseorderDate, info.ExpectedDeliveryDate, info.VendorID, info.ItemCount, info.Subtotal, info.TaxTotal, info.DiscountType, info.DiscountValue, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.ReceiveStatus, info.BillingStatus, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *purchaseorderRepository) UpdatePurchaseorderApprovalStatus(id string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update p_purchaseorders SET
+		approval_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE purchaseorder_id = ?
+	`, status, time.Now(), byUser, id)
 	return err
 }
 
@@ -478,6 +509,9 @@ func (r purchaseorderRepository) CreateBillItem(info BillItem) error {
 			bill_item_id,
 			purchaseorder_item_id,
 			item_id,
+			unit_id,
+			unit_quantity,
+			unit_rate,
 			quantity,
 			rate,
 			tax_id,
@@ -491,8 +525,8 @@ func (r purchaseorderRepository) CreateBillItem(info BillItem) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.BillID, info.BillItemID, info.PurchaseorderItemID, info.ItemID, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BillID, info.BillItemID, info.PurchaseorderItemID, info.ItemID, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -513,7 +547,14 @@ func (r purchaseorderRepository) CreateBill(info Bill) error {
 			discount_value,
 			tax_total,
 			shipping_fee,
+			tax_inclusive,
+			shipping_tax_id,
+			shipping_tax_amount,
+			match_status,
 			total,
+			payment_term_id,
+			discount_date,
+			discount_percent,
 			notes,
 			status,
 			created,
@@ -522,8 +563,8 @@ func (r purchaseorderRepository) CreateBill(info Bill) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.BillID, info.PurchaseorderID, info.BillNumber, info.BillDate, info.DueDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BillID, info.PurchaseorderID, info.BillNumber, info.BillDate, info.DueDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.MatchStatus, info.Total, info.PaymentTermID, info.DiscountDate, info.DiscountPercent, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -564,7 +605,14 @@ func (r *purchaseorderRepository) GetBillByID(organizationID, id string) (*BillR
 		i.discount_value,
 		i.tax_total,
 		i.shipping_fee,
+		i.tax_inclusive,
+		i.shipping_tax_id,
+		i.shipping_tax_amount,
+		i.match_status,
 		i.total,
+		i.payment_term_id,
+		i.discount_date,
+		i.discount_percent,
 		i.notes,
 		i.status
 		FROM p_bills i
@@ -574,7 +622,7 @@ func (r *purchaseorderRepository) GetBillByID(organizationID, id string) (*BillR
 		ON i.vendor_id = c.vendor_id
 		WHERE i.organization_id = ? AND i.bill_id = ? AND s.status > 0  LIMIT 1
 	`, organizationID, id)
-	err := row.Scan(&res.OrganizationID, &res.PurchaseorderID, &res.PurchaseorderNumber, &res.BillID, &res.BillNumber, &res.BillDate, &res.DueDate, &res.VendorID, &res.VendorName, &res.ItemCount, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.TaxTotal, &res.ShippingFee, &res.Total, &res.Notes, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.PurchaseorderID, &res.PurchaseorderNumber, &res.BillID, &res.BillNumber, &res.BillDate, &res.DueDate, &res.VendorID, &res.VendorName, &res.ItemCount, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.TaxTotal, &res.ShippingFee, &res.TaxInclusive, &res.ShippingTaxID, &res.ShippingTaxAmount, &res.MatchStatus, &res.Total, &res.PaymentTermID, &res.DiscountDate, &res.DiscountPercent, &res.Notes, &res.Status)
 	return &res, err
 }
 
@@ -673,13 +721,20 @@ func (r purchaseorderRepository) UpdateBill(id string, info Bill) error {
 		discount_value = ?,
 		tax_total = ?,
 		shipping_fee = ?,
+		tax_inclusive = ?,
+		shipping_tax_id = ?,
+		shipping_tax_amount = ?,
+		match_status = ?,
 		total = ?,
+		payment_term_id = ?,
+		discount_date = ?,
+		discount_percent = ?,
 		notes = ?,
 		status = ?,
 		updated = ?,
 		updated_by =?
 		WHERE bill_id = ?
-	`, info.BillNumber, info.BillDate, info.DueDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.BillNumber, info.BillDate, info.DueDate, info.VendorID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.MatchStatus, info.Total, info.PaymentTermID, info.DiscountDate, info.DiscountPercent, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -706,6 +761,7 @@ func (r purchaseorderRepository) CreatePaymentMade(info PaymentMade) error {
 			payment_made_date,
 			payment_method_id,
 			amount,
+			discount_amount,
 			notes,
 			status,
 			created,
@@ -714,14 +770,21 @@ func (r purchaseorderRepository) CreatePaymentMade(info PaymentMade) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.BillID, info.VendorID, info.PaymentMadeID, info.PaymentMadeNumber, info.PaymentMadeDate, info.PaymentMethodID, info.Amount, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BillID, info.VendorID, info.PaymentMadeID, info.PaymentMadeNumber, info.PaymentMadeDate, info.PaymentMethodID, info.Amount, info.DiscountAmount, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
 func (r *purchaseorderRepository) GetBillPaidCount(organizationID, billID string) (float64, error) {
 	var sum float64
-	row := r.tx.QueryRow("SELECT IFNULL(SUM(amount), 0) FROM p_payment_mades WHERE organization_id = ? AND bill_id = ? AND status > 0", organizationID, billID)
+	row := r.tx.QueryRow("SELECT IFNULL(SUM(amount + discount_amount), 0) FROM p_payment_mades WHERE organization_id = ? AND bill_id = ? AND status > 0", organizationID, billID)
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+func (r *purchaseorderRepository) GetBillDiscountTaken(organizationID, billID, paymentMadeID string) (float64, error) {
+	var sum float64
+	row := r.tx.QueryRow("SELECT IFNULL(SUM(discount_amount), 0) FROM p_payment_mades WHERE organization_id = ? AND bill_id = ? AND payment_made_id != ? AND status > 0", organizationID, billID, paymentMadeID)
 	err := row.Scan(&sum)
 	return sum, err
 }
@@ -752,6 +815,7 @@ func (r *purchaseorderRepository) GetPaymentMadeByID(organizationID, id string)
 		p.payment_method_id,
 		IFNULL(pm.name, "") as payment_method_name,
 		p.amount,
+		p.discount_amount,
 		p.notes,
 		p.status
 		FROM p_payment_mades p
@@ -763,7 +827,7 @@ func (r *purchaseorderRepository) GetPaymentMadeByID(organizationID, id string)
 		ON p.payment_method_id = pm.payment_method_id
 		WHERE p.organization_id = ? AND p.payment_made_id = ? AND p.status > 0  LIMIT 1
 	`, organizationID, id)
-	err := row.Scan(&res.OrganizationID, &res.BillID, &res.BillNumber, &res.VendorID, &res.VendorName, &res.PaymentMadeID, &res.PaymentMadeNumber, &res.PaymentMadeDate, &res.PaymentMethodID, &res.PaymentMethodName, &res.Amount, &res.Notes, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.BillID, &res.BillNumber, &res.VendorID, &res.VendorName, &res.PaymentMadeID, &res.PaymentMadeNumber, &res.PaymentMadeDate, &res.PaymentMethodID, &res.PaymentMethodName, &res.Amount, &res.DiscountAmount, &res.Notes, &res.Status)
 	return &res, err
 }
 
@@ -774,15 +838,23 @@ func (r purchaseorderRepository) UpdatePaymentMade(id string, info PaymentMade)
 		payment_made_date = ?,
 		payment_method_id = ?,
 		amount = ?,
+		discount_amount = ?,
 		notes = ?,
 		status = ?,
 		updated = ?,
 		updated_by =?
 		WHERE payment_made_id = ?
-	`, info.PaymentMadeNumber, info.PaymentMadeDate, info.PaymentMethodID, info.Amount, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.PaymentMadeNumber, info.PaymentMadeDate, info.PaymentMethodID, info.Amount, info.DiscountAmount, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
+func (r *purchaseorderRepository) GetPaymentMadeMatchedCount(organizationID, paymentMadeID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM b_bank_transaction_matches WHERE organization_id = ? AND payment_type = 2 AND payment_id = ? AND status > 0", organizationID, paymentMadeID)
+	err := row.Scan(&count)
+	return count, err
+}
+
 func (r *purchaseorderRepository) DeletePaymentMade(id, byUser string) error {
 	_, err := r.tx.Exec(`
 		Update p_payment_mades SET
@@ -793,3 +865,696 @@ func (r *purchaseorderRepository) DeletePaymentMade(id, byUser string) error {
 	`, time.Now(), byUser, id)
 	return err
 }
+
+// landed cost
+
+func (r *purchaseorderRepository) CheckLandedCostNumberConfict(landedCostID, organizationID, landedCostNumber string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM p_landed_costs WHERE organization_id = ? AND landed_cost_id != ? AND landed_cost_number = ? AND status > 0 ", organizationID, landedCostID, landedCostNumber)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r purchaseorderRepository) CreateLandedCost(info LandedCost) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_landed_costs
+		(
+			organization_id,
+			landed_cost_id,
+			landed_cost_number,
+			landed_cost_date,
+			vendor_id,
+			allocation_method,
+			amount,
+			notes,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LandedCostID, info.LandedCostNumber, info.LandedCostDate, info.VendorID, info.AllocationMethod, info.Amount, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r purchaseorderRepository) CreateLandedCostCharge(info LandedCostCharge) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_landed_cost_charges
+		(
+			organization_id,
+			landed_cost_id,
+			landed_cost_charge_id,
+			charge_type,
+			purchaseorder_id,
+			description,
+			amount,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LandedCostID, info.LandedCostChargeID, info.ChargeType, info.PurchaseorderID, info.Description, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r purchaseorderRepository) CreateLandedCostReference(info LandedCostReference) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_landed_cost_references
+		(
+			organization_id,
+			landed_cost_id,
+			landed_cost_reference_id,
+			reference_type,
+			reference_id,
+			purchaseorder_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LandedCostID, info.LandedCostReferenceID, info.ReferenceType, info.ReferenceID, info.PurchaseorderID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r purchaseorderRepository) CreateLandedCostAllocation(info LandedCostAllocation) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_landed_cost_allocations
+		(
+			organization_id,
+			landed_cost_id,
+			landed_cost_allocation_id,
+			reference_type,
+			reference_id,
+			reference_item_id,
+			item_id,
+			quantity,
+			basis,
+			amount,
+			unit_cost,
+			quantity_consumed,
+			cogs_adjustment,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LandedCostID, info.LandedCostAllocationID, info.ReferenceType, info.ReferenceID, info.ReferenceItemID, info.ItemID, info.Quantity, info.Basis, info.Amount, info.UnitCost, info.QuantityConsumed, info.CogsAdjustment, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r purchaseorderRepository) CreateLandedCostBatch(info LandedCostBatch) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_landed_cost_batches
+		(
+			organization_id,
+			landed_cost_id,
+			landed_cost_allocation_id,
+			landed_cost_batch_id,
+			batch_id,
+			quantity,
+			quantity_consumed,
+			unit_cost,
+			cogs_adjustment,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.LandedCostID, info.LandedCostAllocationID, info.LandedCostBatchID, info.BatchID, info.Quantity, info.QuantityConsumed, info.UnitCost, info.CogsAdjustment, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) GetLandedCostByID(organizationID, landedCostID string) (*LandedCostResponse, error) {
+	var res LandedCostResponse
+	row := r.tx.QueryRow(`
+		SELECT 
+		l.organization_id,
+		l.landed_cost_id,
+		l.landed_cost_number,
+		l.landed_cost_date,
+		l.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		l.allocation_method,
+		l.amount,
+		l.notes,
+		l.status
+		FROM p_landed_costs l
+		LEFT JOIN s_vendors v
+		ON l.vendor_id = v.vendor_id
+		WHERE l.organization_id = ? AND l.landed_cost_id = ? AND l.status > 0 LIMIT 1
+	`, organizationID, landedCostID)
+	err := row.Scan(&res.OrganizationID, &res.LandedCostID, &res.LandedCostNumber, &res.LandedCostDate, &res.VendorID, &res.VendorName, &res.AllocationMethod, &res.Amount, &res.Notes, &res.Status)
+	return &res, err
+}
+
+func (r *purchaseorderRepository) GetLandedCostBatchList(organizationID, landedCostID string) (*[]LandedCostBatchResponse, error) {
+	var batches []LandedCostBatchResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		landed_cost_batch_id,
+		landed_cost_allocation_id,
+		batch_id,
+		unit_cost
+		FROM p_landed_cost_batches
+		WHERE organization_id = ? AND landed_cost_id = ? AND status > 0
+	`, organizationID, landedCostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res LandedCostBatchResponse
+		err = rows.Scan(&res.LandedCostBatchID, &res.LandedCostAllocationID, &res.BatchID, &res.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, res)
+	}
+	return &batches, err
+}
+
+func (r *purchaseorderRepository) DeleteLandedCost(id, byUser string) error {
+	tables := []string{"p_landed_costs", "p_landed_cost_charges", "p_landed_cost_references", "p_landed_cost_allocations", "p_landed_cost_batches"}
+	for _, table := range tables {
+		_, err := r.tx.Exec(`
+			UPDATE `+table+` SET
+			status = -1,
+			updated = ?,
+			updated_by = ?
+			WHERE landed_cost_id = ?
+		`, time.Now(), byUser, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *purchaseorderRepository) GetLandedCostReferenceCount(organizationID, referenceType, referenceID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM p_landed_cost_references WHERE organization_id = ? AND reference_type = ? AND reference_id = ? AND status > 0", organizationID, referenceType, referenceID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *purchaseorderRepository) GetLandedCostShippingCount(organizationID, purchaseorderID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM p_landed_cost_charges WHERE organization_id = ? AND purchaseorder_id = ? AND charge_type = 'shipping' AND status > 0", organizationID, purchaseorderID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *purchaseorderRepository) GetPurchasereceiveItemList(organizationID, purchasereceiveID string) (*[]PurchasereceiveItemResponse, error) {
+	var receiveItems []PurchasereceiveItemResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		p.organization_id,
+		p.purchasereceive_id,
+		p.purchaseorder_item_id,
+		p.purchasereceive_item_id,
+		p.item_id,
+		i.name as item_name,
+		i.sku as sku,
+		p.quantity,
+		p.status
+		FROM p_purchasereceive_items p
+		LEFT JOIN i_items i
+		ON p.item_id = i.item_id
+		WHERE p.organization_id = ? AND p.purchasereceive_id = ? AND p.status > 0
+	`, organizationID, purchasereceiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res PurchasereceiveItemResponse
+		err = rows.Scan(&res.OrganizationID, &res.PurchasereceiveID, &res.PurchaseorderItemID, &res.PurchasereceiveItemID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Status)
+		if err != nil {
+			return nil, err
+		}
+		receiveItems = append(receiveItems, res)
+	}
+	return &receiveItems, err
+}
+
+// GetPurchasereceiveItemListByPOItem lists the receives of a purchase order
+// item in the order they were made
+func (r *purchaseorderRepository) GetPurchasereceiveItemListByPOItem(organizationID, purchaseorderItemID string) (*[]PurchasereceiveItemResponse, error) {
+	var receiveItems []PurchasereceiveItemResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		organization_id,
+		purchasereceive_id,
+		purchaseorder_item_id,
+		purchasereceive_item_id,
+		item_id,
+		quantity,
+		status
+		FROM p_purchasereceive_items
+		WHERE organization_id = ? AND purchaseorder_item_id = ? AND status > 0
+		ORDER BY id ASC
+	`, organizationID, purchaseorderItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res PurchasereceiveItemResponse
+		err = rows.Scan(&res.OrganizationID, &res.PurchasereceiveID, &res.PurchaseorderItemID, &res.PurchasereceiveItemID, &res.ItemID, &res.Quantity, &res.Status)
+		if err != nil {
+			return nil, err
+		}
+		receiveItems = append(receiveItems, res)
+	}
+	return &receiveItems, err
+}
+
+// GetBilledQuantityBefore sums the quantity of a purchase order item billed
+// on the bill lines created before the given one
+func (r *purchaseorderRepository) GetBilledQuantityBefore(organizationID, purchaseorderItemID, billItemID string) (int, error) {
+	var quantity int
+	row := r.tx.QueryRow(`
+		SELECT IFNULL(SUM(quantity), 0)
+		FROM p_bill_items
+		WHERE organization_id = ? AND purchaseorder_item_id = ? AND status > 0
+		AND id < (SELECT id FROM p_bill_items WHERE bill_item_id = ?)
+	`, organizationID, purchaseorderItemID, billItemID)
+	err := row.Scan(&quantity)
+	return quantity, err
+}
+
+// match policy
+
+func (r *purchaseorderRepository) CheckMatchPolicyConfict(matchPolicyID, organizationID, vendorID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM p_match_policies WHERE organization_id = ? AND match_policy_id != ? AND vendor_id = ? AND status > 0 ", organizationID, matchPolicyID, vendorID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r purchaseorderRepository) CreateMatchPolicy(info MatchPolicy) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_match_policies
+		(
+			organization_id,
+			match_policy_id,
+			name,
+			vendor_id,
+			match_type,
+			quantity_tolerance,
+			price_tolerance,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.MatchPolicyID, info.Name, info.VendorID, info.MatchType, info.QuantityTolerance, info.PriceTolerance, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) GetMatchPolicyByID(organizationID, matchPolicyID string) (*MatchPolicyResponse, error) {
+	var res MatchPolicyResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		m.organization_id,
+		m.match_policy_id,
+		m.name,
+		m.vendor_id,
+		IFNULL(v.name, "") as vendor_name,
+		m.match_type,
+		m.quantity_tolerance,
+		m.price_tolerance,
+		m.status
+		FROM p_match_policies m
+		LEFT JOIN s_vendors v
+		ON m.vendor_id = v.vendor_id
+		WHERE m.organization_id = ? AND m.match_policy_id = ? AND m.status > 0 LIMIT 1
+	`, organizationID, matchPolicyID)
+	err := row.Scan(&res.OrganizationID, &res.MatchPolicyID, &res.Name, &res.VendorID, &res.VendorName, &res.MatchType, &res.QuantityTolerance, &res.PriceTolerance, &res.Status)
+	return &res, err
+}
+
+// GetVendorMatchPolicy returns the active policy of the vendor, or the organization default one.
+func (r *purchaseorderRepository) GetVendorMatchPolicy(organizationID, vendorID string) (*MatchPolicyResponse, error) {
+	var res MatchPolicyResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		organization_id,
+		match_policy_id,
+		name,
+		vendor_id,
+		match_type,
+		quantity_tolerance,
+		price_tolerance,
+		status
+		FROM p_match_policies
+		WHERE organization_id = ? AND (vendor_id = ? OR vendor_id = "") AND status = 1
+		ORDER BY vendor_id DESC
+		LIMIT 1
+	`, organizationID, vendorID)
+	err := row.Scan(&res.OrganizationID, &res.MatchPolicyID, &res.Name, &res.VendorID, &res.MatchType, &res.QuantityTolerance, &res.PriceTolerance, &res.Status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &res, err
+}
+
+func (r *purchaseorderRepository) UpdateMatchPolicy(id string, info MatchPolicy) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_match_policies SET
+		name = ?,
+		vendor_id = ?,
+		match_type = ?,
+		quantity_tolerance = ?,
+		price_tolerance = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE match_policy_id = ?
+	`, info.Name, info.VendorID, info.MatchType, info.QuantityTolerance, info.PriceTolerance, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *purchaseorderRepository) DeleteMatchPolicy(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_match_policies SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE match_policy_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r purchaseorderRepository) CreateBillMatchException(info BillMatchException) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_bill_match_exceptions
+		(
+			organization_id,
+			bill_match_exception_id,
+			bill_id,
+			bill_item_id,
+			purchaseorder_item_id,
+			item_id,
+			exception_type,
+			expected,
+			actual,
+			variance,
+			tolerance,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.BillMatchExceptionID, info.BillID, info.BillItemID, info.PurchaseorderItemID, info.ItemID, info.ExceptionType, info.Expected, info.Actual, info.Variance, info.Tolerance, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) DeleteBillMatchException(billID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_bill_match_exceptions SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE bill_id = ? AND status > 0
+	`, time.Now(), byUser, billID)
+	return err
+}
+
+func (r *purchaseorderRepository) UpdateBillMatchStatus(id string, matchStatus int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_bills SET
+		match_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE bill_id = ?
+	`, matchStatus, time.Now(), byUser, id)
+	return err
+}
+
+// recurring bill
+
+func (r *purchaseorderRepository) CheckRecurringBillConfict(recurringBillID, organizationID, profileName string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM p_recurring_bills WHERE organization_id = ? AND recurring_bill_id != ? AND profile_name = ? AND status > 0 ", organizationID, recurringBillID, profileName)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *purchaseorderRepository) CreateRecurringBill(info RecurringBill) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_recurring_bills
+		(
+			organization_id,
+			recurring_bill_id,
+			profile_name,
+			purchaseorder_id,
+			vendor_id,
+			bill_prefix,
+			frequency,
+			repeat_every,
+			start_date,
+			end_date,
+			occurrences,
+			generated_count,
+			next_date,
+			payment_term_id,
+			discount_type,
+			discount_value,
+			shipping_fee,
+			shipping_tax_id,
+			tax_inclusive,
+			notes,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringBillID, info.ProfileName, info.PurchaseorderID, info.VendorID, info.BillPrefix, info.Frequency, info.RepeatEvery, info.StartDate, info.EndDate, info.Occurrences, info.GeneratedCount, info.NextDate, info.PaymentTermID, info.DiscountType, info.DiscountValue, info.ShippingFee, info.ShippingTaxID, info.TaxInclusive, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) GetRecurringBillByID(organizationID, id string) (*RecurringBillResponse, error) {
+	var res RecurringBillResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		r.organization_id,
+		r.recurring_bill_id,
+		r.profile_name,
+		r.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		r.vendor_id,
+		IFNULL(c.name, "") as vendor_name,
+		r.bill_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM p_recurring_bills r
+		LEFT JOIN p_purchaseorders p
+		ON r.purchaseorder_id = p.purchaseorder_id
+		LEFT JOIN s_vendors c
+		ON r.vendor_id = c.vendor_id
+		WHERE r.organization_id = ? AND r.recurring_bill_id = ? AND r.status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.RecurringBillID, &res.ProfileName, &res.PurchaseorderID, &res.PurchaseorderNumber, &res.VendorID, &res.VendorName, &res.BillPrefix, &res.Frequency, &res.RepeatEvery, &res.StartDate, &res.EndDate, &res.Occurrences, &res.GeneratedCount, &res.NextDate, &res.PaymentTermID, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.ShippingTaxID, &res.TaxInclusive, &res.Notes, &res.Status, &res.UpdatedBy)
+	return &res, err
+}
+
+func (r *purchaseorderRepository) UpdateRecurringBill(id string, info RecurringBill) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_recurring_bills SET
+		profile_name = ?,
+		purchaseorder_id = ?,
+		vendor_id = ?,
+		bill_prefix = ?,
+		frequency = ?,
+		repeat_every = ?,
+		start_date = ?,
+		end_date = ?,
+		occurrences = ?,
+		next_date = ?,
+		payment_term_id = ?,
+		discount_type = ?,
+		discount_value = ?,
+		shipping_fee = ?,
+		shipping_tax_id = ?,
+		tax_inclusive = ?,
+		notes = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_bill_id = ?
+	`, info.ProfileName, info.PurchaseorderID, info.VendorID, info.BillPrefix, info.Frequency, info.RepeatEvery, info.StartDate, info.EndDate, info.Occurrences, info.NextDate, info.PaymentTermID, info.DiscountType, info.DiscountValue, info.ShippingFee, info.ShippingTaxID, info.TaxInclusive, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *purchaseorderRepository) UpdateRecurringBillSchedule(id, nextDate string, generatedCount, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_recurring_bills SET
+		next_date = ?,
+		generated_count = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_bill_id = ?
+	`, nextDate, generatedCount, status, time.Now(), byUser, id)
+	return err
+}
+
+func (r *purchaseorderRepository) DeleteRecurringBill(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_recurring_bills SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_bill_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	return r.DeleteRecurringBillItem(id, byUser)
+}
+
+func (r *purchaseorderRepository) CreateRecurringBillItem(info RecurringBillItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_recurring_bill_items
+		(
+			organization_id,
+			recurring_bill_id,
+			recurring_bill_item_id,
+			purchaseorder_item_id,
+			item_id,
+			quantity,
+			rate,
+			tax_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringBillID, info.RecurringBillItemID, info.PurchaseorderItemID, info.ItemID, info.Quantity, info.Rate, info.TaxID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) DeleteRecurringBillItem(recurringBillID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_recurring_bill_items SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_bill_id = ? AND status > 0
+	`, time.Now(), byUser, recurringBillID)
+	return err
+}
+
+func (r *purchaseorderRepository) GetRecurringBillRunByPeriod(organizationID, recurringBillID, periodDate string) (*RecurringBillRunResponse, error) {
+	var res RecurringBillRunResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, recurring_bill_id, recurring_bill_run_id, period_date, bill_id, message, status
+		FROM p_recurring_bill_runs
+		WHERE organization_id = ? AND recurring_bill_id = ? AND period_date = ? AND status > 0 LIMIT 1
+	`, organizationID, recurringBillID, periodDate)
+	err := row.Scan(&res.OrganizationID, &res.RecurringBillID, &res.RecurringBillRunID, &res.PeriodDate, &res.BillID, &res.Message, &res.Status)
+	return &res, err
+}
+
+func (r *purchaseorderRepository) CreateRecurringBillRun(info RecurringBillRun) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO p_recurring_bill_runs
+		(
+			organization_id,
+			recurring_bill_id,
+			recurring_bill_run_id,
+			period_date,
+			bill_id,
+			message,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringBillID, info.RecurringBillRunID, info.PeriodDate, info.BillID, info.Message, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *purchaseorderRepository) UpdateRecurringBillRun(id, billID, message string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE p_recurring_bill_runs SET
+		bill_id = ?,
+		message = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_bill_run_id = ?
+	`, billID, message, status, time.Now(), byUser, id)
+	return err
+}
+
+func (r *purchaseorderRepository) GetBillIDByNumber(organizationID, billNumber string) (string, error) {
+	var billID string
+	row := r.tx.QueryRow("SELECT bill_id FROM p_bills WHERE organization_id = ? AND bill_number = ? AND status > 0 LIMIT 1", organizationID, billNumber)
+	err := row.Scan(&billID)
+	return billID, err
+}
+
+func (r *purchaseorderRepository) ClearSalesorderItemPurchaseorder(purchaseorderID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_salesorder_items SET
+		purchaseorder_id = "",
+		updated = ?,
+		updated_by = ?
+		WHERE purchaseorder_id = ? AND status > 0
+	`, time.Now(), byUser, purchaseorderID)
+	return err
+}
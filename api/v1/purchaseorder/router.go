@@ -9,7 +9,9 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.GET("/purchaseorders/:id", GetPurchaseorderByID)
 	g.DELETE("/purchaseorders/:id", DeletePurchaseorder)
 	g.GET("/purchaseorders/:id/items", GetPurchaseorderItemList)
+	g.GET("/purchaseorders/:id/pdf", GetPurchaseorderPDF)
 	g.POST("/purchaseorders/:id/issued", IssuePurchaseorder)
+	g.POST("/purchaseorders/:id/approval", ApprovePurchaseorder)
 
 	g.POST("/purchaseorders/:id/receives", NewPurchasereceive)
 	g.GET("/purchasereceives", GetPurchasereceiveList)
@@ -22,6 +24,8 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.GET("/bills/:id/items", GetBillItemList)
 	g.PUT("/bills/:id", UpdateBill)
 	g.DELETE("/bills/:id", DeleteBill)
+	g.POST("/bills/:id/release", ReleaseBill)
+	g.GET("/billmismatches", GetBillMismatchList)
 
 	g.POST("/bills/:id/payments", NewPayment)
 	g.GET("/bills/:id/paid", GeBillPaymentMade)
@@ -29,4 +33,28 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/paymentmades/:id", UpdatePayment)
 	g.DELETE("/paymentmades/:id", DeletePayment)
 
+	g.POST("/landedcosts", NewLandedCost)
+	g.GET("/landedcosts", GetLandedCostList)
+	g.GET("/landedcosts/:id", GetLandedCostByID)
+	g.GET("/landedcosts/:id/allocations", GetLandedCostAllocationList)
+	g.DELETE("/landedcosts/:id", DeleteLandedCost)
+
+	g.POST("/matchpolicies", NewMatchPolicy)
+	g.GET("/matchpolicies", GetMatchPolicyList)
+	g.GET("/matchpolicies/:id", GetMatchPolicyByID)
+	g.PUT("/matchpolicies/:id", UpdateMatchPolicy)
+	g.DELETE("/matchpolicies/:id", DeleteMatchPolicy)
+
+	g.POST("/recurringbills", NewRecurringBill)
+	g.GET("/recurringbills", GetRecurringBillList)
+	g.PUT("/recurringbills/:id", UpdateRecurringBill)
+	g.GET("/recurringbills/:id", GetRecurringBillByID)
+	g.DELETE("/recurringbills/:id", DeleteRecurringBill)
+	g.GET("/recurringbills/:id/items", GetRecurringBillItemList)
+	g.GET("/recurringbills/:id/runs", GetRecurringBillRunList)
+	g.POST("/recurringbills/:id/run", RunRecurringBill)
+
+	g.GET("/reorderitems", GetReorderItemList)
+	g.POST("/reorderitems/run", RunReorder)
+
 }
@@ -1,6 +1,7 @@
 package purchaseorder
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"go-api/api/v1/warehouse"
 	"go-api/core/database"
 	"go-api/core/queue"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/rs/xid"
@@ -30,53 +33,98 @@ func (s *purchaseorderService) NewPurchaseorder(info PurchaseorderNew) (*string,
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	poID, err := CreatePurchaseorder(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "purchaseorder"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = info.User
+	newEvent.ReferenceID = poID
+	newEvent.Description = "Purchase Order Created"
+	newEvent.OrganizationID = info.OrganizationID
+	newEvent.Email = info.Email
+	rabbit, _ := queue.GetConn()
+	msg, _ := json.Marshal(newEvent)
+	err = rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &poID, err
+}
+
+// CreatePurchaseorder saves a draft purchase order in the transaction of the
+// caller, who commits it and publishes the history
+func CreatePurchaseorder(tx *sql.Tx, info PurchaseorderNew) (string, error) {
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewPurchaseorderRepository(tx)
 	isConflict, err := repo.CheckPONumberConfict("", info.OrganizationID, info.PurchaseorderNumber)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "purchaseorder number exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	poID := "po-" + xid.New().String()
 	settingService := setting.NewSettingService()
 	_, err = settingService.GetVendorByID(info.OrganizationID, info.VendorID)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemService := item.NewItemService()
 	for _, item := range info.Items {
-		_, err = itemService.GetItemByID(info.OrganizationID, item.ItemID)
+		itemInfo, err := itemService.GetItemByID(info.OrganizationID, item.ItemID)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		taxValue := 0.0
-		if item.TaxID != "" {
-			tax, err := settingService.GetTaxByID(info.OrganizationID, item.TaxID)
-			if err != nil {
-				return nil, err
-			}
-			taxValue = tax.TaxValue
+		if itemInfo.ItemType == 2 {
+			msg := "sales kit can not be purchased: " + itemInfo.SKU
+			return "", errors.New(msg)
+		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, item.ItemID, item.UnitID)
+		if err != nil {
+			return "", err
+		}
+		if item.UnitID == "" {
+			item.UnitID = itemInfo.UnitID
+		}
+		quantity := item.Quantity * factor
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = item.TaxID
+		taxInfo.Amount = item.Rate * float64(item.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return "", err
 		}
-		itemCount += item.Quantity
-		itemTotal += item.Rate * float64(item.Quantity)
-		taxTotal += item.Rate * float64(item.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 		var poItem PurchaseorderItem
 		poItem.OrganizationID = info.OrganizationID
 		poItem.PurchaseorderID = poID
 		poItem.PurchaseorderItemID = "poi-" + xid.New().String()
 		poItem.ItemID = item.ItemID
-		poItem.Quantity = item.Quantity
-		poItem.Rate = item.Rate
+		poItem.UnitID = item.UnitID
+		poItem.UnitQuantity = item.Quantity
+		poItem.UnitRate = item.Rate
+		poItem.Quantity = quantity
+		poItem.Rate = item.Rate / float64(factor)
 		poItem.TaxID = item.TaxID
-		poItem.TaxValue = taxValue
-		poItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
-		poItem.Amount = float64(item.Quantity) * item.Rate
+		poItem.TaxValue = tax.TaxValue
+		poItem.TaxAmount = tax.TaxAmount
+		poItem.Amount = tax.TaxableAmount
 		poItem.QuantityReceived = 0
 		poItem.QuantityBilled = 0
 		poItem.Status = 1
@@ -88,9 +136,19 @@ func (s *purchaseorderService) NewPurchaseorder(info PurchaseorderNew) (*string,
 		err = repo.CreatePurchaseorderItem(poItem)
 		if err != nil {
 			msg := "create purchaseorder item error: " + err.Error()
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
 	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return "", err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
 	var purchaseorder Purchaseorder
 	purchaseorder.PurchaseorderID = poID
 	purchaseorder.OrganizationID = info.OrganizationID
@@ -104,25 +162,31 @@ func (s *purchaseorderService) NewPurchaseorder(info PurchaseorderNew) (*string,
 	purchaseorder.DiscountType = info.DiscountType
 	purchaseorder.DiscountValue = info.DiscountValue
 	purchaseorder.ShippingFee = info.ShippingFee
+	purchaseorder.TaxInclusive = info.TaxInclusive
+	purchaseorder.ShippingTaxID = shippingTaxInfo.TaxID
+	purchaseorder.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
-		purchaseorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		purchaseorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
-		purchaseorder.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		purchaseorder.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		purchaseorder.Total = itemTotal + info.ShippingFee + taxTotal
+		purchaseorder.Total = itemTotal + shippingTotal + taxTotal
 	}
 	purchaseorder.Notes = info.Notes
-	purchaseorder.Status = 1        //Draft
-	purchaseorder.ReceiveStatus = 1 //no receive
-	purchaseorder.BillingStatus = 1 //unbilled
+	purchaseorder.Status = 1         //Draft
+	purchaseorder.ReceiveStatus = 1  //no receive
+	purchaseorder.BillingStatus = 1  //unbilled
+	purchaseorder.ApprovalStatus = 1 //no approval
+	purchaseorder.SalesorderID = info.SalesorderID
+	purchaseorder.ShippingAddress = info.ShippingAddress
 	purchaseorder.Created = time.Now()
 	purchaseorder.CreatedBy = info.Email
 	purchaseorder.Updated = time.Now()
@@ -131,25 +195,9 @@ func (s *purchaseorderService) NewPurchaseorder(info PurchaseorderNew) (*string,
 	err = repo.CreatePurchaseorder(purchaseorder)
 	if err != nil {
 		msg := "create purchaseorder error: " + err.Error()
-		return nil, errors.New(msg)
-	}
-	var newEvent common.NewHistoryCreated
-	newEvent.HistoryType = "purchaseorder"
-	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
-	newEvent.HistoryBy = info.User
-	newEvent.ReferenceID = poID
-	newEvent.Description = "Purchase Order Created"
-	newEvent.OrganizationID = info.OrganizationID
-	newEvent.Email = info.Email
-	rabbit, _ := queue.GetConn()
-	msg, _ := json.Marshal(newEvent)
-	err = rabbit.Publish("NewHistoryCreated", msg)
-	if err != nil {
-		msg := "create event NewHistoryCreated error"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	tx.Commit()
-	return &purchaseorder.PurchaseorderID, err
+	return poID, nil
 }
 
 func (s *purchaseorderService) GetPurchaseorderList(filter PurchaseorderFilter) (int, *[]PurchaseorderResponse, error) {
@@ -173,6 +221,9 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 		return nil, err
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewPurchaseorderRepository(tx)
 	isConflict, err := repo.CheckPONumberConfict(purchaseorderID, info.OrganizationID, info.PurchaseorderNumber)
 	if err != nil {
@@ -193,6 +244,18 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 		msg := "Purchaseorder not exist"
 		return nil, errors.New(msg)
 	}
+	if oldPurchaseorder.ApprovalStatus == 2 {
+		msg := "Purchaseorder pending approval can not be updated"
+		return nil, errors.New(msg)
+	}
+	if oldPurchaseorder.Status == 1 && oldPurchaseorder.ApprovalStatus != 1 {
+		// a changed draft has to be approved again
+		err = repo.UpdatePurchaseorderApprovalStatus(purchaseorderID, 1, info.User)
+		if err != nil {
+			msg := "update purchaseorder approval status error"
+			return nil, errors.New(msg)
+		}
+	}
 	err = repo.DeletePurchaseorder(purchaseorderID, info.User)
 	if err != nil {
 		msg := "Purchaseorder Update error"
@@ -205,17 +268,30 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 	taxTotal := 0.0
 	itemService := item.NewItemService()
 	for _, item := range info.Items {
-		_, err = itemService.GetItemByID(info.OrganizationID, item.ItemID)
+		itemInfo, err := itemService.GetItemByID(info.OrganizationID, item.ItemID)
 		if err != nil {
 			return nil, err
 		}
-		taxValue := 0.0
-		if item.TaxID != "" {
-			tax, err := settingService.GetTaxByID(info.OrganizationID, item.TaxID)
-			if err != nil {
-				return nil, err
-			}
-			taxValue = tax.TaxValue
+		if itemInfo.ItemType == 2 {
+			msg := "sales kit can not be purchased: " + itemInfo.SKU
+			return nil, errors.New(msg)
+		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, item.ItemID, item.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		if item.UnitID == "" {
+			item.UnitID = itemInfo.UnitID
+		}
+		quantity := item.Quantity * factor
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = item.TaxID
+		taxInfo.Amount = item.Rate * float64(item.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
 		}
 		if item.PurchaseorderItemID != "" {
 			oldItem, err := repo.GetPurchaseorderItemByIDAll(info.OrganizationID, purchaseorderID, item.PurchaseorderItemID)
@@ -223,23 +299,26 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 				msg := "Purchaseorder Item not exist"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityBilled > item.Quantity {
+			if oldItem.QuantityBilled > quantity {
 				msg := "can not set quantity lower than quantity billed"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityReceived > item.Quantity {
+			if oldItem.QuantityReceived > quantity {
 				msg := "can not set quantity lower than quantity received"
 				return nil, errors.New(msg)
 			}
 			quantityBilled += oldItem.QuantityBilled
 			quantityReceived += oldItem.QuantityReceived
 			var poItem PurchaseorderItem
-			poItem.Quantity = item.Quantity
-			poItem.Rate = item.Rate
+			poItem.UnitID = item.UnitID
+			poItem.UnitQuantity = item.Quantity
+			poItem.UnitRate = item.Rate
+			poItem.Quantity = quantity
+			poItem.Rate = item.Rate / float64(factor)
 			poItem.TaxID = item.TaxID
-			poItem.TaxValue = taxValue
-			poItem.Amount = float64(item.Quantity) * item.Rate
-			poItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
+			poItem.TaxValue = tax.TaxValue
+			poItem.Amount = tax.TaxableAmount
+			poItem.TaxAmount = tax.TaxAmount
 			poItem.Status = 1
 			poItem.Updated = time.Now()
 			poItem.UpdatedBy = info.User
@@ -254,12 +333,15 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 			poItem.PurchaseorderID = purchaseorderID
 			poItem.PurchaseorderItemID = "poi-" + xid.New().String()
 			poItem.ItemID = item.ItemID
-			poItem.Quantity = item.Quantity
-			poItem.Rate = item.Rate
+			poItem.UnitID = item.UnitID
+			poItem.UnitQuantity = item.Quantity
+			poItem.UnitRate = item.Rate
+			poItem.Quantity = quantity
+			poItem.Rate = item.Rate / float64(factor)
 			poItem.TaxID = item.TaxID
-			poItem.TaxValue = taxValue
-			poItem.Amount = float64(item.Quantity) * item.Rate
-			poItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
+			poItem.TaxValue = tax.TaxValue
+			poItem.Amount = tax.TaxableAmount
+			poItem.TaxAmount = tax.TaxAmount
 			poItem.QuantityReceived = 0
 			poItem.QuantityBilled = 0
 			poItem.Status = 1
@@ -273,9 +355,9 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 				return nil, errors.New(msg)
 			}
 		}
-		itemCount += item.Quantity
-		itemTotal += item.Rate * float64(item.Quantity)
-		taxTotal += item.Rate * float64(item.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 	}
 	itemDeletedError, err := repo.CheckPOItem(purchaseorderID, info.OrganizationID)
 	if err != nil {
@@ -286,6 +368,16 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 		msg := "item received or billed can not be delete"
 		return nil, errors.New(msg)
 	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
 	var purchaseorder Purchaseorder
 	purchaseorder.PurchaseorderNumber = info.PurchaseorderNumber
 	purchaseorder.PurchaseorderDate = info.PurchaseorderDate
@@ -297,20 +389,23 @@ func (s *purchaseorderService) UpdatePurchaseorder(purchaseorderID string, info
 	purchaseorder.DiscountType = info.DiscountType
 	purchaseorder.DiscountValue = info.DiscountValue
 	purchaseorder.ShippingFee = info.ShippingFee
+	purchaseorder.TaxInclusive = info.TaxInclusive
+	purchaseorder.ShippingTaxID = shippingTaxInfo.TaxID
+	purchaseorder.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		purchaseorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		purchaseorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		purchaseorder.Total = itemTotal + taxTotal - info.DiscountValue + info.ShippingFee
+		purchaseorder.Total = itemTotal + taxTotal - info.DiscountValue + shippingTotal
 	} else {
-		purchaseorder.Total = itemTotal + taxTotal + info.ShippingFee
+		purchaseorder.Total = itemTotal + taxTotal + shippingTotal
 	}
 	purchaseorder.Notes = info.Notes
 	if quantityBilled > 0 {
@@ -403,6 +498,20 @@ func (s *purchaseorderService) DeletePurchaseorder(purchaseorderID, organization
 	if err != nil {
 		return err
 	}
+	if po.SalesorderID != "" {
+		err = repo.ClearSalesorderItemPurchaseorder(purchaseorderID, email)
+		if err != nil {
+			msg := "update drop-ship sales order items error"
+			return errors.New(msg)
+		}
+	}
+	if po.ApprovalStatus == 2 {
+		err = common.NewCommonRepository(tx).CancelApproval(organizationID, "purchaseorder", purchaseorderID, email)
+		if err != nil {
+			msg := "cancel approval error"
+			return errors.New(msg)
+		}
+	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "purchaseorder"
 	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
@@ -434,27 +543,59 @@ func (s *purchaseorderService) GetPurchaseorderItemList(purchaseorderID, organiz
 	return list, err
 }
 
-func (s *purchaseorderService) IssuePurchaseorder(purchaseorderID, organizationID, user, email string) error {
+// IssuePurchaseorder returns the status of the purchase order after issuing,
+// it stays 1 while the order waits for approval
+func (s *purchaseorderService) IssuePurchaseorder(purchaseorderID, organizationID, roleID, user, email string) (int, error) {
 	db := database.WDB()
 	tx, err := db.Begin()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 	repo := NewPurchaseorderRepository(tx)
 	oldPurchaseorder, err := repo.GetPurchaseorderByID(organizationID, purchaseorderID)
 	if err != nil {
 		msg := "Purchaseorder not exist"
-		return errors.New(msg)
+		return 0, errors.New(msg)
 	}
 	if oldPurchaseorder.Status != 1 {
 		msg := "Purchaseorder status error"
-		return errors.New(msg)
+		return 0, errors.New(msg)
+	}
+	if oldPurchaseorder.ApprovalStatus == 2 {
+		msg := "Purchaseorder pending approval"
+		return 0, errors.New(msg)
+	}
+	if oldPurchaseorder.ApprovalStatus != 3 {
+		var approvalInfo common.ApprovalStart
+		approvalInfo.DocumentType = "purchaseorder"
+		approvalInfo.DocumentID = purchaseorderID
+		approvalInfo.DocumentNumber = oldPurchaseorder.PurchaseorderNumber
+		approvalInfo.Total = oldPurchaseorder.Total
+		approvalInfo.ContactID = oldPurchaseorder.VendorID
+		approvalInfo.RoleID = roleID
+		approvalInfo.OrganizationID = organizationID
+		approvalInfo.User = user
+		approvalInfo.Email = email
+		approval, description, err := common.StartApproval(common.NewCommonRepository(tx), approvalInfo)
+		if err != nil {
+			return 0, err
+		}
+		if approval != nil {
+			err = repo.UpdatePurchaseorderApprovalStatus(purchaseorderID, 2, email) //PENDING APPROVAL
+			if err != nil {
+				msg := "update purchaseorder approval status error"
+				return 0, errors.New(msg)
+			}
+			tx.Commit()
+			err = publishPurchaseorderHistory(purchaseorderID, organizationID, description, user, email)
+			return oldPurchaseorder.Status, err
+		}
 	}
 	err = repo.UpdatePurchaseorderStatus(purchaseorderID, 2, email) //ISSUED
 	if err != nil {
 		msg := "update purchaseorder error: " + err.Error()
-		return errors.New(msg)
+		return 0, errors.New(msg)
 	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "purchaseorder"
@@ -469,10 +610,69 @@ func (s *purchaseorderService) IssuePurchaseorder(purchaseorderID, organizationI
 	err = rabbit.Publish("NewHistoryCreated", msg)
 	if err != nil {
 		msg := "create event NewHistoryCreated error"
+		return 0, errors.New(msg)
+	}
+	tx.Commit()
+	return 2, err
+}
+
+func publishPurchaseorderHistory(purchaseorderID, organizationID, description, user, email string) error {
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "purchaseorder"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = user
+	newEvent.ReferenceID = purchaseorderID
+	newEvent.Description = description
+	newEvent.OrganizationID = organizationID
+	newEvent.Email = email
+	rabbit, _ := queue.GetConn()
+	msg, _ := json.Marshal(newEvent)
+	err := rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// ApprovePurchaseorder approves or rejects the current approval step of a
+// purchase order, the order can be issued once all steps are approved
+func (s *purchaseorderService) ApprovePurchaseorder(purchaseorderID string, info common.ApprovalAction) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	oldPurchaseorder, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+	if err != nil {
+		msg := "Purchaseorder not exist"
+		return errors.New(msg)
+	}
+	if oldPurchaseorder.ApprovalStatus != 2 {
+		msg := "Purchaseorder not pending approval"
 		return errors.New(msg)
 	}
+	info.DocumentType = "purchaseorder"
+	info.DocumentID = purchaseorderID
+	status, description, err := common.ActApproval(common.NewCommonRepository(tx), info)
+	if err != nil {
+		return err
+	}
+	if status != 1 {
+		approvalStatus := 3 //APPROVED
+		if status == 3 {
+			approvalStatus = 4 //REJECTED
+		}
+		err = repo.UpdatePurchaseorderApprovalStatus(purchaseorderID, approvalStatus, info.Email)
+		if err != nil {
+			msg := "update purchaseorder approval status error"
+			return errors.New(msg)
+		}
+	}
 	tx.Commit()
-	return err
+	return publishPurchaseorderHistory(purchaseorderID, info.OrganizationID, description, info.User, info.Email)
 }
 
 // receive
@@ -495,9 +695,19 @@ func (s *purchaseorderService) NewPurchasereceive(purchaseorderID string, info P
 		msg := "purchase receive number exists"
 		return nil, errors.New(msg)
 	}
+	po, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+	if err != nil {
+		msg := "purchase order not exist"
+		return nil, errors.New(msg)
+	}
+	if po.SalesorderID != "" {
+		msg := "drop-ship purchase order is received by vendor shipment"
+		return nil, errors.New(msg)
+	}
 	var msgs [][]byte
 	receiveID := "rec-" + xid.New().String()
 	itemRepo := item.NewItemRepository(tx)
+	itemService := item.NewItemService()
 	for _, itemRow := range info.Items {
 		oldPoItem, err := repo.GetPurchaseorderItemByID(info.OrganizationID, purchaseorderID, itemRow.ItemID)
 		if err != nil {
@@ -508,6 +718,11 @@ func (s *purchaseorderService) NewPurchasereceive(purchaseorderID string, info P
 		if err != nil {
 			return nil, err
 		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, itemRow.ItemID, itemRow.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		itemRow.Quantity = itemRow.Quantity * factor
 		receiveItemID := "rei-" + xid.New().String()
 		if itemInfo.TrackLocation == 1 {
 			warehouseRepo := warehouse.NewWarehouseRepository(tx)
@@ -601,6 +816,7 @@ func (s *purchaseorderService) NewPurchasereceive(purchaseorderID string, info P
 					newBatchEvent.ItemID = itemRow.ItemID
 					newBatchEvent.LocationID = nextLocation.LocationID
 					newBatchEvent.OrganizationID = info.OrganizationID
+					newBatchEvent.Rate = oldPoItem.Rate
 					newBatchEvent.Email = info.Email
 					msg, _ := json.Marshal(newBatchEvent)
 					msgs = append(msgs, msg)
@@ -665,7 +881,7 @@ func (s *purchaseorderService) NewPurchasereceive(purchaseorderID string, info P
 		msg := "create purchase receive error: " + err.Error()
 		return nil, errors.New(msg)
 	}
-	po, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+	po, err = repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
 	if err != nil {
 		msg := "get purchase order error: " + err.Error()
 		return nil, errors.New(msg)
@@ -780,6 +996,15 @@ func (s *purchaseorderService) DeletePurchasereceive(purchasereceiveID, organiza
 		msg := "get purchase receive error"
 		return errors.New(msg)
 	}
+	landedCostCount, err := repo.GetLandedCostReferenceCount(organizationID, "receive", purchasereceiveID)
+	if err != nil {
+		msg := "get landed cost count error"
+		return errors.New(msg)
+	}
+	if landedCostCount > 0 {
+		msg := "purchase receive has landed cost, delete landed cost first"
+		return errors.New(msg)
+	}
 	details, err := repo.GetPurchasereceiveDetailList(organizationID, purchasereceiveID)
 	if err != nil {
 		msg := "get purchase receive  detail error"
@@ -951,6 +1176,9 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewPurchaseorderRepository(tx)
 	isConflict, err := repo.CheckBillNumberConfict("", info.OrganizationID, info.BillNumber)
 	if err != nil {
@@ -962,11 +1190,39 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 		return nil, errors.New(msg)
 	}
 	billID := "bil-" + xid.New().String()
+	so, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+	if err != nil {
+		msg := "get purchase order error: "
+		return nil, errors.New(msg)
+	}
+	policy, err := repo.GetVendorMatchPolicy(info.OrganizationID, so.VendorID)
+	if err != nil {
+		msg := "get match policy error: "
+		return nil, errors.New(msg)
+	}
+	matchStatus := 1
 	settingRepo := setting.NewSettingRepository(tx)
+	settingService := setting.NewSettingService()
+	vendor, err := settingRepo.GetVendorByID(so.VendorID, info.OrganizationID)
+	if err != nil {
+		msg := "vendor not exist"
+		return nil, errors.New(msg)
+	}
+	if info.PaymentTermID == "" {
+		info.PaymentTermID = vendor.PaymentTermID
+	}
+	termDates, err := settingService.GetPaymentTermDates(info.OrganizationID, info.PaymentTermID, info.BillDate)
+	if err != nil {
+		return nil, err
+	}
+	if info.DueDate == "" {
+		info.DueDate = termDates.DueDate
+	}
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemRepo := item.NewItemRepository(tx)
+	itemService := item.NewItemService()
 	for _, itemRow := range info.Items {
 		oldPoItem, err := repo.GetPurchaseorderItemByID(info.OrganizationID, purchaseorderID, itemRow.ItemID)
 		if err != nil {
@@ -977,33 +1233,41 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 			msg := "purchase order item id error"
 			return nil, errors.New(msg)
 		}
-		_, err = itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
 		if err != nil {
 			msg := "item not exist"
 			return nil, errors.New(msg)
 		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, itemRow.ItemID, itemRow.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		if itemRow.UnitID == "" {
+			itemRow.UnitID = itemInfo.UnitID
+		}
+		quantity := itemRow.Quantity * factor
 
-		taxValue := 0.0
-		if itemRow.TaxID != "" {
-			tax, err := settingRepo.GetTaxByID(itemRow.TaxID, info.OrganizationID)
-			if err != nil {
-				msg := "tax not exist"
-				return nil, errors.New(msg)
-			}
-			taxValue = tax.TaxValue
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = itemRow.TaxID
+		taxInfo.Amount = itemRow.Rate * float64(itemRow.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
 		}
-		itemCount += itemRow.Quantity
-		itemTotal += itemRow.Rate * float64(itemRow.Quantity)
-		taxTotal += itemRow.Rate * float64(itemRow.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 
 		billItemID := "bili-" + xid.New().String()
-		if oldPoItem.Quantity < oldPoItem.QuantityBilled+itemRow.Quantity {
+		if oldPoItem.Quantity < oldPoItem.QuantityBilled+quantity {
 			msg := "invoicing quantity greater than not billd"
 			return nil, errors.New(msg)
 		}
 		var poItem PurchaseorderItem
 		poItem.PurchaseorderItemID = oldPoItem.PurchaseorderItemID
-		poItem.QuantityBilled = oldPoItem.QuantityBilled + itemRow.Quantity
+		poItem.QuantityBilled = oldPoItem.QuantityBilled + quantity
 		poItem.Updated = time.Now()
 		poItem.UpdatedBy = info.Email
 
@@ -1018,12 +1282,15 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 		billItem.BillItemID = billItemID
 		billItem.PurchaseorderItemID = oldPoItem.PurchaseorderItemID
 		billItem.ItemID = oldPoItem.ItemID
-		billItem.Quantity = itemRow.Quantity
-		billItem.Rate = itemRow.Rate
+		billItem.UnitID = itemRow.UnitID
+		billItem.UnitQuantity = itemRow.Quantity
+		billItem.UnitRate = itemRow.Rate
+		billItem.Quantity = quantity
+		billItem.Rate = itemRow.Rate / float64(factor)
 		billItem.TaxID = itemRow.TaxID
-		billItem.TaxValue = taxValue
-		billItem.TaxAmount = float64(itemRow.Quantity) * itemRow.Rate * taxValue / 100
-		billItem.Amount = float64(itemRow.Quantity) * itemRow.Rate
+		billItem.TaxValue = tax.TaxValue
+		billItem.TaxAmount = tax.TaxAmount
+		billItem.Amount = tax.TaxableAmount
 		billItem.Status = 1
 		billItem.CreatedBy = info.Email
 		billItem.Created = time.Now()
@@ -1035,10 +1302,48 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 			msg := "create bill item error: "
 			return nil, errors.New(msg)
 		}
+		onHold, err := checkBillItemMatch(repo, policy, oldPoItem, billItem, oldPoItem.QuantityBilled+quantity)
+		if err != nil {
+			return nil, err
+		}
+		if onHold {
+			matchStatus = 2
+		}
+		var taxRecord setting.TaxRecordNew
+		taxRecord.ReferenceType = "bill"
+		taxRecord.ReferenceID = billID
+		taxRecord.ReferenceItemID = billItemID
+		taxRecord.RecordDate = info.BillDate
+		taxRecord.Tax = tax
+		taxRecord.OrganizationID = info.OrganizationID
+		taxRecord.User = info.Email
+		err = settingRepo.CreateTaxRecords(taxRecord)
+		if err != nil {
+			msg := "create tax record error"
+			return nil, errors.New(msg)
+		}
 	}
-	so, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
 	if err != nil {
-		msg := "get purchase order error: "
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
+	var shippingTaxRecord setting.TaxRecordNew
+	shippingTaxRecord.ReferenceType = "bill"
+	shippingTaxRecord.ReferenceID = billID
+	shippingTaxRecord.ReferenceItemID = "shipping"
+	shippingTaxRecord.RecordDate = info.BillDate
+	shippingTaxRecord.Tax = shippingTax
+	shippingTaxRecord.OrganizationID = info.OrganizationID
+	shippingTaxRecord.User = info.Email
+	err = settingRepo.CreateTaxRecords(shippingTaxRecord)
+	if err != nil {
+		msg := "create tax record error"
 		return nil, errors.New(msg)
 	}
 	var bill Bill
@@ -1048,6 +1353,9 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 	bill.BillNumber = info.BillNumber
 	bill.BillDate = info.BillDate
 	bill.DueDate = info.DueDate
+	bill.PaymentTermID = termDates.PaymentTermID
+	bill.DiscountDate = termDates.DiscountDate
+	bill.DiscountPercent = termDates.DiscountPercent
 	bill.VendorID = so.VendorID
 	bill.ItemCount = itemCount
 	bill.Subtotal = itemTotal
@@ -1055,20 +1363,24 @@ func (s *purchaseorderService) NewBill(purchaseorderID string, info BillNew) (*s
 	bill.DiscountValue = info.DiscountValue
 	bill.TaxTotal = taxTotal
 	bill.ShippingFee = info.ShippingFee
+	bill.TaxInclusive = info.TaxInclusive
+	bill.ShippingTaxID = shippingTaxInfo.TaxID
+	bill.ShippingTaxAmount = shippingTax.TaxAmount
+	bill.MatchStatus = matchStatus
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		bill.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		bill.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		bill.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		bill.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		bill.Total = itemTotal + info.ShippingFee + taxTotal
+		bill.Total = itemTotal + shippingTotal + taxTotal
 	}
 	bill.Notes = info.Notes
 	bill.Status = 1
@@ -1161,6 +1473,9 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewPurchaseorderRepository(tx)
 	isConflict, err := repo.CheckBillNumberConfict(billID, info.OrganizationID, info.BillNumber)
 	if err != nil {
@@ -1172,11 +1487,36 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 		return nil, errors.New(msg)
 	}
 	settingRepo := setting.NewSettingRepository(tx)
+	settingService := setting.NewSettingService()
 	oldBill, err := repo.GetBillByID(info.OrganizationID, billID)
 	if err != nil {
 		msg := "get bill error"
 		return nil, errors.New(msg)
 	}
+	vendor, err := settingRepo.GetVendorByID(oldBill.VendorID, info.OrganizationID)
+	if err != nil {
+		msg := "vendor not exist"
+		return nil, errors.New(msg)
+	}
+	if info.PaymentTermID == "" {
+		info.PaymentTermID = vendor.PaymentTermID
+	}
+	termDates, err := settingService.GetPaymentTermDates(info.OrganizationID, info.PaymentTermID, info.BillDate)
+	if err != nil {
+		return nil, err
+	}
+	if info.DueDate == "" {
+		info.DueDate = termDates.DueDate
+	}
+	landedCostCount, err := repo.GetLandedCostReferenceCount(info.OrganizationID, "bill", billID)
+	if err != nil {
+		msg := "get landed cost count error"
+		return nil, errors.New(msg)
+	}
+	if landedCostCount > 0 {
+		msg := "bill has landed cost, delete landed cost first"
+		return nil, errors.New(msg)
+	}
 	oldBillItems, err := repo.GetBillItemList(info.OrganizationID, billID)
 	if err != nil {
 		msg := "get bill item error"
@@ -1206,10 +1546,27 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 		msg := "Bill Update error"
 		return nil, errors.New(msg)
 	}
+	err = settingRepo.DeleteTaxRecord("bill", billID, info.Email)
+	if err != nil {
+		msg := "delete tax record error"
+		return nil, errors.New(msg)
+	}
+	err = repo.DeleteBillMatchException(billID, info.Email)
+	if err != nil {
+		msg := "delete bill match exception error"
+		return nil, errors.New(msg)
+	}
+	policy, err := repo.GetVendorMatchPolicy(info.OrganizationID, oldBill.VendorID)
+	if err != nil {
+		msg := "get match policy error: "
+		return nil, errors.New(msg)
+	}
+	matchStatus := 1
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemRepo := item.NewItemRepository(tx)
+	itemService := item.NewItemService()
 	for _, itemRow := range info.Items {
 		oldPoItem, err := repo.GetPurchaseorderItemByID(info.OrganizationID, oldBill.PurchaseorderID, itemRow.ItemID)
 		if err != nil {
@@ -1220,34 +1577,42 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 			msg := "purchase order item id error"
 			return nil, errors.New(msg)
 		}
-		_, err = itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
 		if err != nil {
 			msg := "item not exist"
 			return nil, errors.New(msg)
 		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, itemRow.ItemID, itemRow.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		if itemRow.UnitID == "" {
+			itemRow.UnitID = itemInfo.UnitID
+		}
+		quantity := itemRow.Quantity * factor
 
-		taxValue := 0.0
-		if itemRow.TaxID != "" {
-			tax, err := settingRepo.GetTaxByID(itemRow.TaxID, info.OrganizationID)
-			if err != nil {
-				msg := "tax not exist"
-				return nil, errors.New(msg)
-			}
-			taxValue = tax.TaxValue
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = itemRow.TaxID
+		taxInfo.Amount = itemRow.Rate * float64(itemRow.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
 		}
-		itemCount += itemRow.Quantity
-		itemTotal += itemRow.Rate * float64(itemRow.Quantity)
-		taxTotal += itemRow.Rate * float64(itemRow.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 
 		billItemID := "bili-" + xid.New().String()
-		if oldPoItem.Quantity < oldPoItem.QuantityBilled+itemRow.Quantity {
-			fmt.Println(oldPoItem.Quantity, oldPoItem.QuantityBilled, itemRow.Quantity)
+		if oldPoItem.Quantity < oldPoItem.QuantityBilled+quantity {
+			fmt.Println(oldPoItem.Quantity, oldPoItem.QuantityBilled, quantity)
 			msg := "invoicing quantity greater than not billd"
 			return nil, errors.New(msg)
 		}
 		var poItem PurchaseorderItem
 		poItem.PurchaseorderItemID = oldPoItem.PurchaseorderItemID
-		poItem.QuantityBilled = oldPoItem.QuantityBilled + itemRow.Quantity
+		poItem.QuantityBilled = oldPoItem.QuantityBilled + quantity
 		poItem.Updated = time.Now()
 		poItem.UpdatedBy = info.Email
 
@@ -1262,12 +1627,15 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 		billItem.BillItemID = billItemID
 		billItem.PurchaseorderItemID = oldPoItem.PurchaseorderItemID
 		billItem.ItemID = oldPoItem.ItemID
-		billItem.Quantity = itemRow.Quantity
-		billItem.Rate = itemRow.Rate
+		billItem.UnitID = itemRow.UnitID
+		billItem.UnitQuantity = itemRow.Quantity
+		billItem.UnitRate = itemRow.Rate
+		billItem.Quantity = quantity
+		billItem.Rate = itemRow.Rate / float64(factor)
 		billItem.TaxID = itemRow.TaxID
-		billItem.TaxValue = taxValue
-		billItem.TaxAmount = float64(itemRow.Quantity) * itemRow.Rate * taxValue / 100
-		billItem.Amount = float64(itemRow.Quantity) * itemRow.Rate
+		billItem.TaxValue = tax.TaxValue
+		billItem.TaxAmount = tax.TaxAmount
+		billItem.Amount = tax.TaxableAmount
 		billItem.Status = 1
 		billItem.CreatedBy = info.Email
 		billItem.Created = time.Now()
@@ -1279,11 +1647,57 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 			msg := "create bill item error: "
 			return nil, errors.New(msg)
 		}
+		onHold, err := checkBillItemMatch(repo, policy, oldPoItem, billItem, oldPoItem.QuantityBilled+quantity)
+		if err != nil {
+			return nil, err
+		}
+		if onHold {
+			matchStatus = 2
+		}
+		var taxRecord setting.TaxRecordNew
+		taxRecord.ReferenceType = "bill"
+		taxRecord.ReferenceID = billID
+		taxRecord.ReferenceItemID = billItemID
+		taxRecord.RecordDate = info.BillDate
+		taxRecord.Tax = tax
+		taxRecord.OrganizationID = info.OrganizationID
+		taxRecord.User = info.Email
+		err = settingRepo.CreateTaxRecords(taxRecord)
+		if err != nil {
+			msg := "create tax record error"
+			return nil, errors.New(msg)
+		}
+	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
+	var shippingTaxRecord setting.TaxRecordNew
+	shippingTaxRecord.ReferenceType = "bill"
+	shippingTaxRecord.ReferenceID = billID
+	shippingTaxRecord.ReferenceItemID = "shipping"
+	shippingTaxRecord.RecordDate = info.BillDate
+	shippingTaxRecord.Tax = shippingTax
+	shippingTaxRecord.OrganizationID = info.OrganizationID
+	shippingTaxRecord.User = info.Email
+	err = settingRepo.CreateTaxRecords(shippingTaxRecord)
+	if err != nil {
+		msg := "create tax record error"
+		return nil, errors.New(msg)
 	}
 	var bill Bill
 	bill.BillNumber = info.BillNumber
 	bill.BillDate = info.BillDate
 	bill.DueDate = info.DueDate
+	bill.PaymentTermID = termDates.PaymentTermID
+	bill.DiscountDate = termDates.DiscountDate
+	bill.DiscountPercent = termDates.DiscountPercent
 	bill.VendorID = oldBill.VendorID
 	bill.ItemCount = itemCount
 	bill.Subtotal = itemTotal
@@ -1291,20 +1705,24 @@ func (s *purchaseorderService) UpdateBill(billID string, info BillNew) (*string,
 	bill.DiscountValue = info.DiscountValue
 	bill.TaxTotal = taxTotal
 	bill.ShippingFee = info.ShippingFee
+	bill.TaxInclusive = info.TaxInclusive
+	bill.ShippingTaxID = shippingTaxInfo.TaxID
+	bill.ShippingTaxAmount = shippingTax.TaxAmount
+	bill.MatchStatus = matchStatus
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		bill.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		bill.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		bill.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		bill.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		bill.Total = itemTotal + info.ShippingFee + taxTotal
+		bill.Total = itemTotal + shippingTotal + taxTotal
 	}
 	bill.Notes = info.Notes
 	bill.Status = 1
@@ -1380,6 +1798,15 @@ func (s *purchaseorderService) DeleteBill(billID, organizationID, user, email st
 		msg := "get picking order error"
 		return errors.New(msg)
 	}
+	landedCostCount, err := repo.GetLandedCostReferenceCount(organizationID, "bill", billID)
+	if err != nil {
+		msg := "get landed cost count error"
+		return errors.New(msg)
+	}
+	if landedCostCount > 0 {
+		msg := "bill has landed cost, delete landed cost first"
+		return errors.New(msg)
+	}
 	if oldBill.Status != 1 {
 		msg := " bill status error"
 		return errors.New(msg)
@@ -1416,6 +1843,17 @@ func (s *purchaseorderService) DeleteBill(billID, organizationID, user, email st
 		msg := "delete picking order error: "
 		return errors.New(msg)
 	}
+	settingRepo := setting.NewSettingRepository(tx)
+	err = settingRepo.DeleteTaxRecord("bill", billID, email)
+	if err != nil {
+		msg := "delete tax record error"
+		return errors.New(msg)
+	}
+	err = repo.DeleteBillMatchException(billID, email)
+	if err != nil {
+		msg := "delete bill match exception error"
+		return errors.New(msg)
+	}
 	billedCount, err := repo.GetPurchaseorderBilledCount(organizationID, oldBill.PurchaseorderID)
 	if err != nil {
 		msg := "get purchase order billed count error: "
@@ -1451,23 +1889,18 @@ func (s *purchaseorderService) DeleteBill(billID, organizationID, user, email st
 	return err
 }
 
-func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew) (*string, error) {
-	db := database.WDB()
-	tx, err := db.Begin()
-	if err != nil {
-		msg := "begin transaction error"
-		return nil, errors.New(msg)
-	}
-	defer tx.Rollback()
+// CreatePaymentMade records a payment of the bill within the given
+// transaction and updates the bill status
+func CreatePaymentMade(tx *sql.Tx, billID string, info PaymentMadeNew) (string, error) {
 	repo := NewPurchaseorderRepository(tx)
 	isConflict, err := repo.CheckPaymentMadeNumberConfict("", info.OrganizationID, info.PaymentMadeNumber)
 	if err != nil {
 		msg := "check conflict error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "payment number exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	paymentMadeID := "paym-" + xid.New().String()
 	settingRepo := setting.NewSettingRepository(tx)
@@ -1475,21 +1908,29 @@ func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew
 	bill, err := repo.GetBillByID(info.OrganizationID, billID)
 	if err != nil {
 		msg := "get bill error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
+	}
+	if bill.MatchStatus == 2 {
+		msg := "bill is on hold"
+		return "", errors.New(msg)
 	}
 	billdPaid, err := repo.GetBillPaidCount(info.OrganizationID, billID)
 	if err != nil {
 		msg := "get bill paid count error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	if bill.Total < billdPaid+info.Amount {
+	discountAmount, err := billEarlyPaymentDiscount(repo, bill, "", info)
+	if err != nil {
+		return "", err
+	}
+	if bill.Total < billdPaid+info.Amount+discountAmount {
 		msg := "pay too much error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	_, err = settingRepo.GetPaymentMethodByID(info.OrganizationID, info.PaymentMethodID)
 	if err != nil {
 		msg := "payment method not exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	var paymentMade PaymentMade
 	paymentMade.OrganizationID = info.OrganizationID
@@ -1500,6 +1941,7 @@ func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew
 	paymentMade.PaymentMadeDate = info.PaymentMadeDate
 	paymentMade.PaymentMethodID = info.PaymentMethodID
 	paymentMade.Amount = info.Amount
+	paymentMade.DiscountAmount = discountAmount
 	paymentMade.Notes = info.Notes
 	paymentMade.Status = 1
 	paymentMade.Created = time.Now()
@@ -1509,12 +1951,12 @@ func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew
 	err = repo.CreatePaymentMade(paymentMade)
 	if err != nil {
 		msg := "create payment error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	billdPaid, err = repo.GetBillPaidCount(info.OrganizationID, billID)
 	if err != nil {
 		msg := "get bill paid count error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	billStatus := 1
 	if bill.Total == billdPaid {
@@ -1525,8 +1967,23 @@ func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew
 	err = repo.UpdateBillStatus(billID, billStatus, info.Email)
 	if err != nil {
 		msg := "update bill status error: "
+		return "", errors.New(msg)
+	}
+	return paymentMadeID, nil
+}
+
+func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
 		return nil, errors.New(msg)
 	}
+	defer tx.Rollback()
+	paymentMadeID, err := CreatePaymentMade(tx, billID, info)
+	if err != nil {
+		return nil, err
+	}
 	tx.Commit()
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "bill"
@@ -1546,6 +2003,29 @@ func (s *purchaseorderService) NewPaymentMade(billID string, info PaymentMadeNew
 	return &paymentMadeID, err
 }
 
+// billEarlyPaymentDiscount returns the early payment discount a payment takes
+// on the bill. The discount can only be taken once per bill.
+func billEarlyPaymentDiscount(repo *purchaseorderRepository, bill *BillResponse, paymentMadeID string, info PaymentMadeNew) (float64, error) {
+	if info.TakeDiscount != 1 {
+		return 0, nil
+	}
+	discountAmount := setting.CalculateEarlyPaymentDiscount(bill.Total, bill.DiscountPercent, bill.DiscountDate, info.PaymentMadeDate)
+	if discountAmount == 0 {
+		msg := "early payment discount not available"
+		return 0, errors.New(msg)
+	}
+	discountTaken, err := repo.GetBillDiscountTaken(info.OrganizationID, bill.BillID, paymentMadeID)
+	if err != nil {
+		msg := "get bill discount error: "
+		return 0, errors.New(msg)
+	}
+	if discountTaken > 0 {
+		msg := "early payment discount already taken"
+		return 0, errors.New(msg)
+	}
+	return discountAmount, nil
+}
+
 func (s *purchaseorderService) GeBillPaymentMade(organizationID, billID string) (float64, error) {
 	db := database.RDB()
 	query := NewPurchaseorderQuery(db)
@@ -1591,21 +2071,39 @@ func (s *purchaseorderService) UpdatePaymentMade(paymentMadeID string, info Paym
 		msg := "payment not exist"
 		return nil, errors.New(msg)
 	}
+	matchedCount, err := repo.GetPaymentMadeMatchedCount(info.OrganizationID, paymentMadeID)
+	if err != nil {
+		msg := "get payment matched count error"
+		return nil, errors.New(msg)
+	}
+	if matchedCount > 0 {
+		msg := "payment is matched to a bank transaction, remove the match first"
+		return nil, errors.New(msg)
+	}
 	bill, err := repo.GetBillByID(info.OrganizationID, oldPayment.BillID)
 	if err != nil {
 		msg := "get bill error: "
 		return nil, errors.New(msg)
 	}
+	if bill.MatchStatus == 2 {
+		msg := "bill is on hold"
+		return nil, errors.New(msg)
+	}
 	_, err = settingRepo.GetPaymentMethodByID(info.OrganizationID, info.PaymentMethodID)
 	if err != nil {
 		msg := "payment method not exists"
 		return nil, errors.New(msg)
 	}
+	discountAmount, err := billEarlyPaymentDiscount(repo, bill, paymentMadeID, info)
+	if err != nil {
+		return nil, err
+	}
 	var paymentMade PaymentMade
 	paymentMade.PaymentMadeNumber = info.PaymentMadeNumber
 	paymentMade.PaymentMadeDate = info.PaymentMadeDate
 	paymentMade.PaymentMethodID = info.PaymentMethodID
 	paymentMade.Amount = info.Amount
+	paymentMade.DiscountAmount = discountAmount
 	paymentMade.Notes = info.Notes
 	paymentMade.Status = 1
 	paymentMade.Updated = time.Now()
@@ -1667,6 +2165,15 @@ func (s *purchaseorderService) DeletePaymentMade(paymentMadeID, organizationID,
 		msg := "get payment error"
 		return errors.New(msg)
 	}
+	matchedCount, err := repo.GetPaymentMadeMatchedCount(organizationID, paymentMadeID)
+	if err != nil {
+		msg := "get payment matched count error"
+		return errors.New(msg)
+	}
+	if matchedCount > 0 {
+		msg := "payment is matched to a bank transaction, remove the match first"
+		return errors.New(msg)
+	}
 	err = repo.DeletePaymentMade(paymentMadeID, email)
 	if err != nil {
 		msg := "delete picking order error: "
@@ -1706,3 +2213,1481 @@ func (s *purchaseorderService) DeletePaymentMade(paymentMadeID, organizationID,
 	}
 	return err
 }
+
+// landed cost
+
+type landedCostLine struct {
+	ReferenceType   string
+	ReferenceID     string
+	ReferenceItemID string
+	ItemID          string
+	Quantity        int
+	Value           float64
+	Basis           float64
+	ReceiveItemIDs  []string
+}
+
+// billedReceiveItems finds the receives a bill line covers. Bill lines of a
+// purchase order item are matched to its receives first come first served,
+// a receive billed only in part has to be referenced itself.
+func billedReceiveItems(repo *purchaseorderRepository, organizationID string, billItem BillItemResponse) ([]string, error) {
+	receiveItems, err := repo.GetPurchasereceiveItemListByPOItem(organizationID, billItem.PurchaseorderItemID)
+	if err != nil {
+		msg := "get purchase receive item error"
+		return nil, errors.New(msg)
+	}
+	billedBefore, err := repo.GetBilledQuantityBefore(organizationID, billItem.PurchaseorderItemID, billItem.BillItemID)
+	if err != nil {
+		msg := "get billed quantity error"
+		return nil, errors.New(msg)
+	}
+	from, to := billedBefore, billedBefore+billItem.Quantity
+	var res []string
+	received := 0
+	for _, receiveItem := range *receiveItems {
+		start, end := received, received+receiveItem.Quantity
+		received = end
+		if end <= from || start >= to {
+			continue
+		}
+		if start < from || end > to {
+			msg := "bill " + billItem.SKU + " covers part of a receive, reference the receive instead"
+			return nil, errors.New(msg)
+		}
+		res = append(res, receiveItem.PurchasereceiveItemID)
+	}
+	return res, nil
+}
+
+func (s *purchaseorderService) NewLandedCost(info LandedCostNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	isConflict, err := repo.CheckLandedCostNumberConfict("", info.OrganizationID, info.LandedCostNumber)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "landed cost number exists"
+		return nil, errors.New(msg)
+	}
+	if info.VendorID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetVendorByID(info.VendorID, info.OrganizationID)
+		if err != nil {
+			msg := "vendor not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	landedCostID := "lc-" + xid.New().String()
+	itemRepo := item.NewItemRepository(tx)
+	var lines []landedCostLine
+	var purchaseorderIDs []string
+	referenced := make(map[string]bool)
+	receiveItemReferenced := make(map[string]bool)
+	purchaseorderAdded := make(map[string]bool)
+	for _, reference := range info.References {
+		if referenced[reference.ReferenceType+reference.ReferenceID] {
+			msg := "duplicate landed cost reference"
+			return nil, errors.New(msg)
+		}
+		referenced[reference.ReferenceType+reference.ReferenceID] = true
+		purchaseorderID := ""
+		if reference.ReferenceType == "receive" {
+			purchasereceive, err := repo.GetPurchasereceiveByID(info.OrganizationID, reference.ReferenceID)
+			if err != nil {
+				msg := "purchase receive not exist"
+				return nil, errors.New(msg)
+			}
+			purchaseorderID = purchasereceive.PurchaseorderID
+			receiveItems, err := repo.GetPurchasereceiveItemList(info.OrganizationID, reference.ReferenceID)
+			if err != nil {
+				msg := "get purchase receive item error"
+				return nil, errors.New(msg)
+			}
+			for _, receiveItem := range *receiveItems {
+				poItem, err := repo.GetPurchaseorderItemByIDAll(info.OrganizationID, purchaseorderID, receiveItem.PurchaseorderItemID)
+				if err != nil {
+					msg := "purchase order item not exist"
+					return nil, errors.New(msg)
+				}
+				var line landedCostLine
+				line.ReferenceType = reference.ReferenceType
+				line.ReferenceID = reference.ReferenceID
+				line.ReferenceItemID = receiveItem.PurchasereceiveItemID
+				line.ItemID = receiveItem.ItemID
+				line.Quantity = receiveItem.Quantity
+				line.Value = poItem.Rate * float64(receiveItem.Quantity)
+				line.ReceiveItemIDs = []string{receiveItem.PurchasereceiveItemID}
+				lines = append(lines, line)
+			}
+		} else {
+			bill, err := repo.GetBillByID(info.OrganizationID, reference.ReferenceID)
+			if err != nil {
+				msg := "bill not exist"
+				return nil, errors.New(msg)
+			}
+			purchaseorderID = bill.PurchaseorderID
+			billItems, err := repo.GetBillItemList(info.OrganizationID, reference.ReferenceID)
+			if err != nil {
+				msg := "get bill item error"
+				return nil, errors.New(msg)
+			}
+			for _, billItem := range *billItems {
+				receiveItemIDs, err := billedReceiveItems(repo, info.OrganizationID, billItem)
+				if err != nil {
+					return nil, err
+				}
+				var line landedCostLine
+				line.ReferenceType = reference.ReferenceType
+				line.ReferenceID = reference.ReferenceID
+				line.ReferenceItemID = billItem.BillItemID
+				line.ItemID = billItem.ItemID
+				line.Quantity = billItem.Quantity
+				line.Value = billItem.Amount
+				line.ReceiveItemIDs = receiveItemIDs
+				lines = append(lines, line)
+			}
+		}
+		for _, line := range lines {
+			if line.ReferenceID != reference.ReferenceID {
+				continue
+			}
+			for _, receiveItemID := range line.ReceiveItemIDs {
+				if receiveItemReferenced[receiveItemID] {
+					msg := "landed cost references overlap, a receive is also covered by a referenced bill"
+					return nil, errors.New(msg)
+				}
+				receiveItemReferenced[receiveItemID] = true
+			}
+		}
+		var landedCostReference LandedCostReference
+		landedCostReference.OrganizationID = info.OrganizationID
+		landedCostReference.LandedCostID = landedCostID
+		landedCostReference.LandedCostReferenceID = "lcr-" + xid.New().String()
+		landedCostReference.ReferenceType = reference.ReferenceType
+		landedCostReference.ReferenceID = reference.ReferenceID
+		landedCostReference.PurchaseorderID = purchaseorderID
+		landedCostReference.Status = 1
+		landedCostReference.Created = time.Now()
+		landedCostReference.CreatedBy = info.Email
+		landedCostReference.Updated = time.Now()
+		landedCostReference.UpdatedBy = info.Email
+		err = repo.CreateLandedCostReference(landedCostReference)
+		if err != nil {
+			msg := "create landed cost reference error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		if !purchaseorderAdded[purchaseorderID] {
+			purchaseorderAdded[purchaseorderID] = true
+			purchaseorderIDs = append(purchaseorderIDs, purchaseorderID)
+		}
+	}
+	var charges []LandedCostCharge
+	for _, chargeRow := range info.Charges {
+		var charge LandedCostCharge
+		charge.ChargeType = chargeRow.ChargeType
+		charge.Description = chargeRow.Description
+		charge.Amount = chargeRow.Amount
+		charges = append(charges, charge)
+	}
+	if info.IncludeShippingFee == 1 {
+		for _, purchaseorderID := range purchaseorderIDs {
+			shippingCount, err := repo.GetLandedCostShippingCount(info.OrganizationID, purchaseorderID)
+			if err != nil {
+				msg := "get landed cost shipping count error"
+				return nil, errors.New(msg)
+			}
+			if shippingCount > 0 {
+				msg := "purchase order shipping fee already allocated"
+				return nil, errors.New(msg)
+			}
+			po, err := repo.GetPurchaseorderByID(info.OrganizationID, purchaseorderID)
+			if err != nil {
+				msg := "purchase order not exist"
+				return nil, errors.New(msg)
+			}
+			if po.ShippingFee > 0 {
+				var charge LandedCostCharge
+				charge.ChargeType = "shipping"
+				charge.PurchaseorderID = purchaseorderID
+				charge.Description = po.PurchaseorderNumber
+				charge.Amount = po.ShippingFee
+				charges = append(charges, charge)
+			}
+		}
+	}
+	landedCostTotal := 0.0
+	for _, charge := range charges {
+		charge.OrganizationID = info.OrganizationID
+		charge.LandedCostID = landedCostID
+		charge.LandedCostChargeID = "lcc-" + xid.New().String()
+		charge.Status = 1
+		charge.Created = time.Now()
+		charge.CreatedBy = info.Email
+		charge.Updated = time.Now()
+		charge.UpdatedBy = info.Email
+		err = repo.CreateLandedCostCharge(charge)
+		if err != nil {
+			msg := "create landed cost charge error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		landedCostTotal += charge.Amount
+	}
+	if landedCostTotal <= 0 {
+		msg := "landed cost amount error"
+		return nil, errors.New(msg)
+	}
+	basisTotal := 0.0
+	for i, line := range lines {
+		itemInfo, err := itemRepo.GetItemByID(line.ItemID, info.OrganizationID)
+		if err != nil {
+			msg := "item not exist"
+			return nil, errors.New(msg)
+		}
+		switch info.AllocationMethod {
+		case 1:
+			lines[i].Basis = line.Value
+		case 2:
+			lines[i].Basis = float64(line.Quantity)
+		case 3:
+			lines[i].Basis = itemInfo.Weight * float64(line.Quantity)
+		case 4:
+			lines[i].Basis = itemInfo.Length * itemInfo.Width * itemInfo.Height * float64(line.Quantity)
+		}
+		basisTotal += lines[i].Basis
+	}
+	if basisTotal <= 0 {
+		msg := "landed cost allocation basis is zero"
+		return nil, errors.New(msg)
+	}
+	for _, line := range lines {
+		var allocation LandedCostAllocation
+		allocation.OrganizationID = info.OrganizationID
+		allocation.LandedCostID = landedCostID
+		allocation.LandedCostAllocationID = "lca-" + xid.New().String()
+		allocation.ReferenceType = line.ReferenceType
+		allocation.ReferenceID = line.ReferenceID
+		allocation.ReferenceItemID = line.ReferenceItemID
+		allocation.ItemID = line.ItemID
+		allocation.Quantity = line.Quantity
+		allocation.Basis = line.Basis
+		allocation.Amount = landedCostTotal * line.Basis / basisTotal
+		var lineBatches []item.ItemBatchResponse
+		batchQuantity := 0
+		for _, receiveItemID := range line.ReceiveItemIDs {
+			batches, err := itemRepo.GetItemBatchListByReferenceID(receiveItemID, info.OrganizationID)
+			if err != nil {
+				msg := "get item batch error"
+				return nil, errors.New(msg)
+			}
+			for _, batch := range *batches {
+				if batch.ItemID != line.ItemID || batch.Quantity <= 0 {
+					continue
+				}
+				lineBatches = append(lineBatches, batch)
+				batchQuantity += batch.Quantity
+			}
+		}
+		if batchQuantity == 0 {
+			msg := "landed cost item not received yet"
+			return nil, errors.New(msg)
+		}
+		allocation.UnitCost = allocation.Amount / float64(batchQuantity)
+		//split the amount by batch quantity, the last batch takes the remainder so the batches add up to the amount
+		allocated := 0.0
+		for i, batch := range lineBatches {
+			batchAmount := allocation.Amount * float64(batch.Quantity) / float64(batchQuantity)
+			if i == len(lineBatches)-1 {
+				batchAmount = allocation.Amount - allocated
+			}
+			allocated += batchAmount
+			unitCost := batchAmount / float64(batch.Quantity)
+			err = itemRepo.UpdateItemBatchRate(batch.BatchID, unitCost, info.Email)
+			if err != nil {
+				msg := "update item batch rate error"
+				return nil, errors.New(msg)
+			}
+			var landedCostBatch LandedCostBatch
+			landedCostBatch.OrganizationID = info.OrganizationID
+			landedCostBatch.LandedCostID = landedCostID
+			landedCostBatch.LandedCostAllocationID = allocation.LandedCostAllocationID
+			landedCostBatch.LandedCostBatchID = "lcb-" + xid.New().String()
+			landedCostBatch.BatchID = batch.BatchID
+			landedCostBatch.Quantity = batch.Quantity
+			landedCostBatch.QuantityConsumed = batch.Quantity - batch.Balance
+			landedCostBatch.UnitCost = unitCost
+			landedCostBatch.CogsAdjustment = float64(landedCostBatch.QuantityConsumed) * unitCost
+			landedCostBatch.Status = 1
+			landedCostBatch.Created = time.Now()
+			landedCostBatch.CreatedBy = info.Email
+			landedCostBatch.Updated = time.Now()
+			landedCostBatch.UpdatedBy = info.Email
+			err = repo.CreateLandedCostBatch(landedCostBatch)
+			if err != nil {
+				msg := "create landed cost batch error: " + err.Error()
+				return nil, errors.New(msg)
+			}
+			allocation.QuantityConsumed += landedCostBatch.QuantityConsumed
+			allocation.CogsAdjustment += landedCostBatch.CogsAdjustment
+		}
+		allocation.Status = 1
+		allocation.Created = time.Now()
+		allocation.CreatedBy = info.Email
+		allocation.Updated = time.Now()
+		allocation.UpdatedBy = info.Email
+		err = repo.CreateLandedCostAllocation(allocation)
+		if err != nil {
+			msg := "create landed cost allocation error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	var landedCost LandedCost
+	landedCost.OrganizationID = info.OrganizationID
+	landedCost.LandedCostID = landedCostID
+	landedCost.LandedCostNumber = info.LandedCostNumber
+	landedCost.LandedCostDate = info.LandedCostDate
+	landedCost.VendorID = info.VendorID
+	landedCost.AllocationMethod = info.AllocationMethod
+	landedCost.Amount = landedCostTotal
+	landedCost.Notes = info.Notes
+	landedCost.Status = 1
+	landedCost.Created = time.Now()
+	landedCost.CreatedBy = info.Email
+	landedCost.Updated = time.Now()
+	landedCost.UpdatedBy = info.Email
+	err = repo.CreateLandedCost(landedCost)
+	if err != nil {
+		msg := "create landed cost error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	rabbit, _ := queue.GetConn()
+	for _, purchaseorderID := range purchaseorderIDs {
+		var newEvent common.NewHistoryCreated
+		newEvent.HistoryType = "purchaseorder"
+		newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+		newEvent.HistoryBy = info.User
+		newEvent.ReferenceID = purchaseorderID
+		newEvent.Description = "Landed Cost Created"
+		newEvent.OrganizationID = info.OrganizationID
+		newEvent.Email = info.Email
+		msg, _ := json.Marshal(newEvent)
+		err = rabbit.Publish("NewHistoryCreated", msg)
+		if err != nil {
+			msg := "create event NewHistoryCreated error"
+			return nil, errors.New(msg)
+		}
+	}
+	return &landedCostID, err
+}
+
+func (s *purchaseorderService) GetLandedCostList(filter LandedCostFilter) (int, *[]LandedCostResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	count, err := query.GetLandedCostCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetLandedCostList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *purchaseorderService) GetLandedCostByID(organizationID, id string) (*LandedCostResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	landedCost, err := query.GetLandedCostByID(organizationID, id)
+	if err != nil {
+		msg := "get landed cost error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return landedCost, nil
+}
+
+func (s *purchaseorderService) GetLandedCostAllocationList(organizationID, id string) (*[]LandedCostAllocationResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	_, err := query.GetLandedCostByID(organizationID, id)
+	if err != nil {
+		msg := "landed cost not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetLandedCostAllocationList(organizationID, id)
+	if err != nil {
+		msg := "get landed cost allocation error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
+
+func (s *purchaseorderService) DeleteLandedCost(landedCostID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	itemRepo := item.NewItemRepository(tx)
+	_, err = repo.GetLandedCostByID(organizationID, landedCostID)
+	if err != nil {
+		msg := "landed cost not exist"
+		return errors.New(msg)
+	}
+	batches, err := repo.GetLandedCostBatchList(organizationID, landedCostID)
+	if err != nil {
+		msg := "get landed cost batch error"
+		return errors.New(msg)
+	}
+	for _, batch := range *batches {
+		err = itemRepo.UpdateItemBatchRate(batch.BatchID, -batch.UnitCost, email)
+		if err != nil {
+			msg := "update item batch rate error"
+			return errors.New(msg)
+		}
+	}
+	err = repo.DeleteLandedCost(landedCostID, email)
+	if err != nil {
+		msg := "delete landed cost error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// match policy
+
+func (s *purchaseorderService) NewMatchPolicy(info MatchPolicyNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	isConflict, err := repo.CheckMatchPolicyConfict("", info.OrganizationID, info.VendorID)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "match policy of this vendor exists"
+		return nil, errors.New(msg)
+	}
+	if info.VendorID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetVendorByID(info.VendorID, info.OrganizationID)
+		if err != nil {
+			msg := "vendor not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	var policy MatchPolicy
+	policy.OrganizationID = info.OrganizationID
+	policy.MatchPolicyID = "mp-" + xid.New().String()
+	policy.Name = info.Name
+	policy.VendorID = info.VendorID
+	policy.MatchType = info.MatchType
+	policy.QuantityTolerance = info.QuantityTolerance
+	policy.PriceTolerance = info.PriceTolerance
+	policy.Status = info.Status
+	policy.Created = time.Now()
+	policy.CreatedBy = info.Email
+	policy.Updated = time.Now()
+	policy.UpdatedBy = info.Email
+	err = repo.CreateMatchPolicy(policy)
+	if err != nil {
+		msg := "create match policy error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &policy.MatchPolicyID, err
+}
+
+func (s *purchaseorderService) GetMatchPolicyList(filter MatchPolicyFilter) (int, *[]MatchPolicyResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	count, err := query.GetMatchPolicyCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetMatchPolicyList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *purchaseorderService) GetMatchPolicyByID(organizationID, id string) (*MatchPolicyResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	policy, err := query.GetMatchPolicyByID(organizationID, id)
+	if err != nil {
+		msg := "get match policy error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return policy, nil
+}
+
+func (s *purchaseorderService) UpdateMatchPolicy(matchPolicyID string, info MatchPolicyNew) (*MatchPolicyResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	_, err = repo.GetMatchPolicyByID(info.OrganizationID, matchPolicyID)
+	if err != nil {
+		msg := "match policy not exist"
+		return nil, errors.New(msg)
+	}
+	isConflict, err := repo.CheckMatchPolicyConfict(matchPolicyID, info.OrganizationID, info.VendorID)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "match policy of this vendor exists"
+		return nil, errors.New(msg)
+	}
+	if info.VendorID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetVendorByID(info.VendorID, info.OrganizationID)
+		if err != nil {
+			msg := "vendor not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	var policy MatchPolicy
+	policy.Name = info.Name
+	policy.VendorID = info.VendorID
+	policy.MatchType = info.MatchType
+	policy.QuantityTolerance = info.QuantityTolerance
+	policy.PriceTolerance = info.PriceTolerance
+	policy.Status = info.Status
+	policy.Updated = time.Now()
+	policy.UpdatedBy = info.Email
+	err = repo.UpdateMatchPolicy(matchPolicyID, policy)
+	if err != nil {
+		msg := "update match policy error: "
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetMatchPolicyByID(info.OrganizationID, matchPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, err
+}
+
+func (s *purchaseorderService) DeleteMatchPolicy(matchPolicyID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	_, err = repo.GetMatchPolicyByID(organizationID, matchPolicyID)
+	if err != nil {
+		msg := "match policy not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteMatchPolicy(matchPolicyID, email)
+	if err != nil {
+		msg := "delete match policy error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// checkBillItemMatch compares a bill line with the received quantity (three-way
+// policies only) and the purchase order unit amount before tax, recording every
+// exception that is out of tolerance. It returns true when the bill has to be put on hold.
+func checkBillItemMatch(repo *purchaseorderRepository, policy *MatchPolicyResponse, poItem *PurchaseorderItemResponse, billItem BillItem, quantityBilled int) (bool, error) {
+	if policy == nil {
+		return false, nil
+	}
+	var exceptions []BillMatchException
+	if policy.MatchType == 3 {
+		allowed := float64(poItem.QuantityReceived) * (1 + policy.QuantityTolerance/100)
+		if float64(quantityBilled) > allowed {
+			var exception BillMatchException
+			exception.ExceptionType = "quantity"
+			exception.Expected = float64(poItem.QuantityReceived)
+			exception.Actual = float64(quantityBilled)
+			exception.Variance = 100
+			if poItem.QuantityReceived > 0 {
+				exception.Variance = (float64(quantityBilled) - float64(poItem.QuantityReceived)) / float64(poItem.QuantityReceived) * 100
+			}
+			exception.Tolerance = policy.QuantityTolerance
+			exceptions = append(exceptions, exception)
+		}
+	}
+	//compare the unit amounts before tax, either document may be tax inclusive
+	expectedRate, actualRate := poItem.Rate, billItem.Rate
+	if poItem.Quantity > 0 {
+		expectedRate = poItem.Amount / float64(poItem.Quantity)
+	}
+	if billItem.Quantity > 0 {
+		actualRate = billItem.Amount / float64(billItem.Quantity)
+	}
+	variance := 0.0
+	if expectedRate != 0 {
+		variance = math.Abs(actualRate-expectedRate) / expectedRate * 100
+	} else if actualRate != 0 {
+		variance = 100
+	}
+	if variance > policy.PriceTolerance {
+		var exception BillMatchException
+		exception.ExceptionType = "price"
+		exception.Expected = expectedRate
+		exception.Actual = actualRate
+		exception.Variance = variance
+		exception.Tolerance = policy.PriceTolerance
+		exceptions = append(exceptions, exception)
+	}
+	for _, exception := range exceptions {
+		exception.OrganizationID = billItem.OrganizationID
+		exception.BillMatchExceptionID = "bme-" + xid.New().String()
+		exception.BillID = billItem.BillID
+		exception.BillItemID = billItem.BillItemID
+		exception.PurchaseorderItemID = billItem.PurchaseorderItemID
+		exception.ItemID = billItem.ItemID
+		exception.Status = 1
+		exception.Created = time.Now()
+		exception.CreatedBy = billItem.CreatedBy
+		exception.Updated = time.Now()
+		exception.UpdatedBy = billItem.UpdatedBy
+		err := repo.CreateBillMatchException(exception)
+		if err != nil {
+			msg := "create bill match exception error: "
+			return false, errors.New(msg)
+		}
+	}
+	return len(exceptions) > 0, nil
+}
+
+func (s *purchaseorderService) ReleaseBill(billID string, info BillReleaseNew) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	bill, err := repo.GetBillByID(info.OrganizationID, billID)
+	if err != nil {
+		msg := "get bill error"
+		return errors.New(msg)
+	}
+	if bill.MatchStatus != 2 {
+		msg := "bill is not on hold"
+		return errors.New(msg)
+	}
+	err = repo.UpdateBillMatchStatus(billID, 3, info.Email)
+	if err != nil {
+		msg := "update bill match status error"
+		return errors.New(msg)
+	}
+	tx.Commit()
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "bill"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = info.User
+	newEvent.ReferenceID = billID
+	newEvent.Description = "Bill Released"
+	if info.Notes != "" {
+		newEvent.Description = "Bill Released: " + info.Notes
+	}
+	newEvent.OrganizationID = info.OrganizationID
+	newEvent.Email = info.Email
+	rabbit, _ := queue.GetConn()
+	msg, _ := json.Marshal(newEvent)
+	err = rabbit.Publish("NewHistoryCreated", msg)
+	if err != nil {
+		msg := "create event NewHistoryCreated error"
+		return errors.New(msg)
+	}
+	return err
+}
+
+func (s *purchaseorderService) GetBillMismatchList(filter BillMismatchFilter) (int, *[]BillMismatchResponse, error) {
+	if filter.MatchStatus == 0 {
+		filter.MatchStatus = 2
+	}
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	count, err := query.GetBillMismatchCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetBillMismatchList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+// recurring bill
+
+// recurringDate returns the date of the nth occurrence of a schedule. Month
+// based frequencies keep the day of the start date, capped at the month end.
+func recurringDate(startDate string, frequency, repeatEvery, n int) (string, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return "", err
+	}
+	if repeatEvery < 1 {
+		repeatEvery = 1
+	}
+	months := 0
+	switch frequency {
+	case 1:
+		return start.AddDate(0, 0, n*repeatEvery).Format("2006-01-02"), nil
+	case 2:
+		return start.AddDate(0, 0, 7*n*repeatEvery).Format("2006-01-02"), nil
+	case 3:
+		months = n * repeatEvery
+	case 4:
+		months = 3 * n * repeatEvery
+	default:
+		months = 12 * n * repeatEvery
+	}
+	firstDay := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	day := start.Day()
+	lastDay := firstDay.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstDay.AddDate(0, 0, day-1).Format("2006-01-02"), nil
+}
+
+// recurringOccurrences counts the occurrences of a schedule up to the end
+// date and the number of occurrences, whichever comes first
+func recurringOccurrences(startDate, endDate string, frequency, repeatEvery, occurrences int) (int, error) {
+	if endDate == "" {
+		return occurrences, nil
+	}
+	n := 0
+	for occurrences == 0 || n < occurrences {
+		date, err := recurringDate(startDate, frequency, repeatEvery, n)
+		if err != nil {
+			return 0, err
+		}
+		if date > endDate {
+			break
+		}
+		n++
+	}
+	return n, nil
+}
+
+// checkRecurringBillItems checks the profile lines against the purchase order. Every bill
+// takes its quantity from the purchase order line, so the occurrences still to come
+// must fit in the quantity not billed yet.
+func checkRecurringBillItems(repo *purchaseorderRepository, info RecurringBillNew, generatedCount int) error {
+	if info.EndDate != "" && info.EndDate < info.StartDate {
+		msg := "end date can not be earlier than start date"
+		return errors.New(msg)
+	}
+	if info.EndDate == "" && info.Occurrences == 0 {
+		msg := "end date or occurrences required"
+		return errors.New(msg)
+	}
+	occurrences, err := recurringOccurrences(info.StartDate, info.EndDate, info.Frequency, info.RepeatEvery, info.Occurrences)
+	if err != nil {
+		msg := "calculate occurrences error"
+		return errors.New(msg)
+	}
+	remaining := occurrences - generatedCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	for _, itemRow := range info.Items {
+		soItem, err := repo.GetPurchaseorderItemByID(info.OrganizationID, info.PurchaseorderID, itemRow.ItemID)
+		if err != nil {
+			msg := "purchase order item not exist"
+			return errors.New(msg)
+		}
+		if soItem.PurchaseorderItemID != itemRow.PurchaseorderItemID {
+			msg := "purchase order item id error"
+			return errors.New(msg)
+		}
+		if itemRow.Quantity*remaining > soItem.Quantity-soItem.QuantityBilled {
+			msg := fmt.Sprintf("%s: %d occurrences of %d exceed the quantity not billed of %d", soItem.SKU, remaining, itemRow.Quantity, soItem.Quantity-soItem.QuantityBilled)
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func createRecurringBillItems(repo *purchaseorderRepository, recurringBillID string, info RecurringBillNew) error {
+	for _, itemRow := range info.Items {
+		var recurringBillItem RecurringBillItem
+		recurringBillItem.OrganizationID = info.OrganizationID
+		recurringBillItem.RecurringBillID = recurringBillID
+		recurringBillItem.RecurringBillItemID = "rbili-" + xid.New().String()
+		recurringBillItem.PurchaseorderItemID = itemRow.PurchaseorderItemID
+		recurringBillItem.ItemID = itemRow.ItemID
+		recurringBillItem.Quantity = itemRow.Quantity
+		recurringBillItem.Rate = itemRow.Rate
+		recurringBillItem.TaxID = itemRow.TaxID
+		recurringBillItem.Status = 1
+		recurringBillItem.Created = time.Now()
+		recurringBillItem.CreatedBy = info.Email
+		recurringBillItem.Updated = time.Now()
+		recurringBillItem.UpdatedBy = info.Email
+		err := repo.CreateRecurringBillItem(recurringBillItem)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *purchaseorderService) NewRecurringBill(info RecurringBillNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	isConflict, err := repo.CheckRecurringBillConfict("", info.OrganizationID, info.ProfileName)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "recurring bill profile name exists"
+		return nil, errors.New(msg)
+	}
+	po, err := repo.GetPurchaseorderByID(info.OrganizationID, info.PurchaseorderID)
+	if err != nil {
+		msg := "get purchase order error: "
+		return nil, errors.New(msg)
+	}
+	err = checkRecurringBillItems(repo, info, 0)
+	if err != nil {
+		return nil, err
+	}
+	if info.PaymentTermID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	recurringBillID := "rbil-" + xid.New().String()
+	err = createRecurringBillItems(repo, recurringBillID, info)
+	if err != nil {
+		msg := "create recurring bill item error: "
+		return nil, errors.New(msg)
+	}
+	var recurringBill RecurringBill
+	recurringBill.OrganizationID = info.OrganizationID
+	recurringBill.RecurringBillID = recurringBillID
+	recurringBill.ProfileName = info.ProfileName
+	recurringBill.PurchaseorderID = info.PurchaseorderID
+	recurringBill.VendorID = po.VendorID
+	recurringBill.BillPrefix = info.BillPrefix
+	recurringBill.Frequency = info.Frequency
+	recurringBill.RepeatEvery = info.RepeatEvery
+	if recurringBill.RepeatEvery == 0 {
+		recurringBill.RepeatEvery = 1
+	}
+	recurringBill.StartDate = info.StartDate
+	recurringBill.EndDate = info.EndDate
+	recurringBill.Occurrences = info.Occurrences
+	recurringBill.GeneratedCount = 0
+	recurringBill.NextDate = info.StartDate
+	recurringBill.PaymentTermID = info.PaymentTermID
+	recurringBill.DiscountType = info.DiscountType
+	recurringBill.DiscountValue = info.DiscountValue
+	recurringBill.ShippingFee = info.ShippingFee
+	recurringBill.ShippingTaxID = info.ShippingTaxID
+	recurringBill.TaxInclusive = info.TaxInclusive
+	recurringBill.Notes = info.Notes
+	recurringBill.Status = info.Status
+	recurringBill.Created = time.Now()
+	recurringBill.CreatedBy = info.Email
+	recurringBill.Updated = time.Now()
+	recurringBill.UpdatedBy = info.Email
+	err = repo.CreateRecurringBill(recurringBill)
+	if err != nil {
+		msg := "create recurring bill error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &recurringBillID, nil
+}
+
+func (s *purchaseorderService) GetRecurringBillList(filter RecurringBillFilter) (int, *[]RecurringBillResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	count, err := query.GetRecurringBillCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetRecurringBillList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *purchaseorderService) GetRecurringBillByID(organizationID, id string) (*RecurringBillResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	recurringBill, err := query.GetRecurringBillByID(organizationID, id)
+	if err != nil {
+		msg := "get recurring bill error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return recurringBill, nil
+}
+
+func (s *purchaseorderService) GetRecurringBillItemList(recurringBillID, organizationID string) (*[]RecurringBillItemResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	_, err := query.GetRecurringBillByID(organizationID, recurringBillID)
+	if err != nil {
+		msg := "get recurring bill error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetRecurringBillItemList(organizationID, recurringBillID)
+	return list, err
+}
+
+func (s *purchaseorderService) GetRecurringBillRunList(recurringBillID, organizationID string) (*[]RecurringBillRunResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	_, err := query.GetRecurringBillByID(organizationID, recurringBillID)
+	if err != nil {
+		msg := "get recurring bill error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetRecurringBillRunList(organizationID, recurringBillID)
+	return list, err
+}
+
+func (s *purchaseorderService) UpdateRecurringBill(recurringBillID string, info RecurringBillNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	isConflict, err := repo.CheckRecurringBillConfict(recurringBillID, info.OrganizationID, info.ProfileName)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "recurring bill profile name exists"
+		return nil, errors.New(msg)
+	}
+	oldRecurringBill, err := repo.GetRecurringBillByID(info.OrganizationID, recurringBillID)
+	if err != nil {
+		msg := "recurring bill not exist"
+		return nil, errors.New(msg)
+	}
+	nextDate := info.StartDate
+	if oldRecurringBill.GeneratedCount > 0 {
+		if oldRecurringBill.StartDate != info.StartDate || oldRecurringBill.PurchaseorderID != info.PurchaseorderID || oldRecurringBill.Frequency != info.Frequency {
+			msg := "schedule can not be changed after bills generated"
+			return nil, errors.New(msg)
+		}
+		nextDate, err = recurringDate(info.StartDate, info.Frequency, info.RepeatEvery, oldRecurringBill.GeneratedCount)
+		if err != nil {
+			msg := "calculate next date error"
+			return nil, errors.New(msg)
+		}
+	}
+	po, err := repo.GetPurchaseorderByID(info.OrganizationID, info.PurchaseorderID)
+	if err != nil {
+		msg := "get purchase order error: "
+		return nil, errors.New(msg)
+	}
+	err = checkRecurringBillItems(repo, info, oldRecurringBill.GeneratedCount)
+	if err != nil {
+		return nil, err
+	}
+	if info.PaymentTermID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	err = repo.DeleteRecurringBillItem(recurringBillID, info.Email)
+	if err != nil {
+		msg := "delete recurring bill item error: "
+		return nil, errors.New(msg)
+	}
+	err = createRecurringBillItems(repo, recurringBillID, info)
+	if err != nil {
+		msg := "create recurring bill item error: "
+		return nil, errors.New(msg)
+	}
+	var recurringBill RecurringBill
+	recurringBill.ProfileName = info.ProfileName
+	recurringBill.PurchaseorderID = info.PurchaseorderID
+	recurringBill.VendorID = po.VendorID
+	recurringBill.BillPrefix = info.BillPrefix
+	recurringBill.Frequency = info.Frequency
+	recurringBill.RepeatEvery = info.RepeatEvery
+	if recurringBill.RepeatEvery == 0 {
+		recurringBill.RepeatEvery = 1
+	}
+	recurringBill.StartDate = info.StartDate
+	recurringBill.EndDate = info.EndDate
+	recurringBill.Occurrences = info.Occurrences
+	recurringBill.NextDate = nextDate
+	recurringBill.PaymentTermID = info.PaymentTermID
+	recurringBill.DiscountType = info.DiscountType
+	recurringBill.DiscountValue = info.DiscountValue
+	recurringBill.ShippingFee = info.ShippingFee
+	recurringBill.ShippingTaxID = info.ShippingTaxID
+	recurringBill.TaxInclusive = info.TaxInclusive
+	recurringBill.Notes = info.Notes
+	recurringBill.Status = info.Status
+	if (recurringBill.Occurrences > 0 && oldRecurringBill.GeneratedCount >= recurringBill.Occurrences) || (recurringBill.EndDate != "" && nextDate > recurringBill.EndDate) {
+		recurringBill.Status = 3
+	}
+	recurringBill.Updated = time.Now()
+	recurringBill.UpdatedBy = info.Email
+	err = repo.UpdateRecurringBill(recurringBillID, recurringBill)
+	if err != nil {
+		msg := "update recurring bill error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &recurringBillID, nil
+}
+
+func (s *purchaseorderService) DeleteRecurringBill(recurringBillID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	_, err = repo.GetRecurringBillByID(organizationID, recurringBillID)
+	if err != nil {
+		msg := "recurring bill not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteRecurringBill(recurringBillID, email)
+	if err != nil {
+		msg := "delete recurring bill error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// RunRecurringBill generates the bills of every period of the profile
+// due up to today. Periods that already have an bill are skipped, so the
+// run can be repeated safely.
+func (s *purchaseorderService) RunRecurringBill(recurringBillID, organizationID, user, email string) (*[]RecurringBillRunResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	recurringBill, err := query.GetRecurringBillByID(organizationID, recurringBillID)
+	if err != nil {
+		msg := "recurring bill not exist"
+		return nil, errors.New(msg)
+	}
+	if recurringBill.Status != 1 {
+		msg := "recurring bill is not active"
+		return nil, errors.New(msg)
+	}
+	runs, err := s.generateRecurringBills(recurringBill, time.Now().Format("2006-01-02"), user, email)
+	return runs, err
+}
+
+// RunDueRecurringBills generates the due bills of all active profiles.
+func (s *purchaseorderService) RunDueRecurringBills() error {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	today := time.Now().Format("2006-01-02")
+	list, err := query.GetDueRecurringBillList(today)
+	if err != nil {
+		return err
+	}
+	for _, recurringBill := range *list {
+		profile := recurringBill
+		_, err := s.generateRecurringBills(&profile, today, "system", profile.UpdatedBy)
+		if err != nil {
+			err = notifyRecurringBillFailure(&profile, err)
+			if err != nil {
+				fmt.Println("recurring bill " + profile.RecurringBillID + " error: " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// notifyRecurringBillFailure raises a notification when the job could not
+// generate a due bill, the failed run itself is kept on the run list
+func notifyRecurringBillFailure(profile *RecurringBillResponse, runErr error) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	var notification common.NotificationNew
+	notification.NotificationType = "recurring_bill"
+	notification.ReferenceID = profile.RecurringBillID
+	notification.Title = "Recurring bill failed: " + profile.ProfileName
+	notification.Description = runErr.Error()
+	notification.OrganizationID = profile.OrganizationID
+	notification.Email = profile.UpdatedBy
+	err = common.RaiseNotification(common.NewCommonRepository(tx), notification)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *purchaseorderService) generateRecurringBills(profile *RecurringBillResponse, today, user, email string) (*[]RecurringBillRunResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	items, err := query.GetRecurringBillItemList(profile.OrganizationID, profile.RecurringBillID)
+	if err != nil {
+		return nil, err
+	}
+	var runs []RecurringBillRunResponse
+	generatedCount := profile.GeneratedCount
+	nextDate := profile.NextDate
+	status := profile.Status
+	for status == 1 && nextDate <= today {
+		if (profile.Occurrences > 0 && generatedCount >= profile.Occurrences) || (profile.EndDate != "" && nextDate > profile.EndDate) {
+			status = 3
+			break
+		}
+		billID, runErr := s.generateRecurringBill(profile, items, nextDate, user, email)
+		run, err := s.saveRecurringBillRun(profile, nextDate, billID, runErr, email)
+		if err != nil {
+			return &runs, err
+		}
+		runs = append(runs, *run)
+		if runErr != nil {
+			return &runs, runErr
+		}
+		generatedCount++
+		nextDate, err = recurringDate(profile.StartDate, profile.Frequency, profile.RepeatEvery, generatedCount)
+		if err != nil {
+			return &runs, err
+		}
+		if (profile.Occurrences > 0 && generatedCount >= profile.Occurrences) || (profile.EndDate != "" && nextDate > profile.EndDate) {
+			status = 3
+		}
+		err = s.updateRecurringBillSchedule(profile.RecurringBillID, nextDate, generatedCount, status, email)
+		if err != nil {
+			return &runs, err
+		}
+	}
+	if status != profile.Status {
+		err = s.updateRecurringBillSchedule(profile.RecurringBillID, nextDate, generatedCount, status, email)
+		if err != nil {
+			return &runs, err
+		}
+	}
+	return &runs, nil
+}
+
+func (s *purchaseorderService) generateRecurringBill(profile *RecurringBillResponse, items *[]RecurringBillItemResponse, periodDate, user, email string) (string, error) {
+	billNumber := profile.BillPrefix + "-" + strings.ReplaceAll(periodDate, "-", "")
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return "", errors.New(msg)
+	}
+	repo := NewPurchaseorderRepository(tx)
+	run, err := repo.GetRecurringBillRunByPeriod(profile.OrganizationID, profile.RecurringBillID, periodDate)
+	if err == nil && run.Status == 1 {
+		tx.Rollback()
+		return run.BillID, nil
+	}
+	billID, err := repo.GetBillIDByNumber(profile.OrganizationID, billNumber)
+	tx.Rollback()
+	if err == nil {
+		return billID, nil
+	}
+	var info BillNew
+	info.BillNumber = billNumber
+	info.BillDate = periodDate
+	info.PaymentTermID = profile.PaymentTermID
+	info.DiscountType = profile.DiscountType
+	info.DiscountValue = profile.DiscountValue
+	info.ShippingFee = profile.ShippingFee
+	info.ShippingTaxID = profile.ShippingTaxID
+	info.TaxInclusive = profile.TaxInclusive
+	info.Notes = profile.Notes
+	for _, itemRow := range *items {
+		var billItem BillItemNew
+		billItem.PurchaseorderItemID = itemRow.PurchaseorderItemID
+		billItem.ItemID = itemRow.ItemID
+		billItem.Quantity = itemRow.Quantity
+		billItem.Rate = itemRow.Rate
+		billItem.TaxID = itemRow.TaxID
+		info.Items = append(info.Items, billItem)
+	}
+	info.OrganizationID = profile.OrganizationID
+	info.User = user
+	info.Email = email
+	newBillID, err := s.NewBill(profile.PurchaseorderID, info)
+	if err != nil {
+		return "", err
+	}
+	return *newBillID, nil
+}
+
+func (s *purchaseorderService) saveRecurringBillRun(profile *RecurringBillResponse, periodDate, billID string, runErr error, email string) (*RecurringBillRunResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	status := 1
+	message := "Bill Created"
+	if runErr != nil {
+		status = 2
+		message = runErr.Error()
+	}
+	oldRun, err := repo.GetRecurringBillRunByPeriod(profile.OrganizationID, profile.RecurringBillID, periodDate)
+	if err == nil {
+		err = repo.UpdateRecurringBillRun(oldRun.RecurringBillRunID, billID, message, status, email)
+		if err != nil {
+			msg := "update recurring bill run error: "
+			return nil, errors.New(msg)
+		}
+	} else {
+		var run RecurringBillRun
+		run.OrganizationID = profile.OrganizationID
+		run.RecurringBillID = profile.RecurringBillID
+		run.RecurringBillRunID = "rbilr-" + xid.New().String()
+		run.PeriodDate = periodDate
+		run.BillID = billID
+		run.Message = message
+		run.Status = status
+		run.Created = time.Now()
+		run.CreatedBy = email
+		run.Updated = time.Now()
+		run.UpdatedBy = email
+		err = repo.CreateRecurringBillRun(run)
+		if err != nil {
+			msg := "create recurring bill run error: "
+			return nil, errors.New(msg)
+		}
+	}
+	res, err := repo.GetRecurringBillRunByPeriod(profile.OrganizationID, profile.RecurringBillID, periodDate)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, nil
+}
+
+func (s *purchaseorderService) updateRecurringBillSchedule(recurringBillID, nextDate string, generatedCount, status int, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewPurchaseorderRepository(tx)
+	err = repo.UpdateRecurringBillSchedule(recurringBillID, nextDate, generatedCount, status, email)
+	if err != nil {
+		msg := "update recurring bill schedule error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+//reorder
+
+func (s *purchaseorderService) GetReorderItemList(filter ReorderItemFilter) (int, *[]ReorderItemResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	count, err := query.GetReorderItemCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetReorderItemList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *purchaseorderService) RunReorder(organizationID, user, email string) (*ReorderRunResponse, error) {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	list, err := query.GetReorderItems(organizationID)
+	if err != nil {
+		msg := "get reorder items error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return s.createReorderPurchaseorders(organizationID, *list, user, email)
+}
+
+// RunReorderAlerts creates the draft purchase orders for the items below
+// their reorder point in every organization.
+func (s *purchaseorderService) RunReorderAlerts() error {
+	db := database.RDB()
+	query := NewPurchaseorderQuery(db)
+	list, err := query.GetReorderItems("")
+	if err != nil {
+		return err
+	}
+	var organizations []string
+	organizationItems := make(map[string][]ReorderItemResponse)
+	for _, itemRow := range *list {
+		if _, ok := organizationItems[itemRow.OrganizationID]; !ok {
+			organizations = append(organizations, itemRow.OrganizationID)
+		}
+		organizationItems[itemRow.OrganizationID] = append(organizationItems[itemRow.OrganizationID], itemRow)
+	}
+	for _, organizationID := range organizations {
+		_, err := s.createReorderPurchaseorders(organizationID, organizationItems[organizationID], "system", "system")
+		if err != nil {
+			fmt.Println("reorder " + organizationID + " error: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// createReorderPurchaseorders groups the items by default vendor and creates
+// one draft purchase order per vendor, left for review before it is issued.
+// Items without a default vendor and vendors whose order fails only get an
+// alert, refreshed on later runs rather than repeated.
+func (s *purchaseorderService) createReorderPurchaseorders(organizationID string, items []ReorderItemResponse, user, email string) (*ReorderRunResponse, error) {
+	var res ReorderRunResponse
+	var notifications []common.NotificationNew
+	var alerts []common.NotificationNew
+	var vendors []string
+	vendorItems := make(map[string][]ReorderItemResponse)
+	for _, itemRow := range items {
+		if itemRow.DefaultVendorID == "" {
+			var notification common.NotificationNew
+			notification.NotificationType = "reorder"
+			notification.ReferenceID = itemRow.ItemID
+			notification.Title = "Reorder point reached: " + itemRow.SKU
+			notification.Description = fmt.Sprintf("%s is below its reorder point of %d (available %d, on order %d) and has no default vendor", itemRow.Name, itemRow.ReorderStock, itemRow.StockAvailable, itemRow.OpenQuantity)
+			alerts = append(alerts, notification)
+			res.NoVendorCount++
+			continue
+		}
+		if _, ok := vendorItems[itemRow.DefaultVendorID]; !ok {
+			vendors = append(vendors, itemRow.DefaultVendorID)
+		}
+		vendorItems[itemRow.DefaultVendorID] = append(vendorItems[itemRow.DefaultVendorID], itemRow)
+	}
+	commonService := common.NewCommonService()
+	today := time.Now().Format("2006-01-02")
+	for _, vendorID := range vendors {
+		var numberFilter common.NumberFilter
+		numberFilter.NumberType = "purchaseorder"
+		numberFilter.OrganizationID = organizationID
+		number, err := commonService.GetNextNumber(numberFilter)
+		if err != nil {
+			return &res, err
+		}
+		var info PurchaseorderNew
+		info.PurchaseorderNumber = *number
+		info.PurchaseorderDate = today
+		info.ExpectedDeliveryDate = today
+		info.VendorID = vendorID
+		info.Notes = "Auto-generated for items below their reorder point"
+		for _, itemRow := range vendorItems[vendorID] {
+			var poItem PurchaseorderItemNew
+			poItem.ItemID = itemRow.ItemID
+			poItem.Quantity = itemRow.OrderQuantity
+			poItem.Rate = itemRow.CostPrice
+			info.Items = append(info.Items, poItem)
+		}
+		info.OrganizationID = organizationID
+		info.User = user
+		info.Email = email
+		var notification common.NotificationNew
+		notification.NotificationType = "reorder"
+		purchaseorderID, err := s.NewPurchaseorder(info)
+		if err != nil {
+			notification.ReferenceID = vendorID
+			notification.Title = "Reorder purchase order failed: " + vendorItems[vendorID][0].VendorName
+			notification.Description = err.Error()
+			alerts = append(alerts, notification)
+			res.FailedCount++
+		} else {
+			notification.ReferenceID = *purchaseorderID
+			notification.Title = "Draft purchase order created: " + *number
+			notification.Description = fmt.Sprintf("%d item(s) below their reorder point for %s, review and issue the purchase order", len(vendorItems[vendorID]), vendorItems[vendorID][0].VendorName)
+			notifications = append(notifications, notification)
+			res.PurchaseorderIDs = append(res.PurchaseorderIDs, *purchaseorderID)
+		}
+	}
+	if len(notifications) == 0 && len(alerts) == 0 {
+		return &res, nil
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return &res, errors.New(msg)
+	}
+	defer tx.Rollback()
+	commonRepo := common.NewCommonRepository(tx)
+	for _, notification := range notifications {
+		notification.OrganizationID = organizationID
+		notification.Email = email
+		err = common.RaiseNotification(commonRepo, notification)
+		if err != nil {
+			return &res, err
+		}
+	}
+	for _, alert := range alerts {
+		alert.OrganizationID = organizationID
+		alert.Email = email
+		err = common.RefreshNotification(commonRepo, alert)
+		if err != nil {
+			return &res, err
+		}
+	}
+	tx.Commit()
+	return &res, nil
+}
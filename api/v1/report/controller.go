@@ -124,3 +124,34 @@ func GetItemReport(c *gin.Context) {
 	}
 	response.Response(c, res)
 }
+
+// @Summary 税务汇总报告
+// @Id 905
+// @Tags 报告管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param date_from query string true "开始日期"
+// @Param date_to query string true "结束日期"
+// @Param period query string false "统计周期（month/quarter/year）"
+// @Param tax_id query string false "税率ID"
+// @Success 200 object response.SuccessRes{data=TaxReportResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /taxreports [GET]
+func GetTaxReport(c *gin.Context) {
+	var filter TaxReportFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	reportService := NewReportService()
+	res, err := reportService.GetTaxReport(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
@@ -98,3 +98,37 @@ type ItemReportResponse struct {
 	SellingPrice float64 `db:"selling_price" json:"selling_price"`
 	CostPrice    float64 `db:"cost_price" json:"cost_price"`
 }
+
+// tax report
+type TaxReportFilter struct {
+	DateFrom       string `form:"date_from" binding:"required,datetime=2006-01-02"`
+	DateTo         string `form:"date_to" binding:"required,datetime=2006-01-02"`
+	Period         string `form:"period" binding:"omitempty,oneof=month quarter year"`
+	TaxID          string `form:"tax_id" binding:"omitempty,max=64"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type TaxReportResponse struct {
+	PeriodReports []TaxPeriodReportResponse `json:"period_reports"`
+	TaxCollected  float64                   `json:"tax_collected"`
+	TaxPaid       float64                   `json:"tax_paid"`
+	NetTax        float64                   `json:"net_tax"`
+}
+
+type TaxPeriodReportResponse struct {
+	Period       string                       `json:"period"`
+	Components   []TaxComponentReportResponse `json:"components"`
+	TaxCollected float64                      `json:"tax_collected"`
+	TaxPaid      float64                      `json:"tax_paid"`
+	NetTax       float64                      `json:"net_tax"`
+}
+
+type TaxComponentReportResponse struct {
+	Period           string  `db:"period" json:"period"`
+	TaxID            string  `db:"tax_id" json:"tax_id"`
+	TaxName          string  `db:"tax_name" json:"tax_name"`
+	TaxableCollected float64 `db:"taxable_collected" json:"taxable_collected"`
+	TaxCollected     float64 `db:"tax_collected" json:"tax_collected"`
+	TaxablePaid      float64 `db:"taxable_paid" json:"taxable_paid"`
+	TaxPaid          float64 `db:"tax_paid" json:"tax_paid"`
+}
@@ -123,3 +123,42 @@ func (r *reportQuery) GetItemReport(filter ItemReportFilter) (*[]ItemReportRespo
 		WHERE `+strings.Join(where, " AND "), args...)
 	return &res, err
 }
+
+//tax
+func (r *reportQuery) GetTaxReport(filter TaxReportFilter) (*[]TaxComponentReportResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.TaxID; v != "" {
+		where, args = append(where, "tax_id = ?"), append(args, v)
+	}
+	if v := filter.DateFrom; v != "" {
+		where, args = append(where, "record_date >= ?"), append(args, v)
+	}
+	if v := filter.DateTo; v != "" {
+		where, args = append(where, "record_date <= ?"), append(args, v)
+	}
+	period := "DATE_FORMAT(record_date, '%Y-%m')"
+	if filter.Period == "quarter" {
+		period = "CONCAT(YEAR(record_date), '-Q', QUARTER(record_date))"
+	} else if filter.Period == "year" {
+		period = "DATE_FORMAT(record_date, '%Y')"
+	}
+	var res []TaxComponentReportResponse
+	err := r.conn.Select(&res, `
+		SELECT 
+		`+period+` as period,
+		tax_id,
+		tax_name,
+		IFNULL(SUM(CASE WHEN reference_type = 'invoice' THEN taxable_amount ELSE 0 END), 0) as taxable_collected,
+		IFNULL(SUM(CASE WHEN reference_type = 'invoice' THEN tax_amount ELSE 0 END), 0) as tax_collected,
+		IFNULL(SUM(CASE WHEN reference_type = 'bill' THEN taxable_amount ELSE 0 END), 0) as taxable_paid,
+		IFNULL(SUM(CASE WHEN reference_type = 'bill' THEN tax_amount ELSE 0 END), 0) as tax_paid
+		FROM s_tax_records
+		WHERE `+strings.Join(where, " AND ")+`
+		GROUP BY period, tax_id, tax_name
+		ORDER BY period, tax_name
+	`, args...)
+	return &res, err
+}
@@ -7,5 +7,6 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.GET("/purchasereports", GetPurchaseReport)
 	g.GET("/adjustmentreports", GetAdjustmentReport)
 	g.GET("/itemreports", GetItemReport)
+	g.GET("/taxreports", GetTaxReport)
 
 }
@@ -142,3 +142,43 @@ func (s *reportService) GetItemReport(filter ItemReportFilter) (*[]ItemReportRes
 	res, err := query.GetItemReport(filter)
 	return res, err
 }
+
+//tax
+
+func (s *reportService) GetTaxReport(filter TaxReportFilter) (*TaxReportResponse, error) {
+	db := database.RDB()
+	query := NewReportQuery(db)
+	components, err := query.GetTaxReport(filter)
+	if err != nil {
+		return nil, err
+	}
+	var res TaxReportResponse
+	var periods []TaxPeriodReportResponse
+	for _, component := range *components {
+		periodExist := false
+		for idx, period := range periods {
+			if component.Period == period.Period {
+				periods[idx].Components = append(period.Components, component)
+				periods[idx].TaxCollected += component.TaxCollected
+				periods[idx].TaxPaid += component.TaxPaid
+				periods[idx].NetTax += component.TaxCollected - component.TaxPaid
+				periodExist = true
+				break
+			}
+		}
+		if !periodExist {
+			var newPeriod TaxPeriodReportResponse
+			newPeriod.Period = component.Period
+			newPeriod.Components = append(newPeriod.Components, component)
+			newPeriod.TaxCollected = component.TaxCollected
+			newPeriod.TaxPaid = component.TaxPaid
+			newPeriod.NetTax = component.TaxCollected - component.TaxPaid
+			periods = append(periods, newPeriod)
+		}
+		res.TaxCollected += component.TaxCollected
+		res.TaxPaid += component.TaxPaid
+	}
+	res.NetTax = res.TaxCollected - res.TaxPaid
+	res.PeriodReports = periods
+	return &res, err
+}
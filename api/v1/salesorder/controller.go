@@ -1,8 +1,11 @@
 package salesorder
 
 import (
+	"go-api/api/v1/common"
+	"go-api/core/carrier"
 	"go-api/core/response"
 	"go-api/service"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -92,6 +95,7 @@ func UpdateSalesorder(c *gin.Context) {
 	info.User = claims.UserName
 	info.Email = claims.Email
 	info.OrganizationID = claims.OrganizationID
+	info.RoleID = claims.RoleID
 	salesorderService := NewSalesorderService()
 	new, err := salesorderService.UpdateSalesorder(uri.ID, info)
 	if err != nil {
@@ -198,11 +202,19 @@ func ConfirmSalesorder(c *gin.Context) {
 	}
 	claims := c.MustGet("claims").(*service.CustomClaims)
 	salesorderService := NewSalesorderService()
-	err := salesorderService.ConfirmSalesorder(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	status, err := salesorderService.ConfirmSalesorder(uri.ID, claims.OrganizationID, claims.RoleID, claims.UserName, claims.Email)
 	if err != nil {
 		response.ResponseError(c, "DatabaseError", err)
 		return
 	}
+	if status == 1 {
+		response.Response(c, "pending approval")
+		return
+	}
+	if status == 4 {
+		response.Response(c, "on hold")
+		return
+	}
 	response.Response(c, "ok")
 }
 
@@ -622,7 +634,7 @@ func GetShippingorderDetailList(c *gin.Context) {
 // @Produce application/json
 // @Param start_date query string false "开始时间"
 // @Param end_date query string false "结束时间"
-// @Param target_day query int false "目标时间"
+// @Param target_day query int false "目标天数 不含供应商交货期"
 // @Success 200 object response.ListRes{data=[]RequsitionResponse} 成功
 // @Failure 400 object response.ErrorRes 内部错误
 // @Router /requisitions [GET]
@@ -1051,3 +1063,1285 @@ func DeletePayment(c *gin.Context) {
 	}
 	response.Response(c, "OK")
 }
+
+// @Summary 新建循环发票
+// @Id 637
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param recurring_invoice_info body RecurringInvoiceNew true "循环发票信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices [POST]
+func NewRecurringInvoice(c *gin.Context) {
+	var info RecurringInvoiceNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewRecurringInvoice(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 循环发票列表
+// @Id 638
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param profile_name query string false "名称"
+// @Param salesorder_id query string false "销售单ID"
+// @Param customer_id query string false "客户ID"
+// @Param status query int false "状态"
+// @Success 200 object response.ListRes{data=[]RecurringInvoiceResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices [GET]
+func GetRecurringInvoiceList(c *gin.Context) {
+	var filter RecurringInvoiceFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetRecurringInvoiceList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取循环发票
+// @Id 639
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Success 200 object response.SuccessRes{data=RecurringInvoiceResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id [GET]
+func GetRecurringInvoiceByID(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	recurringInvoice, err := salesorderService.GetRecurringInvoiceByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, recurringInvoice)
+}
+
+// @Summary 根据ID更新循环发票
+// @Id 640
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Param recurring_invoice_info body RecurringInvoiceNew true "循环发票信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id [PUT]
+func UpdateRecurringInvoice(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info RecurringInvoiceNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.UserName
+	info.Email = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.UpdateRecurringInvoice(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除循环发票
+// @Id 641
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id [DELETE]
+func DeleteRecurringInvoice(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.DeleteRecurringInvoice(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 循环发票产品列表
+// @Id 642
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Success 200 object response.ListRes{data=[]RecurringInvoiceItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id/items [GET]
+func GetRecurringInvoiceItemList(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetRecurringInvoiceItemList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 循环发票生成记录
+// @Id 643
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Success 200 object response.ListRes{data=[]RecurringInvoiceRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id/runs [GET]
+func GetRecurringInvoiceRunList(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetRecurringInvoiceRunList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 生成到期的循环发票
+// @Id 644
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "循环发票ID"
+// @Success 200 object response.ListRes{data=[]RecurringInvoiceRunResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /recurringinvoices/:id/run [POST]
+func RunRecurringInvoice(c *gin.Context) {
+	var uri RecurringInvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.RunRecurringInvoice(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 发票PDF
+// @Id 645
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param id path string true "invoiceID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /invoices/:id/pdf [GET]
+func GetInvoicePDF(c *gin.Context) {
+	var uri InvoiceID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	content, fileName, err := salesorderService.GetInvoicePDF(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary 装箱单PDF
+// @Id 646
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param id path string true "packageID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /packages/:id/pdf [GET]
+func GetPackagePDF(c *gin.Context) {
+	var uri PackageID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	content, fileName, err := salesorderService.GetPackagePDF(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary 拣货单PDF
+// @Id 647
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param id path string true "pickingorderID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /pickingorders/:id/pdf [GET]
+func GetPickingorderPDF(c *gin.Context) {
+	var uri PickingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	content, fileName, err := salesorderService.GetPickingorderPDF(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary 提单PDF
+// @Id 648
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf
+// @Param id path string true "shippingorderID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/pdf [GET]
+func GetShippingorderPDF(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	content, fileName, err := salesorderService.GetShippingorderPDF(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary 获取物流报价
+// @Id 649
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param rate_info body ShippingRateNew true "报价信息"
+// @Success 200 object response.SuccessRes{data=[]carrier.Rate} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingrates [POST]
+func GetShippingRates(c *gin.Context) {
+	var info ShippingRateNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	rates, err := salesorderService.GetShippingRates(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, rates)
+}
+
+// @Summary 发货单面单列表
+// @Id 650
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "shippingorderID"
+// @Success 200 object response.SuccessRes{data=[]ShippingLabelResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/labels [GET]
+func GetShippingLabelList(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetShippingLabelList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 购买发货单面单
+// @Id 651
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "shippingorderID"
+// @Param label_info body ShippingLabelNew true "面单信息"
+// @Success 200 object response.SuccessRes{data=ShippingLabelResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/label [POST]
+func NewShippingLabel(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ShippingLabelNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewShippingLabel(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 下载发货单面单
+// @Id 652
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/pdf,text/plain
+// @Param id path string true "shippingorderID"
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/label [GET]
+func GetShippingLabel(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	label, err := salesorderService.GetShippingLabel(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	if label.Format == carrier.FormatZPL {
+		c.Header("Content-Disposition", "attachment; filename=\""+label.TrackingNumber+".zpl\"")
+		c.Data(http.StatusOK, "text/plain", label.Data)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+label.TrackingNumber+".pdf\"")
+	c.Data(http.StatusOK, "application/pdf", label.Data)
+}
+
+// @Summary 作废发货单面单
+// @Id 653
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "shippingorderID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/label [DELETE]
+func VoidShippingLabel(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.VoidShippingLabel(claims.OrganizationID, uri.ID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 发货单物流跟踪
+// @Id 654
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "shippingorderID"
+// @Success 200 object response.SuccessRes{data=carrier.Tracking} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/tracking [GET]
+func GetShippingorderTracking(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	tracking, err := salesorderService.GetShippingorderTracking(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, tracking)
+}
+
+// @Summary 物流轨迹推送
+// @Id 655
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "carrierID"
+// @Param X-Webhook-Token header string false "webhook token"
+// @Param token query string false "webhook token"
+// @Success 200 object response.SuccessRes{data=int} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /webhooks/carriers/:id [POST]
+func ReceiveTrackingWebhook(c *gin.Context) {
+	var uri CarrierWebhookID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	token := c.GetHeader("X-Webhook-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	salesorderService := NewSalesorderService()
+	saved, err := salesorderService.ReceiveTrackingWebhook(uri.ID, token, body)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, saved)
+}
+
+// @Summary 发货单物流轨迹列表
+// @Id 656
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "shippingorderID"
+// @Success 200 object response.SuccessRes{data=[]ShippingTrackingEventResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /shippingorders/:id/trackingevents [GET]
+func GetShippingTrackingEventList(c *gin.Context) {
+	var uri ShippingorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetShippingTrackingEventList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 信用冻结销售单列表
+// @Id 657
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param customer_id query string false "客户ID"
+// @Param status query int false "状态（1冻结中/2已释放）"
+// @Success 200 object response.ListRes{data=[]SalesorderHoldResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorderholds [GET]
+func GetSalesorderHoldList(c *gin.Context) {
+	var filter SalesorderHoldFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetSalesorderHoldList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 释放信用冻结销售单
+// @Id 658
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "销售单ID"
+// @Param release_info body SalesorderHoldRelease true "释放信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorders/:id/release [POST]
+func ReleaseSalesorderHold(c *gin.Context) {
+	var uri SalesorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info SalesorderHoldRelease
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.IsAdmin = claims.IsAdmin
+	info.RoleID = claims.RoleID
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	err := salesorderService.ReleaseSalesorderHold(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
+
+// @Summary 客户信用额度
+// @Id 659
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "客户ID"
+// @Success 200 object response.SuccessRes{data=CustomerCreditResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /customers/:id/credit [GET]
+func GetCustomerCredit(c *gin.Context) {
+	var uri CustomerID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	credit, err := salesorderService.GetCustomerCredit(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, credit)
+}
+
+// @Summary 审批销售单
+// @Id 660
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "销售单ID"
+// @Param approval_info body common.ApprovalAction true "审批信息（1通过/2驳回）"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorders/:id/approval [POST]
+func ApproveSalesorder(c *gin.Context) {
+	var uri SalesorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info common.ApprovalAction
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.RoleID = claims.RoleID
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	err := salesorderService.ApproveSalesorder(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "ok")
+}
+
+// @Summary 新建报价单
+// @Id 661
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param quote_info body QuoteNew true "报价单信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes [POST]
+func NewQuote(c *gin.Context) {
+	var info QuoteNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewQuote(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 报价单列表
+// @Id 662
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param quote_number query string false "报价单编码"
+// @Param customer_id query string false "客户ID"
+// @Param lead_id query string false "线索ID"
+// @Param status query int false "状态（1草稿/2已发送/3已接受/4已拒绝/5已过期/6已转销售单）"
+// @Success 200 object response.ListRes{data=[]QuoteResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes [GET]
+func GetQuoteList(c *gin.Context) {
+	var filter QuoteFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetQuoteList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据ID获取报价单
+// @Id 663
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.SuccessRes{data=QuoteResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id [GET]
+func GetQuoteByID(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	quote, err := salesorderService.GetQuoteByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, quote)
+}
+
+// @Summary 根据ID更新报价单
+// @Id 664
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Param quote_info body QuoteNew true "报价单信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id [PUT]
+func UpdateQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info QuoteNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.UserName
+	info.Email = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.UpdateQuote(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID删除报价单
+// @Id 665
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id [DELETE]
+func DeleteQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.DeleteQuote(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 报价单产品列表
+// @Id 666
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.ListRes{data=[]QuoteItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id/items [GET]
+func GetQuoteItemList(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetQuoteItemList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 发送报价单
+// @Id 667
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id/sent [POST]
+func SendQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.UpdateQuoteStatus(uri.ID, claims.OrganizationID, 2, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 接受报价单
+// @Id 668
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id/accepted [POST]
+func AcceptQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.UpdateQuoteStatus(uri.ID, claims.OrganizationID, 3, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 拒绝报价单
+// @Id 669
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id/declined [POST]
+func DeclineQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	err := salesorderService.UpdateQuoteStatus(uri.ID, claims.OrganizationID, 4, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 报价单转销售单
+// @Id 670
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "报价单ID"
+// @Param convert_info body QuoteConvertNew true "销售单信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /quotes/:id/convert [POST]
+func ConvertQuote(c *gin.Context) {
+	var uri QuoteID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info QuoteConvertNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.ConvertQuote(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 生成代发货采购单
+// @Id 671
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "销售单ID"
+// @Success 200 object response.SuccessRes{data=[]string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorders/:id/dropship [POST]
+func NewDropshipPurchaseorders(c *gin.Context) {
+	var uri SalesorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewDropshipPurchaseorders(uri.ID, claims.OrganizationID, claims.UserName, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 确认代发货
+// @Id 672
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "销售单ID"
+// @Param dropshipment_info body DropshipmentNew true "代发货信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorders/:id/dropshipments [POST]
+func NewDropshipment(c *gin.Context) {
+	var uri SalesorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info DropshipmentNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewDropshipment(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 代发货列表
+// @Id 673
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "销售单ID"
+// @Success 200 object response.SuccessRes{data=[]DropshipmentResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /salesorders/:id/dropshipments [GET]
+func GetDropshipmentList(c *gin.Context) {
+	var uri SalesorderID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetDropshipmentList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 代发货商品列表
+// @Id 674
+// @Tags 销售单管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "代发货ID"
+// @Success 200 object response.SuccessRes{data=[]DropshipmentItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /dropshipments/:id/items [GET]
+func GetDropshipmentItemList(c *gin.Context) {
+	var uri DropshipmentID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	list, err := salesorderService.GetDropshipmentItemList(uri.ID, claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 运行需求预测
+// @Id 675
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param info body ForecastNew true "预测信息"
+// @Success 200 object response.SuccessRes{data=[]ForecastResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecasts [POST]
+func NewForecast(c *gin.Context) {
+	var info ForecastNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	salesorderService := NewSalesorderService()
+	new, err := salesorderService.NewForecast(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 需求预测列表
+// @Id 676
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param item_id query string false "商品ID"
+// @Param forecast_date query string false "预测开始日期"
+// @Param evaluated query int false "是否已对比实际销量 1是 2否"
+// @Success 200 object response.ListRes{data=[]ForecastResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecasts [GET]
+func GetForecastList(c *gin.Context) {
+	var filter ForecastFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetForecastList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 预测准确度
+// @Id 677
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param item_id query string false "商品ID"
+// @Param method query int false "预测方法 1移动平均 2指数平滑 3季节性朴素"
+// @Success 200 object response.ListRes{data=[]ForecastAccuracyResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecasts/accuracy [GET]
+func GetForecastAccuracyList(c *gin.Context) {
+	var filter ForecastAccuracyFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetForecastAccuracyList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 预测设置列表
+// @Id 678
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param item_id query string false "商品ID"
+// @Param method query int false "预测方法 1移动平均 2指数平滑 3季节性朴素"
+// @Success 200 object response.ListRes{data=[]ForecastSettingResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecastsettings [GET]
+func GetForecastSettingList(c *gin.Context) {
+	var filter ForecastSettingFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	salesorderService := NewSalesorderService()
+	count, list, err := salesorderService.GetForecastSettingList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 根据商品ID获取预测设置
+// @Id 679
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Success 200 object response.SuccessRes{data=ForecastSettingResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecastsettings/:id [GET]
+func GetForecastSettingByID(c *gin.Context) {
+	var uri ForecastSettingID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	salesorderService := NewSalesorderService()
+	setting, err := salesorderService.GetForecastSettingByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, setting)
+}
+
+// @Summary 更新商品预测设置
+// @Id 680
+// @Tags 需求预测
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "商品ID"
+// @Param info body ForecastSettingNew true "预测设置"
+// @Success 200 object response.SuccessRes{data=ForecastSettingResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /forecastsettings/:id [PUT]
+func UpdateForecastSetting(c *gin.Context) {
+	var uri ForecastSettingID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ForecastSettingNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	salesorderService := NewSalesorderService()
+	setting, err := salesorderService.UpdateForecastSetting(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, setting)
+}
@@ -1,6 +1,7 @@
 package salesorder
 
 import (
+	"go-api/api/v1/setting"
 	"go-api/core/request"
 )
 
@@ -9,11 +10,16 @@ type SalesorderNew struct {
 	SalesorderDate       string              `json:"salesorder_date" binding:"required,datetime=2006-01-02"`
 	ExpectedShipmentDate string              `json:"expected_shipment_date" binding:"required,datetime=2006-01-02"`
 	CustomerID           string              `json:"customer_id" binding:"required"`
+	BillingAddressID     string              `json:"billing_address_id" binding:"omitempty"`
+	ShippingAddressID    string              `json:"shipping_address_id" binding:"omitempty"`
 	DiscountType         int                 `json:"discount_type" binding:"omitempty,oneof=1 2"`
 	DiscountValue        float64             `json:"discount_value" binding:"omitempty"`
 	ShippingFee          float64             `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID        string              `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive         int                 `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
 	Notes                string              `json:"notes" binding:"omitempty"`
 	Items                []SalesorderItemNew `json:"items" binding:"required"`
+	RoleID               string              `json:"role_id" swaggerignore:"true"`
 	OrganizationID       string              `json:"organiztion_id" swaggerignore:"true"`
 	User                 string              `json:"user" swaggerignore:"true"`
 	Email                string              `json:"email" swaggerignore:"true"`
@@ -22,9 +28,11 @@ type SalesorderNew struct {
 type SalesorderItemNew struct {
 	SalesorderItemID string  `json:"salesorder_item_id" binding:"omitempty"`
 	ItemID           string  `json:"item_id" binding:"required"`
+	UnitID           string  `json:"unit_id" binding:"omitempty"`
 	Quantity         int     `json:"quantity" binding:"required"`
-	Rate             float64 `json:"rate" binding:"required"`
+	Rate             float64 `json:"rate" binding:"omitempty"`
 	TaxID            string  `json:"tax_id" binding:"omitempty"`
+	DropShip         int     `json:"drop_ship" binding:"omitempty,oneof=1 2"`
 }
 
 type SalesorderFilter struct {
@@ -34,26 +42,32 @@ type SalesorderFilter struct {
 }
 
 type SalesorderResponse struct {
-	SalesorderID         string  `db:"salesorder_id" json:"salesorder_id"`
-	OrganizationID       string  `db:"organization_id" json:"organization_id"`
-	SalesorderNumber     string  `db:"salesorder_number" json:"salesorder_number"`
-	SalesorderDate       string  `db:"salesorder_date" json:"salesorder_date"`
-	ExpectedShipmentDate string  `db:"expected_shipment_date" json:"expected_shipment_date"`
-	CustomerID           string  `db:"customer_id" json:"customer_id"`
-	CustomerName         string  `db:"customer_name" json:"customer_name"`
-	ItemCount            float64 `db:"item_count" json:"item_count"`
-	Subtotal             float64 `db:"sub_total" json:"sub_total"`
-	TaxTotal             float64 `db:"tax_total" json:"tax_total"`
-	DiscountType         int     `db:"discount_type" json:"discount_type"`
-	DiscountValue        float64 `db:"discount_value" json:"discount_value"`
-	ShippingFee          float64 `db:"shipping_fee" json:"shipping_fee"`
-	Total                float64 `db:"total" json:"total"`
-	Notes                string  `db:"notes" json:"notes"`
-	InvoiceStatus        int     `db:"invoice_status" json:"invoice_status"`
-	PickingStatus        int     `db:"picking_status" json:"picking_status"`
-	PackingStatus        int     `db:"packing_status" json:"packing_status"`
-	ShippingStatus       int     `db:"shipping_status" json:"shipping_status"`
-	Status               int     `db:"status" json:"status"`
+	SalesorderID         string                  `db:"salesorder_id" json:"salesorder_id"`
+	OrganizationID       string                  `db:"organization_id" json:"organization_id"`
+	SalesorderNumber     string                  `db:"salesorder_number" json:"salesorder_number"`
+	SalesorderDate       string                  `db:"salesorder_date" json:"salesorder_date"`
+	ExpectedShipmentDate string                  `db:"expected_shipment_date" json:"expected_shipment_date"`
+	CustomerID           string                  `db:"customer_id" json:"customer_id"`
+	CustomerName         string                  `db:"customer_name" json:"customer_name"`
+	ItemCount            float64                 `db:"item_count" json:"item_count"`
+	Subtotal             float64                 `db:"sub_total" json:"sub_total"`
+	TaxTotal             float64                 `db:"tax_total" json:"tax_total"`
+	DiscountType         int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue        float64                 `db:"discount_value" json:"discount_value"`
+	ShippingFee          float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive         int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID        string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount    float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total                float64                 `db:"total" json:"total"`
+	Notes                string                  `db:"notes" json:"notes"`
+	InvoiceStatus        int                     `db:"invoice_status" json:"invoice_status"`
+	PickingStatus        int                     `db:"picking_status" json:"picking_status"`
+	PackingStatus        int                     `db:"packing_status" json:"packing_status"`
+	ShippingStatus       int                     `db:"shipping_status" json:"shipping_status"`
+	ApprovalStatus       int                     `db:"approval_status" json:"approval_status"`
+	BillingAddress       setting.AddressSnapshot `db:"billing_address" json:"billing_address"`
+	ShippingAddress      setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status               int                     `db:"status" json:"status"`
 }
 
 type SalesorderItemResponse struct {
@@ -63,6 +77,10 @@ type SalesorderItemResponse struct {
 	ItemID           string  `db:"item_id" json:"item_id"`
 	ItemName         string  `db:"item_name" json:"item_name"`
 	SKU              string  `db:"sku" json:"sku"`
+	UnitID           string  `db:"unit_id" json:"unit_id"`
+	UnitName         string  `db:"unit_name" json:"unit_name"`
+	UnitQuantity     int     `db:"unit_quantity" json:"unit_quantity"`
+	UnitRate         float64 `db:"unit_rate" json:"unit_rate"`
 	Quantity         int     `db:"quantity" json:"quantity"`
 	Rate             float64 `db:"rate" json:"rate"`
 	TaxID            string  `db:"tax_id" json:"tax_id"`
@@ -73,6 +91,8 @@ type SalesorderItemResponse struct {
 	QuantityPicked   int     `db:"quantity_picked" json:"quantity_picked"`
 	QuantityPacked   int     `db:"quantity_packed" json:"quantity_packed"`
 	QuantityShipped  int     `db:"quantity_shipped" json:"quantity_shipped"`
+	DropShip         int     `db:"drop_ship" json:"drop_ship"`
+	PurchaseorderID  string  `db:"purchaseorder_id" json:"purchaseorder_id"`
 	Status           int     `db:"status" json:"status"`
 }
 
@@ -232,6 +252,9 @@ type ShippingorderBatch struct {
 	ShippingorderDate   string   `json:"shippingorder_date" binding:"required,datetime=2006-01-02"`
 	CarrierID           string   `json:"carrier_id" binding:"omitempty"`
 	TrackingNumber      string   `json:"tracking_number" binding:"omitempty"`
+	RequestLabel        int      `json:"request_label" binding:"omitempty,oneof=1 2"`
+	ServiceCode         string   `json:"service_code" binding:"omitempty,max=64"`
+	LabelFormat         string   `json:"label_format" binding:"omitempty,oneof=PDF ZPL"`
 	Notes               string   `json:"notes" binding:"omitempty"`
 	OrganizationID      string   `json:"organiztion_id" swaggerignore:"true"`
 	User                string   `json:"user" swaggerignore:"true"`
@@ -270,17 +293,19 @@ type ShippingorderFilter struct {
 }
 
 type ShippingorderResponse struct {
-	OrganizationID      string `db:"organization_id" json:"organization_id"`
-	ShippingorderID     string `db:"shippingorder_id" json:"shippingorder_id"`
-	PackageID           string `db:"package_id" json:"package_id"`
-	PackageNumber       string `db:"package_number" json:"package_number"`
-	ShippingorderNumber string `db:"shippingorder_number" json:"shippingorder_number"`
-	ShippingorderDate   string `db:"shippingorder_date" json:"shippingorder_date"`
-	CarrierID           string `db:"carrier_id" json:"carrier_id"`
-	CarrierName         string `db:"carrier_name" json:"carrier_name"`
-	TrackingNumber      string `db:"tracking_number" json:"tracking_number"`
-	Notes               string `db:"notes" json:"notes"`
-	Status              int    `db:"status" json:"status"`
+	OrganizationID      string                  `db:"organization_id" json:"organization_id"`
+	ShippingorderID     string                  `db:"shippingorder_id" json:"shippingorder_id"`
+	PackageID           string                  `db:"package_id" json:"package_id"`
+	PackageNumber       string                  `db:"package_number" json:"package_number"`
+	ShippingorderNumber string                  `db:"shippingorder_number" json:"shippingorder_number"`
+	ShippingorderDate   string                  `db:"shippingorder_date" json:"shippingorder_date"`
+	CarrierID           string                  `db:"carrier_id" json:"carrier_id"`
+	CarrierName         string                  `db:"carrier_name" json:"carrier_name"`
+	TrackingNumber      string                  `db:"tracking_number" json:"tracking_number"`
+	TrackingStatus      int                     `db:"tracking_status" json:"tracking_status"`
+	Notes               string                  `db:"notes" json:"notes"`
+	ShippingAddress     setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status              int                     `db:"status" json:"status"`
 }
 
 type ShippingorderID struct {
@@ -290,8 +315,7 @@ type ShippingorderID struct {
 type RequsitionFilter struct {
 	StartDate      string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
 	EndDate        string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
-	TargetDay      int    `form:"target_day" binding:"omitempty"`
-	Period         int    `json:"period" swaggerignore:"true"`
+	TargetDay      int    `form:"target_day" binding:"omitempty,min=1,max=365"`
 	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
 }
 
@@ -308,10 +332,13 @@ type RequsitionResponse struct {
 type InvoiceNew struct {
 	InvoiceNumber  string           `json:"invoice_number" binding:"required,min=6,max=64"`
 	InvoiceDate    string           `json:"invoice_date" binding:"required,datetime=2006-01-02"`
-	DueDate        string           `json:"due_date" binding:"required,datetime=2006-01-02"`
+	DueDate        string           `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	PaymentTermID  string           `json:"payment_term_id" binding:"omitempty,max=64"`
 	DiscountType   int              `json:"discount_type" binding:"omitempty,oneof=1 2"`
 	DiscountValue  float64          `json:"discount_value" binding:"omitempty"`
 	ShippingFee    float64          `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID  string           `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive   int              `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
 	Notes          string           `json:"notes" binding:"omitempty"`
 	Items          []InvoiceItemNew `json:"items" binding:"required"`
 	OrganizationID string           `json:"organiztion_id" swaggerignore:"true"`
@@ -322,30 +349,39 @@ type InvoiceNew struct {
 type InvoiceItemNew struct {
 	SalesorderItemID string  `json:"salesorder_item_id" binding:"required"`
 	ItemID           string  `json:"item_id" binding:"required"`
+	UnitID           string  `json:"unit_id" binding:"omitempty"`
 	Quantity         int     `json:"quantity" binding:"required"`
 	Rate             float64 `json:"rate" binding:"required"`
 	TaxID            string  `json:"tax_id" binding:"omitempty"`
 }
 
 type InvoiceResponse struct {
-	OrganizationID   string  `db:"organization_id" json:"organization_id"`
-	SalesorderID     string  `db:"salesorder_id" json:"salesorder_id"`
-	SalesorderNumber string  `db:"salesorder_number" json:"salesorder_number"`
-	InvoiceID        string  `db:"invoice_id" json:"invoice_id"`
-	InvoiceNumber    string  `db:"invoice_number" json:"invoice_number"`
-	InvoiceDate      string  `db:"invoice_date" json:"invoice_date"`
-	DueDate          string  `db:"due_date" json:"due_date"`
-	CustomerID       string  `db:"customer_id" json:"customer_id"`
-	CustomerName     string  `db:"customer_name" json:"customer_name"`
-	ItemCount        float64 `db:"item_count" json:"item_count"`
-	Subtotal         float64 `db:"sub_total" json:"sub_total"`
-	DiscountType     int     `db:"discount_type" json:"discount_type"`
-	DiscountValue    float64 `db:"discount_value" json:"discount_value"`
-	TaxTotal         float64 `db:"tax_total" json:"tax_total"`
-	ShippingFee      float64 `db:"shipping_fee" json:"shipping_fee"`
-	Total            float64 `db:"total" json:"total"`
-	Notes            string  `db:"notes" json:"notes"`
-	Status           int     `db:"status" json:"status"`
+	OrganizationID    string                  `db:"organization_id" json:"organization_id"`
+	SalesorderID      string                  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber  string                  `db:"salesorder_number" json:"salesorder_number"`
+	InvoiceID         string                  `db:"invoice_id" json:"invoice_id"`
+	InvoiceNumber     string                  `db:"invoice_number" json:"invoice_number"`
+	InvoiceDate       string                  `db:"invoice_date" json:"invoice_date"`
+	DueDate           string                  `db:"due_date" json:"due_date"`
+	CustomerID        string                  `db:"customer_id" json:"customer_id"`
+	CustomerName      string                  `db:"customer_name" json:"customer_name"`
+	ItemCount         float64                 `db:"item_count" json:"item_count"`
+	Subtotal          float64                 `db:"sub_total" json:"sub_total"`
+	DiscountType      int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue     float64                 `db:"discount_value" json:"discount_value"`
+	TaxTotal          float64                 `db:"tax_total" json:"tax_total"`
+	ShippingFee       float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive      int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID     string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total             float64                 `db:"total" json:"total"`
+	PaymentTermID     string                  `db:"payment_term_id" json:"payment_term_id"`
+	DiscountDate      string                  `db:"discount_date" json:"discount_date"`
+	DiscountPercent   float64                 `db:"discount_percent" json:"discount_percent"`
+	Notes             string                  `db:"notes" json:"notes"`
+	BillingAddress    setting.AddressSnapshot `db:"billing_address" json:"billing_address"`
+	ShippingAddress   setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status            int                     `db:"status" json:"status"`
 }
 
 type InvoiceFilter struct {
@@ -367,6 +403,10 @@ type InvoiceItemResponse struct {
 	ItemID           string  `db:"item_id" json:"item_id"`
 	ItemName         string  `db:"item_name" json:"item_name"`
 	SKU              string  `db:"sku" json:"sku"`
+	UnitID           string  `db:"unit_id" json:"unit_id"`
+	UnitName         string  `db:"unit_name" json:"unit_name"`
+	UnitQuantity     int     `db:"unit_quantity" json:"unit_quantity"`
+	UnitRate         float64 `db:"unit_rate" json:"unit_rate"`
 	Quantity         int     `db:"quantity" json:"quantity"`
 	Rate             float64 `db:"rate" json:"rate"`
 	TaxID            string  `db:"tax_id" json:"tax_id"`
@@ -381,6 +421,7 @@ type PaymentReceivedNew struct {
 	PaymentReceivedDate   string  `json:"payment_received_date" binding:"required,datetime=2006-01-02"`
 	PaymentMethodID       string  `json:"payment_method_id" binding:"required,min=6,max=64"`
 	Amount                float64 `json:"amount" binding:"required"`
+	TakeDiscount          int     `json:"take_discount" binding:"omitempty,oneof=1 2"`
 	Notes                 string  `json:"notes" binding:"omitempty"`
 	OrganizationID        string  `json:"organiztion_id" swaggerignore:"true"`
 	User                  string  `json:"user" swaggerignore:"true"`
@@ -407,6 +448,7 @@ type PaymentReceivedResponse struct {
 	PaymentMethodID       string  `db:"payment_method_id" json:"payment_method_id"`
 	PaymentMethodName     string  `db:"payment_method_name" json:"payment_method_name"`
 	Amount                float64 `db:"amount" json:"amount"`
+	DiscountAmount        float64 `db:"discount_amount" json:"discount_amount"`
 	Notes                 string  `db:"notes" json:"notes"`
 	Status                int     `db:"status" json:"status"`
 }
@@ -414,3 +456,462 @@ type PaymentReceivedResponse struct {
 type PaymentReceivedID struct {
 	ID string `uri:"id" binding:"required,min=1"`
 }
+
+type RecurringInvoiceNew struct {
+	ProfileName    string                    `json:"profile_name" binding:"required,min=1,max=64"`
+	SalesorderID   string                    `json:"salesorder_id" binding:"required,min=1,max=64"`
+	InvoicePrefix  string                    `json:"invoice_prefix" binding:"required,min=2,max=32"`
+	Frequency      int                       `json:"frequency" binding:"required,oneof=1 2 3 4 5"`
+	RepeatEvery    int                       `json:"repeat_every" binding:"omitempty,min=1,max=365"`
+	StartDate      string                    `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate        string                    `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Occurrences    int                       `json:"occurrences" binding:"omitempty,min=1"`
+	PaymentTermID  string                    `json:"payment_term_id" binding:"omitempty,max=64"`
+	DiscountType   int                       `json:"discount_type" binding:"omitempty,oneof=1 2"`
+	DiscountValue  float64                   `json:"discount_value" binding:"omitempty"`
+	ShippingFee    float64                   `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID  string                    `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive   int                       `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
+	Notes          string                    `json:"notes" binding:"omitempty"`
+	Status         int                       `json:"status" binding:"required,oneof=1 2"`
+	Items          []RecurringInvoiceItemNew `json:"items" binding:"required,dive"`
+	OrganizationID string                    `json:"organiztion_id" swaggerignore:"true"`
+	User           string                    `json:"user" swaggerignore:"true"`
+	Email          string                    `json:"email" swaggerignore:"true"`
+}
+
+type RecurringInvoiceItemNew struct {
+	SalesorderItemID string  `json:"salesorder_item_id" binding:"required"`
+	ItemID           string  `json:"item_id" binding:"required"`
+	Quantity         int     `json:"quantity" binding:"required,min=1"`
+	Rate             float64 `json:"rate" binding:"required"`
+	TaxID            string  `json:"tax_id" binding:"omitempty"`
+}
+
+type RecurringInvoiceFilter struct {
+	ProfileName    string `form:"profile_name" binding:"omitempty,max=64,min=1"`
+	SalesorderID   string `form:"salesorder_id" binding:"omitempty,max=64"`
+	CustomerID     string `form:"customer_id" binding:"omitempty,max=64"`
+	Status         int    `form:"status" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type RecurringInvoiceResponse struct {
+	OrganizationID     string  `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID string  `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	ProfileName        string  `db:"profile_name" json:"profile_name"`
+	SalesorderID       string  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber   string  `db:"salesorder_number" json:"salesorder_number"`
+	CustomerID         string  `db:"customer_id" json:"customer_id"`
+	CustomerName       string  `db:"customer_name" json:"customer_name"`
+	InvoicePrefix      string  `db:"invoice_prefix" json:"invoice_prefix"`
+	Frequency          int     `db:"frequency" json:"frequency"`
+	RepeatEvery        int     `db:"repeat_every" json:"repeat_every"`
+	StartDate          string  `db:"start_date" json:"start_date"`
+	EndDate            string  `db:"end_date" json:"end_date"`
+	Occurrences        int     `db:"occurrences" json:"occurrences"`
+	GeneratedCount     int     `db:"generated_count" json:"generated_count"`
+	NextDate           string  `db:"next_date" json:"next_date"`
+	PaymentTermID      string  `db:"payment_term_id" json:"payment_term_id"`
+	DiscountType       int     `db:"discount_type" json:"discount_type"`
+	DiscountValue      float64 `db:"discount_value" json:"discount_value"`
+	ShippingFee        float64 `db:"shipping_fee" json:"shipping_fee"`
+	ShippingTaxID      string  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	TaxInclusive       int     `db:"tax_inclusive" json:"tax_inclusive"`
+	Notes              string  `db:"notes" json:"notes"`
+	Status             int     `db:"status" json:"status"`
+	UpdatedBy          string  `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringInvoiceItemResponse struct {
+	OrganizationID         string  `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID     string  `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	RecurringInvoiceItemID string  `db:"recurring_invoice_item_id" json:"recurring_invoice_item_id"`
+	SalesorderItemID       string  `db:"salesorder_item_id" json:"salesorder_item_id"`
+	ItemID                 string  `db:"item_id" json:"item_id"`
+	ItemName               string  `db:"item_name" json:"item_name"`
+	SKU                    string  `db:"sku" json:"sku"`
+	Quantity               int     `db:"quantity" json:"quantity"`
+	Rate                   float64 `db:"rate" json:"rate"`
+	TaxID                  string  `db:"tax_id" json:"tax_id"`
+	Status                 int     `db:"status" json:"status"`
+}
+
+type RecurringInvoiceRunResponse struct {
+	OrganizationID        string `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID    string `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	RecurringInvoiceRunID string `db:"recurring_invoice_run_id" json:"recurring_invoice_run_id"`
+	PeriodDate            string `db:"period_date" json:"period_date"`
+	InvoiceID             string `db:"invoice_id" json:"invoice_id"`
+	InvoiceNumber         string `db:"invoice_number" json:"invoice_number"`
+	Message               string `db:"message" json:"message"`
+	Status                int    `db:"status" json:"status"`
+}
+
+type RecurringInvoiceID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ShippingRateNew struct {
+	CarrierID      string   `json:"carrier_id" binding:"required,min=1"`
+	PackageID      []string `json:"package_id" binding:"required,min=1"`
+	OrganizationID string   `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type ShippingLabelNew struct {
+	ServiceCode    string `json:"service_code" binding:"omitempty,max=64"`
+	LabelFormat    string `json:"label_format" binding:"omitempty,oneof=PDF ZPL"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type ShippingLabelResponse struct {
+	OrganizationID  string  `db:"organization_id" json:"organization_id"`
+	ShippingLabelID string  `db:"shipping_label_id" json:"shipping_label_id"`
+	ShippingorderID string  `db:"shippingorder_id" json:"shippingorder_id"`
+	CarrierID       string  `db:"carrier_id" json:"carrier_id"`
+	CarrierName     string  `db:"carrier_name" json:"carrier_name"`
+	ServiceCode     string  `db:"service_code" json:"service_code"`
+	TrackingNumber  string  `db:"tracking_number" json:"tracking_number"`
+	Format          string  `db:"format" json:"format"`
+	Data            []byte  `db:"data" json:"-"`
+	Cost            float64 `db:"cost" json:"cost"`
+	Status          int     `db:"status" json:"status"`
+}
+
+type PackageParcelItemResponse struct {
+	PackageID         string  `db:"package_id" json:"package_id"`
+	ItemID            string  `db:"item_id" json:"item_id"`
+	Quantity          int     `db:"quantity" json:"quantity"`
+	Weight            float64 `db:"weight" json:"weight"`
+	WeightUnitName    string  `db:"weight_unit_name" json:"weight_unit_name"`
+	Length            float64 `db:"length" json:"length"`
+	Width             float64 `db:"width" json:"width"`
+	Height            float64 `db:"height" json:"height"`
+	DimensionUnitName string  `db:"dimension_unit_name" json:"dimension_unit_name"`
+}
+
+type ShippingTrackingEventResponse struct {
+	OrganizationID  string `db:"organization_id" json:"organization_id"`
+	TrackingEventID string `db:"tracking_event_id" json:"tracking_event_id"`
+	ShippingorderID string `db:"shippingorder_id" json:"shippingorder_id"`
+	TrackingNumber  string `db:"tracking_number" json:"tracking_number"`
+	EventTime       string `db:"event_time" json:"event_time"`
+	TrackingStatus  int    `db:"tracking_status" json:"tracking_status"`
+	Description     string `db:"description" json:"description"`
+	Location        string `db:"location" json:"location"`
+	Source          int    `db:"source" json:"source"`
+}
+
+type TrackingShippingorderResponse struct {
+	OrganizationID  string `db:"organization_id" json:"organization_id"`
+	ShippingorderID string `db:"shippingorder_id" json:"shippingorder_id"`
+	CarrierID       string `db:"carrier_id" json:"carrier_id"`
+	TrackingNumber  string `db:"tracking_number" json:"tracking_number"`
+	TrackingStatus  int    `db:"tracking_status" json:"tracking_status"`
+}
+
+type CarrierWebhookID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type SalesorderHoldFilter struct {
+	CustomerID     string `form:"customer_id" binding:"omitempty,max=64,min=1"`
+	Status         int    `form:"status" binding:"omitempty,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type SalesorderHoldResponse struct {
+	OrganizationID   string  `db:"organization_id" json:"organization_id"`
+	SalesorderHoldID string  `db:"salesorder_hold_id" json:"salesorder_hold_id"`
+	SalesorderID     string  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber string  `db:"salesorder_number" json:"salesorder_number"`
+	SalesorderDate   string  `db:"salesorder_date" json:"salesorder_date"`
+	CustomerID       string  `db:"customer_id" json:"customer_id"`
+	CustomerName     string  `db:"customer_name" json:"customer_name"`
+	Reason           int     `db:"reason" json:"reason"`
+	CreditLimit      float64 `db:"credit_limit" json:"credit_limit"`
+	Exposure         float64 `db:"exposure" json:"exposure"`
+	OrderTotal       float64 `db:"order_total" json:"order_total"`
+	ReleasedBy       string  `db:"released_by" json:"released_by"`
+	ReleasedAt       string  `db:"released_at" json:"released_at"`
+	ReleaseNotes     string  `db:"release_notes" json:"release_notes"`
+	Status           int     `db:"status" json:"status"`
+}
+
+type SalesorderHoldRelease struct {
+	Notes          string `json:"notes" binding:"required,min=1,max=512"`
+	IsAdmin        int    `json:"is_admin" swaggerignore:"true"`
+	RoleID         string `json:"role_id" swaggerignore:"true"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type CustomerCreditResponse struct {
+	CustomerID      string  `json:"customer_id"`
+	CreditLimit     float64 `json:"credit_limit"`
+	CreditHold      int     `json:"credit_hold"`
+	OpenInvoices    float64 `json:"open_invoices"`
+	UnbilledOrders  float64 `json:"unbilled_orders"`
+	Exposure        float64 `json:"exposure"`
+	AvailableCredit float64 `json:"available_credit"`
+}
+
+type CustomerID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type QuoteNew struct {
+	QuoteNumber    string         `json:"quote_number" binding:"required,min=6,max=64"`
+	QuoteDate      string         `json:"quote_date" binding:"required,datetime=2006-01-02"`
+	ValidUntil     string         `json:"valid_until" binding:"required,datetime=2006-01-02"`
+	CustomerID     string         `json:"customer_id" binding:"required_without=LeadID"`
+	LeadID         string         `json:"lead_id" binding:"required_without=CustomerID"`
+	DiscountType   int            `json:"discount_type" binding:"omitempty,oneof=1 2"`
+	DiscountValue  float64        `json:"discount_value" binding:"omitempty"`
+	ShippingFee    float64        `json:"shipping_fee" binding:"omitempty"`
+	ShippingTaxID  string         `json:"shipping_tax_id" binding:"omitempty"`
+	TaxInclusive   int            `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
+	Notes          string         `json:"notes" binding:"omitempty"`
+	Items          []QuoteItemNew `json:"items" binding:"required,min=1,dive"`
+	OrganizationID string         `json:"organiztion_id" swaggerignore:"true"`
+	User           string         `json:"user" swaggerignore:"true"`
+	Email          string         `json:"email" swaggerignore:"true"`
+}
+
+type QuoteItemNew struct {
+	ItemID   string  `json:"item_id" binding:"required"`
+	Quantity int     `json:"quantity" binding:"required"`
+	Rate     float64 `json:"rate" binding:"omitempty"`
+	TaxID    string  `json:"tax_id" binding:"omitempty"`
+}
+
+type QuoteFilter struct {
+	QuoteNumber    string `form:"quote_number" binding:"omitempty,max=64,min=1"`
+	CustomerID     string `form:"customer_id" binding:"omitempty,max=64"`
+	LeadID         string `form:"lead_id" binding:"omitempty,max=64"`
+	Status         int    `form:"status" binding:"omitempty,oneof=1 2 3 4 5 6"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type QuoteResponse struct {
+	OrganizationID    string  `db:"organization_id" json:"organization_id"`
+	QuoteID           string  `db:"quote_id" json:"quote_id"`
+	QuoteNumber       string  `db:"quote_number" json:"quote_number"`
+	QuoteDate         string  `db:"quote_date" json:"quote_date"`
+	ValidUntil        string  `db:"valid_until" json:"valid_until"`
+	CustomerID        string  `db:"customer_id" json:"customer_id"`
+	CustomerName      string  `db:"customer_name" json:"customer_name"`
+	LeadID            string  `db:"lead_id" json:"lead_id"`
+	LeadCompany       string  `db:"lead_company" json:"lead_company"`
+	ItemCount         float64 `db:"item_count" json:"item_count"`
+	Subtotal          float64 `db:"sub_total" json:"sub_total"`
+	TaxTotal          float64 `db:"tax_total" json:"tax_total"`
+	DiscountType      int     `db:"discount_type" json:"discount_type"`
+	DiscountValue     float64 `db:"discount_value" json:"discount_value"`
+	ShippingFee       float64 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive      int     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID     string  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount float64 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total             float64 `db:"total" json:"total"`
+	Notes             string  `db:"notes" json:"notes"`
+	SalesorderID      string  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber  string  `db:"salesorder_number" json:"salesorder_number"`
+	Status            int     `db:"status" json:"status"`
+}
+
+type QuoteItemResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	QuoteID        string  `db:"quote_id" json:"quote_id"`
+	QuoteItemID    string  `db:"quote_item_id" json:"quote_item_id"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	ItemName       string  `db:"item_name" json:"item_name"`
+	SKU            string  `db:"sku" json:"sku"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Rate           float64 `db:"rate" json:"rate"`
+	TaxID          string  `db:"tax_id" json:"tax_id"`
+	TaxValue       float64 `db:"tax_value" json:"tax_value"`
+	TaxAmount      float64 `db:"tax_amount" json:"tax_amount"`
+	Amount         float64 `db:"amount" json:"amount"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type QuoteID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type QuoteConvertNew struct {
+	SalesorderNumber     string `json:"salesorder_number" binding:"required,min=6,max=64"`
+	SalesorderDate       string `json:"salesorder_date" binding:"required,datetime=2006-01-02"`
+	ExpectedShipmentDate string `json:"expected_shipment_date" binding:"required,datetime=2006-01-02"`
+	CustomerID           string `json:"customer_id" binding:"omitempty"`
+	BillingAddressID     string `json:"billing_address_id" binding:"omitempty"`
+	ShippingAddressID    string `json:"shipping_address_id" binding:"omitempty"`
+	OrganizationID       string `json:"organiztion_id" swaggerignore:"true"`
+	User                 string `json:"user" swaggerignore:"true"`
+	Email                string `json:"email" swaggerignore:"true"`
+}
+
+type DropshipmentNew struct {
+	PurchaseorderID string                `json:"purchaseorder_id" binding:"required"`
+	ShipmentDate    string                `json:"shipment_date" binding:"required,datetime=2006-01-02"`
+	CarrierName     string                `json:"carrier_name" binding:"omitempty,max=64"`
+	TrackingNumber  string                `json:"tracking_number" binding:"omitempty,max=64"`
+	Notes           string                `json:"notes" binding:"omitempty"`
+	Items           []DropshipmentItemNew `json:"items" binding:"required,min=1,dive"`
+	OrganizationID  string                `json:"organiztion_id" swaggerignore:"true"`
+	User            string                `json:"user" swaggerignore:"true"`
+	Email           string                `json:"email" swaggerignore:"true"`
+}
+
+type DropshipmentItemNew struct {
+	ItemID   string `json:"item_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
+}
+
+type DropshipmentResponse struct {
+	OrganizationID      string `db:"organization_id" json:"organization_id"`
+	DropshipmentID      string `db:"dropshipment_id" json:"dropshipment_id"`
+	SalesorderID        string `db:"salesorder_id" json:"salesorder_id"`
+	PurchaseorderID     string `db:"purchaseorder_id" json:"purchaseorder_id"`
+	PurchaseorderNumber string `db:"purchaseorder_number" json:"purchaseorder_number"`
+	ShipmentDate        string `db:"shipment_date" json:"shipment_date"`
+	CarrierName         string `db:"carrier_name" json:"carrier_name"`
+	TrackingNumber      string `db:"tracking_number" json:"tracking_number"`
+	Notes               string `db:"notes" json:"notes"`
+	Status              int    `db:"status" json:"status"`
+}
+
+type DropshipmentItemResponse struct {
+	OrganizationID      string `db:"organization_id" json:"organization_id"`
+	DropshipmentID      string `db:"dropshipment_id" json:"dropshipment_id"`
+	DropshipmentItemID  string `db:"dropshipment_item_id" json:"dropshipment_item_id"`
+	SalesorderItemID    string `db:"salesorder_item_id" json:"salesorder_item_id"`
+	PurchaseorderItemID string `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
+	ItemID              string `db:"item_id" json:"item_id"`
+	ItemName            string `db:"item_name" json:"item_name"`
+	SKU                 string `db:"sku" json:"sku"`
+	Quantity            int    `db:"quantity" json:"quantity"`
+	Status              int    `db:"status" json:"status"`
+}
+
+type DropshipmentID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ForecastSettingFilter struct {
+	ItemID         string `form:"item_id" binding:"omitempty"`
+	Method         int    `form:"method" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ForecastSettingNew struct {
+	Method         int     `json:"method" binding:"required,oneof=1 2 3"`
+	Window         int     `json:"window_days" binding:"omitempty,min=1,max=365"`
+	Alpha          float64 `json:"alpha" binding:"omitempty,gt=0,max=1"`
+	Beta           float64 `json:"beta" binding:"omitempty,gt=0,max=1"`
+	SeasonLength   int     `json:"season_length" binding:"omitempty,min=2,max=365"`
+	SafetyStock    int     `json:"safety_stock" binding:"omitempty,min=0"`
+	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
+	User           string  `json:"user" swaggerignore:"true"`
+}
+
+type ForecastSettingResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	ItemName       string  `db:"item_name" json:"item_name"`
+	SKU            string  `db:"sku" json:"sku"`
+	Method         int     `db:"method" json:"method"`
+	Window         int     `db:"window_days" json:"window_days"`
+	Alpha          float64 `db:"alpha" json:"alpha"`
+	Beta           float64 `db:"beta" json:"beta"`
+	SeasonLength   int     `db:"season_length" json:"season_length"`
+	SafetyStock    int     `db:"safety_stock" json:"safety_stock"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type ForecastSettingID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type ForecastNew struct {
+	ItemID         string `json:"item_id" binding:"omitempty"`
+	HistoryDays    int    `json:"history_days" binding:"omitempty,min=7,max=730"`
+	HorizonDays    int    `json:"horizon_days" binding:"omitempty,min=1,max=365"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+}
+
+type ForecastFilter struct {
+	ItemID         string `form:"item_id" binding:"omitempty"`
+	ForecastDate   string `form:"forecast_date" binding:"omitempty,datetime=2006-01-02"`
+	Evaluated      int    `form:"evaluated" binding:"omitempty,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ForecastResponse struct {
+	OrganizationID    string  `db:"organization_id" json:"organization_id"`
+	ForecastID        string  `db:"forecast_id" json:"forecast_id"`
+	ItemID            string  `db:"item_id" json:"item_id"`
+	ItemName          string  `db:"item_name" json:"item_name"`
+	SKU               string  `db:"sku" json:"sku"`
+	Unit              string  `db:"unit" json:"unit"`
+	Method            int     `db:"method" json:"method"`
+	ForecastDate      string  `db:"forecast_date" json:"forecast_date"`
+	PeriodEnd         string  `db:"period_end" json:"period_end"`
+	HistoryDays       int     `db:"history_days" json:"history_days"`
+	HorizonDays       int     `db:"horizon_days" json:"horizon_days"`
+	LeadTime          int     `db:"lead_time" json:"lead_time"`
+	DailyDemand       float64 `db:"daily_demand" json:"daily_demand"`
+	ForecastQuantity  int     `db:"forecast_quantity" json:"forecast_quantity"`
+	SafetyStock       int     `db:"safety_stock" json:"safety_stock"`
+	StockAvailable    int     `db:"stock_available" json:"stock_available"`
+	OpenQuantity      int     `db:"open_quantity" json:"open_quantity"`
+	SuggestedQuantity int     `db:"suggested_quantity" json:"suggested_quantity"`
+	ActualQuantity    int     `db:"actual_quantity" json:"actual_quantity"`
+	Evaluated         int     `db:"evaluated" json:"evaluated"`
+	Status            int     `db:"status" json:"status"`
+}
+
+type ForecastAccuracyFilter struct {
+	ItemID         string `form:"item_id" binding:"omitempty"`
+	Method         int    `form:"method" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type ForecastAccuracyResponse struct {
+	ItemID           string  `db:"item_id" json:"item_id"`
+	ItemName         string  `db:"item_name" json:"item_name"`
+	SKU              string  `db:"sku" json:"sku"`
+	Method           int     `db:"method" json:"method"`
+	ForecastCount    int     `db:"forecast_count" json:"forecast_count"`
+	ForecastQuantity int     `db:"forecast_quantity" json:"forecast_quantity"`
+	ActualQuantity   int     `db:"actual_quantity" json:"actual_quantity"`
+	MAE              float64 `db:"mae" json:"mae"`   //mean absolute error
+	MAPE             float64 `db:"mape" json:"mape"` //mean absolute percentage error, periods without sales left out
+	Bias             float64 `db:"bias" json:"bias"` //mean of forecast minus actual
+}
+
+type ForecastItemResponse struct {
+	ItemID         string `db:"item_id" json:"item_id"`
+	ItemName       string `db:"item_name" json:"item_name"`
+	SKU            string `db:"sku" json:"sku"`
+	Unit           string `db:"unit" json:"unit"`
+	StockAvailable int    `db:"stock_available" json:"stock_available"`
+	LeadTime       int    `db:"lead_time" json:"lead_time"`
+	OpenQuantity   int    `db:"open_quantity" json:"open_quantity"`
+}
+
+type DailyDemandResponse struct {
+	ItemID     string `db:"item_id" json:"item_id"`
+	DemandDate string `db:"demand_date" json:"demand_date"`
+	Quantity   int    `db:"quantity" json:"quantity"`
+}
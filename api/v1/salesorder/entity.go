@@ -1,32 +1,41 @@
 package salesorder
 
-import "time"
+import (
+	"go-api/api/v1/setting"
+	"time"
+)
 
 type Salesorder struct {
-	ID                   int64     `db:"id" json:"id"`
-	OrganizationID       string    `db:"organization_id" json:"organization_id"`
-	SalesorderID         string    `db:"salesorder_id" json:"salesorder_id"`
-	SalesorderNumber     string    `db:"salesorder_number" json:"salesorder_number"`
-	SalesorderDate       string    `db:"salesorder_date" json:"salesorder_date"`
-	ExpectedShipmentDate string    `db:"expected_shipment_date" json:"expected_shipment_date"`
-	CustomerID           string    `db:"customer_id" json:"customer_id"`
-	ItemCount            int       `db:"item_count" json:"item_count"`
-	Subtotal             float64   `db:"subtotal" json:"subtotal"`
-	DiscountType         int       `db:"discount_type" json:"discount_type"`
-	DiscountValue        float64   `db:"discount_value" json:"discount_value"`
-	TaxTotal             float64   `db:"tax_total" json:"tax_total"`
-	ShippingFee          float64   `db:"shipping_fee" json:"shipping_fee"`
-	Total                float64   `db:"total" json:"total"`
-	Notes                string    `db:"notes" json:"notes"`
-	InvoiceStatus        int       `db:"invoice_status" json:"invoice_status"`
-	PickingStatus        int       `db:"picking_status" json:"picking_status"`
-	PackingStatus        int       `db:"packing_status" json:"packing_status"`
-	ShippingStatus       int       `db:"shipping_status" json:"shipping_status"`
-	Status               int       `db:"status" json:"status"`
-	Created              time.Time `db:"created" json:"created"`
-	CreatedBy            string    `db:"created_by" json:"created_by"`
-	Updated              time.Time `db:"updated" json:"updated"`
-	UpdatedBy            string    `db:"updated_by" json:"updated_by"`
+	ID                   int64                   `db:"id" json:"id"`
+	OrganizationID       string                  `db:"organization_id" json:"organization_id"`
+	SalesorderID         string                  `db:"salesorder_id" json:"salesorder_id"`
+	SalesorderNumber     string                  `db:"salesorder_number" json:"salesorder_number"`
+	SalesorderDate       string                  `db:"salesorder_date" json:"salesorder_date"`
+	ExpectedShipmentDate string                  `db:"expected_shipment_date" json:"expected_shipment_date"`
+	CustomerID           string                  `db:"customer_id" json:"customer_id"`
+	ItemCount            int                     `db:"item_count" json:"item_count"`
+	Subtotal             float64                 `db:"subtotal" json:"subtotal"`
+	DiscountType         int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue        float64                 `db:"discount_value" json:"discount_value"`
+	TaxTotal             float64                 `db:"tax_total" json:"tax_total"`
+	ShippingFee          float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive         int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID        string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount    float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total                float64                 `db:"total" json:"total"`
+	Notes                string                  `db:"notes" json:"notes"`
+	InvoiceStatus        int                     `db:"invoice_status" json:"invoice_status"`
+	PickingStatus        int                     `db:"picking_status" json:"picking_status"`
+	PackingStatus        int                     `db:"packing_status" json:"packing_status"`
+	ShippingStatus       int                     `db:"shipping_status" json:"shipping_status"`
+	ApprovalStatus       int                     `db:"approval_status" json:"approval_status"`
+	BillingAddress       setting.AddressSnapshot `db:"billing_address" json:"billing_address"`
+	ShippingAddress      setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status               int                     `db:"status" json:"status"`
+	Created              time.Time               `db:"created" json:"created"`
+	CreatedBy            string                  `db:"created_by" json:"created_by"`
+	Updated              time.Time               `db:"updated" json:"updated"`
+	UpdatedBy            string                  `db:"updated_by" json:"updated_by"`
 }
 
 type SalesorderItem struct {
@@ -35,8 +44,11 @@ type SalesorderItem struct {
 	SalesorderID     string    `db:"salesorder_id" json:"salesorder_id"`
 	SalesorderItemID string    `db:"salesorder_item_id" json:"salesorder_item_id"`
 	ItemID           string    `db:"item_id" json:"item_id"`
-	Quantity         int       `db:"quantity" json:"quantity"`
-	Rate             float64   `db:"rate" json:"rate"`
+	UnitID           string    `db:"unit_id" json:"unit_id"`             //unit sold in
+	UnitQuantity     int       `db:"unit_quantity" json:"unit_quantity"` //quantity in the sold unit
+	UnitRate         float64   `db:"unit_rate" json:"unit_rate"`         //rate of the sold unit
+	Quantity         int       `db:"quantity" json:"quantity"`           //quantity in base unit
+	Rate             float64   `db:"rate" json:"rate"`                   //rate of base unit
 	TaxID            string    `db:"tax_id" json:"tax_id"`
 	TaxValue         float64   `db:"tax_value" json:"tax_value"`
 	TaxAmount        float64   `db:"tax_amount" json:"tax_amount"`
@@ -45,6 +57,8 @@ type SalesorderItem struct {
 	QuantityPicked   int       `db:"quantity_picked" json:"quantity_picked"`
 	QuantityPacked   int       `db:"quantity_packed" json:"quantity_packed"`
 	QuantityShipped  int       `db:"quantity_shipped" json:"quantity_shipped"`
+	DropShip         int       `db:"drop_ship" json:"drop_ship"` //1 drop-ship 2 from warehouse
+	PurchaseorderID  string    `db:"purchaseorder_id" json:"purchaseorder_id"`
 	Status           int       `db:"status" json:"status"`
 	Created          time.Time `db:"created" json:"created"`
 	CreatedBy        string    `db:"created_by" json:"created_by"`
@@ -148,20 +162,22 @@ type PackageItem struct {
 }
 
 type Shippingorder struct {
-	ID                  int64     `db:"id" json:"id"`
-	OrganizationID      string    `db:"organization_id" json:"organization_id"`
-	ShippingorderID     string    `db:"shippingorder_id" json:"shippingorder_id"`
-	PackageID           string    `db:"package_id" json:"package_id"`
-	ShippingorderNumber string    `db:"shippingorder_number" json:"shippingorder_number"`
-	ShippingorderDate   string    `db:"shippingorder_date" json:"shippingorder_date"`
-	CarrierID           string    `db:"carrier_id" json:"carrier_id"`
-	TrackingNumber      string    `db:"tracking_number" json:"tracking_number"`
-	Notes               string    `db:"notes" json:"notes"`
-	Status              int       `db:"status" json:"status"`
-	Created             time.Time `db:"created" json:"created"`
-	CreatedBy           string    `db:"created_by" json:"created_by"`
-	Updated             time.Time `db:"updated" json:"updated"`
-	UpdatedBy           string    `db:"updated_by" json:"updated_by"`
+	ID                  int64                   `db:"id" json:"id"`
+	OrganizationID      string                  `db:"organization_id" json:"organization_id"`
+	ShippingorderID     string                  `db:"shippingorder_id" json:"shippingorder_id"`
+	PackageID           string                  `db:"package_id" json:"package_id"`
+	ShippingorderNumber string                  `db:"shippingorder_number" json:"shippingorder_number"`
+	ShippingorderDate   string                  `db:"shippingorder_date" json:"shippingorder_date"`
+	CarrierID           string                  `db:"carrier_id" json:"carrier_id"`
+	TrackingNumber      string                  `db:"tracking_number" json:"tracking_number"`
+	TrackingStatus      int                     `db:"tracking_status" json:"tracking_status"` //0 none 1 label created 2 picked up 3 in transit 4 out for delivery 5 delivered 6 exception
+	Notes               string                  `db:"notes" json:"notes"`
+	ShippingAddress     setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status              int                     `db:"status" json:"status"`
+	Created             time.Time               `db:"created" json:"created"`
+	CreatedBy           string                  `db:"created_by" json:"created_by"`
+	Updated             time.Time               `db:"updated" json:"updated"`
+	UpdatedBy           string                  `db:"updated_by" json:"updated_by"`
 }
 
 type ShippingorderItem struct {
@@ -195,27 +211,35 @@ type ShippingorderDetail struct {
 }
 
 type Invoice struct {
-	ID             int64     `db:"id" json:"id"`
-	OrganizationID string    `db:"organization_id" json:"organization_id"`
-	InvoiceID      string    `db:"invoice_id" json:"invoice_id"`
-	SalesorderID   string    `db:"salesorder_id" json:"salesorder_id"`
-	InvoiceNumber  string    `db:"invoice_number" json:"invoice_number"`
-	InvoiceDate    string    `db:"invoice_date" json:"invoice_date"`
-	DueDate        string    `db:"due_date" json:"due_date"`
-	CustomerID     string    `db:"customer_id" json:"customer_id"`
-	ItemCount      int       `db:"item_count" json:"item_count"`
-	Subtotal       float64   `db:"subtotal" json:"subtotal"`
-	DiscountType   int       `db:"discount_type" json:"discount_type"`
-	DiscountValue  float64   `db:"discount_value" json:"discount_value"`
-	TaxTotal       float64   `db:"tax_total" json:"tax_total"`
-	ShippingFee    float64   `db:"shipping_fee" json:"shipping_fee"`
-	Total          float64   `db:"total" json:"total"`
-	Notes          string    `db:"notes" json:"notes"`
-	Status         int       `db:"status" json:"status"`
-	Created        time.Time `db:"created" json:"created"`
-	CreatedBy      string    `db:"created_by" json:"created_by"`
-	Updated        time.Time `db:"updated" json:"updated"`
-	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+	ID                int64                   `db:"id" json:"id"`
+	OrganizationID    string                  `db:"organization_id" json:"organization_id"`
+	InvoiceID         string                  `db:"invoice_id" json:"invoice_id"`
+	SalesorderID      string                  `db:"salesorder_id" json:"salesorder_id"`
+	InvoiceNumber     string                  `db:"invoice_number" json:"invoice_number"`
+	InvoiceDate       string                  `db:"invoice_date" json:"invoice_date"`
+	DueDate           string                  `db:"due_date" json:"due_date"`
+	CustomerID        string                  `db:"customer_id" json:"customer_id"`
+	ItemCount         int                     `db:"item_count" json:"item_count"`
+	Subtotal          float64                 `db:"subtotal" json:"subtotal"`
+	DiscountType      int                     `db:"discount_type" json:"discount_type"`
+	DiscountValue     float64                 `db:"discount_value" json:"discount_value"`
+	TaxTotal          float64                 `db:"tax_total" json:"tax_total"`
+	ShippingFee       float64                 `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive      int                     `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID     string                  `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount float64                 `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total             float64                 `db:"total" json:"total"`
+	PaymentTermID     string                  `db:"payment_term_id" json:"payment_term_id"`
+	DiscountDate      string                  `db:"discount_date" json:"discount_date"`
+	DiscountPercent   float64                 `db:"discount_percent" json:"discount_percent"`
+	Notes             string                  `db:"notes" json:"notes"`
+	BillingAddress    setting.AddressSnapshot `db:"billing_address" json:"billing_address"`
+	ShippingAddress   setting.AddressSnapshot `db:"shipping_address" json:"shipping_address"`
+	Status            int                     `db:"status" json:"status"`
+	Created           time.Time               `db:"created" json:"created"`
+	CreatedBy         string                  `db:"created_by" json:"created_by"`
+	Updated           time.Time               `db:"updated" json:"updated"`
+	UpdatedBy         string                  `db:"updated_by" json:"updated_by"`
 }
 
 type InvoiceItem struct {
@@ -225,8 +249,11 @@ type InvoiceItem struct {
 	InvoiceItemID    string    `db:"invoice_item_id" json:"invoice_item_id"`
 	SalesorderItemID string    `db:"salesorder_item_id" json:"salesorder_item_id"`
 	ItemID           string    `db:"item_id" json:"item_id"`
-	Quantity         int       `db:"quantity" json:"quantity"`
-	Rate             float64   `db:"rate" json:"rate"`
+	UnitID           string    `db:"unit_id" json:"unit_id"`             //unit invoiced in
+	UnitQuantity     int       `db:"unit_quantity" json:"unit_quantity"` //quantity in the invoiced unit
+	UnitRate         float64   `db:"unit_rate" json:"unit_rate"`         //rate of the invoiced unit
+	Quantity         int       `db:"quantity" json:"quantity"`           //quantity in base unit
+	Rate             float64   `db:"rate" json:"rate"`                   //rate of base unit
 	TaxID            string    `db:"tax_id" json:"tax_id"`
 	TaxValue         float64   `db:"tax_value" json:"tax_value"`
 	TaxAmount        float64   `db:"tax_amount" json:"tax_amount"`
@@ -248,6 +275,7 @@ type PaymentReceived struct {
 	PaymentReceivedDate   string    `db:"payment_received_date" json:"payment_received_date"`
 	PaymentMethodID       string    `db:"payment_method_id" json:"payment_method_id"`
 	Amount                float64   `db:"amount" json:"amount"`
+	DiscountAmount        float64   `db:"discount_amount" json:"discount_amount"`
 	Notes                 string    `db:"notes" json:"notes"`
 	Status                int       `db:"status" json:"status"`
 	Created               time.Time `db:"created" json:"created"`
@@ -255,3 +283,243 @@ type PaymentReceived struct {
 	Updated               time.Time `db:"updated" json:"updated"`
 	UpdatedBy             string    `db:"updated_by" json:"updated_by"`
 }
+
+type RecurringInvoice struct {
+	ID                 int64     `db:"id" json:"id"`
+	OrganizationID     string    `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID string    `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	ProfileName        string    `db:"profile_name" json:"profile_name"`
+	SalesorderID       string    `db:"salesorder_id" json:"salesorder_id"`
+	CustomerID         string    `db:"customer_id" json:"customer_id"`
+	InvoicePrefix      string    `db:"invoice_prefix" json:"invoice_prefix"`
+	Frequency          int       `db:"frequency" json:"frequency"`
+	RepeatEvery        int       `db:"repeat_every" json:"repeat_every"`
+	StartDate          string    `db:"start_date" json:"start_date"`
+	EndDate            string    `db:"end_date" json:"end_date"`
+	Occurrences        int       `db:"occurrences" json:"occurrences"`
+	GeneratedCount     int       `db:"generated_count" json:"generated_count"`
+	NextDate           string    `db:"next_date" json:"next_date"`
+	PaymentTermID      string    `db:"payment_term_id" json:"payment_term_id"`
+	DiscountType       int       `db:"discount_type" json:"discount_type"`
+	DiscountValue      float64   `db:"discount_value" json:"discount_value"`
+	ShippingFee        float64   `db:"shipping_fee" json:"shipping_fee"`
+	ShippingTaxID      string    `db:"shipping_tax_id" json:"shipping_tax_id"`
+	TaxInclusive       int       `db:"tax_inclusive" json:"tax_inclusive"`
+	Notes              string    `db:"notes" json:"notes"`
+	Status             int       `db:"status" json:"status"`
+	Created            time.Time `db:"created" json:"created"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	Updated            time.Time `db:"updated" json:"updated"`
+	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringInvoiceItem struct {
+	ID                     int64     `db:"id" json:"id"`
+	OrganizationID         string    `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID     string    `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	RecurringInvoiceItemID string    `db:"recurring_invoice_item_id" json:"recurring_invoice_item_id"`
+	SalesorderItemID       string    `db:"salesorder_item_id" json:"salesorder_item_id"`
+	ItemID                 string    `db:"item_id" json:"item_id"`
+	Quantity               int       `db:"quantity" json:"quantity"`
+	Rate                   float64   `db:"rate" json:"rate"`
+	TaxID                  string    `db:"tax_id" json:"tax_id"`
+	Status                 int       `db:"status" json:"status"`
+	Created                time.Time `db:"created" json:"created"`
+	CreatedBy              string    `db:"created_by" json:"created_by"`
+	Updated                time.Time `db:"updated" json:"updated"`
+	UpdatedBy              string    `db:"updated_by" json:"updated_by"`
+}
+
+type RecurringInvoiceRun struct {
+	ID                    int64     `db:"id" json:"id"`
+	OrganizationID        string    `db:"organization_id" json:"organization_id"`
+	RecurringInvoiceID    string    `db:"recurring_invoice_id" json:"recurring_invoice_id"`
+	RecurringInvoiceRunID string    `db:"recurring_invoice_run_id" json:"recurring_invoice_run_id"`
+	PeriodDate            string    `db:"period_date" json:"period_date"`
+	InvoiceID             string    `db:"invoice_id" json:"invoice_id"`
+	Message               string    `db:"message" json:"message"`
+	Status                int       `db:"status" json:"status"`
+	Created               time.Time `db:"created" json:"created"`
+	CreatedBy             string    `db:"created_by" json:"created_by"`
+	Updated               time.Time `db:"updated" json:"updated"`
+	UpdatedBy             string    `db:"updated_by" json:"updated_by"`
+}
+
+type ShippingLabel struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	ShippingLabelID string    `db:"shipping_label_id" json:"shipping_label_id"`
+	ShippingorderID string    `db:"shippingorder_id" json:"shippingorder_id"`
+	CarrierID       string    `db:"carrier_id" json:"carrier_id"`
+	ServiceCode     string    `db:"service_code" json:"service_code"`
+	TrackingNumber  string    `db:"tracking_number" json:"tracking_number"`
+	Format          string    `db:"format" json:"format"`
+	Data            []byte    `db:"data" json:"data"`
+	Cost            float64   `db:"cost" json:"cost"`
+	Status          int       `db:"status" json:"status"` //1 active 2 voided
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type ShippingTrackingEvent struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	TrackingEventID string    `db:"tracking_event_id" json:"tracking_event_id"`
+	ShippingorderID string    `db:"shippingorder_id" json:"shippingorder_id"`
+	TrackingNumber  string    `db:"tracking_number" json:"tracking_number"`
+	EventTime       string    `db:"event_time" json:"event_time"`
+	TrackingStatus  int       `db:"tracking_status" json:"tracking_status"`
+	Description     string    `db:"description" json:"description"`
+	Location        string    `db:"location" json:"location"`
+	Source          int       `db:"source" json:"source"` //1 webhook 2 polling
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type SalesorderHold struct {
+	ID               int64     `db:"id" json:"id"`
+	OrganizationID   string    `db:"organization_id" json:"organization_id"`
+	SalesorderHoldID string    `db:"salesorder_hold_id" json:"salesorder_hold_id"`
+	SalesorderID     string    `db:"salesorder_id" json:"salesorder_id"`
+	CustomerID       string    `db:"customer_id" json:"customer_id"`
+	Reason           int       `db:"reason" json:"reason"` //1 customer on hold, 2 over credit limit, 3 total raised pending approval
+	CreditLimit      float64   `db:"credit_limit" json:"credit_limit"`
+	Exposure         float64   `db:"exposure" json:"exposure"`
+	OrderTotal       float64   `db:"order_total" json:"order_total"`
+	ReleasedBy       string    `db:"released_by" json:"released_by"`
+	ReleasedAt       string    `db:"released_at" json:"released_at"`
+	ReleaseNotes     string    `db:"release_notes" json:"release_notes"`
+	Status           int       `db:"status" json:"status"` //1 on hold, 2 released
+	Created          time.Time `db:"created" json:"created"`
+	CreatedBy        string    `db:"created_by" json:"created_by"`
+	Updated          time.Time `db:"updated" json:"updated"`
+	UpdatedBy        string    `db:"updated_by" json:"updated_by"`
+}
+
+type Quote struct {
+	ID                int64     `db:"id" json:"id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	QuoteID           string    `db:"quote_id" json:"quote_id"`
+	QuoteNumber       string    `db:"quote_number" json:"quote_number"`
+	QuoteDate         string    `db:"quote_date" json:"quote_date"`
+	ValidUntil        string    `db:"valid_until" json:"valid_until"`
+	CustomerID        string    `db:"customer_id" json:"customer_id"`
+	LeadID            string    `db:"lead_id" json:"lead_id"`
+	ItemCount         int       `db:"item_count" json:"item_count"`
+	Subtotal          float64   `db:"subtotal" json:"subtotal"`
+	DiscountType      int       `db:"discount_type" json:"discount_type"`
+	DiscountValue     float64   `db:"discount_value" json:"discount_value"`
+	TaxTotal          float64   `db:"tax_total" json:"tax_total"`
+	ShippingFee       float64   `db:"shipping_fee" json:"shipping_fee"`
+	TaxInclusive      int       `db:"tax_inclusive" json:"tax_inclusive"`
+	ShippingTaxID     string    `db:"shipping_tax_id" json:"shipping_tax_id"`
+	ShippingTaxAmount float64   `db:"shipping_tax_amount" json:"shipping_tax_amount"`
+	Total             float64   `db:"total" json:"total"`
+	Notes             string    `db:"notes" json:"notes"`
+	SalesorderID      string    `db:"salesorder_id" json:"salesorder_id"`
+	Status            int       `db:"status" json:"status"` //1 draft, 2 sent, 3 accepted, 4 declined, 5 expired, 6 converted
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
+}
+
+type QuoteItem struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	QuoteID        string    `db:"quote_id" json:"quote_id"`
+	QuoteItemID    string    `db:"quote_item_id" json:"quote_item_id"`
+	ItemID         string    `db:"item_id" json:"item_id"`
+	Quantity       int       `db:"quantity" json:"quantity"`
+	Rate           float64   `db:"rate" json:"rate"`
+	TaxID          string    `db:"tax_id" json:"tax_id"`
+	TaxValue       float64   `db:"tax_value" json:"tax_value"`
+	TaxAmount      float64   `db:"tax_amount" json:"tax_amount"`
+	Amount         float64   `db:"amount" json:"amount"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type Dropshipment struct {
+	ID              int64     `db:"id" json:"id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	DropshipmentID  string    `db:"dropshipment_id" json:"dropshipment_id"`
+	SalesorderID    string    `db:"salesorder_id" json:"salesorder_id"`
+	PurchaseorderID string    `db:"purchaseorder_id" json:"purchaseorder_id"`
+	ShipmentDate    string    `db:"shipment_date" json:"shipment_date"`
+	CarrierName     string    `db:"carrier_name" json:"carrier_name"`
+	TrackingNumber  string    `db:"tracking_number" json:"tracking_number"`
+	Notes           string    `db:"notes" json:"notes"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type DropshipmentItem struct {
+	ID                  int64     `db:"id" json:"id"`
+	OrganizationID      string    `db:"organization_id" json:"organization_id"`
+	DropshipmentID      string    `db:"dropshipment_id" json:"dropshipment_id"`
+	DropshipmentItemID  string    `db:"dropshipment_item_id" json:"dropshipment_item_id"`
+	SalesorderItemID    string    `db:"salesorder_item_id" json:"salesorder_item_id"`
+	PurchaseorderItemID string    `db:"purchaseorder_item_id" json:"purchaseorder_item_id"`
+	ItemID              string    `db:"item_id" json:"item_id"`
+	Quantity            int       `db:"quantity" json:"quantity"`
+	Status              int       `db:"status" json:"status"`
+	Created             time.Time `db:"created" json:"created"`
+	CreatedBy           string    `db:"created_by" json:"created_by"`
+	Updated             time.Time `db:"updated" json:"updated"`
+	UpdatedBy           string    `db:"updated_by" json:"updated_by"`
+}
+
+type ForecastSetting struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	ItemID         string    `db:"item_id" json:"item_id"`
+	Method         int       `db:"method" json:"method"` //1 moving average, 2 exponential smoothing, 3 seasonal naive
+	Window         int       `db:"window_days" json:"window_days"`
+	Alpha          float64   `db:"alpha" json:"alpha"`
+	Beta           float64   `db:"beta" json:"beta"`
+	SeasonLength   int       `db:"season_length" json:"season_length"`
+	SafetyStock    int       `db:"safety_stock" json:"safety_stock"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type Forecast struct {
+	ID                int64     `db:"id" json:"id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	ForecastID        string    `db:"forecast_id" json:"forecast_id"`
+	ItemID            string    `db:"item_id" json:"item_id"`
+	Method            int       `db:"method" json:"method"`
+	ForecastDate      string    `db:"forecast_date" json:"forecast_date"` //first day covered by the forecast
+	PeriodEnd         string    `db:"period_end" json:"period_end"`       //last day covered by the forecast
+	HistoryDays       int       `db:"history_days" json:"history_days"`
+	HorizonDays       int       `db:"horizon_days" json:"horizon_days"`
+	LeadTime          int       `db:"lead_time" json:"lead_time"`
+	DailyDemand       float64   `db:"daily_demand" json:"daily_demand"`
+	ForecastQuantity  int       `db:"forecast_quantity" json:"forecast_quantity"` //demand over lead time plus horizon
+	SafetyStock       int       `db:"safety_stock" json:"safety_stock"`
+	StockAvailable    int       `db:"stock_available" json:"stock_available"`
+	OpenQuantity      int       `db:"open_quantity" json:"open_quantity"`
+	SuggestedQuantity int       `db:"suggested_quantity" json:"suggested_quantity"`
+	ActualQuantity    int       `db:"actual_quantity" json:"actual_quantity"`
+	Evaluated         int       `db:"evaluated" json:"evaluated"` //1 yes 2 no
+	Status            int       `db:"status" json:"status"`
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
+}
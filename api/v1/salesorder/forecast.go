@@ -0,0 +1,87 @@
+package salesorder
+
+import "math"
+
+const (
+	defaultForecastWindow       = 28
+	defaultForecastAlpha        = 0.3
+	defaultForecastBeta         = 0.1
+	defaultForecastSeasonLength = 7
+)
+
+// defaultForecastSetting is used for the items without a forecast setting
+func defaultForecastSetting(organizationID, itemID string) ForecastSettingResponse {
+	var setting ForecastSettingResponse
+	setting.OrganizationID = organizationID
+	setting.ItemID = itemID
+	setting.Method = 1
+	setting.Window = defaultForecastWindow
+	setting.Alpha = defaultForecastAlpha
+	setting.Beta = defaultForecastBeta
+	setting.SeasonLength = defaultForecastSeasonLength
+	setting.Status = 1
+	return setting
+}
+
+// forecastDemand projects the demand of each of the next days from the daily
+// history, oldest day first. Seasonal naive falls back to the moving average
+// while the history is shorter than one season.
+func forecastDemand(history []float64, setting ForecastSettingResponse, days int) []float64 {
+	switch setting.Method {
+	case 2:
+		return exponentialSmoothing(history, setting.Alpha, setting.Beta, days)
+	case 3:
+		if setting.SeasonLength > 0 && len(history) >= setting.SeasonLength {
+			return seasonalNaive(history, setting.SeasonLength, days)
+		}
+	}
+	return movingAverage(history, setting.Window, days)
+}
+
+// movingAverage repeats the mean of the last window days
+func movingAverage(history []float64, window, days int) []float64 {
+	res := make([]float64, days)
+	if window <= 0 || window > len(history) {
+		window = len(history)
+	}
+	if window == 0 {
+		return res
+	}
+	sum := 0.0
+	for _, v := range history[len(history)-window:] {
+		sum += v
+	}
+	for i := range res {
+		res[i] = sum / float64(window)
+	}
+	return res
+}
+
+// exponentialSmoothing uses Holt's linear method, alpha smooths the level and
+// beta the trend. The trend starts flat as daily sales are mostly sparse.
+func exponentialSmoothing(history []float64, alpha, beta float64, days int) []float64 {
+	res := make([]float64, days)
+	if len(history) == 0 {
+		return res
+	}
+	level, trend := history[0], 0.0
+	for _, v := range history[1:] {
+		last := level
+		level = alpha*v + (1-alpha)*(level+trend)
+		trend = beta*(level-last) + (1-beta)*trend
+	}
+	for i := range res {
+		res[i] = math.Max(level+float64(i+1)*trend, 0)
+	}
+	return res
+}
+
+// seasonalNaive repeats the last season
+func seasonalNaive(history []float64, seasonLength, days int) []float64 {
+	res := make([]float64, days)
+	last := history[len(history)-seasonLength:]
+	for i := range res {
+		res[i] = last[i%seasonLength]
+	}
+	return res
+}
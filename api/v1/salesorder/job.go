@@ -0,0 +1,25 @@
+package salesorder
+
+import (
+	"go-api/core/job"
+	"time"
+)
+
+func Schedule(r *job.Runner) {
+	r.Every("RecurringInvoice", time.Hour, func() error {
+		salesorderService := NewSalesorderService()
+		return salesorderService.RunDueRecurringInvoices()
+	})
+	r.Every("ShipmentTracking", time.Hour, func() error {
+		salesorderService := NewSalesorderService()
+		return salesorderService.PollShipmentTracking()
+	})
+	r.Every("QuoteExpiry", time.Hour, func() error {
+		salesorderService := NewSalesorderService()
+		return salesorderService.ExpireQuotes()
+	})
+	r.Every("ForecastAccuracy", time.Hour, func() error {
+		salesorderService := NewSalesorderService()
+		return salesorderService.EvaluateForecasts()
+	})
+}
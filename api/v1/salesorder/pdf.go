@@ -0,0 +1,278 @@
+package salesorder
+
+import (
+	"errors"
+	"go-api/api/v1/setting"
+	"go-api/core/database"
+	"go-api/core/pdf"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func customerAddressLines(customer *setting.CustomerResponse) []string {
+	contact := strings.TrimSpace(customer.ContactSalutation + " " + customer.ContactFirstName + " " + customer.ContactLastName)
+	cityLine := strings.TrimSpace(strings.Join([]string{customer.City, customer.State, customer.Zip}, " "))
+	return []string{customer.Name, contact, customer.Address1, customer.Address2, cityLine, customer.Country, customer.Phone}
+}
+
+// documentAddressLines prints the address saved on the document, documents
+// created before addresses were saved fall back to the customer address
+func documentAddressLines(address setting.AddressSnapshot, customer *setting.CustomerResponse) []string {
+	if address.Name == "" {
+		return customerAddressLines(customer)
+	}
+	return address.Lines()
+}
+
+func (s *salesorderService) GetInvoicePDF(organizationID, invoiceID string) ([]byte, string, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	invoice, err := query.GetInvoiceByID(organizationID, invoiceID)
+	if err != nil {
+		msg := "invoice not exist"
+		return nil, "", errors.New(msg)
+	}
+	items, err := query.GetInvoiceItemList(invoiceID)
+	if err != nil {
+		return nil, "", err
+	}
+	paid, err := query.GeInvoicePaymentReceived(organizationID, invoiceID)
+	if err != nil {
+		return nil, "", err
+	}
+	settingQuery := setting.NewSettingQuery(db)
+	customer, err := settingQuery.GetCustomerByID(organizationID, invoice.CustomerID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingService := setting.NewSettingService()
+	layout, err := settingService.NewDocumentLayout(organizationID, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	layout.Fields = []pdf.Field{
+		{Label: "Invoice #", Value: invoice.InvoiceNumber},
+		{Label: "Invoice Date", Value: invoice.InvoiceDate},
+		{Label: "Due Date", Value: invoice.DueDate},
+		{Label: "Sales Order #", Value: invoice.SalesorderNumber},
+	}
+	layout.Blocks = []pdf.Block{{Title: "Bill To", Lines: documentAddressLines(invoice.BillingAddress, customer)}}
+	if invoice.ShippingAddress.Name != "" && invoice.ShippingAddress != invoice.BillingAddress {
+		layout.Blocks = append(layout.Blocks, pdf.Block{Title: "Ship To", Lines: invoice.ShippingAddress.Lines()})
+	}
+	table := pdf.Table{Columns: []pdf.Column{
+		{Title: "Item", Width: 4},
+		{Title: "SKU", Width: 2},
+		{Title: "Qty", Width: 1, Align: pdf.AlignRight},
+		{Title: "Rate", Width: 1.5, Align: pdf.AlignRight},
+		{Title: "Tax", Width: 1.5, Align: pdf.AlignRight},
+		{Title: "Amount", Width: 1.5, Align: pdf.AlignRight},
+	}}
+	for _, item := range *items {
+		table.Rows = append(table.Rows, []string{item.ItemName, item.SKU, strconv.Itoa(item.Quantity), pdf.Money(item.Rate), pdf.Money(item.TaxAmount), pdf.Money(item.Amount)})
+	}
+	layout.Tables = []pdf.Table{table}
+	discount := invoice.DiscountValue
+	if invoice.DiscountType == 1 {
+		discount = (invoice.Subtotal + invoice.TaxTotal) * invoice.DiscountValue / 100
+	}
+	layout.Totals = []pdf.Field{{Label: "Subtotal", Value: pdf.Money(invoice.Subtotal)}}
+	if discount != 0 {
+		layout.Totals = append(layout.Totals, pdf.Field{Label: "Discount", Value: "-" + pdf.Money(discount)})
+	}
+	layout.Totals = append(layout.Totals,
+		pdf.Field{Label: "Tax", Value: pdf.Money(invoice.TaxTotal)},
+		pdf.Field{Label: "Shipping", Value: pdf.Money(invoice.ShippingFee + invoice.ShippingTaxAmount)},
+		pdf.Field{Label: "Total", Value: pdf.Money(invoice.Total)},
+		pdf.Field{Label: "Paid", Value: pdf.Money(paid)},
+		pdf.Field{Label: "Balance Due", Value: pdf.Money(invoice.Total - paid)},
+	)
+	layout.Notes = invoice.Notes
+	data, err := layout.Render()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, invoice.InvoiceNumber + ".pdf", nil
+}
+
+func (s *salesorderService) GetPackagePDF(organizationID, packageID string) ([]byte, string, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	pack, err := query.GetPackageByID(organizationID, packageID)
+	if err != nil {
+		msg := "package not exist"
+		return nil, "", errors.New(msg)
+	}
+	items, err := query.GetPackageItemList(packageID)
+	if err != nil {
+		return nil, "", err
+	}
+	salesorder, err := query.GetSalesorderByID(organizationID, pack.SalesorderID)
+	if err != nil {
+		msg := "sales order not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingQuery := setting.NewSettingQuery(db)
+	customer, err := settingQuery.GetCustomerByID(organizationID, salesorder.CustomerID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingService := setting.NewSettingService()
+	layout, err := settingService.NewDocumentLayout(organizationID, 3)
+	if err != nil {
+		return nil, "", err
+	}
+	layout.Fields = []pdf.Field{
+		{Label: "Package #", Value: pack.PackageNumber},
+		{Label: "Package Date", Value: pack.PackageDate},
+		{Label: "Sales Order #", Value: pack.SalesorderNumber},
+	}
+	layout.Blocks = []pdf.Block{{Title: "Ship To", Lines: documentAddressLines(salesorder.ShippingAddress, customer)}}
+	table := pdf.Table{Columns: []pdf.Column{
+		{Title: "Item", Width: 5},
+		{Title: "SKU", Width: 3},
+		{Title: "Qty", Width: 1.5, Align: pdf.AlignRight},
+	}}
+	total := 0
+	for _, item := range *items {
+		table.Rows = append(table.Rows, []string{item.ItemName, item.SKU, strconv.Itoa(item.Quantity)})
+		total += item.Quantity
+	}
+	layout.Tables = []pdf.Table{table}
+	layout.Totals = []pdf.Field{{Label: "Total Quantity", Value: strconv.Itoa(total)}}
+	layout.Notes = pack.Notes
+	data, err := layout.Render()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, pack.PackageNumber + ".pdf", nil
+}
+
+// GetPickingorderPDF renders the pick list sorted by location so the picker
+// can walk the warehouse in one pass.
+func (s *salesorderService) GetPickingorderPDF(organizationID, pickingorderID string) ([]byte, string, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	pickingorder, err := query.GetPickingorderByID(organizationID, pickingorderID)
+	if err != nil {
+		msg := "picking order not exist"
+		return nil, "", errors.New(msg)
+	}
+	details, err := query.GetPickingorderDetailList(pickingorderID)
+	if err != nil {
+		return nil, "", err
+	}
+	settingService := setting.NewSettingService()
+	layout, err := settingService.NewDocumentLayout(organizationID, 4)
+	if err != nil {
+		return nil, "", err
+	}
+	layout.Fields = []pdf.Field{
+		{Label: "Picking Order #", Value: pickingorder.PickingorderNumber},
+		{Label: "Date", Value: pickingorder.PickingorderDate},
+		{Label: "Sales Order #", Value: pickingorder.SalesorderNumber},
+	}
+	list := *details
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].LocationCode != list[j].LocationCode {
+			return list[i].LocationCode < list[j].LocationCode
+		}
+		return list[i].SKU < list[j].SKU
+	})
+	table := pdf.Table{Columns: []pdf.Column{
+		{Title: "Location", Width: 2},
+		{Title: "SKU", Width: 2},
+		{Title: "Item", Width: 4},
+		{Title: "Qty", Width: 1, Align: pdf.AlignRight},
+		{Title: "Picked", Width: 1, Align: pdf.AlignRight},
+		{Title: "Check", Width: 1, Align: pdf.AlignCenter},
+	}}
+	for _, detail := range list {
+		table.Rows = append(table.Rows, []string{detail.LocationCode, detail.SKU, detail.ItemName, strconv.Itoa(detail.Quantity), strconv.Itoa(detail.QuantityPicked), "[   ]"})
+	}
+	layout.Tables = []pdf.Table{table}
+	layout.Notes = pickingorder.Notes
+	data, err := layout.Render()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, pickingorder.PickingorderNumber + ".pdf", nil
+}
+
+func (s *salesorderService) GetShippingorderPDF(organizationID, shippingorderID string) ([]byte, string, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	shippingorder, err := query.GetShippingorderByID(organizationID, shippingorderID)
+	if err != nil {
+		msg := "shipping order not exist"
+		return nil, "", errors.New(msg)
+	}
+	details, err := query.GetShippingorderDetailList(shippingorderID)
+	if err != nil {
+		return nil, "", err
+	}
+	// a shipping order can hold several packages of the same sales order
+	packages := make(map[string]*PackageResponse)
+	for _, packageID := range strings.Split(shippingorder.PackageID, ",") {
+		pack, err := query.GetPackageByID(organizationID, packageID)
+		if err != nil {
+			msg := "package not exist"
+			return nil, "", errors.New(msg)
+		}
+		packages[packageID] = pack
+	}
+	firstPackageID := strings.Split(shippingorder.PackageID, ",")[0]
+	salesorder, err := query.GetSalesorderByID(organizationID, packages[firstPackageID].SalesorderID)
+	if err != nil {
+		msg := "sales order not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingQuery := setting.NewSettingQuery(db)
+	customer, err := settingQuery.GetCustomerByID(organizationID, salesorder.CustomerID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, "", errors.New(msg)
+	}
+	settingService := setting.NewSettingService()
+	layout, err := settingService.NewDocumentLayout(organizationID, 5)
+	if err != nil {
+		return nil, "", err
+	}
+	layout.Fields = []pdf.Field{
+		{Label: "Shipping Order #", Value: shippingorder.ShippingorderNumber},
+		{Label: "Ship Date", Value: shippingorder.ShippingorderDate},
+		{Label: "Sales Order #", Value: salesorder.SalesorderNumber},
+		{Label: "Carrier", Value: shippingorder.CarrierName},
+		{Label: "Tracking #", Value: shippingorder.TrackingNumber},
+	}
+	layout.Blocks = []pdf.Block{
+		{Title: "Ship From", Lines: strings.Split(layout.CompanyInfo, "\n")},
+		{Title: "Ship To", Lines: documentAddressLines(shippingorder.ShippingAddress, customer)},
+	}
+	table := pdf.Table{Columns: []pdf.Column{
+		{Title: "Package", Width: 2},
+		{Title: "SKU", Width: 2},
+		{Title: "Item", Width: 4},
+		{Title: "Qty", Width: 1, Align: pdf.AlignRight},
+	}}
+	total := 0
+	for _, detail := range *details {
+		packageNumber := ""
+		if pack, ok := packages[detail.PackageID]; ok {
+			packageNumber = pack.PackageNumber
+		}
+		table.Rows = append(table.Rows, []string{packageNumber, detail.SKU, detail.ItemName, strconv.Itoa(detail.Quantity)})
+		total += detail.Quantity
+	}
+	layout.Tables = []pdf.Table{table}
+	layout.Totals = []pdf.Field{{Label: "Total Quantity", Value: strconv.Itoa(total)}}
+	layout.Notes = shippingorder.Notes
+	data, err := layout.Render()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, shippingorder.ShippingorderNumber + ".pdf", nil
+}
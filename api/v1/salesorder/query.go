@@ -33,12 +33,18 @@ func (r *salesorderQuery) GetSalesorderByID(organizationID, id string) (*Salesor
 	s.discount_type,
 	s.discount_value,
 	s.shipping_fee,
+	s.tax_inclusive,
+	s.shipping_tax_id,
+	s.shipping_tax_amount,
 	s.total,
 	s.notes,
 	s.invoice_status,
 	s.picking_status,
 	s.packing_status,
 	s.shipping_status,
+	s.approval_status,
+	s.billing_address,
+	s.shipping_address,
 	s.status
 	FROM s_salesorders s
 	LEFT JOIN s_customers c
@@ -90,12 +96,18 @@ func (r *salesorderQuery) GetSalesorderList(filter SalesorderFilter) (*[]Salesor
 		s.discount_type,
 		s.discount_value,
 		s.shipping_fee,
+		s.tax_inclusive,
+		s.shipping_tax_id,
+		s.shipping_tax_amount,
 		s.total,
 		s.notes,
 		s.invoice_status,
 		s.picking_status,
 		s.packing_status,
 		s.shipping_status,
+		s.approval_status,
+		s.billing_address,
+		s.shipping_address,
 		s.status
 		FROM s_salesorders s
 		LEFT JOIN s_customers c
@@ -116,6 +128,10 @@ func (r *salesorderQuery) GetSalesorderItemList(salesorderID string) (*[]Salesor
 		s.item_id,
 		i.name as item_name,
 		i.sku as sku,
+		s.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		s.unit_quantity,
+		s.unit_rate,
 		s.quantity,
 		s.rate,
 		s.tax_id,
@@ -126,10 +142,14 @@ func (r *salesorderQuery) GetSalesorderItemList(salesorderID string) (*[]Salesor
 		s.quantity_picked,
 		s.quantity_packed,
 		s.quantity_shipped,
+		s.drop_ship,
+		s.purchaseorder_id,
 		s.status
 		FROM s_salesorder_items s
 		LEFT JOIN i_items i
 		ON s.item_id = i.item_id
+		LEFT JOIN s_units u
+		ON s.unit_id = u.unit_id
 		WHERE s.salesorder_id = ? AND s.status > 0 
 	`, salesorderID)
 	return &salesorders, err
@@ -388,7 +408,10 @@ func (r *salesorderQuery) GetShippingorderList(filter ShippingorderFilter) (*[]S
 		s.shippingorder_date, 
 		s.carrier_id,
 		IFNULL(c.name, "") as carrier_name,
+		s.tracking_number,
+		s.tracking_status,
 		s.notes,
+		s.shipping_address,
 		s.status
 		FROM s_shippingorders s
 		LEFT JOIN s_packages p
@@ -413,7 +436,10 @@ func (r *salesorderQuery) GetShippingorderByID(organizationID, id string) (*Ship
 	s.shippingorder_date, 
 	s.carrier_id,
 	IFNULL(c.name, "") as carrier_name,
+	s.tracking_number,
+	s.tracking_status,
 	s.notes,
+	s.shipping_address,
 	s.status
 	FROM s_shippingorders s
 	LEFT JOIN s_packages p
@@ -467,25 +493,355 @@ func (r *salesorderQuery) GetShippingorderDetailList(shippingorderID string) (*[
 	return &shippingorderDetails, err
 }
 
-func (r *salesorderQuery) GetRequisitionList(filter RequsitionFilter) (*[]RequsitionResponse, error) {
-	var res []RequsitionResponse
-	err := r.conn.Select(&res, `
-		SELECT CEIL(SUM(ssi.quantity)/?*?) as target_stock , ssi.item_id , ii.name as item_name, ii.sku, ii.stock_available as stock_on_hand, (CEIL(SUM(ssi.quantity)/?*?)-ii.stock_available) as quantity, su.name as unit
-		FROM s_salesorder_items ssi 
-		LEFT JOIN s_salesorders ss  
-		on ss.salesorder_id  = ssi.salesorder_id 
-		LEFT JOIN i_items ii 
-		ON ssi.item_id  = ii.item_id 
-		LEFT JOIN s_units su 
-		ON ii.unit_id = su.unit_id 
-		where ssi.status  > 0 
-		AND ss.status > 0
-		AND ss.salesorder_date > ?
-		AND ss.salesorder_date < ?
-		GROUP BY ssi.item_id  
-	`, filter.Period, filter.TargetDay, filter.Period, filter.TargetDay, filter.StartDate, filter.EndDate)
-	return &res, err
+//forecast
 
+// GetForecastItems lists the purchasable items sold between the dates or with
+// a forecast setting, or the given item whatever its history, with the lead time of their default vendor and what is
+// still to receive on draft and issued purchase orders
+func (r *salesorderQuery) GetForecastItems(organizationID, itemID, startDate, endDate string) (*[]ForecastItemResponse, error) {
+	where, args := []string{"i.organization_id = ?", "i.status = 1", "i.item_type <> 2"}, []interface{}{organizationID}
+	if itemID != "" {
+		where, args = append(where, "i.item_id = ?"), append(args, itemID)
+	} else {
+		where, args = append(where, `(
+			EXISTS (
+				SELECT 1
+				FROM s_salesorder_items ssi
+				INNER JOIN s_salesorders ss
+				ON ssi.salesorder_id = ss.salesorder_id
+				WHERE ssi.item_id = i.item_id AND ssi.status > 0 AND ss.status > 0
+				AND ss.salesorder_date >= ? AND ss.salesorder_date <= ?
+			)
+			OR EXISTS (
+				SELECT 1
+				FROM s_forecast_settings fs
+				WHERE fs.item_id = i.item_id AND fs.status > 0
+			)
+		)`), append(args, startDate, endDate)
+	}
+	var items []ForecastItemResponse
+	err := r.conn.Select(&items, `
+		SELECT
+		i.item_id,
+		i.name as item_name,
+		i.sku,
+		IFNULL(u.name, "") as unit,
+		i.stock_available,
+		IFNULL(v.lead_time, 0) as lead_time,
+		IFNULL((
+			SELECT SUM(pi.quantity - pi.quantity_received)
+			FROM p_purchaseorder_items pi
+			INNER JOIN p_purchaseorders p
+			ON pi.purchaseorder_id = p.purchaseorder_id
+			WHERE pi.item_id = i.item_id AND pi.status > 0 AND p.status IN (1, 2)
+		), 0) as open_quantity
+		FROM i_items i
+		LEFT JOIN s_units u
+		ON i.unit_id = u.unit_id
+		LEFT JOIN s_vendors v
+		ON i.default_vendor_id = v.vendor_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY i.id ASC
+	`, args...)
+	return &items, err
+}
+
+// GetDailyDemand sums the sold quantity per item and sales order date, days
+// without sales are not returned. A kit sold counts as demand for each of its
+// components.
+func (r *salesorderQuery) GetDailyDemand(organizationID, itemID, startDate, endDate string) (*[]DailyDemandResponse, error) {
+	where, args := []string{"ss.organization_id = ?", "ssi.status > 0", "ss.status > 0", "ss.salesorder_date >= ?", "ss.salesorder_date <= ?"}, []interface{}{organizationID, startDate, endDate}
+	kitWhere, kitArgs := append([]string{"c.status > 0"}, where...), append([]interface{}{}, args...)
+	if itemID != "" {
+		where, args = append(where, "ssi.item_id = ?"), append(args, itemID)
+		kitWhere, kitArgs = append(kitWhere, "c.component_id = ?"), append(kitArgs, itemID)
+	}
+	var demands []DailyDemandResponse
+	err := r.conn.Select(&demands, `
+		SELECT
+		d.item_id,
+		DATE_FORMAT(d.salesorder_date, "%Y-%m-%d") as demand_date,
+		SUM(d.quantity) as quantity
+		FROM (
+			SELECT ssi.item_id, ss.salesorder_date, ssi.quantity
+			FROM s_salesorder_items ssi
+			INNER JOIN s_salesorders ss
+			ON ssi.salesorder_id = ss.salesorder_id
+			WHERE `+strings.Join(where, " AND ")+`
+			UNION ALL
+			SELECT c.component_id as item_id, ss.salesorder_date, ssi.quantity * c.quantity as quantity
+			FROM s_salesorder_items ssi
+			INNER JOIN s_salesorders ss
+			ON ssi.salesorder_id = ss.salesorder_id
+			INNER JOIN i_item_components c
+			ON ssi.item_id = c.item_id
+			WHERE `+strings.Join(kitWhere, " AND ")+`
+		) d
+		GROUP BY d.item_id, d.salesorder_date
+	`, append(args, kitArgs...)...)
+	return &demands, err
+}
+
+func (r *salesorderQuery) GetForecastSettings(organizationID string) (*[]ForecastSettingResponse, error) {
+	var settings []ForecastSettingResponse
+	err := r.conn.Select(&settings, `
+		SELECT
+		fs.organization_id,
+		fs.item_id,
+		i.name as item_name,
+		i.sku,
+		fs.method,
+		fs.window_days,
+		fs.alpha,
+		fs.beta,
+		fs.season_length,
+		fs.safety_stock,
+		fs.status
+		FROM s_forecast_settings fs
+		LEFT JOIN i_items i
+		ON fs.item_id = i.item_id
+		WHERE fs.organization_id = ? AND fs.status > 0
+	`, organizationID)
+	return &settings, err
+}
+
+func (r *salesorderQuery) GetForecastSettingByID(organizationID, itemID string) (*ForecastSettingResponse, error) {
+	var setting ForecastSettingResponse
+	err := r.conn.Get(&setting, `
+		SELECT
+		fs.organization_id,
+		fs.item_id,
+		i.name as item_name,
+		i.sku,
+		fs.method,
+		fs.window_days,
+		fs.alpha,
+		fs.beta,
+		fs.season_length,
+		fs.safety_stock,
+		fs.status
+		FROM s_forecast_settings fs
+		LEFT JOIN i_items i
+		ON fs.item_id = i.item_id
+		WHERE fs.organization_id = ? AND fs.item_id = ? AND fs.status > 0
+	`, organizationID, itemID)
+	return &setting, err
+}
+
+func (r *salesorderQuery) GetForecastSettingCount(filter ForecastSettingFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "item_id = ?"), append(args, v)
+	}
+	if v := filter.Method; v != 0 {
+		where, args = append(where, "method = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_forecast_settings
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetForecastSettingList(filter ForecastSettingFilter) (*[]ForecastSettingResponse, error) {
+	where, args := []string{"fs.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "fs.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "fs.item_id = ?"), append(args, v)
+	}
+	if v := filter.Method; v != 0 {
+		where, args = append(where, "fs.method = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var settings []ForecastSettingResponse
+	err := r.conn.Select(&settings, `
+		SELECT
+		fs.organization_id,
+		fs.item_id,
+		i.name as item_name,
+		i.sku,
+		fs.method,
+		fs.window_days,
+		fs.alpha,
+		fs.beta,
+		fs.season_length,
+		fs.safety_stock,
+		fs.status
+		FROM s_forecast_settings fs
+		LEFT JOIN i_items i
+		ON fs.item_id = i.item_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY fs.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &settings, err
+}
+
+func (r *salesorderQuery) GetForecastCount(filter ForecastFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "item_id = ?"), append(args, v)
+	}
+	if v := filter.ForecastDate; v != "" {
+		where, args = append(where, "forecast_date = ?"), append(args, v)
+	}
+	if v := filter.Evaluated; v != 0 {
+		where, args = append(where, "evaluated = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_forecasts
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetForecastList(filter ForecastFilter) (*[]ForecastResponse, error) {
+	where, args := []string{"f.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "f.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "f.item_id = ?"), append(args, v)
+	}
+	if v := filter.ForecastDate; v != "" {
+		where, args = append(where, "f.forecast_date = ?"), append(args, v)
+	}
+	if v := filter.Evaluated; v != 0 {
+		where, args = append(where, "f.evaluated = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var forecasts []ForecastResponse
+	err := r.conn.Select(&forecasts, `
+		SELECT
+		f.organization_id,
+		f.forecast_id,
+		f.item_id,
+		i.name as item_name,
+		i.sku,
+		IFNULL(u.name, "") as unit,
+		f.method,
+		DATE_FORMAT(f.forecast_date, "%Y-%m-%d") as forecast_date,
+		DATE_FORMAT(f.period_end, "%Y-%m-%d") as period_end,
+		f.history_days,
+		f.horizon_days,
+		f.lead_time,
+		f.daily_demand,
+		f.forecast_quantity,
+		f.safety_stock,
+		f.stock_available,
+		f.open_quantity,
+		f.suggested_quantity,
+		f.actual_quantity,
+		f.evaluated,
+		f.status
+		FROM s_forecasts f
+		LEFT JOIN i_items i
+		ON f.item_id = i.item_id
+		LEFT JOIN s_units u
+		ON i.unit_id = u.unit_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY f.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &forecasts, err
+}
+
+// GetDueForecastList lists the forecasts of every organization whose period
+// ended before the given date and that are not compared with the sales yet
+func (r *salesorderQuery) GetDueForecastList(date string) (*[]Forecast, error) {
+	var forecasts []Forecast
+	err := r.conn.Select(&forecasts, `
+		SELECT
+		organization_id,
+		forecast_id,
+		item_id,
+		DATE_FORMAT(forecast_date, "%Y-%m-%d") as forecast_date,
+		DATE_FORMAT(period_end, "%Y-%m-%d") as period_end
+		FROM s_forecasts
+		WHERE evaluated = 2 AND period_end < ? AND status > 0
+	`, date)
+	return &forecasts, err
+}
+
+// GetItemDemand sums the quantity of an item sold between the dates, both
+// included, alone or as a component of the kits sold
+func (r *salesorderQuery) GetItemDemand(organizationID, itemID, startDate, endDate string) (int, error) {
+	var quantity int
+	err := r.conn.Get(&quantity, `
+		SELECT IFNULL(SUM(ssi.quantity * IFNULL(c.quantity, 1)), 0) as quantity
+		FROM s_salesorder_items ssi
+		INNER JOIN s_salesorders ss
+		ON ssi.salesorder_id = ss.salesorder_id
+		LEFT JOIN i_item_components c
+		ON ssi.item_id = c.item_id AND c.component_id = ? AND c.status > 0
+		WHERE ss.organization_id = ? AND (ssi.item_id = ? OR c.component_id IS NOT NULL) AND ssi.status > 0 AND ss.status > 0
+		AND ss.salesorder_date >= ? AND ss.salesorder_date <= ?
+	`, itemID, organizationID, itemID, startDate, endDate)
+	return quantity, err
+}
+
+func forecastAccuracyWhere(filter ForecastAccuracyFilter) (string, []interface{}) {
+	where, args := []string{"f.status > 0", "f.evaluated = 1"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "f.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "f.item_id = ?"), append(args, v)
+	}
+	if v := filter.Method; v != 0 {
+		where, args = append(where, "f.method = ?"), append(args, v)
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (r *salesorderQuery) GetForecastAccuracyCount(filter ForecastAccuracyFilter) (int, error) {
+	where, args := forecastAccuracyWhere(filter)
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(DISTINCT f.item_id, f.method) as count
+		FROM s_forecasts f
+		WHERE `+where, args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetForecastAccuracyList(filter ForecastAccuracyFilter) (*[]ForecastAccuracyResponse, error) {
+	where, args := forecastAccuracyWhere(filter)
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var accuracies []ForecastAccuracyResponse
+	err := r.conn.Select(&accuracies, `
+		SELECT
+		f.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		f.method,
+		count(1) as forecast_count,
+		SUM(f.forecast_quantity) as forecast_quantity,
+		SUM(f.actual_quantity) as actual_quantity,
+		AVG(ABS(f.forecast_quantity - f.actual_quantity)) as mae,
+		IFNULL(AVG(CASE WHEN f.actual_quantity > 0 THEN ABS(f.forecast_quantity - f.actual_quantity) / f.actual_quantity * 100 END), 0) as mape,
+		AVG(f.forecast_quantity - f.actual_quantity) as bias
+		FROM s_forecasts f
+		LEFT JOIN i_items i
+		ON f.item_id = i.item_id
+		WHERE `+where+`
+		GROUP BY f.item_id, i.name, i.sku, f.method
+		ORDER BY f.item_id ASC, f.method ASC
+		LIMIT ?, ?
+	`, args...)
+	return &accuracies, err
 }
 
 func (r *salesorderQuery) GetInvoiceCount(filter InvoiceFilter) (int, error) {
@@ -538,8 +894,16 @@ func (r *salesorderQuery) GetInvoiceList(filter InvoiceFilter) (*[]InvoiceRespon
 		i.discount_value,
 		i.tax_total,
 		i.shipping_fee,
+		i.tax_inclusive,
+		i.shipping_tax_id,
+		i.shipping_tax_amount,
 		i.total,
+		i.payment_term_id,
+		i.discount_date,
+		i.discount_percent,
 		i.notes,
+		i.billing_address,
+		i.shipping_address,
 		i.status
 		FROM s_invoices i
 		LEFT JOIN s_salesorders s
@@ -571,8 +935,16 @@ func (r *salesorderQuery) GetInvoiceByID(organizationID, id string) (*InvoiceRes
 	i.discount_value,
 	i.tax_total,
 	i.shipping_fee,
+	i.tax_inclusive,
+	i.shipping_tax_id,
+	i.shipping_tax_amount,
 	i.total,
+	i.payment_term_id,
+	i.discount_date,
+	i.discount_percent,
 	i.notes,
+	i.billing_address,
+	i.shipping_address,
 	i.status
 	FROM s_invoices i
 	LEFT JOIN s_salesorders s
@@ -595,6 +967,10 @@ func (r *salesorderQuery) GetInvoiceItemList(invoiceID string) (*[]InvoiceItemRe
 		s.item_id,
 		i.name as item_name,
 		i.sku as sku,
+		s.unit_id,
+		IFNULL(u.name, "") as unit_name,
+		s.unit_quantity,
+		s.unit_rate,
 		s.quantity,
 		s.rate,
 		s.tax_id,
@@ -605,6 +981,8 @@ func (r *salesorderQuery) GetInvoiceItemList(invoiceID string) (*[]InvoiceItemRe
 		FROM s_invoice_items s
 		LEFT JOIN i_items i
 		ON s.item_id = i.item_id
+		LEFT JOIN s_units u
+		ON s.unit_id = u.unit_id
 		WHERE s.invoice_id = ? AND i.status > 0 
 	`, invoiceID)
 	return &invoiceItems, err
@@ -613,7 +991,7 @@ func (r *salesorderQuery) GetInvoiceItemList(invoiceID string) (*[]InvoiceItemRe
 func (r *salesorderQuery) GeInvoicePaymentReceived(organizationID, invoiceID string) (float64, error) {
 	var count float64
 	err := r.conn.Get(&count, `
-		SELECT IFNULL(SUM(amount),0) FROM s_payment_receiveds 
+		SELECT IFNULL(SUM(amount + discount_amount),0) FROM s_payment_receiveds 
 		WHERE organization_id = ? AND invoice_id = ? AND status > 0 
 		`, organizationID, invoiceID)
 	return count, err
@@ -671,6 +1049,7 @@ func (r *salesorderQuery) GetPaymentReceivedList(filter PaymentReceivedFilter) (
 		p.payment_method_id,
 		IFNULL(pm.name, "") as payment_method_name,
 		p.amount,
+		p.discount_amount,
 		p.notes,
 		p.status
 		FROM s_payment_receiveds p
@@ -685,3 +1064,608 @@ func (r *salesorderQuery) GetPaymentReceivedList(filter PaymentReceivedFilter) (
 	`, args...)
 	return &paymentReceiveds, err
 }
+
+// recurring invoice
+
+func (r *salesorderQuery) GetRecurringInvoiceCount(filter RecurringInvoiceFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ProfileName; v != "" {
+		where, args = append(where, "profile_name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.SalesorderID; v != "" {
+		where, args = append(where, "salesorder_id = ?"), append(args, v)
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "customer_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_recurring_invoices
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetRecurringInvoiceList(filter RecurringInvoiceFilter) (*[]RecurringInvoiceResponse, error) {
+	where, args := []string{"r.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "r.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ProfileName; v != "" {
+		where, args = append(where, "r.profile_name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.SalesorderID; v != "" {
+		where, args = append(where, "r.salesorder_id = ?"), append(args, v)
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "r.customer_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "r.status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var recurringInvoices []RecurringInvoiceResponse
+	err := r.conn.Select(&recurringInvoices, `
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.profile_name,
+		r.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		r.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		r.invoice_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM s_recurring_invoices r
+		LEFT JOIN s_salesorders s
+		ON r.salesorder_id = s.salesorder_id
+		LEFT JOIN s_customers c
+		ON r.customer_id = c.customer_id
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &recurringInvoices, err
+}
+
+func (r *salesorderQuery) GetRecurringInvoiceByID(organizationID, id string) (*RecurringInvoiceResponse, error) {
+	var recurringInvoice RecurringInvoiceResponse
+	err := r.conn.Get(&recurringInvoice, `
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.profile_name,
+		r.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		r.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		r.invoice_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM s_recurring_invoices r
+		LEFT JOIN s_salesorders s
+		ON r.salesorder_id = s.salesorder_id
+		LEFT JOIN s_customers c
+		ON r.customer_id = c.customer_id
+		WHERE r.organization_id = ? AND r.recurring_invoice_id = ? AND r.status > 0
+	`, organizationID, id)
+	return &recurringInvoice, err
+}
+
+func (r *salesorderQuery) GetDueRecurringInvoiceList(date string) (*[]RecurringInvoiceResponse, error) {
+	var recurringInvoices []RecurringInvoiceResponse
+	err := r.conn.Select(&recurringInvoices, `
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.profile_name,
+		r.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		r.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		r.invoice_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM s_recurring_invoices r
+		LEFT JOIN s_salesorders s
+		ON r.salesorder_id = s.salesorder_id
+		LEFT JOIN s_customers c
+		ON r.customer_id = c.customer_id
+		WHERE r.status = 1 AND r.next_date <= ?
+	`, date)
+	return &recurringInvoices, err
+}
+
+func (r *salesorderQuery) GetRecurringInvoiceItemList(organizationID, recurringInvoiceID string) (*[]RecurringInvoiceItemResponse, error) {
+	var recurringInvoiceItems []RecurringInvoiceItemResponse
+	err := r.conn.Select(&recurringInvoiceItems, `
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.recurring_invoice_item_id,
+		r.salesorder_item_id,
+		r.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		r.quantity,
+		r.rate,
+		r.tax_id,
+		r.status
+		FROM s_recurring_invoice_items r
+		LEFT JOIN i_items i
+		ON r.item_id = i.item_id
+		WHERE r.organization_id = ? AND r.recurring_invoice_id = ? AND r.status > 0
+	`, organizationID, recurringInvoiceID)
+	return &recurringInvoiceItems, err
+}
+
+func (r *salesorderQuery) GetRecurringInvoiceRunList(organizationID, recurringInvoiceID string) (*[]RecurringInvoiceRunResponse, error) {
+	var recurringInvoiceRuns []RecurringInvoiceRunResponse
+	err := r.conn.Select(&recurringInvoiceRuns, `
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.recurring_invoice_run_id,
+		r.period_date,
+		r.invoice_id,
+		IFNULL(i.invoice_number, "") as invoice_number,
+		r.message,
+		r.status
+		FROM s_recurring_invoice_runs r
+		LEFT JOIN s_invoices i
+		ON r.invoice_id = i.invoice_id
+		WHERE r.organization_id = ? AND r.recurring_invoice_id = ? AND r.status > 0
+		ORDER BY r.period_date DESC
+	`, organizationID, recurringInvoiceID)
+	return &recurringInvoiceRuns, err
+}
+
+//shipping label
+
+func (r *salesorderQuery) GetShippingLabelList(organizationID, shippingorderID string) (*[]ShippingLabelResponse, error) {
+	var labels []ShippingLabelResponse
+	err := r.conn.Select(&labels, `
+		SELECT
+		l.organization_id,
+		l.shipping_label_id,
+		l.shippingorder_id,
+		l.carrier_id,
+		IFNULL(c.name, "") as carrier_name,
+		l.service_code,
+		l.tracking_number,
+		l.format,
+		l.cost,
+		l.status
+		FROM s_shipping_labels l
+		LEFT JOIN s_carriers c
+		ON l.carrier_id = c.carrier_id
+		WHERE l.organization_id = ? AND l.shippingorder_id = ? AND l.status > 0
+		ORDER BY l.id DESC
+	`, organizationID, shippingorderID)
+	return &labels, err
+}
+
+func (r *salesorderQuery) GetShippingLabel(organizationID, shippingorderID string) (*ShippingLabelResponse, error) {
+	var label ShippingLabelResponse
+	err := r.conn.Get(&label, `
+		SELECT
+		l.organization_id,
+		l.shipping_label_id,
+		l.shippingorder_id,
+		l.carrier_id,
+		IFNULL(c.name, "") as carrier_name,
+		l.service_code,
+		l.tracking_number,
+		l.format,
+		l.data,
+		l.cost,
+		l.status
+		FROM s_shipping_labels l
+		LEFT JOIN s_carriers c
+		ON l.carrier_id = c.carrier_id
+		WHERE l.organization_id = ? AND l.shippingorder_id = ? AND l.status = 1
+		ORDER BY l.id DESC
+		LIMIT 1
+	`, organizationID, shippingorderID)
+	return &label, err
+}
+
+func (r *salesorderQuery) GetPackageParcelItemList(organizationID, packageID string) (*[]PackageParcelItemResponse, error) {
+	var items []PackageParcelItemResponse
+	err := r.conn.Select(&items, `
+		SELECT
+		p.package_id,
+		p.item_id,
+		p.quantity,
+		i.weight,
+		IFNULL(u1.name, "") as weight_unit_name,
+		i.length,
+		i.width,
+		i.height,
+		IFNULL(u2.name, "") as dimension_unit_name
+		FROM s_package_items p
+		LEFT JOIN i_items i
+		ON p.item_id = i.item_id
+		LEFT JOIN s_units u1
+		ON i.weight_unit = u1.unit_id
+		LEFT JOIN s_units u2
+		ON i.dimension_unit = u2.unit_id
+		WHERE p.organization_id = ? AND p.package_id = ? AND p.status > 0
+	`, organizationID, packageID)
+	return &items, err
+}
+
+//tracking
+
+func (r *salesorderQuery) GetShippingTrackingEventList(organizationID, shippingorderID string) (*[]ShippingTrackingEventResponse, error) {
+	var events []ShippingTrackingEventResponse
+	err := r.conn.Select(&events, `
+		SELECT organization_id, tracking_event_id, shippingorder_id, tracking_number, event_time, tracking_status, description, location, source
+		FROM s_shipping_tracking_events
+		WHERE organization_id = ? AND shippingorder_id = ? AND status > 0
+		ORDER BY event_time DESC, id DESC
+	`, organizationID, shippingorderID)
+	return &events, err
+}
+
+// GetTrackingShippingorderList returns the shipping orders still on their way
+// whose carrier has an adapter to poll
+func (r *salesorderQuery) GetTrackingShippingorderList(since string) (*[]TrackingShippingorderResponse, error) {
+	var shippingorders []TrackingShippingorderResponse
+	err := r.conn.Select(&shippingorders, `
+		SELECT s.organization_id, s.shippingorder_id, s.carrier_id, s.tracking_number, s.tracking_status
+		FROM s_shippingorders s
+		INNER JOIN s_carriers c
+		ON s.carrier_id = c.carrier_id
+		WHERE s.status > 0 AND s.tracking_number != "" AND s.tracking_status != 5 AND s.shippingorder_date >= ? AND c.adapter != "" AND c.status > 0
+	`, since)
+	return &shippingorders, err
+}
+
+//credit
+
+func (r *salesorderQuery) GetSalesorderHoldCount(filter SalesorderHoldFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "customer_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_salesorder_holds
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetSalesorderHoldList(filter SalesorderHoldFilter) (*[]SalesorderHoldResponse, error) {
+	where, args := []string{"h.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "h.organization_id = ?"), append(args, v)
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "h.customer_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "h.status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var holds []SalesorderHoldResponse
+	err := r.conn.Select(&holds, `
+		SELECT
+		h.organization_id,
+		h.salesorder_hold_id,
+		h.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		IFNULL(s.salesorder_date, "") as salesorder_date,
+		h.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		h.reason,
+		h.credit_limit,
+		h.exposure,
+		h.order_total,
+		h.released_by,
+		h.released_at,
+		h.release_notes,
+		h.status
+		FROM s_salesorder_holds h
+		LEFT JOIN s_salesorders s
+		ON h.salesorder_id = s.salesorder_id
+		LEFT JOIN s_customers c
+		ON h.customer_id = c.customer_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY h.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &holds, err
+}
+
+func (r *salesorderQuery) GetQuoteCount(filter QuoteFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.QuoteNumber; v != "" {
+		where, args = append(where, "quote_number like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "customer_id = ?"), append(args, v)
+	}
+	if v := filter.LeadID; v != "" {
+		where, args = append(where, "lead_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "status = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_quotes
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *salesorderQuery) GetQuoteList(filter QuoteFilter) (*[]QuoteResponse, error) {
+	where, args := []string{"q.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "q.organization_id = ?"), append(args, v)
+	}
+	if v := filter.QuoteNumber; v != "" {
+		where, args = append(where, "q.quote_number like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.CustomerID; v != "" {
+		where, args = append(where, "q.customer_id = ?"), append(args, v)
+	}
+	if v := filter.LeadID; v != "" {
+		where, args = append(where, "q.lead_id = ?"), append(args, v)
+	}
+	if v := filter.Status; v != 0 {
+		where, args = append(where, "q.status = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var quotes []QuoteResponse
+	err := r.conn.Select(&quotes, `
+		SELECT
+		q.organization_id,
+		q.quote_id,
+		q.quote_number,
+		q.quote_date,
+		q.valid_until,
+		q.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		q.lead_id,
+		IFNULL(l.company, "") as lead_company,
+		q.item_count,
+		q.sub_total,
+		q.tax_total,
+		q.discount_type,
+		q.discount_value,
+		q.shipping_fee,
+		q.tax_inclusive,
+		q.shipping_tax_id,
+		q.shipping_tax_amount,
+		q.total,
+		q.notes,
+		q.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		q.status
+		FROM s_quotes q
+		LEFT JOIN s_customers c
+		ON q.customer_id = c.customer_id
+		LEFT JOIN c_leads l
+		ON q.lead_id = l.lead_id
+		LEFT JOIN s_salesorders s
+		ON q.salesorder_id = s.salesorder_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY q.quote_date DESC
+		LIMIT ?, ?
+	`, args...)
+	return &quotes, err
+}
+
+func (r *salesorderQuery) GetQuoteByID(organizationID, id string) (*QuoteResponse, error) {
+	var quote QuoteResponse
+	err := r.conn.Get(&quote, `
+		SELECT
+		q.organization_id,
+		q.quote_id,
+		q.quote_number,
+		q.quote_date,
+		q.valid_until,
+		q.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		q.lead_id,
+		IFNULL(l.company, "") as lead_company,
+		q.item_count,
+		q.sub_total,
+		q.tax_total,
+		q.discount_type,
+		q.discount_value,
+		q.shipping_fee,
+		q.tax_inclusive,
+		q.shipping_tax_id,
+		q.shipping_tax_amount,
+		q.total,
+		q.notes,
+		q.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		q.status
+		FROM s_quotes q
+		LEFT JOIN s_customers c
+		ON q.customer_id = c.customer_id
+		LEFT JOIN c_leads l
+		ON q.lead_id = l.lead_id
+		LEFT JOIN s_salesorders s
+		ON q.salesorder_id = s.salesorder_id
+		WHERE q.organization_id = ? AND q.quote_id = ? AND q.status > 0
+	`, organizationID, id)
+	return &quote, err
+}
+
+func (r *salesorderQuery) GetQuoteItemList(organizationID, quoteID string) (*[]QuoteItemResponse, error) {
+	var quoteItems []QuoteItemResponse
+	err := r.conn.Select(&quoteItems, `
+		SELECT
+		q.organization_id,
+		q.quote_id,
+		q.quote_item_id,
+		q.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		q.quantity,
+		q.rate,
+		q.tax_id,
+		q.tax_value,
+		q.tax_amount,
+		q.amount,
+		q.status
+		FROM s_quote_items q
+		LEFT JOIN i_items i
+		ON q.item_id = i.item_id
+		WHERE q.organization_id = ? AND q.quote_id = ? AND q.status > 0
+	`, organizationID, quoteID)
+	return &quoteItems, err
+}
+
+func (r *salesorderQuery) GetExpiredQuoteList(date string) (*[]QuoteResponse, error) {
+	var quotes []QuoteResponse
+	err := r.conn.Select(&quotes, `
+		SELECT
+		organization_id,
+		quote_id,
+		quote_number,
+		quote_date,
+		valid_until,
+		customer_id,
+		lead_id,
+		total,
+		salesorder_id,
+		status
+		FROM s_quotes
+		WHERE status = 2 AND valid_until < ?
+	`, date)
+	return &quotes, err
+}
+
+func (r *salesorderQuery) GetDropshipmentList(organizationID, salesorderID string) (*[]DropshipmentResponse, error) {
+	var dropshipments []DropshipmentResponse
+	err := r.conn.Select(&dropshipments, `
+		SELECT
+		d.organization_id,
+		d.dropshipment_id,
+		d.salesorder_id,
+		d.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		d.shipment_date,
+		d.carrier_name,
+		d.tracking_number,
+		d.notes,
+		d.status
+		FROM s_dropshipments d
+		LEFT JOIN p_purchaseorders p
+		ON d.purchaseorder_id = p.purchaseorder_id
+		WHERE d.organization_id = ? AND d.salesorder_id = ? AND d.status > 0
+		ORDER BY d.shipment_date
+	`, organizationID, salesorderID)
+	return &dropshipments, err
+}
+
+func (r *salesorderQuery) GetDropshipmentByID(organizationID, id string) (*DropshipmentResponse, error) {
+	var dropshipment DropshipmentResponse
+	err := r.conn.Get(&dropshipment, `
+		SELECT
+		d.organization_id,
+		d.dropshipment_id,
+		d.salesorder_id,
+		d.purchaseorder_id,
+		IFNULL(p.purchaseorder_number, "") as purchaseorder_number,
+		d.shipment_date,
+		d.carrier_name,
+		d.tracking_number,
+		d.notes,
+		d.status
+		FROM s_dropshipments d
+		LEFT JOIN p_purchaseorders p
+		ON d.purchaseorder_id = p.purchaseorder_id
+		WHERE d.organization_id = ? AND d.dropshipment_id = ? AND d.status > 0
+	`, organizationID, id)
+	return &dropshipment, err
+}
+
+func (r *salesorderQuery) GetDropshipmentItemList(organizationID, dropshipmentID string) (*[]DropshipmentItemResponse, error) {
+	var dropshipmentItems []DropshipmentItemResponse
+	err := r.conn.Select(&dropshipmentItems, `
+		SELECT
+		d.organization_id,
+		d.dropshipment_id,
+		d.dropshipment_item_id,
+		d.salesorder_item_id,
+		d.purchaseorder_item_id,
+		d.item_id,
+		IFNULL(i.name, "") as item_name,
+		IFNULL(i.sku, "") as sku,
+		d.quantity,
+		d.status
+		FROM s_dropshipment_items d
+		LEFT JOIN i_items i
+		ON d.item_id = i.item_id
+		WHERE d.organization_id = ? AND d.dropshipment_id = ? AND d.status > 0
+	`, organizationID, dropshipmentID)
+	return &dropshipmentItems, err
+}
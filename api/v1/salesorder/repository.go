@@ -31,6 +31,9 @@ func (r salesorderRepository) CreateSalesorderItem(info SalesorderItem) error {
 			salesorder_item_id,
 			salesorder_id,
 			item_id,
+			unit_id,
+			unit_quantity,
+			unit_rate,
 			quantity,
 			rate,
 			amount,
@@ -41,6 +44,8 @@ func (r salesorderRepository) CreateSalesorderItem(info SalesorderItem) error {
 			quantity_picked,
 			quantity_packed,
 			quantity_shipped,
+			drop_ship,
+			purchaseorder_id,
 			status,
 			created,
 			created_by,
@@ -48,25 +53,29 @@ func (r salesorderRepository) CreateSalesorderItem(info SalesorderItem) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.SalesorderItemID, info.SalesorderID, info.ItemID, info.Quantity, info.Rate, info.Amount, info.TaxID, info.TaxValue, info.TaxAmount, info.QuantityInvoiced, info.QuantityPicked, info.QuantityPacked, info.QuantityShipped, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.SalesorderItemID, info.SalesorderID, info.ItemID, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.Amount, info.TaxID, info.TaxValue, info.TaxAmount, info.QuantityInvoiced, info.QuantityPicked, info.QuantityPacked, info.QuantityShipped, info.DropShip, info.PurchaseorderID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
 func (r salesorderRepository) UpdateSalesorderItem(id string, info SalesorderItem) error {
 	_, err := r.tx.Exec(`
 		UPDATE s_salesorder_items set
+		unit_id = ?,
+		unit_quantity = ?,
+		unit_rate = ?,
 		quantity = ?,
 		rate = ?,
 		tax_id = ?,
 		tax_value = ?,
 		tax_amount = ?,
 		amount = ?,
+		drop_ship = ?,
 		status = ?,
 		updated = ?,
 		updated_by =?
 		WHERE salesorder_item_id = ?
-	`, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.DropShip, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -86,12 +95,18 @@ func (r salesorderRepository) CreateSalesorder(info Salesorder) error {
 			discount_value,
 			tax_total,
 			shipping_fee,
+			tax_inclusive,
+			shipping_tax_id,
+			shipping_tax_amount,
 			total,
 			notes,
 			invoice_status,
 			picking_status,
 			packing_status,
 			shipping_status,
+			approval_status,
+			billing_address,
+			shipping_address,
 			status,
 			created,
 			created_by,
@@ -99,8 +114,8 @@ func (r salesorderRepository) CreateSalesorder(info Salesorder) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.SalesorderID, info.SalesorderNumber, info.SalesorderDate, info.ExpectedShipmentDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.InvoiceStatus, info.PickingStatus, info.PackingStatus, info.ShippingStatus, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.SalesorderID, info.SalesorderNumber, info.SalesorderDate, info.ExpectedShipmentDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.InvoiceStatus, info.PickingStatus, info.PackingStatus, info.ShippingStatus, info.ApprovalStatus, info.BillingAddress, info.ShippingAddress, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -120,19 +135,33 @@ func (r *salesorderRepository) GetSalesorderByID(organizationID, salesorderID st
 		discount_type,
 		discount_value,
 		shipping_fee,
+		tax_inclusive,
+		shipping_tax_id,
+		shipping_tax_amount,
 		total,
 		notes,
 		invoice_status,
 		picking_status,
 		packing_status,
 		shipping_status,
+		approval_status,
+		billing_address,
+		shipping_address,
 		status
 		FROM s_salesorders WHERE organization_id = ? AND salesorder_id = ? AND status > 0 LIMIT 1
 	`, organizationID, salesorderID)
-	err := row.Scan(&res.SalesorderID, &res.OrganizationID, &res.SalesorderNumber, &res.SalesorderDate, &res.ExpectedShipmentDate, &res.CustomerID, &res.ItemCount, &res.TaxTotal, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.Total, &res.Notes, &res.InvoiceStatus, &res.PickingStatus, &res.PackingStatus, &res.ShippingStatus, &res.Status)
+	err := row.Scan(&res.SalesorderID, &res.OrganizationID, &res.SalesorderNumber, &res.SalesorderDate, &res.ExpectedShipmentDate, &res.CustomerID, &res.ItemCount, &res.TaxTotal, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.TaxInclusive, &res.ShippingTaxID, &res.ShippingTaxAmount, &res.Total, &res.Notes, &res.InvoiceStatus, &res.PickingStatus, &res.PackingStatus, &res.ShippingStatus, &res.ApprovalStatus, &res.BillingAddress, &res.ShippingAddress, &res.Status)
 	return &res, err
 }
 
+// GetSalesorderStatusForUpdate locks the sales order until the transaction ends
+func (r *salesorderRepository) GetSalesorderStatusForUpdate(organizationID, id string) (int, error) {
+	var status int
+	row := r.tx.QueryRow("SELECT status FROM s_salesorders WHERE organization_id = ? AND salesorder_id = ? AND status > 0 LIMIT 1 FOR UPDATE", organizationID, id)
+	err := row.Scan(&status)
+	return status, err
+}
+
 func (r *salesorderRepository) GetSalesorderItemByID(organizationID, salesorderID, itemID string) (*SalesorderItemResponse, error) {
 	var res SalesorderItemResponse
 	row := r.tx.QueryRow(`
@@ -153,13 +182,15 @@ func (r *salesorderRepository) GetSalesorderItemByID(organizationID, salesorderI
 		s.quantity_picked,
 		s.quantity_packed,
 		s.quantity_shipped,
+		s.drop_ship,
+		s.purchaseorder_id,
 		s.status
 		FROM s_salesorder_items s
 		LEFT JOIN i_items i
 		ON s.item_id = i.item_id
 		WHERE s.organization_id = ? AND s.salesorder_id = ? AND s.item_id = ? AND s.status > 0 LIMIT 1
 	`, organizationID, salesorderID, itemID)
-	err := row.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.DropShip, &res.PurchaseorderID, &res.Status)
 	return &res, err
 }
 
@@ -183,6 +214,8 @@ func (r *salesorderRepository) GetSalesorderItemList(organizationID, salesorderI
 		s.quantity_picked,
 		s.quantity_packed,
 		s.quantity_shipped,
+		s.drop_ship,
+		s.purchaseorder_id,
 		s.status
 		FROM s_salesorder_items s
 		LEFT JOIN i_items i
@@ -194,7 +227,7 @@ func (r *salesorderRepository) GetSalesorderItemList(organizationID, salesorderI
 	}
 	for rows.Next() {
 		var res SalesorderItemResponse
-		err = rows.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.Status)
+		err = rows.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.DropShip, &res.PurchaseorderID, &res.Status)
 		salesorders = append(salesorders, res)
 		if err != nil {
 			return nil, err
@@ -216,17 +249,22 @@ func (r *salesorderRepository) UpdateSalesorder(id string, info Salesorder) erro
 		discount_type = ?,
 		discount_value = ?,
 		shipping_fee = ?,
+		tax_inclusive = ?,
+		shipping_tax_id = ?,
+		shipping_tax_amount = ?,
 		total = ?,
 		notes = ?,
 		invoice_status = ?,
 		picking_status = ?,
 		packing_status = ?,
 		shipping_status = ?,
+		billing_address = ?,
+		shipping_address = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE salesorder_id = ?
-	`, info.SalesorderNumber, info.SalesorderDate, info.ExpectedShipmentDate, info.CustomerID, info.ItemCount, info.Subtotal, info.TaxTotal, info.DiscountType, info.DiscountValue, info.ShippingFee, info.Total, info.Notes, info.InvoiceStatus, info.PickingStatus, info.PackingStatus, info.ShippingStatus, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.SalesorderNumber, info.SalesorderDate, info.ExpectedShipmentDate, info.CustomerID, info.ItemCount, info.Subtotal, info.TaxTotal, info.DiscountType, info.DiscountValue, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.InvoiceStatus, info.PickingStatus, info.PackingStatus, info.ShippingStatus, info.BillingAddress, info.ShippingAddress, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -271,13 +309,15 @@ func (r *salesorderRepository) GetSalesorderItemByIDAll(organizationID, salesord
 		s.quantity_picked,
 		s.quantity_packed,
 		s.quantity_shipped,
+		s.drop_ship,
+		s.purchaseorder_id,
 		s.status
 		FROM s_salesorder_items s
 		LEFT JOIN i_items i
 		ON s.item_id = i.item_id
 		WHERE s.salesorder_item_id = ? AND s.organization_id = ? AND s.salesorder_id = ? LIMIT 1
 	`, salesorderItemID, organizationID, salesorderID)
-	err := row.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.QuantityInvoiced, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.DropShip, &res.PurchaseorderID, &res.Status)
 	return &res, err
 }
 
@@ -292,6 +332,17 @@ func (r *salesorderRepository) UpdateSalesorderStatus(id string, status int, byU
 	return err
 }
 
+func (r *salesorderRepository) UpdateSalesorderApprovalStatus(id string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_salesorders SET
+		approval_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE salesorder_id = ?
+	`, status, time.Now(), byUser, id)
+	return err
+}
+
 func (r *salesorderRepository) UpdateSalesorderPickingStatus(id string, status int, byUser string) error {
 	_, err := r.tx.Exec(`
 		Update s_salesorders SET
@@ -305,7 +356,7 @@ func (r *salesorderRepository) UpdateSalesorderPickingStatus(id string, status i
 
 func (r *salesorderRepository) CheckSOItem(salesorder_id, organizationID string) (bool, error) {
 	var existed int
-	row := r.tx.QueryRow("SELECT count(1) FROM s_salesorder_items WHERE organization_id = ? AND salesorder_id = ? AND status = -1  AND (quantity_invoiced > 0 OR quantity_picked > 0 OR quantity_packed > 0 OR quantity_shipped > 0)", organizationID, salesorder_id)
+	row := r.tx.QueryRow("SELECT count(1) FROM s_salesorder_items WHERE organization_id = ? AND salesorder_id = ? AND status = -1  AND (quantity_invoiced > 0 OR quantity_picked > 0 OR quantity_packed > 0 OR quantity_shipped > 0 OR purchaseorder_id != '')", organizationID, salesorder_id)
 	err := row.Scan(&existed)
 	if err != nil {
 		return true, err
@@ -847,7 +898,9 @@ func (r salesorderRepository) CreateShippingorder(info Shippingorder) error {
 			shippingorder_date,
 			carrier_id,
 			tracking_number,
+			tracking_status,
 			notes,
+			shipping_address,
 			status,
 			created,
 			created_by,
@@ -855,8 +908,8 @@ func (r salesorderRepository) CreateShippingorder(info Shippingorder) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.ShippingorderID, info.PackageID, info.ShippingorderNumber, info.ShippingorderDate, info.CarrierID, info.TrackingNumber, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ShippingorderID, info.PackageID, info.ShippingorderNumber, info.ShippingorderDate, info.CarrierID, info.TrackingNumber, info.TrackingStatus, info.Notes, info.ShippingAddress, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -872,7 +925,10 @@ func (r *salesorderRepository) GetShippingorderByID(shippingorderID, organizatio
 		s.shippingorder_date, 
 		s.carrier_id,
 		IFNULL(c.name, "") as carrier_name,
+		s.tracking_number,
+		s.tracking_status,
 		s.notes,
+		s.shipping_address,
 		s.status
 		FROM s_shippingorders s
 		LEFT JOIN s_packages p
@@ -881,7 +937,7 @@ func (r *salesorderRepository) GetShippingorderByID(shippingorderID, organizatio
 		ON s.carrier_id = c.carrier_id
 		WHERE s.organization_id = ? AND s.shippingorder_id = ? AND s.status > 0 LIMIT 1
 	`, organizationID, shippingorderID)
-	err := row.Scan(&res.OrganizationID, &res.ShippingorderID, &res.PackageID, &res.PackageNumber, &res.ShippingorderNumber, &res.ShippingorderDate, &res.CarrierID, &res.CarrierName, &res.Notes, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.ShippingorderID, &res.PackageID, &res.PackageNumber, &res.ShippingorderNumber, &res.ShippingorderDate, &res.CarrierID, &res.CarrierName, &res.TrackingNumber, &res.TrackingStatus, &res.Notes, &res.ShippingAddress, &res.Status)
 	return &res, err
 }
 
@@ -1003,6 +1059,28 @@ func (r *salesorderRepository) GetPickingorderLogList(organizationID, pickingord
 	return &pickingorderLogs, err
 }
 
+func (r *salesorderRepository) GetPickingorderItemByID(organizationID, pickingorderItemID string) (*PickingorderItemResponse, error) {
+	var res PickingorderItemResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		p.organization_id,
+		p.pickingorder_id,
+		p.salesorder_item_id,
+		p.pickingorder_item_id,
+		p.item_id,
+		i.name as item_name,
+		i.sku,
+		p.quantity,
+		p.status
+		FROM s_pickingorder_items p
+		LEFT JOIN i_items i
+		ON p.item_id = i.item_id
+		WHERE p.organization_id = ? AND p.pickingorder_item_id = ? AND p.status > 0 LIMIT 1
+	`, organizationID, pickingorderItemID)
+	err := row.Scan(&res.OrganizationID, &res.PickingorderID, &res.SalesorderItemID, &res.PickingorderItemID, &res.ItemID, &res.ItemName, &res.SKU, &res.Quantity, &res.Status)
+	return &res, err
+}
+
 func (r *salesorderRepository) DeletePickingorder(id, byUser string) error {
 	_, err := r.tx.Exec(`
 		Update s_pickingorders SET
@@ -1076,6 +1154,9 @@ func (r salesorderRepository) CreateInvoiceItem(info InvoiceItem) error {
 			invoice_item_id,
 			salesorder_item_id,
 			item_id,
+			unit_id,
+			unit_quantity,
+			unit_rate,
 			quantity,
 			rate,
 			tax_id,
@@ -1089,8 +1170,8 @@ func (r salesorderRepository) CreateInvoiceItem(info InvoiceItem) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.InvoiceID, info.InvoiceItemID, info.SalesorderItemID, info.ItemID, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.InvoiceID, info.InvoiceItemID, info.SalesorderItemID, info.ItemID, info.UnitID, info.UnitQuantity, info.UnitRate, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -1111,8 +1192,16 @@ func (r salesorderRepository) CreateInvoice(info Invoice) error {
 			discount_value,
 			tax_total,
 			shipping_fee,
+			tax_inclusive,
+			shipping_tax_id,
+			shipping_tax_amount,
 			total,
+			payment_term_id,
+			discount_date,
+			discount_percent,
 			notes,
+			billing_address,
+			shipping_address,
 			status,
 			created,
 			created_by,
@@ -1120,8 +1209,8 @@ func (r salesorderRepository) CreateInvoice(info Invoice) error {
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.InvoiceID, info.SalesorderID, info.InvoiceNumber, info.InvoiceDate, info.DueDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.InvoiceID, info.SalesorderID, info.InvoiceNumber, info.InvoiceDate, info.DueDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.PaymentTermID, info.DiscountDate, info.DiscountPercent, info.Notes, info.BillingAddress, info.ShippingAddress, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -1162,8 +1251,16 @@ func (r *salesorderRepository) GetInvoiceByID(organizationID, id string) (*Invoi
 		i.discount_value,
 		i.tax_total,
 		i.shipping_fee,
+		i.tax_inclusive,
+		i.shipping_tax_id,
+		i.shipping_tax_amount,
 		i.total,
+		i.payment_term_id,
+		i.discount_date,
+		i.discount_percent,
 		i.notes,
+		i.billing_address,
+		i.shipping_address,
 		i.status
 		FROM s_invoices i
 		LEFT JOIN s_salesorders s
@@ -1172,7 +1269,7 @@ func (r *salesorderRepository) GetInvoiceByID(organizationID, id string) (*Invoi
 		ON i.customer_id = c.customer_id
 		WHERE i.organization_id = ? AND i.invoice_id = ? AND s.status > 0  LIMIT 1
 	`, organizationID, id)
-	err := row.Scan(&res.OrganizationID, &res.SalesorderID, &res.SalesorderNumber, &res.InvoiceID, &res.InvoiceNumber, &res.InvoiceDate, &res.DueDate, &res.CustomerID, &res.CustomerName, &res.ItemCount, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.TaxTotal, &res.ShippingFee, &res.Total, &res.Notes, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.SalesorderID, &res.SalesorderNumber, &res.InvoiceID, &res.InvoiceNumber, &res.InvoiceDate, &res.DueDate, &res.CustomerID, &res.CustomerName, &res.ItemCount, &res.Subtotal, &res.DiscountType, &res.DiscountValue, &res.TaxTotal, &res.ShippingFee, &res.TaxInclusive, &res.ShippingTaxID, &res.ShippingTaxAmount, &res.Total, &res.PaymentTermID, &res.DiscountDate, &res.DiscountPercent, &res.Notes, &res.BillingAddress, &res.ShippingAddress, &res.Status)
 	return &res, err
 }
 
@@ -1271,13 +1368,19 @@ func (r salesorderRepository) UpdateInvoice(id string, info Invoice) error {
 		discount_value = ?,
 		tax_total = ?,
 		shipping_fee = ?,
+		tax_inclusive = ?,
+		shipping_tax_id = ?,
+		shipping_tax_amount = ?,
 		total = ?,
+		payment_term_id = ?,
+		discount_date = ?,
+		discount_percent = ?,
 		notes = ?,
 		status = ?,
 		updated = ?,
 		updated_by =?
 		WHERE invoice_id = ?
-	`, info.InvoiceNumber, info.InvoiceDate, info.DueDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.Total, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.InvoiceNumber, info.InvoiceDate, info.DueDate, info.CustomerID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.PaymentTermID, info.DiscountDate, info.DiscountPercent, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -1304,6 +1407,7 @@ func (r salesorderRepository) CreatePaymentReceived(info PaymentReceived) error
 			payment_received_date,
 			payment_method_id,
 			amount,
+			discount_amount,
 			notes,
 			status,
 			created,
@@ -1312,14 +1416,21 @@ func (r salesorderRepository) CreatePaymentReceived(info PaymentReceived) error
 			updated_by
 		)
 		VALUES
-		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.OrganizationID, info.InvoiceID, info.CustomerID, info.PaymentReceivedID, info.PaymentReceivedNumber, info.PaymentReceivedDate, info.PaymentMethodID, info.Amount, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.InvoiceID, info.CustomerID, info.PaymentReceivedID, info.PaymentReceivedNumber, info.PaymentReceivedDate, info.PaymentMethodID, info.Amount, info.DiscountAmount, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
 func (r *salesorderRepository) GetInvoicePaidCount(organizationID, invoiceID string) (float64, error) {
 	var sum float64
-	row := r.tx.QueryRow("SELECT IFNULL(SUM(amount), 0) FROM s_payment_receiveds WHERE organization_id = ? AND invoice_id = ? AND status > 0", organizationID, invoiceID)
+	row := r.tx.QueryRow("SELECT IFNULL(SUM(amount + discount_amount), 0) FROM s_payment_receiveds WHERE organization_id = ? AND invoice_id = ? AND status > 0", organizationID, invoiceID)
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+func (r *salesorderRepository) GetInvoiceDiscountTaken(organizationID, invoiceID, paymentReceivedID string) (float64, error) {
+	var sum float64
+	row := r.tx.QueryRow("SELECT IFNULL(SUM(discount_amount), 0) FROM s_payment_receiveds WHERE organization_id = ? AND invoice_id = ? AND payment_received_id != ? AND status > 0", organizationID, invoiceID, paymentReceivedID)
 	err := row.Scan(&sum)
 	return sum, err
 }
@@ -1350,6 +1461,7 @@ func (r *salesorderRepository) GetPaymentReceivedByID(organizationID, id string)
 		p.payment_method_id,
 		IFNULL(pm.name, "") as payment_method_name,
 		p.amount,
+		p.discount_amount,
 		p.notes,
 		p.status
 		FROM s_payment_receiveds p
@@ -1361,7 +1473,7 @@ func (r *salesorderRepository) GetPaymentReceivedByID(organizationID, id string)
 		ON p.payment_method_id = pm.payment_method_id
 		WHERE p.organization_id = ? AND p.payment_received_id = ? AND p.status > 0  LIMIT 1
 	`, organizationID, id)
-	err := row.Scan(&res.OrganizationID, &res.InvoiceID, &res.InvoiceNumber, &res.CustomerID, &res.CustomerName, &res.PaymentReceivedID, &res.PaymentReceivedNumber, &res.PaymentReceivedDate, &res.PaymentMethodID, &res.PaymentMethodName, &res.Amount, &res.Notes, &res.Status)
+	err := row.Scan(&res.OrganizationID, &res.InvoiceID, &res.InvoiceNumber, &res.CustomerID, &res.CustomerName, &res.PaymentReceivedID, &res.PaymentReceivedNumber, &res.PaymentReceivedDate, &res.PaymentMethodID, &res.PaymentMethodName, &res.Amount, &res.DiscountAmount, &res.Notes, &res.Status)
 	return &res, err
 }
 
@@ -1372,15 +1484,23 @@ func (r salesorderRepository) UpdatePaymentReceived(id string, info PaymentRecei
 		payment_received_date = ?,
 		payment_method_id = ?,
 		amount = ?,
+		discount_amount = ?,
 		notes = ?,
 		status = ?,
 		updated = ?,
 		updated_by =?
 		WHERE payment_received_id = ?
-	`, info.PaymentReceivedNumber, info.PaymentReceivedDate, info.PaymentMethodID, info.Amount, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.PaymentReceivedNumber, info.PaymentReceivedDate, info.PaymentMethodID, info.Amount, info.DiscountAmount, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
+func (r *salesorderRepository) GetPaymentReceivedMatchedCount(organizationID, paymentReceivedID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM b_bank_transaction_matches WHERE organization_id = ? AND payment_type = 1 AND payment_id = ? AND status > 0", organizationID, paymentReceivedID)
+	err := row.Scan(&count)
+	return count, err
+}
+
 func (r *salesorderRepository) DeletePaymentReceived(id, byUser string) error {
 	_, err := r.tx.Exec(`
 		Update s_payment_receiveds SET
@@ -1391,3 +1511,891 @@ func (r *salesorderRepository) DeletePaymentReceived(id, byUser string) error {
 	`, time.Now(), byUser, id)
 	return err
 }
+
+// recurring invoice
+
+func (r *salesorderRepository) CheckRecurringInvoiceConfict(recurringInvoiceID, organizationID, profileName string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_recurring_invoices WHERE organization_id = ? AND recurring_invoice_id != ? AND profile_name = ? AND status > 0 ", organizationID, recurringInvoiceID, profileName)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *salesorderRepository) CreateRecurringInvoice(info RecurringInvoice) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_recurring_invoices
+		(
+			organization_id,
+			recurring_invoice_id,
+			profile_name,
+			salesorder_id,
+			customer_id,
+			invoice_prefix,
+			frequency,
+			repeat_every,
+			start_date,
+			end_date,
+			occurrences,
+			generated_count,
+			next_date,
+			payment_term_id,
+			discount_type,
+			discount_value,
+			shipping_fee,
+			shipping_tax_id,
+			tax_inclusive,
+			notes,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringInvoiceID, info.ProfileName, info.SalesorderID, info.CustomerID, info.InvoicePrefix, info.Frequency, info.RepeatEvery, info.StartDate, info.EndDate, info.Occurrences, info.GeneratedCount, info.NextDate, info.PaymentTermID, info.DiscountType, info.DiscountValue, info.ShippingFee, info.ShippingTaxID, info.TaxInclusive, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) GetRecurringInvoiceByID(organizationID, id string) (*RecurringInvoiceResponse, error) {
+	var res RecurringInvoiceResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		r.organization_id,
+		r.recurring_invoice_id,
+		r.profile_name,
+		r.salesorder_id,
+		IFNULL(s.salesorder_number, "") as salesorder_number,
+		r.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		r.invoice_prefix,
+		r.frequency,
+		r.repeat_every,
+		r.start_date,
+		r.end_date,
+		r.occurrences,
+		r.generated_count,
+		r.next_date,
+		r.payment_term_id,
+		r.discount_type,
+		r.discount_value,
+		r.shipping_fee,
+		r.shipping_tax_id,
+		r.tax_inclusive,
+		r.notes,
+		r.status,
+		r.updated_by
+		FROM s_recurring_invoices r
+		LEFT JOIN s_salesorders s
+		ON r.salesorder_id = s.salesorder_id
+		LEFT JOIN s_customers c
+		ON r.customer_id = c.customer_id
+		WHERE r.organization_id = ? AND r.recurring_invoice_id = ? AND r.status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.RecurringInvoiceID, &res.ProfileName, &res.SalesorderID, &res.SalesorderNumber, &res.CustomerID, &res.CustomerName, &res.InvoicePrefix, &res.Frequency, &res.RepeatEvery, &res.StartDate, &res.EndDate, &res.Occurrences, &res.GeneratedCount, &res.NextDate, &res.PaymentTermID, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.ShippingTaxID, &res.TaxInclusive, &res.Notes, &res.Status, &res.UpdatedBy)
+	return &res, err
+}
+
+func (r *salesorderRepository) UpdateRecurringInvoice(id string, info RecurringInvoice) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_recurring_invoices SET
+		profile_name = ?,
+		salesorder_id = ?,
+		customer_id = ?,
+		invoice_prefix = ?,
+		frequency = ?,
+		repeat_every = ?,
+		start_date = ?,
+		end_date = ?,
+		occurrences = ?,
+		next_date = ?,
+		payment_term_id = ?,
+		discount_type = ?,
+		discount_value = ?,
+		shipping_fee = ?,
+		shipping_tax_id = ?,
+		tax_inclusive = ?,
+		notes = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_invoice_id = ?
+	`, info.ProfileName, info.SalesorderID, info.CustomerID, info.InvoicePrefix, info.Frequency, info.RepeatEvery, info.StartDate, info.EndDate, info.Occurrences, info.NextDate, info.PaymentTermID, info.DiscountType, info.DiscountValue, info.ShippingFee, info.ShippingTaxID, info.TaxInclusive, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *salesorderRepository) UpdateRecurringInvoiceSchedule(id, nextDate string, generatedCount, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_recurring_invoices SET
+		next_date = ?,
+		generated_count = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_invoice_id = ?
+	`, nextDate, generatedCount, status, time.Now(), byUser, id)
+	return err
+}
+
+func (r *salesorderRepository) DeleteRecurringInvoice(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_recurring_invoices SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_invoice_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	return r.DeleteRecurringInvoiceItem(id, byUser)
+}
+
+func (r *salesorderRepository) CreateRecurringInvoiceItem(info RecurringInvoiceItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_recurring_invoice_items
+		(
+			organization_id,
+			recurring_invoice_id,
+			recurring_invoice_item_id,
+			salesorder_item_id,
+			item_id,
+			quantity,
+			rate,
+			tax_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringInvoiceID, info.RecurringInvoiceItemID, info.SalesorderItemID, info.ItemID, info.Quantity, info.Rate, info.TaxID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) DeleteRecurringInvoiceItem(recurringInvoiceID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_recurring_invoice_items SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_invoice_id = ? AND status > 0
+	`, time.Now(), byUser, recurringInvoiceID)
+	return err
+}
+
+func (r *salesorderRepository) GetRecurringInvoiceRunByPeriod(organizationID, recurringInvoiceID, periodDate string) (*RecurringInvoiceRunResponse, error) {
+	var res RecurringInvoiceRunResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, recurring_invoice_id, recurring_invoice_run_id, period_date, invoice_id, message, status
+		FROM s_recurring_invoice_runs
+		WHERE organization_id = ? AND recurring_invoice_id = ? AND period_date = ? AND status > 0 LIMIT 1
+	`, organizationID, recurringInvoiceID, periodDate)
+	err := row.Scan(&res.OrganizationID, &res.RecurringInvoiceID, &res.RecurringInvoiceRunID, &res.PeriodDate, &res.InvoiceID, &res.Message, &res.Status)
+	return &res, err
+}
+
+func (r *salesorderRepository) CreateRecurringInvoiceRun(info RecurringInvoiceRun) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_recurring_invoice_runs
+		(
+			organization_id,
+			recurring_invoice_id,
+			recurring_invoice_run_id,
+			period_date,
+			invoice_id,
+			message,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.RecurringInvoiceID, info.RecurringInvoiceRunID, info.PeriodDate, info.InvoiceID, info.Message, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) UpdateRecurringInvoiceRun(id, invoiceID, message string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_recurring_invoice_runs SET
+		invoice_id = ?,
+		message = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE recurring_invoice_run_id = ?
+	`, invoiceID, message, status, time.Now(), byUser, id)
+	return err
+}
+
+func (r *salesorderRepository) GetInvoiceIDByNumber(organizationID, invoiceNumber string) (string, error) {
+	var invoiceID string
+	row := r.tx.QueryRow("SELECT invoice_id FROM s_invoices WHERE organization_id = ? AND invoice_number = ? AND status > 0 LIMIT 1", organizationID, invoiceNumber)
+	err := row.Scan(&invoiceID)
+	return invoiceID, err
+}
+
+//shipping label
+
+func (r *salesorderRepository) CreateShippingLabel(info ShippingLabel) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_shipping_labels
+		(
+			organization_id,
+			shipping_label_id,
+			shippingorder_id,
+			carrier_id,
+			service_code,
+			tracking_number,
+			format,
+			data,
+			cost,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ShippingLabelID, info.ShippingorderID, info.CarrierID, info.ServiceCode, info.TrackingNumber, info.Format, info.Data, info.Cost, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) GetActiveShippingLabel(organizationID, shippingorderID string) (*ShippingLabelResponse, error) {
+	var res ShippingLabelResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, shipping_label_id, shippingorder_id, carrier_id, service_code, tracking_number, format, cost, status
+		FROM s_shipping_labels
+		WHERE organization_id = ? AND shippingorder_id = ? AND status = 1
+		ORDER BY id DESC LIMIT 1
+	`, organizationID, shippingorderID)
+	err := row.Scan(&res.OrganizationID, &res.ShippingLabelID, &res.ShippingorderID, &res.CarrierID, &res.ServiceCode, &res.TrackingNumber, &res.Format, &res.Cost, &res.Status)
+	return &res, err
+}
+
+func (r *salesorderRepository) VoidShippingLabel(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_shipping_labels SET
+		status = 2,
+		updated = ?,
+		updated_by = ?
+		WHERE shipping_label_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *salesorderRepository) UpdateShippingorderTrackingNumber(id, trackingNumber, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_shippingorders SET
+		tracking_number = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE shippingorder_id = ?
+	`, trackingNumber, time.Now(), byUser, id)
+	return err
+}
+
+//tracking
+
+func (r *salesorderRepository) GetShippingorderByTrackingNumber(organizationID, carrierID, trackingNumber string) (*ShippingorderResponse, error) {
+	var shippingorderID string
+	row := r.tx.QueryRow(`SELECT shippingorder_id FROM s_shippingorders WHERE organization_id = ? AND carrier_id = ? AND tracking_number = ? AND status > 0 ORDER BY id DESC LIMIT 1`, organizationID, carrierID, trackingNumber)
+	err := row.Scan(&shippingorderID)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetShippingorderByID(shippingorderID, organizationID)
+}
+
+func (r *salesorderRepository) CheckTrackingEventExist(shippingorderID, eventTime string, trackingStatus int) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_shipping_tracking_events WHERE shippingorder_id = ? AND event_time = ? AND tracking_status = ? AND status > 0", shippingorderID, eventTime, trackingStatus)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *salesorderRepository) CreateShippingTrackingEvent(info ShippingTrackingEvent) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_shipping_tracking_events
+		(
+			organization_id,
+			tracking_event_id,
+			shippingorder_id,
+			tracking_number,
+			event_time,
+			tracking_status,
+			description,
+			location,
+			source,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.TrackingEventID, info.ShippingorderID, info.TrackingNumber, info.EventTime, info.TrackingStatus, info.Description, info.Location, info.Source, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) UpdateShippingorderTrackingStatus(id string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_shippingorders SET
+		tracking_status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE shippingorder_id = ?
+	`, status, time.Now(), byUser, id)
+	return err
+}
+
+// GetSalesorderUndeliveredCount counts the shipping orders of a sales order
+// that are not delivered yet
+func (r *salesorderRepository) GetSalesorderUndeliveredCount(organizationID, salesorderID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow(`
+		SELECT count(DISTINCT s.shippingorder_id)
+		FROM s_shippingorders s
+		INNER JOIN s_shippingorder_details d
+		ON s.shippingorder_id = d.shippingorder_id AND d.status > 0
+		INNER JOIN s_packages p
+		ON d.package_id = p.package_id
+		WHERE s.organization_id = ? AND p.salesorder_id = ? AND s.status > 0 AND s.tracking_status != 5
+	`, organizationID, salesorderID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+//credit
+
+// GetCustomerOpenInvoiceAmount returns what the customer still owes on the
+// invoices not fully paid
+func (r *salesorderRepository) GetCustomerOpenInvoiceAmount(organizationID, customerID string) (float64, error) {
+	var sum float64
+	row := r.tx.QueryRow(`
+		SELECT IFNULL(SUM(i.total - IFNULL((SELECT SUM(p.amount + p.discount_amount) FROM s_payment_receiveds p WHERE p.invoice_id = i.invoice_id AND p.status > 0), 0)), 0)
+		FROM s_invoices i
+		WHERE i.organization_id = ? AND i.customer_id = ? AND i.status > 0 AND i.status != 3
+	`, organizationID, customerID)
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+// GetCustomerUnbilledAmount returns the part of the confirmed sales orders not
+// invoiced yet, the sales order given is left out
+func (r *salesorderRepository) GetCustomerUnbilledAmount(organizationID, customerID, salesorderID string) (float64, error) {
+	var sum float64
+	row := r.tx.QueryRow(`
+		SELECT IFNULL(SUM(GREATEST(s.total - IFNULL((SELECT SUM(i.total) FROM s_invoices i WHERE i.salesorder_id = s.salesorder_id AND i.status > 0), 0), 0)), 0)
+		FROM s_salesorders s
+		WHERE s.organization_id = ? AND s.customer_id = ? AND s.salesorder_id != ? AND s.status = 2
+	`, organizationID, customerID, salesorderID)
+	err := row.Scan(&sum)
+	return sum, err
+}
+
+func (r *salesorderRepository) CreateSalesorderHold(info SalesorderHold) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_salesorder_holds
+		(
+			organization_id,
+			salesorder_hold_id,
+			salesorder_id,
+			customer_id,
+			reason,
+			credit_limit,
+			exposure,
+			order_total,
+			released_by,
+			released_at,
+			release_notes,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.SalesorderHoldID, info.SalesorderID, info.CustomerID, info.Reason, info.CreditLimit, info.Exposure, info.OrderTotal, info.ReleasedBy, info.ReleasedAt, info.ReleaseNotes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) GetActiveSalesorderHold(organizationID, salesorderID string) (*SalesorderHoldResponse, error) {
+	var res SalesorderHoldResponse
+	row := r.tx.QueryRow(`
+		SELECT organization_id, salesorder_hold_id, salesorder_id, customer_id, reason, credit_limit, exposure, order_total, status
+		FROM s_salesorder_holds
+		WHERE organization_id = ? AND salesorder_id = ? AND status = 1
+		ORDER BY id DESC LIMIT 1
+	`, organizationID, salesorderID)
+	err := row.Scan(&res.OrganizationID, &res.SalesorderHoldID, &res.SalesorderID, &res.CustomerID, &res.Reason, &res.CreditLimit, &res.Exposure, &res.OrderTotal, &res.Status)
+	return &res, err
+}
+
+func (r *salesorderRepository) ReleaseSalesorderHold(id, notes, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_salesorder_holds SET
+		released_by = ?,
+		released_at = ?,
+		release_notes = ?,
+		status = 2,
+		updated = ?,
+		updated_by = ?
+		WHERE salesorder_hold_id = ?
+	`, byUser, time.Now().Format("2006-01-02 15:04:05"), notes, time.Now(), byUser, id)
+	return err
+}
+
+func (r *salesorderRepository) DeleteSalesorderHold(salesorderID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_salesorder_holds SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE salesorder_id = ? AND status = 1
+	`, time.Now(), byUser, salesorderID)
+	return err
+}
+
+func (r *salesorderRepository) CheckQuoteNumberConfict(quoteID, organizationID, quoteNumber string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_quotes WHERE organization_id = ? AND quote_id != ? AND quote_number = ? AND status > 0 ", organizationID, quoteID, quoteNumber)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *salesorderRepository) CreateQuote(info Quote) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_quotes
+		(
+			organization_id,
+			quote_id,
+			quote_number,
+			quote_date,
+			valid_until,
+			customer_id,
+			lead_id,
+			item_count,
+			sub_total,
+			discount_type,
+			discount_value,
+			tax_total,
+			shipping_fee,
+			tax_inclusive,
+			shipping_tax_id,
+			shipping_tax_amount,
+			total,
+			notes,
+			salesorder_id,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.QuoteID, info.QuoteNumber, info.QuoteDate, info.ValidUntil, info.CustomerID, info.LeadID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.SalesorderID, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) GetQuoteByID(organizationID, id string) (*QuoteResponse, error) {
+	var res QuoteResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		q.organization_id,
+		q.quote_id,
+		q.quote_number,
+		q.quote_date,
+		q.valid_until,
+		q.customer_id,
+		IFNULL(c.name, "") as customer_name,
+		q.lead_id,
+		IFNULL(l.company, "") as lead_company,
+		q.item_count,
+		q.sub_total,
+		q.tax_total,
+		q.discount_type,
+		q.discount_value,
+		q.shipping_fee,
+		q.tax_inclusive,
+		q.shipping_tax_id,
+		q.shipping_tax_amount,
+		q.total,
+		q.notes,
+		q.salesorder_id,
+		q.status
+		FROM s_quotes q
+		LEFT JOIN s_customers c
+		ON q.customer_id = c.customer_id
+		LEFT JOIN c_leads l
+		ON q.lead_id = l.lead_id
+		WHERE q.organization_id = ? AND q.quote_id = ? AND q.status > 0 LIMIT 1
+	`, organizationID, id)
+	err := row.Scan(&res.OrganizationID, &res.QuoteID, &res.QuoteNumber, &res.QuoteDate, &res.ValidUntil, &res.CustomerID, &res.CustomerName, &res.LeadID, &res.LeadCompany, &res.ItemCount, &res.Subtotal, &res.TaxTotal, &res.DiscountType, &res.DiscountValue, &res.ShippingFee, &res.TaxInclusive, &res.ShippingTaxID, &res.ShippingTaxAmount, &res.Total, &res.Notes, &res.SalesorderID, &res.Status)
+	return &res, err
+}
+
+func (r *salesorderRepository) UpdateQuote(id string, info Quote) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_quotes SET
+		quote_number = ?,
+		quote_date = ?,
+		valid_until = ?,
+		customer_id = ?,
+		lead_id = ?,
+		item_count = ?,
+		sub_total = ?,
+		discount_type = ?,
+		discount_value = ?,
+		tax_total = ?,
+		shipping_fee = ?,
+		tax_inclusive = ?,
+		shipping_tax_id = ?,
+		shipping_tax_amount = ?,
+		total = ?,
+		notes = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE quote_id = ?
+	`, info.QuoteNumber, info.QuoteDate, info.ValidUntil, info.CustomerID, info.LeadID, info.ItemCount, info.Subtotal, info.DiscountType, info.DiscountValue, info.TaxTotal, info.ShippingFee, info.TaxInclusive, info.ShippingTaxID, info.ShippingTaxAmount, info.Total, info.Notes, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *salesorderRepository) UpdateQuoteStatus(id string, status int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_quotes SET
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE quote_id = ?
+	`, status, time.Now(), byUser, id)
+	return err
+}
+
+// GetQuoteStatusForUpdate locks the quote until the transaction ends
+func (r *salesorderRepository) GetQuoteStatusForUpdate(organizationID, id string) (int, error) {
+	var status int
+	row := r.tx.QueryRow("SELECT status FROM s_quotes WHERE organization_id = ? AND quote_id = ? AND status > 0 LIMIT 1 FOR UPDATE", organizationID, id)
+	err := row.Scan(&status)
+	return status, err
+}
+
+func (r *salesorderRepository) GetQuoteItemList(organizationID, quoteID string) (*[]QuoteItemResponse, error) {
+	var quoteItems []QuoteItemResponse
+	rows, err := r.tx.Query(`
+		SELECT
+		organization_id,
+		quote_id,
+		quote_item_id,
+		item_id,
+		quantity,
+		rate,
+		tax_id,
+		tax_value,
+		tax_amount,
+		amount,
+		status
+		FROM s_quote_items
+		WHERE organization_id = ? AND quote_id = ? AND status > 0
+	`, organizationID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res QuoteItemResponse
+		err = rows.Scan(&res.OrganizationID, &res.QuoteID, &res.QuoteItemID, &res.ItemID, &res.Quantity, &res.Rate, &res.TaxID, &res.TaxValue, &res.TaxAmount, &res.Amount, &res.Status)
+		if err != nil {
+			return nil, err
+		}
+		quoteItems = append(quoteItems, res)
+	}
+	return &quoteItems, rows.Err()
+}
+
+func (r *salesorderRepository) UpdateQuoteConverted(id, customerID, salesorderID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_quotes SET
+		customer_id = ?,
+		salesorder_id = ?,
+		status = 6,
+		updated = ?,
+		updated_by = ?
+		WHERE quote_id = ?
+	`, customerID, salesorderID, time.Now(), byUser, id)
+	return err
+}
+
+func (r *salesorderRepository) DeleteQuote(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_quotes SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE quote_id = ?
+	`, time.Now(), byUser, id)
+	if err != nil {
+		return err
+	}
+	return r.DeleteQuoteItem(id, byUser)
+}
+
+func (r *salesorderRepository) CreateQuoteItem(info QuoteItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_quote_items
+		(
+			organization_id,
+			quote_id,
+			quote_item_id,
+			item_id,
+			quantity,
+			rate,
+			tax_id,
+			tax_value,
+			tax_amount,
+			amount,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.QuoteID, info.QuoteItemID, info.ItemID, info.Quantity, info.Rate, info.TaxID, info.TaxValue, info.TaxAmount, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) DeleteQuoteItem(quoteID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_quote_items SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE quote_id = ? AND status > 0
+	`, time.Now(), byUser, quoteID)
+	return err
+}
+
+func (r *salesorderRepository) UpdateSalesorderItemPurchaseorder(salesorderItemID, purchaseorderID, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_salesorder_items SET
+		purchaseorder_id = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE salesorder_item_id = ?
+	`, purchaseorderID, time.Now(), byUser, salesorderItemID)
+	return err
+}
+
+func (r *salesorderRepository) GetDropshipSalesorderItem(organizationID, salesorderID, purchaseorderID, itemID string) (*SalesorderItemResponse, error) {
+	var res SalesorderItemResponse
+	row := r.tx.QueryRow(`
+		SELECT
+		organization_id,
+		salesorder_item_id,
+		salesorder_id,
+		item_id,
+		quantity,
+		quantity_picked,
+		quantity_packed,
+		quantity_shipped,
+		drop_ship,
+		purchaseorder_id,
+		status
+		FROM s_salesorder_items
+		WHERE organization_id = ? AND salesorder_id = ? AND purchaseorder_id = ? AND item_id = ? AND drop_ship = 1 AND status > 0 LIMIT 1
+	`, organizationID, salesorderID, purchaseorderID, itemID)
+	err := row.Scan(&res.OrganizationID, &res.SalesorderItemID, &res.SalesorderID, &res.ItemID, &res.Quantity, &res.QuantityPicked, &res.QuantityPacked, &res.QuantityShipped, &res.DropShip, &res.PurchaseorderID, &res.Status)
+	return &res, err
+}
+
+// ShipDropshipSalesorderItem counts the quantity as picked, packed and shipped
+// at once, drop-ship lines never go through the warehouse.
+func (r *salesorderRepository) ShipDropshipSalesorderItem(salesorderItemID string, quantity int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_salesorder_items SET
+		quantity_picked = quantity_picked + ?,
+		quantity_packed = quantity_packed + ?,
+		quantity_shipped = quantity_shipped + ?,
+		updated = ?,
+		updated_by = ?
+		WHERE salesorder_item_id = ?
+	`, quantity, quantity, quantity, time.Now(), byUser, salesorderItemID)
+	return err
+}
+
+func (r *salesorderRepository) CreateDropshipment(info Dropshipment) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_dropshipments
+		(
+			organization_id,
+			dropshipment_id,
+			salesorder_id,
+			purchaseorder_id,
+			shipment_date,
+			carrier_name,
+			tracking_number,
+			notes,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.DropshipmentID, info.SalesorderID, info.PurchaseorderID, info.ShipmentDate, info.CarrierName, info.TrackingNumber, info.Notes, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) CreateDropshipmentItem(info DropshipmentItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_dropshipment_items
+		(
+			organization_id,
+			dropshipment_id,
+			dropshipment_item_id,
+			salesorder_item_id,
+			purchaseorder_item_id,
+			item_id,
+			quantity,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.DropshipmentID, info.DropshipmentItemID, info.SalesorderItemID, info.PurchaseorderItemID, info.ItemID, info.Quantity, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+//forecast
+
+func (r *salesorderRepository) GetForecastSettingByID(organizationID, itemID string) (*ForecastSetting, error) {
+	var res ForecastSetting
+	row := r.tx.QueryRow(`
+		SELECT organization_id, item_id, method, window_days, alpha, beta, season_length, safety_stock, status
+		FROM s_forecast_settings
+		WHERE organization_id = ? AND item_id = ? AND status > 0 LIMIT 1
+	`, organizationID, itemID)
+	err := row.Scan(&res.OrganizationID, &res.ItemID, &res.Method, &res.Window, &res.Alpha, &res.Beta, &res.SeasonLength, &res.SafetyStock, &res.Status)
+	return &res, err
+}
+
+func (r *salesorderRepository) CreateForecastSetting(info ForecastSetting) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_forecast_settings
+		(
+			organization_id,
+			item_id,
+			method,
+			window_days,
+			alpha,
+			beta,
+			season_length,
+			safety_stock,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ItemID, info.Method, info.Window, info.Alpha, info.Beta, info.SeasonLength, info.SafetyStock, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *salesorderRepository) UpdateForecastSetting(info ForecastSetting) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_forecast_settings SET
+		method = ?,
+		window_days = ?,
+		alpha = ?,
+		beta = ?,
+		season_length = ?,
+		safety_stock = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND item_id = ? AND status > 0
+	`, info.Method, info.Window, info.Alpha, info.Beta, info.SeasonLength, info.SafetyStock, info.Updated, info.UpdatedBy, info.OrganizationID, info.ItemID)
+	return err
+}
+
+func (r *salesorderRepository) CreateForecast(info Forecast) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_forecasts
+		(
+			organization_id,
+			forecast_id,
+			item_id,
+			method,
+			forecast_date,
+			period_end,
+			history_days,
+			horizon_days,
+			lead_time,
+			daily_demand,
+			forecast_quantity,
+			safety_stock,
+			stock_available,
+			open_quantity,
+			suggested_quantity,
+			actual_quantity,
+			evaluated,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.ForecastID, info.ItemID, info.Method, info.ForecastDate, info.PeriodEnd, info.HistoryDays, info.HorizonDays, info.LeadTime, info.DailyDemand, info.ForecastQuantity, info.SafetyStock, info.StockAvailable, info.OpenQuantity, info.SuggestedQuantity, info.ActualQuantity, info.Evaluated, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+// DeleteForecastByDate removes an earlier run of the same day so the accuracy
+// report counts each item once per forecast date
+func (r *salesorderRepository) DeleteForecastByDate(organizationID, itemID, forecastDate, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_forecasts SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND item_id = ? AND forecast_date = ? AND status > 0
+	`, time.Now(), byUser, organizationID, itemID, forecastDate)
+	return err
+}
+
+func (r *salesorderRepository) UpdateForecastActual(forecastID string, actualQuantity int, byUser string) error {
+	_, err := r.tx.Exec(`
+		UPDATE s_forecasts SET
+		actual_quantity = ?,
+		evaluated = 1,
+		updated = ?,
+		updated_by = ?
+		WHERE forecast_id = ?
+	`, actualQuantity, time.Now(), byUser, forecastID)
+	return err
+}
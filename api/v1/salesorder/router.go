@@ -2,6 +2,10 @@ package salesorder
 
 import "github.com/gin-gonic/gin"
 
+func Routers(g *gin.RouterGroup) {
+	g.POST("/webhooks/carriers/:id", ReceiveTrackingWebhook)
+}
+
 func AuthRouter(g *gin.RouterGroup) {
 	g.POST("/salesorders", NewSalesorder)
 	g.GET("/salesorders", GetSalesorderList)
@@ -10,6 +14,21 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.DELETE("/salesorders/:id", DeleteSalesorder)
 	g.GET("/salesorders/:id/items", GetSalesorderItemList)
 	g.POST("/salesorders/:id/confirmed", ConfirmSalesorder)
+	g.POST("/salesorders/:id/approval", ApproveSalesorder)
+	g.POST("/salesorders/:id/release", ReleaseSalesorderHold)
+	g.GET("/salesorderholds", GetSalesorderHoldList)
+	g.GET("/customers/:id/credit", GetCustomerCredit)
+
+	g.POST("/quotes", NewQuote)
+	g.GET("/quotes", GetQuoteList)
+	g.PUT("/quotes/:id", UpdateQuote)
+	g.GET("/quotes/:id", GetQuoteByID)
+	g.DELETE("/quotes/:id", DeleteQuote)
+	g.GET("/quotes/:id/items", GetQuoteItemList)
+	g.POST("/quotes/:id/sent", SendQuote)
+	g.POST("/quotes/:id/accepted", AcceptQuote)
+	g.POST("/quotes/:id/declined", DeclineQuote)
+	g.POST("/quotes/:id/convert", ConvertQuote)
 
 	g.POST("/salesorders/:id/pickings", NewPickingorder)
 	g.POST("/pickingorders", BatchPickingorder)
@@ -21,25 +40,47 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.POST("/pickingorders/:id/picked", MarkPickingorderPicked)
 	g.POST("/pickingorders/:id/unpicked", MarkPickingorderUnPicked)
 	g.DELETE("/pickingorders/:id", DeletePickingorder)
+	g.GET("/pickingorders/:id/pdf", GetPickingorderPDF)
 
 	g.POST("/salesorders/:id/packages", NewPackage)
 	g.GET("/packages", GetPackageList)
 	g.GET("/packages/:id/items", GetPackageItemList)
 	g.DELETE("/packages/:id", DeletePackage)
+	g.GET("/packages/:id/pdf", GetPackagePDF)
 
 	g.POST("/shippingorders", BatchShippingorder)
 	g.GET("/shippingorders", GetShippingorderList)
 	g.GET("/shippingorders/:id/items", GetShippingorderItemList)
 	g.GET("/shippingorders/:id/details", GetShippingorderDetailList)
 	g.DELETE("/shippingorders/:id", DeleteShippingorder)
+	g.GET("/shippingorders/:id/pdf", GetShippingorderPDF)
+	g.GET("/shippingorders/:id/labels", GetShippingLabelList)
+	g.POST("/shippingorders/:id/label", NewShippingLabel)
+	g.GET("/shippingorders/:id/label", GetShippingLabel)
+	g.DELETE("/shippingorders/:id/label", VoidShippingLabel)
+	g.GET("/shippingorders/:id/tracking", GetShippingorderTracking)
+	g.GET("/shippingorders/:id/trackingevents", GetShippingTrackingEventList)
+	g.POST("/shippingrates", GetShippingRates)
+
+	g.POST("/salesorders/:id/dropship", NewDropshipPurchaseorders)
+	g.POST("/salesorders/:id/dropshipments", NewDropshipment)
+	g.GET("/salesorders/:id/dropshipments", GetDropshipmentList)
+	g.GET("/dropshipments/:id/items", GetDropshipmentItemList)
 
 	g.GET("/requisitions", GetRequisitionList)
+	g.POST("/forecasts", NewForecast)
+	g.GET("/forecasts", GetForecastList)
+	g.GET("/forecasts/accuracy", GetForecastAccuracyList)
+	g.GET("/forecastsettings", GetForecastSettingList)
+	g.GET("/forecastsettings/:id", GetForecastSettingByID)
+	g.PUT("/forecastsettings/:id", UpdateForecastSetting)
 
 	g.POST("/salesorders/:id/invoices", NewInvoice)
 	g.GET("/invoices", GetInvoiceList)
 	g.GET("/invoices/:id/items", GetInvoiceItemList)
 	g.PUT("/invoices/:id", UpdateInvoice)
 	g.DELETE("/invoices/:id", DeleteInvoice)
+	g.GET("/invoices/:id/pdf", GetInvoicePDF)
 
 	g.POST("/invoices/:id/payments", NewPayment)
 	g.GET("/invoices/:id/paid", GeInvoicePaymentReceived)
@@ -47,4 +88,13 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/paymentreceiveds/:id", UpdatePayment)
 	g.DELETE("/paymentreceiveds/:id", DeletePayment)
 
+	g.POST("/recurringinvoices", NewRecurringInvoice)
+	g.GET("/recurringinvoices", GetRecurringInvoiceList)
+	g.PUT("/recurringinvoices/:id", UpdateRecurringInvoice)
+	g.GET("/recurringinvoices/:id", GetRecurringInvoiceByID)
+	g.DELETE("/recurringinvoices/:id", DeleteRecurringInvoice)
+	g.GET("/recurringinvoices/:id/items", GetRecurringInvoiceItemList)
+	g.GET("/recurringinvoices/:id/runs", GetRecurringInvoiceRunList)
+	g.POST("/recurringinvoices/:id/run", RunRecurringInvoice)
+
 }
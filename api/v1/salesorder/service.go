@@ -1,15 +1,22 @@
 package salesorder
 
 import (
+	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"go-api/api/v1/common"
+	"go-api/api/v1/crm"
 	"go-api/api/v1/item"
+	"go-api/api/v1/purchaseorder"
 	"go-api/api/v1/setting"
 	"go-api/api/v1/warehouse"
+	"go-api/core/carrier"
 	"go-api/core/database"
 	"go-api/core/queue"
+	"go-api/service"
+	"math"
 	"strings"
 	"time"
 
@@ -23,65 +30,103 @@ func NewSalesorderService() *salesorderService {
 	return &salesorderService{}
 }
 
-func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
-	db := database.WDB()
-	tx, err := db.Begin()
-	if err != nil {
-		msg := "begin transaction error"
-		return nil, errors.New(msg)
+// createSalesorder prices, taxes and saves a draft sales order within the
+// given transaction
+func (s *salesorderService) createSalesorder(tx *sql.Tx, info SalesorderNew) (string, error) {
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
 	}
-	defer tx.Rollback()
 	repo := NewSalesorderRepository(tx)
 	isConflict, err := repo.CheckSONumberConfict("", info.OrganizationID, info.SalesorderNumber)
 	if err != nil {
 		msg := "check conflict error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "salesorder number exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	soID := "so-" + xid.New().String()
 	settingService := setting.NewSettingService()
-	_, err = settingService.GetCustomerByID(info.OrganizationID, info.CustomerID)
+	customer, err := settingService.GetCustomerByID(info.OrganizationID, info.CustomerID)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	settingRepo := setting.NewSettingRepository(tx)
+	billingAddress, err := settingRepo.GetCustomerAddressSnapshot(info.OrganizationID, info.CustomerID, info.BillingAddressID, 1)
+	if err != nil {
+		return "", err
+	}
+	shippingAddress, err := settingRepo.GetCustomerAddressSnapshot(info.OrganizationID, info.CustomerID, info.ShippingAddressID, 2)
+	if err != nil {
+		return "", err
 	}
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemService := item.NewItemService()
 	for _, item := range info.Items {
-		_, err = itemService.GetItemByID(info.OrganizationID, item.ItemID)
+		itemInfo, err := itemService.GetItemByID(info.OrganizationID, item.ItemID)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		taxValue := 0.0
-		if item.TaxID != "" {
-			tax, err := settingService.GetTaxByID(info.OrganizationID, item.TaxID)
+		if item.DropShip == 0 {
+			item.DropShip = 2
+		}
+		if item.DropShip == 1 && itemInfo.DefaultVendorID == "" {
+			msg := "drop-ship item " + itemInfo.SKU + " has no default vendor"
+			return "", errors.New(msg)
+		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, item.ItemID, item.UnitID)
+		if err != nil {
+			return "", err
+		}
+		if item.UnitID == "" {
+			item.UnitID = itemInfo.UnitID
+		}
+		quantity := item.Quantity * factor
+		if item.Rate == 0 {
+			price, err := itemService.GetItemPrice(info.OrganizationID, customer.PriceListID, item.ItemID, quantity, info.SalesorderDate)
 			if err != nil {
-				return nil, err
+				return "", err
 			}
-			taxValue = tax.TaxValue
+			item.Rate = price.Rate * float64(factor)
+		}
+		taxID := item.TaxID
+		if customer.TaxExempt == 1 {
+			taxID = ""
+		}
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = taxID
+		taxInfo.Amount = item.Rate * float64(item.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return "", err
 		}
-		itemCount += item.Quantity
-		itemTotal += item.Rate * float64(item.Quantity)
-		taxTotal += item.Rate * float64(item.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 		var soItem SalesorderItem
 		soItem.OrganizationID = info.OrganizationID
 		soItem.SalesorderID = soID
 		soItem.SalesorderItemID = "soi-" + xid.New().String()
 		soItem.ItemID = item.ItemID
-		soItem.Quantity = item.Quantity
-		soItem.Rate = item.Rate
-		soItem.TaxID = item.TaxID
-		soItem.TaxValue = taxValue
-		soItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
-		soItem.Amount = float64(item.Quantity) * item.Rate
+		soItem.UnitID = item.UnitID
+		soItem.UnitQuantity = item.Quantity
+		soItem.UnitRate = item.Rate
+		soItem.Quantity = quantity
+		soItem.Rate = item.Rate / float64(factor)
+		soItem.TaxID = taxID
+		soItem.TaxValue = tax.TaxValue
+		soItem.TaxAmount = tax.TaxAmount
+		soItem.Amount = tax.TaxableAmount
 		soItem.QuantityInvoiced = 0
 		soItem.QuantityPicked = 0
 		soItem.QuantityPacked = 0
 		soItem.QuantityShipped = 0
+		soItem.DropShip = item.DropShip
 		soItem.Status = 1
 		soItem.Created = time.Now()
 		soItem.CreatedBy = info.Email
@@ -91,9 +136,22 @@ func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
 		err = repo.CreateSalesorderItem(soItem)
 		if err != nil {
 			msg := "create salesorder item error: "
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
 	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	if customer.TaxExempt == 1 {
+		shippingTaxInfo.TaxID = ""
+	}
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return "", err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
 	var salesorder Salesorder
 	salesorder.SalesorderID = soID
 	salesorder.OrganizationID = info.OrganizationID
@@ -107,20 +165,23 @@ func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
 	salesorder.DiscountType = info.DiscountType
 	salesorder.DiscountValue = info.DiscountValue
 	salesorder.ShippingFee = info.ShippingFee
+	salesorder.TaxInclusive = info.TaxInclusive
+	salesorder.ShippingTaxID = shippingTaxInfo.TaxID
+	salesorder.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
-		salesorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		salesorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
-			return nil, errors.New(msg)
+			return "", errors.New(msg)
 		}
-		salesorder.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		salesorder.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		salesorder.Total = itemTotal + info.ShippingFee + taxTotal
+		salesorder.Total = itemTotal + shippingTotal + taxTotal
 	}
 	salesorder.Notes = info.Notes
 	salesorder.Status = 1         //Draft
@@ -128,6 +189,9 @@ func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
 	salesorder.PickingStatus = 1  //not picked
 	salesorder.PackingStatus = 1  //not packed
 	salesorder.ShippingStatus = 1 //not shipped
+	salesorder.ApprovalStatus = 1 //no approval
+	salesorder.BillingAddress = *billingAddress
+	salesorder.ShippingAddress = *shippingAddress
 	salesorder.Created = time.Now()
 	salesorder.CreatedBy = info.Email
 	salesorder.Updated = time.Now()
@@ -136,8 +200,23 @@ func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
 	err = repo.CreateSalesorder(salesorder)
 	if err != nil {
 		msg := "create salesorder error: "
+		return "", errors.New(msg)
+	}
+	return soID, nil
+}
+
+func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
 		return nil, errors.New(msg)
 	}
+	defer tx.Rollback()
+	soID, err := s.createSalesorder(tx, info)
+	if err != nil {
+		return nil, err
+	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "salesorder"
 	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
@@ -154,7 +233,7 @@ func (s *salesorderService) NewSalesorder(info SalesorderNew) (*string, error) {
 		return nil, errors.New(msg)
 	}
 	tx.Commit()
-	return &salesorder.SalesorderID, err
+	return &soID, err
 }
 
 func (s *salesorderService) GetSalesorderList(filter SalesorderFilter) (int, *[]SalesorderResponse, error) {
@@ -178,6 +257,9 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 		return nil, err
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewSalesorderRepository(tx)
 	isConflict, err := repo.CheckSONumberConfict(salesorderID, info.OrganizationID, info.SalesorderNumber)
 	if err != nil {
@@ -189,7 +271,7 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 		return nil, errors.New(msg)
 	}
 	settingService := setting.NewSettingService()
-	_, err = settingService.GetCustomerByID(info.OrganizationID, info.CustomerID)
+	customer, err := settingService.GetCustomerByID(info.OrganizationID, info.CustomerID)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +280,35 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 		msg := "Salesorder not exist"
 		return nil, errors.New(msg)
 	}
+	if oldSalesorder.ApprovalStatus == 2 {
+		msg := "Salesorder pending approval can not be updated"
+		return nil, errors.New(msg)
+	}
+	// the saved addresses are kept unless the customer or the chosen address
+	// changes, so edits to the address book don't rewrite the order
+	settingRepo := setting.NewSettingRepository(tx)
+	billingAddress := &oldSalesorder.BillingAddress
+	if info.CustomerID != oldSalesorder.CustomerID || (info.BillingAddressID != "" && info.BillingAddressID != billingAddress.AddressID) {
+		billingAddress, err = settingRepo.GetCustomerAddressSnapshot(info.OrganizationID, info.CustomerID, info.BillingAddressID, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	shippingAddress := &oldSalesorder.ShippingAddress
+	if info.CustomerID != oldSalesorder.CustomerID || (info.ShippingAddressID != "" && info.ShippingAddressID != shippingAddress.AddressID) {
+		shippingAddress, err = settingRepo.GetCustomerAddressSnapshot(info.OrganizationID, info.CustomerID, info.ShippingAddressID, 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if oldSalesorder.Status == 1 && oldSalesorder.ApprovalStatus != 1 {
+		// a changed draft has to be approved again
+		err = repo.UpdateSalesorderApprovalStatus(salesorderID, 1, info.User)
+		if err != nil {
+			msg := "update salesorder approval status error"
+			return nil, errors.New(msg)
+		}
+	}
 	err = repo.DeleteSalesorder(salesorderID, info.User)
 	if err != nil {
 		msg := "Salesorder Update error"
@@ -212,17 +323,44 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 	taxTotal := 0.0
 	itemService := item.NewItemService()
 	for _, item := range info.Items {
-		_, err = itemService.GetItemByID(info.OrganizationID, item.ItemID)
+		itemInfo, err := itemService.GetItemByID(info.OrganizationID, item.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if item.DropShip == 0 {
+			item.DropShip = 2
+		}
+		if item.DropShip == 1 && itemInfo.DefaultVendorID == "" {
+			msg := "drop-ship item " + itemInfo.SKU + " has no default vendor"
+			return nil, errors.New(msg)
+		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, item.ItemID, item.UnitID)
 		if err != nil {
 			return nil, err
 		}
-		taxValue := 0.0
-		if item.TaxID != "" {
-			tax, err := settingService.GetTaxByID(info.OrganizationID, item.TaxID)
+		if item.UnitID == "" {
+			item.UnitID = itemInfo.UnitID
+		}
+		quantity := item.Quantity * factor
+		if item.Rate == 0 {
+			price, err := itemService.GetItemPrice(info.OrganizationID, customer.PriceListID, item.ItemID, quantity, info.SalesorderDate)
 			if err != nil {
 				return nil, err
 			}
-			taxValue = tax.TaxValue
+			item.Rate = price.Rate * float64(factor)
+		}
+		taxID := item.TaxID
+		if customer.TaxExempt == 1 {
+			taxID = ""
+		}
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = taxID
+		taxInfo.Amount = item.Rate * float64(item.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
 		}
 		if item.SalesorderItemID != "" {
 			oldItem, err := repo.GetSalesorderItemByIDAll(info.OrganizationID, salesorderID, item.SalesorderItemID)
@@ -230,33 +368,45 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 				msg := "Salesorder Item not exist"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityInvoiced > item.Quantity {
+			if oldItem.QuantityInvoiced > quantity {
 				msg := "can not set quantity lower than quantity invoiced"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityPicked > item.Quantity {
+			if oldItem.QuantityPicked > quantity {
 				msg := "can not set quantity lower than quantity picked"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityPacked > item.Quantity {
+			if oldItem.QuantityPacked > quantity {
 				msg := "can not set quantity lower than quantity packed"
 				return nil, errors.New(msg)
 			}
-			if oldItem.QuantityShipped > item.Quantity {
+			if oldItem.QuantityShipped > quantity {
 				msg := "can not set quantity lower than quantity shipped"
 				return nil, errors.New(msg)
 			}
+			if oldItem.PurchaseorderID != "" && (oldItem.Quantity != quantity || oldItem.DropShip != item.DropShip) {
+				msg := "drop-ship item already ordered from vendor can not be changed"
+				return nil, errors.New(msg)
+			}
+			if oldItem.DropShip != item.DropShip && oldItem.QuantityPicked > 0 {
+				msg := "picked item can not be changed to drop-ship"
+				return nil, errors.New(msg)
+			}
 			quantityInvoiced += oldItem.QuantityInvoiced
 			quantityPicked += oldItem.QuantityPicked
 			quantityPacked += oldItem.QuantityPacked
 			quantityShipped += oldItem.QuantityShipped
 			var soItem SalesorderItem
-			soItem.Quantity = item.Quantity
-			soItem.Rate = item.Rate
-			soItem.TaxID = item.TaxID
-			soItem.TaxValue = taxValue
-			soItem.Amount = float64(item.Quantity) * item.Rate
-			soItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
+			soItem.UnitID = item.UnitID
+			soItem.UnitQuantity = item.Quantity
+			soItem.UnitRate = item.Rate
+			soItem.Quantity = quantity
+			soItem.Rate = item.Rate / float64(factor)
+			soItem.TaxID = taxID
+			soItem.TaxValue = tax.TaxValue
+			soItem.Amount = tax.TaxableAmount
+			soItem.TaxAmount = tax.TaxAmount
+			soItem.DropShip = item.DropShip
 			soItem.Status = 1
 			soItem.Updated = time.Now()
 			soItem.UpdatedBy = info.Email
@@ -271,16 +421,20 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 			soItem.SalesorderID = salesorderID
 			soItem.SalesorderItemID = "soi-" + xid.New().String()
 			soItem.ItemID = item.ItemID
-			soItem.Quantity = item.Quantity
-			soItem.Rate = item.Rate
-			soItem.TaxID = item.TaxID
-			soItem.TaxValue = taxValue
-			soItem.Amount = float64(item.Quantity) * item.Rate
-			soItem.TaxAmount = float64(item.Quantity) * item.Rate * taxValue / 100
+			soItem.UnitID = item.UnitID
+			soItem.UnitQuantity = item.Quantity
+			soItem.UnitRate = item.Rate
+			soItem.Quantity = quantity
+			soItem.Rate = item.Rate / float64(factor)
+			soItem.TaxID = taxID
+			soItem.TaxValue = tax.TaxValue
+			soItem.Amount = tax.TaxableAmount
+			soItem.TaxAmount = tax.TaxAmount
 			soItem.QuantityInvoiced = 0
 			soItem.QuantityPicked = 0
 			soItem.QuantityPacked = 0
 			soItem.QuantityShipped = 0
+			soItem.DropShip = item.DropShip
 			soItem.Status = 1
 			soItem.Created = time.Now()
 			soItem.CreatedBy = info.Email
@@ -292,9 +446,9 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 				return nil, errors.New(msg)
 			}
 		}
-		itemCount += item.Quantity
-		itemTotal += item.Rate * float64(item.Quantity)
-		taxTotal += item.Rate * float64(item.Quantity) * taxValue / 100
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 	}
 	itemDeletedError, err := repo.CheckSOItem(salesorderID, info.OrganizationID)
 	if err != nil {
@@ -305,6 +459,19 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 		msg := "item invoiced or picked or packed or shipped can not be delete"
 		return nil, errors.New(msg)
 	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	if customer.TaxExempt == 1 {
+		shippingTaxInfo.TaxID = ""
+	}
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
 	var salesorder Salesorder
 	salesorder.SalesorderNumber = info.SalesorderNumber
 	salesorder.SalesorderDate = info.SalesorderDate
@@ -316,20 +483,23 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 	salesorder.DiscountType = info.DiscountType
 	salesorder.DiscountValue = info.DiscountValue
 	salesorder.ShippingFee = info.ShippingFee
+	salesorder.TaxInclusive = info.TaxInclusive
+	salesorder.ShippingTaxID = shippingTaxInfo.TaxID
+	salesorder.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		salesorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		salesorder.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		salesorder.Total = itemTotal + taxTotal - info.DiscountValue + info.ShippingFee
+		salesorder.Total = itemTotal + taxTotal - info.DiscountValue + shippingTotal
 	} else {
-		salesorder.Total = itemTotal + taxTotal + info.ShippingFee
+		salesorder.Total = itemTotal + taxTotal + shippingTotal
 	}
 	salesorder.Notes = info.Notes
 	if quantityInvoiced > 0 {
@@ -362,13 +532,16 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 	if quantityShipped > 0 {
 		if quantityPicked == itemCount {
 			salesorder.ShippingStatus = 3
+			if oldSalesorder.ShippingStatus == 4 {
+				salesorder.ShippingStatus = 4 //delivered
+			}
 		} else {
 			salesorder.ShippingStatus = 2
 		}
 	} else {
 		salesorder.ShippingStatus = 1
 	}
-	if salesorder.InvoiceStatus == 3 && salesorder.PickingStatus == 3 && salesorder.PackingStatus == 3 && salesorder.ShippingStatus == 3 {
+	if salesorder.InvoiceStatus == 3 && salesorder.PickingStatus == 3 && salesorder.PackingStatus == 3 && salesorder.ShippingStatus >= 3 {
 		salesorder.Status = 3 //CLOSE
 	} else {
 		if oldSalesorder.Status == 3 {
@@ -377,6 +550,8 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 			salesorder.Status = oldSalesorder.Status
 		}
 	}
+	salesorder.BillingAddress = *billingAddress
+	salesorder.ShippingAddress = *shippingAddress
 	salesorder.Updated = time.Now()
 	salesorder.UpdatedBy = info.Email
 
@@ -385,12 +560,22 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 		msg := "update salesorder error: "
 		return nil, errors.New(msg)
 	}
+	description := "Sales Order Updated"
+	if salesorder.Status == 2 && salesorder.Total > oldSalesorder.Total {
+		holdDescription, err := s.recheckRaisedSalesorder(repo, salesorderID, info)
+		if err != nil {
+			return nil, err
+		}
+		if holdDescription != "" {
+			description = holdDescription
+		}
+	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "salesorder"
 	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
 	newEvent.HistoryBy = info.User
 	newEvent.ReferenceID = salesorderID
-	newEvent.Description = "Sales Order Updated"
+	newEvent.Description = description
 	newEvent.OrganizationID = info.OrganizationID
 	newEvent.Email = info.Email
 	rabbit, _ := queue.GetConn()
@@ -404,6 +589,71 @@ func (s *salesorderService) UpdateSalesorder(salesorderID string, info Salesorde
 	return &salesorderID, err
 }
 
+// recheckRaisedSalesorder runs the approval rules and the credit check again
+// for a confirmed order whose total went up. The order goes back on hold when
+// either one stops it, and the hold description is returned for the history.
+func (s *salesorderService) recheckRaisedSalesorder(repo *salesorderRepository, salesorderID string, info SalesorderNew) (string, error) {
+	salesorder, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "Salesorder not exist"
+		return "", errors.New(msg)
+	}
+	var approvalInfo common.ApprovalStart
+	approvalInfo.DocumentType = "salesorder"
+	approvalInfo.DocumentID = salesorderID
+	approvalInfo.DocumentNumber = salesorder.SalesorderNumber
+	approvalInfo.Total = salesorder.Total
+	approvalInfo.ContactID = salesorder.CustomerID
+	approvalInfo.RoleID = info.RoleID
+	approvalInfo.OrganizationID = info.OrganizationID
+	approvalInfo.User = info.User
+	approvalInfo.Email = info.Email
+	approval, description, err := common.StartApproval(common.NewCommonRepository(repo.tx), approvalInfo)
+	if err != nil {
+		return "", err
+	}
+	if approval != nil {
+		err = repo.UpdateSalesorderApprovalStatus(salesorderID, 2, info.Email) //PENDING APPROVAL
+		if err != nil {
+			msg := "update salesorder approval status error"
+			return "", errors.New(msg)
+		}
+		var hold SalesorderHold
+		hold.OrganizationID = info.OrganizationID
+		hold.SalesorderHoldID = "soh-" + xid.New().String()
+		hold.SalesorderID = salesorderID
+		hold.CustomerID = salesorder.CustomerID
+		hold.Reason = 3
+		hold.OrderTotal = salesorder.Total
+		hold.Status = 1
+		hold.Created = time.Now()
+		hold.CreatedBy = info.Email
+		hold.Updated = time.Now()
+		hold.UpdatedBy = info.Email
+		err = repo.CreateSalesorderHold(hold)
+		if err != nil {
+			msg := "create salesorder hold error"
+			return "", errors.New(msg)
+		}
+		description = "Sales Order On Hold: " + description
+	} else {
+		hold, err := s.checkCreditHold(repo, salesorder, info.Email)
+		if err != nil {
+			return "", err
+		}
+		if hold == nil {
+			return "", nil
+		}
+		description = creditHoldDescription(hold)
+	}
+	err = repo.UpdateSalesorderStatus(salesorderID, 4, info.Email) //ON HOLD
+	if err != nil {
+		msg := "update salesorder error: "
+		return "", errors.New(msg)
+	}
+	return description, nil
+}
+
 func (s *salesorderService) GetSalesorderByID(organizationID, id string) (*SalesorderResponse, error) {
 	db := database.RDB()
 	query := NewSalesorderQuery(db)
@@ -448,6 +698,19 @@ func (s *salesorderService) DeleteSalesorder(salesorderID, organizationID, user,
 	if err != nil {
 		return err
 	}
+	if so.Status == 4 {
+		err = repo.DeleteSalesorderHold(salesorderID, email)
+		if err != nil {
+			return err
+		}
+	}
+	if so.ApprovalStatus == 2 {
+		err = common.NewCommonRepository(tx).CancelApproval(organizationID, "salesorder", salesorderID, email)
+		if err != nil {
+			msg := "cancel approval error"
+			return errors.New(msg)
+		}
+	}
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "salesorder"
 	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
@@ -479,45 +742,239 @@ func (s *salesorderService) GetSalesorderItemList(salesorderID, organizationID s
 	return list, err
 }
 
-func (s *salesorderService) ConfirmSalesorder(salesorderID, organizationID, user, email string) error {
+// ConfirmSalesorder returns the status of the sales order after confirming,
+// it stays 1 while the order waits for approval
+func (s *salesorderService) ConfirmSalesorder(salesorderID, organizationID, roleID, user, email string) (int, error) {
 	db := database.WDB()
 	tx, err := db.Begin()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 	repo := NewSalesorderRepository(tx)
 	oldSalesorder, err := repo.GetSalesorderByID(organizationID, salesorderID)
 	if err != nil {
 		msg := "Salesorder not exist"
-		return errors.New(msg)
+		return 0, errors.New(msg)
 	}
 	if oldSalesorder.Status != 1 {
 		msg := "Salesorder status error"
-		return errors.New(msg)
+		return 0, errors.New(msg)
+	}
+	if oldSalesorder.ApprovalStatus == 2 {
+		msg := "Salesorder pending approval"
+		return 0, errors.New(msg)
+	}
+	if oldSalesorder.ApprovalStatus != 3 {
+		var approvalInfo common.ApprovalStart
+		approvalInfo.DocumentType = "salesorder"
+		approvalInfo.DocumentID = salesorderID
+		approvalInfo.DocumentNumber = oldSalesorder.SalesorderNumber
+		approvalInfo.Total = oldSalesorder.Total
+		approvalInfo.ContactID = oldSalesorder.CustomerID
+		approvalInfo.RoleID = roleID
+		approvalInfo.OrganizationID = organizationID
+		approvalInfo.User = user
+		approvalInfo.Email = email
+		approval, description, err := common.StartApproval(common.NewCommonRepository(tx), approvalInfo)
+		if err != nil {
+			return 0, err
+		}
+		if approval != nil {
+			err = repo.UpdateSalesorderApprovalStatus(salesorderID, 2, email) //PENDING APPROVAL
+			if err != nil {
+				msg := "update salesorder approval status error"
+				return 0, errors.New(msg)
+			}
+			tx.Commit()
+			err = publishHistory([][]byte{salesorderHistory(salesorderID, organizationID, description, user, email)})
+			return oldSalesorder.Status, err
+		}
+	}
+	hold, err := s.checkCreditHold(repo, oldSalesorder, email)
+	if err != nil {
+		return 0, err
+	}
+	status := 2 //CONFIRMED
+	description := "Sales Order Confirmed"
+	if hold != nil {
+		status = 4 //ON HOLD
+		description = creditHoldDescription(hold)
 	}
-	err = repo.UpdateSalesorderStatus(salesorderID, 2, email) //CONFIRMED
+	err = repo.UpdateSalesorderStatus(salesorderID, status, email)
 	if err != nil {
 		msg := "update salesorder error: "
+		return 0, errors.New(msg)
+	}
+	msgs := [][]byte{salesorderHistory(salesorderID, organizationID, description, user, email)}
+	if status == 2 {
+		_, dropshipMsgs, err := createDropshipPurchaseorders(repo, salesorderID, organizationID, user, email)
+		if err != nil {
+			return 0, err
+		}
+		msgs = append(msgs, dropshipMsgs...)
+	}
+	tx.Commit()
+	err = publishHistory(msgs)
+	return status, err
+}
+
+// checkCreditHold puts the sales order on hold when the customer is on credit
+// hold, or when open invoices, unbilled confirmed orders and this order
+// together go over the credit limit. A credit limit of 0 means no limit.
+func (s *salesorderService) checkCreditHold(repo *salesorderRepository, salesorder *SalesorderResponse, email string) (*SalesorderHold, error) {
+	settingRepo := setting.NewSettingRepository(repo.tx)
+	customer, err := settingRepo.GetCustomerByID(salesorder.CustomerID, salesorder.OrganizationID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, errors.New(msg)
+	}
+	if customer.CreditHold != 1 && customer.CreditLimit <= 0 {
+		return nil, nil
+	}
+	openInvoices, err := repo.GetCustomerOpenInvoiceAmount(salesorder.OrganizationID, salesorder.CustomerID)
+	if err != nil {
+		msg := "get customer open invoices error"
+		return nil, errors.New(msg)
+	}
+	unbilled, err := repo.GetCustomerUnbilledAmount(salesorder.OrganizationID, salesorder.CustomerID, salesorder.SalesorderID)
+	if err != nil {
+		msg := "get customer unbilled orders error"
+		return nil, errors.New(msg)
+	}
+	var hold SalesorderHold
+	hold.Exposure = openInvoices + unbilled
+	if customer.CreditHold == 1 {
+		hold.Reason = 1
+	} else if hold.Exposure+salesorder.Total > customer.CreditLimit {
+		hold.Reason = 2
+	} else {
+		return nil, nil
+	}
+	hold.OrganizationID = salesorder.OrganizationID
+	hold.SalesorderHoldID = "soh-" + xid.New().String()
+	hold.SalesorderID = salesorder.SalesorderID
+	hold.CustomerID = salesorder.CustomerID
+	hold.CreditLimit = customer.CreditLimit
+	hold.OrderTotal = salesorder.Total
+	hold.Status = 1
+	hold.Created = time.Now()
+	hold.CreatedBy = email
+	hold.Updated = time.Now()
+	hold.UpdatedBy = email
+	err = repo.CreateSalesorderHold(hold)
+	if err != nil {
+		msg := "create salesorder hold error"
+		return nil, errors.New(msg)
+	}
+	return &hold, nil
+}
+
+func creditHoldDescription(hold *SalesorderHold) string {
+	if hold.Reason == 2 {
+		return fmt.Sprintf("Sales Order On Hold: credit limit %.2f exceeded, exposure %.2f", hold.CreditLimit, hold.Exposure+hold.OrderTotal)
+	}
+	return "Sales Order On Hold: customer on credit hold"
+}
+
+func (s *salesorderService) GetSalesorderHoldList(filter SalesorderHoldFilter) (int, *[]SalesorderHoldResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetSalesorderHoldCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetSalesorderHoldList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+// ReleaseSalesorderHold confirms a sales order on hold. Admins and roles
+// whose menus grant the release api can release a hold.
+func (s *salesorderService) ReleaseSalesorderHold(salesorderID string, info SalesorderHoldRelease) error {
+	if info.IsAdmin != 1 && !service.NewRbacService().CheckPrivilege(info.RoleID, "/salesorders/:id/release", "POST") {
+		msg := "no privilege to release credit hold"
 		return errors.New(msg)
 	}
-	var newEvent common.NewHistoryCreated
-	newEvent.HistoryType = "salesorder"
-	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
-	newEvent.HistoryBy = user
-	newEvent.ReferenceID = salesorderID
-	newEvent.Description = "Sales Order Confirmed"
-	newEvent.OrganizationID = organizationID
-	newEvent.Email = email
-	rabbit, _ := queue.GetConn()
-	msg, _ := json.Marshal(newEvent)
-	err = rabbit.Publish("NewHistoryCreated", msg)
+	db := database.WDB()
+	tx, err := db.Begin()
 	if err != nil {
-		msg := "create event NewHistoryCreated error"
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	oldSalesorder, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "Salesorder not exist"
+		return errors.New(msg)
+	}
+	if oldSalesorder.Status != 4 {
+		msg := "Salesorder not on hold"
+		return errors.New(msg)
+	}
+	hold, err := repo.GetActiveSalesorderHold(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "salesorder hold not exist"
+		return errors.New(msg)
+	}
+	if hold.Reason == 3 && oldSalesorder.ApprovalStatus == 2 {
+		msg := "Salesorder pending approval"
+		return errors.New(msg)
+	}
+	err = repo.ReleaseSalesorderHold(hold.SalesorderHoldID, info.Notes, info.Email)
+	if err != nil {
+		msg := "release salesorder hold error"
+		return errors.New(msg)
+	}
+	err = repo.UpdateSalesorderStatus(salesorderID, 2, info.Email) //CONFIRMED
+	if err != nil {
+		msg := "update salesorder error: "
 		return errors.New(msg)
 	}
+	_, msgs, err := createDropshipPurchaseorders(repo, salesorderID, info.OrganizationID, info.User, info.Email)
+	if err != nil {
+		return err
+	}
+	msgs = append([][]byte{salesorderHistory(salesorderID, info.OrganizationID, "Credit Hold Released: "+info.Notes, info.User, info.Email)}, msgs...)
 	tx.Commit()
-	return err
+	return publishHistory(msgs)
+}
+
+func (s *salesorderService) GetCustomerCredit(organizationID, customerID string) (*CustomerCreditResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	settingRepo := setting.NewSettingRepository(tx)
+	customer, err := settingRepo.GetCustomerByID(customerID, organizationID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, errors.New(msg)
+	}
+	var res CustomerCreditResponse
+	res.CustomerID = customerID
+	res.CreditLimit = customer.CreditLimit
+	res.CreditHold = customer.CreditHold
+	res.OpenInvoices, err = repo.GetCustomerOpenInvoiceAmount(organizationID, customerID)
+	if err != nil {
+		msg := "get customer open invoices error"
+		return nil, errors.New(msg)
+	}
+	res.UnbilledOrders, err = repo.GetCustomerUnbilledAmount(organizationID, customerID, "")
+	if err != nil {
+		msg := "get customer unbilled orders error"
+		return nil, errors.New(msg)
+	}
+	res.Exposure = res.OpenInvoices + res.UnbilledOrders
+	if res.CreditLimit > 0 {
+		res.AvailableCredit = res.CreditLimit - res.Exposure
+	}
+	return &res, nil
 }
 
 // picking
@@ -540,6 +997,15 @@ func (s *salesorderService) NewPickingorder(salesorderID string, info Pickingord
 		msg := "picking order number exists"
 		return nil, errors.New(msg)
 	}
+	salesorder, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "salesorder not exist"
+		return nil, errors.New(msg)
+	}
+	if salesorder.Status == 4 {
+		msg := "salesorder is on credit hold"
+		return nil, errors.New(msg)
+	}
 	pickingorderID := "pic-" + xid.New().String()
 	itemRepo := item.NewItemRepository(tx)
 	warehouseRepo := warehouse.NewWarehouseRepository(tx)
@@ -549,20 +1015,27 @@ func (s *salesorderService) NewPickingorder(salesorderID string, info Pickingord
 			msg := "sales order item not exist"
 			return nil, errors.New(msg)
 		}
-		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		if oldSoItem.DropShip == 1 {
+			msg := "drop-ship item can not be picked"
+			return nil, errors.New(msg)
+		}
+		stockLines, err := itemRepo.GetItemStockLines(itemRow.ItemID, info.OrganizationID, itemRow.Quantity)
 		if err != nil {
-			msg := "item not exist"
+			msg := "item not exist or kit has no components"
 			return nil, errors.New(msg)
 		}
 		pickingorderItemID := "pii-" + xid.New().String()
-		if itemInfo.TrackLocation == 1 {
-			if itemInfo.StockAvailable < itemRow.Quantity {
-				msg := "no enough stock to pick"
+		for _, stockLine := range *stockLines {
+			if stockLine.TrackLocation != 1 {
+				continue
+			}
+			if stockLine.StockAvailable < stockLine.Quantity {
+				msg := "no enough stock to pick: " + stockLine.SKU
 				return nil, errors.New(msg)
 			}
-			quantityToPick := itemRow.Quantity
+			quantityToPick := stockLine.Quantity
 			for quantityToPick > 0 {
-				nextBatch, err := itemRepo.GetItemNextBatch(itemRow.ItemID, info.OrganizationID)
+				nextBatch, err := itemRepo.GetItemNextBatch(stockLine.ItemID, info.OrganizationID)
 				if err != nil {
 					msg := "get next batch error"
 					return nil, errors.New(msg)
@@ -582,7 +1055,7 @@ func (s *salesorderService) NewPickingorder(salesorderID string, info Pickingord
 					pickingorderLog.PickingorderItemID = pickingorderItemID
 					pickingorderLog.LocationID = nextBatch.LocationID
 					pickingorderLog.BatchID = nextBatch.BatchID
-					pickingorderLog.ItemID = itemRow.ItemID
+					pickingorderLog.ItemID = stockLine.ItemID
 					pickingorderLog.Quantity = quantityToPick
 					pickingorderLog.Status = 1
 					pickingorderLog.Created = time.Now()
@@ -615,7 +1088,7 @@ func (s *salesorderService) NewPickingorder(salesorderID string, info Pickingord
 					pickingorderLog.PickingorderItemID = pickingorderItemID
 					pickingorderLog.LocationID = nextBatch.LocationID
 					pickingorderLog.BatchID = nextBatch.BatchID
-					pickingorderLog.ItemID = itemRow.ItemID
+					pickingorderLog.ItemID = stockLine.ItemID
 					pickingorderLog.Quantity = nextBatch.Balance
 					pickingorderLog.Status = 1
 					pickingorderLog.Created = time.Now()
@@ -669,10 +1142,12 @@ func (s *salesorderService) NewPickingorder(salesorderID string, info Pickingord
 			msg := "create picking order item error: "
 			return nil, errors.New(msg)
 		}
-		err = itemRepo.UpdateItemPickingStock(itemRow.ItemID, itemRow.Quantity, info.Email)
-		if err != nil {
-			msg := "update item stock error: "
-			return nil, errors.New(msg)
+		for _, stockLine := range *stockLines {
+			err = itemRepo.UpdateItemPickingStock(stockLine.ItemID, stockLine.Quantity, info.Email)
+			if err != nil {
+				msg := "update item stock error: "
+				return nil, errors.New(msg)
+			}
 		}
 	}
 	logs, err := repo.GetPickingorderLogSum(pickingorderID)
@@ -829,27 +1304,37 @@ func (s *salesorderService) BatchPickingorder(info PickingorderBatch) (*string,
 			msg := "get salesorder error: "
 			return nil, errors.New(msg)
 		}
+		if salesorder.Status == 4 {
+			msg := "salesorder is on credit hold: " + salesorder.SalesorderNumber
+			return nil, errors.New(msg)
+		}
 		items, err := repo.GetSalesorderItemList(info.OrganizationID, soID)
 		if err != nil {
 			msg := "get salesorder items error: "
 			return nil, errors.New(msg)
 		}
 		for _, itemRow := range *items {
+			if itemRow.DropShip == 1 {
+				continue
+			}
 			toPick := itemRow.Quantity - itemRow.QuantityPicked
-			itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+			stockLines, err := itemRepo.GetItemStockLines(itemRow.ItemID, info.OrganizationID, toPick)
 			if err != nil {
-				msg := "item not exist"
+				msg := "item not exist or kit has no components"
 				return nil, errors.New(msg)
 			}
 			pickingorderItemID := "pii-" + xid.New().String()
-			if itemInfo.TrackLocation == 1 {
-				if itemInfo.StockAvailable < toPick {
-					msg := "no enough stock for item: " + itemInfo.Name + " in salesorder :" + salesorder.SalesorderNumber
+			for _, stockLine := range *stockLines {
+				if stockLine.TrackLocation != 1 {
+					continue
+				}
+				if stockLine.StockAvailable < stockLine.Quantity {
+					msg := "no enough stock for item: " + stockLine.Name + " in salesorder :" + salesorder.SalesorderNumber
 					return nil, errors.New(msg)
 				}
-				quantityToPick := toPick
+				quantityToPick := stockLine.Quantity
 				for quantityToPick > 0 {
-					nextBatch, err := itemRepo.GetItemNextBatch(itemRow.ItemID, info.OrganizationID)
+					nextBatch, err := itemRepo.GetItemNextBatch(stockLine.ItemID, info.OrganizationID)
 					if err != nil {
 						msg := "get next batch error"
 						return nil, errors.New(msg)
@@ -869,7 +1354,7 @@ func (s *salesorderService) BatchPickingorder(info PickingorderBatch) (*string,
 						pickingorderLog.PickingorderItemID = pickingorderItemID
 						pickingorderLog.LocationID = nextBatch.LocationID
 						pickingorderLog.BatchID = nextBatch.BatchID
-						pickingorderLog.ItemID = itemRow.ItemID
+						pickingorderLog.ItemID = stockLine.ItemID
 						pickingorderLog.Quantity = quantityToPick
 						pickingorderLog.Status = 1
 						pickingorderLog.Created = time.Now()
@@ -902,7 +1387,7 @@ func (s *salesorderService) BatchPickingorder(info PickingorderBatch) (*string,
 						pickingorderLog.PickingorderItemID = pickingorderItemID
 						pickingorderLog.LocationID = nextBatch.LocationID
 						pickingorderLog.BatchID = nextBatch.BatchID
-						pickingorderLog.ItemID = itemRow.ItemID
+						pickingorderLog.ItemID = stockLine.ItemID
 						pickingorderLog.Quantity = nextBatch.Balance
 						pickingorderLog.Status = 1
 						pickingorderLog.Created = time.Now()
@@ -952,10 +1437,12 @@ func (s *salesorderService) BatchPickingorder(info PickingorderBatch) (*string,
 				msg := "create picking order item error: "
 				return nil, errors.New(msg)
 			}
-			err = itemRepo.UpdateItemPickingStock(itemRow.ItemID, toPick, info.Email)
-			if err != nil {
-				msg := "update item stock error: "
-				return nil, errors.New(msg)
+			for _, stockLine := range *stockLines {
+				err = itemRepo.UpdateItemPickingStock(stockLine.ItemID, stockLine.Quantity, info.Email)
+				if err != nil {
+					msg := "update item stock error: "
+					return nil, errors.New(msg)
+				}
 			}
 		}
 		picking_status := 3
@@ -1314,15 +1801,21 @@ func (s *salesorderService) NewPackage(salesorderID string, info PackageNew) (*s
 			msg := "sales order item not exist"
 			return nil, errors.New(msg)
 		}
-		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		if oldSoItem.DropShip == 1 {
+			msg := "drop-ship item can not be packed"
+			return nil, errors.New(msg)
+		}
+		stockLines, err := itemRepo.GetItemStockLines(itemRow.ItemID, info.OrganizationID, itemRow.Quantity)
 		if err != nil {
-			msg := "item not exist"
+			msg := "item not exist or kit has no components"
 			return nil, errors.New(msg)
 		}
 		packageItemID := "pai-" + xid.New().String()
-		if itemInfo.StockPacking < itemRow.Quantity {
-			msg := "no enough stock to pack"
-			return nil, errors.New(msg)
+		for _, stockLine := range *stockLines {
+			if stockLine.StockPacking < stockLine.Quantity {
+				msg := "no enough stock to pack: " + stockLine.SKU
+				return nil, errors.New(msg)
+			}
 		}
 		if oldSoItem.QuantityPicked < itemRow.Quantity {
 			msg := "packing quantity greater than not packed"
@@ -1357,13 +1850,15 @@ func (s *salesorderService) NewPackage(salesorderID string, info PackageNew) (*s
 			msg := "create package item error: "
 			return nil, errors.New(msg)
 		}
-		err = itemRepo.UpdateItemPackedStock(itemRow.ItemID, itemRow.Quantity, info.Email)
-		if err != nil {
-			msg := "update item stock error: "
-			return nil, errors.New(msg)
-		}
-	}
-	var newPackage Package
+		for _, stockLine := range *stockLines {
+			err = itemRepo.UpdateItemPackedStock(stockLine.ItemID, stockLine.Quantity, info.Email)
+			if err != nil {
+				msg := "update item stock error: "
+				return nil, errors.New(msg)
+			}
+		}
+	}
+	var newPackage Package
 	newPackage.SalesorderID = salesorderID
 	newPackage.PackageID = packageID
 	newPackage.PackageNumber = info.PackageNumber
@@ -1482,6 +1977,7 @@ func (s *salesorderService) BatchShippingorder(info ShippingorderBatch) (*string
 	var msgs [][]byte
 	var salesorders []string
 	var itemHistorys []string
+	var shippingAddress *setting.AddressSnapshot
 	for _, packageID := range info.PackageID {
 		packageInfo, err := repo.GetPackageByID(info.OrganizationID, packageID)
 		if err != nil {
@@ -1492,6 +1988,17 @@ func (s *salesorderService) BatchShippingorder(info ShippingorderBatch) (*string
 			msg := "package status error for package: " + packageInfo.PackageNumber
 			return nil, errors.New(msg)
 		}
+		packageSalesorder, err := repo.GetSalesorderByID(info.OrganizationID, packageInfo.SalesorderID)
+		if err != nil {
+			msg := "salesorder not exist"
+			return nil, errors.New(msg)
+		}
+		if shippingAddress == nil {
+			shippingAddress = &packageSalesorder.ShippingAddress
+		} else if *shippingAddress != packageSalesorder.ShippingAddress {
+			msg := "packages shipped to different addresses can not be in one shipping order"
+			return nil, errors.New(msg)
+		}
 		items, err := repo.GetPackageItemList(info.OrganizationID, packageID)
 		if err != nil {
 			msg := "get package items error: "
@@ -1642,6 +2149,20 @@ func (s *salesorderService) BatchShippingorder(info ShippingorderBatch) (*string
 			return nil, errors.New(msg)
 		}
 	}
+	var adapter carrier.Adapter
+	var label *carrier.Label
+	if info.RequestLabel == 1 {
+		if info.CarrierID == "" {
+			msg := "carrier required to request shipping label"
+			return nil, errors.New(msg)
+		}
+		adapter, label, err = s.requestShippingLabel(info.OrganizationID, info.CarrierID, info.PackageID, info.ServiceCode, info.LabelFormat, info.ShippingorderNumber)
+		if err != nil {
+			msg := "request shipping label error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		info.TrackingNumber = label.TrackingNumber
+	}
 	var shippingorder Shippingorder
 	shippingorder.OrganizationID = info.OrganizationID
 	shippingorder.ShippingorderID = shippingorderID
@@ -1651,6 +2172,7 @@ func (s *salesorderService) BatchShippingorder(info ShippingorderBatch) (*string
 	shippingorder.CarrierID = info.CarrierID
 	shippingorder.TrackingNumber = info.TrackingNumber
 	shippingorder.Notes = info.Notes
+	shippingorder.ShippingAddress = *shippingAddress
 	shippingorder.Status = 1
 	shippingorder.Created = time.Now()
 	shippingorder.CreatedBy = info.Email
@@ -1658,9 +2180,19 @@ func (s *salesorderService) BatchShippingorder(info ShippingorderBatch) (*string
 	shippingorder.UpdatedBy = info.Email
 	err = repo.CreateShippingorder(shippingorder)
 	if err != nil {
+		if label != nil {
+			adapter.Void(label.TrackingNumber)
+		}
 		msg := "create shipping order error: "
 		return nil, errors.New(msg)
 	}
+	if label != nil {
+		err = s.saveShippingLabel(repo, info.OrganizationID, shippingorderID, info.CarrierID, label, info.Email)
+		if err != nil {
+			adapter.Void(label.TrackingNumber)
+			return nil, err
+		}
+	}
 	tx.Commit()
 	rabbit, _ := queue.GetConn()
 	for _, msgRow := range msgs {
@@ -1711,9 +2243,9 @@ func (s *salesorderService) GetShippingorderDetailList(salesorderID, organizatio
 	return list, err
 }
 
+// GetRequisitionList forecasts the demand of the target days plus the vendor
+// lead time from the sales between the dates and lists the items to order
 func (s *salesorderService) GetRequisitionList(filter RequsitionFilter) (*[]RequsitionResponse, error) {
-	db := database.RDB()
-	query := NewSalesorderQuery(db)
 	if filter.StartDate == "" {
 		filter.StartDate = time.Now().AddDate(0, -3, 0).Format("2006-01-02")
 	}
@@ -1723,31 +2255,365 @@ func (s *salesorderService) GetRequisitionList(filter RequsitionFilter) (*[]Requ
 	if filter.TargetDay == 0 {
 		filter.TargetDay = 30
 	}
-	fmt.Println(filter.EndDate)
 	endTime, err := time.Parse("2006-01-02", filter.EndDate)
 	if err != nil {
 		msg := "end date error"
 		return nil, errors.New(msg)
 	}
-	fmt.Println(filter.StartDate)
 	startTime, err := time.Parse("2006-01-02", filter.StartDate)
 	if err != nil {
 		msg := "start date error"
 		return nil, errors.New(msg)
 	}
-	filter.Period = int(endTime.Sub(startTime).Hours() / 24)
-	fmt.Println(filter.Period)
-	list, err := query.GetRequisitionList(filter)
+	if endTime.Before(startTime) {
+		msg := "end date must not be before start date"
+		return nil, errors.New(msg)
+	}
+	forecasts, err := s.buildForecasts(filter.OrganizationID, "", startTime, endTime, filter.TargetDay)
 	if err != nil {
 		return nil, err
 	}
 	var res []RequsitionResponse
-	for _, item := range *list {
-		if item.Quantity > 0 {
-			res = append(res, item)
+	for _, forecast := range *forecasts {
+		if forecast.SuggestedQuantity <= 0 {
+			continue
+		}
+		var requisition RequsitionResponse
+		requisition.ItemID = forecast.ItemID
+		requisition.ItemName = forecast.ItemName
+		requisition.SKU = forecast.SKU
+		requisition.StockOnHand = forecast.StockAvailable
+		requisition.TargetStock = forecast.ForecastQuantity + forecast.SafetyStock
+		requisition.Quantity = forecast.SuggestedQuantity
+		requisition.Unit = forecast.Unit
+		res = append(res, requisition)
+	}
+	return &res, nil
+}
+
+// buildForecasts forecasts the demand of the items from their daily sales
+// between startDate and endDate, both included. Each forecast covers the lead
+// time of the default vendor plus the horizon starting the day after endDate,
+// the suggested quantity tops the stock up to that demand plus safety stock.
+func (s *salesorderService) buildForecasts(organizationID, itemID string, startDate, endDate time.Time, horizonDays int) (*[]ForecastResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	start, end := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+	items, err := query.GetForecastItems(organizationID, itemID, start, end)
+	if err != nil {
+		msg := "get forecast items error"
+		return nil, errors.New(msg)
+	}
+	settingList, err := query.GetForecastSettings(organizationID)
+	if err != nil {
+		msg := "get forecast settings error"
+		return nil, errors.New(msg)
+	}
+	settings := make(map[string]ForecastSettingResponse)
+	for _, setting := range *settingList {
+		settings[setting.ItemID] = setting
+	}
+	demands, err := query.GetDailyDemand(organizationID, itemID, start, end)
+	if err != nil {
+		msg := "get daily demand error"
+		return nil, errors.New(msg)
+	}
+	historyDays := int(endDate.Sub(startDate).Hours()/24) + 1
+	histories := make(map[string][]float64)
+	for _, demand := range *demands {
+		demandDate, err := time.Parse("2006-01-02", demand.DemandDate)
+		if err != nil {
+			continue
+		}
+		day := int(demandDate.Sub(startDate).Hours() / 24)
+		if day < 0 || day >= historyDays {
+			continue
+		}
+		if _, ok := histories[demand.ItemID]; !ok {
+			histories[demand.ItemID] = make([]float64, historyDays)
+		}
+		histories[demand.ItemID][day] += float64(demand.Quantity)
+	}
+	forecastDate := endDate.AddDate(0, 0, 1)
+	var res []ForecastResponse
+	for _, itemRow := range *items {
+		setting, ok := settings[itemRow.ItemID]
+		if !ok {
+			setting = defaultForecastSetting(organizationID, itemRow.ItemID)
+		}
+		history, ok := histories[itemRow.ItemID]
+		if !ok {
+			history = make([]float64, historyDays)
+		}
+		days := itemRow.LeadTime + horizonDays
+		total := 0.0
+		for _, v := range forecastDemand(history, setting, days) {
+			total += v
+		}
+		var forecast ForecastResponse
+		forecast.OrganizationID = organizationID
+		forecast.ItemID = itemRow.ItemID
+		forecast.ItemName = itemRow.ItemName
+		forecast.SKU = itemRow.SKU
+		forecast.Unit = itemRow.Unit
+		forecast.Method = setting.Method
+		forecast.ForecastDate = forecastDate.Format("2006-01-02")
+		forecast.PeriodEnd = forecastDate.AddDate(0, 0, days-1).Format("2006-01-02")
+		forecast.HistoryDays = historyDays
+		forecast.HorizonDays = horizonDays
+		forecast.LeadTime = itemRow.LeadTime
+		forecast.DailyDemand = math.Round(total/float64(days)*100) / 100
+		forecast.ForecastQuantity = int(math.Ceil(math.Round(total*100) / 100))
+		forecast.SafetyStock = setting.SafetyStock
+		forecast.StockAvailable = itemRow.StockAvailable
+		forecast.OpenQuantity = itemRow.OpenQuantity
+		forecast.SuggestedQuantity = forecast.ForecastQuantity + forecast.SafetyStock - forecast.StockAvailable - forecast.OpenQuantity
+		if forecast.SuggestedQuantity < 0 {
+			forecast.SuggestedQuantity = 0
+		}
+		forecast.Evaluated = 2
+		forecast.Status = 1
+		res = append(res, forecast)
+	}
+	return &res, nil
+}
+
+// NewForecast forecasts from the sales up to yesterday and stores the result
+// so it can be compared with the actual sales once the period is over. A run
+// replaces the forecasts of the same day.
+func (s *salesorderService) NewForecast(info ForecastNew) (*[]ForecastResponse, error) {
+	if info.HistoryDays == 0 {
+		info.HistoryDays = 180
+	}
+	if info.HorizonDays == 0 {
+		info.HorizonDays = 30
+	}
+	if info.ItemID != "" {
+		itemService := item.NewItemService()
+		itemInfo, err := itemService.GetItemByID(info.OrganizationID, info.ItemID)
+		if err != nil {
+			msg := "item not exist"
+			return nil, errors.New(msg)
+		}
+		if itemInfo.ItemType == 2 {
+			msg := "sales kit can not be forecasted, forecast its components"
+			return nil, errors.New(msg)
+		}
+	}
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	forecasts, err := s.buildForecasts(info.OrganizationID, info.ItemID, today.AddDate(0, 0, -info.HistoryDays), today.AddDate(0, 0, -1), info.HorizonDays)
+	if err != nil {
+		return nil, err
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	for i, forecastRow := range *forecasts {
+		err = repo.DeleteForecastByDate(info.OrganizationID, forecastRow.ItemID, forecastRow.ForecastDate, info.User)
+		if err != nil {
+			msg := "delete forecast error"
+			return nil, errors.New(msg)
+		}
+		var forecast Forecast
+		forecast.OrganizationID = info.OrganizationID
+		forecast.ForecastID = "fct-" + xid.New().String()
+		forecast.ItemID = forecastRow.ItemID
+		forecast.Method = forecastRow.Method
+		forecast.ForecastDate = forecastRow.ForecastDate
+		forecast.PeriodEnd = forecastRow.PeriodEnd
+		forecast.HistoryDays = forecastRow.HistoryDays
+		forecast.HorizonDays = forecastRow.HorizonDays
+		forecast.LeadTime = forecastRow.LeadTime
+		forecast.DailyDemand = forecastRow.DailyDemand
+		forecast.ForecastQuantity = forecastRow.ForecastQuantity
+		forecast.SafetyStock = forecastRow.SafetyStock
+		forecast.StockAvailable = forecastRow.StockAvailable
+		forecast.OpenQuantity = forecastRow.OpenQuantity
+		forecast.SuggestedQuantity = forecastRow.SuggestedQuantity
+		forecast.ActualQuantity = 0
+		forecast.Evaluated = 2
+		forecast.Status = 1
+		forecast.Created = time.Now()
+		forecast.CreatedBy = info.User
+		forecast.Updated = time.Now()
+		forecast.UpdatedBy = info.User
+		err = repo.CreateForecast(forecast)
+		if err != nil {
+			msg := "create forecast error"
+			return nil, errors.New(msg)
+		}
+		(*forecasts)[i].ForecastID = forecast.ForecastID
+	}
+	tx.Commit()
+	return forecasts, nil
+}
+
+func (s *salesorderService) GetForecastList(filter ForecastFilter) (int, *[]ForecastResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetForecastCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetForecastList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *salesorderService) GetForecastAccuracyList(filter ForecastAccuracyFilter) (int, *[]ForecastAccuracyResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetForecastAccuracyCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetForecastAccuracyList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+// EvaluateForecasts fills in the actual sales of the forecasts whose period
+// is over
+func (s *salesorderService) EvaluateForecasts() error {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	forecasts, err := query.GetDueForecastList(time.Now().Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+	if len(*forecasts) == 0 {
+		return nil
+	}
+	wdb := database.WDB()
+	tx, err := wdb.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	for _, forecast := range *forecasts {
+		actual, err := query.GetItemDemand(forecast.OrganizationID, forecast.ItemID, forecast.ForecastDate, forecast.PeriodEnd)
+		if err != nil {
+			msg := "get item demand error"
+			return errors.New(msg)
+		}
+		err = repo.UpdateForecastActual(forecast.ForecastID, actual, "system")
+		if err != nil {
+			msg := "update forecast error"
+			return errors.New(msg)
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *salesorderService) GetForecastSettingList(filter ForecastSettingFilter) (int, *[]ForecastSettingResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetForecastSettingCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetForecastSettingList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+// GetForecastSettingByID returns the forecast setting of an item, or the
+// default one when the item has none
+func (s *salesorderService) GetForecastSettingByID(organizationID, itemID string) (*ForecastSettingResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	setting, err := query.GetForecastSettingByID(organizationID, itemID)
+	if err == nil {
+		return setting, nil
+	}
+	itemService := item.NewItemService()
+	itemInfo, err := itemService.GetItemByID(organizationID, itemID)
+	if err != nil {
+		msg := "item not exist"
+		return nil, errors.New(msg)
+	}
+	res := defaultForecastSetting(organizationID, itemID)
+	res.ItemName = itemInfo.Name
+	res.SKU = itemInfo.SKU
+	return &res, nil
+}
+
+func (s *salesorderService) UpdateForecastSetting(itemID string, info ForecastSettingNew) (*ForecastSettingResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	itemRepo := item.NewItemRepository(tx)
+	itemInfo, err := itemRepo.GetItemByID(itemID, info.OrganizationID)
+	if err != nil {
+		msg := "item not exist"
+		return nil, errors.New(msg)
+	}
+	if itemInfo.ItemType == 2 {
+		msg := "sales kit can not be forecasted, forecast its components"
+		return nil, errors.New(msg)
+	}
+	var setting ForecastSetting
+	setting.OrganizationID = info.OrganizationID
+	setting.ItemID = itemID
+	setting.Method = info.Method
+	setting.Window = info.Window
+	if setting.Window == 0 {
+		setting.Window = defaultForecastWindow
+	}
+	setting.Alpha = info.Alpha
+	if setting.Alpha == 0 {
+		setting.Alpha = defaultForecastAlpha
+	}
+	setting.Beta = info.Beta
+	if setting.Beta == 0 {
+		setting.Beta = defaultForecastBeta
+	}
+	setting.SeasonLength = info.SeasonLength
+	if setting.SeasonLength == 0 {
+		setting.SeasonLength = defaultForecastSeasonLength
+	}
+	setting.SafetyStock = info.SafetyStock
+	setting.Status = 1
+	setting.Updated = time.Now()
+	setting.UpdatedBy = info.User
+	_, err = repo.GetForecastSettingByID(info.OrganizationID, itemID)
+	if err == nil {
+		err = repo.UpdateForecastSetting(setting)
+		if err != nil {
+			msg := "update forecast setting error"
+			return nil, errors.New(msg)
+		}
+	} else {
+		setting.Created = time.Now()
+		setting.CreatedBy = info.User
+		err = repo.CreateForecastSetting(setting)
+		if err != nil {
+			msg := "create forecast setting error"
+			return nil, errors.New(msg)
 		}
 	}
-	return &res, err
+	tx.Commit()
+	query := NewSalesorderQuery(database.RDB())
+	return query.GetForecastSettingByID(info.OrganizationID, itemID)
 }
 
 func (s *salesorderService) DeleteShippingorder(shippingorderID, organizationID, user, email string) error {
@@ -1764,6 +2630,11 @@ func (s *salesorderService) DeleteShippingorder(shippingorderID, organizationID,
 		msg := "shipping order not exist"
 		return errors.New(msg)
 	}
+	_, err = repo.GetActiveShippingLabel(organizationID, shippingorderID)
+	if err == nil {
+		msg := "shipping order has an active shipping label, void it first"
+		return errors.New(msg)
+	}
 	shippingorderDetails, err := repo.GetShippingorderDetailList(shippingorderID)
 	if err != nil {
 		msg := "get shipping order details error"
@@ -1938,9 +2809,9 @@ func (s *salesorderService) DeletePackage(packageID, organizationID, user, email
 			msg := "sales order item not exist"
 			return errors.New(msg)
 		}
-		_, err = itemRepo.GetItemByID(itemRow.ItemID, organizationID)
+		stockLines, err := itemRepo.GetItemStockLines(itemRow.ItemID, organizationID, itemRow.Quantity)
 		if err != nil {
-			msg := "item not exist"
+			msg := "item not exist or kit has no components"
 			return errors.New(msg)
 		}
 		if oldSoItem.QuantityPacked < itemRow.Quantity {
@@ -1958,10 +2829,12 @@ func (s *salesorderService) DeletePackage(packageID, organizationID, user, email
 			msg := "update salesorder item packed error: "
 			return errors.New(msg)
 		}
-		err = itemRepo.UpdateItemPackedStock(itemRow.ItemID, -itemRow.Quantity, email)
-		if err != nil {
-			msg := "update item stock error: "
-			return errors.New(msg)
+		for _, stockLine := range *stockLines {
+			err = itemRepo.UpdateItemPackedStock(stockLine.ItemID, -stockLine.Quantity, email)
+			if err != nil {
+				msg := "update item stock error: "
+				return errors.New(msg)
+			}
 		}
 		var newEvent common.NewHistoryCreated
 		newEvent.HistoryType = "item"
@@ -2054,8 +2927,9 @@ func (s *salesorderService) DeletePickingorder(pickingorderID, organizationID, u
 	var msgs [][]byte
 	var salesorders []string
 	var itemHistorys []string
+	var kitLogs []PickingorderLogResponse
 	for _, logRow := range *pickingorderLogs {
-		oldSoItem, err := repo.GetSalesorderItemByID(organizationID, logRow.SalesorderID, logRow.ItemID)
+		oldSoItem, err := repo.GetSalesorderItemByIDAll(organizationID, logRow.SalesorderID, logRow.SalesorderItemID)
 		if err != nil {
 			msg := "sales order item not exist"
 			return errors.New(msg)
@@ -2079,20 +2953,33 @@ func (s *salesorderService) DeletePickingorder(pickingorderID, organizationID, u
 			msg := "update location canpick error: "
 			return errors.New(msg)
 		}
-		if oldSoItem.QuantityPicked < logRow.Quantity {
-			msg := "sales order itme picked quantity error"
-			return errors.New(msg)
-		}
-		var soItem SalesorderItem
-		soItem.SalesorderItemID = oldSoItem.SalesorderItemID
-		soItem.QuantityPicked = oldSoItem.QuantityPicked - logRow.Quantity
-		soItem.Updated = time.Now()
-		soItem.UpdatedBy = email
+		if oldSoItem.ItemID != logRow.ItemID {
+			// kit component, the sales order item is unpicked by kit quantity below
+			kitLogged := false
+			for _, kitLog := range kitLogs {
+				if kitLog.PickingorderItemID == logRow.PickingorderItemID {
+					kitLogged = true
+				}
+			}
+			if !kitLogged {
+				kitLogs = append(kitLogs, logRow)
+			}
+		} else {
+			if oldSoItem.QuantityPicked < logRow.Quantity {
+				msg := "sales order itme picked quantity error"
+				return errors.New(msg)
+			}
+			var soItem SalesorderItem
+			soItem.SalesorderItemID = oldSoItem.SalesorderItemID
+			soItem.QuantityPicked = oldSoItem.QuantityPicked - logRow.Quantity
+			soItem.Updated = time.Now()
+			soItem.UpdatedBy = email
 
-		err = repo.PickSalesorderItem(soItem)
-		if err != nil {
-			msg := "unpick salesorder item error: "
-			return errors.New(msg)
+			err = repo.PickSalesorderItem(soItem)
+			if err != nil {
+				msg := "unpick salesorder item error: "
+				return errors.New(msg)
+			}
 		}
 		err = itemRepo.UpdateItemPickingStock(logRow.ItemID, -logRow.Quantity, email)
 		if err != nil {
@@ -2141,6 +3028,33 @@ func (s *salesorderService) DeletePickingorder(pickingorderID, organizationID, u
 			msgs = append(msgs, msg)
 		}
 	}
+	for _, kitLog := range kitLogs {
+		pickingorderItem, err := repo.GetPickingorderItemByID(organizationID, kitLog.PickingorderItemID)
+		if err != nil {
+			msg := "picking order item not exist"
+			return errors.New(msg)
+		}
+		oldSoItem, err := repo.GetSalesorderItemByIDAll(organizationID, kitLog.SalesorderID, kitLog.SalesorderItemID)
+		if err != nil {
+			msg := "sales order item not exist"
+			return errors.New(msg)
+		}
+		if oldSoItem.QuantityPicked < pickingorderItem.Quantity {
+			msg := "sales order itme picked quantity error"
+			return errors.New(msg)
+		}
+		var soItem SalesorderItem
+		soItem.SalesorderItemID = oldSoItem.SalesorderItemID
+		soItem.QuantityPicked = oldSoItem.QuantityPicked - pickingorderItem.Quantity
+		soItem.Updated = time.Now()
+		soItem.UpdatedBy = email
+
+		err = repo.PickSalesorderItem(soItem)
+		if err != nil {
+			msg := "unpick salesorder item error: "
+			return errors.New(msg)
+		}
+	}
 	err = repo.DeletePickingorder(pickingorderID, email)
 	if err != nil {
 		msg := "delete picking order error: "
@@ -2200,6 +3114,9 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewSalesorderRepository(tx)
 	isConflict, err := repo.CheckInvoiceNumberConfict("", info.OrganizationID, info.InvoiceNumber)
 	if err != nil {
@@ -2212,10 +3129,36 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 	}
 	invoiceID := "inv-" + xid.New().String()
 	settingRepo := setting.NewSettingRepository(tx)
+	settingService := setting.NewSettingService()
+	so, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "get sales order error: "
+		return nil, errors.New(msg)
+	}
+	if so.Status == 4 {
+		msg := "salesorder is on credit hold"
+		return nil, errors.New(msg)
+	}
+	customer, err := settingRepo.GetCustomerByID(so.CustomerID, info.OrganizationID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, errors.New(msg)
+	}
+	if info.PaymentTermID == "" {
+		info.PaymentTermID = customer.PaymentTermID
+	}
+	termDates, err := settingService.GetPaymentTermDates(info.OrganizationID, info.PaymentTermID, info.InvoiceDate)
+	if err != nil {
+		return nil, err
+	}
+	if info.DueDate == "" {
+		info.DueDate = termDates.DueDate
+	}
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemRepo := item.NewItemRepository(tx)
+	itemService := item.NewItemService()
 	for _, itemRow := range info.Items {
 		oldSoItem, err := repo.GetSalesorderItemByID(info.OrganizationID, salesorderID, itemRow.ItemID)
 		if err != nil {
@@ -2226,33 +3169,45 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 			msg := "sales order item id error"
 			return nil, errors.New(msg)
 		}
-		_, err = itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
 		if err != nil {
 			msg := "item not exist"
 			return nil, errors.New(msg)
 		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, itemRow.ItemID, itemRow.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		if itemRow.UnitID == "" {
+			itemRow.UnitID = itemInfo.UnitID
+		}
+		quantity := itemRow.Quantity * factor
 
-		taxValue := 0.0
-		if itemRow.TaxID != "" {
-			tax, err := settingRepo.GetTaxByID(itemRow.TaxID, info.OrganizationID)
-			if err != nil {
-				msg := "tax not exist"
-				return nil, errors.New(msg)
-			}
-			taxValue = tax.TaxValue
+		taxID := itemRow.TaxID
+		if customer.TaxExempt == 1 {
+			taxID = ""
 		}
-		itemCount += itemRow.Quantity
-		itemTotal += itemRow.Rate * float64(itemRow.Quantity)
-		taxTotal += itemRow.Rate * float64(itemRow.Quantity) * taxValue / 100
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = taxID
+		taxInfo.Amount = itemRow.Rate * float64(itemRow.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
+		}
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 
 		invoiceItemID := "invi-" + xid.New().String()
-		if oldSoItem.Quantity < oldSoItem.QuantityInvoiced+itemRow.Quantity {
+		if oldSoItem.Quantity < oldSoItem.QuantityInvoiced+quantity {
 			msg := "invoicing quantity greater than not invoiced"
 			return nil, errors.New(msg)
 		}
 		var soItem SalesorderItem
 		soItem.SalesorderItemID = oldSoItem.SalesorderItemID
-		soItem.QuantityInvoiced = oldSoItem.QuantityInvoiced + itemRow.Quantity
+		soItem.QuantityInvoiced = oldSoItem.QuantityInvoiced + quantity
 		soItem.Updated = time.Now()
 		soItem.UpdatedBy = info.Email
 
@@ -2267,12 +3222,15 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 		invoiceItem.InvoiceItemID = invoiceItemID
 		invoiceItem.SalesorderItemID = oldSoItem.SalesorderItemID
 		invoiceItem.ItemID = oldSoItem.ItemID
-		invoiceItem.Quantity = itemRow.Quantity
-		invoiceItem.Rate = itemRow.Rate
-		invoiceItem.TaxID = itemRow.TaxID
-		invoiceItem.TaxValue = taxValue
-		invoiceItem.TaxAmount = float64(itemRow.Quantity) * itemRow.Rate * taxValue / 100
-		invoiceItem.Amount = float64(itemRow.Quantity) * itemRow.Rate
+		invoiceItem.UnitID = itemRow.UnitID
+		invoiceItem.UnitQuantity = itemRow.Quantity
+		invoiceItem.UnitRate = itemRow.Rate
+		invoiceItem.Quantity = quantity
+		invoiceItem.Rate = itemRow.Rate / float64(factor)
+		invoiceItem.TaxID = taxID
+		invoiceItem.TaxValue = tax.TaxValue
+		invoiceItem.TaxAmount = tax.TaxAmount
+		invoiceItem.Amount = tax.TaxableAmount
 		invoiceItem.Status = 1
 		invoiceItem.CreatedBy = info.Email
 		invoiceItem.Created = time.Now()
@@ -2284,10 +3242,44 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 			msg := "create invoice item error: "
 			return nil, errors.New(msg)
 		}
-	}
-	so, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+		var taxRecord setting.TaxRecordNew
+		taxRecord.ReferenceType = "invoice"
+		taxRecord.ReferenceID = invoiceID
+		taxRecord.ReferenceItemID = invoiceItemID
+		taxRecord.RecordDate = info.InvoiceDate
+		taxRecord.Tax = tax
+		taxRecord.OrganizationID = info.OrganizationID
+		taxRecord.User = info.Email
+		err = settingRepo.CreateTaxRecords(taxRecord)
+		if err != nil {
+			msg := "create tax record error"
+			return nil, errors.New(msg)
+		}
+	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	if customer.TaxExempt == 1 {
+		shippingTaxInfo.TaxID = ""
+	}
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
 	if err != nil {
-		msg := "get sales order error: "
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
+	var shippingTaxRecord setting.TaxRecordNew
+	shippingTaxRecord.ReferenceType = "invoice"
+	shippingTaxRecord.ReferenceID = invoiceID
+	shippingTaxRecord.ReferenceItemID = "shipping"
+	shippingTaxRecord.RecordDate = info.InvoiceDate
+	shippingTaxRecord.Tax = shippingTax
+	shippingTaxRecord.OrganizationID = info.OrganizationID
+	shippingTaxRecord.User = info.Email
+	err = settingRepo.CreateTaxRecords(shippingTaxRecord)
+	if err != nil {
+		msg := "create tax record error"
 		return nil, errors.New(msg)
 	}
 	var invoice Invoice
@@ -2297,6 +3289,9 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 	invoice.InvoiceNumber = info.InvoiceNumber
 	invoice.InvoiceDate = info.InvoiceDate
 	invoice.DueDate = info.DueDate
+	invoice.PaymentTermID = termDates.PaymentTermID
+	invoice.DiscountDate = termDates.DiscountDate
+	invoice.DiscountPercent = termDates.DiscountPercent
 	invoice.CustomerID = so.CustomerID
 	invoice.ItemCount = itemCount
 	invoice.Subtotal = itemTotal
@@ -2304,22 +3299,27 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 	invoice.DiscountValue = info.DiscountValue
 	invoice.TaxTotal = taxTotal
 	invoice.ShippingFee = info.ShippingFee
+	invoice.TaxInclusive = info.TaxInclusive
+	invoice.ShippingTaxID = shippingTaxInfo.TaxID
+	invoice.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		invoice.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		invoice.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		invoice.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		invoice.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		invoice.Total = itemTotal + info.ShippingFee + taxTotal
+		invoice.Total = itemTotal + shippingTotal + taxTotal
 	}
 	invoice.Notes = info.Notes
+	invoice.BillingAddress = so.BillingAddress
+	invoice.ShippingAddress = so.ShippingAddress
 	invoice.Status = 1
 	invoice.Created = time.Now()
 	invoice.CreatedBy = info.Email
@@ -2347,7 +3347,7 @@ func (s *salesorderService) NewInvoice(salesorderID string, info InvoiceNew) (*s
 		return nil, errors.New(msg)
 	}
 	soStatus := 1
-	if so.ShippingStatus == 3 && invoiceStatus == 3 {
+	if so.ShippingStatus >= 3 && invoiceStatus == 3 {
 		soStatus = 3
 	} else {
 		soStatus = 2
@@ -2410,6 +3410,9 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
 	repo := NewSalesorderRepository(tx)
 	isConflict, err := repo.CheckInvoiceNumberConfict(invoiceID, info.OrganizationID, info.InvoiceNumber)
 	if err != nil {
@@ -2421,11 +3424,27 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 		return nil, errors.New(msg)
 	}
 	settingRepo := setting.NewSettingRepository(tx)
+	settingService := setting.NewSettingService()
 	oldInvoice, err := repo.GetInvoiceByID(info.OrganizationID, invoiceID)
 	if err != nil {
 		msg := "get invoice error"
 		return nil, errors.New(msg)
 	}
+	customer, err := settingRepo.GetCustomerByID(oldInvoice.CustomerID, info.OrganizationID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, errors.New(msg)
+	}
+	if info.PaymentTermID == "" {
+		info.PaymentTermID = customer.PaymentTermID
+	}
+	termDates, err := settingService.GetPaymentTermDates(info.OrganizationID, info.PaymentTermID, info.InvoiceDate)
+	if err != nil {
+		return nil, err
+	}
+	if info.DueDate == "" {
+		info.DueDate = termDates.DueDate
+	}
 	oldInvoiceItems, err := repo.GetInvoiceItemList(info.OrganizationID, invoiceID)
 	if err != nil {
 		msg := "get invoice item error"
@@ -2455,10 +3474,16 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 		msg := "Invoice Update error"
 		return nil, errors.New(msg)
 	}
+	err = settingRepo.DeleteTaxRecord("invoice", invoiceID, info.Email)
+	if err != nil {
+		msg := "delete tax record error"
+		return nil, errors.New(msg)
+	}
 	itemCount := 0
 	itemTotal := 0.0
 	taxTotal := 0.0
 	itemRepo := item.NewItemRepository(tx)
+	itemService := item.NewItemService()
 	for _, itemRow := range info.Items {
 		oldSoItem, err := repo.GetSalesorderItemByID(info.OrganizationID, oldInvoice.SalesorderID, itemRow.ItemID)
 		if err != nil {
@@ -2469,34 +3494,46 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 			msg := "sales order item id error"
 			return nil, errors.New(msg)
 		}
-		_, err = itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
+		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, info.OrganizationID)
 		if err != nil {
 			msg := "item not exist"
 			return nil, errors.New(msg)
 		}
+		factor, err := itemService.GetItemUnitFactor(info.OrganizationID, itemRow.ItemID, itemRow.UnitID)
+		if err != nil {
+			return nil, err
+		}
+		if itemRow.UnitID == "" {
+			itemRow.UnitID = itemInfo.UnitID
+		}
+		quantity := itemRow.Quantity * factor
 
-		taxValue := 0.0
-		if itemRow.TaxID != "" {
-			tax, err := settingRepo.GetTaxByID(itemRow.TaxID, info.OrganizationID)
-			if err != nil {
-				msg := "tax not exist"
-				return nil, errors.New(msg)
-			}
-			taxValue = tax.TaxValue
+		taxID := itemRow.TaxID
+		if customer.TaxExempt == 1 {
+			taxID = ""
 		}
-		itemCount += itemRow.Quantity
-		itemTotal += itemRow.Rate * float64(itemRow.Quantity)
-		taxTotal += itemRow.Rate * float64(itemRow.Quantity) * taxValue / 100
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = taxID
+		taxInfo.Amount = itemRow.Rate * float64(itemRow.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
+		}
+		itemCount += quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
 
 		invoiceItemID := "invi-" + xid.New().String()
-		if oldSoItem.Quantity < oldSoItem.QuantityInvoiced+itemRow.Quantity {
-			fmt.Println(oldSoItem.Quantity, oldSoItem.QuantityInvoiced, itemRow.Quantity)
+		if oldSoItem.Quantity < oldSoItem.QuantityInvoiced+quantity {
+			fmt.Println(oldSoItem.Quantity, oldSoItem.QuantityInvoiced, quantity)
 			msg := "invoicing quantity greater than not invoiced"
 			return nil, errors.New(msg)
 		}
 		var soItem SalesorderItem
 		soItem.SalesorderItemID = oldSoItem.SalesorderItemID
-		soItem.QuantityInvoiced = oldSoItem.QuantityInvoiced + itemRow.Quantity
+		soItem.QuantityInvoiced = oldSoItem.QuantityInvoiced + quantity
 		soItem.Updated = time.Now()
 		soItem.UpdatedBy = info.Email
 
@@ -2511,12 +3548,15 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 		invoiceItem.InvoiceItemID = invoiceItemID
 		invoiceItem.SalesorderItemID = oldSoItem.SalesorderItemID
 		invoiceItem.ItemID = oldSoItem.ItemID
-		invoiceItem.Quantity = itemRow.Quantity
-		invoiceItem.Rate = itemRow.Rate
-		invoiceItem.TaxID = itemRow.TaxID
-		invoiceItem.TaxValue = taxValue
-		invoiceItem.TaxAmount = float64(itemRow.Quantity) * itemRow.Rate * taxValue / 100
-		invoiceItem.Amount = float64(itemRow.Quantity) * itemRow.Rate
+		invoiceItem.UnitID = itemRow.UnitID
+		invoiceItem.UnitQuantity = itemRow.Quantity
+		invoiceItem.UnitRate = itemRow.Rate
+		invoiceItem.Quantity = quantity
+		invoiceItem.Rate = itemRow.Rate / float64(factor)
+		invoiceItem.TaxID = taxID
+		invoiceItem.TaxValue = tax.TaxValue
+		invoiceItem.TaxAmount = tax.TaxAmount
+		invoiceItem.Amount = tax.TaxableAmount
 		invoiceItem.Status = 1
 		invoiceItem.CreatedBy = info.Email
 		invoiceItem.Created = time.Now()
@@ -2528,11 +3568,53 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 			msg := "create invoice item error: "
 			return nil, errors.New(msg)
 		}
+		var taxRecord setting.TaxRecordNew
+		taxRecord.ReferenceType = "invoice"
+		taxRecord.ReferenceID = invoiceID
+		taxRecord.ReferenceItemID = invoiceItemID
+		taxRecord.RecordDate = info.InvoiceDate
+		taxRecord.Tax = tax
+		taxRecord.OrganizationID = info.OrganizationID
+		taxRecord.User = info.Email
+		err = settingRepo.CreateTaxRecords(taxRecord)
+		if err != nil {
+			msg := "create tax record error"
+			return nil, errors.New(msg)
+		}
+	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	if customer.TaxExempt == 1 {
+		shippingTaxInfo.TaxID = ""
+	}
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
+	var shippingTaxRecord setting.TaxRecordNew
+	shippingTaxRecord.ReferenceType = "invoice"
+	shippingTaxRecord.ReferenceID = invoiceID
+	shippingTaxRecord.ReferenceItemID = "shipping"
+	shippingTaxRecord.RecordDate = info.InvoiceDate
+	shippingTaxRecord.Tax = shippingTax
+	shippingTaxRecord.OrganizationID = info.OrganizationID
+	shippingTaxRecord.User = info.Email
+	err = settingRepo.CreateTaxRecords(shippingTaxRecord)
+	if err != nil {
+		msg := "create tax record error"
+		return nil, errors.New(msg)
 	}
 	var invoice Invoice
 	invoice.InvoiceNumber = info.InvoiceNumber
 	invoice.InvoiceDate = info.InvoiceDate
 	invoice.DueDate = info.DueDate
+	invoice.PaymentTermID = termDates.PaymentTermID
+	invoice.DiscountDate = termDates.DiscountDate
+	invoice.DiscountPercent = termDates.DiscountPercent
 	invoice.CustomerID = oldInvoice.CustomerID
 	invoice.ItemCount = itemCount
 	invoice.Subtotal = itemTotal
@@ -2540,20 +3622,23 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 	invoice.DiscountValue = info.DiscountValue
 	invoice.TaxTotal = taxTotal
 	invoice.ShippingFee = info.ShippingFee
+	invoice.TaxInclusive = info.TaxInclusive
+	invoice.ShippingTaxID = shippingTaxInfo.TaxID
+	invoice.ShippingTaxAmount = shippingTax.TaxAmount
 	if info.DiscountType == 1 {
 		if info.DiscountValue < 0 || info.DiscountValue > 100 {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		invoice.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + info.ShippingFee
+		invoice.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
 	} else if info.DiscountType == 2 {
-		if info.DiscountValue > (itemTotal + taxTotal + info.ShippingFee) {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
 			msg := "discount value error"
 			return nil, errors.New(msg)
 		}
-		invoice.Total = itemTotal - info.DiscountValue + info.ShippingFee + taxTotal
+		invoice.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
 	} else {
-		invoice.Total = itemTotal + info.ShippingFee + taxTotal
+		invoice.Total = itemTotal + shippingTotal + taxTotal
 	}
 	invoice.Notes = info.Notes
 	invoice.Status = 1
@@ -2586,7 +3671,7 @@ func (s *salesorderService) UpdateInvoice(invoiceID string, info InvoiceNew) (*s
 		return nil, errors.New(msg)
 	}
 	soStatus := 1
-	if so.ShippingStatus == 3 && invoiceStatus == 3 {
+	if so.ShippingStatus >= 3 && invoiceStatus == 3 {
 		soStatus = 3
 	} else {
 		soStatus = 2
@@ -2665,6 +3750,12 @@ func (s *salesorderService) DeleteInvoice(invoiceID, organizationID, user, email
 		msg := "delete picking order error: "
 		return errors.New(msg)
 	}
+	settingRepo := setting.NewSettingRepository(tx)
+	err = settingRepo.DeleteTaxRecord("invoice", invoiceID, email)
+	if err != nil {
+		msg := "delete tax record error"
+		return errors.New(msg)
+	}
 	invoicedCount, err := repo.GetSalesorderInvoicedCount(organizationID, oldInvoice.SalesorderID)
 	if err != nil {
 		msg := "get sales order received count error: "
@@ -2700,23 +3791,18 @@ func (s *salesorderService) DeleteInvoice(invoiceID, organizationID, user, email
 	return err
 }
 
-func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentReceivedNew) (*string, error) {
-	db := database.WDB()
-	tx, err := db.Begin()
-	if err != nil {
-		msg := "begin transaction error"
-		return nil, errors.New(msg)
-	}
-	defer tx.Rollback()
+// CreatePaymentReceived records a payment of the invoice within the given
+// transaction and updates the invoice status
+func CreatePaymentReceived(tx *sql.Tx, invoiceID string, info PaymentReceivedNew) (string, error) {
 	repo := NewSalesorderRepository(tx)
 	isConflict, err := repo.CheckPaymentReceivedNumberConfict("", info.OrganizationID, info.PaymentReceivedNumber)
 	if err != nil {
 		msg := "check conflict error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "payment number exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	paymentReceivedID := "payr-" + xid.New().String()
 	settingRepo := setting.NewSettingRepository(tx)
@@ -2724,21 +3810,25 @@ func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentRec
 	invoice, err := repo.GetInvoiceByID(info.OrganizationID, invoiceID)
 	if err != nil {
 		msg := "get invoice error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	invoicedPaid, err := repo.GetInvoicePaidCount(info.OrganizationID, invoiceID)
 	if err != nil {
 		msg := "get invoice paid count error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
+	}
+	discountAmount, err := invoiceEarlyPaymentDiscount(repo, invoice, "", info)
+	if err != nil {
+		return "", err
 	}
-	if invoice.Total < invoicedPaid+info.Amount {
+	if invoice.Total < invoicedPaid+info.Amount+discountAmount {
 		msg := "pay too much error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	_, err = settingRepo.GetPaymentMethodByID(info.OrganizationID, info.PaymentMethodID)
 	if err != nil {
 		msg := "payment method not exists"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	var paymentReceived PaymentReceived
 	paymentReceived.OrganizationID = info.OrganizationID
@@ -2749,6 +3839,7 @@ func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentRec
 	paymentReceived.PaymentReceivedDate = info.PaymentReceivedDate
 	paymentReceived.PaymentMethodID = info.PaymentMethodID
 	paymentReceived.Amount = info.Amount
+	paymentReceived.DiscountAmount = discountAmount
 	paymentReceived.Notes = info.Notes
 	paymentReceived.Status = 1
 	paymentReceived.Created = time.Now()
@@ -2758,12 +3849,12 @@ func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentRec
 	err = repo.CreatePaymentReceived(paymentReceived)
 	if err != nil {
 		msg := "create payment error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	invoicedPaid, err = repo.GetInvoicePaidCount(info.OrganizationID, invoiceID)
 	if err != nil {
 		msg := "get invoice paid count error: "
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	invoiceStatus := 1
 	if invoice.Total == invoicedPaid {
@@ -2774,8 +3865,23 @@ func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentRec
 	err = repo.UpdateInvoiceStatus(invoiceID, invoiceStatus, info.Email)
 	if err != nil {
 		msg := "update invoice status error: "
+		return "", errors.New(msg)
+	}
+	return paymentReceivedID, nil
+}
+
+func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentReceivedNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
 		return nil, errors.New(msg)
 	}
+	defer tx.Rollback()
+	paymentReceivedID, err := CreatePaymentReceived(tx, invoiceID, info)
+	if err != nil {
+		return nil, err
+	}
 	tx.Commit()
 	var newEvent common.NewHistoryCreated
 	newEvent.HistoryType = "invoice"
@@ -2795,6 +3901,29 @@ func (s *salesorderService) NewPaymentReceived(invoiceID string, info PaymentRec
 	return &paymentReceivedID, err
 }
 
+// invoiceEarlyPaymentDiscount returns the early payment discount a payment
+// takes on the invoice. The discount can only be taken once per invoice.
+func invoiceEarlyPaymentDiscount(repo *salesorderRepository, invoice *InvoiceResponse, paymentReceivedID string, info PaymentReceivedNew) (float64, error) {
+	if info.TakeDiscount != 1 {
+		return 0, nil
+	}
+	discountAmount := setting.CalculateEarlyPaymentDiscount(invoice.Total, invoice.DiscountPercent, invoice.DiscountDate, info.PaymentReceivedDate)
+	if discountAmount == 0 {
+		msg := "early payment discount not available"
+		return 0, errors.New(msg)
+	}
+	discountTaken, err := repo.GetInvoiceDiscountTaken(info.OrganizationID, invoice.InvoiceID, paymentReceivedID)
+	if err != nil {
+		msg := "get invoice discount error: "
+		return 0, errors.New(msg)
+	}
+	if discountTaken > 0 {
+		msg := "early payment discount already taken"
+		return 0, errors.New(msg)
+	}
+	return discountAmount, nil
+}
+
 func (s *salesorderService) GeInvoicePaymentReceived(organizationID, invoiceID string) (float64, error) {
 	db := database.RDB()
 	query := NewSalesorderQuery(db)
@@ -2840,6 +3969,15 @@ func (s *salesorderService) UpdatePaymentReceived(paymentReceivedID string, info
 		msg := "payment not exist"
 		return nil, errors.New(msg)
 	}
+	matchedCount, err := repo.GetPaymentReceivedMatchedCount(info.OrganizationID, paymentReceivedID)
+	if err != nil {
+		msg := "get payment matched count error"
+		return nil, errors.New(msg)
+	}
+	if matchedCount > 0 {
+		msg := "payment is matched to a bank transaction, remove the match first"
+		return nil, errors.New(msg)
+	}
 	invoice, err := repo.GetInvoiceByID(info.OrganizationID, oldPayment.InvoiceID)
 	if err != nil {
 		msg := "get invoice error: "
@@ -2850,11 +3988,16 @@ func (s *salesorderService) UpdatePaymentReceived(paymentReceivedID string, info
 		msg := "payment method not exists"
 		return nil, errors.New(msg)
 	}
+	discountAmount, err := invoiceEarlyPaymentDiscount(repo, invoice, paymentReceivedID, info)
+	if err != nil {
+		return nil, err
+	}
 	var paymentReceived PaymentReceived
 	paymentReceived.PaymentReceivedNumber = info.PaymentReceivedNumber
 	paymentReceived.PaymentReceivedDate = info.PaymentReceivedDate
 	paymentReceived.PaymentMethodID = info.PaymentMethodID
 	paymentReceived.Amount = info.Amount
+	paymentReceived.DiscountAmount = discountAmount
 	paymentReceived.Notes = info.Notes
 	paymentReceived.Status = 1
 	paymentReceived.Updated = time.Now()
@@ -2916,6 +4059,15 @@ func (s *salesorderService) DeletePaymentReceived(paymentReceivedID, organizatio
 		msg := "get payment error"
 		return errors.New(msg)
 	}
+	matchedCount, err := repo.GetPaymentReceivedMatchedCount(organizationID, paymentReceivedID)
+	if err != nil {
+		msg := "get payment matched count error"
+		return errors.New(msg)
+	}
+	if matchedCount > 0 {
+		msg := "payment is matched to a bank transaction, remove the match first"
+		return errors.New(msg)
+	}
 	err = repo.DeletePaymentReceived(paymentReceivedID, email)
 	if err != nil {
 		msg := "delete picking order error: "
@@ -2955,3 +4107,2069 @@ func (s *salesorderService) DeletePaymentReceived(paymentReceivedID, organizatio
 	}
 	return err
 }
+
+// recurring invoice
+
+// recurringDate returns the date of the nth occurrence of a schedule. Month
+// based frequencies keep the day of the start date, capped at the month end.
+func recurringDate(startDate string, frequency, repeatEvery, n int) (string, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return "", err
+	}
+	if repeatEvery < 1 {
+		repeatEvery = 1
+	}
+	months := 0
+	switch frequency {
+	case 1:
+		return start.AddDate(0, 0, n*repeatEvery).Format("2006-01-02"), nil
+	case 2:
+		return start.AddDate(0, 0, 7*n*repeatEvery).Format("2006-01-02"), nil
+	case 3:
+		months = n * repeatEvery
+	case 4:
+		months = 3 * n * repeatEvery
+	default:
+		months = 12 * n * repeatEvery
+	}
+	firstDay := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	day := start.Day()
+	lastDay := firstDay.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstDay.AddDate(0, 0, day-1).Format("2006-01-02"), nil
+}
+
+// recurringOccurrences counts the occurrences of a schedule up to the end
+// date and the number of occurrences, whichever comes first
+func recurringOccurrences(startDate, endDate string, frequency, repeatEvery, occurrences int) (int, error) {
+	if endDate == "" {
+		return occurrences, nil
+	}
+	n := 0
+	for occurrences == 0 || n < occurrences {
+		date, err := recurringDate(startDate, frequency, repeatEvery, n)
+		if err != nil {
+			return 0, err
+		}
+		if date > endDate {
+			break
+		}
+		n++
+	}
+	return n, nil
+}
+
+// checkRecurringInvoiceItems checks the profile lines against the sales order. Every invoice
+// takes its quantity from the sales order line, so the occurrences still to come
+// must fit in the quantity not invoiced yet.
+func checkRecurringInvoiceItems(repo *salesorderRepository, info RecurringInvoiceNew, generatedCount int) error {
+	if info.EndDate != "" && info.EndDate < info.StartDate {
+		msg := "end date can not be earlier than start date"
+		return errors.New(msg)
+	}
+	if info.EndDate == "" && info.Occurrences == 0 {
+		msg := "end date or occurrences required"
+		return errors.New(msg)
+	}
+	occurrences, err := recurringOccurrences(info.StartDate, info.EndDate, info.Frequency, info.RepeatEvery, info.Occurrences)
+	if err != nil {
+		msg := "calculate occurrences error"
+		return errors.New(msg)
+	}
+	remaining := occurrences - generatedCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	for _, itemRow := range info.Items {
+		soItem, err := repo.GetSalesorderItemByID(info.OrganizationID, info.SalesorderID, itemRow.ItemID)
+		if err != nil {
+			msg := "sales order item not exist"
+			return errors.New(msg)
+		}
+		if soItem.SalesorderItemID != itemRow.SalesorderItemID {
+			msg := "sales order item id error"
+			return errors.New(msg)
+		}
+		if itemRow.Quantity*remaining > soItem.Quantity-soItem.QuantityInvoiced {
+			msg := fmt.Sprintf("%s: %d occurrences of %d exceed the quantity not invoiced of %d", soItem.SKU, remaining, itemRow.Quantity, soItem.Quantity-soItem.QuantityInvoiced)
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func createRecurringInvoiceItems(repo *salesorderRepository, recurringInvoiceID string, info RecurringInvoiceNew) error {
+	for _, itemRow := range info.Items {
+		var recurringInvoiceItem RecurringInvoiceItem
+		recurringInvoiceItem.OrganizationID = info.OrganizationID
+		recurringInvoiceItem.RecurringInvoiceID = recurringInvoiceID
+		recurringInvoiceItem.RecurringInvoiceItemID = "rinvi-" + xid.New().String()
+		recurringInvoiceItem.SalesorderItemID = itemRow.SalesorderItemID
+		recurringInvoiceItem.ItemID = itemRow.ItemID
+		recurringInvoiceItem.Quantity = itemRow.Quantity
+		recurringInvoiceItem.Rate = itemRow.Rate
+		recurringInvoiceItem.TaxID = itemRow.TaxID
+		recurringInvoiceItem.Status = 1
+		recurringInvoiceItem.Created = time.Now()
+		recurringInvoiceItem.CreatedBy = info.Email
+		recurringInvoiceItem.Updated = time.Now()
+		recurringInvoiceItem.UpdatedBy = info.Email
+		err := repo.CreateRecurringInvoiceItem(recurringInvoiceItem)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *salesorderService) NewRecurringInvoice(info RecurringInvoiceNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	isConflict, err := repo.CheckRecurringInvoiceConfict("", info.OrganizationID, info.ProfileName)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "recurring invoice profile name exists"
+		return nil, errors.New(msg)
+	}
+	so, err := repo.GetSalesorderByID(info.OrganizationID, info.SalesorderID)
+	if err != nil {
+		msg := "get sales order error: "
+		return nil, errors.New(msg)
+	}
+	err = checkRecurringInvoiceItems(repo, info, 0)
+	if err != nil {
+		return nil, err
+	}
+	if info.PaymentTermID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	recurringInvoiceID := "rinv-" + xid.New().String()
+	err = createRecurringInvoiceItems(repo, recurringInvoiceID, info)
+	if err != nil {
+		msg := "create recurring invoice item error: "
+		return nil, errors.New(msg)
+	}
+	var recurringInvoice RecurringInvoice
+	recurringInvoice.OrganizationID = info.OrganizationID
+	recurringInvoice.RecurringInvoiceID = recurringInvoiceID
+	recurringInvoice.ProfileName = info.ProfileName
+	recurringInvoice.SalesorderID = info.SalesorderID
+	recurringInvoice.CustomerID = so.CustomerID
+	recurringInvoice.InvoicePrefix = info.InvoicePrefix
+	recurringInvoice.Frequency = info.Frequency
+	recurringInvoice.RepeatEvery = info.RepeatEvery
+	if recurringInvoice.RepeatEvery == 0 {
+		recurringInvoice.RepeatEvery = 1
+	}
+	recurringInvoice.StartDate = info.StartDate
+	recurringInvoice.EndDate = info.EndDate
+	recurringInvoice.Occurrences = info.Occurrences
+	recurringInvoice.GeneratedCount = 0
+	recurringInvoice.NextDate = info.StartDate
+	recurringInvoice.PaymentTermID = info.PaymentTermID
+	recurringInvoice.DiscountType = info.DiscountType
+	recurringInvoice.DiscountValue = info.DiscountValue
+	recurringInvoice.ShippingFee = info.ShippingFee
+	recurringInvoice.ShippingTaxID = info.ShippingTaxID
+	recurringInvoice.TaxInclusive = info.TaxInclusive
+	recurringInvoice.Notes = info.Notes
+	recurringInvoice.Status = info.Status
+	recurringInvoice.Created = time.Now()
+	recurringInvoice.CreatedBy = info.Email
+	recurringInvoice.Updated = time.Now()
+	recurringInvoice.UpdatedBy = info.Email
+	err = repo.CreateRecurringInvoice(recurringInvoice)
+	if err != nil {
+		msg := "create recurring invoice error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &recurringInvoiceID, nil
+}
+
+func (s *salesorderService) GetRecurringInvoiceList(filter RecurringInvoiceFilter) (int, *[]RecurringInvoiceResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetRecurringInvoiceCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetRecurringInvoiceList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *salesorderService) GetRecurringInvoiceByID(organizationID, id string) (*RecurringInvoiceResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	recurringInvoice, err := query.GetRecurringInvoiceByID(organizationID, id)
+	if err != nil {
+		msg := "get recurring invoice error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return recurringInvoice, nil
+}
+
+func (s *salesorderService) GetRecurringInvoiceItemList(recurringInvoiceID, organizationID string) (*[]RecurringInvoiceItemResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	_, err := query.GetRecurringInvoiceByID(organizationID, recurringInvoiceID)
+	if err != nil {
+		msg := "get recurring invoice error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetRecurringInvoiceItemList(organizationID, recurringInvoiceID)
+	return list, err
+}
+
+func (s *salesorderService) GetRecurringInvoiceRunList(recurringInvoiceID, organizationID string) (*[]RecurringInvoiceRunResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	_, err := query.GetRecurringInvoiceByID(organizationID, recurringInvoiceID)
+	if err != nil {
+		msg := "get recurring invoice error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetRecurringInvoiceRunList(organizationID, recurringInvoiceID)
+	return list, err
+}
+
+func (s *salesorderService) UpdateRecurringInvoice(recurringInvoiceID string, info RecurringInvoiceNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	isConflict, err := repo.CheckRecurringInvoiceConfict(recurringInvoiceID, info.OrganizationID, info.ProfileName)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "recurring invoice profile name exists"
+		return nil, errors.New(msg)
+	}
+	oldRecurringInvoice, err := repo.GetRecurringInvoiceByID(info.OrganizationID, recurringInvoiceID)
+	if err != nil {
+		msg := "recurring invoice not exist"
+		return nil, errors.New(msg)
+	}
+	nextDate := info.StartDate
+	if oldRecurringInvoice.GeneratedCount > 0 {
+		if oldRecurringInvoice.StartDate != info.StartDate || oldRecurringInvoice.SalesorderID != info.SalesorderID || oldRecurringInvoice.Frequency != info.Frequency {
+			msg := "schedule can not be changed after invoices generated"
+			return nil, errors.New(msg)
+		}
+		nextDate, err = recurringDate(info.StartDate, info.Frequency, info.RepeatEvery, oldRecurringInvoice.GeneratedCount)
+		if err != nil {
+			msg := "calculate next date error"
+			return nil, errors.New(msg)
+		}
+	}
+	so, err := repo.GetSalesorderByID(info.OrganizationID, info.SalesorderID)
+	if err != nil {
+		msg := "get sales order error: "
+		return nil, errors.New(msg)
+	}
+	err = checkRecurringInvoiceItems(repo, info, oldRecurringInvoice.GeneratedCount)
+	if err != nil {
+		return nil, err
+	}
+	if info.PaymentTermID != "" {
+		settingRepo := setting.NewSettingRepository(tx)
+		_, err = settingRepo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	err = repo.DeleteRecurringInvoiceItem(recurringInvoiceID, info.Email)
+	if err != nil {
+		msg := "delete recurring invoice item error: "
+		return nil, errors.New(msg)
+	}
+	err = createRecurringInvoiceItems(repo, recurringInvoiceID, info)
+	if err != nil {
+		msg := "create recurring invoice item error: "
+		return nil, errors.New(msg)
+	}
+	var recurringInvoice RecurringInvoice
+	recurringInvoice.ProfileName = info.ProfileName
+	recurringInvoice.SalesorderID = info.SalesorderID
+	recurringInvoice.CustomerID = so.CustomerID
+	recurringInvoice.InvoicePrefix = info.InvoicePrefix
+	recurringInvoice.Frequency = info.Frequency
+	recurringInvoice.RepeatEvery = info.RepeatEvery
+	if recurringInvoice.RepeatEvery == 0 {
+		recurringInvoice.RepeatEvery = 1
+	}
+	recurringInvoice.StartDate = info.StartDate
+	recurringInvoice.EndDate = info.EndDate
+	recurringInvoice.Occurrences = info.Occurrences
+	recurringInvoice.NextDate = nextDate
+	recurringInvoice.PaymentTermID = info.PaymentTermID
+	recurringInvoice.DiscountType = info.DiscountType
+	recurringInvoice.DiscountValue = info.DiscountValue
+	recurringInvoice.ShippingFee = info.ShippingFee
+	recurringInvoice.ShippingTaxID = info.ShippingTaxID
+	recurringInvoice.TaxInclusive = info.TaxInclusive
+	recurringInvoice.Notes = info.Notes
+	recurringInvoice.Status = info.Status
+	if (recurringInvoice.Occurrences > 0 && oldRecurringInvoice.GeneratedCount >= recurringInvoice.Occurrences) || (recurringInvoice.EndDate != "" && nextDate > recurringInvoice.EndDate) {
+		recurringInvoice.Status = 3
+	}
+	recurringInvoice.Updated = time.Now()
+	recurringInvoice.UpdatedBy = info.Email
+	err = repo.UpdateRecurringInvoice(recurringInvoiceID, recurringInvoice)
+	if err != nil {
+		msg := "update recurring invoice error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &recurringInvoiceID, nil
+}
+
+func (s *salesorderService) DeleteRecurringInvoice(recurringInvoiceID, organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	_, err = repo.GetRecurringInvoiceByID(organizationID, recurringInvoiceID)
+	if err != nil {
+		msg := "recurring invoice not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteRecurringInvoice(recurringInvoiceID, email)
+	if err != nil {
+		msg := "delete recurring invoice error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+// RunRecurringInvoice generates the invoices of every period of the profile
+// due up to today. Periods that already have an invoice are skipped, so the
+// run can be repeated safely.
+func (s *salesorderService) RunRecurringInvoice(recurringInvoiceID, organizationID, user, email string) (*[]RecurringInvoiceRunResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	recurringInvoice, err := query.GetRecurringInvoiceByID(organizationID, recurringInvoiceID)
+	if err != nil {
+		msg := "recurring invoice not exist"
+		return nil, errors.New(msg)
+	}
+	if recurringInvoice.Status != 1 {
+		msg := "recurring invoice is not active"
+		return nil, errors.New(msg)
+	}
+	runs, err := s.generateRecurringInvoices(recurringInvoice, time.Now().Format("2006-01-02"), user, email)
+	return runs, err
+}
+
+// RunDueRecurringInvoices generates the due invoices of all active profiles.
+func (s *salesorderService) RunDueRecurringInvoices() error {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	today := time.Now().Format("2006-01-02")
+	list, err := query.GetDueRecurringInvoiceList(today)
+	if err != nil {
+		return err
+	}
+	for _, recurringInvoice := range *list {
+		profile := recurringInvoice
+		_, err := s.generateRecurringInvoices(&profile, today, "system", profile.UpdatedBy)
+		if err != nil {
+			err = notifyRecurringInvoiceFailure(&profile, err)
+			if err != nil {
+				fmt.Println("recurring invoice " + profile.RecurringInvoiceID + " error: " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// notifyRecurringInvoiceFailure raises a notification when the job could not
+// generate a due invoice, the failed run itself is kept on the run list
+func notifyRecurringInvoiceFailure(profile *RecurringInvoiceResponse, runErr error) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	var notification common.NotificationNew
+	notification.NotificationType = "recurring_invoice"
+	notification.ReferenceID = profile.RecurringInvoiceID
+	notification.Title = "Recurring invoice failed: " + profile.ProfileName
+	notification.Description = runErr.Error()
+	notification.OrganizationID = profile.OrganizationID
+	notification.Email = profile.UpdatedBy
+	err = common.RaiseNotification(common.NewCommonRepository(tx), notification)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *salesorderService) generateRecurringInvoices(profile *RecurringInvoiceResponse, today, user, email string) (*[]RecurringInvoiceRunResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	items, err := query.GetRecurringInvoiceItemList(profile.OrganizationID, profile.RecurringInvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	var runs []RecurringInvoiceRunResponse
+	generatedCount := profile.GeneratedCount
+	nextDate := profile.NextDate
+	status := profile.Status
+	for status == 1 && nextDate <= today {
+		if (profile.Occurrences > 0 && generatedCount >= profile.Occurrences) || (profile.EndDate != "" && nextDate > profile.EndDate) {
+			status = 3
+			break
+		}
+		invoiceID, runErr := s.generateRecurringInvoice(profile, items, nextDate, user, email)
+		run, err := s.saveRecurringInvoiceRun(profile, nextDate, invoiceID, runErr, email)
+		if err != nil {
+			return &runs, err
+		}
+		runs = append(runs, *run)
+		if runErr != nil {
+			return &runs, runErr
+		}
+		generatedCount++
+		nextDate, err = recurringDate(profile.StartDate, profile.Frequency, profile.RepeatEvery, generatedCount)
+		if err != nil {
+			return &runs, err
+		}
+		if (profile.Occurrences > 0 && generatedCount >= profile.Occurrences) || (profile.EndDate != "" && nextDate > profile.EndDate) {
+			status = 3
+		}
+		err = s.updateRecurringInvoiceSchedule(profile.RecurringInvoiceID, nextDate, generatedCount, status, email)
+		if err != nil {
+			return &runs, err
+		}
+	}
+	if status != profile.Status {
+		err = s.updateRecurringInvoiceSchedule(profile.RecurringInvoiceID, nextDate, generatedCount, status, email)
+		if err != nil {
+			return &runs, err
+		}
+	}
+	return &runs, nil
+}
+
+func (s *salesorderService) generateRecurringInvoice(profile *RecurringInvoiceResponse, items *[]RecurringInvoiceItemResponse, periodDate, user, email string) (string, error) {
+	invoiceNumber := profile.InvoicePrefix + "-" + strings.ReplaceAll(periodDate, "-", "")
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return "", errors.New(msg)
+	}
+	repo := NewSalesorderRepository(tx)
+	run, err := repo.GetRecurringInvoiceRunByPeriod(profile.OrganizationID, profile.RecurringInvoiceID, periodDate)
+	if err == nil && run.Status == 1 {
+		tx.Rollback()
+		return run.InvoiceID, nil
+	}
+	invoiceID, err := repo.GetInvoiceIDByNumber(profile.OrganizationID, invoiceNumber)
+	tx.Rollback()
+	if err == nil {
+		return invoiceID, nil
+	}
+	var info InvoiceNew
+	info.InvoiceNumber = invoiceNumber
+	info.InvoiceDate = periodDate
+	info.PaymentTermID = profile.PaymentTermID
+	info.DiscountType = profile.DiscountType
+	info.DiscountValue = profile.DiscountValue
+	info.ShippingFee = profile.ShippingFee
+	info.ShippingTaxID = profile.ShippingTaxID
+	info.TaxInclusive = profile.TaxInclusive
+	info.Notes = profile.Notes
+	for _, itemRow := range *items {
+		var invoiceItem InvoiceItemNew
+		invoiceItem.SalesorderItemID = itemRow.SalesorderItemID
+		invoiceItem.ItemID = itemRow.ItemID
+		invoiceItem.Quantity = itemRow.Quantity
+		invoiceItem.Rate = itemRow.Rate
+		invoiceItem.TaxID = itemRow.TaxID
+		info.Items = append(info.Items, invoiceItem)
+	}
+	info.OrganizationID = profile.OrganizationID
+	info.User = user
+	info.Email = email
+	newInvoiceID, err := s.NewInvoice(profile.SalesorderID, info)
+	if err != nil {
+		return "", err
+	}
+	return *newInvoiceID, nil
+}
+
+func (s *salesorderService) saveRecurringInvoiceRun(profile *RecurringInvoiceResponse, periodDate, invoiceID string, runErr error, email string) (*RecurringInvoiceRunResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	status := 1
+	message := "Invoice Created"
+	if runErr != nil {
+		status = 2
+		message = runErr.Error()
+	}
+	oldRun, err := repo.GetRecurringInvoiceRunByPeriod(profile.OrganizationID, profile.RecurringInvoiceID, periodDate)
+	if err == nil {
+		err = repo.UpdateRecurringInvoiceRun(oldRun.RecurringInvoiceRunID, invoiceID, message, status, email)
+		if err != nil {
+			msg := "update recurring invoice run error: "
+			return nil, errors.New(msg)
+		}
+	} else {
+		var run RecurringInvoiceRun
+		run.OrganizationID = profile.OrganizationID
+		run.RecurringInvoiceID = profile.RecurringInvoiceID
+		run.RecurringInvoiceRunID = "rinvr-" + xid.New().String()
+		run.PeriodDate = periodDate
+		run.InvoiceID = invoiceID
+		run.Message = message
+		run.Status = status
+		run.Created = time.Now()
+		run.CreatedBy = email
+		run.Updated = time.Now()
+		run.UpdatedBy = email
+		err = repo.CreateRecurringInvoiceRun(run)
+		if err != nil {
+			msg := "create recurring invoice run error: "
+			return nil, errors.New(msg)
+		}
+	}
+	res, err := repo.GetRecurringInvoiceRunByPeriod(profile.OrganizationID, profile.RecurringInvoiceID, periodDate)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return res, nil
+}
+
+func (s *salesorderService) updateRecurringInvoiceSchedule(recurringInvoiceID, nextDate string, generatedCount, status int, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	err = repo.UpdateRecurringInvoiceSchedule(recurringInvoiceID, nextDate, generatedCount, status, email)
+	if err != nil {
+		msg := "update recurring invoice schedule error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+//shipping label
+
+var weightToKg = map[string]float64{
+	"kg": 1, "kgs": 1, "kilogram": 1, "kilograms": 1,
+	"g": 0.001, "gram": 0.001, "grams": 0.001,
+	"lb": 0.45359237, "lbs": 0.45359237, "pound": 0.45359237, "pounds": 0.45359237,
+	"oz": 0.028349523125, "ounce": 0.028349523125, "ounces": 0.028349523125,
+}
+
+var lengthToCm = map[string]float64{
+	"cm": 1, "centimeter": 1, "centimeters": 1,
+	"mm": 0.1, "millimeter": 0.1, "millimeters": 0.1,
+	"m": 100, "meter": 100, "meters": 100,
+	"in": 2.54, "inch": 2.54, "inches": 2.54,
+	"ft": 30.48, "foot": 30.48, "feet": 30.48,
+}
+
+// unitFactor looks up the conversion of a unit by its name, unknown units
+// are taken as kg and cm already.
+func unitFactor(factors map[string]float64, unitName string) float64 {
+	factor, ok := factors[strings.ToLower(strings.TrimSpace(unitName))]
+	if !ok {
+		return 1
+	}
+	return factor
+}
+
+// packageParcel sums the item weights of a package. The box takes the
+// largest item footprint and is tall enough to hold the volume of all items.
+func packageParcel(items []PackageParcelItemResponse) carrier.Parcel {
+	var parcel carrier.Parcel
+	volume := 0.0
+	for _, itemRow := range items {
+		quantity := float64(itemRow.Quantity)
+		parcel.Weight += itemRow.Weight * unitFactor(weightToKg, itemRow.WeightUnitName) * quantity
+		dimension := unitFactor(lengthToCm, itemRow.DimensionUnitName)
+		length, width, height := itemRow.Length*dimension, itemRow.Width*dimension, itemRow.Height*dimension
+		parcel.Length = math.Max(parcel.Length, length)
+		parcel.Width = math.Max(parcel.Width, width)
+		parcel.Height = math.Max(parcel.Height, height)
+		volume += length * width * height * quantity
+	}
+	if parcel.Length > 0 && parcel.Width > 0 {
+		parcel.Height = math.Max(parcel.Height, math.Ceil(volume/(parcel.Length*parcel.Width)*10)/10)
+	}
+	parcel.Weight = math.Round(parcel.Weight*1000) / 1000
+	return parcel
+}
+
+func (s *salesorderService) newShipment(organizationID string, packageIDs []string, config map[string]string) (*carrier.Shipment, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	var shipment carrier.Shipment
+	shipment.From = carrier.ShipFrom(config)
+	for _, packageID := range packageIDs {
+		packageInfo, err := query.GetPackageByID(organizationID, packageID)
+		if err != nil {
+			msg := "package not exist"
+			return nil, errors.New(msg)
+		}
+		items, err := query.GetPackageParcelItemList(organizationID, packageID)
+		if err != nil {
+			msg := "get package items error"
+			return nil, errors.New(msg)
+		}
+		parcel := packageParcel(*items)
+		parcel.Reference = packageInfo.PackageNumber
+		shipment.Parcels = append(shipment.Parcels, parcel)
+		if shipment.To.Name != "" {
+			continue
+		}
+		salesorder, err := query.GetSalesorderByID(organizationID, packageInfo.SalesorderID)
+		if err != nil {
+			msg := "salesorder not exist"
+			return nil, errors.New(msg)
+		}
+		address := salesorder.ShippingAddress
+		if address.Name == "" {
+			settingQuery := setting.NewSettingQuery(db)
+			customer, err := settingQuery.GetCustomerByID(organizationID, salesorder.CustomerID)
+			if err != nil {
+				msg := "customer not exist"
+				return nil, errors.New(msg)
+			}
+			address.Name = customer.Name
+			address.Address1 = customer.Address1
+			address.Address2 = customer.Address2
+			address.City = customer.City
+			address.State = customer.State
+			address.Zip = customer.Zip
+			address.Country = customer.Country
+			address.Phone = customer.Phone
+		}
+		shipment.To.Name = address.Name
+		shipment.To.Address1 = address.Address1
+		shipment.To.Address2 = address.Address2
+		shipment.To.City = address.City
+		shipment.To.State = address.State
+		shipment.To.Zip = address.Zip
+		shipment.To.Country = address.Country
+		shipment.To.Phone = address.Phone
+	}
+	return &shipment, nil
+}
+
+func (s *salesorderService) GetShippingRates(info ShippingRateNew) ([]carrier.Rate, error) {
+	db := database.RDB()
+	settingQuery := setting.NewSettingQuery(db)
+	carrierInfo, err := settingQuery.GetCarrierByID(info.OrganizationID, info.CarrierID)
+	if err != nil {
+		msg := "carrier not exist"
+		return nil, errors.New(msg)
+	}
+	adapter, config, err := setting.NewCarrierAdapter(carrierInfo)
+	if err != nil {
+		return nil, err
+	}
+	shipment, err := s.newShipment(info.OrganizationID, info.PackageID, config)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.Rates(*shipment)
+}
+
+// requestShippingLabel buys a label for the packages, the adapter is returned
+// so the caller can void the label when saving it fails.
+func (s *salesorderService) requestShippingLabel(organizationID, carrierID string, packageIDs []string, serviceCode, format, reference string) (carrier.Adapter, *carrier.Label, error) {
+	db := database.RDB()
+	settingQuery := setting.NewSettingQuery(db)
+	carrierInfo, err := settingQuery.GetCarrierByID(organizationID, carrierID)
+	if err != nil {
+		msg := "carrier not exist"
+		return nil, nil, errors.New(msg)
+	}
+	adapter, config, err := setting.NewCarrierAdapter(carrierInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+	shipment, err := s.newShipment(organizationID, packageIDs, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	var request carrier.LabelRequest
+	request.Shipment = *shipment
+	request.ServiceCode = serviceCode
+	request.Format = format
+	request.Reference = reference
+	label, err := adapter.CreateLabel(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return adapter, label, nil
+}
+
+func (s *salesorderService) NewShippingLabel(shippingorderID string, info ShippingLabelNew) (*ShippingLabelResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	shippingorder, err := repo.GetShippingorderByID(shippingorderID, info.OrganizationID)
+	if err != nil {
+		msg := "shipping order not exist"
+		return nil, errors.New(msg)
+	}
+	if shippingorder.CarrierID == "" {
+		msg := "shipping order has no carrier"
+		return nil, errors.New(msg)
+	}
+	_, err = repo.GetActiveShippingLabel(info.OrganizationID, shippingorderID)
+	if err == nil {
+		msg := "shipping label exists, void it first"
+		return nil, errors.New(msg)
+	}
+	adapter, label, err := s.requestShippingLabel(info.OrganizationID, shippingorder.CarrierID, strings.Split(shippingorder.PackageID, ","), info.ServiceCode, info.LabelFormat, shippingorder.ShippingorderNumber)
+	if err != nil {
+		msg := "request shipping label error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	err = s.saveShippingLabel(repo, info.OrganizationID, shippingorderID, shippingorder.CarrierID, label, info.Email)
+	if err != nil {
+		adapter.Void(label.TrackingNumber)
+		return nil, err
+	}
+	tx.Commit()
+	return s.GetShippingLabel(info.OrganizationID, shippingorderID)
+}
+
+func (s *salesorderService) saveShippingLabel(repo *salesorderRepository, organizationID, shippingorderID, carrierID string, label *carrier.Label, email string) error {
+	var shippingLabel ShippingLabel
+	shippingLabel.OrganizationID = organizationID
+	shippingLabel.ShippingLabelID = "shl-" + xid.New().String()
+	shippingLabel.ShippingorderID = shippingorderID
+	shippingLabel.CarrierID = carrierID
+	shippingLabel.ServiceCode = label.ServiceCode
+	shippingLabel.TrackingNumber = label.TrackingNumber
+	shippingLabel.Format = label.Format
+	shippingLabel.Data = label.Data
+	shippingLabel.Cost = label.Cost
+	shippingLabel.Status = 1
+	shippingLabel.Created = time.Now()
+	shippingLabel.CreatedBy = email
+	shippingLabel.Updated = time.Now()
+	shippingLabel.UpdatedBy = email
+	err := repo.CreateShippingLabel(shippingLabel)
+	if err != nil {
+		msg := "create shipping label error: " + err.Error()
+		return errors.New(msg)
+	}
+	err = repo.UpdateShippingorderTrackingNumber(shippingorderID, label.TrackingNumber, email)
+	if err != nil {
+		msg := "update tracking number error: " + err.Error()
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *salesorderService) GetShippingLabelList(organizationID, shippingorderID string) (*[]ShippingLabelResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	list, err := query.GetShippingLabelList(organizationID, shippingorderID)
+	return list, err
+}
+
+func (s *salesorderService) GetShippingLabel(organizationID, shippingorderID string) (*ShippingLabelResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	label, err := query.GetShippingLabel(organizationID, shippingorderID)
+	if err != nil {
+		msg := "shipping label not exist"
+		return nil, errors.New(msg)
+	}
+	return label, nil
+}
+
+func (s *salesorderService) VoidShippingLabel(organizationID, shippingorderID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	settingRepo := setting.NewSettingRepository(tx)
+	label, err := repo.GetActiveShippingLabel(organizationID, shippingorderID)
+	if err != nil {
+		msg := "shipping label not exist"
+		return errors.New(msg)
+	}
+	carrierInfo, err := settingRepo.GetCarrierByID(organizationID, label.CarrierID)
+	if err != nil {
+		msg := "carrier not exist"
+		return errors.New(msg)
+	}
+	adapter, _, err := setting.NewCarrierAdapter(carrierInfo)
+	if err != nil {
+		return err
+	}
+	err = repo.VoidShippingLabel(label.ShippingLabelID, email)
+	if err != nil {
+		msg := "void shipping label error: " + err.Error()
+		return errors.New(msg)
+	}
+	err = repo.UpdateShippingorderTrackingNumber(shippingorderID, "", email)
+	if err != nil {
+		msg := "update tracking number error: " + err.Error()
+		return errors.New(msg)
+	}
+	err = adapter.Void(label.TrackingNumber)
+	if err != nil {
+		msg := "void shipping label error: " + err.Error()
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *salesorderService) GetShippingorderTracking(organizationID, shippingorderID string) (*carrier.Tracking, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	shippingorder, err := query.GetShippingorderByID(organizationID, shippingorderID)
+	if err != nil {
+		msg := "shipping order not exist"
+		return nil, errors.New(msg)
+	}
+	if shippingorder.TrackingNumber == "" {
+		msg := "shipping order has no tracking number"
+		return nil, errors.New(msg)
+	}
+	settingQuery := setting.NewSettingQuery(db)
+	carrierInfo, err := settingQuery.GetCarrierByID(organizationID, shippingorder.CarrierID)
+	if err != nil {
+		msg := "carrier not exist"
+		return nil, errors.New(msg)
+	}
+	adapter, _, err := setting.NewCarrierAdapter(carrierInfo)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.Track(shippingorder.TrackingNumber)
+}
+
+//tracking
+
+var trackingStatusNames = map[int]string{
+	carrier.StatusLabelCreated:   "Label Created",
+	carrier.StatusPickedUp:       "Picked Up",
+	carrier.StatusInTransit:      "In Transit",
+	carrier.StatusOutForDelivery: "Out For Delivery",
+	carrier.StatusDelivered:      "Delivered",
+	carrier.StatusException:      "Exception",
+}
+
+// applyTracking saves the new events of a shipment and moves the shipping
+// order to the latest status. A delivered shipment marks its sales orders
+// delivered once all their shipments are delivered. The history messages are
+// returned to be published after commit.
+func (s *salesorderService) applyTracking(repo *salesorderRepository, shippingorder *ShippingorderResponse, tracking *carrier.Tracking, source int, user, email string) (int, [][]byte, error) {
+	var msgs [][]byte
+	saved := 0
+	for _, event := range tracking.Events {
+		eventTime := event.EventTime.In(time.Local).Format("2006-01-02 15:04:05")
+		exist, err := repo.CheckTrackingEventExist(shippingorder.ShippingorderID, eventTime, event.Status)
+		if err != nil {
+			msg := "check tracking event error: " + err.Error()
+			return 0, nil, errors.New(msg)
+		}
+		if exist {
+			continue
+		}
+		var trackingEvent ShippingTrackingEvent
+		trackingEvent.OrganizationID = shippingorder.OrganizationID
+		trackingEvent.TrackingEventID = "shte-" + xid.New().String()
+		trackingEvent.ShippingorderID = shippingorder.ShippingorderID
+		trackingEvent.TrackingNumber = shippingorder.TrackingNumber
+		trackingEvent.EventTime = eventTime
+		trackingEvent.TrackingStatus = event.Status
+		trackingEvent.Description = event.Description
+		trackingEvent.Location = event.Location
+		trackingEvent.Source = source
+		trackingEvent.Status = 1
+		trackingEvent.Created = time.Now()
+		trackingEvent.CreatedBy = email
+		trackingEvent.Updated = time.Now()
+		trackingEvent.UpdatedBy = email
+		err = repo.CreateShippingTrackingEvent(trackingEvent)
+		if err != nil {
+			msg := "create tracking event error: " + err.Error()
+			return 0, nil, errors.New(msg)
+		}
+		saved++
+	}
+	if tracking.Status == 0 || tracking.Status == shippingorder.TrackingStatus || shippingorder.TrackingStatus == carrier.StatusDelivered {
+		return saved, msgs, nil
+	}
+	err := repo.UpdateShippingorderTrackingStatus(shippingorder.ShippingorderID, tracking.Status, email)
+	if err != nil {
+		msg := "update tracking status error: " + err.Error()
+		return 0, nil, errors.New(msg)
+	}
+	if tracking.Status != carrier.StatusDelivered && tracking.Status != carrier.StatusException {
+		return saved, msgs, nil
+	}
+	details, err := repo.GetShippingorderDetailList(shippingorder.ShippingorderID)
+	if err != nil {
+		msg := "get shipping order details error"
+		return 0, nil, errors.New(msg)
+	}
+	var salesorders []string
+	for _, detail := range *details {
+		packageInfo, err := repo.GetPackageByID(shippingorder.OrganizationID, detail.PackageID)
+		if err != nil {
+			msg := "get package error"
+			return 0, nil, errors.New(msg)
+		}
+		salesorderUpdated := false
+		for _, salesorderID := range salesorders {
+			if salesorderID == packageInfo.SalesorderID {
+				salesorderUpdated = true
+			}
+		}
+		if salesorderUpdated {
+			continue
+		}
+		salesorders = append(salesorders, packageInfo.SalesorderID)
+		var newEvent common.NewHistoryCreated
+		newEvent.HistoryType = "salesorder"
+		newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+		newEvent.HistoryBy = user
+		newEvent.ReferenceID = packageInfo.SalesorderID
+		newEvent.Description = "Shipment " + trackingStatusNames[tracking.Status] + ": " + shippingorder.ShippingorderNumber
+		newEvent.OrganizationID = shippingorder.OrganizationID
+		newEvent.Email = email
+		msg, _ := json.Marshal(newEvent)
+		msgs = append(msgs, msg)
+		if tracking.Status != carrier.StatusDelivered {
+			continue
+		}
+		salesorderInfo, err := repo.GetSalesorderByID(shippingorder.OrganizationID, packageInfo.SalesorderID)
+		if err != nil {
+			msg := "salesorder not exist"
+			return 0, nil, errors.New(msg)
+		}
+		if salesorderInfo.ShippingStatus != 3 {
+			continue
+		}
+		undelivered, err := repo.GetSalesorderUndeliveredCount(shippingorder.OrganizationID, packageInfo.SalesorderID)
+		if err != nil {
+			msg := "get sales order undelivered count error"
+			return 0, nil, errors.New(msg)
+		}
+		if undelivered > 0 {
+			continue
+		}
+		err = repo.UpdateSalesorderShippingStatus(packageInfo.SalesorderID, 4, email) //delivered
+		if err != nil {
+			msg := "update salesorder shipping status error"
+			return 0, nil, errors.New(msg)
+		}
+	}
+	return saved, msgs, nil
+}
+
+func publishHistory(msgs [][]byte) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	rabbit, _ := queue.GetConn()
+	for _, msgRow := range msgs {
+		err := rabbit.Publish("NewHistoryCreated", msgRow)
+		if err != nil {
+			msg := "create event NewHistoryCreated error"
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+// ReceiveTrackingWebhook saves the tracking updates a carrier pushes, the
+// token has to match the webhook_token of the carrier config.
+func (s *salesorderService) ReceiveTrackingWebhook(carrierID, token string, body []byte) (int, error) {
+	db := database.RDB()
+	settingQuery := setting.NewSettingQuery(db)
+	carrierInfo, err := settingQuery.GetWebhookCarrierByID(carrierID)
+	if err != nil {
+		msg := "carrier not exist"
+		return 0, errors.New(msg)
+	}
+	adapter, config, err := setting.NewCarrierAdapter(carrierInfo)
+	if err != nil {
+		return 0, err
+	}
+	if config["webhook_token"] == "" || subtle.ConstantTimeCompare([]byte(config["webhook_token"]), []byte(token)) != 1 {
+		msg := "webhook token error"
+		return 0, errors.New(msg)
+	}
+	var trackings []carrier.Tracking
+	if parser, ok := adapter.(carrier.WebhookParser); ok {
+		trackings, err = parser.ParseWebhook(body)
+	} else {
+		trackings, err = carrier.ParseWebhook(body)
+	}
+	if err != nil {
+		return 0, err
+	}
+	tx, err := database.WDB().Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	saved := 0
+	var msgs [][]byte
+	for _, tracking := range trackings {
+		shippingorder, err := repo.GetShippingorderByTrackingNumber(carrierInfo.OrganizationID, carrierID, tracking.TrackingNumber)
+		if err != nil {
+			// the carrier may push shipments that were not sent through this system
+			continue
+		}
+		count, eventMsgs, err := s.applyTracking(repo, shippingorder, &tracking, 1, carrierInfo.Name, "webhook")
+		if err != nil {
+			return 0, err
+		}
+		saved += count
+		msgs = append(msgs, eventMsgs...)
+	}
+	tx.Commit()
+	return saved, publishHistory(msgs)
+}
+
+// PollShipmentTracking asks the carriers about the shipments sent in the last
+// 60 days that are not delivered yet
+func (s *salesorderService) PollShipmentTracking() error {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	settingQuery := setting.NewSettingQuery(db)
+	since := time.Now().AddDate(0, 0, -60).Format("2006-01-02")
+	list, err := query.GetTrackingShippingorderList(since)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, row := range *list {
+		carrierInfo, err := settingQuery.GetCarrierByID(row.OrganizationID, row.CarrierID)
+		if err != nil {
+			continue
+		}
+		adapter, _, err := setting.NewCarrierAdapter(carrierInfo)
+		if err != nil {
+			errs = append(errs, row.TrackingNumber+": "+err.Error())
+			continue
+		}
+		tracking, err := adapter.Track(row.TrackingNumber)
+		if err != nil {
+			errs = append(errs, row.TrackingNumber+": "+err.Error())
+			continue
+		}
+		err = s.saveTracking(row.OrganizationID, row.ShippingorderID, tracking)
+		if err != nil {
+			errs = append(errs, row.TrackingNumber+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		msg := "poll tracking error: " + strings.Join(errs, "; ")
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *salesorderService) saveTracking(organizationID, shippingorderID string, tracking *carrier.Tracking) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	shippingorder, err := repo.GetShippingorderByID(shippingorderID, organizationID)
+	if err != nil {
+		msg := "shipping order not exist"
+		return errors.New(msg)
+	}
+	_, msgs, err := s.applyTracking(repo, shippingorder, tracking, 2, "System", "system")
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return publishHistory(msgs)
+}
+
+func (s *salesorderService) GetShippingTrackingEventList(organizationID, shippingorderID string) (*[]ShippingTrackingEventResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	list, err := query.GetShippingTrackingEventList(organizationID, shippingorderID)
+	return list, err
+}
+
+//approval
+
+func salesorderHistory(salesorderID, organizationID, description, user, email string) []byte {
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "salesorder"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = user
+	newEvent.ReferenceID = salesorderID
+	newEvent.Description = description
+	newEvent.OrganizationID = organizationID
+	newEvent.Email = email
+	msg, _ := json.Marshal(newEvent)
+	return msg
+}
+
+// ApproveSalesorder approves or rejects the current approval step of a sales
+// order, the order can be confirmed once all steps are approved. A confirmed
+// order held because its total went up is released on the last approval.
+func (s *salesorderService) ApproveSalesorder(salesorderID string, info common.ApprovalAction) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	oldSalesorder, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "Salesorder not exist"
+		return errors.New(msg)
+	}
+	if oldSalesorder.ApprovalStatus != 2 {
+		msg := "Salesorder not pending approval"
+		return errors.New(msg)
+	}
+	info.DocumentType = "salesorder"
+	info.DocumentID = salesorderID
+	status, description, err := common.ActApproval(common.NewCommonRepository(tx), info)
+	if err != nil {
+		return err
+	}
+	if status != 1 {
+		approvalStatus := 3 //APPROVED
+		if status == 3 {
+			approvalStatus = 4 //REJECTED
+		}
+		err = repo.UpdateSalesorderApprovalStatus(salesorderID, approvalStatus, info.Email)
+		if err != nil {
+			msg := "update salesorder approval status error"
+			return errors.New(msg)
+		}
+	}
+	msgs := [][]byte{salesorderHistory(salesorderID, info.OrganizationID, description, info.User, info.Email)}
+	if status == 2 && oldSalesorder.Status == 4 {
+		// a confirmed order held for approval after its total went up is
+		// confirmed again, unless the new total now breaks the credit limit
+		hold, err := repo.GetActiveSalesorderHold(info.OrganizationID, salesorderID)
+		if err != nil && err != sql.ErrNoRows {
+			msg := "get salesorder hold error"
+			return errors.New(msg)
+		}
+		if err == nil && hold.Reason == 3 {
+			err = repo.ReleaseSalesorderHold(hold.SalesorderHoldID, "approved", info.Email)
+			if err != nil {
+				msg := "release salesorder hold error"
+				return errors.New(msg)
+			}
+			creditHold, err := s.checkCreditHold(repo, oldSalesorder, info.Email)
+			if err != nil {
+				return err
+			}
+			if creditHold != nil {
+				msgs = append(msgs, salesorderHistory(salesorderID, info.OrganizationID, creditHoldDescription(creditHold), info.User, info.Email))
+			} else {
+				err = repo.UpdateSalesorderStatus(salesorderID, 2, info.Email) //CONFIRMED
+				if err != nil {
+					msg := "update salesorder error: "
+					return errors.New(msg)
+				}
+				_, dropshipMsgs, err := createDropshipPurchaseorders(repo, salesorderID, info.OrganizationID, info.User, info.Email)
+				if err != nil {
+					return err
+				}
+				msgs = append(msgs, dropshipMsgs...)
+			}
+		}
+	}
+	tx.Commit()
+	return publishHistory(msgs)
+}
+
+func quoteHistory(quoteID, organizationID, description, user, email string) []byte {
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "quote"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = user
+	newEvent.ReferenceID = quoteID
+	newEvent.Description = description
+	newEvent.OrganizationID = organizationID
+	newEvent.Email = email
+	msg, _ := json.Marshal(newEvent)
+	return msg
+}
+
+// createQuoteItems prices and taxes the quote lines the same way a sales
+// order does and returns the quote with its totals filled in. A quote for a
+// lead has no price list and no tax exemption until it is converted.
+func createQuoteItems(repo *salesorderRepository, quoteID string, info QuoteNew) (*Quote, error) {
+	priceListID := ""
+	taxExempt := 2
+	settingService := setting.NewSettingService()
+	if info.CustomerID != "" {
+		customer, err := settingService.GetCustomerByID(info.OrganizationID, info.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		priceListID = customer.PriceListID
+		taxExempt = customer.TaxExempt
+	}
+	if info.LeadID != "" {
+		crmRepo := crm.NewCrmRepository(repo.tx)
+		_, err := crmRepo.GetLeadByID(info.OrganizationID, info.LeadID)
+		if err != nil {
+			msg := "lead not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	itemCount := 0
+	itemTotal := 0.0
+	taxTotal := 0.0
+	itemService := item.NewItemService()
+	for _, itemRow := range info.Items {
+		_, err := itemService.GetItemByID(info.OrganizationID, itemRow.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if itemRow.Rate == 0 {
+			price, err := itemService.GetItemPrice(info.OrganizationID, priceListID, itemRow.ItemID, itemRow.Quantity, info.QuoteDate)
+			if err != nil {
+				return nil, err
+			}
+			itemRow.Rate = price.Rate
+		}
+		taxID := itemRow.TaxID
+		if taxExempt == 1 {
+			taxID = ""
+		}
+		var taxInfo setting.TaxCalculationNew
+		taxInfo.TaxID = taxID
+		taxInfo.Amount = itemRow.Rate * float64(itemRow.Quantity)
+		taxInfo.TaxInclusive = info.TaxInclusive
+		taxInfo.OrganizationID = info.OrganizationID
+		tax, err := settingService.CalculateTax(taxInfo)
+		if err != nil {
+			return nil, err
+		}
+		itemCount += itemRow.Quantity
+		itemTotal += tax.TaxableAmount
+		taxTotal += tax.TaxAmount
+		var quoteItem QuoteItem
+		quoteItem.OrganizationID = info.OrganizationID
+		quoteItem.QuoteID = quoteID
+		quoteItem.QuoteItemID = "qti-" + xid.New().String()
+		quoteItem.ItemID = itemRow.ItemID
+		quoteItem.Quantity = itemRow.Quantity
+		quoteItem.Rate = itemRow.Rate
+		quoteItem.TaxID = taxID
+		quoteItem.TaxValue = tax.TaxValue
+		quoteItem.TaxAmount = tax.TaxAmount
+		quoteItem.Amount = tax.TaxableAmount
+		quoteItem.Status = 1
+		quoteItem.Created = time.Now()
+		quoteItem.CreatedBy = info.Email
+		quoteItem.Updated = time.Now()
+		quoteItem.UpdatedBy = info.Email
+		err = repo.CreateQuoteItem(quoteItem)
+		if err != nil {
+			msg := "create quote item error: "
+			return nil, errors.New(msg)
+		}
+	}
+	var shippingTaxInfo setting.TaxCalculationNew
+	shippingTaxInfo.TaxID = info.ShippingTaxID
+	if taxExempt == 1 {
+		shippingTaxInfo.TaxID = ""
+	}
+	shippingTaxInfo.Amount = info.ShippingFee
+	shippingTaxInfo.TaxInclusive = info.TaxInclusive
+	shippingTaxInfo.OrganizationID = info.OrganizationID
+	shippingTax, err := settingService.CalculateTax(shippingTaxInfo)
+	if err != nil {
+		return nil, err
+	}
+	shippingTotal := shippingTax.TaxableAmount + shippingTax.TaxAmount
+	var quote Quote
+	quote.QuoteID = quoteID
+	quote.OrganizationID = info.OrganizationID
+	quote.QuoteNumber = info.QuoteNumber
+	quote.QuoteDate = info.QuoteDate
+	quote.ValidUntil = info.ValidUntil
+	quote.CustomerID = info.CustomerID
+	quote.LeadID = info.LeadID
+	quote.ItemCount = itemCount
+	quote.Subtotal = itemTotal
+	quote.TaxTotal = taxTotal
+	quote.DiscountType = info.DiscountType
+	quote.DiscountValue = info.DiscountValue
+	quote.ShippingFee = info.ShippingFee
+	quote.TaxInclusive = info.TaxInclusive
+	quote.ShippingTaxID = shippingTaxInfo.TaxID
+	quote.ShippingTaxAmount = shippingTax.TaxAmount
+	if info.DiscountType == 1 {
+		if info.DiscountValue < 0 || info.DiscountValue > 100 {
+			msg := "discount value error"
+			return nil, errors.New(msg)
+		}
+		quote.Total = (itemTotal+taxTotal)*(1-info.DiscountValue/100) + shippingTotal
+	} else if info.DiscountType == 2 {
+		if info.DiscountValue > (itemTotal + taxTotal + shippingTotal) {
+			msg := "discount value error"
+			return nil, errors.New(msg)
+		}
+		quote.Total = itemTotal - info.DiscountValue + shippingTotal + taxTotal
+	} else {
+		quote.Total = itemTotal + shippingTotal + taxTotal
+	}
+	quote.Notes = info.Notes
+	return &quote, nil
+}
+
+func (s *salesorderService) NewQuote(info QuoteNew) (*string, error) {
+	if info.ValidUntil < info.QuoteDate {
+		msg := "valid until can not be earlier than quote date"
+		return nil, errors.New(msg)
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
+	repo := NewSalesorderRepository(tx)
+	isConflict, err := repo.CheckQuoteNumberConfict("", info.OrganizationID, info.QuoteNumber)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "quote number exists"
+		return nil, errors.New(msg)
+	}
+	quoteID := "qt-" + xid.New().String()
+	quote, err := createQuoteItems(repo, quoteID, info)
+	if err != nil {
+		return nil, err
+	}
+	quote.Status = 1 //Draft
+	quote.Created = time.Now()
+	quote.CreatedBy = info.Email
+	quote.Updated = time.Now()
+	quote.UpdatedBy = info.Email
+	err = repo.CreateQuote(*quote)
+	if err != nil {
+		msg := "create quote error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	err = publishHistory([][]byte{quoteHistory(quoteID, info.OrganizationID, "Quote Created", info.User, info.Email)})
+	return &quoteID, err
+}
+
+func (s *salesorderService) GetQuoteList(filter QuoteFilter) (int, *[]QuoteResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	count, err := query.GetQuoteCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetQuoteList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *salesorderService) GetQuoteByID(organizationID, id string) (*QuoteResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	quote, err := query.GetQuoteByID(organizationID, id)
+	if err != nil {
+		msg := "get quote error: "
+		return nil, errors.New(msg)
+	}
+	return quote, nil
+}
+
+func (s *salesorderService) GetQuoteItemList(quoteID, organizationID string) (*[]QuoteItemResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	_, err := query.GetQuoteByID(organizationID, quoteID)
+	if err != nil {
+		msg := "get quote error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetQuoteItemList(organizationID, quoteID)
+	return list, err
+}
+
+func (s *salesorderService) UpdateQuote(quoteID string, info QuoteNew) (*string, error) {
+	if info.ValidUntil < info.QuoteDate {
+		msg := "valid until can not be earlier than quote date"
+		return nil, errors.New(msg)
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	if info.TaxInclusive == 0 {
+		info.TaxInclusive = 2
+	}
+	repo := NewSalesorderRepository(tx)
+	isConflict, err := repo.CheckQuoteNumberConfict(quoteID, info.OrganizationID, info.QuoteNumber)
+	if err != nil {
+		msg := "check conflict error: "
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "quote number exists"
+		return nil, errors.New(msg)
+	}
+	oldQuote, err := repo.GetQuoteByID(info.OrganizationID, quoteID)
+	if err != nil {
+		msg := "quote not exist"
+		return nil, errors.New(msg)
+	}
+	if oldQuote.Status != 1 && oldQuote.Status != 2 {
+		msg := "only draft or sent quote can be updated"
+		return nil, errors.New(msg)
+	}
+	err = repo.DeleteQuoteItem(quoteID, info.Email)
+	if err != nil {
+		msg := "delete quote item error: "
+		return nil, errors.New(msg)
+	}
+	quote, err := createQuoteItems(repo, quoteID, info)
+	if err != nil {
+		return nil, err
+	}
+	quote.Status = oldQuote.Status
+	quote.Updated = time.Now()
+	quote.UpdatedBy = info.Email
+	err = repo.UpdateQuote(quoteID, *quote)
+	if err != nil {
+		msg := "update quote error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	err = publishHistory([][]byte{quoteHistory(quoteID, info.OrganizationID, "Quote Updated", info.User, info.Email)})
+	return &quoteID, err
+}
+
+func (s *salesorderService) DeleteQuote(quoteID, organizationID, user, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	oldQuote, err := repo.GetQuoteByID(organizationID, quoteID)
+	if err != nil {
+		msg := "quote not exist"
+		return errors.New(msg)
+	}
+	if oldQuote.Status == 6 {
+		msg := "converted quote can not be deleted"
+		return errors.New(msg)
+	}
+	err = repo.DeleteQuote(quoteID, email)
+	if err != nil {
+		msg := "delete quote error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return publishHistory([][]byte{quoteHistory(quoteID, organizationID, "Quote Deleted", user, email)})
+}
+
+// UpdateQuoteStatus moves a quote to sent, accepted or declined. Only a draft
+// can be sent and only a sent quote can be accepted or declined, a quote past
+// its validity date can do neither.
+func (s *salesorderService) UpdateQuoteStatus(quoteID, organizationID string, status int, user, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	oldQuote, err := repo.GetQuoteByID(organizationID, quoteID)
+	if err != nil {
+		msg := "quote not exist"
+		return errors.New(msg)
+	}
+	description := ""
+	switch status {
+	case 2:
+		if oldQuote.Status != 1 {
+			msg := "only draft quote can be sent"
+			return errors.New(msg)
+		}
+		description = "Quote Sent"
+	case 3:
+		if oldQuote.Status != 2 {
+			msg := "only sent quote can be accepted"
+			return errors.New(msg)
+		}
+		description = "Quote Accepted"
+	case 4:
+		if oldQuote.Status != 2 {
+			msg := "only sent quote can be declined"
+			return errors.New(msg)
+		}
+		description = "Quote Declined"
+	default:
+		msg := "quote status error"
+		return errors.New(msg)
+	}
+	if status != 4 && oldQuote.ValidUntil < time.Now().Format("2006-01-02") {
+		msg := "quote expired"
+		return errors.New(msg)
+	}
+	err = repo.UpdateQuoteStatus(quoteID, status, email)
+	if err != nil {
+		msg := "update quote status error: "
+		return errors.New(msg)
+	}
+	tx.Commit()
+	return publishHistory([][]byte{quoteHistory(quoteID, organizationID, description, user, email)})
+}
+
+// ConvertQuote creates a sales order from the quote lines. A quote made for a
+// lead needs the customer the lead was converted to.
+func (s *salesorderService) ConvertQuote(quoteID string, info QuoteConvertNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	status, err := repo.GetQuoteStatusForUpdate(info.OrganizationID, quoteID)
+	if err != nil {
+		msg := "quote not exist"
+		return nil, errors.New(msg)
+	}
+	if status != 1 && status != 2 && status != 3 {
+		msg := "quote can not be converted"
+		return nil, errors.New(msg)
+	}
+	quote, err := repo.GetQuoteByID(info.OrganizationID, quoteID)
+	if err != nil {
+		msg := "quote not exist"
+		return nil, errors.New(msg)
+	}
+	if quote.ValidUntil < time.Now().Format("2006-01-02") {
+		msg := "quote expired"
+		return nil, errors.New(msg)
+	}
+	customerID := quote.CustomerID
+	if customerID == "" {
+		customerID = info.CustomerID
+	}
+	if customerID == "" {
+		msg := "customer required, convert the lead to a customer first"
+		return nil, errors.New(msg)
+	}
+	items, err := repo.GetQuoteItemList(info.OrganizationID, quoteID)
+	if err != nil {
+		msg := "get quote items error"
+		return nil, errors.New(msg)
+	}
+	var soInfo SalesorderNew
+	soInfo.SalesorderNumber = info.SalesorderNumber
+	soInfo.SalesorderDate = info.SalesorderDate
+	soInfo.ExpectedShipmentDate = info.ExpectedShipmentDate
+	soInfo.CustomerID = customerID
+	soInfo.BillingAddressID = info.BillingAddressID
+	soInfo.ShippingAddressID = info.ShippingAddressID
+	soInfo.DiscountType = quote.DiscountType
+	soInfo.DiscountValue = quote.DiscountValue
+	soInfo.ShippingFee = quote.ShippingFee
+	soInfo.ShippingTaxID = quote.ShippingTaxID
+	soInfo.TaxInclusive = quote.TaxInclusive
+	soInfo.Notes = quote.Notes
+	for _, itemRow := range *items {
+		var soItem SalesorderItemNew
+		soItem.ItemID = itemRow.ItemID
+		soItem.Quantity = itemRow.Quantity
+		soItem.Rate = itemRow.Rate
+		soItem.TaxID = itemRow.TaxID
+		soInfo.Items = append(soInfo.Items, soItem)
+	}
+	soInfo.OrganizationID = info.OrganizationID
+	soInfo.User = info.User
+	soInfo.Email = info.Email
+	salesorderID, err := s.createSalesorder(tx, soInfo)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.UpdateQuoteConverted(quoteID, customerID, salesorderID, info.Email)
+	if err != nil {
+		msg := "update quote error: "
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	err = publishHistory([][]byte{
+		salesorderHistory(salesorderID, info.OrganizationID, "Sales Order Created", info.User, info.Email),
+		quoteHistory(quoteID, info.OrganizationID, "Quote Converted to Sales Order "+info.SalesorderNumber, info.User, info.Email),
+	})
+	return &salesorderID, err
+}
+
+// ExpireQuotes marks the sent quotes past their validity date as expired.
+func (s *salesorderService) ExpireQuotes() error {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	quotes, err := query.GetExpiredQuoteList(time.Now().Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+	if len(*quotes) == 0 {
+		return nil
+	}
+	wdb := database.WDB()
+	tx, err := wdb.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	var msgs [][]byte
+	for _, quote := range *quotes {
+		err = repo.UpdateQuoteStatus(quote.QuoteID, 5, "system")
+		if err != nil {
+			msg := "update quote status error: "
+			return errors.New(msg)
+		}
+		msgs = append(msgs, quoteHistory(quote.QuoteID, quote.OrganizationID, "Quote Expired", "System", "system"))
+	}
+	tx.Commit()
+	return publishHistory(msgs)
+}
+
+// NewDropshipPurchaseorders creates the purchase orders for the drop-ship
+// lines of a confirmed sales order that are not ordered yet. Lines already
+// linked to a purchase order are skipped, so it can be called again after a
+// failed confirmation.
+func (s *salesorderService) NewDropshipPurchaseorders(salesorderID, organizationID, user, email string) (*[]string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	status, err := repo.GetSalesorderStatusForUpdate(organizationID, salesorderID)
+	if err != nil {
+		msg := "salesorder not exist"
+		return nil, errors.New(msg)
+	}
+	if status != 2 {
+		msg := "only confirmed salesorder can be drop-shipped"
+		return nil, errors.New(msg)
+	}
+	purchaseorderIDs, msgs, err := createDropshipPurchaseorders(repo, salesorderID, organizationID, user, email)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	err = publishHistory(msgs)
+	return &purchaseorderIDs, err
+}
+
+// createDropshipPurchaseorders creates one purchase order per default vendor
+// for the drop-ship lines not ordered yet and links the lines to it, all in
+// the transaction of the caller. The vendor ships straight to the shipping
+// address of the sales order. The history of the orders is returned to be
+// published after commit.
+func createDropshipPurchaseorders(repo *salesorderRepository, salesorderID, organizationID, user, email string) ([]string, [][]byte, error) {
+	salesorder, err := repo.GetSalesorderByID(organizationID, salesorderID)
+	if err != nil {
+		msg := "salesorder not exist"
+		return nil, nil, errors.New(msg)
+	}
+	items, err := repo.GetSalesorderItemList(organizationID, salesorderID)
+	if err != nil {
+		msg := "get salesorder items error"
+		return nil, nil, errors.New(msg)
+	}
+	var vendors []string
+	vendorItems := make(map[string][]SalesorderItemResponse)
+	vendorRates := make(map[string]float64)
+	itemRepo := item.NewItemRepository(repo.tx)
+	for _, itemRow := range *items {
+		if itemRow.DropShip != 1 || itemRow.PurchaseorderID != "" {
+			continue
+		}
+		itemInfo, err := itemRepo.GetItemByID(itemRow.ItemID, organizationID)
+		if err != nil {
+			msg := "item not exist"
+			return nil, nil, errors.New(msg)
+		}
+		if itemInfo.DefaultVendorID == "" {
+			msg := "drop-ship item " + itemInfo.SKU + " has no default vendor"
+			return nil, nil, errors.New(msg)
+		}
+		if _, ok := vendorItems[itemInfo.DefaultVendorID]; !ok {
+			vendors = append(vendors, itemInfo.DefaultVendorID)
+		}
+		vendorItems[itemInfo.DefaultVendorID] = append(vendorItems[itemInfo.DefaultVendorID], itemRow)
+		vendorRates[itemRow.SalesorderItemID] = itemInfo.CostPrice
+	}
+	var purchaseorderIDs []string
+	var msgs [][]byte
+	commonService := common.NewCommonService()
+	for _, vendorID := range vendors {
+		var numberFilter common.NumberFilter
+		numberFilter.NumberType = "purchaseorder"
+		numberFilter.OrganizationID = organizationID
+		number, err := commonService.GetNextNumber(numberFilter)
+		if err != nil {
+			return nil, nil, err
+		}
+		var info purchaseorder.PurchaseorderNew
+		info.PurchaseorderNumber = *number
+		info.PurchaseorderDate = time.Now().Format("2006-01-02")
+		info.ExpectedDeliveryDate = salesorder.ExpectedShipmentDate
+		if info.ExpectedDeliveryDate < info.PurchaseorderDate {
+			info.ExpectedDeliveryDate = info.PurchaseorderDate
+		}
+		info.VendorID = vendorID
+		info.Notes = "Drop-ship for sales order " + salesorder.SalesorderNumber
+		for _, itemRow := range vendorItems[vendorID] {
+			var poItem purchaseorder.PurchaseorderItemNew
+			poItem.ItemID = itemRow.ItemID
+			poItem.Quantity = itemRow.Quantity
+			poItem.Rate = vendorRates[itemRow.SalesorderItemID]
+			info.Items = append(info.Items, poItem)
+		}
+		info.SalesorderID = salesorderID
+		info.ShippingAddress = salesorder.ShippingAddress
+		info.OrganizationID = organizationID
+		info.User = user
+		info.Email = email
+		purchaseorderID, err := purchaseorder.CreatePurchaseorder(repo.tx, info)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, itemRow := range vendorItems[vendorID] {
+			err = repo.UpdateSalesorderItemPurchaseorder(itemRow.SalesorderItemID, purchaseorderID, email)
+			if err != nil {
+				msg := "update salesorder item error"
+				return nil, nil, errors.New(msg)
+			}
+		}
+		purchaseorderIDs = append(purchaseorderIDs, purchaseorderID)
+		msgs = append(msgs, purchaseorderHistory(purchaseorderID, organizationID, "Purchase Order Created", user, email))
+		msgs = append(msgs, salesorderHistory(salesorderID, organizationID, "Drop-ship Purchase Order "+*number+" Created", user, email))
+	}
+	return purchaseorderIDs, msgs, nil
+}
+
+func purchaseorderHistory(purchaseorderID, organizationID, description, user, email string) []byte {
+	var newEvent common.NewHistoryCreated
+	newEvent.HistoryType = "purchaseorder"
+	newEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	newEvent.HistoryBy = user
+	newEvent.ReferenceID = purchaseorderID
+	newEvent.Description = description
+	newEvent.OrganizationID = organizationID
+	newEvent.Email = email
+	msg, _ := json.Marshal(newEvent)
+	return msg
+}
+
+func progressStatus(total, done float64) int {
+	if done == 0 {
+		return 1
+	}
+	if done >= total {
+		return 3
+	}
+	return 2
+}
+
+// NewDropshipment records the vendor's shipment of a drop-ship purchase order.
+// The shipped quantity is received on the purchase order and shipped on the
+// sales order without any stock movement.
+func (s *salesorderService) NewDropshipment(salesorderID string, info DropshipmentNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewSalesorderRepository(tx)
+	salesorder, err := repo.GetSalesorderByID(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "salesorder not exist"
+		return nil, errors.New(msg)
+	}
+	if salesorder.Status != 2 {
+		msg := "salesorder status error"
+		return nil, errors.New(msg)
+	}
+	purchaseorderRepo := purchaseorder.NewPurchaseorderRepository(tx)
+	po, err := purchaseorderRepo.GetPurchaseorderByID(info.OrganizationID, info.PurchaseorderID)
+	if err != nil {
+		msg := "purchase order not exist"
+		return nil, errors.New(msg)
+	}
+	if po.SalesorderID != salesorderID {
+		msg := "purchase order is not a drop-ship of this salesorder"
+		return nil, errors.New(msg)
+	}
+	if po.Status == 1 {
+		msg := "purchase order not issued"
+		return nil, errors.New(msg)
+	}
+	dropshipmentID := "dsh-" + xid.New().String()
+	for _, itemRow := range info.Items {
+		soItem, err := repo.GetDropshipSalesorderItem(info.OrganizationID, salesorderID, info.PurchaseorderID, itemRow.ItemID)
+		if err != nil {
+			msg := "drop-ship item not exist"
+			return nil, errors.New(msg)
+		}
+		if soItem.QuantityShipped+itemRow.Quantity > soItem.Quantity {
+			msg := "shipped quantity greater than not shipped"
+			return nil, errors.New(msg)
+		}
+		poItem, err := purchaseorderRepo.GetPurchaseorderItemByID(info.OrganizationID, info.PurchaseorderID, itemRow.ItemID)
+		if err != nil {
+			msg := "purchase order item not exist"
+			return nil, errors.New(msg)
+		}
+		if poItem.QuantityReceived+itemRow.Quantity > poItem.Quantity {
+			msg := "shipped quantity greater than purchase order quantity"
+			return nil, errors.New(msg)
+		}
+		err = repo.ShipDropshipSalesorderItem(soItem.SalesorderItemID, itemRow.Quantity, info.Email)
+		if err != nil {
+			msg := "ship salesorder item error"
+			return nil, errors.New(msg)
+		}
+		var receivedItem purchaseorder.PurchaseorderItem
+		receivedItem.PurchaseorderItemID = poItem.PurchaseorderItemID
+		receivedItem.QuantityReceived = poItem.QuantityReceived + itemRow.Quantity
+		receivedItem.Updated = time.Now()
+		receivedItem.UpdatedBy = info.Email
+		err = purchaseorderRepo.ReceivePurchaseorderItem(receivedItem)
+		if err != nil {
+			msg := "receive purchase order item error"
+			return nil, errors.New(msg)
+		}
+		var dropshipmentItem DropshipmentItem
+		dropshipmentItem.OrganizationID = info.OrganizationID
+		dropshipmentItem.DropshipmentID = dropshipmentID
+		dropshipmentItem.DropshipmentItemID = "dshi-" + xid.New().String()
+		dropshipmentItem.SalesorderItemID = soItem.SalesorderItemID
+		dropshipmentItem.PurchaseorderItemID = poItem.PurchaseorderItemID
+		dropshipmentItem.ItemID = itemRow.ItemID
+		dropshipmentItem.Quantity = itemRow.Quantity
+		dropshipmentItem.Status = 1
+		dropshipmentItem.Created = time.Now()
+		dropshipmentItem.CreatedBy = info.Email
+		dropshipmentItem.Updated = time.Now()
+		dropshipmentItem.UpdatedBy = info.Email
+		err = repo.CreateDropshipmentItem(dropshipmentItem)
+		if err != nil {
+			msg := "create drop shipment item error"
+			return nil, errors.New(msg)
+		}
+	}
+	var dropshipment Dropshipment
+	dropshipment.OrganizationID = info.OrganizationID
+	dropshipment.DropshipmentID = dropshipmentID
+	dropshipment.SalesorderID = salesorderID
+	dropshipment.PurchaseorderID = info.PurchaseorderID
+	dropshipment.ShipmentDate = info.ShipmentDate
+	dropshipment.CarrierName = info.CarrierName
+	dropshipment.TrackingNumber = info.TrackingNumber
+	dropshipment.Notes = info.Notes
+	dropshipment.Status = 1
+	dropshipment.Created = time.Now()
+	dropshipment.CreatedBy = info.Email
+	dropshipment.Updated = time.Now()
+	dropshipment.UpdatedBy = info.Email
+	err = repo.CreateDropshipment(dropshipment)
+	if err != nil {
+		msg := "create drop shipment error"
+		return nil, errors.New(msg)
+	}
+	pickedCount, err := repo.GetSalesorderPickedCount(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "get sales order picked count error"
+		return nil, errors.New(msg)
+	}
+	packedCount, err := repo.GetSalesorderPackedCount(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "get sales order packed count error"
+		return nil, errors.New(msg)
+	}
+	shippedCount, err := repo.GetSalesorderShippedCount(info.OrganizationID, salesorderID)
+	if err != nil {
+		msg := "get sales order shipped count error"
+		return nil, errors.New(msg)
+	}
+	err = repo.UpdateSalesorderPickingStatus(salesorderID, progressStatus(salesorder.ItemCount, pickedCount), info.Email)
+	if err != nil {
+		msg := "update salesorder picking status error"
+		return nil, errors.New(msg)
+	}
+	err = repo.UpdateSalesorderPackingStatus(salesorderID, progressStatus(salesorder.ItemCount, packedCount), info.Email)
+	if err != nil {
+		msg := "update salesorder packing status error"
+		return nil, errors.New(msg)
+	}
+	shippingStatus := progressStatus(salesorder.ItemCount, shippedCount)
+	err = repo.UpdateSalesorderShippingStatus(salesorderID, shippingStatus, info.Email)
+	if err != nil {
+		msg := "update salesorder shipping status error"
+		return nil, errors.New(msg)
+	}
+	if salesorder.InvoiceStatus == 3 && shippingStatus == 3 {
+		err = repo.UpdateSalesorderStatus(salesorderID, 3, info.Email)
+		if err != nil {
+			msg := "update sales order status error"
+			return nil, errors.New(msg)
+		}
+	}
+	receivedCount, err := purchaseorderRepo.GetPurchaseorderReceivedCount(info.OrganizationID, info.PurchaseorderID)
+	if err != nil {
+		msg := "get purchase order received count error"
+		return nil, errors.New(msg)
+	}
+	receiveStatus := progressStatus(po.ItemCount, receivedCount)
+	err = purchaseorderRepo.UpdatePurchaseorderReceiveStatus(info.PurchaseorderID, receiveStatus, info.Email)
+	if err != nil {
+		msg := "update purchase order receive status error"
+		return nil, errors.New(msg)
+	}
+	if po.BillingStatus == 3 && receiveStatus == 3 {
+		err = purchaseorderRepo.UpdatePurchaseorderStatus(info.PurchaseorderID, 3, info.Email)
+		if err != nil {
+			msg := "update purchase order status error"
+			return nil, errors.New(msg)
+		}
+	}
+	tx.Commit()
+	description := "Drop Shipment Confirmed"
+	if info.TrackingNumber != "" {
+		description += ": " + strings.TrimSpace(info.CarrierName+" "+info.TrackingNumber)
+	}
+	var poEvent common.NewHistoryCreated
+	poEvent.HistoryType = "purchaseorder"
+	poEvent.HistoryTime = time.Now().Format("2006-01-02 15:04:05")
+	poEvent.HistoryBy = info.User
+	poEvent.ReferenceID = info.PurchaseorderID
+	poEvent.Description = description
+	poEvent.OrganizationID = info.OrganizationID
+	poEvent.Email = info.Email
+	poMsg, _ := json.Marshal(poEvent)
+	err = publishHistory([][]byte{salesorderHistory(salesorderID, info.OrganizationID, description, info.User, info.Email), poMsg})
+	return &dropshipmentID, err
+}
+
+func (s *salesorderService) GetDropshipmentList(salesorderID, organizationID string) (*[]DropshipmentResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	_, err := query.GetSalesorderByID(organizationID, salesorderID)
+	if err != nil {
+		msg := "get salesorder error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetDropshipmentList(organizationID, salesorderID)
+	return list, err
+}
+
+func (s *salesorderService) GetDropshipmentItemList(dropshipmentID, organizationID string) (*[]DropshipmentItemResponse, error) {
+	db := database.RDB()
+	query := NewSalesorderQuery(db)
+	_, err := query.GetDropshipmentByID(organizationID, dropshipmentID)
+	if err != nil {
+		msg := "get drop shipment error: "
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetDropshipmentItemList(organizationID, dropshipmentID)
+	return list, err
+}
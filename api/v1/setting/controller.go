@@ -1,8 +1,11 @@
 package setting
 
 import (
+	"errors"
 	"go-api/core/response"
 	"go-api/service"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -733,6 +736,33 @@ func DeleteTax(c *gin.Context) {
 	response.Response(c, "OK")
 }
 
+// @Summary 计算税额
+// @Id 346
+// @Tags 税率管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param tax_info body TaxCalculationNew true "税额计算信息"
+// @Success 200 object response.SuccessRes{data=TaxCalculationResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /taxes/calculate [POST]
+func CalculateTax(c *gin.Context) {
+	var info TaxCalculationNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	res, err := settingService.CalculateTax(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
 // @Summary 客户列表
 // @Id 326
 // @Tags 客户管理
@@ -1023,6 +1053,21 @@ func DeleteCarrier(c *gin.Context) {
 	response.Response(c, "OK")
 }
 
+// @Summary 物流商接口列表
+// @Id 359
+// @Tags 物流商管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=[]string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /carrieradapters [GET]
+func GetCarrierAdapterList(c *gin.Context) {
+	settingService := NewSettingService()
+	list := settingService.GetCarrierAdapterList()
+	response.Response(c, list)
+}
+
 // @Summary 库存调整原因列表
 // @Id 336
 // @Tags 库存调整原因管理
@@ -1312,3 +1357,749 @@ func GetPaymentMethodList(c *gin.Context) {
 	}
 	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
 }
+
+// @Summary 新建付款条款
+// @Id 347
+// @Tags 付款条款管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param paymentterm_info body PaymentTermNew true "付款条款信息"
+// @Success 200 object response.SuccessRes{data=PaymentTermResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /paymentterms [POST]
+func NewPaymentTerm(c *gin.Context) {
+	var info PaymentTermNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.NewPaymentTerm(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID更新付款条款
+// @Id 348
+// @Tags 付款条款管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path int true "付款条款ID"
+// @Param paymentterm_info body PaymentTermNew true "付款条款信息"
+// @Success 200 object response.SuccessRes{data=PaymentTermResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /paymentterms/:id [PUT]
+func UpdatePaymentTerm(c *gin.Context) {
+	var uri PaymentTermID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info PaymentTermNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.UpdatePaymentTerm(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID获取付款条款
+// @Id 349
+// @Tags 付款条款管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path int true "付款条款ID"
+// @Success 200 object response.SuccessRes{data=PaymentTermResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /paymentterms/:id [GET]
+func GetPaymentTermByID(c *gin.Context) {
+	var uri PaymentTermID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	paymentterm, err := settingService.GetPaymentTermByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, paymentterm)
+
+}
+
+// @Summary 根据ID删除付款条款
+// @Id 350
+// @Tags 付款条款管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path int true "付款条款ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /paymentterms/:id [DELETE]
+func DeletePaymentTerm(c *gin.Context) {
+	var uri PaymentTermID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	err := settingService.DeletePaymentTerm(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 付款条款列表
+// @Id 351
+// @Tags 付款条款管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数（5/10/15/20）"
+// @Param name query string false "付款条款名称"
+// @Param term_type query int false "条款类型（1净天数/2月结/3见票即付）"
+// @Success 200 object response.ListRes{data=[]PaymentTermResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /paymentterms [GET]
+func GetPaymentTermList(c *gin.Context) {
+	var filter PaymentTermFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	count, list, err := settingService.GetPaymentTermList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 单据模板列表
+// @Id 352
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=[]DocumentTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documenttemplates [GET]
+func GetDocumentTemplateList(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	list, err := settingService.GetDocumentTemplateList(claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 根据单据类型获取单据模板
+// @Id 353
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param type path int true "单据类型（1发票/2采购单/3装箱单/4拣货单/5提单）"
+// @Success 200 object response.SuccessRes{data=DocumentTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documenttemplates/:type [GET]
+func GetDocumentTemplate(c *gin.Context) {
+	var uri DocumentTemplateType
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	template, err := settingService.GetDocumentTemplate(claims.OrganizationID, uri.DocumentType)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, template)
+}
+
+// @Summary 根据单据类型更新单据模板
+// @Id 354
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param type path int true "单据类型（1发票/2采购单/3装箱单/4拣货单/5提单）"
+// @Param template_info body DocumentTemplateNew true "单据模板信息"
+// @Success 200 object response.SuccessRes{data=DocumentTemplateResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documenttemplates/:type [PUT]
+func UpdateDocumentTemplate(c *gin.Context) {
+	var uri DocumentTemplateType
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info DocumentTemplateNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.User = claims.UserName
+	info.Email = claims.Email
+	settingService := NewSettingService()
+	new, err := settingService.UpdateDocumentTemplate(uri.DocumentType, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据单据类型恢复默认单据模板
+// @Id 355
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param type path int true "单据类型（1发票/2采购单/3装箱单/4拣货单/5提单）"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documenttemplates/:type [DELETE]
+func DeleteDocumentTemplate(c *gin.Context) {
+	var uri DocumentTemplateType
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	err := settingService.DeleteDocumentTemplate(claims.OrganizationID, uri.DocumentType, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 上传单据Logo
+// @Id 356
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "Logo图片（PNG/JPEG）"
+// @Success 200 object response.SuccessRes{data=DocumentLogoResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documentlogo [POST]
+func NewDocumentLogo(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	if file.Size > 1<<20 {
+		response.ResponseError(c, "BindingError", errors.New("file too large"))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	new, err := settingService.NewDocumentLogo(claims.OrganizationID, file.Filename, http.DetectContentType(content), content, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 获取单据Logo
+// @Id 357
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce image/png,image/jpeg
+// @Success 200 {file} file 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documentlogo [GET]
+func GetDocumentLogo(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	logo, err := settingService.GetDocumentLogo(claims.OrganizationID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	c.Data(http.StatusOK, logo.ContentType, logo.Data)
+}
+
+// @Summary 删除单据Logo
+// @Id 358
+// @Tags 单据模板管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /documentlogo [DELETE]
+func DeleteDocumentLogo(c *gin.Context) {
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	err := settingService.DeleteDocumentLogo(claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 新建客户地址
+// @Id 360
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "客户ID"
+// @Param address_info body AddressNew true "地址信息"
+// @Success 200 object response.SuccessRes{data=AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /customers/:id/addresses [POST]
+func NewCustomerAddress(c *gin.Context) {
+	var uri CustomerID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info AddressNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.NewAddress("customer", uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 客户地址列表
+// @Id 361
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "客户ID"
+// @Success 200 object response.SuccessRes{data=[]AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /customers/:id/addresses [GET]
+func GetCustomerAddressList(c *gin.Context) {
+	var uri CustomerID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	list, err := settingService.GetAddressList(claims.OrganizationID, "customer", uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 新建供应商地址
+// @Id 362
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "供应商ID"
+// @Param address_info body AddressNew true "地址信息"
+// @Success 200 object response.SuccessRes{data=AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /vendors/:id/addresses [POST]
+func NewVendorAddress(c *gin.Context) {
+	var uri VendorID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info AddressNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.NewAddress("vendor", uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 供应商地址列表
+// @Id 363
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "供应商ID"
+// @Success 200 object response.SuccessRes{data=[]AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /vendors/:id/addresses [GET]
+func GetVendorAddressList(c *gin.Context) {
+	var uri VendorID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	list, err := settingService.GetAddressList(claims.OrganizationID, "vendor", uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 根据ID更新地址
+// @Id 364
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "地址ID"
+// @Param address_info body AddressNew true "地址信息"
+// @Success 200 object response.SuccessRes{data=AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /addresses/:id [PUT]
+func UpdateAddress(c *gin.Context) {
+	var uri AddressID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info AddressNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.UpdateAddress(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID获取地址
+// @Id 365
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "地址ID"
+// @Success 200 object response.SuccessRes{data=AddressResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /addresses/:id [GET]
+func GetAddressByID(c *gin.Context) {
+	var uri AddressID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	res, err := settingService.GetAddressByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 根据ID删除地址
+// @Id 366
+// @Tags 地址簿管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "地址ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /addresses/:id [DELETE]
+func DeleteAddress(c *gin.Context) {
+	var uri AddressID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	err := settingService.DeleteAddress(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
+
+// @Summary 新建客户联系人
+// @Id 367
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "客户ID"
+// @Param contact_info body ContactNew true "联系人信息"
+// @Success 200 object response.SuccessRes{data=ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /customers/:id/contacts [POST]
+func NewCustomerContact(c *gin.Context) {
+	var uri CustomerID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ContactNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.NewContact("customer", uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 客户联系人列表
+// @Id 368
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "客户ID"
+// @Success 200 object response.SuccessRes{data=[]ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /customers/:id/contacts [GET]
+func GetCustomerContactList(c *gin.Context) {
+	var uri CustomerID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	list, err := settingService.GetContactList(claims.OrganizationID, "customer", uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 新建供应商联系人
+// @Id 369
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "供应商ID"
+// @Param contact_info body ContactNew true "联系人信息"
+// @Success 200 object response.SuccessRes{data=ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /vendors/:id/contacts [POST]
+func NewVendorContact(c *gin.Context) {
+	var uri VendorID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ContactNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.NewContact("vendor", uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 供应商联系人列表
+// @Id 370
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "供应商ID"
+// @Success 200 object response.SuccessRes{data=[]ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /vendors/:id/contacts [GET]
+func GetVendorContactList(c *gin.Context) {
+	var uri VendorID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	list, err := settingService.GetContactList(claims.OrganizationID, "vendor", uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
+
+// @Summary 根据ID更新联系人
+// @Id 371
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "联系人ID"
+// @Param contact_info body ContactNew true "联系人信息"
+// @Success 200 object response.SuccessRes{data=ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /contacts/:id [PUT]
+func UpdateContact(c *gin.Context) {
+	var uri ContactID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	var info ContactNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.User = claims.Email
+	info.OrganizationID = claims.OrganizationID
+	settingService := NewSettingService()
+	new, err := settingService.UpdateContact(uri.ID, info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 根据ID获取联系人
+// @Id 372
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "联系人ID"
+// @Success 200 object response.SuccessRes{data=ContactResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /contacts/:id [GET]
+func GetContactByID(c *gin.Context) {
+	var uri ContactID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	res, err := settingService.GetContactByID(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, res)
+}
+
+// @Summary 根据ID删除联系人
+// @Id 373
+// @Tags 联系人管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "联系人ID"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /contacts/:id [DELETE]
+func DeleteContact(c *gin.Context) {
+	var uri ContactID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	settingService := NewSettingService()
+	err := settingService.DeleteContact(uri.ID, claims.OrganizationID, claims.Email)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, "OK")
+}
@@ -102,6 +102,8 @@ type VendorResponse struct {
 	Zip               string `db:"zip" json:"zip"`
 	Phone             string `db:"phone" json:"phone"`
 	Fax               string `db:"fax" json:"fax"`
+	PaymentTermID     string `db:"payment_term_id" json:"payment_term_id"`
+	LeadTime          int    `db:"lead_time" json:"lead_time"`
 	Status            int    `db:"status" json:"status"`
 }
 
@@ -120,6 +122,8 @@ type VendorNew struct {
 	Zip               string `json:"zip" binding:"omitempty,max=64"`
 	Phone             string `json:"phone" binding:"omitempty,max=64"`
 	Fax               string `json:"fax" binding:"omitempty,max=64"`
+	PaymentTermID     string `json:"payment_term_id" binding:"omitempty,max=64"`
+	LeadTime          int    `json:"lead_time" binding:"omitempty,min=0"`
 	Status            int    `json:"status" binding:"required,oneof=1 2"`
 	OrganizationID    string `json:"organiztion_id" swaggerignore:"true"`
 	User              string `json:"user" swaggerignore:"true"`
@@ -130,11 +134,14 @@ type VendorID struct {
 }
 
 type TaxNew struct {
-	Name           string  `json:"name" binding:"required,min=1,max=64"`
-	TaxValue       float64 `json:"tax_value" binding:"required,max=100"`
-	Status         int     `json:"status" binding:"required,oneof=1 2"`
-	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
-	User           string  `json:"user" swaggerignore:"true"`
+	Name           string   `json:"name" binding:"required,min=1,max=64"`
+	TaxValue       float64  `json:"tax_value" binding:"omitempty,min=0,max=100"`
+	TaxType        int      `json:"tax_type" binding:"omitempty,oneof=1 2"`
+	IsCompound     int      `json:"is_compound" binding:"omitempty,oneof=1 2"`
+	Components     []string `json:"components" binding:"omitempty"`
+	Status         int      `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string   `json:"organiztion_id" swaggerignore:"true"`
+	User           string   `json:"user" swaggerignore:"true"`
 }
 
 type TaxID struct {
@@ -148,11 +155,55 @@ type TaxFilter struct {
 }
 
 type TaxResponse struct {
-	TaxID          string  `db:"tax_id" json:"tax_id"`
-	OrganizationID string  `db:"organization_id" json:"organization_id"`
-	Name           string  `db:"name" json:"name"`
-	TaxValue       float64 `db:"tax_value" json:"tax_value"`
-	Status         int     `db:"status" json:"status"`
+	TaxID          string                 `db:"tax_id" json:"tax_id"`
+	OrganizationID string                 `db:"organization_id" json:"organization_id"`
+	Name           string                 `db:"name" json:"name"`
+	TaxValue       float64                `db:"tax_value" json:"tax_value"`
+	TaxType        int                    `db:"tax_type" json:"tax_type"`
+	IsCompound     int                    `db:"is_compound" json:"is_compound"`
+	Components     []TaxComponentResponse `db:"-" json:"components"`
+	Status         int                    `db:"status" json:"status"`
+}
+
+type TaxComponentResponse struct {
+	TaxID      string  `db:"tax_id" json:"tax_id"`
+	Name       string  `db:"name" json:"name"`
+	TaxValue   float64 `db:"tax_value" json:"tax_value"`
+	IsCompound int     `db:"is_compound" json:"is_compound"`
+	Sequence   int     `db:"sequence" json:"sequence"`
+}
+
+type TaxCalculationNew struct {
+	TaxID          string  `json:"tax_id" binding:"omitempty"`
+	Amount         float64 `json:"amount" binding:"omitempty"`
+	TaxInclusive   int     `json:"tax_inclusive" binding:"omitempty,oneof=1 2"`
+	OrganizationID string  `json:"organiztion_id" swaggerignore:"true"`
+}
+
+type TaxCalculationResponse struct {
+	TaxID         string                       `json:"tax_id"`
+	TaxValue      float64                      `json:"tax_value"`
+	TaxableAmount float64                      `json:"taxable_amount"`
+	TaxAmount     float64                      `json:"tax_amount"`
+	Components    []TaxComponentAmountResponse `json:"components"`
+}
+
+type TaxRecordNew struct {
+	ReferenceType   string
+	ReferenceID     string
+	ReferenceItemID string
+	RecordDate      string
+	Tax             *TaxCalculationResponse
+	OrganizationID  string
+	User            string
+}
+
+type TaxComponentAmountResponse struct {
+	TaxID      string  `json:"tax_id"`
+	Name       string  `json:"name"`
+	TaxValue   float64 `json:"tax_value"`
+	IsCompound int     `json:"is_compound"`
+	TaxAmount  float64 `json:"tax_amount"`
 }
 
 //customer
@@ -164,43 +215,53 @@ type CustomerFilter struct {
 }
 
 type CustomerResponse struct {
-	CustomerID        string `db:"customer_id" json:"customer_id"`
-	OrganizationID    string `db:"organization_id" json:"organization_id"`
-	Name              string `db:"name" json:"name"`
-	ContactSalutation string `db:"contact_salutation" json:"contact_salutation"`
-	ContactFirstName  string `db:"contact_first_name" json:"contact_first_name"`
-	ContactLastName   string `db:"contact_last_name" json:"contact_last_name"`
-	ContactEmail      string `db:"contact_email" json:"contact_email"`
-	ContactPhone      string `db:"contact_phone" json:"contact_phone"`
-	Country           string `db:"country" json:"country"`
-	State             string `db:"state" json:"state"`
-	City              string `db:"city" json:"city"`
-	Address1          string `db:"address1" json:"address1"`
-	Address2          string `db:"address2" json:"address2"`
-	Zip               string `db:"zip" json:"zip"`
-	Phone             string `db:"phone" json:"phone"`
-	Fax               string `db:"fax" json:"fax"`
-	Status            int    `db:"status" json:"status"`
+	CustomerID        string  `db:"customer_id" json:"customer_id"`
+	OrganizationID    string  `db:"organization_id" json:"organization_id"`
+	Name              string  `db:"name" json:"name"`
+	ContactSalutation string  `db:"contact_salutation" json:"contact_salutation"`
+	ContactFirstName  string  `db:"contact_first_name" json:"contact_first_name"`
+	ContactLastName   string  `db:"contact_last_name" json:"contact_last_name"`
+	ContactEmail      string  `db:"contact_email" json:"contact_email"`
+	ContactPhone      string  `db:"contact_phone" json:"contact_phone"`
+	Country           string  `db:"country" json:"country"`
+	State             string  `db:"state" json:"state"`
+	City              string  `db:"city" json:"city"`
+	Address1          string  `db:"address1" json:"address1"`
+	Address2          string  `db:"address2" json:"address2"`
+	Zip               string  `db:"zip" json:"zip"`
+	Phone             string  `db:"phone" json:"phone"`
+	Fax               string  `db:"fax" json:"fax"`
+	TaxExempt         int     `db:"tax_exempt" json:"tax_exempt"`
+	PriceListID       string  `db:"price_list_id" json:"price_list_id"`
+	PaymentTermID     string  `db:"payment_term_id" json:"payment_term_id"`
+	CreditLimit       float64 `db:"credit_limit" json:"credit_limit"`
+	CreditHold        int     `db:"credit_hold" json:"credit_hold"`
+	Status            int     `db:"status" json:"status"`
 }
 
 type CustomerNew struct {
-	Name              string `json:"name" binding:"required,min=1,max=64"`
-	ContactSalutation string `json:"contact_salutation" binding:"omitempty,max=64"`
-	ContactFirstName  string `json:"contact_first_name" binding:"omitempty,max=64"`
-	ContactLastName   string `json:"contact_last_name" binding:"omitempty,max=64"`
-	ContactEmail      string `json:"contact_email" binding:"omitempty,email,max=64"`
-	ContactPhone      string `json:"contact_phone" binding:"omitempty,max=64"`
-	Country           string `json:"country" binding:"omitempty,max=64"`
-	State             string `json:"state" binding:"omitempty,max=64"`
-	City              string `json:"city" binding:"omitempty,max=64"`
-	Address1          string `json:"address1" binding:"omitempty,max=255"`
-	Address2          string `json:"address2" binding:"omitempty,max=255"`
-	Zip               string `json:"zip" binding:"omitempty,max=64"`
-	Phone             string `json:"phone" binding:"omitempty,max=64"`
-	Fax               string `json:"fax" binding:"omitempty,max=64"`
-	Status            int    `json:"status" binding:"required,oneof=1 2"`
-	OrganizationID    string `json:"organiztion_id" swaggerignore:"true"`
-	User              string `json:"user" swaggerignore:"true"`
+	Name              string  `json:"name" binding:"required,min=1,max=64"`
+	ContactSalutation string  `json:"contact_salutation" binding:"omitempty,max=64"`
+	ContactFirstName  string  `json:"contact_first_name" binding:"omitempty,max=64"`
+	ContactLastName   string  `json:"contact_last_name" binding:"omitempty,max=64"`
+	ContactEmail      string  `json:"contact_email" binding:"omitempty,email,max=64"`
+	ContactPhone      string  `json:"contact_phone" binding:"omitempty,max=64"`
+	Country           string  `json:"country" binding:"omitempty,max=64"`
+	State             string  `json:"state" binding:"omitempty,max=64"`
+	City              string  `json:"city" binding:"omitempty,max=64"`
+	Address1          string  `json:"address1" binding:"omitempty,max=255"`
+	Address2          string  `json:"address2" binding:"omitempty,max=255"`
+	Zip               string  `json:"zip" binding:"omitempty,max=64"`
+	Phone             string  `json:"phone" binding:"omitempty,max=64"`
+	Fax               string  `json:"fax" binding:"omitempty,max=64"`
+	TaxExempt         int     `json:"tax_exempt" binding:"omitempty,oneof=1 2"`
+	PriceListID       string  `json:"price_list_id" binding:"omitempty,max=64"`
+	PaymentTermID     string  `json:"payment_term_id" binding:"omitempty,max=64"`
+	CreditLimit       float64 `json:"credit_limit" binding:"omitempty,min=0"`
+	CreditHold        int     `json:"credit_hold" binding:"omitempty,oneof=1 2"`
+	Status            int     `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID    string  `json:"organiztion_id" swaggerignore:"true"`
+	User              string  `json:"user" swaggerignore:"true"`
 }
 
 type CustomerID struct {
@@ -219,14 +280,18 @@ type CarrierResponse struct {
 	CarrierID      string `db:"carrier_id" json:"carrier_id"`
 	OrganizationID string `db:"organization_id" json:"organization_id"`
 	Name           string `db:"name" json:"name"`
+	Adapter        string `db:"adapter" json:"adapter"`
+	Config         string `db:"config" json:"-"`
 	Status         int    `db:"status" json:"status"`
 }
 
 type CarrierNew struct {
-	Name           string `json:"name" binding:"required,min=1,max=64"`
-	Status         int    `json:"status" binding:"required,oneof=1 2"`
-	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
-	User           string `json:"user" swaggerignore:"true"`
+	Name           string            `json:"name" binding:"required,min=1,max=64"`
+	Adapter        string            `json:"adapter" binding:"omitempty,max=32"`
+	Config         map[string]string `json:"config" binding:"omitempty"`
+	Status         int               `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string            `json:"organiztion_id" swaggerignore:"true"`
+	User           string            `json:"user" swaggerignore:"true"`
 }
 
 type CarrierID struct {
@@ -284,3 +349,162 @@ type PaymentMethodNew struct {
 type PaymentMethodID struct {
 	ID string `uri:"id" binding:"required,min=1"`
 }
+
+//payment_term
+
+type PaymentTermFilter struct {
+	Name           string `form:"name" binding:"omitempty,max=64,min=1"`
+	TermType       int    `form:"term_type" binding:"omitempty,oneof=1 2 3"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type PaymentTermResponse struct {
+	PaymentTermID   string  `db:"payment_term_id" json:"payment_term_id"`
+	OrganizationID  string  `db:"organization_id" json:"organization_id"`
+	Name            string  `db:"name" json:"name"`
+	TermType        int     `db:"term_type" json:"term_type"`
+	NetDays         int     `db:"net_days" json:"net_days"`
+	DiscountPercent float64 `db:"discount_percent" json:"discount_percent"`
+	DiscountDays    int     `db:"discount_days" json:"discount_days"`
+	Status          int     `db:"status" json:"status"`
+}
+
+type PaymentTermNew struct {
+	Name            string  `json:"name" binding:"required,min=1,max=64"`
+	TermType        int     `json:"term_type" binding:"required,oneof=1 2 3"`
+	NetDays         int     `json:"net_days" binding:"omitempty,min=0,max=365"`
+	DiscountPercent float64 `json:"discount_percent" binding:"omitempty,min=0,max=100"`
+	DiscountDays    int     `json:"discount_days" binding:"omitempty,min=0,max=365"`
+	Status          int     `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID  string  `json:"organiztion_id" swaggerignore:"true"`
+	User            string  `json:"user" swaggerignore:"true"`
+}
+
+type PaymentTermID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+type PaymentTermDatesResponse struct {
+	PaymentTermID   string  `json:"payment_term_id"`
+	DueDate         string  `json:"due_date"`
+	DiscountDate    string  `json:"discount_date"`
+	DiscountPercent float64 `json:"discount_percent"`
+}
+
+type DocumentTemplateNew struct {
+	Title          string `json:"title" binding:"omitempty,max=64"`
+	CompanyInfo    string `json:"company_info" binding:"omitempty,max=512"`
+	HeaderText     string `json:"header_text" binding:"omitempty,max=1024"`
+	FooterText     string `json:"footer_text" binding:"omitempty,max=512"`
+	AccentColor    string `json:"accent_color" binding:"omitempty,hexcolor,len=7"`
+	PaperSize      int    `json:"paper_size" binding:"required,oneof=1 2"`
+	ShowLogo       int    `json:"show_logo" binding:"required,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type DocumentTemplateResponse struct {
+	OrganizationID string `db:"organization_id" json:"organization_id"`
+	DocumentType   int    `db:"document_type" json:"document_type"`
+	Title          string `db:"title" json:"title"`
+	CompanyInfo    string `db:"company_info" json:"company_info"`
+	HeaderText     string `db:"header_text" json:"header_text"`
+	FooterText     string `db:"footer_text" json:"footer_text"`
+	AccentColor    string `db:"accent_color" json:"accent_color"`
+	PaperSize      int    `db:"paper_size" json:"paper_size"`
+	ShowLogo       int    `db:"show_logo" json:"show_logo"`
+	IsDefault      int    `db:"is_default" json:"is_default"`
+}
+
+type DocumentTemplateType struct {
+	DocumentType int `uri:"type" binding:"required,oneof=1 2 3 4 5"`
+}
+
+type DocumentLogoResponse struct {
+	OrganizationID string `db:"organization_id" json:"organization_id"`
+	FileName       string `db:"file_name" json:"file_name"`
+	ContentType    string `db:"content_type" json:"content_type"`
+	Data           []byte `db:"data" json:"-"`
+}
+
+//address
+
+type AddressResponse struct {
+	AddressID         string `db:"address_id" json:"address_id"`
+	OrganizationID    string `db:"organization_id" json:"organization_id"`
+	OwnerType         string `db:"owner_type" json:"owner_type"`
+	OwnerID           string `db:"owner_id" json:"owner_id"`
+	Name              string `db:"name" json:"name"`
+	Attention         string `db:"attention" json:"attention"`
+	Country           string `db:"country" json:"country"`
+	State             string `db:"state" json:"state"`
+	City              string `db:"city" json:"city"`
+	Address1          string `db:"address1" json:"address1"`
+	Address2          string `db:"address2" json:"address2"`
+	Zip               string `db:"zip" json:"zip"`
+	Phone             string `db:"phone" json:"phone"`
+	Fax               string `db:"fax" json:"fax"`
+	IsDefaultBilling  int    `db:"is_default_billing" json:"is_default_billing"`
+	IsDefaultShipping int    `db:"is_default_shipping" json:"is_default_shipping"`
+	Status            int    `db:"status" json:"status"`
+}
+
+type AddressNew struct {
+	Name              string `json:"name" binding:"omitempty,max=64"`
+	Attention         string `json:"attention" binding:"omitempty,max=64"`
+	Country           string `json:"country" binding:"omitempty,max=64"`
+	State             string `json:"state" binding:"omitempty,max=64"`
+	City              string `json:"city" binding:"omitempty,max=64"`
+	Address1          string `json:"address1" binding:"required,max=255"`
+	Address2          string `json:"address2" binding:"omitempty,max=255"`
+	Zip               string `json:"zip" binding:"omitempty,max=64"`
+	Phone             string `json:"phone" binding:"omitempty,max=64"`
+	Fax               string `json:"fax" binding:"omitempty,max=64"`
+	IsDefaultBilling  int    `json:"is_default_billing" binding:"omitempty,oneof=1 2"`
+	IsDefaultShipping int    `json:"is_default_shipping" binding:"omitempty,oneof=1 2"`
+	Status            int    `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID    string `json:"organiztion_id" swaggerignore:"true"`
+	User              string `json:"user" swaggerignore:"true"`
+}
+
+type AddressID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
+
+//contact
+
+type ContactResponse struct {
+	ContactID      string `db:"contact_id" json:"contact_id"`
+	OrganizationID string `db:"organization_id" json:"organization_id"`
+	OwnerType      string `db:"owner_type" json:"owner_type"`
+	OwnerID        string `db:"owner_id" json:"owner_id"`
+	Salutation     string `db:"salutation" json:"salutation"`
+	FirstName      string `db:"first_name" json:"first_name"`
+	LastName       string `db:"last_name" json:"last_name"`
+	Email          string `db:"email" json:"email"`
+	Phone          string `db:"phone" json:"phone"`
+	Mobile         string `db:"mobile" json:"mobile"`
+	Designation    string `db:"designation" json:"designation"`
+	IsPrimary      int    `db:"is_primary" json:"is_primary"`
+	Status         int    `db:"status" json:"status"`
+}
+
+type ContactNew struct {
+	Salutation     string `json:"salutation" binding:"omitempty,max=64"`
+	FirstName      string `json:"first_name" binding:"required,max=64"`
+	LastName       string `json:"last_name" binding:"omitempty,max=64"`
+	Email          string `json:"email" binding:"omitempty,email,max=64"`
+	Phone          string `json:"phone" binding:"omitempty,max=64"`
+	Mobile         string `json:"mobile" binding:"omitempty,max=64"`
+	Designation    string `json:"designation" binding:"omitempty,max=64"`
+	IsPrimary      int    `json:"is_primary" binding:"omitempty,oneof=1 2"`
+	Status         int    `json:"status" binding:"required,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+}
+
+type ContactID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
@@ -1,6 +1,12 @@
 package setting
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
 
 type Unit struct {
 	ID             int64     `db:"id" json:"id"`
@@ -57,6 +63,8 @@ type Vendor struct {
 	Zip               string    `db:"zip" json:"zip"`
 	Phone             string    `db:"phone" json:"phone"`
 	Fax               string    `db:"fax" json:"fax"`
+	PaymentTermID     string    `db:"payment_term_id" json:"payment_term_id"`
+	LeadTime          int       `db:"lead_time" json:"lead_time"`
 	Status            int       `db:"status" json:"status"`
 	Created           time.Time `db:"created" json:"created"`
 	CreatedBy         string    `db:"created_by" json:"created_by"`
@@ -70,6 +78,8 @@ type Tax struct {
 	OrganizationID string    `db:"organization_id" json:"organization_id"`
 	Name           string    `db:"name" json:"name"`
 	TaxValue       float64   `db:"tax_value" json:"tax_value"`
+	TaxType        int       `db:"tax_type" json:"tax_type"`
+	IsCompound     int       `db:"is_compound" json:"is_compound"`
 	Status         int       `db:"status" json:"status"`
 	Created        time.Time `db:"created" json:"created"`
 	CreatedBy      string    `db:"created_by" json:"created_by"`
@@ -95,6 +105,11 @@ type Customer struct {
 	Zip               string    `db:"zip" json:"zip"`
 	Phone             string    `db:"phone" json:"phone"`
 	Fax               string    `db:"fax" json:"fax"`
+	TaxExempt         int       `db:"tax_exempt" json:"tax_exempt"`
+	PriceListID       string    `db:"price_list_id" json:"price_list_id"`
+	PaymentTermID     string    `db:"payment_term_id" json:"payment_term_id"`
+	CreditLimit       float64   `db:"credit_limit" json:"credit_limit"`
+	CreditHold        int       `db:"credit_hold" json:"credit_hold"`
 	Status            int       `db:"status" json:"status"`
 	Created           time.Time `db:"created" json:"created"`
 	CreatedBy         string    `db:"created_by" json:"created_by"`
@@ -107,6 +122,8 @@ type Carrier struct {
 	CarrierID      string    `db:"carrier_id" json:"carrier_id"`
 	OrganizationID string    `db:"organization_id" json:"organization_id"`
 	Name           string    `db:"name" json:"name"`
+	Adapter        string    `db:"adapter" json:"adapter"`
+	Config         string    `db:"config" json:"config"`
 	Status         int       `db:"status" json:"status"`
 	Created        time.Time `db:"created" json:"created"`
 	CreatedBy      string    `db:"created_by" json:"created_by"`
@@ -137,3 +154,178 @@ type PaymentMethod struct {
 	Updated         time.Time `db:"updated" json:"updated"`
 	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
 }
+
+type TaxGroupItem struct {
+	ID             int64     `db:"id" json:"id"`
+	TaxGroupItemID string    `db:"tax_group_item_id" json:"tax_group_item_id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	TaxGroupID     string    `db:"tax_group_id" json:"tax_group_id"`
+	TaxID          string    `db:"tax_id" json:"tax_id"`
+	Sequence       int       `db:"sequence" json:"sequence"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type TaxRecord struct {
+	ID              int64     `db:"id" json:"id"`
+	TaxRecordID     string    `db:"tax_record_id" json:"tax_record_id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	ReferenceType   string    `db:"reference_type" json:"reference_type"`
+	ReferenceID     string    `db:"reference_id" json:"reference_id"`
+	ReferenceItemID string    `db:"reference_item_id" json:"reference_item_id"`
+	RecordDate      string    `db:"record_date" json:"record_date"`
+	TaxID           string    `db:"tax_id" json:"tax_id"`
+	TaxName         string    `db:"tax_name" json:"tax_name"`
+	TaxValue        float64   `db:"tax_value" json:"tax_value"`
+	TaxableAmount   float64   `db:"taxable_amount" json:"taxable_amount"`
+	TaxAmount       float64   `db:"tax_amount" json:"tax_amount"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type PaymentTerm struct {
+	ID              int64     `db:"id" json:"id"`
+	PaymentTermID   string    `db:"payment_term_id" json:"payment_term_id"`
+	OrganizationID  string    `db:"organization_id" json:"organization_id"`
+	Name            string    `db:"name" json:"name"`
+	TermType        int       `db:"term_type" json:"term_type"`
+	NetDays         int       `db:"net_days" json:"net_days"`
+	DiscountPercent float64   `db:"discount_percent" json:"discount_percent"`
+	DiscountDays    int       `db:"discount_days" json:"discount_days"`
+	Status          int       `db:"status" json:"status"`
+	Created         time.Time `db:"created" json:"created"`
+	CreatedBy       string    `db:"created_by" json:"created_by"`
+	Updated         time.Time `db:"updated" json:"updated"`
+	UpdatedBy       string    `db:"updated_by" json:"updated_by"`
+}
+
+type DocumentTemplate struct {
+	ID                 int64     `db:"id" json:"id"`
+	DocumentTemplateID string    `db:"document_template_id" json:"document_template_id"`
+	OrganizationID     string    `db:"organization_id" json:"organization_id"`
+	DocumentType       int       `db:"document_type" json:"document_type"` //1 invoice 2 purchase order 3 packing slip 4 pick list 5 bill of lading
+	Title              string    `db:"title" json:"title"`
+	CompanyInfo        string    `db:"company_info" json:"company_info"`
+	HeaderText         string    `db:"header_text" json:"header_text"`
+	FooterText         string    `db:"footer_text" json:"footer_text"`
+	AccentColor        string    `db:"accent_color" json:"accent_color"`
+	PaperSize          int       `db:"paper_size" json:"paper_size"` //1 A4 2 letter
+	ShowLogo           int       `db:"show_logo" json:"show_logo"`
+	Status             int       `db:"status" json:"status"`
+	Created            time.Time `db:"created" json:"created"`
+	CreatedBy          string    `db:"created_by" json:"created_by"`
+	Updated            time.Time `db:"updated" json:"updated"`
+	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
+}
+
+type DocumentLogo struct {
+	ID             int64     `db:"id" json:"id"`
+	DocumentLogoID string    `db:"document_logo_id" json:"document_logo_id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	FileName       string    `db:"file_name" json:"file_name"`
+	ContentType    string    `db:"content_type" json:"content_type"`
+	Data           []byte    `db:"data" json:"data"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type Address struct {
+	ID                int64     `db:"id" json:"id"`
+	AddressID         string    `db:"address_id" json:"address_id"`
+	OrganizationID    string    `db:"organization_id" json:"organization_id"`
+	OwnerType         string    `db:"owner_type" json:"owner_type"` //customer vendor
+	OwnerID           string    `db:"owner_id" json:"owner_id"`
+	Name              string    `db:"name" json:"name"`
+	Attention         string    `db:"attention" json:"attention"`
+	Country           string    `db:"country" json:"country"`
+	State             string    `db:"state" json:"state"`
+	City              string    `db:"city" json:"city"`
+	Address1          string    `db:"address1" json:"address1"`
+	Address2          string    `db:"address2" json:"address2"`
+	Zip               string    `db:"zip" json:"zip"`
+	Phone             string    `db:"phone" json:"phone"`
+	Fax               string    `db:"fax" json:"fax"`
+	IsDefaultBilling  int       `db:"is_default_billing" json:"is_default_billing"`
+	IsDefaultShipping int       `db:"is_default_shipping" json:"is_default_shipping"`
+	Status            int       `db:"status" json:"status"`
+	Created           time.Time `db:"created" json:"created"`
+	CreatedBy         string    `db:"created_by" json:"created_by"`
+	Updated           time.Time `db:"updated" json:"updated"`
+	UpdatedBy         string    `db:"updated_by" json:"updated_by"`
+}
+
+type Contact struct {
+	ID             int64     `db:"id" json:"id"`
+	ContactID      string    `db:"contact_id" json:"contact_id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	OwnerType      string    `db:"owner_type" json:"owner_type"` //customer vendor
+	OwnerID        string    `db:"owner_id" json:"owner_id"`
+	Salutation     string    `db:"salutation" json:"salutation"`
+	FirstName      string    `db:"first_name" json:"first_name"`
+	LastName       string    `db:"last_name" json:"last_name"`
+	Email          string    `db:"email" json:"email"`
+	Phone          string    `db:"phone" json:"phone"`
+	Mobile         string    `db:"mobile" json:"mobile"`
+	Designation    string    `db:"designation" json:"designation"`
+	IsPrimary      int       `db:"is_primary" json:"is_primary"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+// AddressSnapshot is the copy of an address kept on a document, it is stored
+// as json so later edits of the address book don't change the document
+type AddressSnapshot struct {
+	AddressID string `json:"address_id"`
+	Name      string `json:"name"`
+	Attention string `json:"attention"`
+	Country   string `json:"country"`
+	State     string `json:"state"`
+	City      string `json:"city"`
+	Address1  string `json:"address1"`
+	Address2  string `json:"address2"`
+	Zip       string `json:"zip"`
+	Phone     string `json:"phone"`
+}
+
+func (a AddressSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+func (a *AddressSnapshot) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*a = AddressSnapshot{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("address snapshot type error")
+	}
+	if len(b) == 0 {
+		*a = AddressSnapshot{}
+		return nil
+	}
+	return json.Unmarshal(b, a)
+}
+
+// Lines returns the printable lines of the address
+func (a AddressSnapshot) Lines() []string {
+	cityLine := strings.TrimSpace(strings.Join([]string{a.City, a.State, a.Zip}, " "))
+	return []string{a.Name, a.Attention, a.Address1, a.Address2, cityLine, a.Country, a.Phone}
+}
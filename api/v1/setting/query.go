@@ -156,7 +156,7 @@ func (r *settingQuery) GetBrandList(filter BrandFilter) (*[]BrandResponse, error
 
 func (r *settingQuery) GetVendorByID(organizationID, id string) (*VendorResponse, error) {
 	var vendor VendorResponse
-	err := r.conn.Get(&vendor, "SELECT vendor_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, status FROM s_vendors WHERE organization_id = ? AND vendor_id = ? AND status > 0", organizationID, id)
+	err := r.conn.Get(&vendor, "SELECT vendor_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, payment_term_id, lead_time, status FROM s_vendors WHERE organization_id = ? AND vendor_id = ? AND status > 0", organizationID, id)
 	return &vendor, err
 }
 
@@ -188,7 +188,7 @@ func (r *settingQuery) GetVendorList(filter VendorFilter) (*[]VendorResponse, er
 	args = append(args, filter.PageSize)
 	var vendors []VendorResponse
 	err := r.conn.Select(&vendors, `
-		SELECT vendor_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, status
+		SELECT vendor_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, payment_term_id, lead_time, status
 		FROM s_vendors
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
@@ -200,7 +200,7 @@ func (r *settingQuery) GetVendorList(filter VendorFilter) (*[]VendorResponse, er
 
 func (r *settingQuery) GetTaxByID(organizationID, id string) (*TaxResponse, error) {
 	var tax TaxResponse
-	err := r.conn.Get(&tax, "SELECT tax_id, organization_id, name, tax_value, status FROM s_taxes WHERE organization_id = ? AND tax_id = ? AND status > 0", organizationID, id)
+	err := r.conn.Get(&tax, "SELECT tax_id, organization_id, name, tax_value, tax_type, is_compound, status FROM s_taxes WHERE organization_id = ? AND tax_id = ? AND status > 0", organizationID, id)
 	return &tax, err
 }
 
@@ -232,7 +232,7 @@ func (r *settingQuery) GetTaxList(filter TaxFilter) (*[]TaxResponse, error) {
 	args = append(args, filter.PageSize)
 	var taxes []TaxResponse
 	err := r.conn.Select(&taxes, `
-		SELECT tax_id, organization_id, name, tax_value, status
+		SELECT tax_id, organization_id, name, tax_value, tax_type, is_compound, status
 		FROM s_taxes
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
@@ -240,11 +240,32 @@ func (r *settingQuery) GetTaxList(filter TaxFilter) (*[]TaxResponse, error) {
 	return &taxes, err
 }
 
+func (r *settingQuery) GetTaxComponents(organizationID, taxID string) (*[]TaxComponentResponse, error) {
+	var components []TaxComponentResponse
+	tax, err := r.GetTaxByID(organizationID, taxID)
+	if err != nil {
+		return nil, err
+	}
+	if tax.TaxType != 2 {
+		components = append(components, TaxComponentResponse{TaxID: tax.TaxID, Name: tax.Name, TaxValue: tax.TaxValue, IsCompound: tax.IsCompound, Sequence: 1})
+		return &components, nil
+	}
+	err = r.conn.Select(&components, `
+		SELECT t.tax_id, t.name, t.tax_value, t.is_compound, g.sequence
+		FROM s_tax_group_items g
+		LEFT JOIN s_taxes t
+		ON g.tax_id = t.tax_id
+		WHERE g.organization_id = ? AND g.tax_group_id = ? AND g.status > 0
+		ORDER BY g.sequence ASC
+	`, organizationID, taxID)
+	return &components, err
+}
+
 //Customer
 
 func (r *settingQuery) GetCustomerByID(organizationID, id string) (*CustomerResponse, error) {
 	var customer CustomerResponse
-	err := r.conn.Get(&customer, "SELECT customer_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, status FROM s_customers WHERE organization_id = ? AND customer_id = ? AND status > 0", organizationID, id)
+	err := r.conn.Get(&customer, "SELECT customer_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, tax_exempt, price_list_id, payment_term_id, credit_limit, credit_hold, status FROM s_customers WHERE organization_id = ? AND customer_id = ? AND status > 0", organizationID, id)
 	return &customer, err
 }
 
@@ -276,7 +297,7 @@ func (r *settingQuery) GetCustomerList(filter CustomerFilter) (*[]CustomerRespon
 	args = append(args, filter.PageSize)
 	var customers []CustomerResponse
 	err := r.conn.Select(&customers, `
-		SELECT customer_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, status
+		SELECT customer_id, organization_id, name, contact_salutation, contact_first_name, contact_last_name, contact_email, contact_phone, country, state, city, address1, address2, zip, phone, fax, tax_exempt, price_list_id, payment_term_id, credit_limit, credit_hold, status
 		FROM s_customers
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
@@ -288,7 +309,15 @@ func (r *settingQuery) GetCustomerList(filter CustomerFilter) (*[]CustomerRespon
 
 func (r *settingQuery) GetCarrierByID(organizationID, id string) (*CarrierResponse, error) {
 	var carrier CarrierResponse
-	err := r.conn.Get(&carrier, "SELECT carrier_id, organization_id, name, status FROM s_carriers WHERE organization_id = ? AND carrier_id = ? AND status > 0", organizationID, id)
+	err := r.conn.Get(&carrier, "SELECT carrier_id, organization_id, name, adapter, config, status FROM s_carriers WHERE organization_id = ? AND carrier_id = ? AND status > 0", organizationID, id)
+	return &carrier, err
+}
+
+// GetWebhookCarrierByID finds a carrier without the organization, carriers
+// pushing tracking updates only know the carrier id
+func (r *settingQuery) GetWebhookCarrierByID(id string) (*CarrierResponse, error) {
+	var carrier CarrierResponse
+	err := r.conn.Get(&carrier, "SELECT carrier_id, organization_id, name, adapter, config, status FROM s_carriers WHERE carrier_id = ? AND status > 0", id)
 	return &carrier, err
 }
 
@@ -320,7 +349,7 @@ func (r *settingQuery) GetCarrierList(filter CarrierFilter) (*[]CarrierResponse,
 	args = append(args, filter.PageSize)
 	var carriers []CarrierResponse
 	err := r.conn.Select(&carriers, `
-		SELECT carrier_id, organization_id, name, status
+		SELECT carrier_id, organization_id, name, adapter, config, status
 		FROM s_carriers
 		WHERE `+strings.Join(where, " AND ")+`
 		LIMIT ?, ?
@@ -415,3 +444,127 @@ func (r *settingQuery) GetPaymentMethodList(filter PaymentMethodFilter) (*[]Paym
 	`, args...)
 	return &paymentMethods, err
 }
+
+//PaymentTerm
+
+func (r *settingQuery) GetPaymentTermByID(organizationID, id string) (*PaymentTermResponse, error) {
+	var paymentTerm PaymentTermResponse
+	err := r.conn.Get(&paymentTerm, "SELECT payment_term_id, organization_id, name, term_type, net_days, discount_percent, discount_days, status FROM s_payment_terms WHERE organization_id = ? AND payment_term_id = ? AND status > 0", organizationID, id)
+	return &paymentTerm, err
+}
+
+func (r *settingQuery) GetPaymentTermCount(filter PaymentTermFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.TermType; v != 0 {
+		where, args = append(where, "term_type = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM s_payment_terms
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *settingQuery) GetPaymentTermList(filter PaymentTermFilter) (*[]PaymentTermResponse, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.Name; v != "" {
+		where, args = append(where, "name like ?"), append(args, "%"+v+"%")
+	}
+	if v := filter.TermType; v != 0 {
+		where, args = append(where, "term_type = ?"), append(args, v)
+	}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var paymentTerms []PaymentTermResponse
+	err := r.conn.Select(&paymentTerms, `
+		SELECT payment_term_id, organization_id, name, term_type, net_days, discount_percent, discount_days, status
+		FROM s_payment_terms
+		WHERE `+strings.Join(where, " AND ")+`
+		LIMIT ?, ?
+	`, args...)
+	return &paymentTerms, err
+}
+
+//DocumentTemplate
+
+func (r *settingQuery) GetDocumentTemplateList(organizationID string) (*[]DocumentTemplateResponse, error) {
+	var documentTemplates []DocumentTemplateResponse
+	err := r.conn.Select(&documentTemplates, `
+		SELECT organization_id, document_type, title, company_info, header_text, footer_text, accent_color, paper_size, show_logo, 2 as is_default
+		FROM s_document_templates
+		WHERE organization_id = ? AND status > 0
+		ORDER BY document_type ASC
+	`, organizationID)
+	return &documentTemplates, err
+}
+
+func (r *settingQuery) GetDocumentTemplate(organizationID string, documentType int) (*DocumentTemplateResponse, error) {
+	var documentTemplate DocumentTemplateResponse
+	err := r.conn.Get(&documentTemplate, `
+		SELECT organization_id, document_type, title, company_info, header_text, footer_text, accent_color, paper_size, show_logo, 2 as is_default
+		FROM s_document_templates
+		WHERE organization_id = ? AND document_type = ? AND status > 0
+		LIMIT 1
+	`, organizationID, documentType)
+	return &documentTemplate, err
+}
+
+func (r *settingQuery) GetDocumentLogo(organizationID string) (*DocumentLogoResponse, error) {
+	var documentLogo DocumentLogoResponse
+	err := r.conn.Get(&documentLogo, `
+		SELECT organization_id, file_name, content_type, data
+		FROM s_document_logos
+		WHERE organization_id = ? AND status > 0
+		ORDER BY id DESC
+		LIMIT 1
+	`, organizationID)
+	return &documentLogo, err
+}
+
+//Address
+
+func (r *settingQuery) GetAddressByID(organizationID, id string) (*AddressResponse, error) {
+	var address AddressResponse
+	err := r.conn.Get(&address, "SELECT address_id, organization_id, owner_type, owner_id, name, attention, country, state, city, address1, address2, zip, phone, fax, is_default_billing, is_default_shipping, status FROM s_addresses WHERE organization_id = ? AND address_id = ? AND status > 0", organizationID, id)
+	return &address, err
+}
+
+func (r *settingQuery) GetAddressList(organizationID, ownerType, ownerID string) (*[]AddressResponse, error) {
+	var addresses []AddressResponse
+	err := r.conn.Select(&addresses, `
+		SELECT address_id, organization_id, owner_type, owner_id, name, attention, country, state, city, address1, address2, zip, phone, fax, is_default_billing, is_default_shipping, status
+		FROM s_addresses
+		WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND status > 0
+		ORDER BY id ASC
+	`, organizationID, ownerType, ownerID)
+	return &addresses, err
+}
+
+//Contact
+
+func (r *settingQuery) GetContactByID(organizationID, id string) (*ContactResponse, error) {
+	var contact ContactResponse
+	err := r.conn.Get(&contact, "SELECT contact_id, organization_id, owner_type, owner_id, salutation, first_name, last_name, email, phone, mobile, designation, is_primary, status FROM s_contacts WHERE organization_id = ? AND contact_id = ? AND status > 0", organizationID, id)
+	return &contact, err
+}
+
+func (r *settingQuery) GetContactList(organizationID, ownerType, ownerID string) (*[]ContactResponse, error) {
+	var contacts []ContactResponse
+	err := r.conn.Select(&contacts, `
+		SELECT contact_id, organization_id, owner_type, owner_id, salutation, first_name, last_name, email, phone, mobile, designation, is_primary, status
+		FROM s_contacts
+		WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND status > 0
+		ORDER BY id ASC
+	`, organizationID, ownerType, ownerID)
+	return &contacts, err
+}
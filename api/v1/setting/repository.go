@@ -2,8 +2,12 @@ package setting
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/rs/xid"
 )
 
 type settingRepository struct {
@@ -238,10 +242,12 @@ func (r *settingRepository) GetVendorByID(vendorID, organizationID string) (*Ven
 	zip,
 	phone,
 	fax,
+	payment_term_id,
+	lead_time,
 	status
 	FROM s_vendors 
 	WHERE vendor_id = ? AND organization_id = ? AND status > 0 LIMIT 1`, vendorID, organizationID)
-	err := row.Scan(&res.VendorID, &res.OrganizationID, &res.Name, &res.ContactSalutation, &res.ContactFirstName, &res.ContactLastName, &res.ContactEmail, &res.ContactPhone, &res.Country, &res.State, &res.City, &res.Address1, &res.Address2, &res.Zip, &res.Phone, &res.Fax, &res.Status)
+	err := row.Scan(&res.VendorID, &res.OrganizationID, &res.Name, &res.ContactSalutation, &res.ContactFirstName, &res.ContactLastName, &res.ContactEmail, &res.ContactPhone, &res.Country, &res.State, &res.City, &res.Address1, &res.Address2, &res.Zip, &res.Phone, &res.Fax, &res.PaymentTermID, &res.LeadTime, &res.Status)
 	return &res, err
 }
 
@@ -275,14 +281,16 @@ func (r *settingRepository) CreateVendor(info Vendor) error {
 			zip,
 			phone,
 			fax,
+			payment_term_id,
+			lead_time,
 			status,
 			created,
 			created_by,
 			updated,
 			updated_by
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.VendorID, info.OrganizationID, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.VendorID, info.OrganizationID, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.PaymentTermID, info.LeadTime, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -303,11 +311,13 @@ func (r *settingRepository) UpdateVendor(id string, info Vendor) error {
 		zip = ?,
 		phone = ?,
 		fax = ?,
+		payment_term_id = ?,
+		lead_time = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE vendor_id = ?
-	`, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.PaymentTermID, info.LeadTime, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -339,10 +349,12 @@ func (r *settingRepository) GetTaxByID(taxID, organizationID string) (*TaxRespon
 	organization_id,
 	name,
 	tax_value,
+	tax_type,
+	is_compound,
 	status
 	FROM s_taxes 
 	WHERE tax_id = ? AND organization_id = ? AND status > 0 LIMIT 1`, taxID, organizationID)
-	err := row.Scan(&res.TaxID, &res.OrganizationID, &res.Name, &res.TaxValue, &res.Status)
+	err := row.Scan(&res.TaxID, &res.OrganizationID, &res.Name, &res.TaxValue, &res.TaxType, &res.IsCompound, &res.Status)
 	return &res, err
 }
 
@@ -364,14 +376,16 @@ func (r *settingRepository) CreateTax(info Tax) error {
 			organization_id,
 			name,
 			tax_value,
+			tax_type,
+			is_compound,
 			status,
 			created,
 			created_by,
 			updated,
 			updated_by
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.TaxID, info.OrganizationID, info.Name, info.TaxValue, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.TaxID, info.OrganizationID, info.Name, info.TaxValue, info.TaxType, info.IsCompound, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -380,11 +394,24 @@ func (r *settingRepository) UpdateTax(id string, info Tax) error {
 		Update s_taxes SET
 		name = ?,
 		tax_value = ?,
+		tax_type = ?,
+		is_compound = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE tax_id = ?
-	`, info.Name, info.TaxValue, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.Name, info.TaxValue, info.TaxType, info.IsCompound, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *settingRepository) UpdateTaxValue(id string, taxValue float64, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_taxes SET
+		tax_value = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE tax_id = ?
+	`, taxValue, time.Now(), byUser, id)
 	return err
 }
 
@@ -413,6 +440,164 @@ func (r *settingRepository) GetSOTaxCount(taxID, organizationID string) (int, er
 	return count, err
 }
 
+func (r *settingRepository) GetTaxGroupCount(taxID, organizationID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_tax_group_items WHERE organization_id = ? AND tax_id = ? AND status > 0 ", organizationID, taxID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (r *settingRepository) CreateTaxGroupItem(info TaxGroupItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_tax_group_items
+		(
+			tax_group_item_id,
+			organization_id,
+			tax_group_id,
+			tax_id,
+			sequence,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.TaxGroupItemID, info.OrganizationID, info.TaxGroupID, info.TaxID, info.Sequence, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) DeleteTaxGroupItem(taxGroupID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_tax_group_items SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE tax_group_id = ?
+	`, time.Now(), byUser, taxGroupID)
+	return err
+}
+
+func (r *settingRepository) GetTaxComponents(taxID, organizationID string) (*[]TaxComponentResponse, error) {
+	var components []TaxComponentResponse
+	tax, err := r.GetTaxByID(taxID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if tax.TaxType != 2 {
+		components = append(components, TaxComponentResponse{TaxID: tax.TaxID, Name: tax.Name, TaxValue: tax.TaxValue, IsCompound: tax.IsCompound, Sequence: 1})
+		return &components, nil
+	}
+	rows, err := r.tx.Query(`
+		SELECT
+		t.tax_id,
+		t.name,
+		t.tax_value,
+		t.is_compound,
+		g.sequence
+		FROM s_tax_group_items g
+		LEFT JOIN s_taxes t
+		ON g.tax_id = t.tax_id
+		WHERE g.organization_id = ? AND g.tax_group_id = ? AND g.status > 0
+		ORDER BY g.sequence ASC
+	`, organizationID, taxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res TaxComponentResponse
+		err = rows.Scan(&res.TaxID, &res.Name, &res.TaxValue, &res.IsCompound, &res.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, res)
+	}
+	return &components, nil
+}
+
+func (r *settingRepository) GetTaxGroupIDsByComponent(taxID, organizationID string) ([]string, error) {
+	var groups []string
+	rows, err := r.tx.Query("SELECT tax_group_id FROM s_tax_group_items WHERE organization_id = ? AND tax_id = ? AND status > 0", organizationID, taxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var groupID string
+		err = rows.Scan(&groupID)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, groupID)
+	}
+	return groups, nil
+}
+
+func (r *settingRepository) CreateTaxRecord(info TaxRecord) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_tax_records
+		(
+			tax_record_id,
+			organization_id,
+			reference_type,
+			reference_id,
+			reference_item_id,
+			record_date,
+			tax_id,
+			tax_name,
+			tax_value,
+			taxable_amount,
+			tax_amount,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.TaxRecordID, info.OrganizationID, info.ReferenceType, info.ReferenceID, info.ReferenceItemID, info.RecordDate, info.TaxID, info.TaxName, info.TaxValue, info.TaxableAmount, info.TaxAmount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) CreateTaxRecords(info TaxRecordNew) error {
+	for _, component := range info.Tax.Components {
+		var taxRecord TaxRecord
+		taxRecord.TaxRecordID = "tr-" + xid.New().String()
+		taxRecord.OrganizationID = info.OrganizationID
+		taxRecord.ReferenceType = info.ReferenceType
+		taxRecord.ReferenceID = info.ReferenceID
+		taxRecord.ReferenceItemID = info.ReferenceItemID
+		taxRecord.RecordDate = info.RecordDate
+		taxRecord.TaxID = component.TaxID
+		taxRecord.TaxName = component.Name
+		taxRecord.TaxValue = component.TaxValue
+		taxRecord.TaxableAmount = info.Tax.TaxableAmount
+		taxRecord.TaxAmount = component.TaxAmount
+		taxRecord.Status = 1
+		taxRecord.Created = time.Now()
+		taxRecord.CreatedBy = info.User
+		taxRecord.Updated = time.Now()
+		taxRecord.UpdatedBy = info.User
+		err := r.CreateTaxRecord(taxRecord)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *settingRepository) DeleteTaxRecord(referenceType, referenceID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_tax_records SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE reference_type = ? AND reference_id = ?
+	`, time.Now(), byUser, referenceType, referenceID)
+	return err
+}
+
 // Customer
 
 func (r *settingRepository) GetCustomerByID(customerID, organizationID string) (*CustomerResponse, error) {
@@ -435,10 +620,15 @@ func (r *settingRepository) GetCustomerByID(customerID, organizationID string) (
 	zip,
 	phone,
 	fax,
+	tax_exempt,
+	price_list_id,
+	payment_term_id,
+	credit_limit,
+	credit_hold,
 	status
 	FROM s_customers 
 	WHERE customer_id = ? AND organization_id = ? AND status > 0 LIMIT 1`, customerID, organizationID)
-	err := row.Scan(&res.CustomerID, &res.OrganizationID, &res.Name, &res.ContactSalutation, &res.ContactFirstName, &res.ContactLastName, &res.ContactEmail, &res.ContactPhone, &res.Country, &res.State, &res.City, &res.Address1, &res.Address2, &res.Zip, &res.Phone, &res.Fax, &res.Status)
+	err := row.Scan(&res.CustomerID, &res.OrganizationID, &res.Name, &res.ContactSalutation, &res.ContactFirstName, &res.ContactLastName, &res.ContactEmail, &res.ContactPhone, &res.Country, &res.State, &res.City, &res.Address1, &res.Address2, &res.Zip, &res.Phone, &res.Fax, &res.TaxExempt, &res.PriceListID, &res.PaymentTermID, &res.CreditLimit, &res.CreditHold, &res.Status)
 	return &res, err
 }
 
@@ -472,14 +662,19 @@ func (r *settingRepository) CreateCustomer(info Customer) error {
 			zip,
 			phone,
 			fax,
+			tax_exempt,
+			price_list_id,
+			payment_term_id,
+			credit_limit,
+			credit_hold,
 			status,
 			created,
 			created_by,
 			updated,
 			updated_by
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.CustomerID, info.OrganizationID, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.CustomerID, info.OrganizationID, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.TaxExempt, info.PriceListID, info.PaymentTermID, info.CreditLimit, info.CreditHold, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -500,11 +695,16 @@ func (r *settingRepository) UpdateCustomer(id string, info Customer) error {
 		zip = ?,
 		phone = ?,
 		fax = ?,
+		tax_exempt = ?,
+		price_list_id = ?,
+		payment_term_id = ?,
+		credit_limit = ?,
+		credit_hold = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE customer_id = ?
-	`, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.Name, info.ContactSalutation, info.ContactFirstName, info.ContactLastName, info.ContactEmail, info.ContactPhone, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.TaxExempt, info.PriceListID, info.PaymentTermID, info.CreditLimit, info.CreditHold, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -526,12 +726,22 @@ func (r *settingRepository) GetSOCustomerCount(customerID, organizationID string
 	return count, err
 }
 
+func (r *settingRepository) CheckPriceListExist(priceListID, organizationID string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_price_lists WHERE organization_id = ? AND price_list_id = ? AND status > 0", organizationID, priceListID)
+	err := row.Scan(&existed)
+	if err != nil {
+		return false, err
+	}
+	return existed != 0, nil
+}
+
 // Carrier
 
 func (r *settingRepository) GetCarrierByID(organizationID, carrierID string) (*CarrierResponse, error) {
 	var res CarrierResponse
-	row := r.tx.QueryRow(`SELECT carrier_id, organization_id, name, status FROM s_carriers WHERE organization_id = ? AND carrier_id = ? AND status > 0 LIMIT 1`, organizationID, carrierID)
-	err := row.Scan(&res.CarrierID, &res.OrganizationID, &res.Name, &res.Status)
+	row := r.tx.QueryRow(`SELECT carrier_id, organization_id, name, adapter, config, status FROM s_carriers WHERE organization_id = ? AND carrier_id = ? AND status > 0 LIMIT 1`, organizationID, carrierID)
+	err := row.Scan(&res.CarrierID, &res.OrganizationID, &res.Name, &res.Adapter, &res.Config, &res.Status)
 	return &res, err
 }
 
@@ -552,14 +762,16 @@ func (r *settingRepository) CreateCarrier(info Carrier) error {
 			carrier_id,
 			organization_id,
 			name,
+			adapter,
+			config,
 			status,
 			created,
 			created_by,
 			updated,
 			updated_by
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, info.CarrierID, info.OrganizationID, info.Name, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.CarrierID, info.OrganizationID, info.Name, info.Adapter, info.Config, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
 
@@ -567,11 +779,13 @@ func (r *settingRepository) UpdateCarrier(id string, info Carrier) error {
 	_, err := r.tx.Exec(`
 		Update s_carriers SET
 		name = ?,
+		adapter = ?,
+		config = ?,
 		status = ?,
 		updated = ?,
 		updated_by = ?
 		WHERE carrier_id = ?
-	`, info.Name, info.Status, info.Updated, info.UpdatedBy, id)
+	`, info.Name, info.Adapter, info.Config, info.Status, info.Updated, info.UpdatedBy, id)
 	return err
 }
 
@@ -727,3 +941,455 @@ func (r *settingRepository) GetPaymentPaymentMethodCount(paymentMethodID, organi
 	err := row.Scan(&count)
 	return count, err
 }
+
+// PaymentTerm
+
+func (r *settingRepository) GetPaymentTermByID(organizationID, paymentTermID string) (*PaymentTermResponse, error) {
+	var res PaymentTermResponse
+	row := r.tx.QueryRow(`SELECT payment_term_id, organization_id, name, term_type, net_days, discount_percent, discount_days, status FROM s_payment_terms WHERE organization_id = ? AND payment_term_id = ? AND status > 0 LIMIT 1`, organizationID, paymentTermID)
+	err := row.Scan(&res.PaymentTermID, &res.OrganizationID, &res.Name, &res.TermType, &res.NetDays, &res.DiscountPercent, &res.DiscountDays, &res.Status)
+	return &res, err
+}
+
+func (r *settingRepository) CheckPaymentTermConfict(paymentTermID, organizationID, name string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM s_payment_terms WHERE organization_id = ? AND payment_term_id != ? AND name = ? AND status > 0", organizationID, paymentTermID, name)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r *settingRepository) CreatePaymentTerm(info PaymentTerm) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_payment_terms
+		(
+			payment_term_id,
+			organization_id,
+			name,
+			term_type,
+			net_days,
+			discount_percent,
+			discount_days,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.PaymentTermID, info.OrganizationID, info.Name, info.TermType, info.NetDays, info.DiscountPercent, info.DiscountDays, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) UpdatePaymentTerm(id string, info PaymentTerm) error {
+	_, err := r.tx.Exec(`
+		Update s_payment_terms SET
+		name = ?,
+		term_type = ?,
+		net_days = ?,
+		discount_percent = ?,
+		discount_days = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE payment_term_id = ?
+	`, info.Name, info.TermType, info.NetDays, info.DiscountPercent, info.DiscountDays, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *settingRepository) DeletePaymentTerm(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_payment_terms SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE payment_term_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *settingRepository) GetPaymentTermUsedCount(paymentTermID, organizationID string) (int, error) {
+	var count int
+	row := r.tx.QueryRow(`
+		SELECT
+		(SELECT count(1) FROM s_customers WHERE organization_id = ? AND payment_term_id = ? AND status > 0) +
+		(SELECT count(1) FROM s_vendors WHERE organization_id = ? AND payment_term_id = ? AND status > 0)
+	`, organizationID, paymentTermID, organizationID, paymentTermID)
+	err := row.Scan(&count)
+	return count, err
+}
+
+//DocumentTemplate
+
+func (r *settingRepository) GetDocumentTemplateID(organizationID string, documentType int) (string, error) {
+	var documentTemplateID string
+	row := r.tx.QueryRow(`SELECT document_template_id FROM s_document_templates WHERE organization_id = ? AND document_type = ? AND status > 0 LIMIT 1`, organizationID, documentType)
+	err := row.Scan(&documentTemplateID)
+	return documentTemplateID, err
+}
+
+func (r *settingRepository) CreateDocumentTemplate(info DocumentTemplate) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_document_templates
+		(
+			document_template_id,
+			organization_id,
+			document_type,
+			title,
+			company_info,
+			header_text,
+			footer_text,
+			accent_color,
+			paper_size,
+			show_logo,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.DocumentTemplateID, info.OrganizationID, info.DocumentType, info.Title, info.CompanyInfo, info.HeaderText, info.FooterText, info.AccentColor, info.PaperSize, info.ShowLogo, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) UpdateDocumentTemplate(id string, info DocumentTemplate) error {
+	_, err := r.tx.Exec(`
+		Update s_document_templates SET
+		title = ?,
+		company_info = ?,
+		header_text = ?,
+		footer_text = ?,
+		accent_color = ?,
+		paper_size = ?,
+		show_logo = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE document_template_id = ?
+	`, info.Title, info.CompanyInfo, info.HeaderText, info.FooterText, info.AccentColor, info.PaperSize, info.ShowLogo, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *settingRepository) DeleteDocumentTemplate(organizationID string, documentType int, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_document_templates SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND document_type = ? AND status > 0
+	`, time.Now(), byUser, organizationID, documentType)
+	return err
+}
+
+func (r *settingRepository) CreateDocumentLogo(info DocumentLogo) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_document_logos
+		(
+			document_logo_id,
+			organization_id,
+			file_name,
+			content_type,
+			data,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.DocumentLogoID, info.OrganizationID, info.FileName, info.ContentType, info.Data, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) DeleteDocumentLogo(organizationID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_document_logos SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND status > 0
+	`, time.Now(), byUser, organizationID)
+	return err
+}
+
+// Address
+
+func (r *settingRepository) GetAddressByID(organizationID, addressID string) (*AddressResponse, error) {
+	var res AddressResponse
+	row := r.tx.QueryRow(`
+	SELECT 
+	address_id,
+	organization_id,
+	owner_type,
+	owner_id,
+	name,
+	attention,
+	country,
+	state,
+	city,
+	address1,
+	address2,
+	zip,
+	phone,
+	fax,
+	is_default_billing,
+	is_default_shipping,
+	status
+	FROM s_addresses 
+	WHERE organization_id = ? AND address_id = ? AND status > 0 LIMIT 1`, organizationID, addressID)
+	err := row.Scan(&res.AddressID, &res.OrganizationID, &res.OwnerType, &res.OwnerID, &res.Name, &res.Attention, &res.Country, &res.State, &res.City, &res.Address1, &res.Address2, &res.Zip, &res.Phone, &res.Fax, &res.IsDefaultBilling, &res.IsDefaultShipping, &res.Status)
+	return &res, err
+}
+
+// GetDefaultAddressID returns the default billing (1) or shipping (2) address
+// of a customer or vendor, empty when there is none
+func (r *settingRepository) GetDefaultAddressID(organizationID, ownerType, ownerID string, addressType int) (string, error) {
+	column := "is_default_billing"
+	if addressType == 2 {
+		column = "is_default_shipping"
+	}
+	var addressID string
+	row := r.tx.QueryRow("SELECT address_id FROM s_addresses WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND "+column+" = 1 AND status = 1 LIMIT 1", organizationID, ownerType, ownerID)
+	err := row.Scan(&addressID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return addressID, err
+}
+
+func (r *settingRepository) CreateAddress(info Address) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_addresses
+		(
+			address_id,
+			organization_id,
+			owner_type,
+			owner_id,
+			name,
+			attention,
+			country,
+			state,
+			city,
+			address1,
+			address2,
+			zip,
+			phone,
+			fax,
+			is_default_billing,
+			is_default_shipping,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.AddressID, info.OrganizationID, info.OwnerType, info.OwnerID, info.Name, info.Attention, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.IsDefaultBilling, info.IsDefaultShipping, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) UpdateAddress(id string, info Address) error {
+	_, err := r.tx.Exec(`
+		Update s_addresses SET
+		name = ?,
+		attention = ?,
+		country = ?,
+		state = ?,
+		city = ?,
+		address1 = ?,
+		address2 = ?,
+		zip = ?,
+		phone = ?,
+		fax = ?,
+		is_default_billing = ?,
+		is_default_shipping = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE address_id = ?
+	`, info.Name, info.Attention, info.Country, info.State, info.City, info.Address1, info.Address2, info.Zip, info.Phone, info.Fax, info.IsDefaultBilling, info.IsDefaultShipping, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *settingRepository) DeleteAddress(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_addresses SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE address_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *settingRepository) ResetDefaultBillingAddress(organizationID, ownerType, ownerID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_addresses SET
+		is_default_billing = 2,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND is_default_billing = 1 AND status > 0
+	`, time.Now(), byUser, organizationID, ownerType, ownerID)
+	return err
+}
+
+func (r *settingRepository) ResetDefaultShippingAddress(organizationID, ownerType, ownerID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_addresses SET
+		is_default_shipping = 2,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND is_default_shipping = 1 AND status > 0
+	`, time.Now(), byUser, organizationID, ownerType, ownerID)
+	return err
+}
+
+// GetCustomerAddressSnapshot returns the billing (1) or shipping (2) address
+// copied onto a sales document. Without an address ID the customer's default
+// address is used, then the address kept on the customer itself.
+func (r *settingRepository) GetCustomerAddressSnapshot(organizationID, customerID, addressID string, addressType int) (*AddressSnapshot, error) {
+	customer, err := r.GetCustomerByID(customerID, organizationID)
+	if err != nil {
+		msg := "customer not exist"
+		return nil, errors.New(msg)
+	}
+	if addressID == "" {
+		addressID, err = r.GetDefaultAddressID(organizationID, "customer", customerID, addressType)
+		if err != nil {
+			msg := "get default address error"
+			return nil, errors.New(msg)
+		}
+	}
+	var snapshot AddressSnapshot
+	snapshot.Name = customer.Name
+	if addressID == "" {
+		snapshot.Attention = strings.TrimSpace(customer.ContactSalutation + " " + customer.ContactFirstName + " " + customer.ContactLastName)
+		snapshot.Country = customer.Country
+		snapshot.State = customer.State
+		snapshot.City = customer.City
+		snapshot.Address1 = customer.Address1
+		snapshot.Address2 = customer.Address2
+		snapshot.Zip = customer.Zip
+		snapshot.Phone = customer.Phone
+		return &snapshot, nil
+	}
+	address, err := r.GetAddressByID(organizationID, addressID)
+	if err != nil || address.OwnerType != "customer" || address.OwnerID != customerID {
+		msg := "address not exist"
+		return nil, errors.New(msg)
+	}
+	if address.Status != 1 {
+		msg := "address not active"
+		return nil, errors.New(msg)
+	}
+	snapshot.AddressID = address.AddressID
+	if address.Name != "" {
+		snapshot.Name = customer.Name + " - " + address.Name
+	}
+	snapshot.Attention = address.Attention
+	snapshot.Country = address.Country
+	snapshot.State = address.State
+	snapshot.City = address.City
+	snapshot.Address1 = address.Address1
+	snapshot.Address2 = address.Address2
+	snapshot.Zip = address.Zip
+	snapshot.Phone = address.Phone
+	return &snapshot, nil
+}
+
+// Contact
+
+func (r *settingRepository) GetContactByID(organizationID, contactID string) (*ContactResponse, error) {
+	var res ContactResponse
+	row := r.tx.QueryRow(`
+	SELECT 
+	contact_id,
+	organization_id,
+	owner_type,
+	owner_id,
+	salutation,
+	first_name,
+	last_name,
+	email,
+	phone,
+	mobile,
+	designation,
+	is_primary,
+	status
+	FROM s_contacts 
+	WHERE organization_id = ? AND contact_id = ? AND status > 0 LIMIT 1`, organizationID, contactID)
+	err := row.Scan(&res.ContactID, &res.OrganizationID, &res.OwnerType, &res.OwnerID, &res.Salutation, &res.FirstName, &res.LastName, &res.Email, &res.Phone, &res.Mobile, &res.Designation, &res.IsPrimary, &res.Status)
+	return &res, err
+}
+
+func (r *settingRepository) CreateContact(info Contact) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO s_contacts
+		(
+			contact_id,
+			organization_id,
+			owner_type,
+			owner_id,
+			salutation,
+			first_name,
+			last_name,
+			email,
+			phone,
+			mobile,
+			designation,
+			is_primary,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.ContactID, info.OrganizationID, info.OwnerType, info.OwnerID, info.Salutation, info.FirstName, info.LastName, info.Email, info.Phone, info.Mobile, info.Designation, info.IsPrimary, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r *settingRepository) UpdateContact(id string, info Contact) error {
+	_, err := r.tx.Exec(`
+		Update s_contacts SET
+		salutation = ?,
+		first_name = ?,
+		last_name = ?,
+		email = ?,
+		phone = ?,
+		mobile = ?,
+		designation = ?,
+		is_primary = ?,
+		status = ?,
+		updated = ?,
+		updated_by = ?
+		WHERE contact_id = ?
+	`, info.Salutation, info.FirstName, info.LastName, info.Email, info.Phone, info.Mobile, info.Designation, info.IsPrimary, info.Status, info.Updated, info.UpdatedBy, id)
+	return err
+}
+
+func (r *settingRepository) DeleteContact(id, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_contacts SET
+		status = -1,
+		updated = ?,
+		updated_by = ?
+		WHERE contact_id = ?
+	`, time.Now(), byUser, id)
+	return err
+}
+
+func (r *settingRepository) ResetPrimaryContact(organizationID, ownerType, ownerID, byUser string) error {
+	_, err := r.tx.Exec(`
+		Update s_contacts SET
+		is_primary = 2,
+		updated = ?,
+		updated_by = ?
+		WHERE organization_id = ? AND owner_type = ? AND owner_id = ? AND is_primary = 1 AND status > 0
+	`, time.Now(), byUser, organizationID, ownerType, ownerID)
+	return err
+}
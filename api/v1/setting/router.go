@@ -32,6 +32,7 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/taxes/:id", UpdateTax)
 	g.GET("/taxes/:id", GetTaxByID)
 	g.DELETE("/taxes/:id", DeleteTax)
+	g.POST("/taxes/calculate", CalculateTax)
 
 	g.POST("/customers", NewCustomer)
 	g.GET("/customers", GetCustomerList)
@@ -39,11 +40,28 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.GET("/customers/:id", GetCustomerByID)
 	g.DELETE("/customers/:id", DeleteCustomer)
 
+	g.POST("/customers/:id/addresses", NewCustomerAddress)
+	g.GET("/customers/:id/addresses", GetCustomerAddressList)
+	g.POST("/vendors/:id/addresses", NewVendorAddress)
+	g.GET("/vendors/:id/addresses", GetVendorAddressList)
+	g.PUT("/addresses/:id", UpdateAddress)
+	g.GET("/addresses/:id", GetAddressByID)
+	g.DELETE("/addresses/:id", DeleteAddress)
+
+	g.POST("/customers/:id/contacts", NewCustomerContact)
+	g.GET("/customers/:id/contacts", GetCustomerContactList)
+	g.POST("/vendors/:id/contacts", NewVendorContact)
+	g.GET("/vendors/:id/contacts", GetVendorContactList)
+	g.PUT("/contacts/:id", UpdateContact)
+	g.GET("/contacts/:id", GetContactByID)
+	g.DELETE("/contacts/:id", DeleteContact)
+
 	g.POST("/carriers", NewCarrier)
 	g.GET("/carriers", GetCarrierList)
 	g.PUT("/carriers/:id", UpdateCarrier)
 	g.GET("/carriers/:id", GetCarrierByID)
 	g.DELETE("/carriers/:id", DeleteCarrier)
+	g.GET("/carrieradapters", GetCarrierAdapterList)
 
 	g.POST("/adjustmentreasons", NewAdjustmentReason)
 	g.GET("/adjustmentreasons", GetAdjustmentReasonList)
@@ -56,4 +74,18 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.PUT("/paymentmethods/:id", UpdatePaymentMethod)
 	g.GET("/paymentmethods/:id", GetPaymentMethodByID)
 	g.DELETE("/paymentmethods/:id", DeletePaymentMethod)
+
+	g.POST("/paymentterms", NewPaymentTerm)
+	g.GET("/paymentterms", GetPaymentTermList)
+	g.PUT("/paymentterms/:id", UpdatePaymentTerm)
+	g.GET("/paymentterms/:id", GetPaymentTermByID)
+	g.DELETE("/paymentterms/:id", DeletePaymentTerm)
+
+	g.GET("/documenttemplates", GetDocumentTemplateList)
+	g.GET("/documenttemplates/:type", GetDocumentTemplate)
+	g.PUT("/documenttemplates/:type", UpdateDocumentTemplate)
+	g.DELETE("/documenttemplates/:type", DeleteDocumentTemplate)
+	g.POST("/documentlogo", NewDocumentLogo)
+	g.GET("/documentlogo", GetDocumentLogo)
+	g.DELETE("/documentlogo", DeleteDocumentLogo)
 }
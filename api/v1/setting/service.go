@@ -1,8 +1,13 @@
 package setting
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"go-api/core/carrier"
 	"go-api/core/database"
+	"go-api/core/pdf"
+	"math"
 	"time"
 
 	"github.com/rs/xid"
@@ -431,15 +436,27 @@ func (s *settingService) NewVendor(info VendorNew) (*VendorResponse, error) {
 		return nil, err
 	}
 	defer tx.Rollback()
+	vendorID, err := CreateVendor(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	repo := NewSettingRepository(tx)
+	res, err := repo.GetVendorByID(vendorID, info.OrganizationID)
+	tx.Commit()
+	return res, err
+}
+
+// CreateVendor saves a vendor within tx, for NewVendor and the vendor import
+func CreateVendor(tx *sql.Tx, info VendorNew) (string, error) {
 	repo := NewSettingRepository(tx)
 	isConflict, err := repo.CheckVendorConfict("", info.OrganizationID, info.Name)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "Vendor name conflict"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	var vendor Vendor
 	vendor.VendorID = "ven-" + xid.New().String()
@@ -458,6 +475,15 @@ func (s *settingService) NewVendor(info VendorNew) (*VendorResponse, error) {
 	vendor.Zip = info.Zip
 	vendor.Phone = info.Phone
 	vendor.Fax = info.Fax
+	if info.PaymentTermID != "" {
+		_, err = repo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "Payment term not exist"
+			return "", errors.New(msg)
+		}
+	}
+	vendor.PaymentTermID = info.PaymentTermID
+	vendor.LeadTime = info.LeadTime
 	vendor.Status = info.Status
 	vendor.Created = time.Now()
 	vendor.CreatedBy = info.User
@@ -465,11 +491,10 @@ func (s *settingService) NewVendor(info VendorNew) (*VendorResponse, error) {
 	vendor.UpdatedBy = info.User
 	err = repo.CreateVendor(vendor)
 	if err != nil {
-		return nil, err
+		msg := "create vendor error: " + err.Error()
+		return "", errors.New(msg)
 	}
-	res, err := repo.GetVendorByID(vendor.VendorID, info.OrganizationID)
-	tx.Commit()
-	return res, err
+	return vendor.VendorID, nil
 }
 
 func (s *settingService) GetVendorList(filter VendorFilter) (int, *[]VendorResponse, error) {
@@ -523,6 +548,15 @@ func (s *settingService) UpdateVendor(vendorID string, info VendorNew) (*VendorR
 	vendor.Zip = info.Zip
 	vendor.Phone = info.Phone
 	vendor.Fax = info.Fax
+	if info.PaymentTermID != "" {
+		_, err = repo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "Payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	vendor.PaymentTermID = info.PaymentTermID
+	vendor.LeadTime = info.LeadTime
 	vendor.UpdatedBy = info.User
 	vendor.Updated = time.Now()
 	vendor.Status = info.Status
@@ -576,6 +610,14 @@ func (s *settingService) GetTaxByID(organizationID, id string) (*TaxResponse, er
 		msg := "get tax error: " + err.Error()
 		return nil, errors.New(msg)
 	}
+	if tax.TaxType == 2 {
+		components, err := query.GetTaxComponents(organizationID, id)
+		if err != nil {
+			msg := "get tax components error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		tax.Components = *components
+	}
 	return tax, nil
 }
 
@@ -601,11 +643,17 @@ func (s *settingService) NewTax(info TaxNew) (*TaxResponse, error) {
 	tax.OrganizationID = info.OrganizationID
 	tax.Name = info.Name
 	tax.TaxValue = info.TaxValue
+	tax.TaxType = info.TaxType
+	tax.IsCompound = info.IsCompound
 	tax.Status = info.Status
 	tax.Created = time.Now()
 	tax.CreatedBy = info.User
 	tax.Updated = time.Now()
 	tax.UpdatedBy = info.User
+	err = s.setTaxComponents(repo, &tax, info)
+	if err != nil {
+		return nil, err
+	}
 	err = repo.CreateTax(tax)
 	if err != nil {
 		return nil, err
@@ -615,6 +663,70 @@ func (s *settingService) NewTax(info TaxNew) (*TaxResponse, error) {
 	return res, err
 }
 
+// setTaxComponents validates the tax type, writes the group components and
+// works out the combined tax value of a tax group
+func (s *settingService) setTaxComponents(repo *settingRepository, tax *Tax, info TaxNew) error {
+	if tax.TaxType == 0 {
+		tax.TaxType = 1
+	}
+	if tax.IsCompound == 0 {
+		tax.IsCompound = 2
+	}
+	if tax.TaxType == 1 {
+		if info.TaxValue == 0 {
+			msg := "tax value required"
+			return errors.New(msg)
+		}
+		return nil
+	}
+	if len(info.Components) == 0 {
+		msg := "tax group components required"
+		return errors.New(msg)
+	}
+	tax.IsCompound = 2
+	var components []TaxComponentResponse
+	for idx, componentID := range info.Components {
+		if componentID == tax.TaxID {
+			msg := "tax group can not contain itself"
+			return errors.New(msg)
+		}
+		for _, component := range components {
+			if component.TaxID == componentID {
+				msg := "tax group component duplicated"
+				return errors.New(msg)
+			}
+		}
+		component, err := repo.GetTaxByID(componentID, info.OrganizationID)
+		if err != nil {
+			msg := "tax group component not exist"
+			return errors.New(msg)
+		}
+		if component.TaxType != 1 {
+			msg := "tax group component must be a single tax"
+			return errors.New(msg)
+		}
+		var groupItem TaxGroupItem
+		groupItem.TaxGroupItemID = "tgi-" + xid.New().String()
+		groupItem.OrganizationID = info.OrganizationID
+		groupItem.TaxGroupID = tax.TaxID
+		groupItem.TaxID = componentID
+		groupItem.Sequence = idx + 1
+		groupItem.Status = 1
+		groupItem.Created = time.Now()
+		groupItem.CreatedBy = info.User
+		groupItem.Updated = time.Now()
+		groupItem.UpdatedBy = info.User
+		err = repo.CreateTaxGroupItem(groupItem)
+		if err != nil {
+			msg := "create tax group item error"
+			return errors.New(msg)
+		}
+		components = append(components, TaxComponentResponse{TaxID: component.TaxID, Name: component.Name, TaxValue: component.TaxValue, IsCompound: component.IsCompound, Sequence: idx + 1})
+	}
+	tax.TaxValue = CalculateTaxAmount(&components, 100, false).TaxAmount
+	return nil
+}
+
 func (s *settingService) GetTaxList(filter TaxFilter) (int, *[]TaxResponse, error) {
 	db := database.RDB()
 	query := NewSettingQuery(db)
@@ -651,17 +763,50 @@ func (s *settingService) UpdateTax(taxID string, info TaxNew) (*TaxResponse, err
 		msg := "Tax not exist"
 		return nil, errors.New(msg)
 	}
+	groups, err := repo.GetTaxGroupIDsByComponent(taxID, info.OrganizationID)
+	if err != nil {
+		msg := "get tax group error"
+		return nil, errors.New(msg)
+	}
+	if len(groups) > 0 && info.TaxType == 2 {
+		msg := "Tax used in tax group can not be a tax group"
+		return nil, errors.New(msg)
+	}
+	err = repo.DeleteTaxGroupItem(taxID, info.User)
+	if err != nil {
+		msg := "delete tax group item error"
+		return nil, errors.New(msg)
+	}
 	var tax Tax
+	tax.TaxID = taxID
 	tax.Name = info.Name
 	tax.TaxValue = info.TaxValue
+	tax.TaxType = info.TaxType
+	tax.IsCompound = info.IsCompound
 	tax.UpdatedBy = info.User
 	tax.Updated = time.Now()
 	tax.Status = info.Status
+	err = s.setTaxComponents(repo, &tax, info)
+	if err != nil {
+		return nil, err
+	}
 	err = repo.UpdateTax(taxID, tax)
 	if err != nil {
 		msg := "update tax error"
 		return nil, errors.New(msg)
 	}
+	for _, groupID := range groups {
+		components, err := repo.GetTaxComponents(groupID, info.OrganizationID)
+		if err != nil {
+			msg := "get tax group components error"
+			return nil, errors.New(msg)
+		}
+		err = repo.UpdateTaxValue(groupID, CalculateTaxAmount(components, 100, false).TaxAmount, info.User)
+		if err != nil {
+			msg := "update tax group error"
+			return nil, errors.New(msg)
+		}
+	}
 	res, err := repo.GetTaxByID(taxID, info.OrganizationID)
 	tx.Commit()
 	return res, err
@@ -675,7 +820,7 @@ func (s *settingService) DeleteTax(taxID, organizationID, user string) error {
 	}
 	defer tx.Rollback()
 	repo := NewSettingRepository(tx)
-	_, err = repo.GetTaxByID(taxID, organizationID)
+	oldTax, err := repo.GetTaxByID(taxID, organizationID)
 	if err != nil {
 		msg := "Tax not exist"
 		return errors.New(msg)
@@ -698,6 +843,21 @@ func (s *settingService) DeleteTax(taxID, organizationID, user string) error {
 		msg := "Tax used in Sales order can not be deleted"
 		return errors.New(msg)
 	}
+	usedCount, err = repo.GetTaxGroupCount(taxID, organizationID)
+	if err != nil {
+		msg := "get tax group count error"
+		return errors.New(msg)
+	}
+	if usedCount > 0 {
+		msg := "Tax used in tax group can not be deleted"
+		return errors.New(msg)
+	}
+	if oldTax.TaxType == 2 {
+		err = repo.DeleteTaxGroupItem(taxID, user)
+		if err != nil {
+			return err
+		}
+	}
 	err = repo.DeleteTax(taxID, user)
 	if err != nil {
 		return err
@@ -706,6 +866,62 @@ func (s *settingService) DeleteTax(taxID, organizationID, user string) error {
 	return nil
 }
 
+// CalculateTaxAmount applies the tax components in sequence. Compound
+// components are charged on the amount plus the taxes before them. A tax
+// inclusive amount has the tax backed out first.
+func CalculateTaxAmount(components *[]TaxComponentResponse, amount float64, inclusive bool) *TaxCalculationResponse {
+	var res TaxCalculationResponse
+	rate := 0.0
+	for _, component := range *components {
+		if component.IsCompound == 1 {
+			rate += (100 + rate) * component.TaxValue / 100
+		} else {
+			rate += component.TaxValue
+		}
+	}
+	taxableAmount := amount
+	if inclusive {
+		taxableAmount = amount / (1 + rate/100)
+	}
+	taxAmount := 0.0
+	for _, component := range *components {
+		base := taxableAmount
+		if component.IsCompound == 1 {
+			base += taxAmount
+		}
+		var componentAmount TaxComponentAmountResponse
+		componentAmount.TaxID = component.TaxID
+		componentAmount.Name = component.Name
+		componentAmount.TaxValue = component.TaxValue
+		componentAmount.IsCompound = component.IsCompound
+		componentAmount.TaxAmount = base * component.TaxValue / 100
+		taxAmount += componentAmount.TaxAmount
+		res.Components = append(res.Components, componentAmount)
+	}
+	res.TaxValue = rate
+	res.TaxableAmount = taxableAmount
+	res.TaxAmount = taxAmount
+	return &res
+}
+
+func (s *settingService) CalculateTax(info TaxCalculationNew) (*TaxCalculationResponse, error) {
+	if info.TaxID == "" {
+		var res TaxCalculationResponse
+		res.TaxableAmount = info.Amount
+		return &res, nil
+	}
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	components, err := query.GetTaxComponents(info.OrganizationID, info.TaxID)
+	if err != nil {
+		msg := "tax not exist"
+		return nil, errors.New(msg)
+	}
+	res := CalculateTaxAmount(components, info.Amount, info.TaxInclusive == 1)
+	res.TaxID = info.TaxID
+	return res, nil
+}
+
 //customer
 
 func (s *settingService) GetCustomerByID(organizationID, id string) (*CustomerResponse, error) {
@@ -726,15 +942,28 @@ func (s *settingService) NewCustomer(info CustomerNew) (*CustomerResponse, error
 		return nil, err
 	}
 	defer tx.Rollback()
+	customerID, err := CreateCustomer(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	repo := NewSettingRepository(tx)
+	res, err := repo.GetCustomerByID(customerID, info.OrganizationID)
+	tx.Commit()
+	return res, err
+}
+
+// CreateCustomer saves a customer within tx after checking its price list and
+// payment term
+func CreateCustomer(tx *sql.Tx, info CustomerNew) (string, error) {
 	repo := NewSettingRepository(tx)
 	isConflict, err := repo.CheckCustomerConfict("", info.OrganizationID, info.Name)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "Customer name conflict"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	var customer Customer
 	customer.CustomerID = "cus-" + xid.New().String()
@@ -753,6 +982,35 @@ func (s *settingService) NewCustomer(info CustomerNew) (*CustomerResponse, error
 	customer.Zip = info.Zip
 	customer.Phone = info.Phone
 	customer.Fax = info.Fax
+	customer.TaxExempt = info.TaxExempt
+	if customer.TaxExempt == 0 {
+		customer.TaxExempt = 2
+	}
+	if info.PriceListID != "" {
+		priceListExist, err := repo.CheckPriceListExist(info.PriceListID, info.OrganizationID)
+		if err != nil {
+			msg := "check price list error: " + err.Error()
+			return "", errors.New(msg)
+		}
+		if !priceListExist {
+			msg := "Price list not exist"
+			return "", errors.New(msg)
+		}
+	}
+	customer.PriceListID = info.PriceListID
+	if info.PaymentTermID != "" {
+		_, err = repo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "Payment term not exist"
+			return "", errors.New(msg)
+		}
+	}
+	customer.PaymentTermID = info.PaymentTermID
+	customer.CreditLimit = info.CreditLimit
+	customer.CreditHold = info.CreditHold
+	if customer.CreditHold == 0 {
+		customer.CreditHold = 2
+	}
 	customer.Status = info.Status
 	customer.Created = time.Now()
 	customer.CreatedBy = info.User
@@ -760,11 +1018,10 @@ func (s *settingService) NewCustomer(info CustomerNew) (*CustomerResponse, error
 	customer.UpdatedBy = info.User
 	err = repo.CreateCustomer(customer)
 	if err != nil {
-		return nil, err
+		msg := "create customer error: " + err.Error()
+		return "", errors.New(msg)
 	}
-	res, err := repo.GetCustomerByID(customer.CustomerID, info.OrganizationID)
-	tx.Commit()
-	return res, err
+	return customer.CustomerID, nil
 }
 
 func (s *settingService) GetCustomerList(filter CustomerFilter) (int, *[]CustomerResponse, error) {
@@ -822,6 +1079,35 @@ func (s *settingService) UpdateCustomer(customerID string, info CustomerNew) (*C
 	customer.Zip = info.Zip
 	customer.Phone = info.Phone
 	customer.Fax = info.Fax
+	customer.TaxExempt = info.TaxExempt
+	if customer.TaxExempt == 0 {
+		customer.TaxExempt = 2
+	}
+	if info.PriceListID != "" {
+		priceListExist, err := repo.CheckPriceListExist(info.PriceListID, info.OrganizationID)
+		if err != nil {
+			msg := "check price list error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		if !priceListExist {
+			msg := "Price list not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	customer.PriceListID = info.PriceListID
+	if info.PaymentTermID != "" {
+		_, err = repo.GetPaymentTermByID(info.OrganizationID, info.PaymentTermID)
+		if err != nil {
+			msg := "Payment term not exist"
+			return nil, errors.New(msg)
+		}
+	}
+	customer.PaymentTermID = info.PaymentTermID
+	customer.CreditLimit = info.CreditLimit
+	customer.CreditHold = info.CreditHold
+	if customer.CreditHold == 0 {
+		customer.CreditHold = 2
+	}
 	customer.UpdatedBy = info.User
 	customer.Updated = time.Now()
 	customer.Status = info.Status
@@ -895,10 +1181,16 @@ func (s *settingService) NewCarrier(info CarrierNew) (*CarrierResponse, error) {
 		msg := "Carrier name conflict"
 		return nil, errors.New(msg)
 	}
+	config, err := checkCarrierAdapter(info)
+	if err != nil {
+		return nil, err
+	}
 	var carrier Carrier
 	carrier.CarrierID = "car-" + xid.New().String()
 	carrier.OrganizationID = info.OrganizationID
 	carrier.Name = info.Name
+	carrier.Adapter = info.Adapter
+	carrier.Config = config
 	carrier.Status = info.Status
 	carrier.Created = time.Now()
 	carrier.CreatedBy = info.User
@@ -944,13 +1236,27 @@ func (s *settingService) UpdateCarrier(carrierID string, info CarrierNew) (*Carr
 		msg := "carrier name conflict"
 		return nil, errors.New(msg)
 	}
-	_, err = repo.GetCarrierByID(info.OrganizationID, carrierID)
+	oldCarrier, err := repo.GetCarrierByID(info.OrganizationID, carrierID)
 	if err != nil {
 		msg := "Carrier not exist"
 		return nil, errors.New(msg)
 	}
+	// the account config is write only, keep it when it is not sent again
+	if info.Config == nil && info.Adapter == oldCarrier.Adapter && oldCarrier.Config != "" {
+		err = json.Unmarshal([]byte(oldCarrier.Config), &info.Config)
+		if err != nil {
+			msg := "carrier config error"
+			return nil, errors.New(msg)
+		}
+	}
+	config, err := checkCarrierAdapter(info)
+	if err != nil {
+		return nil, err
+	}
 	var carrier Carrier
 	carrier.Name = info.Name
+	carrier.Adapter = info.Adapter
+	carrier.Config = config
 	carrier.UpdatedBy = info.User
 	carrier.Updated = time.Now()
 	carrier.Status = info.Status
@@ -964,6 +1270,50 @@ func (s *settingService) UpdateCarrier(carrierID string, info CarrierNew) (*Carr
 	return res, err
 }
 
+// checkCarrierAdapter makes sure the adapter can be built from the config
+// and returns the config to store.
+func checkCarrierAdapter(info CarrierNew) (string, error) {
+	if info.Adapter == "" {
+		return "", nil
+	}
+	_, err := carrier.New(info.Adapter, info.Config)
+	if err != nil {
+		msg := "carrier adapter error: " + err.Error()
+		return "", errors.New(msg)
+	}
+	config, err := json.Marshal(info.Config)
+	if err != nil {
+		return "", err
+	}
+	return string(config), nil
+}
+
+// NewCarrierAdapter returns the adapter of a carrier and its account config,
+// carriers without an adapter only have their tracking numbers typed in.
+func NewCarrierAdapter(info *CarrierResponse) (carrier.Adapter, map[string]string, error) {
+	if info.Adapter == "" {
+		msg := "carrier " + info.Name + " has no adapter"
+		return nil, nil, errors.New(msg)
+	}
+	config := make(map[string]string)
+	if info.Config != "" {
+		err := json.Unmarshal([]byte(info.Config), &config)
+		if err != nil {
+			msg := "carrier config error"
+			return nil, nil, errors.New(msg)
+		}
+	}
+	adapter, err := carrier.New(info.Adapter, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return adapter, config, nil
+}
+
+func (s *settingService) GetCarrierAdapterList() []string {
+	return carrier.Names()
+}
+
 func (s *settingService) DeleteCarrier(carrierID, organizationID, user string) error {
 	db := database.WDB()
 	tx, err := db.Begin()
@@ -1251,3 +1601,751 @@ func (s *settingService) DeletePaymentMethod(paymentMethodID, organizationID, us
 	tx.Commit()
 	return nil
 }
+
+//paymentTerm
+
+func (s *settingService) GetPaymentTermByID(organizationID, id string) (*PaymentTermResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	paymentTerm, err := query.GetPaymentTermByID(organizationID, id)
+	if err != nil {
+		msg := "get paymentTerm error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return paymentTerm, nil
+}
+
+func checkPaymentTerm(info PaymentTermNew) error {
+	if info.TermType == 3 && info.NetDays != 0 {
+		msg := "due on receipt term can not have net days"
+		return errors.New(msg)
+	}
+	if info.DiscountPercent > 0 && info.DiscountDays == 0 {
+		msg := "discount days required for early payment discount"
+		return errors.New(msg)
+	}
+	if info.TermType == 1 && info.DiscountDays > info.NetDays {
+		msg := "discount days can not be greater than net days"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *settingService) NewPaymentTerm(info PaymentTermNew) (*PaymentTermResponse, error) {
+	err := checkPaymentTerm(info)
+	if err != nil {
+		return nil, err
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	isConflict, err := repo.CheckPaymentTermConfict("", info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "PaymentTerm name conflict"
+		return nil, errors.New(msg)
+	}
+	var paymentTerm PaymentTerm
+	paymentTerm.PaymentTermID = "pt-" + xid.New().String()
+	paymentTerm.OrganizationID = info.OrganizationID
+	paymentTerm.Name = info.Name
+	paymentTerm.TermType = info.TermType
+	paymentTerm.NetDays = info.NetDays
+	paymentTerm.DiscountPercent = info.DiscountPercent
+	paymentTerm.DiscountDays = info.DiscountDays
+	paymentTerm.Status = info.Status
+	paymentTerm.Created = time.Now()
+	paymentTerm.CreatedBy = info.User
+	paymentTerm.Updated = time.Now()
+	paymentTerm.UpdatedBy = info.User
+	err = repo.CreatePaymentTerm(paymentTerm)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetPaymentTermByID(info.OrganizationID, paymentTerm.PaymentTermID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) GetPaymentTermList(filter PaymentTermFilter) (int, *[]PaymentTermResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	count, err := query.GetPaymentTermCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetPaymentTermList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *settingService) UpdatePaymentTerm(paymentTermID string, info PaymentTermNew) (*PaymentTermResponse, error) {
+	err := checkPaymentTerm(info)
+	if err != nil {
+		return nil, err
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	isConflict, err := repo.CheckPaymentTermConfict(paymentTermID, info.OrganizationID, info.Name)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "paymentTerm name conflict"
+		return nil, errors.New(msg)
+	}
+	_, err = repo.GetPaymentTermByID(info.OrganizationID, paymentTermID)
+	if err != nil {
+		msg := "PaymentTerm not exist"
+		return nil, errors.New(msg)
+	}
+	var paymentTerm PaymentTerm
+	paymentTerm.Name = info.Name
+	paymentTerm.TermType = info.TermType
+	paymentTerm.NetDays = info.NetDays
+	paymentTerm.DiscountPercent = info.DiscountPercent
+	paymentTerm.DiscountDays = info.DiscountDays
+	paymentTerm.UpdatedBy = info.User
+	paymentTerm.Updated = time.Now()
+	paymentTerm.Status = info.Status
+	err = repo.UpdatePaymentTerm(paymentTermID, paymentTerm)
+	if err != nil {
+		msg := "update paymentTerm error"
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetPaymentTermByID(info.OrganizationID, paymentTermID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) DeletePaymentTerm(paymentTermID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	_, err = repo.GetPaymentTermByID(organizationID, paymentTermID)
+	if err != nil {
+		msg := "PaymentTerm not exist"
+		return errors.New(msg)
+	}
+	usedCount, err := repo.GetPaymentTermUsedCount(paymentTermID, organizationID)
+	if err != nil {
+		msg := "get payment term count error"
+		return errors.New(msg)
+	}
+	if usedCount > 0 {
+		msg := "PaymentTerm used by customer or vendor can not be deleted"
+		return errors.New(msg)
+	}
+	err = repo.DeletePaymentTerm(paymentTermID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// CalculatePaymentTermDates returns the due date and the last day an early
+// payment discount can be taken for a document dated documentDate. The
+// discount date is empty when the term has no discount.
+func CalculatePaymentTermDates(term *PaymentTermResponse, documentDate string) (string, string, error) {
+	date, err := time.Parse("2006-01-02", documentDate)
+	if err != nil {
+		return "", "", err
+	}
+	var dueDate time.Time
+	switch term.TermType {
+	case 1:
+		dueDate = date.AddDate(0, 0, term.NetDays)
+	case 2:
+		endOfMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location())
+		dueDate = endOfMonth.AddDate(0, 0, term.NetDays)
+	default:
+		dueDate = date
+	}
+	discountDate := ""
+	if term.DiscountPercent > 0 && term.DiscountDays > 0 {
+		discountDate = date.AddDate(0, 0, term.DiscountDays).Format("2006-01-02")
+	}
+	return dueDate.Format("2006-01-02"), discountDate, nil
+}
+
+// GetPaymentTermDates resolves the due date and early payment discount of a
+// document. Without a payment term the document is due on its own date.
+func (s *settingService) GetPaymentTermDates(organizationID, paymentTermID, documentDate string) (*PaymentTermDatesResponse, error) {
+	var res PaymentTermDatesResponse
+	res.PaymentTermID = paymentTermID
+	res.DueDate = documentDate
+	if paymentTermID == "" {
+		return &res, nil
+	}
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	term, err := query.GetPaymentTermByID(organizationID, paymentTermID)
+	if err != nil {
+		msg := "payment term not exist"
+		return nil, errors.New(msg)
+	}
+	dueDate, discountDate, err := CalculatePaymentTermDates(term, documentDate)
+	if err != nil {
+		msg := "calculate payment term error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	res.DueDate = dueDate
+	res.DiscountDate = discountDate
+	if discountDate != "" {
+		res.DiscountPercent = term.DiscountPercent
+	}
+	return &res, nil
+}
+
+// CalculateEarlyPaymentDiscount returns the discount a payment made on
+// paymentDate earns, or 0 when it is made after the discount date.
+func CalculateEarlyPaymentDiscount(total, discountPercent float64, discountDate, paymentDate string) float64 {
+	if discountPercent <= 0 || discountDate == "" || paymentDate > discountDate {
+		return 0
+	}
+	return math.Round(total*discountPercent) / 100
+}
+
+//DocumentTemplate
+
+var documentTemplateTitles = map[int]string{
+	1: "Invoice",
+	2: "Purchase Order",
+	3: "Packing Slip",
+	4: "Pick List",
+	5: "Bill of Lading",
+}
+
+func defaultDocumentTemplate(organizationID string, documentType int) DocumentTemplateResponse {
+	var res DocumentTemplateResponse
+	res.OrganizationID = organizationID
+	res.DocumentType = documentType
+	res.Title = documentTemplateTitles[documentType]
+	res.PaperSize = 1
+	res.ShowLogo = 1
+	res.IsDefault = 1
+	return res
+}
+
+// GetDocumentTemplateList returns the template of every document type, types
+// without an override use the built in default.
+func (s *settingService) GetDocumentTemplateList(organizationID string) (*[]DocumentTemplateResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	stored, err := query.GetDocumentTemplateList(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	var res []DocumentTemplateResponse
+	for documentType := 1; documentType <= len(documentTemplateTitles); documentType++ {
+		template := defaultDocumentTemplate(organizationID, documentType)
+		for _, v := range *stored {
+			if v.DocumentType == documentType {
+				template = v
+			}
+		}
+		res = append(res, template)
+	}
+	return &res, nil
+}
+
+func (s *settingService) GetDocumentTemplate(organizationID string, documentType int) (*DocumentTemplateResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	template, err := query.GetDocumentTemplate(organizationID, documentType)
+	if err == sql.ErrNoRows {
+		res := defaultDocumentTemplate(organizationID, documentType)
+		return &res, nil
+	}
+	return template, err
+}
+
+func (s *settingService) UpdateDocumentTemplate(documentType int, info DocumentTemplateNew) (*DocumentTemplateResponse, error) {
+	if info.Title == "" {
+		info.Title = documentTemplateTitles[documentType]
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	var documentTemplate DocumentTemplate
+	documentTemplate.Title = info.Title
+	documentTemplate.CompanyInfo = info.CompanyInfo
+	documentTemplate.HeaderText = info.HeaderText
+	documentTemplate.FooterText = info.FooterText
+	documentTemplate.AccentColor = info.AccentColor
+	documentTemplate.PaperSize = info.PaperSize
+	documentTemplate.ShowLogo = info.ShowLogo
+	documentTemplate.Updated = time.Now()
+	documentTemplate.UpdatedBy = info.Email
+	documentTemplateID, err := repo.GetDocumentTemplateID(info.OrganizationID, documentType)
+	if err == sql.ErrNoRows {
+		documentTemplate.DocumentTemplateID = "dtpl-" + xid.New().String()
+		documentTemplate.OrganizationID = info.OrganizationID
+		documentTemplate.DocumentType = documentType
+		documentTemplate.Status = 1
+		documentTemplate.Created = time.Now()
+		documentTemplate.CreatedBy = info.Email
+		err = repo.CreateDocumentTemplate(documentTemplate)
+	} else if err == nil {
+		err = repo.UpdateDocumentTemplate(documentTemplateID, documentTemplate)
+	}
+	if err != nil {
+		msg := "update document template error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return s.GetDocumentTemplate(info.OrganizationID, documentType)
+}
+
+// DeleteDocumentTemplate removes the override so the default is used again
+func (s *settingService) DeleteDocumentTemplate(organizationID string, documentType int, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	err = repo.DeleteDocumentTemplate(organizationID, documentType, email)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *settingService) NewDocumentLogo(organizationID, fileName, contentType string, data []byte, email string) (*DocumentLogoResponse, error) {
+	_, _, err := pdf.ImageSize(data)
+	if err != nil {
+		msg := "logo must be a png or jpeg image"
+		return nil, errors.New(msg)
+	}
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	err = repo.DeleteDocumentLogo(organizationID, email)
+	if err != nil {
+		return nil, err
+	}
+	var documentLogo DocumentLogo
+	documentLogo.DocumentLogoID = "dlogo-" + xid.New().String()
+	documentLogo.OrganizationID = organizationID
+	documentLogo.FileName = fileName
+	documentLogo.ContentType = contentType
+	documentLogo.Data = data
+	documentLogo.Status = 1
+	documentLogo.Created = time.Now()
+	documentLogo.CreatedBy = email
+	documentLogo.Updated = time.Now()
+	documentLogo.UpdatedBy = email
+	err = repo.CreateDocumentLogo(documentLogo)
+	if err != nil {
+		msg := "create document logo error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	var res DocumentLogoResponse
+	res.OrganizationID = organizationID
+	res.FileName = fileName
+	res.ContentType = contentType
+	return &res, nil
+}
+
+func (s *settingService) GetDocumentLogo(organizationID string) (*DocumentLogoResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	logo, err := query.GetDocumentLogo(organizationID)
+	if err != nil {
+		msg := "document logo not exist"
+		return nil, errors.New(msg)
+	}
+	return logo, nil
+}
+
+func (s *settingService) DeleteDocumentLogo(organizationID, email string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	err = repo.DeleteDocumentLogo(organizationID, email)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// NewDocumentLayout returns a pdf layout with the organization template of
+// the document type applied, the caller fills in the document content.
+func (s *settingService) NewDocumentLayout(organizationID string, documentType int) (*pdf.Layout, error) {
+	template, err := s.GetDocumentTemplate(organizationID, documentType)
+	if err != nil {
+		msg := "get document template error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	var layout pdf.Layout
+	layout.PageSize = pdf.A4
+	if template.PaperSize == 2 {
+		layout.PageSize = pdf.Letter
+	}
+	layout.Title = template.Title
+	layout.CompanyInfo = template.CompanyInfo
+	layout.HeaderText = template.HeaderText
+	layout.FooterText = template.FooterText
+	layout.AccentColor = template.AccentColor
+	if template.ShowLogo == 1 {
+		db := database.RDB()
+		query := NewSettingQuery(db)
+		logo, err := query.GetDocumentLogo(organizationID)
+		if err == nil {
+			layout.Logo = logo.Data
+		}
+	}
+	return &layout, nil
+}
+
+//address
+
+func checkAddressOwner(repo *settingRepository, organizationID, ownerType, ownerID string) error {
+	var err error
+	if ownerType == "vendor" {
+		_, err = repo.GetVendorByID(ownerID, organizationID)
+	} else {
+		_, err = repo.GetCustomerByID(ownerID, organizationID)
+	}
+	if err != nil {
+		msg := ownerType + " not exist"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// resetAddressDefaults clears the other default billing and shipping
+// addresses of the owner, there is at most one of each
+func resetAddressDefaults(repo *settingRepository, address Address) error {
+	if address.IsDefaultBilling == 1 {
+		err := repo.ResetDefaultBillingAddress(address.OrganizationID, address.OwnerType, address.OwnerID, address.UpdatedBy)
+		if err != nil {
+			msg := "reset default billing address error"
+			return errors.New(msg)
+		}
+	}
+	if address.IsDefaultShipping == 1 {
+		err := repo.ResetDefaultShippingAddress(address.OrganizationID, address.OwnerType, address.OwnerID, address.UpdatedBy)
+		if err != nil {
+			msg := "reset default shipping address error"
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+func (s *settingService) NewAddress(ownerType, ownerID string, info AddressNew) (*AddressResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	err = checkAddressOwner(repo, info.OrganizationID, ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	var address Address
+	address.AddressID = "addr-" + xid.New().String()
+	address.OrganizationID = info.OrganizationID
+	address.OwnerType = ownerType
+	address.OwnerID = ownerID
+	address.Name = info.Name
+	address.Attention = info.Attention
+	address.Country = info.Country
+	address.State = info.State
+	address.City = info.City
+	address.Address1 = info.Address1
+	address.Address2 = info.Address2
+	address.Zip = info.Zip
+	address.Phone = info.Phone
+	address.Fax = info.Fax
+	address.IsDefaultBilling = info.IsDefaultBilling
+	if address.IsDefaultBilling == 0 {
+		address.IsDefaultBilling = 2
+	}
+	address.IsDefaultShipping = info.IsDefaultShipping
+	if address.IsDefaultShipping == 0 {
+		address.IsDefaultShipping = 2
+	}
+	address.Status = info.Status
+	address.Created = time.Now()
+	address.CreatedBy = info.User
+	address.Updated = time.Now()
+	address.UpdatedBy = info.User
+	err = resetAddressDefaults(repo, address)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.CreateAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetAddressByID(info.OrganizationID, address.AddressID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) GetAddressList(organizationID, ownerType, ownerID string) (*[]AddressResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	list, err := query.GetAddressList(organizationID, ownerType, ownerID)
+	return list, err
+}
+
+func (s *settingService) GetAddressByID(organizationID, id string) (*AddressResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	address, err := query.GetAddressByID(organizationID, id)
+	if err != nil {
+		msg := "get address error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return address, nil
+}
+
+func (s *settingService) UpdateAddress(addressID string, info AddressNew) (*AddressResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	oldAddress, err := repo.GetAddressByID(info.OrganizationID, addressID)
+	if err != nil {
+		msg := "Address not exist"
+		return nil, errors.New(msg)
+	}
+	var address Address
+	address.OrganizationID = info.OrganizationID
+	address.OwnerType = oldAddress.OwnerType
+	address.OwnerID = oldAddress.OwnerID
+	address.Name = info.Name
+	address.Attention = info.Attention
+	address.Country = info.Country
+	address.State = info.State
+	address.City = info.City
+	address.Address1 = info.Address1
+	address.Address2 = info.Address2
+	address.Zip = info.Zip
+	address.Phone = info.Phone
+	address.Fax = info.Fax
+	address.IsDefaultBilling = info.IsDefaultBilling
+	if address.IsDefaultBilling == 0 {
+		address.IsDefaultBilling = 2
+	}
+	address.IsDefaultShipping = info.IsDefaultShipping
+	if address.IsDefaultShipping == 0 {
+		address.IsDefaultShipping = 2
+	}
+	address.Status = info.Status
+	address.Updated = time.Now()
+	address.UpdatedBy = info.User
+	err = resetAddressDefaults(repo, address)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.UpdateAddress(addressID, address)
+	if err != nil {
+		msg := "update address error"
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetAddressByID(info.OrganizationID, addressID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) DeleteAddress(addressID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	_, err = repo.GetAddressByID(organizationID, addressID)
+	if err != nil {
+		msg := "Address not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteAddress(addressID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+//contact
+
+func (s *settingService) NewContact(ownerType, ownerID string, info ContactNew) (*ContactResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	err = checkAddressOwner(repo, info.OrganizationID, ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	var contact Contact
+	contact.ContactID = "con-" + xid.New().String()
+	contact.OrganizationID = info.OrganizationID
+	contact.OwnerType = ownerType
+	contact.OwnerID = ownerID
+	contact.Salutation = info.Salutation
+	contact.FirstName = info.FirstName
+	contact.LastName = info.LastName
+	contact.Email = info.Email
+	contact.Phone = info.Phone
+	contact.Mobile = info.Mobile
+	contact.Designation = info.Designation
+	contact.IsPrimary = info.IsPrimary
+	if contact.IsPrimary == 0 {
+		contact.IsPrimary = 2
+	}
+	contact.Status = info.Status
+	contact.Created = time.Now()
+	contact.CreatedBy = info.User
+	contact.Updated = time.Now()
+	contact.UpdatedBy = info.User
+	if contact.IsPrimary == 1 {
+		err = repo.ResetPrimaryContact(info.OrganizationID, ownerType, ownerID, info.User)
+		if err != nil {
+			msg := "reset primary contact error"
+			return nil, errors.New(msg)
+		}
+	}
+	err = repo.CreateContact(contact)
+	if err != nil {
+		return nil, err
+	}
+	res, err := repo.GetContactByID(info.OrganizationID, contact.ContactID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) GetContactList(organizationID, ownerType, ownerID string) (*[]ContactResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	list, err := query.GetContactList(organizationID, ownerType, ownerID)
+	return list, err
+}
+
+func (s *settingService) GetContactByID(organizationID, id string) (*ContactResponse, error) {
+	db := database.RDB()
+	query := NewSettingQuery(db)
+	contact, err := query.GetContactByID(organizationID, id)
+	if err != nil {
+		msg := "get contact error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return contact, nil
+}
+
+func (s *settingService) UpdateContact(contactID string, info ContactNew) (*ContactResponse, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	oldContact, err := repo.GetContactByID(info.OrganizationID, contactID)
+	if err != nil {
+		msg := "Contact not exist"
+		return nil, errors.New(msg)
+	}
+	var contact Contact
+	contact.Salutation = info.Salutation
+	contact.FirstName = info.FirstName
+	contact.LastName = info.LastName
+	contact.Email = info.Email
+	contact.Phone = info.Phone
+	contact.Mobile = info.Mobile
+	contact.Designation = info.Designation
+	contact.IsPrimary = info.IsPrimary
+	if contact.IsPrimary == 0 {
+		contact.IsPrimary = 2
+	}
+	contact.Status = info.Status
+	contact.Updated = time.Now()
+	contact.UpdatedBy = info.User
+	if contact.IsPrimary == 1 {
+		err = repo.ResetPrimaryContact(info.OrganizationID, oldContact.OwnerType, oldContact.OwnerID, info.User)
+		if err != nil {
+			msg := "reset primary contact error"
+			return nil, errors.New(msg)
+		}
+	}
+	err = repo.UpdateContact(contactID, contact)
+	if err != nil {
+		msg := "update contact error"
+		return nil, errors.New(msg)
+	}
+	res, err := repo.GetContactByID(info.OrganizationID, contactID)
+	tx.Commit()
+	return res, err
+}
+
+func (s *settingService) DeleteContact(contactID, organizationID, user string) error {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	repo := NewSettingRepository(tx)
+	_, err = repo.GetContactByID(organizationID, contactID)
+	if err != nil {
+		msg := "Contact not exist"
+		return errors.New(msg)
+	}
+	err = repo.DeleteContact(contactID, user)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
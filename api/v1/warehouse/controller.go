@@ -163,6 +163,8 @@ func DeleteBay(c *gin.Context) {
 // @Param code query string false "货位编码"
 // @Param bay_id query string false "货架ID"
 // @Param is_alert query bool false "是否警告"
+// @Param abc_class query string false "商品ABC分类 A B C，用于盘点范围和储位规划"
+// @Param xyz_class query string false "商品XYZ分类 X Y Z"
 // @Param level query string false "第几层"
 // @Success 200 object response.ListRes{data=[]BayResponse} 成功
 // @Failure 400 object response.ErrorRes 内部错误
@@ -359,3 +361,89 @@ func GetAdjustmentList(c *gin.Context) {
 	}
 	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
 }
+
+// @Summary 新建组装单
+// @Id 513
+// @Tags 组装管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param info body AssemblyNew true "组装信息"
+// @Success 200 object response.SuccessRes{data=string} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /assemblies [POST]
+func NewAssembly(c *gin.Context) {
+	var info AssemblyNew
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	warehouseService := NewWarehouseService()
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	info.OrganizationID = claims.OrganizationID
+	info.Email = claims.Email
+	info.User = claims.UserName
+	new, err := warehouseService.NewAssembly(info)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, new)
+}
+
+// @Summary 组装单列表
+// @Id 514
+// @Tags 组装管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param page_id query int true "页码"
+// @Param page_size query int true "每页行数"
+// @Param item_id query string false "商品ID"
+// @Param type query int false "类型(1组装 2拆卸)"
+// @Success 200 object response.ListRes{data=[]AssemblyResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /assemblies [GET]
+func GetAssemblyList(c *gin.Context) {
+	var filter AssemblyFilter
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	filter.OrganizationID = claims.OrganizationID
+	warehouseService := NewWarehouseService()
+	count, list, err := warehouseService.GetAssemblyList(filter)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.ResponseList(c, filter.PageID, filter.PageSize, count, list)
+}
+
+// @Summary 组装单明细
+// @Id 515
+// @Tags 组装管理
+// @version 1.0
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "组装单ID"
+// @Success 200 object response.SuccessRes{data=[]AssemblyItemResponse} 成功
+// @Failure 400 object response.ErrorRes 内部错误
+// @Router /assemblies/:id/items [GET]
+func GetAssemblyItemList(c *gin.Context) {
+	var uri AssemblyID
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ResponseError(c, "BindingError", err)
+		return
+	}
+	claims := c.MustGet("claims").(*service.CustomClaims)
+	warehouseService := NewWarehouseService()
+	list, err := warehouseService.GetAssemblyItemList(claims.OrganizationID, uri.ID)
+	if err != nil {
+		response.ResponseError(c, "DatabaseError", err)
+		return
+	}
+	response.Response(c, list)
+}
@@ -37,6 +37,8 @@ type LocationFilter struct {
 	Level          string `form:"level" binding:"omitempty,min=1,max=64"`
 	SKU            string `form:"sku" binding:"omitempty,max=64,min=1"`
 	IsAlert        bool   `form:"is_alert" binding:"omitempty"`
+	AbcClass       string `form:"abc_class" binding:"omitempty,oneof=A B C"`
+	XyzClass       string `form:"xyz_class" binding:"omitempty,oneof=X Y Z"`
 	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
 	request.PageInfo
 }
@@ -51,6 +53,8 @@ type LocationResponse struct {
 	ItemID         string `db:"item_id" json:"item_id"`
 	ItemName       string `db:"item_name" json:"item_name"`
 	SKU            string `db:"sku" json:"sku"`
+	AbcClass       string `db:"abc_class" json:"abc_class"`
+	XyzClass       string `db:"xyz_class" json:"xyz_class"`
 	Capacity       int    `db:"capacity" json:"capacity"`
 	Quantity       int    `db:"quantity" json:"quantity"`
 	Available      int    `db:"available" json:"available"`
@@ -116,3 +120,55 @@ type AdjustmentResponse struct {
 	Remark               string  `db:"remark" json:"remark"`
 	Status               int     `db:"status" json:"status"`
 }
+
+type AssemblyNew struct {
+	AssemblyNumber string `json:"assembly_number" binding:"required,min=1,max=64"`
+	AssemblyDate   string `json:"assembly_date" binding:"required,datetime=2006-01-02"`
+	ItemID         string `json:"item_id" binding:"required,min=1,max=64"`
+	Type           int    `json:"type" binding:"required,oneof=1 2"`
+	Quantity       int    `json:"quantity" binding:"required,min=1"`
+	Remark         string `json:"remark" binding:"omitempty,max=255"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	User           string `json:"user" swaggerignore:"true"`
+	Email          string `json:"email" swaggerignore:"true"`
+}
+
+type AssemblyFilter struct {
+	ItemID         string `form:"item_id" binding:"omitempty"`
+	Type           int    `form:"type" binding:"omitempty,oneof=1 2"`
+	OrganizationID string `json:"organiztion_id" swaggerignore:"true"`
+	request.PageInfo
+}
+
+type AssemblyResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	AssemblyID     string  `db:"assembly_id" json:"assembly_id"`
+	AssemblyNumber string  `db:"assembly_number" json:"assembly_number"`
+	AssemblyDate   string  `db:"assembly_date" json:"assembly_date"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	ItemName       string  `db:"item_name" json:"item_name"`
+	SKU            string  `db:"sku" json:"sku"`
+	Type           int     `db:"type" json:"type"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Rate           float64 `db:"rate" json:"rate"`
+	Amount         float64 `db:"amount" json:"amount"`
+	Remark         string  `db:"remark" json:"remark"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type AssemblyItemResponse struct {
+	OrganizationID string  `db:"organization_id" json:"organization_id"`
+	AssemblyID     string  `db:"assembly_id" json:"assembly_id"`
+	AssemblyItemID string  `db:"assembly_item_id" json:"assembly_item_id"`
+	ItemID         string  `db:"item_id" json:"item_id"`
+	ItemName       string  `db:"item_name" json:"item_name"`
+	SKU            string  `db:"sku" json:"sku"`
+	Quantity       int     `db:"quantity" json:"quantity"`
+	Rate           float64 `db:"rate" json:"rate"`
+	Amount         float64 `db:"amount" json:"amount"`
+	Status         int     `db:"status" json:"status"`
+}
+
+type AssemblyID struct {
+	ID string `uri:"id" binding:"required,min=1"`
+}
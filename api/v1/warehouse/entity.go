@@ -55,3 +55,38 @@ type Adjustment struct {
 	Updated            time.Time `db:"updated" json:"updated"`
 	UpdatedBy          string    `db:"updated_by" json:"updated_by"`
 }
+
+type Assembly struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	AssemblyID     string    `db:"assembly_id" json:"assembly_id"`
+	AssemblyNumber string    `db:"assembly_number" json:"assembly_number"`
+	AssemblyDate   string    `db:"assembly_date" json:"assembly_date"`
+	ItemID         string    `db:"item_id" json:"item_id"`
+	Type           int       `db:"type" json:"type"` //1 assembly, 2 disassembly
+	Quantity       int       `db:"quantity" json:"quantity"`
+	Rate           float64   `db:"rate" json:"rate"`
+	Amount         float64   `db:"amount" json:"amount"`
+	Remark         string    `db:"remark" json:"remark"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
+
+type AssemblyItem struct {
+	ID             int64     `db:"id" json:"id"`
+	OrganizationID string    `db:"organization_id" json:"organization_id"`
+	AssemblyID     string    `db:"assembly_id" json:"assembly_id"`
+	AssemblyItemID string    `db:"assembly_item_id" json:"assembly_item_id"`
+	ItemID         string    `db:"item_id" json:"item_id"`
+	Quantity       int       `db:"quantity" json:"quantity"`
+	Rate           float64   `db:"rate" json:"rate"`
+	Amount         float64   `db:"amount" json:"amount"`
+	Status         int       `db:"status" json:"status"`
+	Created        time.Time `db:"created" json:"created"`
+	CreatedBy      string    `db:"created_by" json:"created_by"`
+	Updated        time.Time `db:"updated" json:"updated"`
+	UpdatedBy      string    `db:"updated_by" json:"updated_by"`
+}
@@ -93,6 +93,12 @@ func (r *warehouseQuery) GetLocationCount(filter LocationFilter) (int, error) {
 	if v := filter.IsAlert; v {
 		where = append(where, "quantity < alert")
 	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "item_id IN (SELECT item_id FROM i_items WHERE abc_class = ?)"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "item_id IN (SELECT item_id FROM i_items WHERE xyz_class = ?)"), append(args, v)
+	}
 	var count int
 	err := r.conn.Get(&count, `
 		SELECT count(1) as count
@@ -118,6 +124,12 @@ func (r *warehouseQuery) GetLocationList(filter LocationFilter) (*[]LocationResp
 	if v := filter.IsAlert; v {
 		where = append(where, "l.quantity < l.alert")
 	}
+	if v := filter.AbcClass; v != "" {
+		where, args = append(where, "i.abc_class = ?"), append(args, v)
+	}
+	if v := filter.XyzClass; v != "" {
+		where, args = append(where, "i.xyz_class = ?"), append(args, v)
+	}
 	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
 	args = append(args, filter.PageSize)
 	var locations []LocationResponse
@@ -132,6 +144,8 @@ func (r *warehouseQuery) GetLocationList(filter LocationFilter) (*[]LocationResp
 		l.item_id,
 		i.name as item_name,
 		i.sku,
+		IFNULL(i.abc_class, "") as abc_class,
+		IFNULL(i.xyz_class, "") as xyz_class,
 		l.capacity,
 		l.quantity,
 		l.available,
@@ -162,6 +176,8 @@ func (r *warehouseQuery) GetLocationByCode(organizationID, locationCode string)
 		l.item_id,
 		i.name as item_name,
 		i.sku,
+		IFNULL(i.abc_class, "") as abc_class,
+		IFNULL(i.xyz_class, "") as xyz_class,
 		l.capacity,
 		l.quantity,
 		l.available,
@@ -178,6 +194,37 @@ func (r *warehouseQuery) GetLocationByCode(organizationID, locationCode string)
 	return &location, err
 }
 
+func (r *warehouseQuery) GetLocationByID(organizationID, locationID string) (*LocationResponse, error) {
+	var location LocationResponse
+	err := r.conn.Get(&location, `
+		SELECT 
+		l.location_id, 
+		l.organization_id,
+		l.code,
+		l.level, 
+		l.bay_id,
+		b.code as bay_code,
+		l.item_id,
+		i.name as item_name,
+		i.sku,
+		IFNULL(i.abc_class, "") as abc_class,
+		IFNULL(i.xyz_class, "") as xyz_class,
+		l.capacity,
+		l.quantity,
+		l.available,
+		l.can_pick,
+		l.alert, 
+		l.status
+		FROM w_locations l
+		LEFT JOIN w_bays b
+		ON l.bay_id = b.bay_id
+		LEFT JOIN i_items i
+		ON l.item_id = i.item_id
+		WHERE l.organization_id = ? AND l.location_id = ? AND l.status > 0
+	`, organizationID, locationID)
+	return &location, err
+}
+
 //Adjustment
 func (r *warehouseQuery) GetAdjustmentCount(filter AdjustmentFilter) (int, error) {
 	where, args := []string{"status > 0"}, []interface{}{}
@@ -242,3 +289,110 @@ func (r *warehouseQuery) GetAdjustmentList(filter AdjustmentFilter) (*[]Adjustme
 	`, args...)
 	return &adjustments, err
 }
+
+//Assembly
+func (r *warehouseQuery) GetAssemblyCount(filter AssemblyFilter) (int, error) {
+	where, args := []string{"status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "item_id = ?"), append(args, v)
+	}
+	if v := filter.Type; v != 0 {
+		where, args = append(where, "type = ?"), append(args, v)
+	}
+	var count int
+	err := r.conn.Get(&count, `
+		SELECT count(1) as count
+		FROM i_assemblies
+		WHERE `+strings.Join(where, " AND "), args...)
+	return count, err
+}
+
+func (r *warehouseQuery) GetAssemblyList(filter AssemblyFilter) (*[]AssemblyResponse, error) {
+	where, args := []string{"a.status > 0"}, []interface{}{}
+	if v := filter.OrganizationID; v != "" {
+		where, args = append(where, "a.organization_id = ?"), append(args, v)
+	}
+	if v := filter.ItemID; v != "" {
+		where, args = append(where, "a.item_id = ?"), append(args, v)
+	}
+	if v := filter.Type; v != 0 {
+		where, args = append(where, "a.type = ?"), append(args, v)
+	}
+	args = append(args, filter.PageID*filter.PageSize-filter.PageSize)
+	args = append(args, filter.PageSize)
+	var assemblies []AssemblyResponse
+	err := r.conn.Select(&assemblies, `
+		SELECT
+		a.organization_id,
+		a.assembly_id,
+		a.assembly_number,
+		a.assembly_date,
+		a.item_id,
+		i.name as item_name,
+		i.sku,
+		a.type,
+		a.quantity,
+		a.rate,
+		a.amount,
+		a.remark,
+		a.status
+		FROM i_assemblies a
+		LEFT JOIN i_items i
+		ON a.item_id = i.item_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY a.id DESC
+		LIMIT ?, ?
+	`, args...)
+	return &assemblies, err
+}
+
+func (r *warehouseQuery) GetAssemblyByID(organizationID, assemblyID string) (*AssemblyResponse, error) {
+	var assembly AssemblyResponse
+	err := r.conn.Get(&assembly, `
+		SELECT
+		a.organization_id,
+		a.assembly_id,
+		a.assembly_number,
+		a.assembly_date,
+		a.item_id,
+		i.name as item_name,
+		i.sku,
+		a.type,
+		a.quantity,
+		a.rate,
+		a.amount,
+		a.remark,
+		a.status
+		FROM i_assemblies a
+		LEFT JOIN i_items i
+		ON a.item_id = i.item_id
+		WHERE a.organization_id = ? AND a.assembly_id = ? AND a.status > 0
+	`, organizationID, assemblyID)
+	return &assembly, err
+}
+
+func (r *warehouseQuery) GetAssemblyItemList(organizationID, assemblyID string) (*[]AssemblyItemResponse, error) {
+	var items []AssemblyItemResponse
+	err := r.conn.Select(&items, `
+		SELECT
+		a.organization_id,
+		a.assembly_id,
+		a.assembly_item_id,
+		a.item_id,
+		i.name as item_name,
+		i.sku,
+		a.quantity,
+		a.rate,
+		a.amount,
+		a.status
+		FROM i_assembly_items a
+		LEFT JOIN i_items i
+		ON a.item_id = i.item_id
+		WHERE a.organization_id = ? AND a.assembly_id = ? AND a.status > 0
+		ORDER BY a.id ASC
+	`, organizationID, assemblyID)
+	return &items, err
+}
@@ -284,3 +284,64 @@ func (r warehouseRepository) CreateAdjustment(info Adjustment) error {
 	`, info.OrganizationID, info.LocationID, info.ItemID, info.AdjustmentID, info.Quantity, info.OriginalQuantiy, info.NewQuantiy, info.Rate, info.AdjustmentDate, info.AdjustmentReasonID, info.Remark, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
 	return err
 }
+
+//Assembly
+
+func (r *warehouseRepository) CheckAssemblyNumberConfict(assemblyID, organizationID, assemblyNumber string) (bool, error) {
+	var existed int
+	row := r.tx.QueryRow("SELECT count(1) FROM i_assemblies WHERE organization_id = ? AND assembly_id != ? AND assembly_number = ? AND status > 0", organizationID, assemblyID, assemblyNumber)
+	err := row.Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	return existed != 0, nil
+}
+
+func (r warehouseRepository) CreateAssembly(info Assembly) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_assemblies
+		(
+			organization_id,
+			assembly_id,
+			assembly_number,
+			assembly_date,
+			item_id,
+			type,
+			quantity,
+			rate,
+			amount,
+			remark,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.AssemblyID, info.AssemblyNumber, info.AssemblyDate, info.ItemID, info.Type, info.Quantity, info.Rate, info.Amount, info.Remark, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
+
+func (r warehouseRepository) CreateAssemblyItem(info AssemblyItem) error {
+	_, err := r.tx.Exec(`
+		INSERT INTO i_assembly_items
+		(
+			organization_id,
+			assembly_id,
+			assembly_item_id,
+			item_id,
+			quantity,
+			rate,
+			amount,
+			status,
+			created,
+			created_by,
+			updated,
+			updated_by
+		)
+		VALUES
+		(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.OrganizationID, info.AssemblyID, info.AssemblyItemID, info.ItemID, info.Quantity, info.Rate, info.Amount, info.Status, info.Created, info.CreatedBy, info.Updated, info.UpdatedBy)
+	return err
+}
@@ -18,4 +18,8 @@ func AuthRouter(g *gin.RouterGroup) {
 	g.POST("/adjustments", NewAdjustment)
 	g.GET("/adjustments", GetAdjustmentList)
 
+	g.POST("/assemblies", NewAssembly)
+	g.GET("/assemblies", GetAssemblyList)
+	g.GET("/assemblies/:id/items", GetAssemblyItemList)
+
 }
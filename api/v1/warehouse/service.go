@@ -1,6 +1,7 @@
 package warehouse
 
 import (
+	"database/sql"
 	"errors"
 	"go-api/api/v1/item"
 	"go-api/api/v1/setting"
@@ -179,25 +180,40 @@ func (s *warehouseService) NewLocation(info LocationNew) (*string, error) {
 		return nil, errors.New(msg)
 	}
 	defer tx.Rollback()
+	locationID, err := CreateLocation(tx, info)
+	if err != nil {
+		return nil, err
+	}
+	tx.Commit()
+	return &locationID, err
+}
+
+// CreateLocation saves a location of a stocked item within tx
+func CreateLocation(tx *sql.Tx, info LocationNew) (string, error) {
 	repo := NewWarehouseRepository(tx)
 	isConflict, err := repo.CheckLocationConfict("", info.OrganizationID, info.Code)
 	if err != nil {
 		msg := "check conflict error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	if isConflict {
 		msg := "location code conflict"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
 	_, err = repo.GetBayByID(info.BayID, info.OrganizationID)
 	if err != nil {
 		msg := "bay not exist"
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	itemService := item.NewItemService()
-	_, err = itemService.GetItemByID(info.OrganizationID, info.ItemID)
+	itemRepo := item.NewItemRepository(tx)
+	itemInfo, err := itemRepo.GetItemByID(info.ItemID, info.OrganizationID)
 	if err != nil {
-		return nil, err
+		msg := "Item not exist"
+		return "", errors.New(msg)
+	}
+	if itemInfo.ItemType == 2 {
+		msg := "sales kit can not be stored in location"
+		return "", errors.New(msg)
 	}
 	var location Location
 	location.LocationID = "loc-" + xid.New().String()
@@ -220,10 +236,9 @@ func (s *warehouseService) NewLocation(info LocationNew) (*string, error) {
 	err = repo.CreateLocation(location)
 	if err != nil {
 		msg := "create location error: " + err.Error()
-		return nil, errors.New(msg)
+		return "", errors.New(msg)
 	}
-	tx.Commit()
-	return &location.LocationID, err
+	return location.LocationID, nil
 }
 
 func (s *warehouseService) UpdateLocation(locationID string, info LocationNew) (*LocationResponse, error) {
@@ -470,3 +485,277 @@ func (s *warehouseService) GetAdjustmentList(filter AdjustmentFilter) (int, *[]A
 	}
 	return count, list, err
 }
+
+//Assembly
+
+// takeItemStock removes quantity of an item from stock and returns its cost,
+// first in first out over the batches of items tracked in locations.
+func takeItemStock(tx *sql.Tx, itemInfo *item.ItemResponse, quantity int, email string) (float64, error) {
+	repo := NewWarehouseRepository(tx)
+	itemRepo := item.NewItemRepository(tx)
+	if itemInfo.StockAvailable < quantity {
+		msg := "item stock not enough: " + itemInfo.SKU
+		return 0, errors.New(msg)
+	}
+	amount := 0.0
+	if itemInfo.TrackLocation == 1 {
+		toTake := quantity
+		for toTake > 0 {
+			nextBatch, err := itemRepo.GetItemNextBatch(itemInfo.ItemID, itemInfo.OrganizationID)
+			if err != nil {
+				msg := "get next batch error: " + itemInfo.SKU
+				return 0, errors.New(msg)
+			}
+			taken := nextBatch.Balance
+			if taken > toTake {
+				taken = toTake
+			}
+			err = itemRepo.PickItem(nextBatch.BatchID, taken, email)
+			if err != nil {
+				msg := "pick item from batch error"
+				return 0, errors.New(msg)
+			}
+			err = repo.UpdateLocationCanPick(nextBatch.LocationID, taken, email)
+			if err != nil {
+				msg := "update location canpick error"
+				return 0, errors.New(msg)
+			}
+			err = repo.UpdateLocationPicked(nextBatch.LocationID, taken, email)
+			if err != nil {
+				msg := "update location stock error"
+				return 0, errors.New(msg)
+			}
+			amount += float64(taken) * nextBatch.Rate
+			toTake = toTake - taken
+		}
+	} else {
+		amount = float64(quantity) * itemInfo.CostPrice
+	}
+	err := itemRepo.UpdateItemStock(itemInfo.ItemID, -quantity, email)
+	if err != nil {
+		msg := "update item stock error"
+		return 0, errors.New(msg)
+	}
+	return amount, nil
+}
+
+// putItemStock adds quantity of an item to stock at rate, filling its
+// locations with a new batch each when it is tracked in locations.
+func putItemStock(tx *sql.Tx, itemInfo *item.ItemResponse, quantity int, rate float64, batchType, referenceID, email string) error {
+	repo := NewWarehouseRepository(tx)
+	itemRepo := item.NewItemRepository(tx)
+	if itemInfo.TrackLocation == 1 {
+		canReceive, err := repo.GetItemAvailable(itemInfo.ItemID, itemInfo.OrganizationID)
+		if err != nil {
+			msg := "get available location error: " + itemInfo.SKU
+			return errors.New(msg)
+		}
+		if canReceive < quantity {
+			msg := "no enough space to receive item: " + itemInfo.SKU
+			return errors.New(msg)
+		}
+		toReceive := quantity
+		for toReceive > 0 {
+			nextLocation, err := repo.GetNextLocation(itemInfo.ItemID, itemInfo.OrganizationID)
+			if err != nil {
+				msg := "get next location error"
+				return errors.New(msg)
+			}
+			received := nextLocation.Available
+			if received > toReceive {
+				received = toReceive
+			}
+			err = repo.ReceiveItem(nextLocation.LocationID, received, email)
+			if err != nil {
+				msg := "receive item to location error"
+				return errors.New(msg)
+			}
+			var batch item.ItemBatch
+			batch.OrganizationID = itemInfo.OrganizationID
+			batch.ItemID = itemInfo.ItemID
+			batch.BatchID = "bat-" + xid.New().String()
+			batch.Type = batchType
+			batch.ReferenceID = referenceID
+			batch.LocationID = nextLocation.LocationID
+			batch.Quantity = received
+			batch.Rate = rate
+			batch.Balance = received
+			batch.Status = 1
+			batch.Created = time.Now()
+			batch.CreatedBy = email
+			batch.Updated = time.Now()
+			batch.UpdatedBy = email
+			err = itemRepo.CreateItemBatch(batch)
+			if err != nil {
+				msg := "create item batch error"
+				return errors.New(msg)
+			}
+			toReceive = toReceive - received
+		}
+	}
+	err := itemRepo.UpdateItemStock(itemInfo.ItemID, quantity, email)
+	if err != nil {
+		msg := "update item stock error"
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *warehouseService) NewAssembly(info AssemblyNew) (*string, error) {
+	db := database.WDB()
+	tx, err := db.Begin()
+	if err != nil {
+		msg := "begin transaction error"
+		return nil, errors.New(msg)
+	}
+	defer tx.Rollback()
+	repo := NewWarehouseRepository(tx)
+	itemRepo := item.NewItemRepository(tx)
+	isConflict, err := repo.CheckAssemblyNumberConfict("", info.OrganizationID, info.AssemblyNumber)
+	if err != nil {
+		msg := "check conflict error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	if isConflict {
+		msg := "assembly number exists"
+		return nil, errors.New(msg)
+	}
+	kitInfo, err := itemRepo.GetItemByID(info.ItemID, info.OrganizationID)
+	if err != nil {
+		msg := "item not exist"
+		return nil, errors.New(msg)
+	}
+	if kitInfo.ItemType != 3 {
+		msg := "item is not an assembled kit"
+		return nil, errors.New(msg)
+	}
+	components, err := itemRepo.GetItemComponentList(info.ItemID, info.OrganizationID)
+	if err != nil {
+		msg := "get item components error"
+		return nil, errors.New(msg)
+	}
+	if len(*components) == 0 {
+		msg := "kit has no components"
+		return nil, errors.New(msg)
+	}
+	assemblyID := "asm-" + xid.New().String()
+	var componentInfos []*item.ItemResponse
+	standardCost := 0.0
+	for _, component := range *components {
+		componentInfo, err := itemRepo.GetItemByID(component.ComponentID, info.OrganizationID)
+		if err != nil {
+			msg := "component not exist: " + component.SKU
+			return nil, errors.New(msg)
+		}
+		componentInfos = append(componentInfos, componentInfo)
+		standardCost += float64(component.Quantity) * componentInfo.CostPrice
+	}
+	amount := 0.0
+	if info.Type == 2 {
+		amount, err = takeItemStock(tx, kitInfo, info.Quantity, info.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, component := range *components {
+		componentInfo := componentInfos[i]
+		quantity := component.Quantity * info.Quantity
+		componentAmount := 0.0
+		if info.Type == 1 {
+			componentAmount, err = takeItemStock(tx, componentInfo, quantity, info.Email)
+			if err != nil {
+				return nil, err
+			}
+			amount += componentAmount
+		} else {
+			//kit cost goes back to the components by their share of the standard cost
+			if standardCost > 0 {
+				componentAmount = amount * float64(component.Quantity) * componentInfo.CostPrice / standardCost
+			} else {
+				componentAmount = amount / float64(len(*components))
+			}
+			err = putItemStock(tx, componentInfo, quantity, componentAmount/float64(quantity), "Disassembly", assemblyID, info.Email)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var assemblyItem AssemblyItem
+		assemblyItem.OrganizationID = info.OrganizationID
+		assemblyItem.AssemblyID = assemblyID
+		assemblyItem.AssemblyItemID = "asmi-" + xid.New().String()
+		assemblyItem.ItemID = component.ComponentID
+		assemblyItem.Quantity = quantity
+		assemblyItem.Rate = componentAmount / float64(quantity)
+		assemblyItem.Amount = componentAmount
+		assemblyItem.Status = 1
+		assemblyItem.Created = time.Now()
+		assemblyItem.CreatedBy = info.Email
+		assemblyItem.Updated = time.Now()
+		assemblyItem.UpdatedBy = info.Email
+		err = repo.CreateAssemblyItem(assemblyItem)
+		if err != nil {
+			msg := "create assembly item error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+	}
+	rate := amount / float64(info.Quantity)
+	if info.Type == 1 {
+		err = putItemStock(tx, kitInfo, info.Quantity, rate, "Assembly", assemblyID, info.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var assembly Assembly
+	assembly.OrganizationID = info.OrganizationID
+	assembly.AssemblyID = assemblyID
+	assembly.AssemblyNumber = info.AssemblyNumber
+	assembly.AssemblyDate = info.AssemblyDate
+	assembly.ItemID = info.ItemID
+	assembly.Type = info.Type
+	assembly.Quantity = info.Quantity
+	assembly.Rate = rate
+	assembly.Amount = amount
+	assembly.Remark = info.Remark
+	assembly.Status = 1
+	assembly.Created = time.Now()
+	assembly.CreatedBy = info.Email
+	assembly.Updated = time.Now()
+	assembly.UpdatedBy = info.Email
+	err = repo.CreateAssembly(assembly)
+	if err != nil {
+		msg := "create assembly error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	tx.Commit()
+	return &assemblyID, nil
+}
+
+func (s *warehouseService) GetAssemblyList(filter AssemblyFilter) (int, *[]AssemblyResponse, error) {
+	db := database.RDB()
+	query := NewWarehouseQuery(db)
+	count, err := query.GetAssemblyCount(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := query.GetAssemblyList(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, list, err
+}
+
+func (s *warehouseService) GetAssemblyItemList(organizationID, assemblyID string) (*[]AssemblyItemResponse, error) {
+	db := database.RDB()
+	query := NewWarehouseQuery(db)
+	_, err := query.GetAssemblyByID(organizationID, assemblyID)
+	if err != nil {
+		msg := "assembly not exist"
+		return nil, errors.New(msg)
+	}
+	list, err := query.GetAssemblyItemList(organizationID, assemblyID)
+	if err != nil {
+		msg := "get assembly items error: " + err.Error()
+		return nil, errors.New(msg)
+	}
+	return list, nil
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"go-api/api/v1/auth"
+	"go-api/api/v1/bank"
+	"go-api/api/v1/bulk"
 	"go-api/api/v1/common"
 	"go-api/api/v1/crm"
 	"go-api/api/v1/item"
+	"go-api/api/v1/label"
 	"go-api/api/v1/organization"
 	"go-api/api/v1/purchaseorder"
 	"go-api/api/v1/report"
@@ -14,7 +17,9 @@ import (
 	"go-api/core/config"
 	"go-api/core/database"
 	"go-api/core/event"
+	"go-api/core/job"
 	"go-api/core/log"
+	"go-api/core/pdf"
 	"go-api/core/router"
 )
 
@@ -23,9 +28,11 @@ func Run(args []string) {
 	log.ConfigLogger()
 	// cache.ConfigCache()
 	database.ConfigMysql()
+	pdf.ConfigFont()
 	event.Subscribe(auth.Subscribe, common.Subscribe, item.Subscribe, setting.Subscribe)
+	job.Schedule(salesorder.Schedule, purchaseorder.Schedule, item.Schedule)
 	r := router.InitRouter()
-	router.InitPublicRouter(r, auth.Routers, organization.Routers)
-	router.InitAuthRouter(r, auth.AuthRouter, setting.AuthRouter, item.AuthRouter, purchaseorder.AuthRouter, warehouse.AuthRouter, common.AuthRouter, salesorder.AuthRouter, crm.AuthRouter, report.AuthRouter)
+	router.InitPublicRouter(r, auth.Routers, organization.Routers, salesorder.Routers)
+	router.InitAuthRouter(r, auth.AuthRouter, setting.AuthRouter, item.AuthRouter, purchaseorder.AuthRouter, warehouse.AuthRouter, common.AuthRouter, salesorder.AuthRouter, crm.AuthRouter, report.AuthRouter, bank.AuthRouter, bulk.AuthRouter, label.AuthRouter)
 	router.RunServer(r)
 }
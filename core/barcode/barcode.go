@@ -0,0 +1,109 @@
+package barcode
+
+import (
+	"errors"
+	"strconv"
+)
+
+const (
+	Code128 = "code128"
+	EAN13   = "ean13"
+	QR      = "qr"
+)
+
+// Code is an encoded symbol. A linear code has a height of one module, each
+// module being one narrow bar or space.
+type Code struct {
+	Symbology string
+	Text      string //human readable text, with the check digit for EAN-13
+	Width     int
+	Height    int
+	QuietZone int //light modules needed around the symbol
+	modules   []bool
+}
+
+func newCode(symbology, text string, width, height, quietZone int) *Code {
+	return &Code{Symbology: symbology, Text: text, Width: width, Height: height, QuietZone: quietZone, modules: make([]bool, width*height)}
+}
+
+// Dark tells whether the module at column x and row y is dark
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+		return false
+	}
+	return c.modules[y*c.Width+x]
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Width+x] = dark
+}
+
+// Linear tells whether the code is a one dimensional bar code
+func (c *Code) Linear() bool {
+	return c.Height == 1
+}
+
+// Encode encodes data with the given symbology
+func Encode(symbology, data string) (*Code, error) {
+	switch symbology {
+	case Code128:
+		return EncodeCode128(data)
+	case EAN13:
+		return EncodeEAN13(data)
+	case QR:
+		return EncodeQR(data)
+	}
+	return nil, errors.New("unknown symbology " + symbology)
+}
+
+// CheckDigit computes the GS1 mod 10 check digit of the digits given, which
+// must not include the check digit itself
+func CheckDigit(digits string) (int, error) {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := digits[len(digits)-1-i]
+		if d < '0' || d > '9' {
+			return 0, errors.New("only digits have a check digit")
+		}
+		if i%2 == 0 {
+			sum += int(d-'0') * 3
+		} else {
+			sum += int(d - '0')
+		}
+	}
+	return (10 - sum%10) % 10, nil
+}
+
+// IsGTIN tells whether the code has the length of a GTIN-8, 12, 13 or 14 and
+// only digits, such codes must carry a valid check digit
+func IsGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks a code before it is saved. GTIN codes must have a valid
+// check digit, other codes must be printable as Code 128.
+func Validate(code string) error {
+	if IsGTIN(code) {
+		check, _ := CheckDigit(code[:len(code)-1])
+		if int(code[len(code)-1]-'0') != check {
+			return errors.New("invalid check digit, expected " + strconv.Itoa(check))
+		}
+		return nil
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 32 || code[i] > 126 {
+			return errors.New("barcode may only contain printable ascii characters")
+		}
+	}
+	return nil
+}
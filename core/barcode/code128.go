@@ -0,0 +1,101 @@
+package barcode
+
+import "errors"
+
+// code128Patterns holds the bar and space widths of each symbol value,
+// starting with a bar. 103 to 105 are the start codes, 106 is the stop code.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+func digitRun(data string, from int) int {
+	n := 0
+	for i := from; i < len(data) && data[i] >= '0' && data[i] <= '9'; i++ {
+		n++
+	}
+	return n
+}
+
+// EncodeCode128 encodes printable ascii with code set B, switching to code
+// set C for runs of digits where it makes the symbol shorter
+func EncodeCode128(data string) (*Code, error) {
+	if data == "" {
+		return nil, errors.New("nothing to encode")
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] < 32 || data[i] > 126 {
+			return nil, errors.New("code 128 only encodes printable ascii characters")
+		}
+	}
+	var values []int
+	setC := false
+	if run := digitRun(data, 0); run >= 4 || run == len(data) && run%2 == 0 {
+		values = append(values, code128StartC)
+		setC = true
+	} else {
+		values = append(values, code128StartB)
+	}
+	for i := 0; i < len(data); {
+		run := digitRun(data, i)
+		if setC {
+			if run >= 2 {
+				values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+				continue
+			}
+			values = append(values, code128CodeB)
+			setC = false
+		}
+		if run >= 6 || run >= 4 && i+run == len(data) {
+			if run%2 == 1 {
+				values = append(values, int(data[i])-32)
+				i++
+			}
+			values = append(values, code128CodeC)
+			setC = true
+			continue
+		}
+		values = append(values, int(data[i])-32)
+		i++
+	}
+	check := values[0]
+	for i, v := range values[1:] {
+		check += (i + 1) * v
+	}
+	values = append(values, check%103, code128Stop)
+	width := 0
+	for _, v := range values {
+		for _, w := range code128Patterns[v] {
+			width += int(w - '0')
+		}
+	}
+	code := newCode(Code128, data, width, 1, 10)
+	x := 0
+	for _, v := range values {
+		for i, w := range code128Patterns[v] {
+			for n := 0; n < int(w-'0'); n++ {
+				code.set(x, 0, i%2 == 0)
+				x++
+			}
+		}
+	}
+	return code, nil
+}
@@ -0,0 +1,50 @@
+package barcode
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ean13L = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	ean13G = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	ean13R = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+	// ean13Parity gives the L or G set of the left half, the first digit is
+	// not encoded but read from this parity
+	ean13Parity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLG", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// EncodeEAN13 encodes 12 digits, adding the check digit, or 13 digits with a
+// valid check digit
+func EncodeEAN13(data string) (*Code, error) {
+	if len(data) != 12 && len(data) != 13 {
+		return nil, errors.New("ean-13 needs 12 or 13 digits")
+	}
+	check, err := CheckDigit(data[:12])
+	if err != nil {
+		return nil, errors.New("ean-13 only encodes digits")
+	}
+	if len(data) == 13 && int(data[12]-'0') != check {
+		return nil, errors.New("invalid check digit, expected " + strconv.Itoa(check))
+	}
+	data = data[:12] + strconv.Itoa(check)
+	bits := "101"
+	parity := ean13Parity[data[0]-'0']
+	for i := 1; i <= 6; i++ {
+		if parity[i-1] == 'L' {
+			bits += ean13L[data[i]-'0']
+		} else {
+			bits += ean13G[data[i]-'0']
+		}
+	}
+	bits += "01010"
+	for i := 7; i <= 12; i++ {
+		bits += ean13R[data[i]-'0']
+	}
+	bits += "101"
+	code := newCode(EAN13, data, len(bits), 1, 11)
+	for x := range bits {
+		code.set(x, 0, bits[x] == '1')
+	}
+	return code, nil
+}
@@ -0,0 +1,397 @@
+package barcode
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GS is the group separator scanners transmit for FNC1 between variable
+// length elements
+const GS = "\x1d"
+
+// GS1 holds the application identifiers read from a GS1-128 or GS1
+// DataMatrix code
+type GS1 struct {
+	GTIN     string //(01), or (02) the contained trade items of a logistic unit
+	Lot      string //(10)
+	Serial   string //(21)
+	Expiry   string //(17) as 2006-01-02
+	Count    int    //(30) or (37), zero when not given
+	Elements []GS1Element
+}
+
+type GS1Element struct {
+	AI    string `json:"ai"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type gs1AI struct {
+	name   string
+	length int //fixed data length, zero for variable
+	max    int
+	date   bool
+}
+
+var gs1AIs = map[string]gs1AI{
+	"00":   {"SSCC", 18, 18, false},
+	"01":   {"GTIN", 14, 14, false},
+	"02":   {"CONTENT", 14, 14, false},
+	"10":   {"BATCH/LOT", 0, 20, false},
+	"11":   {"PROD DATE", 6, 6, true},
+	"12":   {"DUE DATE", 6, 6, true},
+	"13":   {"PACK DATE", 6, 6, true},
+	"15":   {"BEST BEFORE", 6, 6, true},
+	"16":   {"SELL BY", 6, 6, true},
+	"17":   {"USE BY OR EXPIRY", 6, 6, true},
+	"20":   {"VARIANT", 2, 2, false},
+	"21":   {"SERIAL", 0, 20, false},
+	"22":   {"CPV", 0, 20, false},
+	"235":  {"TPX", 0, 28, false},
+	"240":  {"ADDITIONAL ID", 0, 30, false},
+	"241":  {"CUST. PART No.", 0, 30, false},
+	"242":  {"MTO VARIANT", 0, 6, false},
+	"243":  {"PCN", 0, 20, false},
+	"250":  {"SECONDARY SERIAL", 0, 30, false},
+	"251":  {"REF. TO SOURCE", 0, 30, false},
+	"253":  {"GDTI", 0, 30, false},
+	"254":  {"GLN EXTENSION COMPONENT", 0, 20, false},
+	"255":  {"GCN", 0, 25, false},
+	"30":   {"VAR. COUNT", 0, 8, false},
+	"37":   {"COUNT", 0, 8, false},
+	"400":  {"ORDER NUMBER", 0, 30, false},
+	"401":  {"GINC", 0, 30, false},
+	"402":  {"GSIN", 17, 17, false},
+	"403":  {"ROUTE", 0, 30, false},
+	"410":  {"SHIP TO LOC", 13, 13, false},
+	"411":  {"BILL TO", 13, 13, false},
+	"412":  {"PURCHASE FROM", 13, 13, false},
+	"413":  {"SHIP FOR LOC", 13, 13, false},
+	"414":  {"LOC No.", 13, 13, false},
+	"415":  {"PAY TO", 13, 13, false},
+	"416":  {"PROD/SERV LOC", 13, 13, false},
+	"417":  {"PARTY", 13, 13, false},
+	"420":  {"SHIP TO POST", 0, 20, false},
+	"421":  {"SHIP TO POST", 0, 12, false},
+	"422":  {"ORIGIN", 3, 3, false},
+	"423":  {"COUNTRY - INITIAL PROCESS.", 0, 15, false},
+	"424":  {"COUNTRY - PROCESS.", 3, 3, false},
+	"425":  {"COUNTRY - DISASSEMBLY", 0, 15, false},
+	"426":  {"COUNTRY - FULL PROCESS", 3, 3, false},
+	"427":  {"ORIGIN SUBDIVISION", 0, 3, false},
+	"7001": {"NSN", 13, 13, false},
+	"7002": {"MEAT CUT", 0, 30, false},
+	"7003": {"EXPIRY TIME", 10, 10, false},
+	"7004": {"ACTIVE POTENCY", 0, 4, false},
+	"7005": {"CATCH AREA", 0, 12, false},
+	"7006": {"FIRST FREEZE DATE", 6, 6, true},
+	"7007": {"HARVEST DATE", 0, 12, false},
+	"7008": {"AQUATIC SPECIES", 0, 3, false},
+	"7009": {"FISHING GEAR TYPE", 0, 10, false},
+	"7010": {"PROD METHOD", 0, 2, false},
+	"7020": {"REFURB LOT", 0, 20, false},
+	"7021": {"FUNC STAT", 0, 20, false},
+	"7022": {"REV STAT", 0, 20, false},
+	"7023": {"GIAI - ASSEMBLY", 0, 30, false},
+	"8001": {"DIMENSIONS", 14, 14, false},
+	"8002": {"CMT No.", 0, 20, false},
+	"8003": {"GRAI", 0, 30, false},
+	"8004": {"GIAI", 0, 30, false},
+	"8005": {"PRICE PER UNIT", 6, 6, false},
+	"8006": {"ITIP", 18, 18, false},
+	"8007": {"IBAN", 0, 34, false},
+	"8008": {"PROD TIME", 0, 12, false},
+	"8010": {"CPID", 0, 30, false},
+	"8011": {"CPID SERIAL", 0, 12, false},
+	"8012": {"VERSION", 0, 20, false},
+	"8017": {"GSRN - PROVIDER", 18, 18, false},
+	"8018": {"GSRN - RECIPIENT", 18, 18, false},
+	"8019": {"SRIN", 0, 10, false},
+	"8020": {"REF No.", 0, 25, false},
+	"8026": {"ITIP CONTENT", 18, 18, false},
+	"8200": {"PRODUCT URL", 0, 70, false},
+	"90":   {"INTERNAL", 0, 30, false},
+}
+
+// gs1AIFamilies are the four digit identifiers whose last digit is the
+// decimal position of the value, keyed by the first three digits. Measures
+// 310n to 369n not listed here are read as MEASURE.
+var gs1AIFamilies = map[string]gs1AI{
+	"310": {"NET WEIGHT (kg)", 6, 6, false},
+	"320": {"NET WEIGHT (lb)", 6, 6, false},
+	"330": {"GROSS WEIGHT (kg)", 6, 6, false},
+	"340": {"GROSS WEIGHT (lb)", 6, 6, false},
+	"390": {"AMOUNT", 0, 15, false},
+	"391": {"AMOUNT", 0, 18, false},
+	"392": {"PRICE", 0, 15, false},
+	"393": {"PRICE", 0, 18, false},
+	"394": {"PRCNT OFF", 0, 4, false},
+	"395": {"PRICE/UoM", 0, 6, false},
+}
+
+// gs1FixedLengths are the data lengths of the identifiers starting with
+// these two digits, they are never ended by FNC1 even when not known here
+var gs1FixedLengths = map[string]int{
+	"00": 18, "01": 14, "02": 14, "03": 14, "04": 16,
+	"11": 6, "12": 6, "13": 6, "14": 6, "15": 6, "16": 6, "17": 6, "18": 6, "19": 6,
+	"20": 2,
+	"31": 6, "32": 6, "33": 6, "34": 6, "35": 6, "36": 6,
+	"41": 13,
+}
+
+// symbologyIdentifiers are the prefixes scanners add to GS1 codes, they
+// stand for the leading FNC1
+var symbologyIdentifiers = []string{"]C1", "]d2", "]Q3", "]e0"}
+
+// aiLength is the number of digits of the application identifiers starting
+// with the two digits, zero when the range is not assigned
+func aiLength(prefix string) int {
+	switch {
+	case prefix <= "22", prefix == "30", prefix == "37", prefix >= "90":
+		return 2
+	case prefix <= "29", prefix >= "40" && prefix <= "42":
+		return 3
+	case prefix <= "39", prefix == "43", prefix >= "70" && prefix <= "72", prefix >= "80" && prefix <= "82":
+		return 4
+	}
+	return 0
+}
+
+// lookupAI finds the application identifier at the start of s. Identifiers
+// missing from the tables are returned as UNKNOWN, of variable length up to
+// the next FNC1 unless their first two digits have a predefined length.
+func lookupAI(s string) (string, gs1AI, bool) {
+	if len(s) < 2 || !isDigits(s[:2]) {
+		return "", gs1AI{}, false
+	}
+	n := aiLength(s[:2])
+	if n == 0 || len(s) < n || !isDigits(s[:n]) {
+		return "", gs1AI{}, false
+	}
+	code := s[:n]
+	if ai, ok := gs1AIs[code]; ok {
+		return code, ai, true
+	}
+	if ai, ok := gs1AIFamilies[code[:n-1]]; ok && n == 4 {
+		return code, ai, true
+	}
+	if length, ok := gs1FixedLengths[code[:2]]; ok {
+		name := "UNKNOWN"
+		if code[0] == '3' {
+			name = "MEASURE"
+		}
+		return code, gs1AI{name, length, length, false}, true
+	}
+	name := "UNKNOWN"
+	if code[0] == '9' {
+		name = "INTERNAL"
+	}
+	return code, gs1AI{name, 0, 90, false}, true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// IsGS1 tells whether the data looks like a GS1 element string, in human
+// readable form, with a symbology identifier or with a separator
+func IsGS1(data string) bool {
+	if strings.HasPrefix(data, "(") || strings.Contains(data, GS) {
+		return true
+	}
+	for _, prefix := range symbologyIdentifiers {
+		if strings.HasPrefix(data, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGS1 reads the elements of a GS1 code, either in the human readable
+// form "(01)09501101530003(10)AB12" or as transmitted by the scanner, with
+// variable length elements ended by FNC1 sent as the GS character
+func ParseGS1(data string) (*GS1, error) {
+	data = strings.TrimSpace(data)
+	for _, prefix := range symbologyIdentifiers {
+		data = strings.TrimPrefix(data, prefix)
+	}
+	var elements []GS1Element
+	var err error
+	if strings.HasPrefix(data, "(") {
+		elements, err = parseHumanReadable(data)
+	} else {
+		elements, err = parseRaw(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("no gs1 element found")
+	}
+	var res GS1
+	res.Elements = elements
+	for _, e := range elements {
+		switch e.AI {
+		case "01":
+			res.GTIN = e.Value
+		case "02":
+			if res.GTIN == "" {
+				res.GTIN = e.Value
+			}
+		case "10":
+			res.Lot = e.Value
+		case "21":
+			res.Serial = e.Value
+		case "17":
+			res.Expiry = e.Value
+		case "30", "37":
+			count, err := strconv.Atoi(e.Value)
+			if err != nil {
+				return nil, errors.New("invalid count " + e.Value)
+			}
+			res.Count = count
+		}
+	}
+	return &res, nil
+}
+
+func parseHumanReadable(data string) ([]GS1Element, error) {
+	var res []GS1Element
+	for data != "" {
+		if data[0] != '(' {
+			return nil, errors.New("application identifier expected in brackets")
+		}
+		end := strings.Index(data, ")")
+		if end < 0 {
+			return nil, errors.New("unclosed application identifier")
+		}
+		code := data[1:end]
+		data = data[end+1:]
+		next := strings.Index(data, "(")
+		if next < 0 {
+			next = len(data)
+		}
+		value := strings.TrimSuffix(data[:next], GS)
+		data = data[next:]
+		aiCode, ai, ok := lookupAI(code)
+		if !ok || aiCode != code {
+			return nil, errors.New("unknown application identifier " + code)
+		}
+		e, err := newElement(code, ai, value)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *e)
+	}
+	return res, nil
+}
+
+func parseRaw(data string) ([]GS1Element, error) {
+	var res []GS1Element
+	for {
+		data = strings.TrimLeft(data, GS)
+		if data == "" {
+			break
+		}
+		code, ai, ok := lookupAI(data)
+		if !ok {
+			return nil, errors.New("unknown application identifier at " + data)
+		}
+		data = data[len(code):]
+		var value string
+		if ai.length > 0 {
+			if len(data) < ai.length {
+				return nil, errors.New("element (" + code + ") too short")
+			}
+			value, data = data[:ai.length], data[ai.length:]
+		} else {
+			end := strings.Index(data, GS)
+			if end < 0 {
+				end = len(data)
+			}
+			value, data = data[:end], data[end:]
+		}
+		e, err := newElement(code, ai, value)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *e)
+	}
+	return res, nil
+}
+
+func newElement(code string, ai gs1AI, value string) (*GS1Element, error) {
+	if value == "" || len(value) > ai.max || ai.length > 0 && len(value) != ai.length {
+		return nil, errors.New("invalid length of element (" + code + ")")
+	}
+	switch {
+	case ai.length > 0 && !isDigits(value):
+		return nil, errors.New("element (" + code + ") must be numeric")
+	case code == "00" || code == "01" || code == "02":
+		check, _ := CheckDigit(value[:len(value)-1])
+		if int(value[len(value)-1]-'0') != check {
+			return nil, errors.New("invalid check digit in (" + code + "), expected " + strconv.Itoa(check))
+		}
+	case ai.date:
+		date, err := gs1Date(value)
+		if err != nil {
+			return nil, errors.New("invalid date in (" + code + ")")
+		}
+		value = date
+	case code == "30" || code == "37":
+		if !isDigits(value) {
+			return nil, errors.New("element (" + code + ") must be numeric")
+		}
+	}
+	return &GS1Element{AI: code, Name: ai.name, Value: value}, nil
+}
+
+// gs1Date converts YYMMDD, a day of 00 meaning the last day of the month.
+// The century is the one putting the year within 49 years back and 50
+// years ahead of today, as the GS1 specification asks.
+func gs1Date(value string) (string, error) {
+	yy, _ := strconv.Atoi(value[:2])
+	mm, _ := strconv.Atoi(value[2:4])
+	dd, _ := strconv.Atoi(value[4:6])
+	if mm < 1 || mm > 12 {
+		return "", errors.New("invalid month")
+	}
+	current := time.Now().Year()
+	year := current/100*100 + yy
+	switch diff := yy - current%100; {
+	case diff >= 51:
+		year -= 100
+	case diff <= -50:
+		year += 100
+	}
+	last := time.Date(year, time.Month(mm)+1, 0, 0, 0, 0, 0, time.UTC)
+	if dd == 0 {
+		dd = last.Day()
+	}
+	if dd > last.Day() {
+		return "", errors.New("invalid day")
+	}
+	return time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC).Format("2006-01-02"), nil
+}
+
+// GTINForms lists the ways a GTIN-14 may have been registered, as is and
+// without the leading zeros padding a GTIN-13, GTIN-12 or GTIN-8
+func GTINForms(gtin string) []string {
+	res := []string{gtin}
+	if len(gtin) != 14 {
+		return res
+	}
+	for _, n := range []int{13, 12, 8} {
+		if strings.Trim(gtin[:14-n], "0") == "" {
+			res = append(res, gtin[14-n:])
+		}
+	}
+	return res
+}
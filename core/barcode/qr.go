@@ -0,0 +1,436 @@
+package barcode
+
+import "errors"
+
+// qrVersion holds the error correction level M block layout of a version
+type qrVersion struct {
+	ecPerBlock int
+	blocks1    int
+	data1      int
+	blocks2    int
+	data2      int
+	alignment  []int
+}
+
+// qrVersions covers versions 1 to 10 at level M, up to 213 bytes which is
+// plenty for labels
+var qrVersions = []qrVersion{
+	{10, 1, 16, 0, 0, nil},
+	{16, 1, 28, 0, 0, []int{6, 18}},
+	{26, 1, 44, 0, 0, []int{6, 22}},
+	{18, 2, 32, 0, 0, []int{6, 26}},
+	{24, 2, 43, 0, 0, []int{6, 30}},
+	{16, 4, 27, 0, 0, []int{6, 34}},
+	{18, 4, 31, 0, 0, []int{6, 22, 38}},
+	{22, 2, 38, 2, 39, []int{6, 24, 42}},
+	{22, 3, 36, 2, 37, []int{6, 26, 46}},
+	{26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+func (v qrVersion) dataCodewords() int {
+	return v.blocks1*v.data1 + v.blocks2*v.data2
+}
+
+type qrMatrix struct {
+	size     int
+	modules  []bool
+	function []bool
+}
+
+func (m *qrMatrix) set(x, y int, dark bool) {
+	m.modules[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+func (m *qrMatrix) dark(x, y int) bool {
+	return m.modules[y*m.size+x]
+}
+
+// EncodeQR encodes data in byte mode with error correction level M
+func EncodeQR(data string) (*Code, error) {
+	if data == "" {
+		return nil, errors.New("nothing to encode")
+	}
+	version := 0
+	for i, v := range qrVersions {
+		countBits := 8
+		if i+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= v.dataCodewords()*8 {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("data too long for a qr code")
+	}
+	v := qrVersions[version-1]
+	codewords := qrInterleave(v, qrData(v, version, []byte(data)))
+	size := version*4 + 17
+	m := &qrMatrix{size: size, modules: make([]bool, size*size), function: make([]bool, size*size)}
+	m.drawFunctionPatterns(version, v)
+	m.drawCodewords(codewords)
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormat(mask)
+		penalty := m.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		m.applyMask(mask)
+	}
+	m.applyMask(best)
+	m.drawFormat(best)
+	code := newCode(QR, data, size, size, 4)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			code.set(x, y, m.dark(x, y))
+		}
+	}
+	return code, nil
+}
+
+// qrData builds the byte mode segment padded to the data capacity
+func qrData(v qrVersion, version int, data []byte) []byte {
+	var bits []bool
+	appendBits := func(value, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, value>>uint(i)&1 == 1)
+		}
+	}
+	appendBits(4, 4)
+	if version >= 10 {
+		appendBits(len(data), 16)
+	} else {
+		appendBits(len(data), 8)
+	}
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capacity := v.dataCodewords() * 8
+	for i := 0; i < 4 && len(bits) < capacity; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+	res := make([]byte, 0, v.dataCodewords())
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << uint(7-j)
+			}
+		}
+		res = append(res, b)
+	}
+	for pad := byte(0xEC); len(res) < v.dataCodewords(); pad ^= 0xEC ^ 0x11 {
+		res = append(res, pad)
+	}
+	return res
+}
+
+// qrInterleave splits the data into blocks, adds the error correction of
+// each block and interleaves them
+func qrInterleave(v qrVersion, data []byte) []byte {
+	var blocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < v.blocks1+v.blocks2; i++ {
+		n := v.data1
+		if i >= v.blocks1 {
+			n = v.data2
+		}
+		block := data[offset : offset+n]
+		offset += n
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, reedSolomon(block, v.ecPerBlock))
+	}
+	var res []byte
+	for i := 0; i < v.data1 || i < v.data2; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				res = append(res, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			res = append(res, block[i])
+		}
+	}
+	return res
+}
+
+var gfExp, gfLog [512]int
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+// reedSolomon returns the n error correction codewords of data
+func reedSolomon(data []byte, n int) []byte {
+	generator := []int{1}
+	for i := 0; i < n; i++ {
+		next := make([]int, len(generator)+1)
+		for j, g := range generator {
+			next[j] ^= g
+			next[j+1] ^= gfMul(g, gfExp[i])
+		}
+		generator = next
+	}
+	remainder := make([]int, n)
+	for _, b := range data {
+		factor := int(b) ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[n-1] = 0
+		for j := 0; j < n; j++ {
+			remainder[j] ^= gfMul(generator[j+1], factor)
+		}
+	}
+	res := make([]byte, n)
+	for i, r := range remainder {
+		res[i] = byte(r)
+	}
+	return res
+}
+
+func (m *qrMatrix) drawFunctionPatterns(version int, v qrVersion) {
+	for i := 0; i < m.size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+	last := len(v.alignment) - 1
+	for i, ax := range v.alignment {
+		for j, ay := range v.alignment {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(ax+dx, ay+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	// reserve the format areas, drawn once the mask is known
+	m.drawFormat(0)
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 == 1
+			a, b := m.size-11+i%3, i/3
+			m.set(a, b, dark)
+			m.set(b, a, dark)
+		}
+	}
+}
+
+func (m *qrMatrix) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= m.size || y >= m.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			m.set(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormat writes both copies of the level M format information
+func (m *qrMatrix) drawFormat(mask int) {
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return bits>>uint(i)&1 == 1
+	}
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// drawCodewords places the bits in the zigzag order, two columns at a time
+// from the bottom right corner
+func (m *qrMatrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if m.function[y*m.size+x] {
+					continue
+				}
+				if i < len(codewords)*8 {
+					m.modules[y*m.size+x] = codewords[i>>3]>>uint(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func qrMask(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	}
+	return ((x+y)%2+x*y%3)%2 == 0
+}
+
+// applyMask flips the data modules, applying it twice restores them
+func (m *qrMatrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.function[y*m.size+x] && qrMask(mask, x, y) {
+				m.modules[y*m.size+x] = !m.modules[y*m.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four rules of the specification, the
+// mask with the lowest score is used
+func (m *qrMatrix) penalty() int {
+	res := 0
+	finder := []bool{true, false, true, true, true, false, true}
+	for line := 0; line < m.size; line++ {
+		for _, horizontal := range []bool{true, false} {
+			at := func(i int) bool {
+				if horizontal {
+					return m.dark(i, line)
+				}
+				return m.dark(line, i)
+			}
+			run := 1
+			for i := 1; i <= m.size; i++ {
+				if i < m.size && at(i) == at(i-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					res += run - 2
+				}
+				run = 1
+			}
+			for i := 0; i+7 <= m.size; i++ {
+				match := true
+				for j, dark := range finder {
+					if at(i+j) != dark {
+						match = false
+						break
+					}
+				}
+				if !match {
+					continue
+				}
+				before, after := true, true
+				for j := 1; j <= 4; j++ {
+					if i-j >= 0 && at(i-j) {
+						before = false
+					}
+					if i+6+j < m.size && at(i+6+j) {
+						after = false
+					}
+				}
+				if before || after {
+					res += 40
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.dark(x, y) {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				c := m.dark(x, y)
+				if m.dark(x+1, y) == c && m.dark(x, y+1) == c && m.dark(x+1, y+1) == c {
+					res += 3
+				}
+			}
+		}
+	}
+	total := m.size * m.size
+	res += abs(dark*20-total*10) / total * 10
+	return res
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
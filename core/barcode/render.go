@@ -0,0 +1,84 @@
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// PNG draws the code with its quiet zone, scale is the pixel size of a
+// module and height the bar height in pixels of a linear code
+func PNG(code *Code, scale, height int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	rows := code.Height * scale
+	if code.Linear() {
+		rows = height
+	}
+	width := (code.Width + 2*code.QuietZone) * scale
+	top := 0
+	if !code.Linear() {
+		top = code.QuietZone * scale
+		rows += 2 * top
+	}
+	img := image.NewGray(image.Rect(0, 0, width, rows))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y := 0; y < rows-2*top; y++ {
+		for x := 0; x < code.Width*scale; x++ {
+			my := y / scale
+			if code.Linear() {
+				my = 0
+			}
+			if code.Dark(x/scale, my) {
+				img.SetGray(x+code.QuietZone*scale, y+top, color.Gray{Y: 0})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+// SVG draws the code with its quiet zone, in module units scaled by scale.
+// Runs of dark modules in a row are merged into one rectangle.
+func SVG(code *Code, scale, height int) []byte {
+	if scale < 1 {
+		scale = 1
+	}
+	rows, rowHeight := code.Height, 1
+	if code.Linear() {
+		rowHeight = height / scale
+		if rowHeight < 1 {
+			rowHeight = 1
+		}
+	}
+	top := code.QuietZone
+	if code.Linear() {
+		top = 0
+	}
+	width := code.Width + 2*code.QuietZone
+	total := rows*rowHeight + 2*top
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, width*scale, total*scale, width, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, width, total)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < code.Width; x++ {
+			if !code.Dark(x, y) {
+				continue
+			}
+			run := 1
+			for code.Dark(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", x+code.QuietZone, y*rowHeight+top, run, rowHeight, run)
+			x += run
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
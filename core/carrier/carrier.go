@@ -0,0 +1,240 @@
+package carrier
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatPDF = "PDF"
+	FormatZPL = "ZPL"
+)
+
+// tracking status shared by all adapters
+const (
+	StatusLabelCreated = iota + 1
+	StatusPickedUp
+	StatusInTransit
+	StatusOutForDelivery
+	StatusDelivered
+	StatusException
+)
+
+var statusNames = map[string]int{
+	"label_created":    StatusLabelCreated,
+	"picked_up":        StatusPickedUp,
+	"in_transit":       StatusInTransit,
+	"out_for_delivery": StatusOutForDelivery,
+	"delivered":        StatusDelivered,
+	"exception":        StatusException,
+}
+
+// ParseStatus reads a tracking status sent by its name or its number
+func ParseStatus(v string) (int, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if status, ok := statusNames[v]; ok {
+		return status, nil
+	}
+	status, err := strconv.Atoi(v)
+	if err != nil || status < StatusLabelCreated || status > StatusException {
+		msg := "tracking status error: " + v
+		return 0, errors.New(msg)
+	}
+	return status, nil
+}
+
+type Address struct {
+	Name     string `json:"name"`
+	Address1 string `json:"address1"`
+	Address2 string `json:"address2"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	Zip      string `json:"zip"`
+	Country  string `json:"country"`
+	Phone    string `json:"phone"`
+}
+
+// Parcel is one box, weight in kg and dimensions in cm
+type Parcel struct {
+	Reference string  `json:"reference"`
+	Weight    float64 `json:"weight"`
+	Length    float64 `json:"length"`
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+}
+
+type Shipment struct {
+	From    Address  `json:"from"`
+	To      Address  `json:"to"`
+	Parcels []Parcel `json:"parcels"`
+}
+
+type Rate struct {
+	ServiceCode   string  `json:"service_code"`
+	ServiceName   string  `json:"service_name"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	EstimatedDays int     `json:"estimated_days"`
+}
+
+type LabelRequest struct {
+	Shipment    Shipment
+	ServiceCode string
+	Format      string
+	Reference   string
+}
+
+type Label struct {
+	TrackingNumber string
+	ServiceCode    string
+	Format         string
+	Data           []byte
+	Cost           float64
+}
+
+type TrackingEvent struct {
+	EventTime   time.Time `json:"event_time"`
+	Status      int       `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+}
+
+type Tracking struct {
+	TrackingNumber string          `json:"tracking_number"`
+	Status         int             `json:"status"`
+	Events         []TrackingEvent `json:"events"`
+}
+
+// Adapter is implemented by every carrier integration
+type Adapter interface {
+	Rates(shipment Shipment) ([]Rate, error)
+	CreateLabel(request LabelRequest) (*Label, error)
+	Track(trackingNumber string) (*Tracking, error)
+	Void(trackingNumber string) error
+}
+
+// WebhookParser is implemented by adapters whose carrier pushes tracking
+// updates in its own format, other adapters receive the format read by
+// ParseWebhook.
+type WebhookParser interface {
+	ParseWebhook(body []byte) ([]Tracking, error)
+}
+
+type webhookEvent struct {
+	TrackingNumber string `json:"tracking_number"`
+	EventTime      string `json:"event_time"`
+	Status         string `json:"status"`
+	Description    string `json:"description"`
+	Location       string `json:"location"`
+}
+
+// ParseWebhook reads a json list of events, or a single event, each with
+// tracking_number, event_time (RFC 3339 or 2006-01-02 15:04:05), status,
+// description and location.
+func ParseWebhook(body []byte) ([]Tracking, error) {
+	var events []webhookEvent
+	err := json.Unmarshal(body, &events)
+	if err != nil {
+		var event webhookEvent
+		err = json.Unmarshal(body, &event)
+		if err != nil {
+			msg := "webhook body error: " + err.Error()
+			return nil, errors.New(msg)
+		}
+		events = append(events, event)
+	}
+	var trackings []Tracking
+	index := make(map[string]int)
+	for _, event := range events {
+		if event.TrackingNumber == "" {
+			msg := "tracking number required"
+			return nil, errors.New(msg)
+		}
+		var trackingEvent TrackingEvent
+		trackingEvent.Status, err = ParseStatus(event.Status)
+		if err != nil {
+			return nil, err
+		}
+		trackingEvent.EventTime, err = time.Parse(time.RFC3339, event.EventTime)
+		if err != nil {
+			trackingEvent.EventTime, err = time.ParseInLocation("2006-01-02 15:04:05", event.EventTime, time.Local)
+		}
+		if err != nil {
+			msg := "event time error: " + event.EventTime
+			return nil, errors.New(msg)
+		}
+		trackingEvent.Description = event.Description
+		trackingEvent.Location = event.Location
+		i, ok := index[event.TrackingNumber]
+		if !ok {
+			i = len(trackings)
+			index[event.TrackingNumber] = i
+			trackings = append(trackings, Tracking{TrackingNumber: event.TrackingNumber})
+		}
+		// the status is the one of the latest event
+		last := len(trackings[i].Events) - 1
+		if last < 0 || !trackingEvent.EventTime.Before(trackings[i].Events[last].EventTime) {
+			trackings[i].Status = trackingEvent.Status
+		}
+		trackings[i].Events = append(trackings[i].Events, trackingEvent)
+		sort.SliceStable(trackings[i].Events, func(a, b int) bool {
+			return trackings[i].Events[a].EventTime.Before(trackings[i].Events[b].EventTime)
+		})
+	}
+	return trackings, nil
+}
+
+// Factory builds an adapter from the account config stored on the carrier
+type Factory func(config map[string]string) (Adapter, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+func New(name string, config map[string]string) (Adapter, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		msg := "carrier adapter not exist: " + name
+		return nil, errors.New(msg)
+	}
+	return factory(config)
+}
+
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var names []string
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ShipFrom reads the origin address kept in the carrier account config
+func ShipFrom(config map[string]string) Address {
+	return Address{
+		Name:     config["from_name"],
+		Address1: config["from_address1"],
+		Address2: config["from_address2"],
+		City:     config["from_city"],
+		State:    config["from_state"],
+		Zip:      config["from_zip"],
+		Country:  config["from_country"],
+		Phone:    config["from_phone"],
+	}
+}
@@ -0,0 +1,240 @@
+package carrier
+
+import (
+	"errors"
+	"fmt"
+	"go-api/core/pdf"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+	Register("mock", func(config map[string]string) (Adapter, error) {
+		return &mockCarrier{}, nil
+	})
+}
+
+// mockCarrier prices parcels by billable weight and moves shipments through
+// the tracking statuses by the age of the label, it never leaves the process.
+type mockCarrier struct{}
+
+var mockServices = []struct {
+	code   string
+	name   string
+	factor float64
+	days   int
+}{
+	{"GROUND", "Mock Ground", 1, 5},
+	{"EXPRESS", "Mock Express", 2.1, 2},
+	{"OVERNIGHT", "Mock Overnight", 3.5, 1},
+}
+
+var (
+	mockVoidedMu sync.Mutex
+	mockVoided   = make(map[string]bool)
+)
+
+func (c *mockCarrier) Rates(shipment Shipment) ([]Rate, error) {
+	if len(shipment.Parcels) == 0 {
+		msg := "shipment has no parcel"
+		return nil, errors.New(msg)
+	}
+	billable := 0.0
+	for _, parcel := range shipment.Parcels {
+		volumetric := parcel.Length * parcel.Width * parcel.Height / 5000
+		billable += math.Max(math.Max(parcel.Weight, volumetric), 0.1)
+	}
+	var rates []Rate
+	for _, service := range mockServices {
+		var rate Rate
+		rate.ServiceCode = service.code
+		rate.ServiceName = service.name
+		rate.Amount = math.Round((5*float64(len(shipment.Parcels))+1.2*billable)*service.factor*100) / 100
+		rate.Currency = "USD"
+		rate.EstimatedDays = service.days
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (c *mockCarrier) CreateLabel(request LabelRequest) (*Label, error) {
+	rates, err := c.Rates(request.Shipment)
+	if err != nil {
+		return nil, err
+	}
+	serviceCode := request.ServiceCode
+	if serviceCode == "" {
+		serviceCode = rates[0].ServiceCode
+	}
+	var label Label
+	for _, rate := range rates {
+		if rate.ServiceCode == serviceCode {
+			label.Cost = rate.Amount
+		}
+	}
+	if label.Cost == 0 {
+		msg := "carrier service not exist: " + serviceCode
+		return nil, errors.New(msg)
+	}
+	// the label time is kept in the tracking number so tracking needs no state
+	label.TrackingNumber = "MK" + strings.ToUpper(strconv.FormatInt(time.Now().Unix(), 36)) + fmt.Sprintf("%06d", rand.Intn(1000000))
+	label.ServiceCode = serviceCode
+	label.Format = request.Format
+	switch request.Format {
+	case FormatZPL:
+		label.Data = mockZPL(request, label.TrackingNumber)
+	case FormatPDF, "":
+		label.Format = FormatPDF
+		label.Data = mockPDF(request, label.TrackingNumber)
+	default:
+		msg := "label format not supported: " + request.Format
+		return nil, errors.New(msg)
+	}
+	return &label, nil
+}
+
+func (c *mockCarrier) Track(trackingNumber string) (*Tracking, error) {
+	if !strings.HasPrefix(trackingNumber, "MK") || len(trackingNumber) < 9 {
+		msg := "tracking number not exist"
+		return nil, errors.New(msg)
+	}
+	created, err := strconv.ParseInt(trackingNumber[2:len(trackingNumber)-6], 36, 64)
+	if err != nil {
+		msg := "tracking number not exist"
+		return nil, errors.New(msg)
+	}
+	labelTime := time.Unix(created, 0)
+	var tracking Tracking
+	tracking.TrackingNumber = trackingNumber
+	steps := []TrackingEvent{
+		{EventTime: labelTime, Status: StatusLabelCreated, Description: "Shipping label created", Location: "Origin"},
+		{EventTime: labelTime.Add(2 * time.Hour), Status: StatusPickedUp, Description: "Picked up by carrier", Location: "Origin"},
+		{EventTime: labelTime.Add(6 * time.Hour), Status: StatusInTransit, Description: "Departed origin hub", Location: "Origin hub"},
+		{EventTime: labelTime.Add(24 * time.Hour), Status: StatusOutForDelivery, Description: "Out for delivery", Location: "Destination hub"},
+		{EventTime: labelTime.Add(30 * time.Hour), Status: StatusDelivered, Description: "Delivered", Location: "Destination"},
+	}
+	mockVoidedMu.Lock()
+	voided := mockVoided[trackingNumber]
+	mockVoidedMu.Unlock()
+	for _, step := range steps {
+		if voided && step.Status != StatusLabelCreated {
+			break
+		}
+		if step.EventTime.After(time.Now()) {
+			break
+		}
+		tracking.Events = append(tracking.Events, step)
+		tracking.Status = step.Status
+	}
+	if voided {
+		tracking.Events = append(tracking.Events, TrackingEvent{EventTime: time.Now(), Status: StatusException, Description: "Label voided", Location: "Origin"})
+		tracking.Status = StatusException
+	}
+	return &tracking, nil
+}
+
+func (c *mockCarrier) Void(trackingNumber string) error {
+	tracking, err := c.Track(trackingNumber)
+	if err != nil {
+		return err
+	}
+	if tracking.Status != StatusLabelCreated {
+		msg := "shipment already picked up, label can not be voided"
+		return errors.New(msg)
+	}
+	mockVoidedMu.Lock()
+	mockVoided[trackingNumber] = true
+	mockVoidedMu.Unlock()
+	return nil
+}
+
+func addressLines(a Address) []string {
+	var lines []string
+	for _, line := range []string{a.Name, a.Address1, a.Address2, strings.TrimSpace(a.City + " " + a.State + " " + a.Zip), a.Country} {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// mockPDF draws a 4x6 inch label
+func mockPDF(request LabelRequest, trackingNumber string) []byte {
+	doc := pdf.New(pdf.PageSize{Width: 288, Height: 432})
+	doc.AddPage()
+	doc.StrokeRect(8, 8, 272, 416, 1.5, pdf.Black)
+	doc.Text(18, 30, 14, true, pdf.Black, "MOCK CARRIER")
+	doc.Text(190, 30, 10, true, pdf.Black, request.ServiceCode)
+	doc.Line(8, 40, 280, 40, 1, pdf.Black)
+	y := 56.0
+	doc.Text(18, y, 7, true, pdf.Black, "FROM")
+	for _, line := range addressLines(request.Shipment.From) {
+		y += 10
+		doc.Text(18, y, 8, false, pdf.Black, line)
+	}
+	y = math.Max(y+16, 120)
+	doc.Line(8, y-12, 280, y-12, 1, pdf.Black)
+	doc.Text(18, y, 7, true, pdf.Black, "SHIP TO")
+	for _, line := range addressLines(request.Shipment.To) {
+		y += 14
+		doc.Text(18, y, 11, true, pdf.Black, line)
+	}
+	y = math.Max(y+20, 250)
+	doc.Line(8, y-12, 280, y-12, 1, pdf.Black)
+	// not a scannable symbology, the bars only mark the barcode area
+	x := 24.0
+	for _, b := range []byte(trackingNumber) {
+		for bit := 0; bit < 8; bit++ {
+			width := 0.8
+			if b&(1<<uint(bit)) != 0 {
+				width = 1.6
+			}
+			if x+width < 264 {
+				doc.Rect(x, y, width, 70, pdf.Black)
+			}
+			x += width + 1
+		}
+	}
+	doc.Text(24, y+86, 12, true, pdf.Black, trackingNumber)
+	if request.Reference != "" {
+		doc.Text(24, y+104, 8, false, pdf.Black, "Ref: "+request.Reference)
+	}
+	doc.Text(24, y+118, 8, false, pdf.Black, fmt.Sprintf("Parcels: %d", len(request.Shipment.Parcels)))
+	return doc.Bytes()
+}
+
+func mockZPL(request LabelRequest, trackingNumber string) []byte {
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	b.WriteString("^FO30,30^A0N,40,40^FDMOCK CARRIER^FS\n")
+	b.WriteString("^FO560,40^A0N,30,30^FD" + zplText(request.ServiceCode) + "^FS\n")
+	y := 100
+	for _, line := range addressLines(request.Shipment.From) {
+		b.WriteString(fmt.Sprintf("^FO30,%d^A0N,22,22^FD%s^FS\n", y, zplText(line)))
+		y += 26
+	}
+	y += 30
+	b.WriteString(fmt.Sprintf("^FO30,%d^A0N,24,24^FDSHIP TO^FS\n", y))
+	y += 34
+	for _, line := range addressLines(request.Shipment.To) {
+		b.WriteString(fmt.Sprintf("^FO30,%d^A0N,36,36^FD%s^FS\n", y, zplText(line)))
+		y += 42
+	}
+	y += 40
+	b.WriteString(fmt.Sprintf("^FO60,%d^BY3^BCN,180,Y,N,N^FD%s^FS\n", y, trackingNumber))
+	if request.Reference != "" {
+		b.WriteString(fmt.Sprintf("^FO60,%d^A0N,24,24^FDRef: %s^FS\n", y+240, zplText(request.Reference)))
+	}
+	b.WriteString("^XZ\n")
+	return []byte(b.String())
+}
+
+// zplText removes the characters ZPL uses as command prefixes
+func zplText(s string) string {
+	return strings.NewReplacer("^", " ", "~", " ").Replace(s)
+}
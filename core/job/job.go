@@ -0,0 +1,33 @@
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+type Runner struct {
+}
+
+type Scheduler func(*Runner)
+
+func Schedule(schedulers ...Scheduler) {
+	runner := &Runner{}
+	for _, scheduler := range schedulers {
+		scheduler(runner)
+	}
+}
+
+// Every runs the handler once at start up and then on every interval
+func (r *Runner) Every(name string, interval time.Duration, handler func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := handler()
+			if err != nil {
+				fmt.Println("Job " + name + " error: " + err.Error())
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,228 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"go-api/core/barcode"
+	"go-api/core/pdf"
+	"strings"
+)
+
+// Layout places labels on a sheet, sizes are in millimetres. A layout with one
+// column and one row and no page size prints each label on its own page, as
+// roll printers expect.
+type Layout struct {
+	PageWidth   float64 //zero uses the label size
+	PageHeight  float64
+	LabelWidth  float64
+	LabelHeight float64
+	Columns     int
+	Rows        int
+	MarginTop   float64
+	MarginLeft  float64
+	GapX        float64
+	GapY        float64
+	Symbology   string
+	FontSize    float64
+	ShowText    bool //human readable text under linear codes
+}
+
+type Label struct {
+	Title string
+	Lines []string
+	Data  string //encoded in the barcode
+}
+
+const (
+	pointsPerMM = 72 / 25.4
+	dotsPerMM   = 8 //203 dpi thermal printers
+	padding     = 2.0
+)
+
+// encode uses the layout symbology, data Code 128 can encode but EAN-13 can
+// not, like location codes on an EAN-13 layout, falls back to Code 128
+func encode(symbology, data string) (*barcode.Code, error) {
+	code, err := barcode.Encode(symbology, data)
+	if err != nil && symbology == barcode.EAN13 {
+		return barcode.EncodeCode128(data)
+	}
+	return code, err
+}
+
+func fit(s string, width, size float64, bold bool) string {
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes), size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
+
+// PDF renders the labels filling each sheet row by row
+func PDF(layout Layout, labels []Label) ([]byte, error) {
+	if layout.Columns < 1 {
+		layout.Columns = 1
+	}
+	if layout.Rows < 1 {
+		layout.Rows = 1
+	}
+	if layout.FontSize <= 0 {
+		layout.FontSize = 8
+	}
+	size := pdf.PageSize{Width: layout.PageWidth * pointsPerMM, Height: layout.PageHeight * pointsPerMM}
+	if layout.PageWidth == 0 || layout.PageHeight == 0 {
+		size.Width = (layout.MarginLeft*2 + layout.LabelWidth*float64(layout.Columns) + layout.GapX*float64(layout.Columns-1)) * pointsPerMM
+		size.Height = (layout.MarginTop*2 + layout.LabelHeight*float64(layout.Rows) + layout.GapY*float64(layout.Rows-1)) * pointsPerMM
+	}
+	doc := pdf.New(size)
+	perPage := layout.Columns * layout.Rows
+	for i, l := range labels {
+		if i%perPage == 0 {
+			doc.AddPage()
+		}
+		column, row := i%perPage%layout.Columns, i%perPage/layout.Columns
+		x := (layout.MarginLeft + float64(column)*(layout.LabelWidth+layout.GapX)) * pointsPerMM
+		y := (layout.MarginTop + float64(row)*(layout.LabelHeight+layout.GapY)) * pointsPerMM
+		err := drawLabel(doc, layout, l, x, y)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := doc.Err()
+	if err != nil {
+		return nil, err
+	}
+	return doc.Bytes(), nil
+}
+
+func drawLabel(doc *pdf.Document, layout Layout, l Label, x, y float64) error {
+	pad := padding * pointsPerMM
+	width := layout.LabelWidth*pointsPerMM - 2*pad
+	bottom := y + layout.LabelHeight*pointsPerMM - pad
+	lineHeight := layout.FontSize * 1.2
+	ty := y + pad
+	if l.Title != "" {
+		ty += lineHeight
+		doc.Text(x+pad, ty, layout.FontSize, true, pdf.Black, fit(l.Title, width, layout.FontSize, true))
+	}
+	for _, line := range l.Lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ty += lineHeight
+		doc.Text(x+pad, ty, layout.FontSize, false, pdf.Black, fit(line, width, layout.FontSize, false))
+	}
+	if l.Data == "" {
+		return nil
+	}
+	code, err := encode(layout.Symbology, l.Data)
+	if err != nil {
+		return err
+	}
+	top := ty + lineHeight/2
+	height := bottom - top
+	if code.Linear() && layout.ShowText {
+		height -= lineHeight
+	}
+	if height <= 0 {
+		return nil
+	}
+	modules := float64(code.Width + 2*code.QuietZone)
+	if code.Linear() {
+		module := width / modules
+		left := x + pad + float64(code.QuietZone)*module
+		for mx := 0; mx < code.Width; mx++ {
+			if code.Dark(mx, 0) {
+				doc.Rect(left+float64(mx)*module, top, module, height, pdf.Black)
+			}
+		}
+		if layout.ShowText {
+			tw := pdf.TextWidth(code.Text, layout.FontSize, false)
+			doc.Text(x+pad+(width-tw)/2, bottom, layout.FontSize, false, pdf.Black, code.Text)
+		}
+		return nil
+	}
+	side := width
+	if height < side {
+		side = height
+	}
+	module := side / modules
+	left := x + pad + (width-side)/2 + float64(code.QuietZone)*module
+	for my := 0; my < code.Height; my++ {
+		for mx := 0; mx < code.Width; mx++ {
+			if code.Dark(mx, my) {
+				doc.Rect(left+float64(mx)*module, top+float64(code.QuietZone+my)*module, module, module, pdf.Black)
+			}
+		}
+	}
+	return nil
+}
+
+// zplEscape hex encodes the characters ZPL reads as commands, used with ^FH
+func zplEscape(s string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(s)
+}
+
+// ZPL renders one ^XA ^XZ format per label, sheets do not apply to thermal
+// printers so only the label size of the layout is used
+func ZPL(layout Layout, labels []Label) ([]byte, error) {
+	if layout.FontSize <= 0 {
+		layout.FontSize = 8
+	}
+	width := int(layout.LabelWidth * dotsPerMM)
+	height := int(layout.LabelHeight * dotsPerMM)
+	pad := int(padding * dotsPerMM)
+	font := int(layout.FontSize * 25.4 / 72 * dotsPerMM * 1.2)
+	var buf bytes.Buffer
+	for _, l := range labels {
+		fmt.Fprintf(&buf, "^XA^CI28^PW%d^LL%d\n", width, height)
+		y := pad
+		if l.Title != "" {
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH^FD%s^FS\n", pad, y, font, font, zplEscape(l.Title))
+			y += font + font/4
+		}
+		for _, line := range l.Lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH^FD%s^FS\n", pad, y, font, font, zplEscape(line))
+			y += font + font/4
+		}
+		if l.Data != "" {
+			code, err := encode(layout.Symbology, l.Data)
+			if err != nil {
+				return nil, err
+			}
+			barHeight := height - pad - y
+			interpretation := "N"
+			if code.Linear() && layout.ShowText {
+				barHeight -= font + font/4
+				interpretation = "Y"
+			}
+			module := (width - 2*pad) / (code.Width + 2*code.QuietZone)
+			if module < 1 {
+				module = 1
+			}
+			switch {
+			case code.Symbology == barcode.QR:
+				side := barHeight
+				if width-2*pad < side {
+					side = width - 2*pad
+				}
+				magnification := side / (code.Width + 2*code.QuietZone)
+				if magnification < 1 {
+					magnification = 1
+				}
+				if magnification > 10 {
+					magnification = 10
+				}
+				fmt.Fprintf(&buf, "^FO%d,%d^BQN,2,%d^FH^FDMA,%s^FS\n", pad, y, magnification, zplEscape(l.Data))
+			case code.Symbology == barcode.EAN13:
+				fmt.Fprintf(&buf, "^FO%d,%d^BY%d^BEN,%d,%s,N^FD%s^FS\n", pad+code.QuietZone*module, y, module, barHeight, interpretation, code.Text[:12])
+			default:
+				fmt.Fprintf(&buf, "^FO%d,%d^BY%d^BCN,%d,%s,N,N,A^FH^FD%s^FS\n", pad+code.QuietZone*module, y, module, barHeight, interpretation, zplEscape(l.Data))
+			}
+		}
+		buf.WriteString("^XZ\n")
+	}
+	return buf.Bytes(), nil
+}
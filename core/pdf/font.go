@@ -0,0 +1,551 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"go-api/core/config"
+)
+
+// ttfFont is a TrueType or OpenType font embedded as a CID font with
+// Identity-H encoding, so text is written as glyph ids and a ToUnicode map
+// keeps it searchable and copyable. TrueType outlines are subset to the
+// glyphs a document uses, CFF outlines are embedded whole.
+type ttfFont struct {
+	name      string
+	data      []byte
+	tables    map[string][]byte
+	cff       bool //OpenType with CFF outlines instead of TrueType glyf
+	scale     float64
+	ascent    int
+	descent   int
+	capHeight int
+	bbox      [4]int
+	glyphs    map[rune]uint16
+	advances  []int //in 1/1000 of the font size
+}
+
+var regularFont, boldFont *ttfFont
+
+// ConfigFont loads the fonts set as pdf.font and pdf.font_bold. Without them
+// documents use the built in Helvetica, which only covers Latin-1.
+func ConfigFont() {
+	regular := config.ReadConfig("pdf.font")
+	if regular == "" {
+		return
+	}
+	bold := config.ReadConfig("pdf.font_bold")
+	if bold == "" {
+		bold = regular
+	}
+	err := LoadFont(regular, bold)
+	if err != nil {
+		panic(fmt.Errorf("fatal error pdf font: %s ", err))
+	}
+}
+
+// LoadFont reads the regular and bold font files used for all documents
+func LoadFont(regular, bold string) error {
+	r, err := readFont(regular)
+	if err != nil {
+		return err
+	}
+	b := r
+	if bold != regular {
+		b, err = readFont(bold)
+		if err != nil {
+			return err
+		}
+	}
+	regularFont, boldFont = r, b
+	return nil
+}
+
+func readFont(path string) (*ttfFont, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	font, err := parseFont(data)
+	if err != nil {
+		return nil, errors.New(path + ": " + err.Error())
+	}
+	return font, nil
+}
+
+func fontFor(bold bool) *ttfFont {
+	if bold {
+		return boldFont
+	}
+	return regularFont
+}
+
+func parseFont(data []byte) (*ttfFont, error) {
+	if len(data) < 12 {
+		return nil, errors.New("font file too short")
+	}
+	version := string(data[:4])
+	if version != "\x00\x01\x00\x00" && version != "true" && version != "OTTO" {
+		return nil, errors.New("not a TrueType or OpenType font, collections are not supported")
+	}
+	tables := make(map[string][]byte)
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < count; i++ {
+		entry := 12 + 16*i
+		if entry+16 > len(data) {
+			return nil, errors.New("font table directory truncated")
+		}
+		offset := int(binary.BigEndian.Uint32(data[entry+8:]))
+		length := int(binary.BigEndian.Uint32(data[entry+12:]))
+		if offset+length > len(data) {
+			return nil, errors.New("font table out of range")
+		}
+		tables[string(data[entry:entry+4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if len(tables[tag]) == 0 {
+			return nil, errors.New("font table " + tag + " missing")
+		}
+	}
+	head, hhea, hmtx := tables["head"], tables["hhea"], tables["hmtx"]
+	if len(head) < 54 || len(hhea) < 36 || len(tables["maxp"]) < 6 {
+		return nil, errors.New("font header truncated")
+	}
+	font := &ttfFont{data: data, tables: tables, cff: version == "OTTO"}
+	if !font.cff && (len(tables["glyf"]) == 0 || len(tables["loca"]) == 0) {
+		return nil, errors.New("font table glyf missing")
+	}
+	unitsPerEm := int(binary.BigEndian.Uint16(head[18:]))
+	if unitsPerEm == 0 {
+		return nil, errors.New("font units per em is zero")
+	}
+	font.scale = 1000 / float64(unitsPerEm)
+	for i := range font.bbox {
+		font.bbox[i] = font.units(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	font.ascent = font.units(int16(binary.BigEndian.Uint16(hhea[4:])))
+	font.descent = font.units(int16(binary.BigEndian.Uint16(hhea[6:])))
+	font.capHeight = font.ascent
+	if os2 := tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		font.capHeight = font.units(int16(binary.BigEndian.Uint16(os2[88:])))
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(tables["maxp"][4:]))
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if metrics == 0 || metrics > numGlyphs || len(hmtx) < 4*metrics {
+		return nil, errors.New("font metrics truncated")
+	}
+	font.advances = make([]int, numGlyphs)
+	for i := range font.advances {
+		//glyphs after the last metric share its advance
+		m := i
+		if m >= metrics {
+			m = metrics - 1
+		}
+		font.advances[i] = font.units(int16(binary.BigEndian.Uint16(hmtx[4*m:])))
+	}
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+	font.name = fontName(tables["name"])
+	return font, nil
+}
+
+func (f *ttfFont) units(v int16) int {
+	return int(float64(v) * f.scale)
+}
+
+// glyph returns the glyph of r, false when the font does not cover it
+func (f *ttfFont) glyph(r rune) (uint16, bool) {
+	g, ok := f.glyphs[r]
+	return g, ok && int(g) < len(f.advances)
+}
+
+func (f *ttfFont) advance(g uint16) int {
+	if int(g) < len(f.advances) {
+		return f.advances[g]
+	}
+	return 0
+}
+
+// parseCmap reads the Unicode subtable, full repertoire format 12 preferred
+// over the BMP only format 4
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errors.New("font cmap truncated")
+	}
+	var bmp, full []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count && 4+8*i+8 <= len(cmap); i++ {
+		record := cmap[4+8*i:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+4 > len(cmap) || platform != 0 && platform != 3 {
+			continue
+		}
+		if platform == 3 && encoding != 1 && encoding != 10 {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			bmp = cmap[offset:]
+		case 12:
+			full = cmap[offset:]
+		}
+	}
+	if full != nil {
+		return parseCmap12(full)
+	}
+	if bmp != nil {
+		return parseCmap4(bmp)
+	}
+	return nil, errors.New("font has no unicode cmap")
+}
+
+func parseCmap4(t []byte) (map[rune]uint16, error) {
+	if len(t) < 14 {
+		return nil, errors.New("font cmap truncated")
+	}
+	segments := int(binary.BigEndian.Uint16(t[6:])) / 2
+	ends := 14
+	starts := ends + 2*segments + 2
+	deltas := starts + 2*segments
+	rangeOffsets := deltas + 2*segments
+	if rangeOffsets+2*segments > len(t) {
+		return nil, errors.New("font cmap truncated")
+	}
+	res := make(map[rune]uint16)
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(t[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(t[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(t[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(t[rangeOffsets+2*i:]))
+		for c := start; c <= end && c != 0xffff; c++ {
+			var g uint16
+			if rangeOffset == 0 {
+				g = uint16(c) + delta
+			} else {
+				at := rangeOffsets + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(t) {
+					continue
+				}
+				g = binary.BigEndian.Uint16(t[at:])
+				if g != 0 {
+					g += delta
+				}
+			}
+			if g != 0 {
+				res[rune(c)] = g
+			}
+		}
+	}
+	return res, nil
+}
+
+func parseCmap12(t []byte) (map[rune]uint16, error) {
+	if len(t) < 16 {
+		return nil, errors.New("font cmap truncated")
+	}
+	groups := int(binary.BigEndian.Uint32(t[12:]))
+	if 16+12*groups > len(t) {
+		return nil, errors.New("font cmap truncated")
+	}
+	res := make(map[rune]uint16)
+	for i := 0; i < groups; i++ {
+		group := t[16+12*i:]
+		start := binary.BigEndian.Uint32(group)
+		end := binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for c := start; c <= end && c <= 0x10ffff; c++ {
+			res[rune(c)] = uint16(glyph + c - start)
+		}
+	}
+	return res, nil
+}
+
+// fontName reads the PostScript name, id 6 of the name table
+func fontName(t []byte) string {
+	name := "EmbeddedFont"
+	if len(t) < 6 {
+		return name
+	}
+	count := int(binary.BigEndian.Uint16(t[2:]))
+	storage := int(binary.BigEndian.Uint16(t[4:]))
+	for i := 0; i < count && 6+12*i+12 <= len(t); i++ {
+		record := t[6+12*i:]
+		if binary.BigEndian.Uint16(record[6:]) != 6 {
+			continue
+		}
+		platform := binary.BigEndian.Uint16(record)
+		length := int(binary.BigEndian.Uint16(record[8:]))
+		offset := storage + int(binary.BigEndian.Uint16(record[10:]))
+		if offset+length > len(t) {
+			continue
+		}
+		raw := t[offset : offset+length]
+		value := string(raw)
+		if platform == 0 || platform == 3 {
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			value = string(utf16.Decode(units))
+		}
+		//a PDF name may not hold delimiters or spaces
+		value = strings.Map(func(r rune) rune {
+			if r <= 32 || r >= 127 || strings.ContainsRune("()<>[]{}/%#", r) {
+				return -1
+			}
+			return r
+		}, value)
+		if value != "" {
+			return value
+		}
+	}
+	return name
+}
+
+// baseFont is the font name, subsets get a tag derived from their glyphs as
+// the PDF specification asks
+func (f *ttfFont) baseFont(used map[uint16]rune) string {
+	if f.cff {
+		return f.name
+	}
+	var hash uint32 = 2166136261
+	for g := range used {
+		hash ^= uint32(g) * 2654435761
+	}
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = byte('A' + hash%26)
+		hash /= 26
+	}
+	return string(tag) + "+" + f.name
+}
+
+// dict is the Type0 font referenced from the page resources, its descendant
+// font, descriptor, font file and ToUnicode map are objects first to first+3
+func (f *ttfFont) dict(first int, used map[uint16]rune) string {
+	return fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", f.baseFont(used), first, first+3)
+}
+
+// writeObjects writes the four objects dict refers to, with the widths and
+// unicode values of the glyphs used in the document
+func (f *ttfFont) writeObjects(object func(string), stream func(string, []byte), first int, used map[uint16]rune) {
+	glyphs := make([]int, 0, len(used))
+	for g := range used {
+		glyphs = append(glyphs, int(g))
+	}
+	sort.Ints(glyphs)
+	var widths []string
+	for _, g := range glyphs {
+		widths = append(widths, fmt.Sprintf("%d [%d]", g, f.advance(uint16(g))))
+	}
+	name := f.baseFont(used)
+	data := f.data
+	subtype, fontFile, cidToGID := "CIDFontType2", "FontFile2", " /CIDToGIDMap /Identity"
+	if f.cff {
+		subtype, fontFile, cidToGID = "CIDFontType0", "FontFile3", ""
+	} else {
+		data = f.subset(used)
+	}
+	fileDict := fmt.Sprintf("/Length1 %d /Filter /FlateDecode", len(data))
+	if f.cff {
+		fileDict = "/Subtype /OpenType /Filter /FlateDecode"
+	}
+	object(fmt.Sprintf("<< /Type /Font /Subtype /%s /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /W [%s]%s >>", subtype, name, first+1, f.advance(0), strings.Join(widths, " "), cidToGID))
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /%s %d 0 R >>", name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.capHeight, fontFile, first+2))
+	stream(fileDict, deflate(data))
+	stream("/Filter /FlateDecode", deflate(toUnicode(glyphs, used)))
+}
+
+func toUnicode(glyphs []int, used map[uint16]rune) []byte {
+	var buf strings.Builder
+	buf.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	buf.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	buf.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	buf.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	//at most 100 entries per block
+	for i := 0; i < len(glyphs); i += 100 {
+		block := glyphs[i:]
+		if len(block) > 100 {
+			block = block[:100]
+		}
+		fmt.Fprintf(&buf, "%d beginbfchar\n", len(block))
+		for _, g := range block {
+			fmt.Fprintf(&buf, "<%04X> <", g)
+			for _, u := range utf16.Encode([]rune{used[uint16(g)]}) {
+				fmt.Fprintf(&buf, "%04X", u)
+			}
+			buf.WriteString(">\n")
+		}
+		buf.WriteString("endbfchar\n")
+	}
+	buf.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return []byte(buf.String())
+}
+
+// glyphRange returns where glyph g is stored in the glyf table
+func (f *ttfFont) glyphRange(g int) (int, int) {
+	loca := f.tables["loca"]
+	var start, end int
+	if binary.BigEndian.Uint16(f.tables["head"][50:]) == 0 {
+		if 2*g+4 > len(loca) {
+			return 0, 0
+		}
+		start, end = 2*int(binary.BigEndian.Uint16(loca[2*g:])), 2*int(binary.BigEndian.Uint16(loca[2*g+2:]))
+	} else {
+		if 4*g+8 > len(loca) {
+			return 0, 0
+		}
+		start, end = int(binary.BigEndian.Uint32(loca[4*g:])), int(binary.BigEndian.Uint32(loca[4*g+4:]))
+	}
+	if start > end || end > len(f.tables["glyf"]) {
+		return 0, 0
+	}
+	return start, end
+}
+
+// components lists the glyphs a composite glyph is built from
+func (f *ttfFont) components(g int) []int {
+	start, end := f.glyphRange(g)
+	glyph := f.tables["glyf"][start:end]
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+	var res []int
+	for at := 10; at+4 <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[at:])
+		res = append(res, int(binary.BigEndian.Uint16(glyph[at+2:])))
+		at += 4
+		if flags&0x1 != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&0x8 != 0:
+			at += 2
+		case flags&0x40 != 0:
+			at += 4
+		case flags&0x80 != 0:
+			at += 8
+		}
+		if flags&0x20 == 0 {
+			break
+		}
+	}
+	return res
+}
+
+// subset rebuilds the font keeping the outlines of the used glyphs and the
+// glyphs they are composed of. Glyph ids stay the same, the other glyphs are
+// left empty.
+func (f *ttfFont) subset(used map[uint16]rune) []byte {
+	keep := map[int]bool{0: true}
+	queue := []int{0}
+	for g := range used {
+		queue = append(queue, int(g))
+	}
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		keep[g] = true
+		for _, c := range f.components(g) {
+			if !keep[c] && c < len(f.advances) {
+				keep[c] = true
+				queue = append(queue, c)
+			}
+		}
+	}
+	var glyf []byte
+	loca := make([]byte, 4*(len(f.advances)+1))
+	for g := 0; g < len(f.advances); g++ {
+		binary.BigEndian.PutUint32(loca[4*g:], uint32(len(glyf)))
+		if !keep[g] {
+			continue
+		}
+		start, end := f.glyphRange(g)
+		glyf = append(glyf, f.tables["glyf"][start:end]...)
+		for len(glyf)%4 != 0 {
+			glyf = append(glyf, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*len(f.advances):], uint32(len(glyf)))
+	head := append([]byte{}, f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+	tables := map[string][]byte{"head": head, "loca": loca, "glyf": glyf}
+	for _, tag := range []string{"hhea", "hmtx", "maxp", "cvt ", "fpgm", "prep"} {
+		if len(f.tables[tag]) > 0 {
+			tables[tag] = f.tables[tag]
+		}
+	}
+	data := sfnt(tables)
+	//the whole font must sum to 0xB1B0AFBA
+	headAt := 12 + 16*len(tables)
+	for _, tag := range sortedTags(tables) {
+		if tag == "head" {
+			break
+		}
+		headAt += (len(tables[tag]) + 3) &^ 3
+	}
+	binary.BigEndian.PutUint32(data[headAt+8:], 0xB1B0AFBA-checksum(data))
+	return data
+}
+
+func sortedTags(tables map[string][]byte) []string {
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// sfnt writes the tables as a TrueType font file
+func sfnt(tables map[string][]byte) []byte {
+	tags := sortedTags(tables)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+	header := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(16*len(tags)-searchRange))
+	var body []byte
+	for i, tag := range tags {
+		table := tables[tag]
+		record := header[12+16*i:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(len(header)+len(body)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+		body = append(body, table...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	return append(header, body...)
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
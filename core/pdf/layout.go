@@ -0,0 +1,319 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	AlignLeft = iota
+	AlignRight
+	AlignCenter
+)
+
+type Field struct {
+	Label string
+	Value string
+}
+
+type Block struct {
+	Title string
+	Lines []string
+}
+
+type Column struct {
+	Title string
+	Width float64 //relative width
+	Align int
+}
+
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    [][]string
+}
+
+// Layout is the business document used for invoices, orders, packing slips
+// and the like. The organization template fills the page settings and the
+// header and footer texts, the module fills the content.
+type Layout struct {
+	PageSize    PageSize
+	AccentColor string
+	Logo        []byte
+	Title       string
+	CompanyInfo string
+	HeaderText  string
+	FooterText  string
+	Fields      []Field
+	Blocks      []Block
+	Tables      []Table
+	Totals      []Field
+	Notes       string
+}
+
+const (
+	margin     = 40.0
+	lineHeight = 13.0
+	fontSize   = 9.0
+)
+
+type layoutWriter struct {
+	doc    *Document
+	accent Color
+	y      float64
+	bottom float64
+}
+
+// Render lays out the document, it fails when text can not be printed with
+// the configured fonts
+func (l *Layout) Render() ([]byte, error) {
+	size := l.PageSize
+	if size.Width == 0 {
+		size = A4
+	}
+	w := &layoutWriter{doc: New(size), accent: ParseColor(l.AccentColor)}
+	if l.AccentColor == "" {
+		w.accent = Color{0.2, 0.3, 0.45}
+	}
+	w.bottom = size.Height - margin - 2*lineHeight
+	w.doc.AddPage()
+	w.y = margin
+	w.header(l)
+	for _, table := range l.Tables {
+		w.table(table)
+	}
+	w.totals(l.Totals)
+	if l.Notes != "" {
+		w.space(lineHeight)
+		w.paragraph("Notes", l.Notes)
+	}
+	w.footer(l.FooterText)
+	err := w.doc.Err()
+	if err != nil {
+		return nil, err
+	}
+	return w.doc.Bytes(), nil
+}
+
+func (w *layoutWriter) width() float64 {
+	return w.doc.Size().Width - 2*margin
+}
+
+func (w *layoutWriter) newPage() {
+	w.doc.AddPage()
+	w.y = margin
+}
+
+// space moves down by h, starting a new page when the content area is full
+func (w *layoutWriter) space(h float64) {
+	if w.y+h > w.bottom {
+		w.newPage()
+		return
+	}
+	w.y += h
+}
+
+func (w *layoutWriter) header(l *Layout) {
+	right := w.doc.Size().Width - margin
+	top := w.y
+	logoBottom := top
+	if len(l.Logo) > 0 {
+		pw, ph, err := ImageSize(l.Logo)
+		if err == nil && pw > 0 && ph > 0 {
+			lw, lh := 140.0, 140.0*float64(ph)/float64(pw)
+			if lh > 60 {
+				lw, lh = 60*float64(pw)/float64(ph), 60.0
+			}
+			if w.doc.Image(l.Logo, margin, top, lw, lh) == nil {
+				logoBottom = top + lh + 6
+			}
+		}
+	}
+	title := strings.ToUpper(l.Title)
+	w.doc.Text(right-TextWidth(title, 20, true), top+18, 20, true, w.accent, title)
+	y := logoBottom
+	for _, line := range strings.Split(l.CompanyInfo, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		y += lineHeight
+		w.doc.Text(margin, y, fontSize, false, Black, line)
+	}
+	fieldY := top + 30
+	for _, field := range l.Fields {
+		fieldY += lineHeight
+		w.doc.Text(right-150, fieldY, fontSize, true, Black, field.Label)
+		w.doc.Text(right-TextWidth(field.Value, fontSize, false), fieldY, fontSize, false, Black, field.Value)
+	}
+	if fieldY > y {
+		y = fieldY
+	}
+	w.y = y + 2*lineHeight
+	if len(l.Blocks) > 0 {
+		blockWidth := w.width() / float64(len(l.Blocks))
+		maxY := w.y
+		for i, block := range l.Blocks {
+			x := margin + float64(i)*blockWidth
+			by := w.y
+			w.doc.Text(x, by, fontSize, true, w.accent, strings.ToUpper(block.Title))
+			for _, line := range block.Lines {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				by += lineHeight
+				w.doc.Text(x, by, fontSize, false, Black, fit(line, blockWidth-10, fontSize, false))
+			}
+			if by > maxY {
+				maxY = by
+			}
+		}
+		w.y = maxY + 2*lineHeight
+	}
+	if l.HeaderText != "" {
+		w.paragraph("", l.HeaderText)
+		w.y += lineHeight
+	}
+}
+
+func (w *layoutWriter) paragraph(title, text string) {
+	if title != "" {
+		w.space(0)
+		w.doc.Text(margin, w.y, fontSize, true, w.accent, strings.ToUpper(title))
+		w.y += lineHeight
+	}
+	for _, line := range wrap(text, w.width(), fontSize) {
+		w.space(0)
+		w.doc.Text(margin, w.y, fontSize, false, Black, line)
+		w.y += lineHeight
+	}
+}
+
+func (w *layoutWriter) tableHeader(table Table, widths []float64) {
+	w.doc.Rect(margin, w.y-lineHeight+3, w.width(), lineHeight+4, w.accent)
+	x := margin
+	for i, column := range table.Columns {
+		w.cell(x, widths[i], column.Title, column.Align, true, White)
+		x += widths[i]
+	}
+	w.y += lineHeight + 4
+}
+
+func (w *layoutWriter) cell(x, width float64, text string, align int, bold bool, c Color) {
+	text = fit(text, width-8, fontSize, bold)
+	tw := TextWidth(text, fontSize, bold)
+	switch align {
+	case AlignRight:
+		x = x + width - 4 - tw
+	case AlignCenter:
+		x = x + (width-tw)/2
+	default:
+		x = x + 4
+	}
+	w.doc.Text(x, w.y, fontSize, bold, c, text)
+}
+
+func (w *layoutWriter) table(table Table) {
+	total := 0.0
+	for _, column := range table.Columns {
+		total += column.Width
+	}
+	if total == 0 {
+		return
+	}
+	widths := make([]float64, len(table.Columns))
+	for i, column := range table.Columns {
+		widths[i] = w.width() * column.Width / total
+	}
+	if w.y+3*lineHeight > w.bottom {
+		w.newPage()
+	}
+	if table.Title != "" {
+		w.doc.Text(margin, w.y, fontSize+1, true, w.accent, table.Title)
+		w.y += lineHeight + 4
+	}
+	w.tableHeader(table, widths)
+	for _, row := range table.Rows {
+		if w.y+lineHeight > w.bottom {
+			w.newPage()
+			w.tableHeader(table, widths)
+		}
+		x := margin
+		for i := range table.Columns {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			w.cell(x, widths[i], value, table.Columns[i].Align, false, Black)
+			x += widths[i]
+		}
+		w.doc.Line(margin, w.y+4, margin+w.width(), w.y+4, 0.3, Light)
+		w.y += lineHeight + 2
+	}
+	w.y += lineHeight
+}
+
+func (w *layoutWriter) totals(totals []Field) {
+	right := w.doc.Size().Width - margin
+	for i, field := range totals {
+		if w.y+lineHeight > w.bottom {
+			w.newPage()
+		}
+		bold := i == len(totals)-1
+		w.doc.Text(right-200, w.y, fontSize, bold, Black, field.Label)
+		w.doc.Text(right-4-TextWidth(field.Value, fontSize, bold), w.y, fontSize, bold, Black, field.Value)
+		w.y += lineHeight + 2
+	}
+}
+
+func (w *layoutWriter) footer(text string) {
+	size := w.doc.Size()
+	count := w.doc.PageCount()
+	for page := 1; page <= count; page++ {
+		w.doc.SetPage(page)
+		y := size.Height - margin
+		w.doc.Line(margin, y-lineHeight, size.Width-margin, y-lineHeight, 0.5, Gray)
+		if text != "" {
+			w.doc.Text(margin, y, fontSize-1, false, Gray, fit(strings.ReplaceAll(text, "\n", " "), w.width()-80, fontSize-1, false))
+		}
+		pageText := fmt.Sprintf("Page %d / %d", page, count)
+		w.doc.Text(size.Width-margin-TextWidth(pageText, fontSize-1, false), y, fontSize-1, false, Gray, pageText)
+	}
+}
+
+// fit shortens text with an ellipsis so it is no wider than width
+func fit(text string, width, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// wrap splits text into lines no wider than width
+func wrap(text string, width, size float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if TextWidth(candidate, size, false) > width && line != "" {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Money formats an amount with two decimals
+func Money(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
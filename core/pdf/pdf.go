@@ -0,0 +1,334 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+)
+
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4     = PageSize{Width: 595.28, Height: 841.89}
+	Letter = PageSize{Width: 612, Height: 792}
+)
+
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	Black = Color{0, 0, 0}
+	White = Color{1, 1, 1}
+	Gray  = Color{0.6, 0.6, 0.6}
+	Light = Color{0.93, 0.93, 0.93}
+)
+
+// ParseColor reads a #RRGGBB color, falling back to black
+func ParseColor(v string) Color {
+	var r, g, b int
+	_, err := fmt.Sscanf(strings.TrimPrefix(v, "#"), "%02x%02x%02x", &r, &g, &b)
+	if err != nil {
+		return Black
+	}
+	return Color{float64(r) / 255, float64(g) / 255, float64(b) / 255}
+}
+
+type pdfImage struct {
+	width  int
+	height int
+	data   []byte
+}
+
+// Document is a minimal PDF writer using the built in Helvetica fonts, or
+// the TrueType fonts of the config when set, so no network access is needed.
+// Coordinates are in points with the origin at the top left corner of the
+// page.
+type Document struct {
+	size    PageSize
+	pages   []*bytes.Buffer
+	current int
+	images  []pdfImage
+	used    map[*ttfFont]map[uint16]rune
+	missing rune
+}
+
+func New(size PageSize) *Document {
+	return &Document{size: size, current: -1, used: make(map[*ttfFont]map[uint16]rune)}
+}
+
+// Err reports text the fonts could not print, the first character missing
+// would otherwise come out as '?' or an empty box
+func (d *Document) Err() error {
+	if d.missing == 0 {
+		return nil
+	}
+	if regularFont == nil {
+		return fmt.Errorf("character %q can not be printed with Helvetica, set a unicode font as pdf.font in the config", d.missing)
+	}
+	return fmt.Errorf("character %q is not covered by the pdf font", d.missing)
+}
+
+func (d *Document) Size() PageSize {
+	return d.size
+}
+
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage selects an existing page for drawing, pages start at 1
+func (d *Document) SetPage(n int) {
+	if n >= 1 && n <= len(d.pages) {
+		d.current = n - 1
+	}
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if d.current < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.current]
+}
+
+func (d *Document) Text(x, y, size float64, bold bool, c Color, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	var text string
+	if f := fontFor(bold); f != nil {
+		text = "<" + d.glyphs(f, s) + ">"
+	} else {
+		text = "(" + d.escape(s) + ")"
+	}
+	fmt.Fprintf(d.page(), "BT %.3f %.3f %.3f rg /%s %.2f Tf %.2f %.2f Td %s Tj ET\n", c.R, c.G, c.B, font, size, x, d.size.Height-y, text)
+}
+
+func (d *Document) Line(x1, y1, x2, y2, width float64, c Color) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n", c.R, c.G, c.B, width, x1, d.size.Height-y1, x2, d.size.Height-y2)
+}
+
+// Rect fills a rectangle whose top left corner is at x, y
+func (d *Document) Rect(x, y, w, h float64, c Color) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c.R, c.G, c.B, x, d.size.Height-y-h, w, h)
+}
+
+// StrokeRect draws the outline of a rectangle whose top left corner is at x, y
+func (d *Document) StrokeRect(x, y, w, h, width float64, c Color) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG %.2f w %.2f %.2f %.2f %.2f re S\n", c.R, c.G, c.B, width, x, d.size.Height-y-h, w, h)
+}
+
+// Image draws a PNG or JPEG image scaled into w x h
+func (d *Document) Image(data []byte, x, y, w, h float64) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	bounds := img.Bounds()
+	var raw bytes.Buffer
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			c := color.NRGBAModel.Convert(img.At(px, py)).(color.NRGBA)
+			// transparent pixels are drawn over a white page
+			alpha := float64(c.A) / 255
+			raw.WriteByte(byte(float64(c.R)*alpha + 255*(1-alpha)))
+			raw.WriteByte(byte(float64(c.G)*alpha + 255*(1-alpha)))
+			raw.WriteByte(byte(float64(c.B)*alpha + 255*(1-alpha)))
+		}
+	}
+	d.images = append(d.images, pdfImage{width: bounds.Dx(), height: bounds.Dy(), data: deflate(raw.Bytes())})
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, x, d.size.Height-y-h, len(d.images))
+	return nil
+}
+
+// ImageSize returns the pixel size of a PNG or JPEG image
+func ImageSize(data []byte) (int, int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", len(offsets), dict, len(data))
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1 catalog, 2 page tree, 3 and 4 fonts, then images, then page and
+	// content pairs, then the objects of the embedded fonts
+	firstImage := 5
+	firstPage := firstImage + len(d.images)
+	regularObjects := firstPage + 2*len(d.pages)
+	boldObjects := regularObjects
+	if boldFont != regularFont {
+		boldObjects += 4
+	}
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	if regularFont != nil {
+		object(regularFont.dict(regularObjects, d.used[regularFont]))
+		object(boldFont.dict(boldObjects, d.used[boldFont]))
+	} else {
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	}
+	var xobjects []string
+	for i, img := range d.images {
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", img.width, img.height), img.data)
+		xobjects = append(xobjects, fmt.Sprintf("/Im%d %d 0 R", i+1, firstImage+i))
+	}
+	resources := "<< /Font << /F1 3 0 R /F2 4 0 R >> /XObject << " + strings.Join(xobjects, " ") + " >> >>"
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>", d.size.Width, d.size.Height, resources, firstPage+i*2+1))
+		stream("/Filter /FlateDecode", deflate(content.Bytes()))
+	}
+	if regularFont != nil {
+		regularFont.writeObjects(object, stream, regularObjects, d.used[regularFont])
+		if boldFont != regularFont {
+			boldFont.writeObjects(object, stream, boldObjects, d.used[boldFont])
+		}
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+// escape converts text to WinAnsi, characters outside Latin-1 become '?' and
+// are reported by Err
+func (d *Document) escape(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r == '\t':
+			buf.WriteByte(' ')
+		case r < 32:
+			continue
+		case r < 127:
+			buf.WriteRune(r)
+		case r >= 160 && r <= 255:
+			buf.WriteString(fmt.Sprintf("\\%03o", r))
+		default:
+			buf.WriteByte('?')
+			if d.missing == 0 {
+				d.missing = r
+			}
+		}
+	}
+	return buf.String()
+}
+
+// glyphs converts text to the hex glyph ids of an embedded font
+func (d *Document) glyphs(f *ttfFont, s string) string {
+	used := d.used[f]
+	if used == nil {
+		used = make(map[uint16]rune)
+		d.used[f] = used
+	}
+	var buf strings.Builder
+	for _, r := range s {
+		if r == '\t' {
+			r = ' '
+		}
+		if r < 32 {
+			continue
+		}
+		g, ok := f.glyph(r)
+		if !ok && d.missing == 0 {
+			d.missing = r
+		}
+		if _, exists := used[g]; !exists && ok {
+			used[g] = r
+		}
+		fmt.Fprintf(&buf, "%04X", g)
+	}
+	return buf.String()
+}
+
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(s string, size float64, bold bool) float64 {
+	if f := fontFor(bold); f != nil {
+		total := 0
+		for _, r := range s {
+			g, _ := f.glyph(r)
+			total += f.advance(g)
+		}
+		return float64(total) * size / 1000
+	}
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}